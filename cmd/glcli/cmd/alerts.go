@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+var (
+	alertsType           string
+	alertsUnacknowledged bool
+	alertsLimit          int
+	alertsWeeks          int
+)
+
+var alertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "Show the alert history",
+	Long: `Display the glucose alerts fired by glcore, newest first.
+
+Use it to audit alert frequency: too many alerts lead to alert fatigue,
+too few may mean thresholds are missing real lows.
+
+Examples:
+  glcli alerts                   # Last 20 alerts
+  glcli alerts --type low        # Low glucose alerts only
+  glcli alerts --unacked         # Alerts not yet acknowledged
+  glcli alerts weekly            # Alert counts for the last 4 weeks
+  glcli alerts ack 42            # Acknowledge alert 42`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		result, err := client.GetAlerts(ctx, cli.AlertParams{
+			Type:           alertsType,
+			Unacknowledged: alertsUnacknowledged,
+			Limit:          alertsLimit,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(result)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatAlerts(result.Data, result.Pagination.Total))
+		}
+	},
+}
+
+var alertsWeeklyCmd = &cobra.Command{
+	Use:   "weekly",
+	Short: "Show alert counts per week",
+	Long: `Display the number of alerts fired per week (Monday to Sunday), oldest first.
+
+Examples:
+  glcli alerts weekly
+  glcli alerts weekly --weeks 12`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		weeks, err := client.GetAlertWeekly(ctx, alertsWeeks)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(weeks)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatAlertWeekly(weeks))
+		}
+	},
+}
+
+var alertsAckCmd = &cobra.Command{
+	Use:   "ack ID",
+	Short: "Acknowledge an alert",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil || id == 0 {
+			fmt.Fprintf(os.Stderr, "Error: invalid alert ID %q\n", args[0])
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		if err := client.AcknowledgeAlert(ctx, uint(id)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Alert %d acknowledged\n", id)
+	},
+}
+
+func init() {
+	alertsCmd.Flags().StringVar(&alertsType, "type", "", "Filter by alert type (low, falling)")
+	alertsCmd.Flags().BoolVar(&alertsUnacknowledged, "unacked", false, "Only show alerts not yet acknowledged")
+	alertsCmd.Flags().IntVar(&alertsLimit, "limit", 20, "Maximum number of alerts")
+	alertsWeeklyCmd.Flags().IntVar(&alertsWeeks, "weeks", 4, "Number of weeks (max 52)")
+	alertsCmd.AddCommand(alertsWeeklyCmd)
+	alertsCmd.AddCommand(alertsAckCmd)
+	rootCmd.AddCommand(alertsCmd)
+}
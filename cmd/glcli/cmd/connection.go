@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+var connectionCmd = &cobra.Command{
+	Use:   "connection",
+	Short: "Show the upstream connection details",
+	Long: `Display where glcore fetches glucose data from: the upstream source, the
+patient's initials and country, the sensor brand and how delayed readings are.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		connection, err := client.GetConnection(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(connection)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatConnection(connection))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(connectionCmd)
+}
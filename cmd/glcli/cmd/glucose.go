@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"time"
@@ -20,10 +19,12 @@ var glucoseCmd = &cobra.Command{
 By default, shows a compact one-line output with value and trend.
 Use --verbose for detailed output including status and timestamp.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := commandContext(10 * time.Second)
 		defer cancel()
 
-		reading, err := client.GetLatestGlucose(ctx)
+		reading, age, err := cli.FetchCached(cache, "glucose-latest", allowStale, func() (*cli.GlucoseReading, error) {
+			return client.GetLatestGlucose(ctx)
+		})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -36,10 +37,15 @@ Use --verbose for detailed output including status and timestamp.`,
 				os.Exit(1)
 			}
 			fmt.Println(output)
+			printStaleWarning(age)
 		} else if verbose {
-			fmt.Println(cli.FormatGlucose(reading))
+			fmt.Println(cli.FormatGlucose(reading, displayPreferences(ctx, client, age > 0)))
+			printStaleWarning(age)
+		} else if age > 0 {
+			// Keep the short format on a single line (status bars)
+			fmt.Println(cli.FormatGlucoseShort(reading, displayPreferences(ctx, client, true)) + " " + cli.FormatStaleMarker(age))
 		} else {
-			fmt.Println(cli.FormatGlucoseShort(reading))
+			fmt.Println(cli.FormatGlucoseShort(reading, displayPreferences(ctx, client, false)))
 		}
 	},
 }
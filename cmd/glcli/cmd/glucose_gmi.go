@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"sync"
@@ -27,7 +26,7 @@ Examples:
 }
 
 func runGlucoseGmi(cmd *cobra.Command, args []string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := commandContext(30 * time.Second)
 	defer cancel()
 
 	now := time.Now()
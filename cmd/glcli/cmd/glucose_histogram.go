@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/R4yL-dev/glcmd/internal/utils/periodparser"
+	"github.com/spf13/cobra"
+)
+
+var (
+	histogramPeriod string
+	histogramBucket int
+)
+
+var glucoseHistogramCmd = &cobra.Command{
+	Use:   "histogram",
+	Short: "Show the distribution of glucose readings",
+	Long: `Display how many readings fall in each glucose range, as a text histogram.
+Counts are computed by glcore: the readings are not downloaded.
+
+Examples:
+  glcli glucose histogram                     # Last 14 days, 10 mg/dL buckets
+  glcli glucose histogram --period 30d --bucket 20
+  glcli glucose histogram --period all`,
+	Run: func(cmd *cobra.Command, args []string) {
+		start, end, err := periodparser.Parse(histogramPeriod)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(30 * time.Second)
+		defer cancel()
+
+		histogram, err := client.GetGlucoseHistogram(ctx, start, end, histogramBucket)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(histogram)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatHistogram(histogram))
+		}
+	},
+}
+
+func init() {
+	glucoseHistogramCmd.Flags().StringVar(&histogramPeriod, "period", "14d", "Time period (e.g. today, 7d, 4w, all)")
+	glucoseHistogramCmd.Flags().IntVar(&histogramBucket, "bucket", 10, "Bucket size in mg/dL (1-100)")
+	glucoseCmd.AddCommand(glucoseHistogramCmd)
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"time"
@@ -16,6 +15,7 @@ var (
 	historyStart  string
 	historyEnd    string
 	historyLimit  int
+	historyQuery  string
 )
 
 var glucoseHistoryCmd = &cobra.Command{
@@ -35,19 +35,26 @@ Period formats:
 Examples:
   glcli glucose history                 # Last 50 measurements
   glcli glucose history --period 24h    # Last 24 hours
+  glcli glucose history --last 6h       # Same as --period 6h
   glcli glucose history --period 7d     # Last 7 days
   glcli glucose history --period 2w     # Last 2 weeks
   glcli glucose history --start 2025-01-10 --end 2025-01-17
-  glcli glucose history --limit 100     # Change the limit`,
+  glcli glucose history --limit 100     # Change the limit
+
+Filter expressions (--query) combine conditions on value_mgdl, value_mmol,
+color, type, trend and hour (local time) with AND, OR, NOT and parentheses:
+  glcli glucose history --period 7d --query "value_mgdl > 180 AND hour in 0..6"
+  glcli glucose history --period 30d --query "value_mgdl < 70 OR color = 3"`,
 	Run: runGlucoseHistory,
 }
 
 func runGlucoseHistory(cmd *cobra.Command, args []string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := commandContext(30 * time.Second)
 	defer cancel()
 
 	params := cli.GlucoseParams{
 		Limit: historyLimit,
+		Query: historyQuery,
 	}
 
 	now := time.Now()
@@ -108,7 +115,7 @@ func runGlucoseHistory(cmd *cobra.Command, args []string) {
 		}
 		fmt.Println(output)
 	} else {
-		fmt.Println(cli.FormatMeasurementTable(result.Data, result.Pagination.Total))
+		fmt.Println(cli.FormatMeasurementTable(result.Data, result.Pagination.Total, displayPreferences(ctx, client, false)))
 	}
 }
 
@@ -116,6 +123,9 @@ func init() {
 	glucoseHistoryCmd.Flags().StringVar(&historyPeriod, "period", "", "Relative period (e.g., today, 24h, 7d, 2w, 1m)")
 	glucoseHistoryCmd.Flags().StringVar(&historyStart, "start", "", "Start date (YYYY-MM-DD)")
 	glucoseHistoryCmd.Flags().StringVar(&historyEnd, "end", "", "End date (YYYY-MM-DD)")
+	glucoseHistoryCmd.Flags().StringVar(&historyPeriod, "last", "", "Alias for --period")
+	glucoseHistoryCmd.MarkFlagsMutuallyExclusive("period", "last")
 	glucoseHistoryCmd.Flags().IntVar(&historyLimit, "limit", 50, "Maximum number of measurements")
+	glucoseHistoryCmd.Flags().StringVar(&historyQuery, "query", "", "Filter expression (e.g., \"value_mgdl > 180 AND hour in 0..6\")")
 	glucoseCmd.AddCommand(glucoseHistoryCmd)
 }
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+var (
+	percentilesDays   int
+	percentilesBucket time.Duration
+)
+
+var glucosePercentilesCmd = &cobra.Command{
+	Use:   "percentiles",
+	Short: "Show glucose percentiles by time of day",
+	Long: `Display the median and the 5th, 25th, 75th and 95th percentiles of the
+readings by time of day (the data behind an AGP chart).
+
+Examples:
+  glcli glucose percentiles                    # Last 14 days, 30 minute buckets
+  glcli glucose percentiles --days 30 --bucket 1h`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(30 * time.Second)
+		defer cancel()
+
+		percentiles, err := client.GetGlucosePercentiles(ctx, percentilesDays, percentilesBucket)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(percentiles)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatPercentiles(percentiles))
+		}
+	},
+}
+
+func init() {
+	glucosePercentilesCmd.Flags().IntVar(&percentilesDays, "days", 14, "Number of days (1-90)")
+	glucosePercentilesCmd.Flags().DurationVar(&percentilesBucket, "bucket", 30*time.Minute, "Time-of-day bucket (5m-2h, dividing a day)")
+	glucoseCmd.AddCommand(glucosePercentilesCmd)
+}
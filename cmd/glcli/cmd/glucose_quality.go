@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/R4yL-dev/glcmd/internal/utils/periodparser"
+	"github.com/spf13/cobra"
+)
+
+var qualityPeriod string
+
+var glucoseQualityCmd = &cobra.Command{
+	Use:   "quality",
+	Short: "Show daily data quality",
+	Long: `Display the data quality of each day: capture (share of the day covered by
+readings), gaps of 30 minutes or more, artifacts (implausibly fast changes) and
+a 0-100 score. Days scoring below 70 are flagged as low-confidence.
+
+Examples:
+  glcli glucose quality               # Last 14 days
+  glcli glucose quality --period 30d`,
+	Run: func(cmd *cobra.Command, args []string) {
+		duration, err := periodparser.ParseDuration(qualityPeriod)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		end := time.Now()
+		start := end.Add(-duration)
+
+		ctx, cancel := commandContext(30 * time.Second)
+		defer cancel()
+
+		report, err := client.GetGlucoseQuality(ctx, start, end)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(report)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatQuality(report))
+		}
+	},
+}
+
+func init() {
+	glucoseQualityCmd.Flags().StringVar(&qualityPeriod, "period", "14d", "Time period (e.g. 7d, 14d, 4w)")
+	glucoseCmd.AddCommand(glucoseQualityCmd)
+}
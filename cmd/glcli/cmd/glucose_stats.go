@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"time"
@@ -41,7 +40,7 @@ Examples:
 }
 
 func runGlucoseStats(cmd *cobra.Command, args []string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := commandContext(30 * time.Second)
 	defer cancel()
 
 	var start, end *time.Time
@@ -82,7 +81,15 @@ func runGlucoseStats(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	result, err := client.GetGlucoseStatistics(ctx, start, end)
+	// Cache key follows the requested period, not its absolute bounds
+	cacheKey := "glucose-stats-" + statsPeriod
+	if statsStart != "" || statsEnd != "" {
+		cacheKey = "glucose-stats-" + statsStart + "_" + statsEnd
+	}
+
+	result, age, err := cli.FetchCached(cache, cacheKey, allowStale, func() (*cli.StatisticsResponse, error) {
+		return client.GetGlucoseStatistics(ctx, start, end)
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -98,6 +105,7 @@ func runGlucoseStats(cmd *cobra.Command, args []string) {
 	} else {
 		fmt.Println(cli.FormatStatistics(&result.Data))
 	}
+	printStaleWarning(age)
 }
 
 func init() {
@@ -21,6 +21,9 @@ func init() {
 	historyCmd.Flags().StringVar(&historyPeriod, "period", "", "Relative period (e.g., today, 24h, 7d, 2w, 1m)")
 	historyCmd.Flags().StringVar(&historyStart, "start", "", "Start date (YYYY-MM-DD)")
 	historyCmd.Flags().StringVar(&historyEnd, "end", "", "End date (YYYY-MM-DD)")
+	historyCmd.Flags().StringVar(&historyPeriod, "last", "", "Alias for --period")
+	historyCmd.MarkFlagsMutuallyExclusive("period", "last")
 	historyCmd.Flags().IntVar(&historyLimit, "limit", 50, "Maximum number of measurements")
+	historyCmd.Flags().StringVar(&historyQuery, "query", "", "Filter expression (e.g., \"value_mgdl > 180 AND hour in 0..6\")")
 	rootCmd.AddCommand(historyCmd)
 }
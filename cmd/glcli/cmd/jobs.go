@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+// jobPollInterval is how often the progress of a background job is polled.
+const jobPollInterval = 500 * time.Millisecond
+
+var (
+	jobsWait   bool
+	jobsType   string
+	jobsStatus string
+	jobsLimit  int
+)
+
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "List, follow or cancel background jobs",
+	Long: `Display the background jobs of glcore, newest first: imports, replication,
+the morning summary and maintenance. Jobs can be followed or canceled by ID.
+Finished jobs are kept for GLCMD_JOB_RETENTION (7 days by default).
+
+Examples:
+  glcli jobs                            # Last 20 jobs
+  glcli jobs --status failed            # Failed jobs only
+  glcli jobs --type replication         # Replication passes only
+  glcli jobs schedules                  # Recurring jobs and their next run
+  glcli jobs show 3f2a...               # Status, progress and errors
+  glcli jobs show 3f2a... --wait        # Progress bar until the job is finished
+  glcli jobs cancel 3f2a...`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		result, err := client.GetJobs(ctx, cli.JobParams{
+			Type:   jobsType,
+			Status: jobsStatus,
+			Limit:  jobsLimit,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(result)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatJobs(result.Data, result.Pagination.Total))
+		}
+	},
+}
+
+var jobsSchedulesCmd = &cobra.Command{
+	Use:   "schedules",
+	Short: "Show the recurring jobs and their next run",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		schedules, err := client.GetJobSchedules(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(schedules)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatJobSchedules(schedules))
+		}
+	},
+}
+
+var jobsShowCmd = &cobra.Command{
+	Use:   "show ID",
+	Short: "Show the status and progress of a job",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var job *cli.Job
+		if jobsWait {
+			job = followJob(args[0])
+		} else {
+			ctx, cancel := commandContext(10 * time.Second)
+			defer cancel()
+
+			var err error
+			job, err = client.GetJob(ctx, args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		printJob(job)
+	},
+}
+
+var jobsCancelCmd = &cobra.Command{
+	Use:   "cancel ID",
+	Short: "Cancel a pending or running job",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		job, err := client.CancelJob(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput || job.Finished() {
+			printJob(job)
+			return
+		}
+		fmt.Printf("Cancel requested for job %s\n", job.ID)
+	},
+}
+
+func init() {
+	jobsCmd.Flags().StringVar(&jobsType, "type", "", "Filter by job type (e.g. treatmentImport, replication)")
+	jobsCmd.Flags().StringVar(&jobsStatus, "status", "", "Filter by status (pending, running, succeeded, failed, canceled)")
+	jobsCmd.Flags().IntVar(&jobsLimit, "limit", 20, "Maximum number of jobs")
+	jobsShowCmd.Flags().BoolVar(&jobsWait, "wait", false, "Show a progress bar until the job is finished")
+	jobsCmd.AddCommand(jobsSchedulesCmd)
+	jobsCmd.AddCommand(jobsShowCmd)
+	jobsCmd.AddCommand(jobsCancelCmd)
+	rootCmd.AddCommand(jobsCmd)
+}
+
+// printJob prints a job as JSON or text.
+func printJob(job *cli.Job) {
+	if jsonOutput {
+		output, err := cli.FormatJSON(job)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(output)
+		return
+	}
+	fmt.Println(cli.FormatJob(job))
+}
+
+// followJob polls a job until it is finished, drawing a progress bar on
+// stderr (not in JSON mode). Ctrl+C cancels the job on glcore, then waits for
+// it to stop. Exits on connection or API errors.
+func followJob(id string) *cli.Job {
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	go func() {
+		select {
+		case <-sigChan:
+			stop()
+		case <-ctx.Done():
+		}
+	}()
+
+	progress := func(job *cli.Job) {
+		if !jsonOutput {
+			fmt.Fprintf(os.Stderr, "\r%s", cli.FormatJobProgress(job))
+		}
+	}
+
+	job, err := client.WaitForJob(ctx, id, jobPollInterval, progress)
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "\nCanceling job...")
+
+		cancelCtx, cancel := commandContext(30 * time.Second)
+		defer cancel()
+		if _, err = client.CancelJob(cancelCtx, id); err == nil {
+			job, err = client.WaitForJob(cancelCtx, id, jobPollInterval, nil)
+		}
+	}
+	if !jsonOutput {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	return job
+}
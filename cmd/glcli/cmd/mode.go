@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+var exerciseDuration time.Duration
+
+var modeCmd = &cobra.Command{
+	Use:   "mode",
+	Short: "Show the current activity mode",
+	Long: `Display the activity mode glcore evaluates glucose alerts in, and the
+alert thresholds in effect.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		mode, err := client.GetMode(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		printMode(mode)
+	},
+}
+
+var modeExerciseCmd = &cobra.Command{
+	Use:   "exercise",
+	Short: "Start exercise mode",
+	Long: `Temporarily raise the low glucose alert threshold and alert on slower
+falls while exercising. Normal mode resumes after the duration.
+
+Examples:
+  glcli mode exercise                 # For 1 hour
+  glcli mode exercise --duration 45m
+  glcli mode exercise --duration 2h30m`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		mode, err := client.StartExercise(ctx, exerciseDuration)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		printMode(mode)
+	},
+}
+
+var modeNormalCmd = &cobra.Command{
+	Use:   "normal",
+	Short: "End exercise mode",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		mode, err := client.EndExercise(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		printMode(mode)
+	},
+}
+
+// printMode prints a mode as JSON or text depending on --json
+func printMode(mode *cli.ModeStatus) {
+	if jsonOutput {
+		output, err := cli.FormatJSON(mode)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(output)
+	} else {
+		fmt.Println(cli.FormatMode(mode))
+	}
+}
+
+func init() {
+	modeExerciseCmd.Flags().DurationVar(&exerciseDuration, "duration", time.Hour, "How long exercise mode lasts (e.g., 45m, 1h30m)")
+	modeCmd.AddCommand(modeExerciseCmd)
+	modeCmd.AddCommand(modeNormalCmd)
+	rootCmd.AddCommand(modeCmd)
+}
@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/R4yL-dev/glcmd/internal/utils/periodparser"
+	"github.com/spf13/cobra"
+)
+
+const replHelp = `Commands:
+  latest [-v]              Current glucose reading (-v for details)
+  history [period]         Measurements for a period (default 2h)
+  stats [period]           Statistics for a period (default today)
+  sensor                   Current sensor information
+  watch [glucose|sensor]   Stream events until Ctrl+C
+  connect <url>            Switch to another glcore server
+  json [on|off]            Toggle JSON output
+  status                   Show session settings
+  help                     Show this help
+  exit                     Leave the REPL (or Ctrl+D)
+
+Periods: today, Xh, Xd, Xw, Xm, all`
+
+var replCmd = &cobra.Command{
+	Use:   "repl",
+	Short: "Interactive prompt",
+	Long: `Start an interactive prompt to run several queries without relaunching glcli.
+
+The server URL and output mode are kept for the whole session and the
+HTTP connection is reused between commands, which keeps round trips short
+over slow SSH links.
+
+` + replHelp,
+	Run: runREPL,
+}
+
+func init() {
+	rootCmd.AddCommand(replCmd)
+}
+
+// replSession holds the state kept between REPL commands.
+type replSession struct {
+	client  *cli.Client
+	apiURL  string
+	json    bool
+	verbose bool
+}
+
+func runREPL(cmd *cobra.Command, args []string) {
+	session := &replSession{
+		client: client,
+		apiURL: apiURL,
+		json:   jsonOutput,
+	}
+
+	// Ctrl+C only interrupts a running watch, not the REPL itself
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT)
+	defer signal.Stop(sigChan)
+
+	fmt.Printf("glcli %s connected to %s. Type 'help' for commands.\n", Version, session.apiURL)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("glcli> ")
+		if !scanner.Scan() {
+			fmt.Println()
+			return
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Drain a Ctrl+C pressed at the prompt so it doesn't stop the next watch
+		select {
+		case <-sigChan:
+		default:
+		}
+
+		if !session.execute(fields[0], fields[1:], sigChan) {
+			return
+		}
+	}
+}
+
+// execute runs a single REPL command. Returns false when the session should end.
+func (s *replSession) execute(name string, args []string, sigChan <-chan os.Signal) bool {
+	var err error
+
+	switch name {
+	case "latest", "glucose":
+		err = s.latest(args)
+	case "history":
+		err = s.history(args)
+	case "stats":
+		err = s.stats(args)
+	case "sensor":
+		err = s.sensor()
+	case "watch":
+		err = s.watch(args, sigChan)
+	case "connect":
+		err = s.connect(args)
+	case "json":
+		err = s.toggleJSON(args)
+	case "status":
+		fmt.Printf("Server: %s\nJSON:   %t\n", s.apiURL, s.json)
+	case "help", "?":
+		fmt.Println(replHelp)
+	case "exit", "quit", "q":
+		return false
+	default:
+		err = fmt.Errorf("unknown command %q (type 'help')", name)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	return true
+}
+
+func (s *replSession) latest(args []string) error {
+	verbose := len(args) > 0 && (args[0] == "-v" || args[0] == "--verbose")
+
+	ctx, cancel := commandContext(10 * time.Second)
+	defer cancel()
+
+	reading, err := s.client.GetLatestGlucose(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case s.json:
+		return s.printJSON(reading)
+	case verbose:
+		fmt.Println(cli.FormatGlucose(reading, displayPreferences(ctx, s.client, false)))
+	default:
+		fmt.Println(cli.FormatGlucoseShort(reading, displayPreferences(ctx, s.client, false)))
+	}
+	return nil
+}
+
+func (s *replSession) history(args []string) error {
+	period := "2h"
+	if len(args) > 0 {
+		period = args[0]
+	}
+
+	start, end, err := periodparser.Parse(period)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext(30 * time.Second)
+	defer cancel()
+
+	result, err := s.client.GetGlucose(ctx, cli.GlucoseParams{Start: start, End: end, Limit: 1000})
+	if err != nil {
+		return err
+	}
+
+	if s.json {
+		return s.printJSON(result)
+	}
+	fmt.Println(cli.FormatMeasurementTable(result.Data, result.Pagination.Total, displayPreferences(ctx, s.client, false)))
+	return nil
+}
+
+func (s *replSession) stats(args []string) error {
+	period := "today"
+	if len(args) > 0 {
+		period = args[0]
+	}
+
+	start, end, err := periodparser.Parse(period)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext(30 * time.Second)
+	defer cancel()
+
+	result, err := s.client.GetGlucoseStatistics(ctx, start, end)
+	if err != nil {
+		return err
+	}
+
+	if s.json {
+		return s.printJSON(result.Data)
+	}
+	fmt.Println(cli.FormatStatistics(&result.Data))
+	return nil
+}
+
+func (s *replSession) sensor() error {
+	ctx, cancel := commandContext(10 * time.Second)
+	defer cancel()
+
+	sensor, err := s.client.GetLatestSensor(ctx)
+	if err != nil {
+		return err
+	}
+
+	if s.json {
+		return s.printJSON(sensor)
+	}
+	fmt.Println(cli.FormatSensor(sensor))
+	return nil
+}
+
+// watch streams events until the stream ends or Ctrl+C is pressed.
+func (s *replSession) watch(args []string, sigChan <-chan os.Signal) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, errs := s.client.Stream(ctx, args)
+	if !s.json {
+		fmt.Println("Watching for events... (Ctrl+C to return to the prompt)")
+	}
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			formatEvent(event, s.json, false)
+		case err, ok := <-errs:
+			if !ok {
+				return nil
+			}
+			return err
+		case <-sigChan:
+			fmt.Println()
+			return nil
+		}
+	}
+}
+
+func (s *replSession) connect(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: connect <url>")
+	}
+
+	url := strings.TrimRight(args[0], "/")
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return fmt.Errorf("invalid URL %q: must start with http:// or https://", url)
+	}
+
+	s.client = cli.NewClientWithConfig(url, clientConfig())
+	s.apiURL = url
+	fmt.Printf("Connected to %s\n", url)
+	return nil
+}
+
+func (s *replSession) toggleJSON(args []string) error {
+	switch {
+	case len(args) == 0:
+		s.json = !s.json
+	case args[0] == "on":
+		s.json = true
+	case args[0] == "off":
+		s.json = false
+	default:
+		return fmt.Errorf("usage: json [on|off]")
+	}
+
+	fmt.Printf("JSON output: %t\n", s.json)
+	return nil
+}
+
+func (s *replSession) printJSON(v interface{}) error {
+	output, err := cli.FormatJSON(v)
+	if err != nil {
+		return fmt.Errorf("formatting JSON: %w", err)
+	}
+	fmt.Println(output)
+	return nil
+}
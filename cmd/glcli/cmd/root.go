@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/R4yL-dev/glcmd/internal/cli"
 	"github.com/spf13/cobra"
@@ -14,9 +17,16 @@ var (
 	// Global flags
 	jsonOutput bool
 	apiURL     string
+	allowStale bool
+	timeout    time.Duration
+	retries    int
+	apiToken   string
+	profile    string
+	patientID  string
 
-	// Shared client (initialized in PersistentPreRun)
+	// Shared client and response cache (initialized in PersistentPreRun)
 	client *cli.Client
+	cache  *cli.Cache
 )
 
 var rootCmd = &cobra.Command{
@@ -27,7 +37,22 @@ var rootCmd = &cobra.Command{
 A command-line interface for querying glucose readings and sensor
 information from a glcore API server.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		client = cli.NewClient(apiURL)
+		if err := applyProfile(cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		client = cli.NewClientWithConfig(apiURL, clientConfig())
+
+		// Cache is optional: commands still work without a writable cache dir.
+		// Each patient has its own cache, as each server does.
+		if dir, err := cli.DefaultCacheDir(); err == nil {
+			cacheKey := apiURL
+			if patientID != "" {
+				cacheKey += "?patientId=" + patientID
+			}
+			cache = cli.NewCache(dir, cacheKey)
+		}
 	},
 	// When called without subcommand, run glucose
 	Run: func(cmd *cobra.Command, args []string) {
@@ -51,4 +76,96 @@ func init() {
 	// Global persistent flags
 	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for scripting)")
 	rootCmd.PersistentFlags().StringVar(&apiURL, "api-url", defaultAPIURL, "API server URL")
+	rootCmd.PersistentFlags().BoolVar(&allowStale, "allow-stale", false, "Show the last cached value when the server is unreachable")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", cli.DefaultClientConfig().Timeout, "Timeout of a single request attempt")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", cli.DefaultClientConfig().Retries, "Retries on transient network errors (0 to disable)")
+	rootCmd.PersistentFlags().StringVar(&apiToken, "token", "", "API token sent as a bearer token (default $GLCMD_API_TOKEN)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", os.Getenv("GLCMD_PROFILE"), "Profile of the config file to use (default \"default\")")
+	rootCmd.PersistentFlags().StringVar(&patientID, "patient", "", "Patient ID to show the data of, when glcore stores several patients (see glcli connection)")
+}
+
+// applyProfile fills the API URL and token from the selected profile of the
+// config file. The flags and environment variables take precedence.
+func applyProfile(cmd *cobra.Command) error {
+	if profile == "" {
+		profile = cli.DefaultProfile
+	}
+
+	p := cli.Profile{Name: profile}
+	path, err := cli.DefaultConfigPath()
+	switch {
+	case err == nil:
+		if p, err = cli.LoadProfile(path, profile); err != nil {
+			return err
+		}
+	case profile != cli.DefaultProfile:
+		// No config directory (e.g. $HOME unset): only the default profile works
+		return fmt.Errorf("profile %q: %w", profile, err)
+	}
+
+	if p.APIURL != "" && !cmd.Flags().Changed("api-url") && os.Getenv("GLCMD_API_URL") == "" {
+		apiURL = p.APIURL
+	}
+
+	// The token is not a flag default, so that --help never prints it
+	if !cmd.Flags().Changed("token") {
+		apiToken = os.Getenv("GLCMD_API_TOKEN")
+		if apiToken == "" {
+			apiToken = p.Token
+		}
+	}
+	return nil
+}
+
+// clientConfig builds the client settings from the global flags.
+func clientConfig() cli.ClientConfig {
+	config := cli.DefaultClientConfig()
+	config.Timeout = timeout
+	config.Retries = max(retries, 0)
+	config.Token = apiToken
+	config.Profile = profile
+	config.PatientID = patientID
+	return config
+}
+
+// commandContext returns a context bounding a whole command. The deadline is
+// at least minimum and always leaves room for every retry of the client.
+func commandContext(minimum time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), max(minimum, clientConfig().MaxDuration()))
+}
+
+// displayPreferences returns the display preferences of glcore. When the
+// server is offline or has none (older servers), the last cached preferences
+// or the defaults are used: formatting never fails a command.
+func displayPreferences(ctx context.Context, c *cli.Client, offline bool) *cli.Preferences {
+	if !offline {
+		if prefs, err := c.GetPreferences(ctx); err == nil {
+			if cache != nil {
+				// Best effort, as cached readings
+				_ = cache.Save("preferences", prefs)
+			}
+			return prefs
+		}
+	}
+
+	var cached cli.Preferences
+	if cache != nil {
+		if _, err := cache.Load("preferences", &cached); err == nil {
+			return &cached
+		}
+	}
+	return cli.DefaultPreferences()
+}
+
+// printStaleWarning reports that a cached value is displayed.
+// In JSON mode the marker goes to stderr so stdout stays valid JSON.
+func printStaleWarning(age time.Duration) {
+	if age == 0 {
+		return
+	}
+	if jsonOutput {
+		fmt.Fprintf(os.Stderr, "Warning: server unreachable, showing cached value %s\n", cli.FormatStaleMarker(age))
+		return
+	}
+	fmt.Println(cli.FormatStaleMarker(age))
 }
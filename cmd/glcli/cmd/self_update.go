@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+var selfUpdateForce bool
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update glcli to the latest release",
+	Long: `Check GitHub for the latest glcli release and replace the running binary.
+
+The download is verified against the release SHA256SUMS (and its signature
+when the binary was built with a release public key) before the executable
+is atomically replaced. The directory of the binary must be writable.
+
+Examples:
+  glcli self-update
+  sudo glcli self-update          # Installed in /usr/local/bin
+  glcli self-update --force       # Reinstall even if up to date`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		if _, err := selfupdate.New("glcli").Run(ctx, Version, selfUpdateForce, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateForce, "force", false, "Install the latest release even if it is not newer")
+	rootCmd.AddCommand(selfUpdateCmd)
+}
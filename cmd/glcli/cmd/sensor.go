@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"time"
@@ -17,10 +16,12 @@ var sensorCmd = &cobra.Command{
 
 Shows serial number, days elapsed, days remaining, and expiration date.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := commandContext(10 * time.Second)
 		defer cancel()
 
-		sensor, err := client.GetLatestSensor(ctx)
+		sensor, age, err := cli.FetchCached(cache, "sensor-latest", allowStale, func() (*cli.SensorInfo, error) {
+			return client.GetLatestSensor(ctx)
+		})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -35,7 +36,12 @@ Shows serial number, days elapsed, days remaining, and expiration date.`,
 			fmt.Println(output)
 		} else {
 			fmt.Println(cli.FormatSensor(sensor))
+			// Remind to record the site while the sensor is new
+			if sensor.ApplicationSite == "" && sensor.DaysElapsed < 1 {
+				fmt.Println("   Tip: record its site with 'glcli sensor site <site>'")
+			}
 		}
+		printStaleWarning(age)
 	},
 }
 
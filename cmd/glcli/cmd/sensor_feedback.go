@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sensorFeedbackNote   string
+	sensorFeedbackRating int
+)
+
+var sensorFeedbackCmd = &cobra.Command{
+	Use:   "feedback SERIAL",
+	Short: "Record a note and rating for a sensor",
+	Long: `Attach a free-text note and a 1-5 rating to a sensor, e.g. adhesion
+issues or accuracy impressions. Both appear in the sensor list and in the
+sensor statistics.
+
+Only the given flags are changed. An empty note or a rating of 0 clears it.
+
+Examples:
+  glcli sensor feedback 0M0012345 --rating 4
+  glcli sensor feedback 0M0012345 --note "Lifted at the edges on day 10" --rating 2
+  glcli sensor feedback 0M0012345 --note ""`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var note *string
+		var rating *int
+		if cmd.Flags().Changed("note") {
+			note = &sensorFeedbackNote
+		}
+		if cmd.Flags().Changed("rating") {
+			rating = &sensorFeedbackRating
+		}
+		if note == nil && rating == nil {
+			fmt.Fprintln(os.Stderr, "Error: --note or --rating is required")
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		sensor, err := client.UpdateSensorFeedback(ctx, args[0], note, rating)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(sensor)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+			return
+		}
+
+		fmt.Printf("Feedback recorded for sensor %s\n", sensor.SerialNumber)
+		if sensor.Rating != nil {
+			fmt.Printf("   Rating: %d/5\n", *sensor.Rating)
+		}
+		if sensor.Note != "" {
+			fmt.Printf("   Note:   %s\n", sensor.Note)
+		}
+	},
+}
+
+func init() {
+	sensorFeedbackCmd.Flags().StringVar(&sensorFeedbackNote, "note", "", "Free-text note (empty clears it)")
+	sensorFeedbackCmd.Flags().IntVar(&sensorFeedbackRating, "rating", 0, "Rating from 1 to 5 (0 clears it)")
+	sensorCmd.AddCommand(sensorFeedbackCmd)
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"time"
@@ -42,7 +41,7 @@ Examples:
 }
 
 func runSensorHistory(cmd *cobra.Command, args []string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := commandContext(30 * time.Second)
 	defer cancel()
 
 	params := cli.SensorParams{
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var sensorSitesLimit int
+
+var sensorSiteCmd = &cobra.Command{
+	Use:   "site SITE",
+	Short: "Record where the current sensor is applied",
+	Long: `Record the body site of the current sensor.
+
+Warns when the previous sensor was applied to the same site, to help
+rotating sites for skin health.
+
+Sites: ` + strings.Join(domain.SensorApplicationSites, ", ") + `
+
+Examples:
+  glcli sensor site left-arm
+  glcli sensor site right-abdomen`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: domain.SensorApplicationSites,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		result, err := client.SetSensorSite(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(result)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+			return
+		}
+
+		fmt.Printf("Sensor %s applied to %s\n", result.Sensor.SerialNumber, result.Sensor.ApplicationSite)
+		if result.Warning != "" {
+			fmt.Printf("⚠️  Warning: %s\n", result.Warning)
+		}
+	},
+}
+
+var sensorSitesCmd = &cobra.Command{
+	Use:   "sites",
+	Short: "Show sensor application site history",
+	Long: `Display where the most recent sensors were applied, newest first.
+
+Sensors applied to the same site as the previous one are flagged.
+
+Examples:
+  glcli sensor sites
+  glcli sensor sites --limit 20`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		sites, err := client.GetSensorSites(ctx, sensorSitesLimit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(sites)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatSensorSites(sites))
+		}
+	},
+}
+
+func init() {
+	sensorSitesCmd.Flags().IntVar(&sensorSitesLimit, "limit", 10, "Maximum number of sensors")
+	sensorCmd.AddCommand(sensorSiteCmd)
+	sensorCmd.AddCommand(sensorSitesCmd)
+}
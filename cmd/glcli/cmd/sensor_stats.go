@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"time"
@@ -15,6 +14,7 @@ var (
 	sensorStatsPeriod string
 	sensorStatsStart  string
 	sensorStatsEnd    string
+	sensorStatsEnded  string
 )
 
 var sensorStatsCmd = &cobra.Command{
@@ -23,6 +23,8 @@ var sensorStatsCmd = &cobra.Command{
 	Long: `Display sensor lifecycle statistics for a time period.
 
 Shows total sensors, average/min/max duration, and comparison with expected duration.
+The period filters on sensor activation; --ended keeps only the sensors ended
+(replaced) in another period, excluding the current sensor.
 
 Period formats:
   today   Since midnight
@@ -36,12 +38,13 @@ Examples:
   glcli sensor stats                 # All time statistics (default)
   glcli sensor stats --period 90d    # Last 90 days
   glcli sensor stats --period 6m     # Last 6 months
-  glcli sensor stats --start 2025-01-01 --end 2025-06-01`,
+  glcli sensor stats --start 2025-01-01 --end 2025-06-01
+  glcli sensor stats --ended 30d     # Sensors replaced in the last 30 days`,
 	Run: runSensorStats,
 }
 
 func runSensorStats(cmd *cobra.Command, args []string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := commandContext(30 * time.Second)
 	defer cancel()
 
 	var start, end *time.Time
@@ -82,7 +85,13 @@ func runSensorStats(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	result, err := client.GetSensorStatistics(ctx, start, end)
+	endedStart, endedEnd, err := periodparser.Parse(sensorStatsEnded)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	result, err := client.GetSensorStatistics(ctx, start, end, endedStart, endedEnd)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -104,5 +113,6 @@ func init() {
 	sensorStatsCmd.Flags().StringVar(&sensorStatsPeriod, "period", "all", "Time period (today, Xh, Xd, Xw, Xm, all)")
 	sensorStatsCmd.Flags().StringVar(&sensorStatsStart, "start", "", "Start date (YYYY-MM-DD)")
 	sensorStatsCmd.Flags().StringVar(&sensorStatsEnd, "end", "", "End date (YYYY-MM-DD)")
+	sensorStatsCmd.Flags().StringVar(&sensorStatsEnded, "ended", "all", "Only sensors ended in the period (today, Xh, Xd, Xw, Xm, all)")
 	sensorCmd.AddCommand(sensorStatsCmd)
 }
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusFormat      string
+	statusListFormats bool
+	statusFadeAfter   time.Duration
+	statusStaleAfter  time.Duration
+)
+
+// statusFormats lists the output formats of glcli status, for --format
+// validation and --list-formats.
+var statusFormats = []struct {
+	name        string
+	description string
+}{
+	{"text", "One line for status bars (default)"},
+	{"xbar", "xbar/SwiftBar plugin output for the macOS menu bar"},
+	{"tmux", "tmux status-right snippet coloured by status, fading to grey when stale"},
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show a status summary for menu bars",
+	Long: `Display the latest reading, its age and the sensor's remaining days.
+
+Formats:
+  text  One line for status bars (default)
+  xbar  xbar/SwiftBar plugin output: the reading in the menu bar, the last
+        24 hours statistics and the sensor in the dropdown
+  tmux  tmux status-right snippet: the reading coloured by status, fading to
+        grey from --fade-after until it is --stale-after old
+
+The xbar format never fails: when glcore is unreachable the menu bar shows
+"--" and the dropdown the error, so the plugin keeps refreshing. Save a plugin
+such as glcmd.1m.sh (refreshed every minute) in the plugin folder:
+
+  #!/bin/sh
+  # <xbar.title>glcmd</xbar.title>
+  # <xbar.desc>Latest glucose reading from glcore</xbar.desc>
+  exec /usr/local/bin/glcli status --format xbar --allow-stale
+
+The tmux format never fails either. Add it to ~/.tmux.conf (tmux 3.0 or
+later for the colours):
+
+  set -g status-interval 60
+  set -g status-right '#(glcli status --format tmux --allow-stale) %H:%M'
+
+Run glcli status --list-formats to list the formats.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if statusListFormats {
+			for _, f := range statusFormats {
+				fmt.Printf("%-6s %s\n", f.name, f.description)
+			}
+			return
+		}
+
+		valid := false
+		names := make([]string, len(statusFormats))
+		for i, f := range statusFormats {
+			names[i] = f.name
+			valid = valid || f.name == statusFormat
+		}
+		if !valid {
+			fmt.Fprintf(os.Stderr, "Error: invalid format %q (must be %s)\n", statusFormat, strings.Join(names, ", "))
+			os.Exit(1)
+		}
+		if statusFadeAfter >= statusStaleAfter {
+			fmt.Fprintln(os.Stderr, "Error: --fade-after must be shorter than --stale-after")
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		report := &cli.StatusReport{}
+		reading, age, err := cli.FetchCached(cache, "glucose-latest", allowStale, func() (*cli.GlucoseReading, error) {
+			return client.GetLatestGlucose(ctx)
+		})
+		if err != nil {
+			report.Error = err.Error()
+		} else {
+			report.Glucose = reading
+		}
+
+		// The sensor and statistics are optional: a failure only hides them
+		offline := err != nil || age > 0
+		if !offline {
+			if sensor, err := client.GetLatestSensor(ctx); err == nil {
+				report.Sensor = sensor
+			}
+			end := time.Now()
+			start := end.Add(-24 * time.Hour)
+			if stats, err := client.GetGlucoseStatistics(ctx, &start, &end); err == nil {
+				report.Stats = &stats.Data
+			}
+		}
+		report.CheckedAt = time.Now()
+
+		switch {
+		case jsonOutput:
+			output, err := cli.FormatJSON(report)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		case statusFormat == "xbar":
+			statusURL := strings.TrimRight(apiURL, "/") + "/status"
+			fmt.Println(cli.FormatXbar(report, displayPreferences(ctx, client, offline), statusURL))
+		case statusFormat == "tmux":
+			fmt.Println(cli.FormatTmux(report, displayPreferences(ctx, client, offline), statusFadeAfter, statusStaleAfter))
+		case report.Glucose == nil:
+			fmt.Fprintf(os.Stderr, "Error: %s\n", report.Error)
+			os.Exit(1)
+		default:
+			fmt.Println(cli.FormatStatusLine(report, displayPreferences(ctx, client, offline)))
+		}
+	},
+}
+
+func init() {
+	statusCmd.Flags().StringVar(&statusFormat, "format", "text", "Output format (text, xbar, tmux)")
+	statusCmd.Flags().BoolVar(&statusListFormats, "list-formats", false, "List the output formats and exit")
+	statusCmd.Flags().DurationVar(&statusFadeAfter, "fade-after", cli.TmuxFadeAfter, "tmux format: age at which the reading starts fading to grey")
+	statusCmd.Flags().DurationVar(&statusStaleAfter, "stale-after", cli.StatusStaleAfter, "tmux format: age at which the reading is grey")
+	rootCmd.AddCommand(statusCmd)
+}
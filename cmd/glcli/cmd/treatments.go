@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/utils/periodparser"
+	"github.com/spf13/cobra"
+)
+
+var (
+	treatmentsPeriod string
+	treatmentsSource string
+	analysisPeriod   string
+)
+
+var treatmentsCmd = &cobra.Command{
+	Use:   "treatments",
+	Short: "Show insulin treatments imported from pump exports",
+	Long: `Display boluses and basal segments imported from insulin pump exports,
+oldest first, with bolus and basal totals.
+
+Examples:
+  glcli treatments                      # Last 24 hours
+  glcli treatments --period 7d          # Last 7 days
+  glcli treatments import --source tandem tconnect.csv
+  glcli treatments analysis             # Glucose response to boluses`,
+	Run: func(cmd *cobra.Command, args []string) {
+		duration, err := periodparser.ParseDuration(treatmentsPeriod)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		end := time.Now()
+		start := end.Add(-duration)
+
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		treatments, err := client.GetTreatments(ctx, start, end)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(treatments)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatTreatments(treatments))
+		}
+	},
+}
+
+var treatmentsImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import boluses and basal from a pump CSV export",
+	Long: `Upload an insulin pump CSV export to glcore.
+
+Sources:
+  omnipod   Omnipod data exported from Glooko (bolus_data.csv, basal_data.csv)
+  tandem    Tandem t:connect CSV export
+
+Timestamps are read in the pump's local time, assumed to match glcore's.
+Importing the same export again does not create duplicates.
+
+The import runs in the background on glcore with a progress bar; Ctrl+C
+cancels it and nothing is imported.
+
+Examples:
+  glcli treatments import --source tandem tconnect.csv
+  glcli treatments import --source omnipod bolus_data.csv`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := os.Open(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+
+		ctx, cancel := commandContext(time.Minute)
+		defer cancel()
+
+		// The import runs as a background job on glcore: follow its progress
+		job, err := client.StartTreatmentImport(ctx, treatmentsSource, file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		job = followJob(job.ID)
+		if job.Status != "succeeded" {
+			printJob(job)
+			os.Exit(1)
+		}
+
+		var result cli.TreatmentImportResult
+		if err := json.Unmarshal(job.Result, &result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid import result: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(result)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+			return
+		}
+
+		fmt.Printf("Imported %d treatment(s), %d already imported\n", result.Imported, result.Duplicates)
+		if result.Skipped > 0 {
+			fmt.Printf("⚠️  Skipped %d row(s) that could not be parsed\n", result.Skipped)
+		}
+	},
+}
+
+var treatmentsAnalysisCmd = &cobra.Command{
+	Use:   "analysis",
+	Short: "Show how glucose responds to boluses",
+	Long: `Follow glucose for 3 hours after each bolus and summarize the response.
+
+Meal boluses (with carbs) are grouped by time of day with the average rise per
+10 g of carbs; boluses without carbs are reported as corrections. Boluses
+followed by another bolus within 3 hours are left out.
+
+Examples:
+  glcli treatments analysis             # Last 14 days
+  glcli treatments analysis --period 30d`,
+	Run: func(cmd *cobra.Command, args []string) {
+		duration, err := periodparser.ParseDuration(analysisPeriod)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		end := time.Now()
+		start := end.Add(-duration)
+
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		analysis, err := client.GetTreatmentAnalysis(ctx, start, end)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(analysis)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatTreatmentAnalysis(analysis))
+		}
+	},
+}
+
+func init() {
+	treatmentsCmd.Flags().StringVar(&treatmentsPeriod, "period", "24h", "Time period (e.g. 24h, 7d, 2w)")
+	treatmentsImportCmd.Flags().StringVar(&treatmentsSource, "source", "", "Export source ("+strings.Join(domain.TreatmentSources, ", ")+")")
+	treatmentsImportCmd.MarkFlagRequired("source")
+	treatmentsAnalysisCmd.Flags().StringVar(&analysisPeriod, "period", "14d", "Time period (e.g. 14d, 4w)")
+	treatmentsCmd.AddCommand(treatmentsImportCmd)
+	treatmentsCmd.AddCommand(treatmentsAnalysisCmd)
+	rootCmd.AddCommand(treatmentsCmd)
+}
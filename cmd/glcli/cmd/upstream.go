@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+var upstreamDays int
+
+var upstreamCmd = &cobra.Command{
+	Use:   "upstream",
+	Short: "Show LibreView availability and outages",
+	Long: `Display whether LibreView is reachable, its uptime over the last 24h, 7d and
+30d, and the recorded outages, to tell an upstream outage from a setup problem.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		status, err := client.GetUpstreamStatus(ctx, upstreamDays)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(status)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+		} else {
+			fmt.Println(cli.FormatUpstreamStatus(status))
+		}
+	},
+}
+
+func init() {
+	upstreamCmd.Flags().IntVar(&upstreamDays, "days", 30, "Days of outage history to show")
+	rootCmd.AddCommand(upstreamCmd)
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+var (
+	viewLimit       int
+	viewQuery       string
+	viewPeriod      string
+	viewAggregation string
+	viewDescription string
+)
+
+var viewCmd = &cobra.Command{
+	Use:   "view [NAME]",
+	Short: "Run a saved view",
+	Long: `Run a saved view: a named filter expression, period and aggregation
+stored by glcore, so common analyses are one command away.
+
+Without a name, the saved views are listed.
+
+Examples:
+  glcli view                                   # List saved views
+  glcli view nights-last-month                 # Run a view
+  glcli view save nights-last-month --query "hour in 0..6" --period 30d --aggregation stats
+  glcli view save highs --query "value_mgdl > 180" --period 7d
+  glcli view delete highs`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			listViews()
+			return
+		}
+
+		ctx, cancel := commandContext(15 * time.Second)
+		defer cancel()
+
+		result, err := client.RunView(ctx, args[0], viewLimit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			output, err := cli.FormatJSON(result)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(output)
+			return
+		}
+
+		if result.Data.Statistics != nil {
+			fmt.Println(cli.FormatStatistics(result.Data.Statistics))
+			return
+		}
+		total := len(result.Data.Measurements)
+		if result.Pagination != nil {
+			total = result.Pagination.Total
+		}
+		fmt.Println(cli.FormatMeasurementTable(result.Data.Measurements, total, displayPreferences(ctx, client, false)))
+	},
+}
+
+var viewListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved views",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listViews()
+	},
+}
+
+var viewSaveCmd = &cobra.Command{
+	Use:   "save NAME",
+	Short: "Create or replace a saved view",
+	Long: `Create a saved view, or replace the view with the same name.
+
+Names are lowercase letters, digits and dashes. The query uses the
+filter expression syntax of glcli glucose history --query, and the
+period is resolved each time the view runs (e.g. 30d = the 30 days
+before now). The aggregation is "list" (measurements) or "stats".
+
+Examples:
+  glcli view save nights-last-month --query "hour in 0..6" --period 30d --aggregation stats
+  glcli view save lows --query "value_mgdl < 70" --description "All lows"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		view, err := client.SaveView(ctx, cli.SavedView{
+			Name:        args[0],
+			Description: viewDescription,
+			Query:       viewQuery,
+			Period:      viewPeriod,
+			Aggregation: viewAggregation,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("View %s saved\n", view.Name)
+	},
+}
+
+var viewDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Delete a saved view",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(10 * time.Second)
+		defer cancel()
+
+		if err := client.DeleteView(ctx, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("View %s deleted\n", args[0])
+	},
+}
+
+// listViews prints the saved views.
+func listViews() {
+	ctx, cancel := commandContext(10 * time.Second)
+	defer cancel()
+
+	views, err := client.GetViews(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		output, err := cli.FormatJSON(views)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(output)
+	} else {
+		fmt.Println(cli.FormatViews(views))
+	}
+}
+
+func init() {
+	viewCmd.Flags().IntVar(&viewLimit, "limit", 0, "Maximum number of measurements for list views (default: server default)")
+	viewSaveCmd.Flags().StringVar(&viewQuery, "query", "", "Filter expression (default: all measurements)")
+	viewSaveCmd.Flags().StringVar(&viewPeriod, "period", "", "Period relative to now, e.g. 24h, 30d, today (default: all time)")
+	viewSaveCmd.Flags().StringVar(&viewAggregation, "aggregation", "list", "Aggregation: list or stats")
+	viewSaveCmd.Flags().StringVar(&viewDescription, "description", "", "Description shown in listings")
+	viewCmd.AddCommand(viewListCmd)
+	viewCmd.AddCommand(viewSaveCmd)
+	viewCmd.AddCommand(viewDeleteCmd)
+	rootCmd.AddCommand(viewCmd)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by glcli wait
+const (
+	waitExitMet     = 0 // Condition met
+	waitExitError   = 1 // Connection or API error
+	waitExitTimeout = 2 // Timeout elapsed before the condition was met
+)
+
+var (
+	waitBelow    float64
+	waitAbove    float64
+	waitMmol     bool
+	waitTimeout  time.Duration
+	waitInterval time.Duration
+)
+
+var waitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Block until glucose crosses a threshold",
+	Long: `Wait until a glucose reading is below or above a threshold, then exit.
+
+The latest reading is checked first, then new readings are received in
+real time via SSE (falling back to polling if streaming is unavailable).
+Thresholds are in mg/dL unless --mmol is given.
+
+Exit codes:
+  0  Condition met (the matching reading is printed)
+  1  Error (server unreachable, invalid flags)
+  2  Timeout elapsed before the condition was met
+
+Examples:
+  glcli wait --below 70 --timeout 2h && dim-lights
+  glcli wait --above 180 --timeout 30m
+  glcli wait --below 3.9 --mmol
+  glcli wait --below 70 --above 250   # Whichever comes first`,
+	Run: runWait,
+}
+
+func init() {
+	waitCmd.Flags().Float64Var(&waitBelow, "below", 0, "Wait for a reading below this value")
+	waitCmd.Flags().Float64Var(&waitAbove, "above", 0, "Wait for a reading above this value")
+	waitCmd.Flags().BoolVar(&waitMmol, "mmol", false, "Thresholds are in mmol/L instead of mg/dL")
+	waitCmd.Flags().DurationVar(&waitTimeout, "timeout", time.Hour, "Maximum time to wait")
+	waitCmd.Flags().DurationVar(&waitInterval, "interval", time.Minute, "Polling interval when SSE is unavailable")
+	rootCmd.AddCommand(waitCmd)
+}
+
+func runWait(cmd *cobra.Command, args []string) {
+	var cond cli.GlucoseCondition
+	if cmd.Flags().Changed("below") {
+		below := toMgDl(waitBelow, waitMmol)
+		cond.BelowMgDl = &below
+	}
+	if cmd.Flags().Changed("above") {
+		above := toMgDl(waitAbove, waitMmol)
+		cond.AboveMgDl = &above
+	}
+	if cond.BelowMgDl == nil && cond.AboveMgDl == nil {
+		fmt.Fprintln(os.Stderr, "Error: at least one of --below or --above is required")
+		os.Exit(waitExitError)
+	}
+	if waitTimeout <= 0 || waitInterval <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --timeout and --interval must be positive")
+		os.Exit(waitExitError)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+
+	// Handle Ctrl+C gracefully
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	reading, err := client.WaitForGlucose(ctx, cond, waitInterval)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			if !jsonOutput {
+				fmt.Fprintf(os.Stderr, "Timeout: glucose not %s after %s\n", cond, waitTimeout)
+			}
+			os.Exit(waitExitTimeout)
+		}
+		if errors.Is(err, context.Canceled) {
+			os.Exit(waitExitError)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(waitExitError)
+	}
+
+	if jsonOutput {
+		output, err := cli.FormatJSON(reading)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+			os.Exit(waitExitError)
+		}
+		fmt.Println(output)
+	} else {
+		// The wait may have used up ctx
+		prefsCtx, prefsCancel := commandContext(5 * time.Second)
+		fmt.Println(cli.FormatGlucoseShort(reading, displayPreferences(prefsCtx, client, false)))
+		prefsCancel()
+	}
+	os.Exit(waitExitMet)
+}
+
+// toMgDl converts a threshold to mg/dL.
+func toMgDl(value float64, mmol bool) float64 {
+	if mmol {
+		return value * 18.0182
+	}
+	return value
+}
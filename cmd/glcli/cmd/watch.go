@@ -23,20 +23,23 @@ var watchCmd = &cobra.Command{
 	Short: "Stream real-time events (glucose measurements, sensor changes)",
 	Long: `Stream events from glcore in real-time using Server-Sent Events (SSE).
 
-By default, streams all event types (glucose, sensor).
+By default, streams all event types (glucose, sensor, summary, config, alert).
 Keepalive events are hidden by default. Use --verbose to show them.
 
 Examples:
   glcli watch                  # All events
   glcli watch --only glucose   # Glucose only
   glcli watch --only sensor    # Sensor changes only
+  glcli watch --only summary   # Morning summaries only
+  glcli watch --only config    # LibreLink app settings changes only
+  glcli watch --only alert     # Alerts and sensor expiry reminders only
   glcli watch --json           # JSON output for scripting
   glcli watch --verbose        # Show keepalive events`,
 	Run: runWatch,
 }
 
 func init() {
-	watchCmd.Flags().StringVar(&onlyFlag, "only", "", "Filter by event type (glucose, sensor)")
+	watchCmd.Flags().StringVar(&onlyFlag, "only", "", "Filter by event type (glucose, sensor, summary, config, alert)")
 	watchCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show keepalive events")
 	rootCmd.AddCommand(watchCmd)
 }
@@ -123,6 +126,12 @@ func formatEvent(event cli.SSEEvent, jsonMode bool, verbose bool) {
 		formatGlucoseEvent(event.Data)
 	case "sensor":
 		formatSensorEvent(event.Data)
+	case "summary":
+		formatSummaryEvent(event.Data)
+	case "config":
+		formatConfigEvent(event.Data)
+	case "alert":
+		formatAlertEvent(event.Data)
 	case "keepalive":
 		// Only shown if verbose (already filtered above)
 		fmt.Printf("[%s] · keepalive\n", time.Now().Format("15:04:05"))
@@ -171,6 +180,62 @@ func formatSensorEvent(data []byte) {
 	fmt.Printf("         Expires: %s (%d days)\n", formatDateTime(sensor.ExpiresAt), sensor.DurationDays)
 }
 
+func formatSummaryEvent(data []byte) {
+	var summary cli.MorningSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		fmt.Printf("[%s] Failed to parse summary event\n", time.Now().Format("15:04:05"))
+		return
+	}
+
+	timestamp := time.Now().Format("15:04:05")
+	fmt.Printf("[%s] 🌅 Morning summary (%s → %s)\n", timestamp, formatDateTime(summary.Start), formatDateTime(summary.End))
+	if summary.Count == 0 {
+		fmt.Println("         No measurements overnight")
+	} else {
+		fmt.Printf("         Min: %.1f mmol/L (%d mg/dL)  Max: %.1f mmol/L (%d mg/dL)\n",
+			summary.Min, summary.MinMgDl, summary.Max, summary.MaxMgDl)
+		fmt.Printf("         Time low (<%d mg/dL): %d min\n", summary.LowThresholdMgDl, summary.TimeLowMinutes)
+	}
+	if summary.Current != nil {
+		fmt.Printf("         Current: %.1f mmol/L (%d mg/dL) %s\n",
+			summary.Current.Value, summary.Current.ValueInMgPerDl, cli.TrendArrowText(summary.Current.TrendArrow))
+	}
+}
+
+func formatConfigEvent(data []byte) {
+	var change cli.ConfigChange
+	if err := json.Unmarshal(data, &change); err != nil {
+		fmt.Printf("[%s] Failed to parse config event\n", time.Now().Format("15:04:05"))
+		return
+	}
+
+	timestamp := time.Now().Format("15:04:05")
+	if d := change.Device; d != nil {
+		alarms := "off"
+		if d.AlarmsEnabled {
+			alarms = "on"
+		}
+		fmt.Printf("[%s] ⚙️  App alarms %s: low %d mg/dL, high %d mg/dL\n", timestamp, alarms, d.LowLimit, d.HighLimit)
+	}
+	if t := change.Targets; t != nil {
+		fmt.Printf("[%s] ⚙️  Targets: %d-%d mg/dL\n", timestamp, t.TargetLow, t.TargetHigh)
+	}
+}
+
+func formatAlertEvent(data []byte) {
+	var alert struct {
+		Rule    string `json:"rule"`
+		Title   string `json:"title"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &alert); err != nil {
+		fmt.Printf("[%s] Failed to parse alert event\n", time.Now().Format("15:04:05"))
+		return
+	}
+
+	fmt.Printf("[%s] 🔔 %s: %s\n", time.Now().Format("15:04:05"), alert.Title, alert.Message)
+}
+
 func formatDateTime(isoTimestamp string) string {
 	// Parse and reformat for readability
 	t, err := time.Parse(time.RFC3339, isoTimestamp)
@@ -0,0 +1,117 @@
+// Command glcmd-loadtest generates concurrent API traffic against a running
+// glcore instance and reports latency percentiles per traffic profile:
+// widgets polling the latest reading, clients exporting a week of history and
+// SSE clients. With -budget it exits with status 1 when a p95 latency exceeds
+// its budget, to check the performance budgets of docs/PERFORMANCE.md.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run runs the load test and returns the exit code.
+func run(args []string) int {
+	defaultURL := os.Getenv("GLCMD_API_URL")
+	if defaultURL == "" {
+		defaultURL = "http://localhost:8080"
+	}
+
+	flags := flag.NewFlagSet("glcmd-loadtest", flag.ContinueOnError)
+	apiURL := flags.String("url", defaultURL, "Base URL of the glcore API")
+	duration := flags.Duration("duration", 30*time.Second, "Length of the test")
+	widgets := flags.Int("widgets", 20, "Concurrent widgets polling the latest reading")
+	widgetInterval := flags.Duration("widget-interval", time.Second, "Polling interval of each widget")
+	history := flags.Int("history", 2, "Concurrent clients exporting a week of history")
+	sseClients := flags.Int("sse", 10, "Concurrent SSE clients")
+	timeout := flags.Duration("timeout", 10*time.Second, "Timeout of each request")
+	budgetSpec := flags.String("budget", "", "p95 latency budgets, e.g. widget=50ms,history=1s,sse=100ms")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	budgets, err := parseBudgets(*budgetSpec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -budget: %v\n", err)
+		return 2
+	}
+	if *widgets < 0 || *history < 0 || *sseClients < 0 || *widgets+*history+*sseClients == 0 {
+		fmt.Fprintln(os.Stderr, "Error: at least one of -widgets, -history and -sse must be positive")
+		return 2
+	}
+	if *duration <= 0 || *widgetInterval <= 0 || *timeout <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: -duration, -widget-interval and -timeout must be positive")
+		return 2
+	}
+
+	// Enough idle connections for every worker to reuse its own
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = *widgets + *history + *sseClients
+	test := &loadTest{
+		baseURL:  strings.TrimRight(*apiURL, "/"),
+		client:   &http.Client{Transport: transport, Timeout: *timeout},
+		stream:   &http.Client{Transport: transport},
+		recorder: newRecorder(),
+	}
+
+	// Fail fast if the instance is not reachable
+	checkCtx, cancelCheck := context.WithTimeout(context.Background(), *timeout)
+	err = test.get(checkCtx, "/health")
+	cancelCheck()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s is not reachable: %v\n", test.baseURL, err)
+		return 1
+	}
+
+	fmt.Printf("Load testing %s for %s: %d widgets every %s, %d history exports, %d SSE clients\n\n",
+		test.baseURL, *duration, *widgets, *widgetInterval, *history, *sseClients)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	spawn := func(n int, worker func(context.Context)) {
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				worker(ctx)
+			}()
+		}
+	}
+	spawn(*widgets, func(ctx context.Context) { test.widget(ctx, *widgetInterval) })
+	spawn(*history, test.history)
+	spawn(*sseClients, test.sse)
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	results := test.recorder.results()
+	fmt.Println(formatReport(results, elapsed, test.recorder.events))
+
+	if violations := checkBudgets(results, budgets); len(violations) > 0 {
+		fmt.Println("\nBudget violations:")
+		for _, v := range violations {
+			fmt.Printf("  - %s\n", v)
+		}
+		return 1
+	}
+	if len(budgets) > 0 {
+		fmt.Println("\nAll budgets met")
+	}
+	return 0
+}
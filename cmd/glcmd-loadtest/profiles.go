@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// historyRange is the period each history export requests.
+const historyRange = 7 * 24 * time.Hour
+
+// loadTest sends the traffic of each profile to an API until its context ends.
+type loadTest struct {
+	baseURL  string
+	client   *http.Client // Requests with a timeout
+	stream   *http.Client // SSE connections, without timeout
+	recorder *recorder
+}
+
+// get requests path and reads the whole response. Returns an error for
+// transport errors and non-200 responses.
+func (l *loadTest) get(ctx context.Context, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// timed sends a request of profile and records its outcome. Requests cut
+// short by the end of the test are not recorded.
+func (l *loadTest) timed(ctx context.Context, profile, path string) {
+	start := time.Now()
+	err := l.get(ctx, path)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		l.recorder.fail(profile)
+		return
+	}
+	l.recorder.record(profile, time.Since(start))
+}
+
+// widget polls the latest reading every interval, as a home screen widget does.
+func (l *loadTest) widget(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		l.timed(ctx, profileWidget, "/v1/glucose/latest")
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// history exports the last week of readings back to back.
+func (l *loadTest) history(ctx context.Context) {
+	for ctx.Err() == nil {
+		end := time.Now().UTC()
+		query := url.Values{}
+		query.Set("start", end.Add(-historyRange).Format(time.RFC3339))
+		query.Set("end", end.Format(time.RFC3339))
+		query.Set("limit", "1000")
+		l.timed(ctx, profileHistory, "/v1/glucose?"+query.Encode())
+	}
+}
+
+// sse connects to the event stream and reads events until the end of the
+// test. The recorded latency is the time to establish the stream; the client
+// reconnects if the stream ends early.
+func (l *loadTest) sse(ctx context.Context) {
+	for ctx.Err() == nil {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.baseURL+"/v1/stream", nil)
+		if err != nil {
+			l.recorder.fail(profileSSE)
+			return
+		}
+
+		start := time.Now()
+		resp, err := l.stream.Do(req)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil || resp.StatusCode != http.StatusOK {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			l.recorder.fail(profileSSE)
+			time.Sleep(time.Second) // Do not hammer a failing server
+			continue
+		}
+		l.recorder.record(profileSSE, time.Since(start))
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "event: ") {
+				l.recorder.event()
+			}
+		}
+		resp.Body.Close()
+	}
+}
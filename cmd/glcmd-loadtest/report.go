@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Traffic profiles
+const (
+	profileWidget  = "widget"  // Widgets polling the latest reading
+	profileHistory = "history" // Clients exporting a week of history
+	profileSSE     = "sse"     // Clients connecting to the event stream
+)
+
+// profiles lists the traffic profiles in report order.
+var profiles = []string{profileWidget, profileHistory, profileSSE}
+
+// recorder collects the request latencies and errors of each profile.
+type recorder struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+	events    int // SSE events received
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		latencies: make(map[string][]time.Duration),
+		errors:    make(map[string]int),
+	}
+}
+
+// record adds the latency of a successful request.
+func (r *recorder) record(profile string, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies[profile] = append(r.latencies[profile], latency)
+}
+
+// fail counts a failed request.
+func (r *recorder) fail(profile string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors[profile]++
+}
+
+// event counts an SSE event received.
+func (r *recorder) event() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events++
+}
+
+// profileResult summarizes the requests of one profile.
+type profileResult struct {
+	Profile  string
+	Requests int
+	Errors   int
+	P50      time.Duration
+	P90      time.Duration
+	P95      time.Duration
+	P99      time.Duration
+	Max      time.Duration
+}
+
+// results returns the summary of each profile that sent requests, in report order.
+func (r *recorder) results() []profileResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var results []profileResult
+	for _, profile := range profiles {
+		latencies := append([]time.Duration(nil), r.latencies[profile]...)
+		if len(latencies) == 0 && r.errors[profile] == 0 {
+			continue
+		}
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		result := profileResult{
+			Profile:  profile,
+			Requests: len(latencies) + r.errors[profile],
+			Errors:   r.errors[profile],
+		}
+		if len(latencies) > 0 {
+			result.P50 = percentile(latencies, 50)
+			result.P90 = percentile(latencies, 90)
+			result.P95 = percentile(latencies, 95)
+			result.P99 = percentile(latencies, 99)
+			result.Max = latencies[len(latencies)-1]
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// percentile returns the p-th percentile of sorted latencies (nearest rank).
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// parseBudgets parses a comma-separated list of profile=duration p95 budgets,
+// e.g. "widget=50ms,history=1s,sse=100ms".
+func parseBudgets(s string) (map[string]time.Duration, error) {
+	budgets := make(map[string]time.Duration)
+	if strings.TrimSpace(s) == "" {
+		return budgets, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		profile, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid budget %q: expected profile=duration", part)
+		}
+		if !isProfile(profile) {
+			return nil, fmt.Errorf("unknown profile %q (must be %s)", profile, strings.Join(profiles, ", "))
+		}
+		budget, err := time.ParseDuration(value)
+		if err != nil || budget <= 0 {
+			return nil, fmt.Errorf("invalid %s budget %q: must be a positive duration", profile, value)
+		}
+		budgets[profile] = budget
+	}
+	return budgets, nil
+}
+
+func isProfile(name string) bool {
+	for _, p := range profiles {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBudgets returns a message for each profile whose p95 latency exceeds
+// its budget or that had failed requests.
+func checkBudgets(results []profileResult, budgets map[string]time.Duration) []string {
+	var violations []string
+	for _, r := range results {
+		if r.Errors > 0 {
+			violations = append(violations, fmt.Sprintf("%s: %d of %d requests failed", r.Profile, r.Errors, r.Requests))
+		}
+		if budget, ok := budgets[r.Profile]; ok && r.P95 > budget {
+			violations = append(violations, fmt.Sprintf("%s: p95 %s exceeds the %s budget", r.Profile, formatLatency(r.P95), budget))
+		}
+	}
+	return violations
+}
+
+// formatReport formats the results as a table.
+func formatReport(results []profileResult, elapsed time.Duration, events int) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%-8s %9s %7s %8s %9s %9s %9s %9s %9s\n",
+		"PROFILE", "REQUESTS", "ERRORS", "REQ/S", "P50", "P90", "P95", "P99", "MAX"))
+	for _, r := range results {
+		sb.WriteString(fmt.Sprintf("%-8s %9d %7d %8.1f %9s %9s %9s %9s %9s\n",
+			r.Profile, r.Requests, r.Errors, float64(r.Requests)/elapsed.Seconds(),
+			formatLatency(r.P50), formatLatency(r.P90), formatLatency(r.P95), formatLatency(r.P99), formatLatency(r.Max)))
+	}
+	sb.WriteString(fmt.Sprintf("\nDuration: %s, SSE events received: %d", elapsed.Round(time.Millisecond), events))
+
+	return sb.String()
+}
+
+// formatLatency rounds a latency for display.
+func formatLatency(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
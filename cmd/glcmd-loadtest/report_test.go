@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{50, 50 * time.Millisecond},
+		{95, 95 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+		{0, time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := percentile(latencies, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestParseBudgets(t *testing.T) {
+	budgets, err := parseBudgets("widget=50ms, history=1s")
+	if err != nil {
+		t.Fatalf("parseBudgets failed: %v", err)
+	}
+	if budgets[profileWidget] != 50*time.Millisecond || budgets[profileHistory] != time.Second || len(budgets) != 2 {
+		t.Errorf("unexpected budgets: %v", budgets)
+	}
+
+	for _, spec := range []string{"widget", "widget=fast", "widget=0s", "export=1s"} {
+		if _, err := parseBudgets(spec); err == nil {
+			t.Errorf("expected error for %q, got nil", spec)
+		}
+	}
+}
+
+func TestCheckBudgets(t *testing.T) {
+	results := []profileResult{
+		{Profile: profileWidget, Requests: 100, P95: 40 * time.Millisecond},
+		{Profile: profileHistory, Requests: 10, P95: 2 * time.Second},
+		{Profile: profileSSE, Requests: 5, Errors: 1, P95: time.Millisecond},
+	}
+	budgets := map[string]time.Duration{
+		profileWidget:  50 * time.Millisecond,
+		profileHistory: time.Second,
+	}
+
+	violations := checkBudgets(results, budgets)
+	if len(violations) != 2 {
+		t.Fatalf("expected the history budget and the SSE errors as violations, got %v", violations)
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/stream":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: keepalive\ndata: {}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			fmt.Fprint(w, `{"data":{}}`)
+		}
+	}))
+	defer server.Close()
+
+	args := []string{"-url", server.URL, "-duration", "300ms", "-widgets", "2", "-widget-interval", "50ms", "-history", "1", "-sse", "2"}
+	if code := run(append(args, "-budget", "widget=1s")); code != 0 {
+		t.Errorf("expected exit code 0, got %d", code)
+	}
+	if code := run(append(args, "-budget", "widget=1ns")); code != 1 {
+		t.Errorf("expected exit code 1 for an exceeded budget, got %d", code)
+	}
+	if code := run([]string{"-url", server.URL, "-widgets", "0", "-history", "0", "-sse", "0"}); code != 2 {
+		t.Errorf("expected exit code 2 without traffic, got %d", code)
+	}
+}
@@ -0,0 +1,112 @@
+//go:build js && wasm
+
+// Command glcmd-wasm exposes the read-only glclient to JavaScript, for
+// browser dashboards that want the typed client instead of raw fetch calls.
+//
+// Build with "make build-wasm" and load bin/glcmd.wasm with wasm_exec.js from
+// the Go distribution. It defines a global glcmd object:
+//
+//	const client = glcmd.client("http://localhost:8080", token);
+//	const latest = await client.latest();         // Measurement as JSON object
+//	const stop = client.stream(["glucose"], (type, data) => { ... });
+//	stop();                                       // Closes the stream
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
+)
+
+func main() {
+	js.Global().Set("glcmd", js.ValueOf(map[string]any{
+		"client": js.FuncOf(newClient),
+	}))
+	select {} // Keep the Go runtime alive for the callbacks
+}
+
+// newClient implements glcmd.client(baseURL, token).
+func newClient(this js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return jsError("glcmd.client: missing base URL")
+	}
+	client := glclient.NewClient(args[0].String())
+	if len(args) > 1 && args[1].Type() == js.TypeString {
+		client.Token = args[1].String()
+	}
+
+	return js.ValueOf(map[string]any{
+		"latest": js.FuncOf(func(this js.Value, args []js.Value) any {
+			return promise(func() (any, error) { return client.Latest(context.Background()) })
+		}),
+		"latestSensor": js.FuncOf(func(this js.Value, args []js.Value) any {
+			return promise(func() (any, error) { return client.LatestSensor(context.Background()) })
+		}),
+		"capabilities": js.FuncOf(func(this js.Value, args []js.Value) any {
+			return promise(func() (any, error) { return client.Capabilities(context.Background()) })
+		}),
+		"stream": js.FuncOf(func(this js.Value, args []js.Value) any {
+			return stream(client, args)
+		}),
+	})
+}
+
+// stream implements client.stream(types, callback) and returns a stop function.
+func stream(client *glclient.Client, args []js.Value) any {
+	if len(args) < 2 || args[1].Type() != js.TypeFunction {
+		return jsError("stream: expected (types, callback)")
+	}
+	var types []string
+	for i := 0; i < args[0].Length(); i++ {
+		types = append(types, args[0].Index(i).String())
+	}
+	callback := args[1]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		err := client.Stream(ctx, types, func(event glclient.Event) error {
+			callback.Invoke(event.Type, js.Global().Get("JSON").Call("parse", string(event.Data)))
+			return nil
+		})
+		if err != nil && ctx.Err() == nil {
+			js.Global().Get("console").Call("error", "glcmd stream: "+err.Error())
+		}
+	}()
+
+	return js.FuncOf(func(this js.Value, args []js.Value) any {
+		cancel()
+		return nil
+	})
+}
+
+// promise runs fn outside the JavaScript event loop and returns a Promise of
+// its JSON-encoded result. Blocking HTTP calls must not run on the callback
+// goroutine.
+func promise(fn func() (any, error)) js.Value {
+	executor := js.FuncOf(func(this js.Value, args []js.Value) any {
+		resolve, reject := args[0], args[1]
+		go func() {
+			result, err := fn()
+			if err != nil {
+				reject.Invoke(jsError(err.Error()))
+				return
+			}
+			data, err := json.Marshal(result)
+			if err != nil {
+				reject.Invoke(jsError(err.Error()))
+				return
+			}
+			resolve.Invoke(js.Global().Get("JSON").Call("parse", string(data)))
+		}()
+		return nil
+	})
+	defer executor.Release() // Promise calls the executor synchronously
+	return js.Global().Get("Promise").New(executor)
+}
+
+// jsError creates a JavaScript Error.
+func jsError(message string) js.Value {
+	return js.Global().Get("Error").New(message)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/backfill"
+	"github.com/R4yL-dev/glcmd/internal/config"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+// runBackfill imports the LibreView history of the last days and returns the
+// exit code.
+func runBackfill(args []string) int {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	days := flags.Int("days", 90, fmt.Sprintf("Days of history to import (at most %d)", backfill.MaxDays))
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *days < 1 || *days > backfill.MaxDays {
+		fmt.Fprintf(os.Stderr, "Error: --days must be between 1 and %d\n", backfill.MaxDays)
+		return 2
+	}
+
+	if err := backfillHistory(*days); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// backfillHistory imports the history of the configured patients into the
+// configured database. glcore may keep running: stored readings are skipped.
+func backfillHistory(days int) error {
+	if _, err := loadEnvFile(); err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	logConfigWarnings(cfg)
+
+	database, err := openDatabase(cfg.Database.ToPersistenceConfig())
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	glucoseRepo := repository.NewGlucoseRepository(database.DB())
+	glucoseService := service.NewGlucoseService(glucoseRepo, cfg.Statistics.TargetBands, slog.Default(), nil)
+
+	// Roll up the imported history as the retention job would
+	var retentionService service.RetentionService
+	if cfg.Retention.Enabled() {
+		retentionService = service.NewRetentionService(
+			repository.NewGlucoseRollupRepository(database.DB()),
+			glucoseRepo,
+			repository.NewUnitOfWork(database.DB()),
+			time.Duration(cfg.Retention.DownsampleDays)*24*time.Hour,
+			time.Duration(cfg.Retention.RawDays)*24*time.Hour,
+			slog.Default(),
+		)
+	}
+
+	backfiller := backfill.New(nil, cfg.Credentials.Email, cfg.Credentials.Password,
+		cfg.Credentials.PatientID, cfg.Credentials.MultiPatient, glucoseService, retentionService, slog.Default())
+
+	// About 2 seconds per day and patient
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	defer cancel()
+
+	result, err := backfiller.Run(ctx, days, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d measurements (%d already stored) from %d days of %d patient(s).\n",
+		result.Inserted, result.Skipped, result.Periods, result.Patients)
+	if result.Oldest != nil {
+		fmt.Printf("Oldest reading: %s\n", result.Oldest.Local().Format("2006-01-02 15:04"))
+	}
+	if result.Rollups > 0 {
+		fmt.Printf("Updated %d retention rollups.\n", result.Rollups)
+	}
+	return nil
+}
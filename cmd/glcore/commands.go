@@ -0,0 +1,411 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/config"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/internal/selfupdate"
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+// version is set at build time
+var version = "dev"
+
+// runCommand runs a glcore subcommand instead of the daemon and returns the exit code.
+func runCommand(args []string) int {
+	switch args[0] {
+	case "version":
+		fmt.Printf("glcore %s\n", version)
+		return 0
+
+	case "self-update":
+		flags := flag.NewFlagSet("self-update", flag.ContinueOnError)
+		force := flags.Bool("force", false, "Install the latest release even if it is not newer")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 2
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		updated, err := selfupdate.New("glcore").Run(ctx, version, *force, os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if updated {
+			fmt.Println("Restart glcore to run the new version.")
+		}
+		return 0
+
+	case "export":
+		flags := flag.NewFlagSet("export", flag.ContinueOnError)
+		output := flags.String("o", "", "Write the export to this file instead of stdout")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 2
+		}
+
+		if err := runExport(*output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+
+	case "erase":
+		flags := flag.NewFlagSet("erase", flag.ContinueOnError)
+		yes := flags.Bool("yes", false, "Do not ask for confirmation")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 2
+		}
+
+		if err := runErase(*yes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+
+	case "backup":
+		flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+		output := flags.String("out", "", "Write the backup to this file (default glcmd-backup-<date>.tar.gz)")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 2
+		}
+
+		if err := runBackup(*output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+
+	case "restore":
+		flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+		yes := flags.Bool("yes", false, "Do not ask for confirmation")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if flags.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "Usage: glcore restore [--yes] <backup.tar.gz>")
+			return 2
+		}
+
+		if err := runRestore(flags.Arg(0), *yes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+
+	case "migrate":
+		return runMigrate(args[1:])
+
+	case "init":
+		return runInit(args[1:])
+
+	case "gen-fixtures":
+		return runGenFixtures(args[1:])
+
+	case "backfill":
+		return runBackfill(args[1:])
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\nUsage:\n  glcore                  Run the daemon\n  glcore version          Show version information\n  glcore init             Create the configuration file interactively [--help for flags]\n  glcore self-update      Update glcore to the latest release [--force]\n  glcore export           Export all stored personal data as JSON [-o file]\n  glcore erase            Delete all stored personal data [--yes]\n  glcore backup           Back up the SQLite database [--out file.tar.gz]\n  glcore restore          Restore the SQLite database from a backup [--yes] <file>\n  glcore migrate          Show, apply or roll back database migrations [status|up|down --to N]\n  glcore gen-fixtures     Write test fixtures from live data [--days N] [--anonymize] [--out dir]\n  glcore backfill         Import LibreView history older than 12 hours [--days N]\n", args[0])
+		return 2
+	}
+}
+
+// openPrivacyService opens the configured database for the export and erase commands.
+// The returned function closes the database.
+func openPrivacyService() (*service.PrivacyServiceImpl, *service.AttachmentServiceImpl, func(), error) {
+	if _, err := loadEnvFile(); err != nil {
+		return nil, nil, nil, err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	logConfigWarnings(cfg)
+
+	database, err := openDatabase(cfg.Database.ToPersistenceConfig())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	privacyService := service.NewPrivacyService(
+		repository.NewPrivacyRepository(database.DB()),
+		repository.NewUnitOfWork(database.DB()),
+		slog.Default(),
+	)
+	attachmentService := service.NewAttachmentService(
+		repository.NewAttachmentRepository(database.DB()),
+		repository.NewSensorRepository(database.DB()),
+		cfg.API.AttachmentsDir,
+		slog.Default(),
+	)
+	return privacyService, attachmentService, func() { database.Close() }, nil
+}
+
+// runExport writes all stored personal data as JSON to output, or stdout if empty.
+func runExport(output string) error {
+	privacyService, _, closeDB, err := openPrivacyService()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	export, err := privacyService.Export(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	// The export holds health data: keep it private to the user
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d measurements, %d sensors, %d treatments and %d alerts to %s\n",
+		len(export.Measurements), len(export.Sensors), len(export.Treatments), len(export.Alerts), output)
+	return nil
+}
+
+// runErase deletes all stored personal data after an interactive confirmation.
+func runErase(yes bool) error {
+	if !yes {
+		fmt.Print("This permanently deletes all glucose readings, sensors, attachments, treatments,\nalerts and account data stored by glcore. Type \"erase\" to confirm: ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(answer) != "erase" {
+			return errors.New("erasure cancelled")
+		}
+	}
+
+	privacyService, attachmentService, closeDB, err := openPrivacyService()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	confirmation, err := privacyService.RequestErasure()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	result, err := privacyService.Erase(ctx, confirmation.Token)
+	if err != nil {
+		return err
+	}
+	removed, err := attachmentService.PruneFiles(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to remove attachment files: %w", err)
+	}
+
+	fmt.Printf("Erased %d measurements, %d sensors, %d treatments and %d alerts.\n",
+		result.Deleted["measurements"], result.Deleted["sensors"], result.Deleted["treatments"], result.Deleted["alerts"])
+	if removed > 0 {
+		fmt.Printf("Removed %d attachment files.\n", removed)
+	}
+	fmt.Println("Stop glcore or remove its LibreView credentials, otherwise new readings will be stored again.")
+	return nil
+}
+
+// loadDatabaseConfig loads the database configuration for the backup,
+// restore and migrate commands. sqliteOnly rejects PostgreSQL.
+func loadDatabaseConfig(sqliteOnly bool) (*persistence.DatabaseConfig, error) {
+	if _, err := loadEnvFile(); err != nil {
+		return nil, err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	logConfigWarnings(cfg)
+
+	dbConfig := cfg.Database.ToPersistenceConfig()
+	if sqliteOnly && dbConfig.Type != "sqlite" {
+		return nil, fmt.Errorf("%w (use pg_dump for PostgreSQL)", persistence.ErrBackupUnsupported)
+	}
+	return dbConfig, nil
+}
+
+// runBackup writes a backup of the database to output. The database is not
+// migrated, so the backup holds its schema as is, and glcore may keep running.
+func runBackup(output string) error {
+	dbConfig, err := loadDatabaseConfig(true)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = "glcmd-backup-" + time.Now().Format("20060102-150405") + ".tar.gz"
+	}
+
+	database, err := persistence.NewDatabase(dbConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+
+	// The backup holds health data: keep it private to the user
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	manifest, err := database.Backup(ctx, f, version)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Backed up %s (%d bytes, schema version %d) to %s\n",
+		dbConfig.SQLitePath, manifest.DatabaseSize, manifest.SchemaVersion, output)
+	return nil
+}
+
+// runRestore replaces the database with the backup at input after an
+// interactive confirmation.
+func runRestore(input string, yes bool) error {
+	dbConfig, err := loadDatabaseConfig(true)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if !yes {
+		fmt.Printf("This replaces the database %s with the backup %s.\nStop glcore first. Type \"restore\" to confirm: ", dbConfig.SQLitePath, input)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(answer) != "restore" {
+			return errors.New("restore cancelled")
+		}
+	}
+
+	if err := persistence.PrepareSQLiteDir(dbConfig.SQLitePath, dbConfig.SecureFiles); err != nil {
+		return err
+	}
+	manifest, err := persistence.RestoreBackup(f, dbConfig.SQLitePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored the backup of %s (glcore %s, schema version %d) to %s.\n",
+		manifest.CreatedAt.Local().Format("2006-01-02 15:04"), manifest.GlcoreVersion, manifest.SchemaVersion, dbConfig.SQLitePath)
+	if _, err := os.Stat(dbConfig.SQLitePath + ".pre-restore"); err == nil {
+		fmt.Printf("The previous database was kept as %s.pre-restore.\n", dbConfig.SQLitePath)
+	}
+	return nil
+}
+
+// runMigrate shows the database migrations, applies the pending ones or rolls
+// back those after a version, and returns the exit code.
+func runMigrate(args []string) int {
+	action := "status"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	to := flags.Int("to", -1, "Roll back the migrations after this version (down only)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if (action == "down") != (*to >= 0) || !slices.Contains([]string{"status", "up", "down"}, action) {
+		fmt.Fprintln(os.Stderr, "Usage: glcore migrate [status | up | down --to <version>]")
+		return 2
+	}
+
+	if err := migrate(action, *to); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// migrate runs a migrate action on the configured database.
+func migrate(action string, to int) error {
+	dbConfig, err := loadDatabaseConfig(false)
+	if err != nil {
+		return err
+	}
+
+	// up migrates as the daemon does at startup
+	var database *persistence.Database
+	if action == "up" {
+		database, err = openDatabase(dbConfig)
+	} else if database, err = persistence.NewDatabase(dbConfig); err != nil {
+		err = fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	migrator := database.SchemaMigrator()
+
+	if action == "down" {
+		rolledBack, err := migrator.Down(ctx, to)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %d migrations. Run a glcore release matching schema version %d, or glcore applies them again at startup.\n", len(rolledBack), to)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED\tROLLBACK")
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = status.AppliedAt.Local().Format("2006-01-02 15:04")
+		}
+		rollback := "yes"
+		switch {
+		case status.Unknown:
+			rollback = "newer release"
+		case !status.Reversible:
+			rollback = "no"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, applied, rollback)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/config"
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/fixtures"
+	"github.com/R4yL-dev/glcmd/internal/libreclient"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// fixturesOptions are the flags of glcore gen-fixtures.
+type fixturesOptions struct {
+	days      int
+	anonymize bool
+	out       string
+	api       bool
+}
+
+// runGenFixtures writes test fixtures from the live LibreView payloads and the
+// recent database content, and returns the exit code.
+func runGenFixtures(args []string) int {
+	var opts fixturesOptions
+	flags := flag.NewFlagSet("gen-fixtures", flag.ContinueOnError)
+	flags.IntVar(&opts.days, "days", 2, "Days of measurements and sensors to snapshot from the database")
+	flags.BoolVar(&opts.anonymize, "anonymize", false, "Replace patient IDs, names, e-mails, serial and device numbers with pseudonyms")
+	flags.StringVar(&opts.out, "out", filepath.Join("testdata", "fixtures"), "Directory the fixtures are written to")
+	flags.BoolVar(&opts.api, "api", true, "Capture the LibreView payloads (logs in with the configured account)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if opts.days < 1 {
+		fmt.Fprintln(os.Stderr, "Error: --days must be at least 1")
+		return 2
+	}
+
+	if err := genFixtures(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// genFixtures writes the LibreView payloads under out/libreview and the
+// database snapshot to out/db/snapshot.json.
+func genFixtures(opts fixturesOptions) error {
+	if _, err := loadEnvFile(); err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	logConfigWarnings(cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var anonymizer *fixtures.Anonymizer
+	if opts.anonymize {
+		anonymizer = fixtures.NewAnonymizer()
+	} else {
+		fmt.Fprintln(os.Stderr, "Warning: fixtures hold personal health data; use --anonymize before committing them")
+	}
+
+	if opts.api {
+		payloads, err := captureLibreView(ctx, cfg.Credentials.Email, cfg.Credentials.Password)
+		if err != nil {
+			return fmt.Errorf("failed to capture LibreView payloads: %w", err)
+		}
+		for _, name := range payloads.names {
+			raw := payloads.data[name]
+			if anonymizer != nil {
+				if raw, err = anonymizer.Payload(raw); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+			}
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, raw, "", "  "); err != nil {
+				return fmt.Errorf("%s: invalid payload: %w", name, err)
+			}
+			indented.WriteByte('\n')
+			if err := writeFixture(filepath.Join(opts.out, "libreview", name), indented.Bytes(), opts.anonymize); err != nil {
+				return err
+			}
+		}
+	}
+
+	database, err := openDatabase(cfg.Database.ToPersistenceConfig())
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	now := time.Now().UTC()
+	start := now.AddDate(0, 0, -opts.days)
+	snapshot := &fixtures.Snapshot{
+		GeneratedAt: now,
+		Days:        opts.days,
+		Anonymized:  opts.anonymize,
+	}
+
+	snapshot.Measurements, err = repository.NewGlucoseRepository(database.DB()).FindByTimeRange(ctx, start, now)
+	if err != nil {
+		return fmt.Errorf("failed to read measurements: %w", err)
+	}
+	sensors, err := repository.NewSensorRepository(database.DB()).FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read sensors: %w", err)
+	}
+	// Keep the sensors worn during the period
+	snapshot.Sensors = []*domain.SensorConfig{}
+	for _, s := range sensors {
+		if s.EndedAt == nil || !s.EndedAt.Before(start) {
+			snapshot.Sensors = append(snapshot.Sensors, s)
+		}
+	}
+
+	if anonymizer != nil {
+		anonymizer.Measurements(snapshot.Measurements)
+		anonymizer.Sensors(snapshot.Sensors)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if err := writeFixture(filepath.Join(opts.out, "db", "snapshot.json"), data, opts.anonymize); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d measurements and %d sensors of the last %d days to %s\n",
+		len(snapshot.Measurements), len(snapshot.Sensors), opts.days, opts.out)
+	return nil
+}
+
+// libreViewPayloads are the raw LibreView responses, by fixture file name.
+type libreViewPayloads struct {
+	names []string // In capture order
+	data  map[string][]byte
+}
+
+// add records a payload.
+func (p *libreViewPayloads) add(name string, data []byte) {
+	p.names = append(p.names, name)
+	p.data[name] = data
+}
+
+// captureLibreView logs in and fetches the connections and the graph of each
+// followed patient. Graphs are numbered in the order of the connections, so
+// their file names do not hold patient IDs.
+func captureLibreView(ctx context.Context, email, password string) (*libreViewPayloads, error) {
+	client := libreclient.NewClient(nil)
+	session, err := client.Login(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	payloads := &libreViewPayloads{data: make(map[string][]byte)}
+	raw, err := client.GetConnectionsRaw(ctx, session.Token, session.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	payloads.add("connections.json", raw)
+
+	var connections libreclient.ConnectionsResponse
+	if err := json.Unmarshal(raw, &connections); err != nil {
+		return nil, fmt.Errorf("invalid connections payload: %w", err)
+	}
+	if len(connections.Data) == 0 {
+		return nil, errors.New("no patient connections found")
+	}
+
+	for i, connection := range connections.Data {
+		raw, err := client.GetGraphRaw(ctx, session.Token, session.AccountID, connection.PatientID)
+		if err != nil {
+			return nil, err
+		}
+		payloads.add(fmt.Sprintf("graph-%d.json", i+1), raw)
+	}
+	return payloads, nil
+}
+
+// writeFixture writes a fixture file, creating its directory. Fixtures that
+// were not anonymized are kept private to the user.
+func writeFixture(path string, data []byte, anonymized bool) error {
+	perm := os.FileMode(0600)
+	if anonymized {
+		perm = 0644
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, perm)
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/config"
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/libreclient"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// systemdUnitPath is where glcore init installs the service unit.
+const systemdUnitPath = "/etc/systemd/system/glcore.service"
+
+// initOptions are the settings collected by glcore init.
+type initOptions struct {
+	email      string
+	password   string
+	dbType     string
+	dbPath     string
+	dbHost     string
+	dbPort     int
+	dbName     string
+	dbUser     string
+	dbPassword string
+	port       int
+	unit       string
+	output     string
+	force      bool
+	systemd    bool
+	noInput    bool
+	skipCheck  bool
+}
+
+// runInit parses the init flags and runs the setup. Returns the exit code.
+func runInit(args []string) int {
+	var opts initOptions
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	flags.StringVar(&opts.email, "email", "", "LibreView email")
+	flags.StringVar(&opts.password, "password", "", "LibreView password")
+	flags.StringVar(&opts.dbType, "db", "", "Database: sqlite or postgres (default sqlite)")
+	flags.StringVar(&opts.dbPath, "db-path", "", "SQLite database file (default ./data/glcmd.db)")
+	flags.StringVar(&opts.dbHost, "db-host", "", "PostgreSQL host (default localhost)")
+	flags.IntVar(&opts.dbPort, "db-port", 0, "PostgreSQL port (default 5432)")
+	flags.StringVar(&opts.dbName, "db-name", "", "PostgreSQL database (default glcmd)")
+	flags.StringVar(&opts.dbUser, "db-user", "", "PostgreSQL user (default glcmd)")
+	flags.StringVar(&opts.dbPassword, "db-password", "", "PostgreSQL password")
+	flags.IntVar(&opts.port, "port", 0, "API port (default 8080)")
+	flags.StringVar(&opts.unit, "unit", "", "Display unit: mmol or mgdl (default mmol)")
+	flags.StringVar(&opts.output, "o", config.DefaultEnvFile, "Configuration file to write")
+	flags.BoolVar(&opts.force, "force", false, "Overwrite an existing configuration file")
+	flags.BoolVar(&opts.systemd, "systemd", false, "Install a systemd unit running glcore with this configuration")
+	flags.BoolVar(&opts.noInput, "no-input", false, "Do not prompt, fail if a required setting is missing")
+	flags.BoolVar(&opts.skipCheck, "skip-check", false, "Do not check the credentials against LibreView")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	// Keep the output to the setup steps
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	// Only prompt on a terminal, so the command can be scripted
+	var p *prompter
+	if !opts.noInput && isTerminal(os.Stdin) {
+		p = &prompter{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	}
+
+	if err := runSetup(&opts, p); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runSetup collects the missing settings, validates them and writes the
+// configuration. p is nil when not running interactively.
+func runSetup(opts *initOptions, p *prompter) error {
+	if _, err := os.Stat(opts.output); err == nil && !opts.force {
+		return fmt.Errorf("%s already exists (use --force to overwrite it)", opts.output)
+	}
+
+	if p != nil {
+		fmt.Println("glcore setup: press Enter to keep the value in brackets.")
+		fmt.Println()
+		opts.email = p.ask("LibreView email", opts.email)
+		if opts.password == "" {
+			opts.password = p.askSecret("LibreView password")
+		}
+		opts.dbType = p.ask("Database (sqlite, postgres)", orDefault(opts.dbType, "sqlite"))
+		if opts.dbType == "postgres" {
+			opts.dbHost = p.ask("PostgreSQL host", orDefault(opts.dbHost, "localhost"))
+			opts.dbPort = p.askInt("PostgreSQL port", orDefaultInt(opts.dbPort, 5432))
+			opts.dbName = p.ask("PostgreSQL database", orDefault(opts.dbName, "glcmd"))
+			opts.dbUser = p.ask("PostgreSQL user", orDefault(opts.dbUser, "glcmd"))
+			if opts.dbPassword == "" {
+				opts.dbPassword = p.askSecret("PostgreSQL password")
+			}
+		} else {
+			opts.dbPath = p.ask("SQLite database file", orDefault(opts.dbPath, "./data/glcmd.db"))
+		}
+		opts.port = p.askInt("API port", orDefaultInt(opts.port, 8080))
+		opts.unit = p.ask("Display unit (mmol, mgdl)", orDefault(opts.unit, "mmol"))
+		if !opts.systemd && systemdAvailable() {
+			opts.systemd = p.confirm("Install a systemd unit", false)
+		}
+		fmt.Println()
+	}
+
+	opts.unit = orDefault(opts.unit, "mmol")
+	if opts.unit != "mmol" && opts.unit != "mgdl" {
+		return fmt.Errorf("invalid unit %q (must be mmol or mgdl)", opts.unit)
+	}
+	values, err := opts.envValues()
+	if err != nil {
+		return err
+	}
+
+	// Validate with the loader glcore uses at startup
+	for key, value := range values {
+		os.Setenv(key, value)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	if !opts.skipCheck {
+		fmt.Print("Checking LibreView credentials... ")
+		if err := checkCredentials(cfg.Credentials.Email, cfg.Credentials.Password); err != nil {
+			fmt.Println("failed")
+			return fmt.Errorf("LibreView rejected the login: %w", err)
+		}
+		fmt.Println("ok")
+	}
+
+	fmt.Print("Preparing the database... ")
+	if err := prepareDatabase(cfg.Database.ToPersistenceConfig(), opts.unit); err != nil {
+		fmt.Println("failed")
+		return err
+	}
+	fmt.Println("ok")
+
+	header := fmt.Sprintf("glcore configuration, written by glcore init on %s.\nSettings in the environment take precedence.", time.Now().Format("2006-01-02"))
+	if err := config.WriteEnvFile(opts.output, header, values); err != nil {
+		return fmt.Errorf("failed to write %s: %w", opts.output, err)
+	}
+	fmt.Printf("Configuration written to %s\n", opts.output)
+
+	if opts.systemd {
+		if err := installSystemdUnit(opts.output); err != nil {
+			return fmt.Errorf("failed to install the systemd unit: %w", err)
+		}
+		fmt.Printf("Systemd unit written to %s. Start glcore with:\n  sudo systemctl daemon-reload && sudo systemctl enable --now glcore\n", systemdUnitPath)
+		return nil
+	}
+
+	fmt.Printf("Start glcore from %s with:\n  glcore\n", filepath.Dir(absPath(opts.output)))
+	return nil
+}
+
+// envValues returns the environment variables for the options. Settings left
+// to their default are not written, so later default changes apply.
+func (o *initOptions) envValues() (map[string]string, error) {
+	if o.email == "" || o.password == "" {
+		return nil, errors.New("the LibreView email and password are required (--email, --password)")
+	}
+
+	values := map[string]string{
+		"GLCMD_EMAIL":    o.email,
+		"GLCMD_PASSWORD": o.password,
+	}
+	if o.port != 0 && o.port != 8080 {
+		values["GLCMD_API_PORT"] = strconv.Itoa(o.port)
+	}
+
+	switch orDefault(o.dbType, "sqlite") {
+	case "sqlite":
+		if o.dbPath != "" && o.dbPath != "./data/glcmd.db" {
+			values["GLCMD_DB_PATH"] = o.dbPath
+		}
+	case "postgres":
+		if o.dbPassword == "" {
+			return nil, errors.New("the PostgreSQL password is required (--db-password)")
+		}
+		values["GLCMD_DB_TYPE"] = "postgres"
+		values["GLCMD_DB_HOST"] = orDefault(o.dbHost, "localhost")
+		values["GLCMD_DB_PORT"] = strconv.Itoa(orDefaultInt(o.dbPort, 5432))
+		values["GLCMD_DB_NAME"] = orDefault(o.dbName, "glcmd")
+		values["GLCMD_DB_USER"] = orDefault(o.dbUser, "glcmd")
+		values["GLCMD_DB_PASSWORD"] = o.dbPassword
+	default:
+		return nil, fmt.Errorf("invalid database %q (must be sqlite or postgres)", o.dbType)
+	}
+
+	return values, nil
+}
+
+// checkCredentials logs in to LibreView once.
+func checkCredentials(email, password string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, _, _, err := libreclient.NewClient(nil).Authenticate(ctx, email, password)
+	return err
+}
+
+// prepareDatabase connects to the database, creates the tables and stores
+// the display unit in the dashboard configuration.
+func prepareDatabase(dbConfig *persistence.DatabaseConfig, unit string) error {
+	dbConfig.LogLevel = "silent"
+	database, err := openDatabase(dbConfig)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dashboardRepo := repository.NewDashboardRepository(database.DB())
+	dashboard, err := dashboardRepo.Find(ctx)
+	if errors.Is(err, persistence.ErrNotFound) {
+		dashboard, err = domain.DefaultDashboardConfig(), nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the dashboard configuration: %w", err)
+	}
+	dashboard.Unit = unit
+	if err := dashboardRepo.Save(ctx, dashboard); err != nil {
+		return fmt.Errorf("failed to save the display unit: %w", err)
+	}
+	return nil
+}
+
+// systemdAvailable returns true if the system runs systemd.
+func systemdAvailable() bool {
+	_, err := os.Stat("/run/systemd/system")
+	return err == nil
+}
+
+// installSystemdUnit writes a unit running this glcore binary with envFile,
+// from the directory of envFile so relative database paths still apply.
+func installSystemdUnit(envFile string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	envFile = absPath(envFile)
+
+	var sb strings.Builder
+	sb.WriteString("[Unit]\nDescription=glcmd glucose monitoring daemon\nAfter=network-online.target\nWants=network-online.target\n\n[Service]\nType=simple\n")
+	if u, err := user.Current(); err == nil && u.Uid != "0" {
+		fmt.Fprintf(&sb, "User=%s\n", u.Username)
+	}
+	fmt.Fprintf(&sb, "WorkingDirectory=%s\n", filepath.Dir(envFile))
+	fmt.Fprintf(&sb, "EnvironmentFile=%s\n", envFile)
+	fmt.Fprintf(&sb, "ExecStart=%s\n", executable)
+	sb.WriteString("Restart=always\nRestartSec=10\n\n[Install]\nWantedBy=multi-user.target\n")
+
+	return os.WriteFile(systemdUnitPath, []byte(sb.String()), 0644)
+}
+
+// absPath returns path made absolute, or path itself if that fails.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func orDefaultInt(value, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+// prompter asks for settings on a terminal.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prompts for a value, returning fallback if the answer is empty.
+func (p *prompter) ask(label, fallback string) string {
+	if fallback != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, fallback)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", label)
+	}
+	answer, _ := p.in.ReadString('\n')
+	return orDefault(strings.TrimSpace(answer), fallback)
+}
+
+// askInt prompts for a number until a valid one is given.
+func (p *prompter) askInt(label string, fallback int) int {
+	for {
+		answer := p.ask(label, strconv.Itoa(fallback))
+		if n, err := strconv.Atoi(answer); err == nil {
+			return n
+		}
+		fmt.Fprintln(p.out, "Please enter a number.")
+	}
+}
+
+// askSecret prompts for a value without echoing it.
+func (p *prompter) askSecret(label string) string {
+	fmt.Fprintf(p.out, "%s: ", label)
+
+	// Best effort: stty is missing on some systems, the input is then echoed
+	if err := stty("-echo"); err == nil {
+		defer func() {
+			stty("echo")
+			fmt.Fprintln(p.out)
+		}()
+	}
+	answer, _ := p.in.ReadString('\n')
+	return strings.TrimRight(answer, "\r\n")
+}
+
+// confirm asks a yes/no question.
+func (p *prompter) confirm(label string, fallback bool) bool {
+	choices := "y/N"
+	if fallback {
+		choices = "Y/n"
+	}
+	fmt.Fprintf(p.out, "%s? [%s]: ", label, choices)
+	answer, _ := p.in.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	default:
+		return fallback
+	}
+}
+
+// stty changes the terminal settings of stdin.
+func stty(setting string) error {
+	cmd := exec.Command("stty", setting)
+	cmd.Stdin = os.Stdin
+	if err := cmd.Run(); err != nil {
+		slog.Debug("stty failed", "setting", setting, "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/backfill"
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/jobs"
+	"github.com/R4yL-dev/glcmd/internal/replication"
+	"github.com/R4yL-dev/glcmd/internal/service"
+	"github.com/R4yL-dev/glcmd/internal/summary"
+)
+
+// Job types run by glcore itself (the API registers the import jobs)
+const (
+	jobTypeAttachmentPrune = "attachmentPrune"
+	jobTypeGlucoseEvents   = "glucoseEventDetection"
+	jobTypeDailySummaries  = "dailySummaries"
+	jobTypeRetention       = "retention"
+	jobTypeReplication     = "replication"
+	jobTypeMorningSummary  = "morningSummary"
+)
+
+// morningSummaryAttempts is how many times the morning summary is tried, a
+// minute apart, before it is given up for the day.
+const morningSummaryAttempts = 3
+
+// registerJobs registers and schedules the maintenance, analytics,
+// replication and report jobs, and registers the backfill job started by the
+// API. retentionService, replicator and scheduler are nil when disabled. It returns
+// the job types to run once at startup.
+func registerJobs(
+	manager *jobs.Manager,
+	attachmentService service.AttachmentService,
+	glucoseEventService service.GlucoseEventService,
+	dailySummaryService service.DailySummaryService,
+	retentionService service.RetentionService,
+	replicator *replication.Replicator,
+	replicationInterval time.Duration,
+	scheduler *summary.Scheduler,
+	backfiller *backfill.Backfiller,
+) ([]string, error) {
+	// Remove the files of erased attachments, including those erased while
+	// glcore was stopped
+	manager.Register(jobTypeAttachmentPrune, func(ctx context.Context, _ *domain.Job, _ *jobs.Progress) (any, error) {
+		removed, err := attachmentService.PruneFiles(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]int{"removed": removed}, nil
+	}, jobs.Options{})
+	if err := manager.Schedule("@daily", jobTypeAttachmentPrune); err != nil {
+		return nil, err
+	}
+	startup := []string{jobTypeAttachmentPrune}
+
+	// Detect the low and high events of the new measurements. The first run
+	// scans the whole history.
+	manager.Register(jobTypeGlucoseEvents, func(ctx context.Context, _ *domain.Job, _ *jobs.Progress) (any, error) {
+		return glucoseEventService.DetectEvents(ctx)
+	}, jobs.Options{})
+	if err := manager.Schedule("*/15 * * * *", jobTypeGlucoseEvents); err != nil {
+		return nil, err
+	}
+	startup = append(startup, jobTypeGlucoseEvents)
+
+	// Summarize the current and previous days hourly, before the retention
+	// job prunes their measurements. The first run summarizes the whole
+	// history.
+	manager.Register(jobTypeDailySummaries, func(ctx context.Context, _ *domain.Job, _ *jobs.Progress) (any, error) {
+		return dailySummaryService.Summarize(ctx)
+	}, jobs.Options{})
+	if err := manager.Schedule("5 * * * *", jobTypeDailySummaries); err != nil {
+		return nil, err
+	}
+	startup = append(startup, jobTypeDailySummaries)
+
+	// Downsample and prune the old measurements nightly, when glcore is the
+	// least busy
+	if retentionService != nil {
+		manager.Register(jobTypeRetention, func(ctx context.Context, _ *domain.Job, _ *jobs.Progress) (any, error) {
+			return retentionService.Apply(ctx)
+		}, jobs.Options{})
+		if err := manager.Schedule("30 3 * * *", jobTypeRetention); err != nil {
+			return nil, err
+		}
+	}
+
+	// Pull the changed days from the primary instance (secondary mode only).
+	// A failed pass is not retried: the next one covers the same days.
+	if replicator != nil {
+		manager.Register(jobTypeReplication, func(ctx context.Context, _ *domain.Job, _ *jobs.Progress) (any, error) {
+			return replicator.SyncOnce(ctx)
+		}, jobs.Options{})
+		if err := manager.Schedule("@every "+replicationInterval.String(), jobTypeReplication); err != nil {
+			return nil, err
+		}
+		startup = append(startup, jobTypeReplication)
+	}
+
+	// Publish the morning summary of the night ending at the scheduled time
+	// (when the job was enqueued, as retries delay RunAt)
+	if scheduler != nil {
+		manager.Register(jobTypeMorningSummary, func(ctx context.Context, job *domain.Job, _ *jobs.Progress) (any, error) {
+			return nil, scheduler.Publish(ctx, job.CreatedAt.Local().Truncate(time.Minute))
+		}, jobs.Options{MaxAttempts: morningSummaryAttempts})
+		if err := manager.Schedule(scheduler.Spec(), jobTypeMorningSummary); err != nil {
+			return nil, err
+		}
+	}
+
+	// Import the LibreView history older than /graph, on request
+	// (POST /v1/admin/backfill). Not retried: LibreView rate-limits logins.
+	manager.Register(backfill.JobType, func(ctx context.Context, job *domain.Job, p *jobs.Progress) (any, error) {
+		var payload backfill.Payload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("invalid backfill payload: %w", err)
+		}
+		return backfiller.Run(ctx, payload.Days, p)
+	}, jobs.Options{})
+
+	return startup, nil
+}
@@ -2,21 +2,36 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"runtime/debug"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/R4yL-dev/glcmd/internal/alerts"
 	"github.com/R4yL-dev/glcmd/internal/api"
+	"github.com/R4yL-dev/glcmd/internal/backfill"
 	"github.com/R4yL-dev/glcmd/internal/config"
 	"github.com/R4yL-dev/glcmd/internal/daemon"
 	"github.com/R4yL-dev/glcmd/internal/domain"
 	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/faultinject"
+	"github.com/R4yL-dev/glcmd/internal/heartbeat"
+	"github.com/R4yL-dev/glcmd/internal/jobs"
+	"github.com/R4yL-dev/glcmd/internal/libreclient"
+	"github.com/R4yL-dev/glcmd/internal/logger"
+	"github.com/R4yL-dev/glcmd/internal/nightscout"
 	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/plugin"
+	"github.com/R4yL-dev/glcmd/internal/replication"
 	"github.com/R4yL-dev/glcmd/internal/repository"
 	"github.com/R4yL-dev/glcmd/internal/service"
+	"github.com/R4yL-dev/glcmd/internal/summary"
 )
 
 // getLogLevel returns the slog level from GLCMD_LOG_LEVEL env var.
@@ -38,7 +53,9 @@ func getLogLevel() slog.Level {
 // setupLogger configures slog based on environment variables.
 // GLCMD_LOG_FORMAT: "text" (default) or "json"
 // GLCMD_LOG_LEVEL: "debug", "info" (default), "warn", "error"
-func setupLogger() {
+// GLCMD_LOG_SAMPLING: "first/period" (default "3/10m") or "off"
+// Recent records are also kept in the returned ring for GET /v1/admin/logs.
+func setupLogger() *logger.Ring {
 	opts := &slog.HandlerOptions{
 		Level: getLogLevel(),
 	}
@@ -50,28 +67,214 @@ func setupLogger() {
 		handler = slog.NewTextHandler(os.Stderr, opts)
 	}
 
-	slog.SetDefault(slog.New(handler))
+	// Sample before the ring, so an outage does not push older records out of it either
+	sampling, samplingErr := logger.ParseSampling(os.Getenv("GLCMD_LOG_SAMPLING"))
+	if samplingErr != nil {
+		sampling, _ = logger.ParseSampling("")
+	}
+
+	ring := logger.NewRing(logger.DefaultRingSize)
+	slog.SetDefault(slog.New(logger.NewSamplingHandler(logger.NewRingHandler(handler, ring), sampling)))
+	if samplingErr != nil {
+		slog.Warn("invalid GLCMD_LOG_SAMPLING, using the default", "error", samplingErr)
+	}
+	return ring
+}
+
+// loadEnvFile loads the configuration file named by GLCMD_ENV_FILE, or
+// glcore.env in the working directory if it exists. Variables already set in
+// the environment take precedence. Returns the path loaded, empty if none.
+func loadEnvFile() (string, error) {
+	path := os.Getenv("GLCMD_ENV_FILE")
+	explicit := path != ""
+	if !explicit {
+		path = config.DefaultEnvFile
+	}
+
+	if err := config.LoadEnvFile(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) && !explicit {
+			return "", nil
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// logConfigWarnings logs the configuration problems found by config.Load,
+// which are only fatal with GLCMD_STRICT_CONFIG.
+func logConfigWarnings(cfg *config.Config) {
+	for _, warning := range cfg.Warnings {
+		slog.Warn("configuration problem", "problem", warning)
+	}
+	if len(cfg.Warnings) > 0 {
+		slog.Info("set GLCMD_STRICT_CONFIG=1 to refuse to start with configuration problems")
+	}
+}
+
+// isTerminal returns true if f is an interactive terminal rather than a
+// file, pipe or journal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// /dev/null is a character device too
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, null)
+}
+
+// printBanner shows where glcore is serving once started, when run from a
+// terminal. Services logging to a journal only get the log records.
+func printBanner(cfg *config.Config) {
+	if !isTerminal(os.Stderr) {
+		return
+	}
+
+	database := cfg.Database.SQLitePath
+	if cfg.Database.Type == "postgres" {
+		database = fmt.Sprintf("postgres://%s:%d/%s", cfg.Database.Host, cfg.Database.Port, cfg.Database.Database)
+	}
+	fmt.Fprintf(os.Stderr, "\n  glcore %s\n\n", version)
+	fmt.Fprintf(os.Stderr, "  API        http://localhost:%d/v1\n", cfg.API.Port)
+	fmt.Fprintf(os.Stderr, "  Status     http://localhost:%d/status\n", cfg.API.Port)
+	fmt.Fprintf(os.Stderr, "  Database   %s\n", database)
+	fmt.Fprintf(os.Stderr, "  Account    %s\n\n", cfg.Credentials.Email)
+}
+
+// patientContext returns a context scoping glucose and sensor data to the
+// configured patient in multi-patient mode, where several patients are stored.
+func patientContext(cfg config.CredentialsConfig) context.Context {
+	if !cfg.MultiPatient {
+		return context.Background()
+	}
+	return repository.WithPatient(context.Background(), cfg.PatientID)
+}
+
+// alertNotifiers returns the notification channels configured in cfg.
+// Webhooks are signed by signer.
+func alertNotifiers(cfg config.AlertsConfig, signer alerts.Signer) []alerts.Notifier {
+	var notifiers []alerts.Notifier
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, alerts.NewWebhookNotifier(cfg.WebhookURL, signer))
+	}
+	if cfg.SMTP.Host != "" {
+		notifiers = append(notifiers, alerts.NewEmailNotifier(cfg.SMTP))
+	}
+	if cfg.TelegramToken != "" {
+		notifiers = append(notifiers, alerts.NewTelegramNotifier(cfg.TelegramToken, cfg.TelegramChat))
+	}
+	if cfg.PushoverToken != "" {
+		notifiers = append(notifiers, alerts.NewPushoverNotifier(cfg.PushoverToken, cfg.PushoverUser))
+	}
+	if cfg.Sound != "" {
+		notifiers = append(notifiers, alerts.NewSoundNotifier(cfg.Sound))
+	}
+	return notifiers
+}
+
+// escalationNotifiers returns the escalation channels configured in cfg.
+// Webhooks are signed by signer.
+func escalationNotifiers(cfg config.AlertsConfig, signer alerts.Signer) []alerts.Notifier {
+	var notifiers []alerts.Notifier
+	if cfg.EscalationWebhookURL != "" {
+		notifiers = append(notifiers, alerts.NewWebhookNotifier(cfg.EscalationWebhookURL, signer))
+	}
+	if cfg.EscalationTelegramChat != "" {
+		notifiers = append(notifiers, alerts.NewTelegramNotifier(cfg.TelegramToken, cfg.EscalationTelegramChat))
+	}
+	return notifiers
+}
+
+// openDatabase connects to the database and runs migrations.
+func openDatabase(dbConfig *persistence.DatabaseConfig) (*persistence.Database, error) {
+	database, err := persistence.NewDatabase(dbConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if err := database.Migrate(schemaModels...); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to run database migrations: %w", err)
+	}
+
+	return database, nil
+}
+
+// schemaModels are the models whose tables AutoMigrate creates.
+var schemaModels = []any{
+	&domain.GlucoseMeasurement{},
+	&domain.SensorConfig{},
+	&domain.UserPreferences{},
+	&domain.DeviceInfo{},
+	&domain.GlucoseTargets{},
+	&domain.DashboardConfig{},
+	&domain.DisplayPreferences{},
+	&domain.SavedView{},
+	&domain.Note{},
+	&domain.InsulinDose{},
+	&domain.CarbIntake{},
+	&domain.APIToken{},
+	&domain.SigningKey{},
+	&domain.Alert{},
+	&domain.TreatmentEntry{},
+	&domain.UpstreamOutage{},
+	&domain.UpstreamSession{},
+	&domain.SensorAttachment{},
+	&domain.Job{},
+	&domain.GlucoseEvent{},
+	&domain.GlucoseRollup{},
+	&domain.DailySummary{},
 }
 
 func main() {
+	// Subcommands (version, init, self-update, export, erase) run instead of the daemon
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	// Setup logger
-	setupLogger()
+	logRing := setupLogger()
 
 	slog.Info("glcore starting")
 
+	// Load the configuration file written by glcore init, if any
+	envFile, err := loadEnvFile()
+	if err != nil {
+		slog.Error("failed to load configuration file", "error", err)
+		os.Exit(1)
+	}
+	if envFile != "" {
+		slog.Info("configuration file loaded", "path", envFile)
+	}
+
 	// Load centralized configuration
 	cfg, err := config.Load()
 	if err != nil {
 		slog.Error("failed to load configuration", "error", err)
+		if envFile == "" {
+			slog.Info("no configuration file found, run 'glcore init' to create one")
+		}
 		os.Exit(1)
 	}
+	logConfigWarnings(cfg)
+
+	// Low-memory mode: cap the heap so the GC works harder before the OS has to swap
+	if cfg.Runtime.LowMemory {
+		if cfg.Runtime.MemoryLimit > 0 {
+			debug.SetMemoryLimit(cfg.Runtime.MemoryLimit)
+		}
+		slog.Info("low memory mode enabled",
+			"memoryLimitMB", debug.SetMemoryLimit(-1)>>20,
+			"eventBufferSize", cfg.Runtime.EventBufferSize,
+		)
+	}
 
 	// Database setup
 	dbStart := time.Now()
 	dbConfig := cfg.Database.ToPersistenceConfig()
-	database, err := persistence.NewDatabase(dbConfig)
+	database, err := openDatabase(dbConfig)
 	if err != nil {
-		slog.Error("failed to connect to database", "error", err)
+		slog.Error("failed to open database", "error", err)
 		os.Exit(1)
 	}
 	defer func() {
@@ -79,18 +282,6 @@ func main() {
 		slog.Info("database closed")
 	}()
 
-	// Run migrations
-	if err := database.AutoMigrate(
-		&domain.GlucoseMeasurement{},
-		&domain.SensorConfig{},
-		&domain.UserPreferences{},
-		&domain.DeviceInfo{},
-		&domain.GlucoseTargets{},
-	); err != nil {
-		slog.Error("failed to run database migrations", "error", err)
-		os.Exit(1)
-	}
-
 	// Database health check
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -101,52 +292,219 @@ func main() {
 
 	slog.Info("database ready",
 		"type", dbConfig.Type,
+		"backend", dbConfig.Backend,
 		"duration", time.Since(dbStart),
 	)
 
+	// Fault injection (developer mode): random upstream and database failures
+	var faults *faultinject.Injector
+	if cfg.Faults.Enabled() {
+		faults = faultinject.NewInjector(cfg.Faults, slog.Default())
+		if err := faults.RegisterGORM(database.DB()); err != nil {
+			slog.Error("failed to enable fault injection", "error", err)
+			os.Exit(1)
+		}
+		slog.Warn("FAULT INJECTION ENABLED, do not use in production",
+			"upstreamRate", cfg.Faults.UpstreamRate,
+			"dbLockRate", cfg.Faults.DBLockRate,
+			"slowQueryRate", cfg.Faults.SlowQueryRate,
+			"slowQueryDelay", cfg.Faults.SlowQueryDelay,
+		)
+	}
+
 	// Create repositories
-	glucoseRepo := repository.NewGlucoseRepository(database.DB())
+	var glucoseRepo repository.GlucoseRepository = repository.NewGlucoseRepository(database.DB())
+	if dbConfig.Backend == persistence.BackendSQL {
+		sqlRepo, err := repository.NewGlucoseRepositorySQL(database.DB())
+		if err != nil {
+			slog.Error("failed to create SQL glucose repository", "error", err)
+			os.Exit(1)
+		}
+		defer sqlRepo.Close()
+		glucoseRepo = sqlRepo
+	}
 	sensorRepo := repository.NewSensorRepository(database.DB())
 	userRepo := repository.NewUserRepository(database.DB())
 	deviceRepo := repository.NewDeviceRepository(database.DB())
 	targetsRepo := repository.NewTargetsRepository(database.DB())
+	dashboardRepo := repository.NewDashboardRepository(database.DB())
+	prefsRepo := repository.NewPreferencesRepository(database.DB())
+	tokenRepo := repository.NewTokenRepository(database.DB())
+	signingKeyRepo := repository.NewSigningKeyRepository(database.DB())
+	alertRepo := repository.NewAlertRepository(database.DB())
+	glucoseEventRepo := repository.NewGlucoseEventRepository(database.DB())
+	glucoseRollupRepo := repository.NewGlucoseRollupRepository(database.DB())
+	dailySummaryRepo := repository.NewDailySummaryRepository(database.DB())
+	treatmentRepo := repository.NewTreatmentRepository(database.DB())
+	privacyRepo := repository.NewPrivacyRepository(database.DB())
+	viewRepo := repository.NewViewRepository(database.DB())
+	noteRepo := repository.NewNoteRepository(database.DB())
+	insulinRepo := repository.NewInsulinRepository(database.DB())
+	carbRepo := repository.NewCarbRepository(database.DB())
+	upstreamRepo := repository.NewUpstreamRepository(database.DB())
+	upstreamSessionRepo := repository.NewUpstreamSessionRepository(database.DB())
+	attachmentRepo := repository.NewAttachmentRepository(database.DB())
+	jobRepo := repository.NewJobRepository(database.DB())
 
 	// Create Unit of Work
 	uow := repository.NewUnitOfWork(database.DB())
 
 	// Create event broker for SSE streaming
-	eventBroker := events.NewBroker(10, slog.Default())
+	eventBroker := events.NewBroker(cfg.Runtime.EventBufferSize, slog.Default())
 	eventBroker.Start()
 	defer eventBroker.Stop()
 
 	// Create services with event broker
-	glucoseService := service.NewGlucoseService(glucoseRepo, slog.Default(), eventBroker)
-	sensorService := service.NewSensorService(sensorRepo, uow, slog.Default(), eventBroker)
-	configService := service.NewConfigService(userRepo, deviceRepo, targetsRepo, slog.Default())
+	glucoseService := service.NewGlucoseService(glucoseRepo, cfg.Statistics.TargetBands, slog.Default(), eventBroker)
+	sensorService := service.NewSensorService(sensorRepo, glucoseRepo, uow, cfg.Sensor.GracePeriod, slog.Default(), eventBroker)
+	configService := service.NewConfigService(userRepo, deviceRepo, targetsRepo, dashboardRepo, prefsRepo, slog.Default(), eventBroker)
+	syncService := service.NewSyncService(glucoseRepo, sensorRepo, slog.Default())
+	tokenService := service.NewTokenService(tokenRepo, slog.Default())
+	signingService := service.NewSigningService(signingKeyRepo, slog.Default())
+	modeService := service.NewModeService(slog.Default())
+	alertService := service.NewAlertService(alertRepo, slog.Default())
+	glucoseEventService := service.NewGlucoseEventService(glucoseEventRepo, glucoseRepo, targetsRepo, uow, slog.Default())
+	dailySummaryService := service.NewDailySummaryService(dailySummaryRepo, glucoseRepo, targetsRepo, slog.Default())
+	treatmentService := service.NewTreatmentService(treatmentRepo, glucoseRepo, uow, slog.Default())
+	privacyService := service.NewPrivacyService(privacyRepo, uow, slog.Default())
+	viewService := service.NewViewService(viewRepo, slog.Default())
+	noteService := service.NewNoteService(noteRepo, slog.Default())
+	logbookService := service.NewLogbookService(insulinRepo, carbRepo, slog.Default())
+	upstreamService := service.NewUpstreamService(upstreamRepo, slog.Default())
+	sessionService := service.NewSessionService(upstreamSessionRepo)
+	attachmentService := service.NewAttachmentService(attachmentRepo, sensorRepo, cfg.API.AttachmentsDir, slog.Default())
+
+	// Downsample and prune old measurements (opt-in)
+	var retentionService service.RetentionService
+	if cfg.Retention.Enabled() {
+		retentionService = service.NewRetentionService(
+			glucoseRollupRepo,
+			glucoseRepo,
+			uow,
+			time.Duration(cfg.Retention.DownsampleDays)*24*time.Hour,
+			time.Duration(cfg.Retention.RawDays)*24*time.Hour,
+			slog.Default(),
+		)
+	}
+
+	// Forecast the database growth with the retention policy
+	storageService := service.NewStorageService(
+		glucoseRepo,
+		glucoseRollupRepo,
+		database.Size,
+		time.Duration(cfg.Retention.DownsampleDays)*24*time.Hour,
+		time.Duration(cfg.Retention.RawDays)*24*time.Hour,
+	)
+
+	// Saved display preferences override the tight band of GLCMD_TARGET_BANDS
+	if prefs, err := prefsRepo.Find(context.Background()); err == nil {
+		glucoseService.SetTightBand(prefs.TightBand())
+	}
+
+	// Create the background job manager (imports, maintenance, reports and replication)
+	jobManager := jobs.NewManager(jobRepo, cfg.Jobs.Workers, cfg.Jobs.Retention, slog.Default())
+
+	// Ping the external monitor, upload to Nightscout and evaluate the alert rules after each successful fetch (opt-in)
+	// In multi-patient mode, background work follows the configured patient
+	afterFetchCtx, stopAfterFetch := context.WithCancel(patientContext(cfg.Credentials))
+	defer stopAfterFetch()
+	var afterFetch []func()
+	if cfg.Heartbeat.URL != "" {
+		pinger := heartbeat.NewPinger(cfg.Heartbeat.URL, slog.Default())
+		go pinger.Run(afterFetchCtx)
+		afterFetch = append(afterFetch, pinger.Notify)
+		slog.Info("heartbeat monitoring enabled")
+	}
+	if cfg.Nightscout.URL != "" {
+		uploader := nightscout.NewUploader(cfg.Nightscout.URL, cfg.Nightscout.APISecret, glucoseService, slog.Default())
+		go uploader.Run(afterFetchCtx)
+		afterFetch = append(afterFetch, uploader.Notify)
+		slog.Info("nightscout upload enabled")
+	}
+	// Alerts always reach the event stream, and the configured channels
+	notifiers := alertNotifiers(cfg.Alerts, signingService)
+	evaluator := alerts.NewEvaluator(cfg.Alerts.Rules, glucoseService, sensorService, modeService,
+		append(notifiers, alerts.NewEventNotifier(eventBroker)), slog.Default())
+	if cfg.Alerts.Escalates() {
+		evaluator.SetEscalation(alerts.Escalation{
+			After:     cfg.Alerts.EscalateAfter,
+			Notifiers: escalationNotifiers(cfg.Alerts, signingService),
+			Readings:  glucoseService,
+			History:   alertService,
+		})
+		slog.Info("low alert escalation enabled", "after", cfg.Alerts.EscalateAfter)
+	}
+	go evaluator.Run(afterFetchCtx)
+	afterFetch = append(afterFetch, evaluator.Notify)
+	if cfg.Alerts.Enabled() {
+		slog.Info("alert notifications enabled", "channels", len(notifiers), "rules", len(cfg.Alerts.Rules.Rules))
+	}
+	if cfg.Alerts.TelegramCommands {
+		bot := alerts.NewTelegramBot(cfg.Alerts.TelegramToken, cfg.Alerts.TelegramChat, glucoseService, sensorService, configService, slog.Default())
+		go bot.Run(afterFetchCtx)
+		slog.Info("telegram commands enabled")
+	}
+	var afterFetchFn func()
+	if len(afterFetch) > 0 {
+		afterFetchFn = func() {
+			for _, notify := range afterFetch {
+				notify()
+			}
+		}
+	}
 
 	// Create daemon
-	d, err := daemon.New(glucoseService, sensorService, configService, cfg.Credentials.Email, cfg.Credentials.Password)
+	d, err := daemon.New(glucoseService, sensorService, configService, modeService, alertService, upstreamService, afterFetchFn, cfg.Credentials.Email, cfg.Credentials.Password, cfg.Credentials.SecondaryEmail, cfg.Credentials.SecondaryPassword)
 	if err != nil {
 		slog.Error("failed to create daemon", "error", err)
 		os.Exit(1)
 	}
+	if faults != nil {
+		d.SetTransport(faults.Transport(nil))
+	}
+	d.SetPatients(cfg.Credentials.PatientID, cfg.Credentials.MultiPatient)
+	d.SetSessionService(sessionService)
+	d.SetAcceptTerms(cfg.Credentials.AcceptTerms)
 
 	// Create unified API server with daemon health status callback
-	apiServer := api.NewServer(
-		cfg.API.Port,
-		glucoseService,
-		sensorService,
-		configService,
-		eventBroker,
-		func() daemon.HealthStatus {
+	apiServer := api.NewServer(api.Options{
+		Port:                cfg.API.Port,
+		SyncToken:           cfg.Sync.Token,
+		AdminToken:          cfg.API.AdminToken,
+		APITokens:           cfg.API.Tokens,
+		DefaultPatientID:    repository.PatientFromContext(patientContext(cfg.Credentials)),
+		GlucoseService:      glucoseService,
+		SensorService:       sensorService,
+		ConfigService:       configService,
+		SyncService:         syncService,
+		TokenService:        tokenService,
+		SigningService:      signingService,
+		EventBroker:         eventBroker,
+		ModeService:         modeService,
+		AlertService:        alertService,
+		GlucoseEventService: glucoseEventService,
+		DailySummaryService: dailySummaryService,
+		TreatmentService:    treatmentService,
+		PrivacyService:      privacyService,
+		ViewService:         viewService,
+		NoteService:         noteService,
+		LogbookService:      logbookService,
+		UpstreamService:     upstreamService,
+		AttachmentService:   attachmentService,
+		StorageService:      storageService,
+		JobManager:          jobManager,
+		LogRing:             logRing,
+		GetHealthStatus: func() daemon.HealthStatus {
 			return d.GetHealthStatus()
 		},
-		func() bool {
+		GetConnectionInfo: d.GetConnectionInfo,
+		GetFetchStats:     d.GetFetchStats,
+		GetDatabaseHealth: func() bool {
 			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 			defer cancel()
 			return database.Ping(ctx) == nil
 		},
-		func() *api.DatabasePoolStats {
+		GetDatabasePoolStats: func() *api.DatabasePoolStats {
 			stats, err := database.Stats()
 			if err != nil {
 				return nil
@@ -159,14 +517,88 @@ func main() {
 				WaitDuration:    stats.WaitDuration.String(),
 			}
 		},
-		slog.Default(),
-	)
+	}, slog.Default())
 
+	apiServer.SetTermsAcceptor(d.AcceptTerms)
+	if cfg.API.HealthPort != 0 {
+		apiServer.SetHealthPort(cfg.API.HealthPort)
+	}
 	if err := apiServer.Start(); err != nil {
 		slog.Error("failed to start API server", "error", err)
 		os.Exit(1)
 	}
 	slog.Info("API server listening", "port", cfg.API.Port)
+	if cfg.API.HealthPort != 0 {
+		slog.Info("health listener listening", "port", cfg.API.HealthPort)
+	}
+	printBanner(cfg)
+
+	// Replication from the primary instance (secondary mode only)
+	var replicator *replication.Replicator
+	if cfg.Sync.PrimaryURL != "" {
+		replicator = replication.NewReplicator(
+			cfg.Sync.PrimaryURL,
+			cfg.Sync.Token,
+			cfg.Sync.Days,
+			syncService,
+			glucoseService,
+			sensorService,
+			slog.Default(),
+		)
+		slog.Info("replication enabled",
+			"primary", cfg.Sync.PrimaryURL,
+			"interval", cfg.Sync.Interval,
+			"days", cfg.Sync.Days,
+		)
+	}
+
+	// Morning summary (opt-in)
+	var scheduler *summary.Scheduler
+	if cfg.Summary.Enabled {
+		scheduler = summary.NewScheduler(
+			cfg.Summary.MorningAt,
+			cfg.Summary.Night,
+			repository.PatientFromContext(patientContext(cfg.Credentials)),
+			glucoseService,
+			eventBroker,
+			slog.Default(),
+		)
+	}
+
+	// History backfill, with its own LibreView session
+	var backfillClient *libreclient.Client
+	if faults != nil {
+		backfillClient = libreclient.NewClient(&http.Client{Timeout: libreclient.DefaultTimeout, Transport: faults.Transport(nil)})
+	}
+	backfiller := backfill.New(backfillClient, cfg.Credentials.Email, cfg.Credentials.Password,
+		cfg.Credentials.PatientID, cfg.Credentials.MultiPatient, glucoseService, retentionService, slog.Default())
+
+	// Start the background jobs, then run the startup maintenance and replication
+	startupJobs, err := registerJobs(jobManager, attachmentService, glucoseEventService, dailySummaryService, retentionService, replicator, cfg.Sync.Interval, scheduler, backfiller)
+	if err != nil {
+		slog.Error("failed to register background jobs", "error", err)
+		os.Exit(1)
+	}
+	if err := jobManager.Start(context.Background()); err != nil {
+		slog.Error("failed to start background jobs", "error", err)
+		os.Exit(1)
+	}
+	for _, jobType := range startupJobs {
+		if _, err := jobManager.Enqueue(context.Background(), jobType, nil); err != nil {
+			slog.Warn("failed to enqueue startup job", "type", jobType, "error", err)
+		}
+	}
+
+	// Invoke the plugin executables on each event (opt-in)
+	pluginCtx, stopPlugins := context.WithCancel(context.Background())
+	defer stopPlugins()
+	if len(cfg.Plugins.Commands) > 0 {
+		runner := plugin.NewRunner(cfg.Plugins.Commands, cfg.Plugins.Timeout, cfg.Plugins.Concurrency, slog.Default())
+		// Only the events of the configured patient, as alerts
+		patientID := repository.PatientFromContext(patientContext(cfg.Credentials))
+		go runner.Run(pluginCtx, eventBroker.SubscribePatient("plugins", patientID, plugin.EventTypes))
+		slog.Info("plugins enabled", "count", len(cfg.Plugins.Commands))
+	}
 
 	// Setup signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -186,13 +618,16 @@ func main() {
 		// Stop daemon
 		d.Stop()
 
-		// Stop API server
+		// Stop API server, then the background jobs (interrupted jobs run again at the next start)
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
 		if err := apiServer.Stop(ctx); err != nil {
 			slog.Error("failed to stop API server", "error", err)
 		}
+		if err := jobManager.Shutdown(ctx); err != nil {
+			slog.Error("failed to stop background jobs", "error", err)
+		}
 
 		// Wait for daemon to finish
 		if err := <-errChan; err != nil {
@@ -207,6 +642,7 @@ func main() {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 			apiServer.Stop(ctx)
+			jobManager.Shutdown(ctx)
 
 			os.Exit(1)
 		}
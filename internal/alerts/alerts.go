@@ -0,0 +1,467 @@
+// Package alerts evaluates alert rules after each fetch and sends the alerts
+// through the configured notification channels (webhook, email, Telegram,
+// Pushover, local sound) and the event stream.
+//
+// Each rule fires once when its condition starts, and again only after the
+// condition has ended: a glucose value staying low sends a single alert, not
+// one per reading. Low and falling glucose are evaluated against the
+// thresholds of the current activity mode, so exercise mode applies to
+// notifications as it does to the alert history.
+//
+// Sensor expiry reminders are the exception: one is sent at each configured
+// lead time before expiry (e.g. 72h, 24h and 2h), once per sensor.
+//
+// Rules are also evaluated on a timer, since no fetch completes while data is
+// stale.
+//
+// A low alert that is still unacknowledged in the alert history once the
+// escalation delay has passed is forwarded, with the last readings, to the
+// escalation channels (e.g. a partner's Telegram), once per low episode.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// Rule identifies an alert condition.
+type Rule string
+
+const (
+	// RuleLow fires when glucose drops below the low threshold of the current mode.
+	RuleLow Rule = "low"
+	// RuleHigh fires when glucose rises above HighMgDl.
+	RuleHigh Rule = "high"
+	// RuleFalling fires when glucose falls as fast as the current mode alerts on.
+	RuleFalling Rule = "falling"
+	// RuleStale fires when no new reading has been stored for StaleAfter.
+	RuleStale Rule = "stale"
+	// RuleSensorExpiring fires at each of the SensorReminders lead times before
+	// the current sensor expires.
+	RuleSensorExpiring Rule = "sensor-expiring"
+)
+
+// Rules lists the valid rules.
+var Rules = []Rule{RuleLow, RuleHigh, RuleFalling, RuleStale, RuleSensorExpiring}
+
+// Rule defaults
+const (
+	// DefaultHighMgDl is the high glucose alert threshold (level 2 hyperglycemia).
+	DefaultHighMgDl = 250
+	// DefaultStaleAfter is how long without a new reading before data is stale.
+	DefaultStaleAfter = 15 * time.Minute
+)
+
+// DefaultSensorReminders are the lead times of the sensor expiry reminders:
+// three days to order a sensor, a day to plan the change, and the last hours.
+var DefaultSensorReminders = []time.Duration{72 * time.Hour, 24 * time.Hour, 2 * time.Hour}
+
+const (
+	// checkInterval is how often the rules are evaluated between fetches.
+	checkInterval = time.Minute
+	// readTimeout bounds the reads of the latest reading and sensor.
+	readTimeout = 5 * time.Second
+	// sendTimeout bounds a single notification.
+	sendTimeout = 30 * time.Second
+	// escalationHistory is how far back the readings of an escalation go.
+	escalationHistory = 30 * time.Minute
+	// escalationReadings is the maximum number of readings in an escalation.
+	escalationReadings = 6
+)
+
+// Config holds the enabled rules and their thresholds.
+// SensorReminders are the lead times before sensor expiry, in any order.
+type Config struct {
+	Rules           []Rule
+	HighMgDl        int
+	StaleAfter      time.Duration
+	SensorReminders []time.Duration
+}
+
+// DefaultConfig returns a Config enabling all rules with the default thresholds.
+func DefaultConfig() Config {
+	return Config{
+		Rules:           Rules,
+		HighMgDl:        DefaultHighMgDl,
+		StaleAfter:      DefaultStaleAfter,
+		SensorReminders: DefaultSensorReminders,
+	}
+}
+
+// Alert is a notification sent when a rule fires.
+type Alert struct {
+	Rule      Rule      `json:"rule"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	ValueMgDl int       `json:"valueMgDl,omitempty"` // Glucose value that fired the alert (0 for stale data and sensor alerts)
+	At        time.Time `json:"at"`                  // Timestamp of the reading or evaluation that fired the alert
+}
+
+// Urgent reports whether the alert needs immediate attention (low or falling
+// glucose), so channels supporting priorities can raise it.
+func (a Alert) Urgent() bool {
+	return a.Rule == RuleLow || a.Rule == RuleFalling
+}
+
+// Notifier delivers alerts through a notification channel.
+type Notifier interface {
+	// Name identifies the channel in logs
+	Name() string
+
+	// Send delivers an alert
+	Send(ctx context.Context, alert Alert) error
+}
+
+// MeasurementSource reads the latest stored reading.
+type MeasurementSource interface {
+	GetLatestMeasurement(ctx context.Context) (*domain.GlucoseMeasurement, error)
+}
+
+// SensorSource reads the current sensor.
+type SensorSource interface {
+	GetCurrentSensor(ctx context.Context) (*domain.SensorConfig, error)
+}
+
+// ModeSource returns the current activity mode and its thresholds.
+type ModeSource interface {
+	Current() *domain.ModeStatus
+}
+
+// RecentSource reads the stored readings of a time range, newest first.
+type RecentSource interface {
+	GetMeasurementsByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error)
+}
+
+// AlertHistory reads the alert history, where alerts are acknowledged.
+type AlertHistory interface {
+	GetAlertsWithFilters(ctx context.Context, filters repository.AlertFilters, limit, offset int) ([]*domain.Alert, int64, error)
+}
+
+// DefaultEscalateAfter is how long a low alert may stay unacknowledged before
+// it is escalated.
+const DefaultEscalateAfter = 15 * time.Minute
+
+// Escalation forwards the low alerts not acknowledged within After to a
+// secondary contact through Notifiers, with the readings from Readings.
+// Acknowledgements are read from History.
+type Escalation struct {
+	After     time.Duration
+	Notifiers []Notifier
+	Readings  RecentSource
+	History   AlertHistory
+}
+
+// Evaluator evaluates the rules and sends the alerts that fire.
+type Evaluator struct {
+	cfg          Config
+	enabled      map[Rule]bool
+	measurements MeasurementSource
+	sensors      SensorSource
+	modes        ModeSource
+	notifiers    []Notifier
+	logger       *slog.Logger
+	pending      chan struct{}
+	now          func() time.Time
+
+	// escalation is nil unless set with SetEscalation.
+	escalation *Escalation
+
+	// active holds the rules whose condition currently holds, and reminded
+	// the reminder lead times already passed for remindedSerial. lowSince is
+	// the reading that started the current low episode, and escalated
+	// whether that episode has been checked for escalation. Only used by Run.
+	active         map[Rule]bool
+	reminded       map[time.Duration]bool
+	remindedSerial string
+	lowSince       time.Time
+	escalated      bool
+}
+
+// NewEvaluator creates an Evaluator sending alerts to notifiers.
+// modes may be nil, in which case the default thresholds apply.
+func NewEvaluator(cfg Config, measurements MeasurementSource, sensors SensorSource, modes ModeSource, notifiers []Notifier, logger *slog.Logger) *Evaluator {
+	enabled := make(map[Rule]bool, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		enabled[rule] = true
+	}
+
+	return &Evaluator{
+		cfg:          cfg,
+		enabled:      enabled,
+		measurements: measurements,
+		sensors:      sensors,
+		modes:        modes,
+		notifiers:    notifiers,
+		logger:       logger,
+		pending:      make(chan struct{}, 1),
+		now:          time.Now,
+		active:       make(map[Rule]bool),
+		reminded:     make(map[time.Duration]bool),
+	}
+}
+
+// SetEscalation enables the escalation of unacknowledged low alerts.
+// Must be called before Run.
+func (e *Evaluator) SetEscalation(escalation Escalation) {
+	e.escalation = &escalation
+}
+
+// Notify requests an evaluation without blocking the caller.
+// Requests made while an evaluation is pending are coalesced into it.
+func (e *Evaluator) Notify() {
+	select {
+	case e.pending <- struct{}{}:
+	default:
+	}
+}
+
+// Run evaluates the rules after each Notify and every minute until ctx is
+// cancelled.
+func (e *Evaluator) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.pending:
+			e.evaluate(ctx)
+		case <-ticker.C:
+			e.evaluate(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// evaluate checks every enabled rule and sends the alerts that fire, then
+// escalates the current low alert if it is due.
+func (e *Evaluator) evaluate(ctx context.Context) {
+	for _, alert := range e.check(ctx) {
+		e.send(ctx, e.notifiers, alert)
+	}
+	if alert, ok := e.checkEscalation(ctx); ok {
+		e.send(ctx, e.escalation.Notifiers, alert)
+	}
+}
+
+// check evaluates the enabled rules and returns the alerts of the conditions
+// that started since the last evaluation. A rule that cannot be evaluated
+// (read error, no data yet) keeps its previous state.
+func (e *Evaluator) check(ctx context.Context) []Alert {
+	now := e.now()
+	var fired []Alert
+
+	readCtx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	if e.enabled[RuleLow] || e.enabled[RuleHigh] || e.enabled[RuleFalling] || e.enabled[RuleStale] {
+		m, err := e.measurements.GetLatestMeasurement(readCtx)
+		if err != nil {
+			e.logger.Debug("alert rules skipped, no latest reading", "error", err)
+		} else {
+			fired = append(fired, e.checkMeasurement(m, now)...)
+		}
+	}
+
+	if e.enabled[RuleSensorExpiring] {
+		sensor, err := e.sensors.GetCurrentSensor(readCtx)
+		if err != nil {
+			e.logger.Debug("sensor alert rule skipped, no current sensor", "error", err)
+		} else if e.sensorReminderDue(sensor, now) {
+			remaining := sensor.ExpiresAt.Sub(now).Round(time.Hour)
+			fired = append(fired, Alert{
+				Rule:    RuleSensorExpiring,
+				Title:   "Sensor expiring",
+				Message: fmt.Sprintf("Sensor %s expires in %s (%s)", sensor.SerialNumber, formatHours(remaining), sensor.ExpiresAt.Local().Format("Mon 15:04")),
+				At:      now,
+			})
+		}
+	}
+
+	return fired
+}
+
+// checkMeasurement evaluates the glucose and stale data rules on the latest reading.
+func (e *Evaluator) checkMeasurement(m *domain.GlucoseMeasurement, now time.Time) []Alert {
+	thresholds := domain.DefaultAlertThresholds()
+	if e.modes != nil {
+		if mode := e.modes.Current(); mode != nil {
+			thresholds = mode.Thresholds
+		}
+	}
+	var fired []Alert
+
+	if alert, ok := e.transition(RuleLow, thresholds.IsLow(m)); ok {
+		e.lowSince = m.Timestamp
+		e.escalated = false
+		alert.Title = "Low glucose"
+		alert.Message = fmt.Sprintf("Glucose is %d mg/dL (%.1f mmol/L), below %d mg/dL", m.ValueInMgPerDl, m.Value, thresholds.LowMgDl)
+		fired = append(fired, withReading(alert, m))
+	}
+
+	if alert, ok := e.transition(RuleHigh, m.ValueInMgPerDl > e.cfg.HighMgDl); ok {
+		alert.Title = "High glucose"
+		alert.Message = fmt.Sprintf("Glucose is %d mg/dL (%.1f mmol/L), above %d mg/dL", m.ValueInMgPerDl, m.Value, e.cfg.HighMgDl)
+		fired = append(fired, withReading(alert, m))
+	}
+
+	if alert, ok := e.transition(RuleFalling, thresholds.IsFalling(m)); ok {
+		alert.Title = "Glucose falling fast"
+		alert.Message = fmt.Sprintf("Glucose is %d mg/dL (%.1f mmol/L) and falling fast", m.ValueInMgPerDl, m.Value)
+		fired = append(fired, withReading(alert, m))
+	}
+
+	age := now.Sub(m.Timestamp)
+	if alert, ok := e.transition(RuleStale, age >= e.cfg.StaleAfter); ok {
+		alert.Title = "No recent glucose data"
+		alert.Message = fmt.Sprintf("No reading received for %s (last: %d mg/dL at %s)", age.Round(time.Minute), m.ValueInMgPerDl, m.Timestamp.Local().Format("15:04"))
+		alert.At = now
+		fired = append(fired, alert)
+	}
+
+	return fired
+}
+
+// checkEscalation returns the escalation of the current low episode once it
+// has lasted the escalation delay without its alert being acknowledged. Each
+// episode is checked once; a failed acknowledgement read is retried at the
+// next evaluation.
+func (e *Evaluator) checkEscalation(ctx context.Context) (Alert, bool) {
+	if e.escalation == nil || !e.active[RuleLow] || e.escalated {
+		return Alert{}, false
+	}
+	now := e.now()
+	if now.Sub(e.lowSince) < e.escalation.After {
+		return Alert{}, false
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	acknowledged, err := e.lowAcknowledged(readCtx)
+	if err != nil {
+		e.logger.Warn("alert escalation skipped, failed to read the alert history", "error", err)
+		return Alert{}, false
+	}
+	e.escalated = true
+	if acknowledged {
+		return Alert{}, false
+	}
+
+	readings, err := e.escalation.Readings.GetMeasurementsByTimeRange(readCtx, now.Add(-escalationHistory), now)
+	if err != nil {
+		e.logger.Warn("failed to read the readings of the alert escalation", "error", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Glucose has been low since %s and the alert was not acknowledged within %s.",
+		e.lowSince.Local().Format("15:04"), e.escalation.After)
+	if len(readings) > 0 {
+		b.WriteString("\nLast readings:")
+		for _, m := range readings[:min(len(readings), escalationReadings)] {
+			fmt.Fprintf(&b, "\n%s  %d mg/dL (%.1f mmol/L)", m.Timestamp.Local().Format("15:04"), m.ValueInMgPerDl, m.Value)
+		}
+	}
+
+	e.logger.Warn("low alert not acknowledged, escalating", "lowSince", e.lowSince, "after", e.escalation.After)
+
+	alert := Alert{
+		Rule:    RuleLow,
+		Title:   "Unacknowledged low glucose",
+		Message: b.String(),
+		At:      now,
+	}
+	if len(readings) > 0 {
+		alert.ValueMgDl = readings[0].ValueInMgPerDl
+	}
+	return alert, true
+}
+
+// lowAcknowledged reports whether the low alert of the current episode has
+// been acknowledged. The daemon records it from the same reading, or an
+// earlier one if this evaluation ran late; an older alert belongs to a
+// previous episode.
+func (e *Evaluator) lowAcknowledged(ctx context.Context) (bool, error) {
+	alertType := domain.AlertTypeLow
+	since := e.lowSince.Add(-e.escalation.After)
+	latest, _, err := e.escalation.History.GetAlertsWithFilters(ctx, repository.AlertFilters{
+		StartTime: &since,
+		Type:      &alertType,
+	}, 1, 0)
+	if err != nil {
+		return false, err
+	}
+	return len(latest) > 0 && latest[0].IsAcknowledged(), nil
+}
+
+// sensorReminderDue reports whether a reminder lead time has been reached
+// since the last evaluation. Lead times passed together (e.g. at startup)
+// send a single reminder. Expired sensors are reported by the daemon instead.
+func (e *Evaluator) sensorReminderDue(sensor *domain.SensorConfig, now time.Time) bool {
+	if sensor.SerialNumber != e.remindedSerial {
+		e.remindedSerial = sensor.SerialNumber
+		clear(e.reminded)
+	}
+
+	remaining := sensor.ExpiresAt.Sub(now)
+	if remaining <= 0 {
+		return false
+	}
+
+	due := false
+	for _, lead := range e.cfg.SensorReminders {
+		if remaining <= lead && !e.reminded[lead] {
+			e.reminded[lead] = true
+			due = true
+		}
+	}
+	return due
+}
+
+// transition records the state of a rule and returns an alert when its
+// condition starts. Disabled rules never fire.
+func (e *Evaluator) transition(rule Rule, holds bool) (Alert, bool) {
+	if !e.enabled[rule] {
+		return Alert{}, false
+	}
+	started := holds && !e.active[rule]
+	e.active[rule] = holds
+	return Alert{Rule: rule}, started
+}
+
+// send delivers an alert through notifiers. Failures are logged and not
+// retried: the condition is still visible in the API and the alert history.
+func (e *Evaluator) send(ctx context.Context, notifiers []Notifier, alert Alert) {
+	e.logger.Info("alert fired", "rule", alert.Rule, "message", alert.Message)
+
+	for _, notifier := range notifiers {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err := notifier.Send(sendCtx, alert)
+		cancel()
+		if err != nil {
+			e.logger.Warn("alert notification failed", "channel", notifier.Name(), "rule", alert.Rule, "error", err)
+		}
+	}
+}
+
+// withReading sets the value and timestamp of the reading that fired an alert.
+func withReading(alert Alert, m *domain.GlucoseMeasurement) Alert {
+	alert.ValueMgDl = m.ValueInMgPerDl
+	alert.At = m.Timestamp
+	return alert
+}
+
+// formatHours formats a duration rounded to the hour, e.g. "5h" or "1d 3h".
+func formatHours(d time.Duration) string {
+	hours := int(d.Hours())
+	if hours < 24 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+}
@@ -0,0 +1,358 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// fakeSources serves a fixed latest reading, current sensor and mode.
+type fakeSources struct {
+	latest *domain.GlucoseMeasurement
+	sensor *domain.SensorConfig
+	mode   *domain.ModeStatus
+}
+
+func (f *fakeSources) GetLatestMeasurement(ctx context.Context) (*domain.GlucoseMeasurement, error) {
+	if f.latest == nil {
+		return nil, errors.New("not found")
+	}
+	return f.latest, nil
+}
+
+func (f *fakeSources) GetCurrentSensor(ctx context.Context) (*domain.SensorConfig, error) {
+	if f.sensor == nil {
+		return nil, errors.New("not found")
+	}
+	return f.sensor, nil
+}
+
+func (f *fakeSources) Current() *domain.ModeStatus {
+	return f.mode
+}
+
+// recordingNotifier records the alerts sent.
+type recordingNotifier struct {
+	sent []Alert
+	err  error
+}
+
+func (n *recordingNotifier) Name() string { return "recording" }
+
+func (n *recordingNotifier) Send(ctx context.Context, alert Alert) error {
+	n.sent = append(n.sent, alert)
+	return n.err
+}
+
+var testNow = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func reading(mgdl int, trend int, age time.Duration) *domain.GlucoseMeasurement {
+	return &domain.GlucoseMeasurement{
+		Timestamp:      testNow.Add(-age),
+		Value:          float64(mgdl) / 18,
+		ValueInMgPerDl: mgdl,
+		TrendArrow:     &trend,
+		Type:           domain.GlucoseTypeCurrent,
+	}
+}
+
+func newTestEvaluator(cfg Config, sources *fakeSources) (*Evaluator, *recordingNotifier) {
+	notifier := &recordingNotifier{}
+	e := NewEvaluator(cfg, sources, sources, sources, []Notifier{notifier}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	e.now = func() time.Time { return testNow }
+	return e, notifier
+}
+
+func rulesOf(alerts []Alert) []Rule {
+	var rules []Rule
+	for _, a := range alerts {
+		rules = append(rules, a.Rule)
+	}
+	return rules
+}
+
+func TestEvaluator_FiresOnceWhileConditionHolds(t *testing.T) {
+	sources := &fakeSources{latest: reading(60, domain.TrendArrowStable, time.Minute)}
+	e, _ := newTestEvaluator(DefaultConfig(), sources)
+
+	fired := e.check(context.Background())
+	if len(fired) != 1 || fired[0].Rule != RuleLow {
+		t.Fatalf("expected a low alert, got %v", rulesOf(fired))
+	}
+	if fired[0].ValueMgDl != 60 {
+		t.Errorf("expected value 60, got %d", fired[0].ValueMgDl)
+	}
+
+	sources.latest = reading(58, domain.TrendArrowStable, 0)
+	if fired := e.check(context.Background()); len(fired) != 0 {
+		t.Errorf("expected no alert while still low, got %v", rulesOf(fired))
+	}
+
+	// Recovery then a new low fires again
+	sources.latest = reading(90, domain.TrendArrowStable, 0)
+	e.check(context.Background())
+	sources.latest = reading(65, domain.TrendArrowStable, 0)
+	if fired := e.check(context.Background()); len(fired) != 1 || fired[0].Rule != RuleLow {
+		t.Errorf("expected a new low alert after recovery, got %v", rulesOf(fired))
+	}
+}
+
+func TestEvaluator_GlucoseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		reading *domain.GlucoseMeasurement
+		mode    *domain.ModeStatus
+		want    []Rule
+	}{
+		{"in range", reading(120, domain.TrendArrowStable, 0), nil, nil},
+		{"high", reading(260, domain.TrendArrowRising, 0), nil, []Rule{RuleHigh}},
+		{"low and falling", reading(65, domain.TrendArrowFallingRapidly, 0), nil, []Rule{RuleLow, RuleFalling}},
+		{"falling slowly in normal mode", reading(120, domain.TrendArrowFalling, 0), nil, nil},
+		{
+			"exercise mode thresholds",
+			reading(95, domain.TrendArrowFalling, 0),
+			&domain.ModeStatus{Mode: domain.ModeExercise, Thresholds: domain.ExerciseAlertThresholds()},
+			[]Rule{RuleLow, RuleFalling},
+		},
+		{"stale", reading(120, domain.TrendArrowStable, 20*time.Minute), nil, []Rule{RuleStale}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newTestEvaluator(DefaultConfig(), &fakeSources{latest: tt.reading, mode: tt.mode})
+
+			got := rulesOf(e.check(context.Background()))
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluator_DisabledRules(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Rules = []Rule{RuleHigh}
+	e, _ := newTestEvaluator(cfg, &fakeSources{latest: reading(50, domain.TrendArrowFallingRapidly, time.Hour)})
+
+	if fired := e.check(context.Background()); len(fired) != 0 {
+		t.Errorf("expected no alert for disabled rules, got %v", rulesOf(fired))
+	}
+}
+
+func TestEvaluator_SensorExpiring(t *testing.T) {
+	sources := &fakeSources{sensor: &domain.SensorConfig{SerialNumber: "ABC123", ExpiresAt: testNow.Add(100 * time.Hour)}}
+	e, _ := newTestEvaluator(DefaultConfig(), sources)
+
+	if fired := e.check(context.Background()); len(fired) != 0 {
+		t.Fatalf("expected no alert 100h before expiry, got %v", rulesOf(fired))
+	}
+
+	// Each lead time fires once
+	for _, remaining := range []time.Duration{70 * time.Hour, 23 * time.Hour, 90 * time.Minute} {
+		sources.sensor.ExpiresAt = testNow.Add(remaining)
+		fired := e.check(context.Background())
+		if len(fired) != 1 || fired[0].Rule != RuleSensorExpiring {
+			t.Fatalf("expected a sensor alert %s before expiry, got %v", remaining, rulesOf(fired))
+		}
+		if fired := e.check(context.Background()); len(fired) != 0 {
+			t.Fatalf("expected the %s reminder sent once, got %v", remaining, rulesOf(fired))
+		}
+	}
+
+	// Expired sensors are reported by the daemon
+	sources.sensor.ExpiresAt = testNow.Add(-time.Hour)
+	if fired := e.check(context.Background()); len(fired) != 0 {
+		t.Errorf("expected no alert for an expired sensor, got %v", rulesOf(fired))
+	}
+}
+
+func TestEvaluator_SensorRemindersReset(t *testing.T) {
+	// Lead times already passed at startup are sent as one reminder
+	sources := &fakeSources{sensor: &domain.SensorConfig{SerialNumber: "ABC123", ExpiresAt: testNow.Add(5 * time.Hour)}}
+	e, _ := newTestEvaluator(DefaultConfig(), sources)
+
+	if fired := e.check(context.Background()); len(fired) != 1 {
+		t.Fatalf("expected one sensor alert, got %v", rulesOf(fired))
+	}
+	sources.sensor.ExpiresAt = testNow.Add(3 * time.Hour)
+	if fired := e.check(context.Background()); len(fired) != 0 {
+		t.Fatalf("expected no new alert before the next lead time, got %v", rulesOf(fired))
+	}
+
+	// A new sensor starts over
+	sources.sensor = &domain.SensorConfig{SerialNumber: "DEF456", ExpiresAt: testNow.Add(60 * time.Hour)}
+	if fired := e.check(context.Background()); len(fired) != 1 {
+		t.Errorf("expected a reminder for the new sensor, got %v", rulesOf(fired))
+	}
+}
+
+func TestEventNotifier(t *testing.T) {
+	broker := events.NewBroker(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ch := broker.Subscribe("test", []events.EventType{events.EventTypeAlert})
+	defer broker.Unsubscribe("test")
+
+	alert := Alert{Rule: RuleHigh, Title: "High glucose"}
+	if err := NewEventNotifier(broker).Send(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case event := <-ch:
+		if event.Type != events.EventTypeAlert || event.Data.(Alert).Rule != RuleHigh {
+			t.Errorf("unexpected event %+v", event)
+		}
+	default:
+		t.Fatal("expected an alert event")
+	}
+}
+
+func TestEvaluator_SendContinuesAfterFailure(t *testing.T) {
+	failing := &recordingNotifier{err: errors.New("unreachable")}
+	working := &recordingNotifier{}
+	e := NewEvaluator(DefaultConfig(), &fakeSources{}, &fakeSources{}, nil, []Notifier{failing, working}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	e.send(context.Background(), e.notifiers, Alert{Rule: RuleHigh, Title: "High glucose"})
+
+	if len(failing.sent) != 1 || len(working.sent) != 1 {
+		t.Errorf("expected the alert sent to both notifiers, got %d and %d", len(failing.sent), len(working.sent))
+	}
+}
+
+// fakeHistory serves the readings and low alert of an escalation.
+type fakeHistory struct {
+	readings []*domain.GlucoseMeasurement
+	alert    *domain.Alert
+	err      error
+}
+
+func (f *fakeHistory) GetMeasurementsByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error) {
+	return f.readings, nil
+}
+
+func (f *fakeHistory) GetAlertsWithFilters(ctx context.Context, filters repository.AlertFilters, limit, offset int) ([]*domain.Alert, int64, error) {
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+	if f.alert == nil || f.alert.FiredAt.Before(*filters.StartTime) || *filters.Type != f.alert.Type {
+		return nil, 0, nil
+	}
+	return []*domain.Alert{f.alert}, 1, nil
+}
+
+func TestEvaluator_Escalation(t *testing.T) {
+	low := reading(52, 3, 0)
+	history := &fakeHistory{
+		readings: []*domain.GlucoseMeasurement{reading(48, 2, 0), reading(50, 3, 5*time.Minute), reading(52, 3, 10*time.Minute)},
+		alert:    &domain.Alert{Type: domain.AlertTypeLow, FiredAt: low.Timestamp},
+	}
+	sources := &fakeSources{latest: low}
+	e, notifier := newTestEvaluator(DefaultConfig(), sources)
+	contact := &recordingNotifier{}
+	e.SetEscalation(Escalation{After: 15 * time.Minute, Notifiers: []Notifier{contact}, Readings: history, History: history})
+
+	e.evaluate(context.Background())
+	if len(notifier.sent) != 1 || len(contact.sent) != 0 {
+		t.Fatalf("expected the low alert without escalation, got %d and %d", len(notifier.sent), len(contact.sent))
+	}
+
+	// Still low and unacknowledged after the escalation delay
+	e.now = func() time.Time { return testNow.Add(15 * time.Minute) }
+	sources.latest = reading(48, 2, -15*time.Minute)
+	e.evaluate(context.Background())
+	if len(contact.sent) != 1 || len(notifier.sent) != 1 {
+		t.Fatalf("expected the escalation sent to the contact only, got %d and %d", len(contact.sent), len(notifier.sent))
+	}
+	alert := contact.sent[0]
+	if alert.Rule != RuleLow || alert.ValueMgDl != 48 || !alert.Urgent() {
+		t.Errorf("unexpected escalation %+v", alert)
+	}
+	if lines := strings.Split(alert.Message, "\n"); len(lines) != 5 || !strings.Contains(lines[2], "48 mg/dL") {
+		t.Errorf("expected the last readings newest first, got %q", alert.Message)
+	}
+
+	// Once per episode
+	e.evaluate(context.Background())
+	if len(contact.sent) != 1 {
+		t.Errorf("expected a single escalation, got %d", len(contact.sent))
+	}
+}
+
+func TestEvaluator_EscalationAcknowledged(t *testing.T) {
+	low := reading(52, 3, 0)
+	acknowledgedAt := testNow.Add(5 * time.Minute)
+	history := &fakeHistory{alert: &domain.Alert{Type: domain.AlertTypeLow, FiredAt: low.Timestamp, AcknowledgedAt: &acknowledgedAt}}
+	sources := &fakeSources{latest: low}
+	e, _ := newTestEvaluator(DefaultConfig(), sources)
+	contact := &recordingNotifier{}
+	e.SetEscalation(Escalation{After: 15 * time.Minute, Notifiers: []Notifier{contact}, Readings: history, History: history})
+
+	e.evaluate(context.Background())
+	e.now = func() time.Time { return testNow.Add(20 * time.Minute) }
+	e.evaluate(context.Background())
+	if len(contact.sent) != 0 {
+		t.Errorf("expected no escalation of an acknowledged alert, got %+v", contact.sent)
+	}
+}
+
+func TestEvaluator_EscalationRetriesHistoryErrors(t *testing.T) {
+	history := &fakeHistory{err: errors.New("database locked")}
+	sources := &fakeSources{latest: reading(52, 3, 0)}
+	e, _ := newTestEvaluator(DefaultConfig(), sources)
+	contact := &recordingNotifier{}
+	e.SetEscalation(Escalation{After: 15 * time.Minute, Notifiers: []Notifier{contact}, Readings: history, History: history})
+
+	e.evaluate(context.Background())
+	e.now = func() time.Time { return testNow.Add(20 * time.Minute) }
+	e.evaluate(context.Background())
+	if len(contact.sent) != 0 {
+		t.Fatal("expected no escalation while the alert history cannot be read")
+	}
+
+	// The alert was not recorded: escalate rather than assume it was acknowledged
+	history.err = nil
+	e.evaluate(context.Background())
+	if len(contact.sent) != 1 {
+		t.Errorf("expected the escalation once the history is read, got %d", len(contact.sent))
+	}
+}
+
+func TestEvaluator_EscalationEndsWithLow(t *testing.T) {
+	history := &fakeHistory{}
+	sources := &fakeSources{latest: reading(52, 3, 0)}
+	e, _ := newTestEvaluator(DefaultConfig(), sources)
+	contact := &recordingNotifier{}
+	e.SetEscalation(Escalation{After: 15 * time.Minute, Notifiers: []Notifier{contact}, Readings: history, History: history})
+
+	e.evaluate(context.Background())
+	e.now = func() time.Time { return testNow.Add(20 * time.Minute) }
+	sources.latest = reading(95, 3, -20*time.Minute)
+	e.evaluate(context.Background())
+	if len(contact.sent) != 0 {
+		t.Errorf("expected no escalation once glucose recovered, got %+v", contact.sent)
+	}
+}
+
+func TestFormatHours(t *testing.T) {
+	tests := map[time.Duration]string{
+		5 * time.Hour:  "5h",
+		27 * time.Hour: "1d 3h",
+	}
+	for d, want := range tests {
+		if got := formatHours(d); got != want {
+			t.Errorf("formatHours(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
+)
+
+// maxErrorBody is how much of a failed response body is included in errors.
+const maxErrorBody = 256
+
+// Signer signs pushed payloads with the current signing key
+// (service.SigningService).
+type Signer interface {
+	// Sign returns the signature of a payload, or "" when no key exists
+	Sign(ctx context.Context, eventType string, payload []byte) (string, error)
+}
+
+// WebhookNotifier posts alerts as JSON to a URL, signed in the
+// X-Glcmd-Signature header while a signing key exists.
+type WebhookNotifier struct {
+	url    string
+	signer Signer
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a WebhookNotifier posting to url. A nil signer
+// sends alerts unsigned.
+func NewWebhookNotifier(url string, signer Signer) *WebhookNotifier {
+	return &WebhookNotifier{url: url, signer: signer, client: &http.Client{Timeout: sendTimeout}}
+}
+
+// Name returns "webhook".
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Send posts the alert as its JSON document, signed as an alert event.
+func (n *WebhookNotifier) Send(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if n.signer != nil {
+		signature, err := n.signer.Sign(ctx, string(events.EventTypeAlert), body)
+		if err != nil {
+			return fmt.Errorf("failed to sign alert: %w", err)
+		}
+		if signature != "" {
+			req.Header.Set(glclient.SignatureHeader, signature)
+		}
+	}
+
+	return do(n.client, req)
+}
+
+// SMTPConfig holds the mail server and addresses of the EmailNotifier.
+// Username empty disables authentication.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// EmailNotifier sends alerts by email through an SMTP server.
+// The connection is upgraded with STARTTLS when the server supports it, or
+// uses implicit TLS on port 465.
+type EmailNotifier struct {
+	cfg SMTPConfig
+}
+
+// NewEmailNotifier creates an EmailNotifier for cfg.
+func NewEmailNotifier(cfg SMTPConfig) *EmailNotifier {
+	return &EmailNotifier{cfg: cfg}
+}
+
+// Name returns "email".
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+// Send mails the alert to every recipient.
+func (n *EmailNotifier) Send(ctx context.Context, alert Alert) error {
+	addr := net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: n.cfg.Host}
+
+	var conn net.Conn
+	var err error
+	if n.cfg.Port == 465 {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.cfg.Host)
+	if err != nil {
+		return fmt.Errorf("smtp handshake failed: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("starttls failed: %w", err)
+		}
+	}
+	if n.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(n.cfg.From); err != nil {
+		return fmt.Errorf("smtp sender rejected: %w", err)
+	}
+	for _, to := range n.cfg.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("smtp recipient %s rejected: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data failed: %w", err)
+	}
+	if _, err := w.Write(n.message(alert)); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp message rejected: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// message returns the RFC 5322 message of an alert.
+func (n *EmailNotifier) message(alert Alert) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: [glcmd] %s\r\n", alert.Title)
+	fmt.Fprintf(&b, "Date: %s\r\n", alert.At.Format(time.RFC1123Z))
+	if alert.Urgent() {
+		b.WriteString("X-Priority: 1\r\n")
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(alert.Message)
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// telegramAPIURL is the Telegram Bot API base URL.
+const telegramAPIURL = "https://api.telegram.org"
+
+// TelegramNotifier sends alerts to a Telegram chat through a bot.
+type TelegramNotifier struct {
+	apiURL string
+	token  string
+	chatID string
+	client *http.Client
+}
+
+// NewTelegramNotifier creates a TelegramNotifier sending with the bot token
+// to chatID (a user, group or channel ID).
+func NewTelegramNotifier(token, chatID string) *TelegramNotifier {
+	return &TelegramNotifier{
+		apiURL: telegramAPIURL,
+		token:  token,
+		chatID: chatID,
+		client: &http.Client{Timeout: sendTimeout},
+	}
+}
+
+// Name returns "telegram".
+func (n *TelegramNotifier) Name() string {
+	return "telegram"
+}
+
+// Send posts the alert to the chat with the sendMessage method.
+func (n *TelegramNotifier) Send(ctx context.Context, alert Alert) error {
+	return sendTelegramMessage(ctx, n.client, n.apiURL, n.token, n.chatID, alert.Title+"\n"+alert.Message)
+}
+
+// sendTelegramMessage posts text to a chat with the sendMessage method.
+func sendTelegramMessage(ctx context.Context, client *http.Client, apiURL, token, chatID, text string) error {
+	body, err := json.Marshal(map[string]string{
+		"chat_id": chatID,
+		"text":    text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL+"/bot"+token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		// The URL embeds the bot token
+		return errors.New("failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return do(client, req)
+}
+
+// pushoverAPIURL is the Pushover messages API.
+const pushoverAPIURL = "https://api.pushover.net/1/messages.json"
+
+// PushoverNotifier sends alerts to Pushover devices.
+// Urgent alerts are sent with high priority, bypassing quiet hours.
+type PushoverNotifier struct {
+	apiURL string
+	token  string
+	user   string
+	client *http.Client
+}
+
+// NewPushoverNotifier creates a PushoverNotifier sending with the application
+// token to the user (or group) key.
+func NewPushoverNotifier(token, user string) *PushoverNotifier {
+	return &PushoverNotifier{
+		apiURL: pushoverAPIURL,
+		token:  token,
+		user:   user,
+		client: &http.Client{Timeout: sendTimeout},
+	}
+}
+
+// Name returns "pushover".
+func (n *PushoverNotifier) Name() string {
+	return "pushover"
+}
+
+// Send posts the alert to the Pushover messages API.
+func (n *PushoverNotifier) Send(ctx context.Context, alert Alert) error {
+	form := url.Values{
+		"token":     {n.token},
+		"user":      {n.user},
+		"title":     {alert.Title},
+		"message":   {alert.Message},
+		"timestamp": {strconv.FormatInt(alert.At.Unix(), 10)},
+	}
+	if alert.Urgent() {
+		form.Set("priority", "1")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.apiURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return do(n.client, req)
+}
+
+// SoundBeep is the sound command beeping over the PC speaker instead of
+// running a command.
+const SoundBeep = "beep"
+
+const (
+	// beepDevice is the console receiving the bell characters of SoundBeep.
+	beepDevice = "/dev/console"
+	// beepCount and beepInterval make SoundBeep a repeated beep.
+	beepCount    = 5
+	beepInterval = 300 * time.Millisecond
+	// maxSoundOutput is how much of a failed sound command's output is included in errors.
+	maxSoundOutput = 256
+)
+
+// SoundNotifier plays an alarm on the server for urgent alerts, for a bedside
+// server with a speaker: it runs a command (e.g. "aplay -q /home/pi/alarm.wav")
+// or, with SoundBeep, rings the console bell. Other alerts are ignored.
+type SoundNotifier struct {
+	command []string
+	device  string
+}
+
+// NewSoundNotifier creates a SoundNotifier running command, a program and its
+// arguments separated by spaces, or beeping if command is SoundBeep.
+func NewSoundNotifier(command string) *SoundNotifier {
+	n := &SoundNotifier{device: beepDevice}
+	if command != SoundBeep {
+		n.command = strings.Fields(command)
+	}
+	return n
+}
+
+// Name returns "sound".
+func (n *SoundNotifier) Name() string {
+	return "sound"
+}
+
+// Send plays the alarm if the alert is urgent.
+func (n *SoundNotifier) Send(ctx context.Context, alert Alert) error {
+	if !alert.Urgent() {
+		return nil
+	}
+	if n.command == nil {
+		return n.beep(ctx)
+	}
+
+	cmd := exec.CommandContext(ctx, n.command[0], n.command[1:]...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		text := strings.TrimSpace(string(output))
+		if len(text) > maxSoundOutput {
+			text = text[:maxSoundOutput]
+		}
+		if text != "" {
+			return fmt.Errorf("sound command failed: %w: %s", err, text)
+		}
+		return fmt.Errorf("sound command failed: %w", err)
+	}
+	return nil
+}
+
+// beep writes bell characters to the console, which the kernel plays on the
+// PC speaker (pcspkr module).
+func (n *SoundNotifier) beep(ctx context.Context) error {
+	f, err := os.OpenFile(n.device, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open the console: %w", err)
+	}
+	defer f.Close()
+
+	for i := range beepCount {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(beepInterval):
+			}
+		}
+		if _, err := f.WriteString("\a"); err != nil {
+			return fmt.Errorf("failed to beep: %w", err)
+		}
+	}
+	return nil
+}
+
+// do sends a request and returns an error for non-2xx responses, with the
+// start of the response body. Transport errors do not include the URL, which
+// may embed a secret.
+func do(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// EventNotifier publishes alerts as alert events on the event stream (SSE).
+type EventNotifier struct {
+	broker *events.Broker
+}
+
+// NewEventNotifier creates an EventNotifier publishing to broker.
+func NewEventNotifier(broker *events.Broker) *EventNotifier {
+	return &EventNotifier{broker: broker}
+}
+
+// Name returns "events".
+func (n *EventNotifier) Name() string {
+	return "events"
+}
+
+// Send publishes the alert for the patient of ctx. Slow subscribers miss it,
+// as any other event.
+func (n *EventNotifier) Send(ctx context.Context, alert Alert) error {
+	n.broker.Publish(events.Event{
+		Type:      events.EventTypeAlert,
+		Data:      alert,
+		PatientID: repository.PatientFromContext(ctx),
+	})
+	return nil
+}
@@ -0,0 +1,224 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
+)
+
+var testAlert = Alert{
+	Rule:      RuleLow,
+	Title:     "Low glucose",
+	Message:   "Glucose is 62 mg/dL (3.4 mmol/L), below 70 mg/dL",
+	ValueMgDl: 62,
+	At:        time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC),
+}
+
+// capture records the last request received by a test server.
+type capture struct {
+	path        string
+	contentType string
+	signature   string
+	body        []byte
+}
+
+func newCaptureServer(t *testing.T, status int) (*httptest.Server, *capture) {
+	t.Helper()
+	c := &capture{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.path = r.URL.Path
+		c.contentType = r.Header.Get("Content-Type")
+		c.signature = r.Header.Get(glclient.SignatureHeader)
+		c.body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		w.Write([]byte(`{"ok":false,"description":"chat not found"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, c
+}
+
+func TestWebhookNotifier_Send(t *testing.T) {
+	server, c := newCaptureServer(t, http.StatusNoContent)
+
+	if err := NewWebhookNotifier(server.URL+"/hook", nil).Send(context.Background(), testAlert); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	var got Alert
+	if err := json.Unmarshal(c.body, &got); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if got != testAlert {
+		t.Errorf("expected %+v, got %+v", testAlert, got)
+	}
+	if c.contentType != "application/json" {
+		t.Errorf("expected JSON content type, got %q", c.contentType)
+	}
+	if c.signature != "" {
+		t.Errorf("expected no signature without a signer, got %q", c.signature)
+	}
+}
+
+// keySigner signs with a fixed key, or with none when secret is empty.
+type keySigner struct {
+	secret string
+}
+
+func (s keySigner) Sign(ctx context.Context, eventType string, payload []byte) (string, error) {
+	if s.secret == "" {
+		return "", nil
+	}
+	return glclient.Sign(s.secret, "k_test", time.Now(), eventType, payload), nil
+}
+
+func TestWebhookNotifier_Signed(t *testing.T) {
+	server, c := newCaptureServer(t, http.StatusNoContent)
+
+	if err := NewWebhookNotifier(server.URL, keySigner{secret: "s3cret"}).Send(context.Background(), testAlert); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	secrets := map[string]string{"k_test": "s3cret"}
+	if err := glclient.Verify(c.signature, secrets, "alert", c.body, time.Minute); err != nil {
+		t.Errorf("expected a valid signature of the body, got %q: %v", c.signature, err)
+	}
+
+	// No signing key: sent unsigned
+	if err := NewWebhookNotifier(server.URL, keySigner{}).Send(context.Background(), testAlert); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if c.signature != "" {
+		t.Errorf("expected no signature without a key, got %q", c.signature)
+	}
+}
+
+func TestTelegramNotifier_Send(t *testing.T) {
+	server, c := newCaptureServer(t, http.StatusOK)
+	n := NewTelegramNotifier("123:secret", "42")
+	n.apiURL = server.URL
+
+	if err := n.Send(context.Background(), testAlert); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if c.path != "/bot123:secret/sendMessage" {
+		t.Errorf("unexpected path %q", c.path)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(c.body, &got); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if got["chat_id"] != "42" || !strings.HasPrefix(got["text"], "Low glucose\n") {
+		t.Errorf("unexpected message %v", got)
+	}
+}
+
+func TestTelegramNotifier_ErrorHidesToken(t *testing.T) {
+	n := NewTelegramNotifier("123:secret", "42")
+	n.apiURL = "http://127.0.0.1:1"
+
+	err := n.Send(context.Background(), testAlert)
+	if err == nil {
+		t.Fatal("expected an error for an unreachable API")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error leaks the bot token: %v", err)
+	}
+}
+
+func TestPushoverNotifier_Send(t *testing.T) {
+	server, c := newCaptureServer(t, http.StatusOK)
+	n := NewPushoverNotifier("apptoken", "userkey")
+	n.apiURL = server.URL
+
+	if err := n.Send(context.Background(), testAlert); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	form, err := url.ParseQuery(string(c.body))
+	if err != nil {
+		t.Fatalf("invalid form body: %v", err)
+	}
+	if form.Get("token") != "apptoken" || form.Get("user") != "userkey" {
+		t.Errorf("unexpected credentials in %v", form)
+	}
+	if form.Get("priority") != "1" {
+		t.Errorf("expected high priority for a low alert, got %q", form.Get("priority"))
+	}
+}
+
+func TestNotifier_ErrorStatus(t *testing.T) {
+	server, _ := newCaptureServer(t, http.StatusBadRequest)
+
+	err := NewWebhookNotifier(server.URL, nil).Send(context.Background(), testAlert)
+	if err == nil || !strings.Contains(err.Error(), "status 400") || !strings.Contains(err.Error(), "chat not found") {
+		t.Errorf("expected status 400 error with the response body, got %v", err)
+	}
+}
+
+func TestEmailNotifier_Message(t *testing.T) {
+	n := NewEmailNotifier(SMTPConfig{From: "glcmd@example.com", To: []string{"me@example.com", "carer@example.com"}})
+
+	msg := string(n.message(testAlert))
+
+	for _, want := range []string{
+		"To: me@example.com, carer@example.com\r\n",
+		"Subject: [glcmd] Low glucose\r\n",
+		"X-Priority: 1\r\n",
+		"\r\n\r\n" + testAlert.Message,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestSoundNotifier_Command(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "played")
+	n := NewSoundNotifier("touch " + out)
+
+	if err := n.Send(context.Background(), Alert{Rule: RuleHigh}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(out); err == nil {
+		t.Fatal("expected no sound for a non-urgent alert")
+	}
+
+	if err := n.Send(context.Background(), testAlert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Errorf("expected the sound command run for an urgent alert: %v", err)
+	}
+
+	n = NewSoundNotifier("false")
+	if err := n.Send(context.Background(), testAlert); err == nil || !strings.Contains(err.Error(), "sound command failed") {
+		t.Errorf("expected the command error, got %v", err)
+	}
+}
+
+func TestSoundNotifier_Beep(t *testing.T) {
+	device := filepath.Join(t.TempDir(), "console")
+	if err := os.WriteFile(device, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	n := NewSoundNotifier(SoundBeep)
+	n.device = device
+
+	if err := n.Send(context.Background(), testAlert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(device); string(data) != strings.Repeat("\a", beepCount) {
+		t.Errorf("expected %d bell characters, got %q", beepCount, data)
+	}
+}
@@ -0,0 +1,259 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+const (
+	// telegramPollTimeout is how long getUpdates waits for a new message.
+	telegramPollTimeout = 30 * time.Second
+
+	// telegramRetryDelay is the pause after a failed getUpdates.
+	telegramRetryDelay = 10 * time.Second
+)
+
+// telegramHelp is the reply to /start, /help and unknown commands.
+const telegramHelp = "Commands:\n/glucose - latest glucose reading\n/sensor - current sensor"
+
+// PreferencesSource reads the display preferences.
+type PreferencesSource interface {
+	GetDisplayPreferences(ctx context.Context) (*domain.DisplayPreferences, error)
+}
+
+// TelegramBot answers the /glucose and /sensor commands of a Telegram chat
+// with the stored readings. Messages from other chats are ignored, so the bot
+// only answers the chat alerts are sent to.
+type TelegramBot struct {
+	apiURL       string
+	token        string
+	chatID       string
+	measurements MeasurementSource
+	sensors      SensorSource
+	prefs        PreferencesSource
+	client       *http.Client
+	logger       *slog.Logger
+	now          func() time.Time
+
+	// offset is the ID of the next update to receive. Only used by Run.
+	offset int64
+}
+
+// NewTelegramBot creates a TelegramBot receiving commands with the bot token
+// from chatID (a numeric user or group ID).
+func NewTelegramBot(token, chatID string, measurements MeasurementSource, sensors SensorSource, prefs PreferencesSource, logger *slog.Logger) *TelegramBot {
+	return &TelegramBot{
+		apiURL:       telegramAPIURL,
+		token:        token,
+		chatID:       chatID,
+		measurements: measurements,
+		sensors:      sensors,
+		prefs:        prefs,
+		client:       &http.Client{Timeout: telegramPollTimeout + sendTimeout},
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// Run receives and answers commands until ctx is canceled.
+func (b *TelegramBot) Run(ctx context.Context) {
+	for {
+		updates, err := b.getUpdates(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			b.logger.Warn("failed to receive telegram commands", "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(telegramRetryDelay):
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			b.offset = u.UpdateID + 1
+			if u.Message == nil {
+				continue
+			}
+			b.handle(ctx, u.Message)
+		}
+	}
+}
+
+// telegramUpdate is an update of the getUpdates method.
+type telegramUpdate struct {
+	UpdateID int64            `json:"update_id"`
+	Message  *telegramMessage `json:"message"`
+}
+
+// telegramMessage is the part of a Telegram message the bot reads.
+type telegramMessage struct {
+	Chat struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text string `json:"text"`
+}
+
+// getUpdates long-polls the messages received since the last call.
+func (b *TelegramBot) getUpdates(ctx context.Context) ([]telegramUpdate, error) {
+	query := url.Values{
+		"offset":          {strconv.FormatInt(b.offset, 10)},
+		"timeout":         {strconv.Itoa(int(telegramPollTimeout.Seconds()))},
+		"allowed_updates": {`["message"]`},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.apiURL+"/bot"+b.token+"/getUpdates?"+query.Encode(), nil)
+	if err != nil {
+		// The URL embeds the bot token
+		return nil, errors.New("failed to create request")
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool             `json:"ok"`
+		Description string           `json:"description"`
+		Result      []telegramUpdate `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("status %d: invalid response: %w", resp.StatusCode, err)
+	}
+	if !result.OK {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, result.Description)
+	}
+	return result.Result, nil
+}
+
+// handle answers a message from the configured chat.
+func (b *TelegramBot) handle(ctx context.Context, msg *telegramMessage) {
+	if strconv.FormatInt(msg.Chat.ID, 10) != b.chatID {
+		b.logger.Debug("ignoring telegram message from another chat", "chatId", msg.Chat.ID)
+		return
+	}
+	if !strings.HasPrefix(msg.Text, "/") {
+		return
+	}
+
+	reply := b.reply(ctx, msg.Text)
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	if err := sendTelegramMessage(sendCtx, b.client, b.apiURL, b.token, b.chatID, reply); err != nil {
+		b.logger.Warn("failed to answer telegram command", "error", err)
+	}
+}
+
+// reply returns the answer to a command.
+// In groups, commands may be addressed to the bot (e.g. "/glucose@glcmd_bot").
+func (b *TelegramBot) reply(ctx context.Context, text string) string {
+	command, _, _ := strings.Cut(strings.Fields(text)[0], "@")
+
+	switch command {
+	case "/glucose":
+		return b.glucose(ctx)
+	case "/sensor":
+		return b.sensor(ctx)
+	default:
+		return telegramHelp
+	}
+}
+
+// glucose describes the latest reading with the display preferences.
+func (b *TelegramBot) glucose(ctx context.Context) string {
+	m, err := b.measurements.GetLatestMeasurement(ctx)
+	if err != nil {
+		return "No glucose reading available"
+	}
+
+	prefs := b.preferences(ctx)
+	var sb strings.Builder
+	sb.WriteString(prefs.FormatValue(m))
+	if m.TrendArrow != nil {
+		sb.WriteString(" " + trendText(*m.TrendArrow, prefs.Emoji))
+	}
+	age := b.now().Sub(m.Timestamp).Round(time.Minute)
+	fmt.Fprintf(&sb, "\n%s (%s ago)", m.Timestamp.In(prefs.Location()).Format("15:04"), formatMinutes(age))
+	if m.IsLow {
+		sb.WriteString("\nLOW")
+	} else if m.IsHigh {
+		sb.WriteString("\nHIGH")
+	}
+	return sb.String()
+}
+
+// sensor describes the current sensor.
+func (b *TelegramBot) sensor(ctx context.Context) string {
+	s, err := b.sensors.GetCurrentSensor(ctx)
+	if err != nil {
+		return "No active sensor"
+	}
+
+	remaining := s.ExpiresAt.Sub(b.now())
+	if remaining <= 0 {
+		return fmt.Sprintf("Sensor %s (%s) expired", s.SerialNumber, domain.SensorBrand(s.SensorType))
+	}
+	expires := s.ExpiresAt.In(b.preferences(ctx).Location()).Format("Mon 02 Jan 15:04")
+	return fmt.Sprintf("Sensor %s (%s)\nExpires in %s (%s)", s.SerialNumber, domain.SensorBrand(s.SensorType), formatHours(remaining), expires)
+}
+
+// preferences returns the display preferences, or the defaults when they
+// cannot be read.
+func (b *TelegramBot) preferences(ctx context.Context) *domain.DisplayPreferences {
+	if b.prefs != nil {
+		if prefs, err := b.prefs.GetDisplayPreferences(ctx); err == nil {
+			return prefs
+		}
+	}
+	return domain.DefaultDisplayPreferences()
+}
+
+// trendText describes a trend arrow, with or without its emoji.
+func trendText(arrow int, emoji bool) string {
+	var symbol, text string
+	switch arrow {
+	case domain.TrendArrowFallingRapidly:
+		symbol, text = "⬇️⬇️", "falling rapidly"
+	case domain.TrendArrowFalling:
+		symbol, text = "⬇️", "falling"
+	case domain.TrendArrowStable:
+		symbol, text = "➡️", "stable"
+	case domain.TrendArrowRising:
+		symbol, text = "⬆️", "rising"
+	case domain.TrendArrowRisingRapidly:
+		symbol, text = "⬆️⬆️", "rising rapidly"
+	default:
+		return ""
+	}
+	if emoji {
+		return symbol + " " + text
+	}
+	return text
+}
+
+// formatMinutes formats a duration in minutes, e.g. "3 min" or "2h 05".
+func formatMinutes(d time.Duration) string {
+	minutes := int(d.Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	return fmt.Sprintf("%dh %02d", minutes/60, minutes%60)
+}
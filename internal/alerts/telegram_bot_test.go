@@ -0,0 +1,116 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// fakePreferences serves fixed display preferences.
+type fakePreferences struct {
+	prefs *domain.DisplayPreferences
+}
+
+func (f fakePreferences) GetDisplayPreferences(ctx context.Context) (*domain.DisplayPreferences, error) {
+	return f.prefs, nil
+}
+
+func newTestBot(sources *fakeSources, prefs *domain.DisplayPreferences) *TelegramBot {
+	b := NewTelegramBot("123:secret", "42", sources, sources, fakePreferences{prefs}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	b.now = func() time.Time { return testNow }
+	return b
+}
+
+func TestTelegramBot_Reply(t *testing.T) {
+	sources := &fakeSources{
+		latest: reading(97, domain.TrendArrowFalling, 3*time.Minute),
+		sensor: &domain.SensorConfig{SerialNumber: "ABC123", SensorType: 4, ExpiresAt: testNow.Add(27 * time.Hour)},
+	}
+	prefs := domain.DefaultDisplayPreferences()
+	prefs.Unit = domain.DisplayUnitMgDl
+	prefs.Emoji = false
+	prefs.Timezone = "Europe/Zurich"
+	b := newTestBot(sources, prefs)
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"/glucose", "97 mg/dL falling\n12:57 (3 min ago)"},
+		{"/glucose@glcmd_bot", "97 mg/dL falling\n12:57 (3 min ago)"},
+		{"/sensor", "Sensor ABC123 (FreeStyle Libre 3 Plus)\nExpires in 1d 3h (Wed 11 Mar 16:00)"},
+		{"/start", telegramHelp},
+	}
+	for _, tt := range tests {
+		if got := b.reply(context.Background(), tt.text); got != tt.want {
+			t.Errorf("reply(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	sources.latest, sources.sensor = nil, nil
+	if got := b.reply(context.Background(), "/glucose"); got != "No glucose reading available" {
+		t.Errorf("unexpected reply without reading: %q", got)
+	}
+	if got := b.reply(context.Background(), "/sensor"); got != "No active sensor" {
+		t.Errorf("unexpected reply without sensor: %q", got)
+	}
+}
+
+func TestTelegramBot_Run(t *testing.T) {
+	var mu sync.Mutex
+	var replies []map[string]string
+	var offsets []string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/bot123:secret/getUpdates":
+			offsets = append(offsets, r.URL.Query().Get("offset"))
+			if len(offsets) > 1 {
+				// Stop after the first batch
+				cancel()
+				w.Write([]byte(`{"ok":true,"result":[]}`))
+				return
+			}
+			w.Write([]byte(`{"ok":true,"result":[
+				{"update_id":7,"message":{"chat":{"id":99},"text":"/glucose"}},
+				{"update_id":8,"message":{"chat":{"id":42},"text":"hello"}},
+				{"update_id":9,"message":{"chat":{"id":42},"text":"/glucose"}}
+			]}`))
+		case "/bot123:secret/sendMessage":
+			var msg map[string]string
+			json.NewDecoder(r.Body).Decode(&msg)
+			replies = append(replies, msg)
+			w.Write([]byte(`{"ok":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	b := newTestBot(&fakeSources{latest: reading(97, domain.TrendArrowStable, 0)}, domain.DefaultDisplayPreferences())
+	b.apiURL = server.URL
+	b.Run(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(replies) != 1 || replies[0]["chat_id"] != "42" || !strings.HasPrefix(replies[0]["text"], "5.4 mmol/L") {
+		t.Errorf("expected one reply to the configured chat, got %v", replies)
+	}
+	if len(offsets) < 2 || offsets[0] != "0" || offsets[1] != "10" {
+		t.Errorf("expected the offset to follow the updates, got %v", offsets)
+	}
+}
@@ -0,0 +1,321 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/R4yL-dev/glcmd/internal/backfill"
+	"github.com/R4yL-dev/glcmd/internal/daemon"
+)
+
+// handleListTokens handles GET /v1/admin/tokens
+// Lists all issued tokens with their last use, including revoked and expired ones.
+func (s *Server) handleListTokens(w http.ResponseWriter, r *http.Request) {
+	if s.tokenService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Token management not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	tokens, err := s.tokenService.ListTokens(ctx)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := TokenListResponse{
+		Data: tokens,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleCreateToken handles POST /v1/admin/tokens
+// The plaintext token is only included in this response.
+func (s *Server) handleCreateToken(w http.ResponseWriter, r *http.Request) {
+	if s.tokenService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Token management not available")
+		return
+	}
+
+	req, expiresAt, err := parseCreateTokenRequest(w, r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	token, plaintext, err := s.tokenService.CreateToken(ctx, req.Name, req.Scope, expiresAt)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := CreatedTokenResponse{
+		Data: &CreatedToken{APIToken: token, Token: plaintext},
+	}
+
+	if err := writeJSONResponse(w, http.StatusCreated, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleRevokeToken handles DELETE /v1/admin/tokens/{id}
+// Revocation takes effect immediately, no restart required.
+func (s *Server) handleRevokeToken(w http.ResponseWriter, r *http.Request) {
+	if s.tokenService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Token management not available")
+		return
+	}
+
+	id, err := parseIDParam(chi.URLParam(r, "id"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := s.tokenService.RevokeToken(ctx, id); err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListSigningKeys handles GET /v1/admin/keys
+// Lists signing keys without their secrets, newest first.
+func (s *Server) handleListSigningKeys(w http.ResponseWriter, r *http.Request) {
+	if s.signingService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Event signing not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	keys, err := s.signingService.ListKeys(ctx)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := SigningKeyListResponse{
+		Data: keys,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleCreateSigningKey handles POST /v1/admin/keys
+// Rotates the signing key: the new key signs from now on. The secret is only
+// included in this response.
+func (s *Server) handleCreateSigningKey(w http.ResponseWriter, r *http.Request) {
+	if s.signingService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Event signing not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	key, secret, err := s.signingService.CreateKey(ctx)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := CreatedSigningKeyResponse{
+		Data: &CreatedSigningKey{SigningKey: key, Secret: secret},
+	}
+
+	if err := writeJSONResponse(w, http.StatusCreated, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleRetireSigningKey handles DELETE /v1/admin/keys/{id}
+// The newest remaining key takes over signing.
+func (s *Server) handleRetireSigningKey(w http.ResponseWriter, r *http.Request) {
+	if s.signingService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Event signing not available")
+		return
+	}
+
+	id, err := parseIDParam(chi.URLParam(r, "id"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := s.signingService.RetireKey(ctx, id); err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetFetchStats handles GET /v1/admin/fetch-stats
+// Returns the fetch cycle counters and durations since the daemon started,
+// to tune the fetch interval from real data.
+func (s *Server) handleGetFetchStats(w http.ResponseWriter, r *http.Request) {
+	if s.getFetchStats == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Fetch statistics not available")
+		return
+	}
+
+	response := FetchStatsResponse{
+		Data: s.getFetchStats(),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetStorageForecast handles GET /v1/admin/storage
+// Estimates the database growth at the current cadence and retention policy,
+// to pick retention settings and hardware.
+func (s *Server) handleGetStorageForecast(w http.ResponseWriter, r *http.Request) {
+	if s.storageService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Storage forecast not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	forecast, err := s.storageService.Forecast(ctx)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := StorageForecastResponse{
+		Data: forecast,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleListSSEClients handles GET /v1/admin/sse
+// Lists the connected event stream subscribers with their delivery counters,
+// to find dashboard clients that drop events or never disconnect.
+// Long-poll requests appear with a "longpoll-" ID while they wait.
+func (s *Server) handleListSSEClients(w http.ResponseWriter, r *http.Request) {
+	if s.eventBroker == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "SSE streaming not available")
+		return
+	}
+
+	response := SSEClientListResponse{
+		Data: s.eventBroker.Subscribers(),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleDisconnectSSEClient handles DELETE /v1/admin/sse/{id}
+// Ends the subscriber's stream; the client may reconnect with a new ID.
+func (s *Server) handleDisconnectSSEClient(w http.ResponseWriter, r *http.Request) {
+	if s.eventBroker == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "SSE streaming not available")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if !s.eventBroker.Disconnect(id) {
+		writeJSONError(w, http.StatusNotFound, "SSE client not found")
+		return
+	}
+
+	s.logger.Info("SSE client disconnected by admin", "clientID", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetLogs handles GET /v1/admin/logs?since=1h&level=warn
+// Returns the recent log records kept in memory, for remote troubleshooting.
+// Only the last records are kept, so older ones may be missing.
+func (s *Server) handleGetLogs(w http.ResponseWriter, r *http.Request) {
+	if s.logRing == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Log export not available")
+		return
+	}
+
+	since, level, err := parseLogsParams(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := LogsResponse{
+		Data: s.logRing.Since(since, level),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleAcceptTerms handles POST /v1/admin/upstream/accept-terms
+// Accepts the documents LibreView requires before a login succeeds. The daemon
+// logs in right away; the health status tells when it succeeded.
+func (s *Server) handleAcceptTerms(w http.ResponseWriter, r *http.Request) {
+	if s.acceptTerms == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Terms acceptance not available")
+		return
+	}
+
+	if err := s.acceptTerms(); err != nil {
+		if errors.Is(err, daemon.ErrNoPendingTerms) {
+			writeJSONError(w, http.StatusConflict, "No LibreView terms to accept")
+			return
+		}
+		handleError(w, err, s.logger)
+		return
+	}
+
+	s.logger.Info("LibreView terms accepted by admin")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleStartBackfill handles POST /v1/admin/backfill
+// Starts a background job importing the LibreView history of the last days,
+// older than the 12 hours the daemon fetches.
+func (s *Server) handleStartBackfill(w http.ResponseWriter, r *http.Request) {
+	if s.jobManager == nil || !s.jobManager.Registered(backfill.JobType) {
+		writeJSONError(w, http.StatusServiceUnavailable, "Backfill not available")
+		return
+	}
+
+	req, err := parseBackfillRequest(w, r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	s.logger.Info("history backfill requested", "days", req.Days)
+	s.startJob(w, r, backfill.JobType, req)
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// handleGetAlertHistory handles GET /v1/alerts/history
+// Returns a paginated list of fired alerts, newest first, with optional filters.
+func (s *Server) handleGetAlertHistory(w http.ResponseWriter, r *http.Request) {
+	if s.alertService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Alert history not available")
+		return
+	}
+
+	limit, offset, err := parsePaginationParams(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	filters, err := parseAlertFilters(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	alerts, total, err := s.alertService.GetAlertsWithFilters(ctx, filters, limit, offset)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := AlertListResponse{
+		Data:       alerts,
+		Pagination: newPaginationMetadata(limit, offset, total),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetAlertWeekly handles GET /v1/alerts/weekly
+// Returns alert counts per week (Monday to Sunday), oldest first.
+func (s *Server) handleGetAlertWeekly(w http.ResponseWriter, r *http.Request) {
+	if s.alertService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Alert history not available")
+		return
+	}
+
+	weeks, err := parseAlertWeeks(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	counts, err := s.alertService.GetWeeklyCounts(ctx, weeks)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := AlertWeeklyResponse{
+		Data: counts,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleAcknowledgeAlert handles POST /v1/alerts/{id}/ack
+// Returns 404 if the alert does not exist or is already acknowledged.
+func (s *Server) handleAcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	if s.alertService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Alert history not available")
+		return
+	}
+
+	id, err := parseIDParam(chi.URLParam(r, "id"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := s.alertService.AcknowledgeAlert(ctx, id); err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
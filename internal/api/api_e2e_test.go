@@ -1,10 +1,22 @@
 package api_test
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log/slog"
+	"math"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,18 +25,76 @@ import (
 	gormlogger "gorm.io/gorm/logger"
 
 	"github.com/R4yL-dev/glcmd/internal/api"
+	"github.com/R4yL-dev/glcmd/internal/backfill"
 	"github.com/R4yL-dev/glcmd/internal/daemon"
 	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/jobs"
+	"github.com/R4yL-dev/glcmd/internal/logger"
 	"github.com/R4yL-dev/glcmd/internal/repository"
 	"github.com/R4yL-dev/glcmd/internal/service"
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
 )
 
+// testSyncToken is the sync token configured on the test server
+const testSyncToken = "test-sync-token"
+
+// testAdminToken is the bootstrap admin token configured on the test server
+const testAdminToken = "test-admin-token-0123456789"
+
+// testDatabaseSize is the database size reported to the storage forecast
+const testDatabaseSize = 192 * 1000
+
 // setupE2ETest creates a test environment with in-memory database and API server
 func setupE2ETest(t *testing.T) (http.Handler, *gorm.DB) {
 	t.Helper()
+	return setupE2ETestWithBroker(t, nil)
+}
+
+// setupE2ETestWithBroker is like setupE2ETest with an optional event broker
+// (nil disables SSE)
+func setupE2ETestWithBroker(t *testing.T, eventBroker *events.Broker) (http.Handler, *gorm.DB) {
+	t.Helper()
+	return setupE2EServer(t, eventBroker, nil)
+}
+
+// setupE2EServer creates the test environment with an optional event broker
+// and static API tokens (nil leaves the data endpoints open)
+func setupE2EServer(t *testing.T, eventBroker *events.Broker, apiTokens map[string]string) (http.Handler, *gorm.DB) {
+	t.Helper()
 
 	// Setup in-memory database
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+	db := openE2EDatabase(t, ":memory:")
+
+	// Job workers are not started: enqueued jobs stay pending
+	server, _ := newE2EServer(t, db, eventBroker, apiTokens)
+	return server.HTTPHandler(), db
+}
+
+// setupE2EJobServer is like setupE2ETest with the background job workers
+// running. The database is a file, as the workers use their own connections.
+func setupE2EJobServer(t *testing.T) http.Handler {
+	t.Helper()
+
+	db := openE2EDatabase(t, filepath.Join(t.TempDir(), "e2e.db")+"?_journal_mode=WAL&_busy_timeout=5000")
+	server, jobManager := newE2EServer(t, db, nil, nil)
+	if err := jobManager.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start job workers: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		jobManager.Shutdown(ctx)
+	})
+
+	return server.HTTPHandler()
+}
+
+// openE2EDatabase opens and migrates the test database
+func openE2EDatabase(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
 		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
 	})
 	if err != nil {
@@ -38,32 +108,112 @@ func setupE2ETest(t *testing.T) (http.Handler, *gorm.DB) {
 		&domain.UserPreferences{},
 		&domain.DeviceInfo{},
 		&domain.GlucoseTargets{},
+		&domain.DashboardConfig{},
+		&domain.DisplayPreferences{},
+		&domain.SavedView{},
+		&domain.Note{},
+		&domain.InsulinDose{},
+		&domain.CarbIntake{},
+		&domain.APIToken{},
+		&domain.SigningKey{},
+		&domain.Alert{},
+		&domain.TreatmentEntry{},
+		&domain.UpstreamOutage{},
+		&domain.UpstreamSession{},
+		&domain.SensorAttachment{},
+		&domain.Job{},
+		&domain.GlucoseEvent{},
+		&domain.GlucoseRollup{},
+		&domain.DailySummary{},
 	)
 	if err != nil {
 		t.Fatalf("failed to run migrations: %v", err)
 	}
 
+	return db
+}
+
+// newE2EServer creates the API server and its job manager on db
+func newE2EServer(t *testing.T, db *gorm.DB, eventBroker *events.Broker, apiTokens map[string]string) (*api.Server, *jobs.Manager) {
+	t.Helper()
+
 	// Create repositories
 	measurementRepo := repository.NewGlucoseRepository(db)
 	sensorRepo := repository.NewSensorRepository(db)
 	userRepo := repository.NewUserRepository(db)
 	deviceRepo := repository.NewDeviceRepository(db)
 	targetsRepo := repository.NewTargetsRepository(db)
+	dashboardRepo := repository.NewDashboardRepository(db)
+	prefsRepo := repository.NewPreferencesRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	signingKeyRepo := repository.NewSigningKeyRepository(db)
+	alertRepo := repository.NewAlertRepository(db)
+	glucoseEventRepo := repository.NewGlucoseEventRepository(db)
+	dailySummaryRepo := repository.NewDailySummaryRepository(db)
+	treatmentRepo := repository.NewTreatmentRepository(db)
+	privacyRepo := repository.NewPrivacyRepository(db)
+	viewRepo := repository.NewViewRepository(db)
+	noteRepo := repository.NewNoteRepository(db)
+	insulinRepo := repository.NewInsulinRepository(db)
+	carbRepo := repository.NewCarbRepository(db)
+	upstreamRepo := repository.NewUpstreamRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	rollupRepo := repository.NewGlucoseRollupRepository(db)
+	jobRepo := repository.NewJobRepository(db)
 	uow := repository.NewUnitOfWork(db)
 
 	// Create services (nil event broker for tests)
-	glucoseService := service.NewGlucoseService(measurementRepo, slog.Default(), nil)
-	sensorService := service.NewSensorService(sensorRepo, uow, slog.Default(), nil)
-	configService := service.NewConfigService(userRepo, deviceRepo, targetsRepo, slog.Default())
-
-	// Create API server (nil event broker for tests)
-	server := api.NewServer(
-		8080,
-		glucoseService,
-		sensorService,
-		configService,
-		nil, // eventBroker
-		func() daemon.HealthStatus {
+	glucoseService := service.NewGlucoseService(measurementRepo, nil, slog.Default(), nil)
+	sensorService := service.NewSensorService(sensorRepo, measurementRepo, uow, domain.DefaultSensorGracePeriod, slog.Default(), nil)
+	configService := service.NewConfigService(userRepo, deviceRepo, targetsRepo, dashboardRepo, prefsRepo, slog.Default(), nil)
+	syncService := service.NewSyncService(measurementRepo, sensorRepo, slog.Default())
+	tokenService := service.NewTokenService(tokenRepo, slog.Default())
+	signingService := service.NewSigningService(signingKeyRepo, slog.Default())
+	modeService := service.NewModeService(slog.Default())
+	alertService := service.NewAlertService(alertRepo, slog.Default())
+	glucoseEventService := service.NewGlucoseEventService(glucoseEventRepo, measurementRepo, targetsRepo, uow, slog.Default())
+	dailySummaryService := service.NewDailySummaryService(dailySummaryRepo, measurementRepo, targetsRepo, slog.Default())
+	treatmentService := service.NewTreatmentService(treatmentRepo, measurementRepo, uow, slog.Default())
+	privacyService := service.NewPrivacyService(privacyRepo, uow, slog.Default())
+	viewService := service.NewViewService(viewRepo, slog.Default())
+	noteService := service.NewNoteService(noteRepo, slog.Default())
+	logbookService := service.NewLogbookService(insulinRepo, carbRepo, slog.Default())
+	upstreamService := service.NewUpstreamService(upstreamRepo, slog.Default())
+	attachmentService := service.NewAttachmentService(attachmentRepo, sensorRepo, t.TempDir(), slog.Default())
+	storageService := service.NewStorageService(measurementRepo, rollupRepo, func(ctx context.Context) (int64, error) { return testDatabaseSize, nil }, 0, 0)
+	jobManager := jobs.NewManager(jobRepo, 1, time.Hour, slog.Default())
+
+	// Keep the server's logs in memory, as glcore does for the log export
+	logRing := logger.NewRing(logger.DefaultRingSize)
+
+	// Create API server
+	server := api.NewServer(api.Options{
+		Port:                8080,
+		SyncToken:           testSyncToken,
+		AdminToken:          testAdminToken,
+		APITokens:           apiTokens,
+		GlucoseService:      glucoseService,
+		SensorService:       sensorService,
+		ConfigService:       configService,
+		SyncService:         syncService,
+		TokenService:        tokenService,
+		SigningService:      signingService,
+		EventBroker:         eventBroker,
+		ModeService:         modeService,
+		AlertService:        alertService,
+		GlucoseEventService: glucoseEventService,
+		DailySummaryService: dailySummaryService,
+		TreatmentService:    treatmentService,
+		PrivacyService:      privacyService,
+		ViewService:         viewService,
+		NoteService:         noteService,
+		LogbookService:      logbookService,
+		UpstreamService:     upstreamService,
+		AttachmentService:   attachmentService,
+		StorageService:      storageService,
+		JobManager:          jobManager,
+		LogRing:             logRing,
+		GetHealthStatus: func() daemon.HealthStatus {
 			return daemon.HealthStatus{
 				Status:            "healthy",
 				Timestamp:         time.Now(),
@@ -74,14 +224,22 @@ func setupE2ETest(t *testing.T) (http.Handler, *gorm.DB) {
 				DataFresh:         true,
 			}
 		},
-		func() bool { return true },
-		nil, // getDatabasePoolStats
-		slog.Default(),
-	)
+		GetConnectionInfo: func() *domain.ConnectionInfo {
+			return &domain.ConnectionInfo{
+				Source:          domain.ConnectionSourceLibreLinkUp,
+				PatientInitials: "J.D.",
+				Country:         "CH",
+				SensorBrand:     domain.SensorBrand(4),
+				SensorType:      4,
+			}
+		},
+		GetFetchStats: func() daemon.FetchStats {
+			return daemon.FetchStats{Cycles: 3, Succeeded: 2, Failed: 1, MeasurementsStored: 2, Reauthentications: 1}
+		},
+		GetDatabaseHealth: func() bool { return true },
+	}, slog.New(logger.NewRingHandler(slog.Default().Handler(), logRing)))
 
-	// Return the HTTP handler from the server's httpServer field
-	// We access the Handler field which contains the chi router
-	return server.HTTPHandler(), db
+	return server, jobManager
 }
 
 // TestE2E_GetLatestMeasurement_NotFound tests getting latest measurement from empty database
@@ -151,351 +309,510 @@ func TestE2E_SaveAndGetMeasurement(t *testing.T) {
 	}
 }
 
-// TestE2E_GetMeasurements_WithPagination tests pagination
-func TestE2E_GetMeasurements_WithPagination(t *testing.T) {
+// TestE2E_GlucoseDebug tests the fetch provenance exposed with ?debug=true
+func TestE2E_GlucoseDebug(t *testing.T) {
 	server, db := setupE2ETest(t)
 
-	// Insert 5 test measurements
-	for i := 0; i < 5; i++ {
-		ts := time.Now().UTC().Add(time.Duration(-i) * time.Hour)
-		measurement := &domain.GlucoseMeasurement{
-			FactoryTimestamp: ts,
-			Timestamp:        ts,
-			Value:            5.0 + float64(i)*0.1,
-			ValueInMgPerDl:   90 + i,
-			GlucoseColor: domain.GlucoseColorNormal,
-			Type:             domain.GlucoseTypeCurrent,
-		}
-		if err := db.Create(measurement).Error; err != nil {
-			t.Fatalf("failed to insert test measurement: %v", err)
-		}
+	now := time.Now().UTC().Truncate(time.Second)
+	fetchedAt := now.Add(20 * time.Second)
+	measurement := insertLatestMeasurement(t, db, now, 110)
+	measurement.FetchCycleID = "cycle-1"
+	measurement.FetchedAt = &fetchedAt
+	measurement.FetchSource = "glucoseMeasurement"
+	if err := db.Save(measurement).Error; err != nil {
+		t.Fatalf("failed to update measurement: %v", err)
 	}
+	insertLatestMeasurement(t, db, now.Add(-5*time.Minute), 100)
 
-	// GET first page (limit=2)
-	req := httptest.NewRequest("GET", "/v1/glucose?limit=2&offset=0", nil)
+	// Without debug, provenance is not exposed
 	w := httptest.NewRecorder()
-
-	server.ServeHTTP(w, req)
-
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/glucose/latest", nil))
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status 200, got %d", w.Code)
 	}
+	if strings.Contains(w.Body.String(), "fetchCycleId") {
+		t.Errorf("expected no provenance without debug, got %s", w.Body.String())
+	}
 
-	var response api.MeasurementListResponse
-	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/glucose/latest?debug=true", nil))
+	var latest api.GlucoseDebugResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &latest); err != nil {
 		t.Fatalf("failed to parse response: %v", err)
 	}
-
-	if len(response.Data) != 2 {
-		t.Errorf("expected 2 measurements, got %d", len(response.Data))
+	if latest.Data.ValueInMgPerDl != 110 || latest.Data.FetchCycleID != "cycle-1" ||
+		latest.Data.FetchedAt == nil || !latest.Data.FetchedAt.Equal(fetchedAt) || latest.Data.FetchSource != "glucoseMeasurement" {
+		t.Errorf("unexpected debug measurement: %s", w.Body.String())
 	}
 
-	if response.Pagination.Total != 5 {
-		t.Errorf("expected total 5, got %d", response.Pagination.Total)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/glucose?debug=true", nil))
+	var list api.GlucoseDebugListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
 	}
-
-	if !response.Pagination.HasMore {
-		t.Error("expected hasMore to be true")
+	if len(list.Data) != 2 || list.Data[0].FetchCycleID != "cycle-1" || list.Data[1].FetchCycleID != "" || list.Data[1].FetchedAt != nil {
+		t.Errorf("unexpected debug list: %s", w.Body.String())
 	}
 
-	// GET second page (limit=2, offset=2)
-	req = httptest.NewRequest("GET", "/v1/glucose?limit=2&offset=2", nil)
 	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/glucose?debug=maybe", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid debug, got %d", w.Code)
+	}
+}
 
-	server.ServeHTTP(w, req)
+// TestE2E_GlucoseExport tests the CSV and TSV export of the glucose history
+func TestE2E_GlucoseExport(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	now := time.Date(2025, 1, 3, 10, 30, 0, 0, time.UTC)
+	insertLatestMeasurement(t, db, now, 139)
+	insertLatestMeasurement(t, db, now.Add(-5*time.Minute), 126)
+	insertLatestMeasurement(t, db, now.Add(-time.Hour), 90)
 
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/glucose/export?start="+now.Add(-10*time.Minute).Format(time.RFC3339), nil))
 	if w.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", w.Code)
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("expected text/csv, got %q", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, "glcmd-glucose.csv") {
+		t.Errorf("expected CSV attachment, got %q", cd)
 	}
 
-	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
-		t.Fatalf("failed to parse response: %v", err)
+	// Oldest first, the measurement before start excluded
+	want := "timestamp,factory_timestamp,value_mgdl,value_mmol,trend_arrow,measurement_color,type\n" +
+		"2025-01-03T10:25:00Z,2025-01-03T10:25:00Z,126,7,,1,1\n" +
+		"2025-01-03T10:30:00Z,2025-01-03T10:30:00Z,139,7.722222222222222,,1,1\n"
+	if w.Body.String() != want {
+		t.Errorf("unexpected CSV:\n%s\nexpected:\n%s", w.Body.String(), want)
 	}
 
-	if len(response.Data) != 2 {
-		t.Errorf("expected 2 measurements on page 2, got %d", len(response.Data))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/glucose/export?format=tsv", nil))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "2025-01-03T09:30:00Z\t") {
+		t.Errorf("unexpected TSV: %q", w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/tab-separated-values") {
+		t.Errorf("expected TSV content type, got %q", ct)
 	}
 
-	if !response.Pagination.HasMore {
-		t.Error("expected hasMore to be true (1 item left)")
+	// No measurements: header row only
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/glucose/export?start=2030-01-01T00:00:00Z", nil))
+	if w.Code != http.StatusOK || strings.Count(w.Body.String(), "\n") != 1 {
+		t.Errorf("expected header row only, got %d: %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/glucose/export?format=xlsx", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid format, got %d", w.Code)
 	}
 }
 
-// TestE2E_GetStatistics_WithData tests statistics calculation
-func TestE2E_GetStatistics_WithData(t *testing.T) {
+// TestE2E_Bootstrap tests the dashboard startup data in one response
+func TestE2E_Bootstrap(t *testing.T) {
 	server, db := setupE2ETest(t)
 
-	// Insert glucose targets (in mg/dL)
-	targets := &domain.GlucoseTargets{
-		TargetLow:     72,  // 4.0 mmol/L = 72 mg/dL
-		TargetHigh:    126, // 7.0 mmol/L = 126 mg/dL
-		UnitOfMeasure: domain.GlucoseUnitsMgDl,
+	// Empty database: nulls and an empty list
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/bootstrap", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
 	}
-	if err := db.Create(targets).Error; err != nil {
-		t.Fatalf("failed to insert targets: %v", err)
+	var empty map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &empty); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if string(empty["data"]["latest"]) != "null" || string(empty["data"]["recent"]) != "[]" || string(empty["data"]["sensor"]) != "null" {
+		t.Errorf("unexpected empty bootstrap: %s", w.Body.String())
 	}
 
-	// Insert measurements with different colors (use UTC time)
 	now := time.Now().UTC()
-	measurements := []*domain.GlucoseMeasurement{
-		{FactoryTimestamp: now.Add(-3 * time.Hour), Timestamp: now.Add(-3 * time.Hour), Value: 5.0, ValueInMgPerDl: 90, GlucoseColor: domain.GlucoseColorNormal, Type: domain.GlucoseTypeCurrent},
-		{FactoryTimestamp: now.Add(-2 * time.Hour), Timestamp: now.Add(-2 * time.Hour), Value: 8.5, ValueInMgPerDl: 153, GlucoseColor: domain.GlucoseColorWarning, IsHigh: true, Type: domain.GlucoseTypeCurrent},
-		{FactoryTimestamp: now.Add(-1 * time.Hour), Timestamp: now.Add(-1 * time.Hour), Value: 5.2, ValueInMgPerDl: 94, GlucoseColor: domain.GlucoseColorNormal, Type: domain.GlucoseTypeCurrent},
+	insertLatestMeasurement(t, db, now.Add(-4*time.Hour), 100)
+	insertLatestMeasurement(t, db, now.Add(-time.Hour), 120)
+	insertLatestMeasurement(t, db, now.Add(-time.Second), 140)
+	sensor := &domain.SensorConfig{
+		SerialNumber: "SENSOR001",
+		Activation:   now.Add(-2 * 24 * time.Hour),
+		ExpiresAt:    now.Add(12 * 24 * time.Hour),
+		SensorType:   4,
+		DurationDays: 14,
+		DetectedAt:   now.Add(-2 * 24 * time.Hour),
 	}
-	for _, m := range measurements {
-		if err := db.Create(m).Error; err != nil {
-			t.Fatalf("failed to insert measurement: %v", err)
-		}
+	if err := db.Create(sensor).Error; err != nil {
+		t.Fatalf("failed to insert sensor: %v", err)
 	}
 
-	// GET statistics
-	start := now.Add(-4 * time.Hour).Format(time.RFC3339)
-	end := now.Format(time.RFC3339)
-
-	req := httptest.NewRequest("GET", "/v1/glucose/stats?start="+start+"&end="+end, nil)
-	w := httptest.NewRecorder()
-
-	server.ServeHTTP(w, req)
-
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/bootstrap", nil))
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
 	}
-
-	var response api.StatisticsResponse
+	var response api.BootstrapResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("failed to parse response: %v", err)
 	}
 
-	if response.Data.Statistics.Count != 3 {
-		t.Errorf("expected count 3, got %d", response.Data.Statistics.Count)
+	data := response.Data
+	if data.Latest == nil || data.Latest.ValueInMgPerDl != 140 {
+		t.Errorf("expected latest 140 mg/dL, got %+v", data.Latest)
 	}
-
-	if response.Data.Distribution.Normal != 2 {
-		t.Errorf("expected 2 normal measurements, got %d", response.Data.Distribution.Normal)
+	if len(data.Recent) != 2 || data.Recent[0].ValueInMgPerDl != 140 {
+		t.Errorf("expected the 2 readings of the last 3h, newest first, got %d", len(data.Recent))
 	}
-
-	if response.Data.Distribution.High != 1 {
-		t.Errorf("expected 1 high measurement, got %d", response.Data.Distribution.High)
+	if data.Today.Statistics.Count < 1 || data.Today.Period.Start == "" {
+		t.Errorf("expected today's statistics, got %+v", data.Today)
 	}
-
-	// Verify Time in Range data is present
-	if response.Data.TimeInRange == nil {
-		t.Error("expected TimeInRange data, got nil")
-	} else {
-		if response.Data.TimeInRange.TargetLowMgDl != 72 {
-			t.Errorf("expected target low 72 mg/dL, got %d", response.Data.TimeInRange.TargetLowMgDl)
-		}
-		if response.Data.TimeInRange.TargetHighMgDl != 126 {
-			t.Errorf("expected target high 126 mg/dL, got %d", response.Data.TimeInRange.TargetHighMgDl)
-		}
+	if data.Sensor == nil || data.Sensor.SerialNumber != "SENSOR001" {
+		t.Errorf("expected the current sensor, got %+v", data.Sensor)
+	}
+	if data.Health.Status != "healthy" || !data.Health.DatabaseConnected {
+		t.Errorf("expected healthy status, got %+v", data.Health)
 	}
 }
 
-// TestE2E_GetStatistics_InvalidTimeRange tests validation of time range
-func TestE2E_GetStatistics_InvalidTimeRange(t *testing.T) {
-	server, _ := setupE2ETest(t)
-
-	// end < start (invalid)
-	start := time.Now().UTC().Format(time.RFC3339)
-	end := time.Now().UTC().Add(-1 * time.Hour).Format(time.RFC3339)
+// TestE2E_GlclientModels tests that the public client models decode the server responses
+func TestE2E_GlclientModels(t *testing.T) {
+	server, db := setupE2ETest(t)
 
-	req := httptest.NewRequest("GET", "/v1/glucose/stats?start="+start+"&end="+end, nil)
-	w := httptest.NewRecorder()
+	now := time.Now().UTC().Truncate(time.Second)
+	arrow := domain.TrendArrowFalling
+	measurement := &domain.GlucoseMeasurement{
+		FactoryTimestamp: now,
+		Timestamp:        now,
+		Value:            3.8,
+		ValueInMgPerDl:   68,
+		TrendArrow:       &arrow,
+		GlucoseColor:     domain.GlucoseColorWarning,
+		GlucoseUnits:     domain.GlucoseUnitsMgDl,
+		IsLow:            true,
+		Type:             domain.GlucoseTypeCurrent,
+	}
+	if err := db.Create(measurement).Error; err != nil {
+		t.Fatalf("failed to insert measurement: %v", err)
+	}
+	sensor := &domain.SensorConfig{
+		SerialNumber: "SENSOR001",
+		Activation:   now.Add(-2 * 24 * time.Hour),
+		ExpiresAt:    now.Add(12 * 24 * time.Hour),
+		SensorType:   4,
+		DurationDays: 14,
+		DetectedAt:   now.Add(-2 * 24 * time.Hour),
+	}
+	if err := db.Create(sensor).Error; err != nil {
+		t.Fatalf("failed to insert sensor: %v", err)
+	}
 
-	server.ServeHTTP(w, req)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	client := glclient.NewClient(httpServer.URL)
+	ctx := context.Background()
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("expected status 400, got %d", w.Code)
+	latest, err := client.Latest(ctx)
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if !latest.Timestamp.Equal(now) || latest.ValueInMgPerDl != 68 || latest.Value != 3.8 || latest.TrendArrow == nil ||
+		*latest.TrendArrow != arrow || latest.MeasurementColor != domain.GlucoseColorWarning || !latest.IsLow ||
+		latest.GlucoseUnits != domain.GlucoseUnitsMgDl || latest.Type != domain.GlucoseTypeCurrent {
+		t.Errorf("unexpected measurement: %+v", latest)
 	}
-}
-
-// TestE2E_GetStatistics_LargeTimeRange tests that large time ranges work (no 90-day limit)
-func TestE2E_GetStatistics_LargeTimeRange(t *testing.T) {
-	server, _ := setupE2ETest(t)
 
-	// Range > 90 days should now work (no limit)
-	start := time.Now().UTC().Add(-100 * 24 * time.Hour).Format(time.RFC3339)
-	end := time.Now().UTC().Format(time.RFC3339)
+	page, pagination, err := client.Glucose(ctx, glclient.GlucoseQuery{Limit: 10})
+	if err != nil || len(page) != 1 || pagination.Total != 1 {
+		t.Errorf("unexpected page: %+v %+v (%v)", page, pagination, err)
+	}
 
-	req := httptest.NewRequest("GET", "/v1/glucose/stats?start="+start+"&end="+end, nil)
-	w := httptest.NewRecorder()
+	current, err := client.LatestSensor(ctx)
+	if err != nil {
+		t.Fatalf("LatestSensor: %v", err)
+	}
+	if current.SerialNumber != "SENSOR001" || current.DurationDays != 14 || current.Status != "running" || current.DaysRemaining == nil {
+		t.Errorf("unexpected sensor: %+v", current)
+	}
 
-	server.ServeHTTP(w, req)
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil || !capabilities.Features[api.FeatureDeltaEncoding].Enabled {
+		t.Errorf("unexpected capabilities: %+v (%v)", capabilities, err)
+	}
 
-	if w.Code != http.StatusOK {
-		t.Errorf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	health, err := client.Health(ctx)
+	if err != nil || health.Status != "healthy" || !health.DatabaseConnected {
+		t.Errorf("unexpected health: %+v (%v)", health, err)
 	}
 }
 
-// TestE2E_GetStatistics_AllTime tests statistics without time bounds (all time)
-func TestE2E_GetStatistics_AllTime(t *testing.T) {
+// TestE2E_StatusSummary tests the flat status bar summary, with and without data
+func TestE2E_StatusSummary(t *testing.T) {
 	server, db := setupE2ETest(t)
 
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/status", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 without data, got %d", w.Code)
+	}
+	var empty map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &empty); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	// The field set is a contract with status bar scripts
+	for _, key := range []string{"glucose", "unit", "trend", "ageSeconds", "sensorDaysLeft", "serviceState"} {
+		if _, ok := empty[key]; !ok {
+			t.Errorf("missing field %q in %s", key, w.Body.String())
+		}
+	}
+	if string(empty["glucose"]) != "null" || string(empty["sensorDaysLeft"]) != "null" || string(empty["serviceState"]) != `"ok"` {
+		t.Errorf("unexpected empty summary: %s", w.Body.String())
+	}
+
 	now := time.Now().UTC()
+	m := insertLatestMeasurement(t, db, now.Add(-2*time.Minute), 97)
+	trend := domain.TrendArrowFalling
+	db.Model(m).Update("trend_arrow", trend)
+	sensor := &domain.SensorConfig{
+		SerialNumber: "SENSOR001",
+		Activation:   now.Add(-10 * 24 * time.Hour),
+		ExpiresAt:    now.Add(4*24*time.Hour + time.Hour),
+		SensorType:   4,
+		DurationDays: 14,
+		DetectedAt:   now.Add(-10 * 24 * time.Hour),
+	}
+	if err := db.Create(sensor).Error; err != nil {
+		t.Fatalf("failed to insert sensor: %v", err)
+	}
 
-	// Insert test measurement
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/status", nil))
+	var summary api.StatusSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if summary.Glucose == nil || *summary.Glucose != 5.4 || summary.Unit != "mmol" || summary.Trend != "falling" {
+		t.Errorf("unexpected glucose in summary: %s", w.Body.String())
+	}
+	if summary.AgeSeconds == nil || *summary.AgeSeconds < 119 || *summary.AgeSeconds > 130 {
+		t.Errorf("expected an age of ~120s, got %s", w.Body.String())
+	}
+	if summary.SensorDaysLeft == nil || *summary.SensorDaysLeft != 4.0 {
+		t.Errorf("expected 4.0 sensor days left, got %s", w.Body.String())
+	}
+}
+
+// insertLatestMeasurement inserts a current measurement taken at ts
+func insertLatestMeasurement(t *testing.T, db *gorm.DB, ts time.Time, mgdl int) *domain.GlucoseMeasurement {
+	t.Helper()
 	measurement := &domain.GlucoseMeasurement{
-		FactoryTimestamp: now.Add(-1 * time.Hour),
-		Timestamp:        now.Add(-1 * time.Hour),
-		Value:            7.0,
-		ValueInMgPerDl:   126,
+		FactoryTimestamp: ts,
+		Timestamp:        ts,
+		Value:            float64(mgdl) / 18.0,
+		ValueInMgPerDl:   mgdl,
+		GlucoseColor:     domain.GlucoseColorNormal,
 		Type:             domain.GlucoseTypeCurrent,
-		GlucoseColor: domain.GlucoseColorNormal,
 	}
-	db.Create(measurement)
+	if err := db.Create(measurement).Error; err != nil {
+		t.Fatalf("failed to insert test measurement: %v", err)
+	}
+	return measurement
+}
 
-	// Query without start/end (all time)
-	req := httptest.NewRequest("GET", "/v1/glucose/stats", nil)
-	w := httptest.NewRecorder()
+// TestE2E_GetLatestMeasurement_ETag tests conditional requests on the latest value
+func TestE2E_GetLatestMeasurement_ETag(t *testing.T) {
+	server, db := setupE2ETest(t)
+	insertLatestMeasurement(t, db, time.Now().UTC().Truncate(time.Second), 110)
 
+	req := httptest.NewRequest("GET", "/v1/glucose/latest", nil)
+	w := httptest.NewRecorder()
 	server.ServeHTTP(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Errorf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d (ETag %q)", w.Code, etag)
 	}
 
-	var response api.StatisticsResponse
-	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
-		t.Fatalf("failed to unmarshal response: %v", err)
-	}
+	// Same ETag: not modified
+	req = httptest.NewRequest("GET", "/v1/glucose/latest", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
 
-	if response.Data.Statistics.Count != 1 {
-		t.Errorf("expected count 1, got %d", response.Data.Statistics.Count)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("expected status 304, got %d", w.Code)
 	}
 
-	// Period should contain actual data bounds for all-time queries
-	if response.Data.Period.Start == "" || response.Data.Period.End == "" {
-		t.Errorf("expected period with actual data bounds, got start=%s end=%s",
-			response.Data.Period.Start, response.Data.Period.End)
+	// Outdated ETag with wait: answered immediately
+	req = httptest.NewRequest("GET", "/v1/glucose/latest?wait=30s", nil)
+	req.Header.Set("If-None-Match", `"1"`)
+	w = httptest.NewRecorder()
+	start := time.Now()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("expected immediate response, took %s", time.Since(start))
 	}
 }
 
-// TestE2E_GetSensor tests sensor listing
-func TestE2E_GetSensor(t *testing.T) {
+// TestE2E_GetLatestMeasurement_LongPollTimeout tests that a long-poll without
+// new data ends with 304 after the wait duration
+func TestE2E_GetLatestMeasurement_LongPollTimeout(t *testing.T) {
 	server, db := setupE2ETest(t)
+	m := insertLatestMeasurement(t, db, time.Now().UTC().Truncate(time.Second), 110)
 
-	now := time.Now().UTC()
-	endedAt := now.Add(-2 * 24 * time.Hour)
+	req := httptest.NewRequest("GET", "/v1/glucose/latest?wait=1s", nil)
+	req.Header.Set("If-None-Match", fmt.Sprintf(`"%d"`, m.FactoryTimestamp.Unix()))
+	w := httptest.NewRecorder()
+	start := time.Now()
+	server.ServeHTTP(w, req)
 
-	// Insert test sensors
-	sensors := []*domain.SensorConfig{
-		{
-			SerialNumber: "SENSOR001",
-			Activation:   now.Add(-22 * 24 * time.Hour),
-			ExpiresAt:    now.Add(-7 * 24 * time.Hour),
-			EndedAt:      &endedAt, // Ended sensor
-			SensorType:   4,
-			DurationDays: 15,
-			DetectedAt:   now.Add(-22 * 24 * time.Hour),
-		},
-		{
-			SerialNumber: "SENSOR002",
-			Activation:   now.Add(-2 * 24 * time.Hour),
-			ExpiresAt:    now.Add(13 * 24 * time.Hour),
-			EndedAt:      nil, // Current sensor
-			SensorType:   4,
-			DurationDays: 15,
-			DetectedAt:   now.Add(-2 * 24 * time.Hour),
-		},
+	if w.Code != http.StatusNotModified {
+		t.Errorf("expected status 304, got %d", w.Code)
 	}
-	for _, s := range sensors {
-		if err := db.Create(s).Error; err != nil {
-			t.Fatalf("failed to insert sensor: %v", err)
-		}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected the request to be held for 1s, took %s", elapsed)
 	}
+}
 
-	// GET sensors (paginated list)
-	req := httptest.NewRequest("GET", "/v1/sensor", nil)
-	w := httptest.NewRecorder()
+// TestE2E_GetLatestMeasurement_LongPollNewData tests that a long-poll returns
+// as soon as a new measurement is published
+func TestE2E_GetLatestMeasurement_LongPollNewData(t *testing.T) {
+	broker := events.NewBroker(10, slog.Default())
+	defer broker.Stop()
+
+	server, db := setupE2ETestWithBroker(t, broker)
+	ts := time.Now().UTC().Truncate(time.Second)
+	m := insertLatestMeasurement(t, db, ts.Add(-5*time.Minute), 110)
+
+	go func() {
+		// Publish once the long-poll has subscribed
+		for broker.SubscriberCount() == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		newer := &domain.GlucoseMeasurement{
+			FactoryTimestamp: ts,
+			Timestamp:        ts,
+			Value:            6.9,
+			ValueInMgPerDl:   125,
+			GlucoseColor:     domain.GlucoseColorNormal,
+			Type:             domain.GlucoseTypeCurrent,
+		}
+		db.Create(newer)
+		broker.Publish(events.Event{Type: events.EventTypeGlucose, Data: newer})
+	}()
 
+	req := httptest.NewRequest("GET", "/v1/glucose/latest?wait=10s", nil)
+	req.Header.Set("If-None-Match", fmt.Sprintf(`"%d"`, m.FactoryTimestamp.Unix()))
+	w := httptest.NewRecorder()
 	server.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status 200, got %d", w.Code)
 	}
 
-	var response api.SensorListResponse
+	var response api.MeasurementResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("failed to parse response: %v", err)
 	}
-
-	// Verify all sensors are returned
-	if len(response.Data) != 2 {
-		t.Errorf("expected 2 sensors, got %d", len(response.Data))
+	if response.Data.ValueInMgPerDl != 125 {
+		t.Errorf("expected new value 125, got %d", response.Data.ValueInMgPerDl)
 	}
-
-	if response.Pagination.Total != 2 {
-		t.Errorf("expected total 2, got %d", response.Pagination.Total)
+	if w.Header().Get("ETag") != fmt.Sprintf(`"%d"`, ts.Unix()) {
+		t.Errorf("unexpected ETag %q", w.Header().Get("ETag"))
 	}
 }
 
-// TestE2E_GetLatestSensor tests getting the current sensor
-func TestE2E_GetLatestSensor(t *testing.T) {
-	server, db := setupE2ETest(t)
-
-	now := time.Now().UTC()
-
-	// Insert current sensor
-	sensor := &domain.SensorConfig{
-		SerialNumber: "SENSOR001",
-		Activation:   now.Add(-2 * 24 * time.Hour),
-		ExpiresAt:    now.Add(12 * 24 * time.Hour),
-		EndedAt:      nil, // Current sensor
-		SensorType:   4,
-		DurationDays: 14,
-		DetectedAt:   now.Add(-2 * 24 * time.Hour),
-	}
-	if err := db.Create(sensor).Error; err != nil {
-		t.Fatalf("failed to insert sensor: %v", err)
-	}
+// TestE2E_GetLatestMeasurement_LongPollPatient tests that a long-poll scoped
+// to a patient ignores the new measurements of other patients
+func TestE2E_GetLatestMeasurement_LongPollPatient(t *testing.T) {
+	broker := events.NewBroker(10, slog.Default())
+	defer broker.Stop()
+
+	server, db := setupE2ETestWithBroker(t, broker)
+	patients := []string{"6a1f3a4e-0000-4000-8000-000000000001", "6a1f3a4e-0000-4000-8000-000000000002"}
+	ts := time.Now().UTC().Truncate(time.Second)
+	m := insertLatestMeasurement(t, db, ts.Add(-5*time.Minute), 110)
+	db.Model(m).Update("patient_id", patients[0])
+
+	go func() {
+		for broker.SubscriberCount() == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		for i, patientID := range []string{patients[1], patients[0]} {
+			newer := &domain.GlucoseMeasurement{
+				PatientID:        patientID,
+				FactoryTimestamp: ts.Add(time.Duration(i) * time.Second),
+				Timestamp:        ts.Add(time.Duration(i) * time.Second),
+				Value:            6.9,
+				ValueInMgPerDl:   125 + i,
+				GlucoseColor:     domain.GlucoseColorNormal,
+				Type:             domain.GlucoseTypeCurrent,
+			}
+			db.Create(newer)
+			broker.Publish(events.Event{Type: events.EventTypeGlucose, Data: newer, PatientID: patientID})
+		}
+	}()
 
-	// GET latest sensor
-	req := httptest.NewRequest("GET", "/v1/sensor/latest", nil)
+	req := httptest.NewRequest("GET", "/v1/glucose/latest?wait=10s&patientId="+patients[0], nil)
+	req.Header.Set("If-None-Match", fmt.Sprintf(`"%d"`, m.FactoryTimestamp.Unix()))
 	w := httptest.NewRecorder()
-
 	server.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected status 200, got %d", w.Code)
 	}
-
-	var response api.LatestSensorResponse
+	var response api.MeasurementResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("failed to parse response: %v", err)
 	}
-
-	if response.Data == nil {
-		t.Fatal("expected sensor data, got nil")
-	}
-
-	if response.Data.SerialNumber != "SENSOR001" {
-		t.Errorf("expected SENSOR001, got %s", response.Data.SerialNumber)
-	}
-
-	if response.Data.Status != "running" {
-		t.Errorf("expected status running, got %s", response.Data.Status)
+	if response.Data.ValueInMgPerDl != 126 || response.Data.PatientID != patients[0] {
+		t.Errorf("expected the patient's new value 126, got %d of %q", response.Data.ValueInMgPerDl, response.Data.PatientID)
 	}
 }
 
-// TestE2E_GetLatestSensor_NotFound tests getting latest sensor when none exists
-func TestE2E_GetLatestSensor_NotFound(t *testing.T) {
+// TestE2E_GetLatestMeasurement_InvalidWait tests wait parameter validation
+func TestE2E_GetLatestMeasurement_InvalidWait(t *testing.T) {
 	server, _ := setupE2ETest(t)
 
-	req := httptest.NewRequest("GET", "/v1/sensor/latest", nil)
-	w := httptest.NewRecorder()
-
-	server.ServeHTTP(w, req)
+	for _, wait := range []string{"abc", "-1s", "5m"} {
+		req := httptest.NewRequest("GET", "/v1/glucose/latest?wait="+wait, nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
 
-	if w.Code != http.StatusNotFound {
-		t.Errorf("expected status 404, got %d", w.Code)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("wait=%s: expected status 400, got %d", wait, w.Code)
+		}
 	}
 }
 
-// TestE2E_Health tests health endpoint
-func TestE2E_Health(t *testing.T) {
-	server, _ := setupE2ETest(t)
+// TestE2E_GetMeasurements_WithPagination tests pagination
+func TestE2E_GetMeasurements_WithPagination(t *testing.T) {
+	server, db := setupE2ETest(t)
 
-	req := httptest.NewRequest("GET", "/health", nil)
+	// Insert 5 test measurements
+	for i := 0; i < 5; i++ {
+		ts := time.Now().UTC().Add(time.Duration(-i) * time.Hour)
+		measurement := &domain.GlucoseMeasurement{
+			FactoryTimestamp: ts,
+			Timestamp:        ts,
+			Value:            5.0 + float64(i)*0.1,
+			ValueInMgPerDl:   90 + i,
+			GlucoseColor: domain.GlucoseColorNormal,
+			Type:             domain.GlucoseTypeCurrent,
+		}
+		if err := db.Create(measurement).Error; err != nil {
+			t.Fatalf("failed to insert test measurement: %v", err)
+		}
+	}
+
+	// GET first page (limit=2)
+	req := httptest.NewRequest("GET", "/v1/glucose?limit=2&offset=0", nil)
 	w := httptest.NewRecorder()
 
 	server.ServeHTTP(w, req)
@@ -504,30 +821,26 @@ func TestE2E_Health(t *testing.T) {
 		t.Fatalf("expected status 200, got %d", w.Code)
 	}
 
-	var response api.HealthResponse
+	var response api.MeasurementListResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("failed to parse response: %v", err)
 	}
 
-	if response.Data.Status != "healthy" {
-		t.Errorf("expected status healthy, got %s", response.Data.Status)
+	if len(response.Data) != 2 {
+		t.Errorf("expected 2 measurements, got %d", len(response.Data))
 	}
 
-	if !response.Data.DatabaseConnected {
-		t.Error("expected database connected")
+	if response.Pagination.Total != 5 {
+		t.Errorf("expected total 5, got %d", response.Pagination.Total)
 	}
 
-	if !response.Data.DataFresh {
-		t.Error("expected DataFresh = true")
+	if !response.Pagination.HasMore {
+		t.Error("expected hasMore to be true")
 	}
-}
-
-// TestE2E_Metrics tests metrics endpoint
-func TestE2E_Metrics(t *testing.T) {
-	server, _ := setupE2ETest(t)
 
-	req := httptest.NewRequest("GET", "/metrics", nil)
-	w := httptest.NewRecorder()
+	// GET second page (limit=2, offset=2)
+	req = httptest.NewRequest("GET", "/v1/glucose?limit=2&offset=2", nil)
+	w = httptest.NewRecorder()
 
 	server.ServeHTTP(w, req)
 
@@ -535,79 +848,3213 @@ func TestE2E_Metrics(t *testing.T) {
 		t.Fatalf("expected status 200, got %d", w.Code)
 	}
 
-	var response api.MetricsResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("failed to parse response: %v", err)
 	}
 
-	if response.Data.Goroutines <= 0 {
-		t.Errorf("expected positive goroutines count, got %d", response.Data.Goroutines)
+	if len(response.Data) != 2 {
+		t.Errorf("expected 2 measurements on page 2, got %d", len(response.Data))
 	}
 
-	if response.Data.Runtime.Version == "" {
-		t.Error("expected runtime version, got empty string")
+	if !response.Pagination.HasMore {
+		t.Error("expected hasMore to be true (1 item left)")
 	}
+}
 
-	if response.Data.Process.PID <= 0 {
-		t.Errorf("expected positive PID, got %d", response.Data.Process.PID)
+// TestE2E_GetMeasurements_PatientFilter tests scoping the data to a patient
+func TestE2E_GetMeasurements_PatientFilter(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	patients := []string{"6a1f3a4e-0000-4000-8000-000000000001", "6a1f3a4e-0000-4000-8000-000000000002"}
+	ts := time.Now().UTC().Truncate(time.Second)
+	for i, patientID := range patients {
+		for j := 0; j <= i; j++ {
+			measurement := &domain.GlucoseMeasurement{
+				PatientID:        patientID,
+				FactoryTimestamp: ts.Add(time.Duration(-j) * time.Minute),
+				Timestamp:        ts.Add(time.Duration(-j) * time.Minute),
+				Value:            5.5,
+				ValueInMgPerDl:   100 + i,
+				GlucoseColor:     domain.GlucoseColorNormal,
+				Type:             domain.GlucoseTypeCurrent,
+			}
+			if err := db.Create(measurement).Error; err != nil {
+				t.Fatalf("failed to insert test measurement: %v", err)
+			}
+		}
 	}
 
-	// SSE metrics (broker is nil in tests)
-	if response.Data.SSE.Enabled {
-		t.Error("expected SSE.Enabled = false (no broker)")
+	tests := []struct {
+		query string
+		total int64
+	}{
+		{"", 3},
+		{"?patientId=" + patients[0], 1},
+		{"?patientId=" + strings.ToUpper(patients[1]), 2},
+		{"?patientId=6a1f3a4e-0000-4000-8000-000000000003", 0},
 	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/v1/glucose"+tt.query, nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
 
-	if response.Data.SSE.Subscribers != 0 {
-		t.Errorf("expected SSE.Subscribers = 0, got %d", response.Data.SSE.Subscribers)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected status 200, got %d", tt.query, w.Code)
+		}
+		var response api.MeasurementListResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		if response.Pagination.Total != tt.total {
+			t.Errorf("%q: expected total %d, got %d", tt.query, tt.total, response.Pagination.Total)
+		}
 	}
 
-	// Database pool stats (nil callback in tests)
-	if response.Data.Database != nil {
-		t.Error("expected Database = nil (no callback)")
+	// The latest reading is the patient's own
+	req := httptest.NewRequest("GET", "/v1/glucose/latest?patientId="+patients[1], nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	var latest api.MeasurementResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &latest); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if w.Code != http.StatusOK || latest.Data == nil || latest.Data.PatientID != patients[1] || latest.Data.ValueInMgPerDl != 101 {
+		t.Errorf("expected the latest reading of the second patient, got %d %s", w.Code, w.Body.String())
+	}
+
+	// Invalid patient ID
+	req = httptest.NewRequest("GET", "/v1/glucose?patientId=jane", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid patientId, got %d", w.Code)
 	}
 }
 
-// TestE2E_CORS_Preflight tests CORS preflight request
-func TestE2E_CORS_Preflight(t *testing.T) {
-	server, _ := setupE2ETest(t)
+// TestE2E_GetMeasurements_FilterExpression tests the q filter expression
+func TestE2E_GetMeasurements_FilterExpression(t *testing.T) {
+	server, db := setupE2ETest(t)
 
-	req := httptest.NewRequest("OPTIONS", "/v1/glucose/latest", nil)
-	req.Header.Set("Origin", "http://localhost:3000")
-	req.Header.Set("Access-Control-Request-Method", "GET")
-	w := httptest.NewRecorder()
+	for i, mgdl := range []int{65, 110, 190, 250} {
+		ts := time.Now().UTC().Add(time.Duration(-i) * time.Hour)
+		insertLatestMeasurement(t, db, ts, mgdl)
+	}
 
+	q := url.QueryEscape("value_mgdl > 180 OR value_mgdl < 70")
+	req := httptest.NewRequest("GET", "/v1/glucose?q="+q, nil)
+	w := httptest.NewRecorder()
 	server.ServeHTTP(w, req)
 
-	if w.Code != http.StatusNoContent {
-		t.Errorf("expected status 204, got %d", w.Code)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
 	}
-
-	// Verify CORS headers
-	if w.Header().Get("Access-Control-Allow-Origin") != "*" {
-		t.Errorf("expected CORS origin *, got %s", w.Header().Get("Access-Control-Allow-Origin"))
+	var response api.MeasurementListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Pagination.Total != 3 {
+		t.Errorf("expected total 3, got %d", response.Pagination.Total)
+	}
+	for _, m := range response.Data {
+		if m.ValueInMgPerDl == 110 {
+			t.Errorf("unexpected in-range measurement %+v", m)
+		}
 	}
 
-	if w.Header().Get("Access-Control-Allow-Methods") == "" {
-		t.Error("expected Access-Control-Allow-Methods header")
+	for _, q := range []string{"glucose > 180", "value_mgdl > 'x'", "value_mgdl > 180 AND"} {
+		req := httptest.NewRequest("GET", "/v1/glucose?q="+url.QueryEscape(q), nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", q, w.Code)
+		}
 	}
 }
 
-// TestE2E_CORS_Headers tests CORS headers on actual request
-func TestE2E_CORS_Headers(t *testing.T) {
-	server, _ := setupE2ETest(t)
+// TestE2E_GetGlucoseHistogram tests binned reading counts
+func TestE2E_GetGlucoseHistogram(t *testing.T) {
+	server, db := setupE2ETest(t)
 
-	req := httptest.NewRequest("GET", "/health", nil)
-	req.Header.Set("Origin", "http://localhost:3000")
-	w := httptest.NewRecorder()
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, mgdl := range []int{62, 118, 112, 145, 290} {
+		insertLatestMeasurement(t, db, now.Add(time.Duration(-i)*time.Hour), mgdl)
+	}
 
+	req := httptest.NewRequest("GET", "/v1/glucose/histogram?bucketMgDl=20", nil)
+	w := httptest.NewRecorder()
 	server.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", w.Code)
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.HistogramResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	histogram := response.Data.Histogram
+	if histogram == nil || histogram.Total != 5 || histogram.BucketMgDl != 20 {
+		t.Fatalf("unexpected histogram: %+v", histogram)
+	}
+	// 60 to 300 mg/dL, contiguous
+	if len(histogram.Buckets) != 12 || histogram.Buckets[0].StartMgDl != 60 || histogram.Buckets[11].EndMgDl != 300 {
+		t.Errorf("expected 12 buckets from 60 to 300, got %+v", histogram.Buckets)
+	}
+	if histogram.Buckets[2].Count != 2 {
+		t.Errorf("expected 2 readings in 100-120, got %+v", histogram.Buckets[2])
+	}
+	if response.Data.Period != nil {
+		t.Errorf("expected no period for all time, got %+v", response.Data.Period)
 	}
 
-	// Verify CORS headers are present
-	if w.Header().Get("Access-Control-Allow-Origin") != "*" {
-		t.Errorf("expected CORS origin *, got %s", w.Header().Get("Access-Control-Allow-Origin"))
+	for _, query := range []string{"bucketMgDl=0", "bucketMgDl=500", "bucketMgDl=ten", "start=" + now.Format(time.RFC3339)} {
+		req := httptest.NewRequest("GET", "/v1/glucose/histogram?"+query, nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+// TestE2E_GetGlucosePercentiles tests the percentile bands by time of day
+func TestE2E_GetGlucosePercentiles(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	// Three days of readings one hour ago, and one older than the period
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, mgdl := range []int{100, 120, 140, 300} {
+		days := []int{0, 1, 2, 20}[i]
+		insertLatestMeasurement(t, db, now.Add(-time.Hour).AddDate(0, 0, -days), mgdl)
+	}
+
+	req := httptest.NewRequest("GET", "/v1/glucose/percentiles?days=14&bucket=1h", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.PercentilesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	percentiles := response.Data.Percentiles
+	if percentiles == nil || percentiles.BucketMinutes != 60 || response.Data.Days != 14 || response.Data.Period == nil {
+		t.Fatalf("unexpected response: %+v", response.Data)
+	}
+	if len(percentiles.Buckets) != 1 {
+		t.Fatalf("expected 1 bucket, got %+v", percentiles.Buckets)
+	}
+	if bucket := percentiles.Buckets[0]; bucket.Count != 3 || bucket.P50 != 120 {
+		t.Errorf("expected a median of 120 over 3 readings, got %+v", bucket)
+	}
+
+	for _, query := range []string{"days=0", "days=91", "bucket=7m", "bucket=3h", "bucket=30s", "bucket=half"} {
+		req := httptest.NewRequest("GET", "/v1/glucose/percentiles?"+query, nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+// TestE2E_GetConnection tests the upstream connection details
+func TestE2E_GetConnection(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/v1/connection", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.ConnectionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	info := response.Data
+	if info == nil || info.Source != "librelinkup" || info.PatientInitials != "J.D." || info.SensorBrand != "FreeStyle Libre 3 Plus" {
+		t.Errorf("unexpected connection details: %+v", info)
+	}
+}
+
+// TestE2E_GetUpstreamStatus tests the upstream availability history and status page
+func TestE2E_GetUpstreamStatus(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	ended := time.Now().UTC().Add(-time.Hour)
+	outage := &domain.UpstreamOutage{
+		StartedAt: ended.Add(-30 * time.Minute),
+		EndedAt:   &ended,
+		Cause:     domain.OutageCauseServer,
+		Failures:  30,
+	}
+	if err := db.Create(outage).Error; err != nil {
+		t.Fatalf("failed to insert test outage: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/v1/upstream/status?days=7", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.UpstreamStatusResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	status := response.Data
+	if status == nil || status.Status != "up" || len(status.Outages) != 1 || len(status.Uptime) != 3 {
+		t.Fatalf("unexpected upstream status: %+v", status)
+	}
+	if status.Outages[0].Cause != domain.OutageCauseServer || status.Uptime[0].Percent >= 100 {
+		t.Errorf("unexpected outage or uptime: %+v %+v", status.Outages[0], status.Uptime[0])
+	}
+
+	// Invalid days
+	req = httptest.NewRequest("GET", "/v1/upstream/status?days=0", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+
+	// Status page
+	req = httptest.NewRequest("GET", "/status", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("expected an HTML page, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), "/v1/upstream/status") {
+		t.Error("expected the status page to read the upstream status")
+	}
+}
+
+// TestE2E_GetMeasurements_DeltaEncoding tests the compact delta encoding
+func TestE2E_GetMeasurements_DeltaEncoding(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	base := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		ts := base.Add(time.Duration(-i) * 5 * time.Minute)
+		measurement := &domain.GlucoseMeasurement{
+			FactoryTimestamp: ts,
+			Timestamp:        ts,
+			Value:            5.0,
+			ValueInMgPerDl:   100 + i*3,
+			GlucoseColor:     domain.GlucoseColorNormal,
+			Type:             domain.GlucoseTypeCurrent,
+		}
+		if err := db.Create(measurement).Error; err != nil {
+			t.Fatalf("failed to insert test measurement: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/v1/glucose?encoding=delta", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response api.GlucoseDeltaListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	points, err := response.Data.Decode()
+	if err != nil {
+		t.Fatalf("failed to decode series: %v", err)
+	}
+	if len(points) != 3 {
+		t.Fatalf("expected 3 points, got %d", len(points))
+	}
+	if !points[0].Timestamp.Equal(base) || points[0].ValueInMgPerDl != 100 {
+		t.Errorf("unexpected first point: %+v", points[0])
+	}
+	if points[2].ValueInMgPerDl != 106 {
+		t.Errorf("expected last value 106, got %d", points[2].ValueInMgPerDl)
+	}
+	if response.Pagination.Total != 3 {
+		t.Errorf("expected total 3, got %d", response.Pagination.Total)
+	}
+
+	// Unknown encoding is rejected
+	req = httptest.NewRequest("GET", "/v1/glucose?encoding=zip", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+// TestE2E_GetStatistics_WithData tests statistics calculation
+func TestE2E_GetStatistics_WithData(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	// Insert glucose targets (in mg/dL)
+	targets := &domain.GlucoseTargets{
+		TargetLow:     72,  // 4.0 mmol/L = 72 mg/dL
+		TargetHigh:    126, // 7.0 mmol/L = 126 mg/dL
+		UnitOfMeasure: domain.GlucoseUnitsMgDl,
+	}
+	if err := db.Create(targets).Error; err != nil {
+		t.Fatalf("failed to insert targets: %v", err)
+	}
+
+	// Insert measurements with different colors (use UTC time)
+	now := time.Now().UTC()
+	measurements := []*domain.GlucoseMeasurement{
+		{FactoryTimestamp: now.Add(-3 * time.Hour), Timestamp: now.Add(-3 * time.Hour), Value: 5.0, ValueInMgPerDl: 90, GlucoseColor: domain.GlucoseColorNormal, Type: domain.GlucoseTypeCurrent},
+		{FactoryTimestamp: now.Add(-2 * time.Hour), Timestamp: now.Add(-2 * time.Hour), Value: 8.5, ValueInMgPerDl: 153, GlucoseColor: domain.GlucoseColorWarning, IsHigh: true, Type: domain.GlucoseTypeCurrent},
+		{FactoryTimestamp: now.Add(-1 * time.Hour), Timestamp: now.Add(-1 * time.Hour), Value: 5.2, ValueInMgPerDl: 94, GlucoseColor: domain.GlucoseColorNormal, Type: domain.GlucoseTypeCurrent},
+	}
+	for _, m := range measurements {
+		if err := db.Create(m).Error; err != nil {
+			t.Fatalf("failed to insert measurement: %v", err)
+		}
+	}
+
+	// GET statistics
+	start := now.Add(-4 * time.Hour).Format(time.RFC3339)
+	end := now.Format(time.RFC3339)
+
+	req := httptest.NewRequest("GET", "/v1/glucose/stats?start="+start+"&end="+end, nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response api.StatisticsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if response.Data.Statistics.Count != 3 {
+		t.Errorf("expected count 3, got %d", response.Data.Statistics.Count)
+	}
+
+	if response.Data.Distribution.Normal != 2 {
+		t.Errorf("expected 2 normal measurements, got %d", response.Data.Distribution.Normal)
+	}
+
+	if response.Data.Distribution.High != 1 {
+		t.Errorf("expected 1 high measurement, got %d", response.Data.Distribution.High)
+	}
+
+	if response.Data.Statistics.CV <= 0 || response.Data.Statistics.GMI == nil || response.Data.Statistics.EstimatedA1c == nil {
+		t.Errorf("expected CV, GMI and estimated A1c, got %+v", response.Data.Statistics)
+	}
+
+	// Verify Time in Range data is present
+	if response.Data.TimeInRange == nil {
+		t.Error("expected TimeInRange data, got nil")
+	} else {
+		if response.Data.TimeInRange.TargetLowMgDl != 72 {
+			t.Errorf("expected target low 72 mg/dL, got %d", response.Data.TimeInRange.TargetLowMgDl)
+		}
+		if response.Data.TimeInRange.TargetHighMgDl != 126 {
+			t.Errorf("expected target high 126 mg/dL, got %d", response.Data.TimeInRange.TargetHighMgDl)
+		}
+	}
+
+	// 3 hourly readings cover 45 minutes of the 4 hours
+	if q := response.Data.DataQuality; q == nil || !q.LowConfidence || q.Gaps < 3 {
+		t.Errorf("expected low-confidence data quality with gaps, got %+v", q)
+	}
+}
+
+// TestE2E_GetStatistics_InvalidTimeRange tests validation of time range
+func TestE2E_GetStatistics_InvalidTimeRange(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	// end < start (invalid)
+	start := time.Now().UTC().Format(time.RFC3339)
+	end := time.Now().UTC().Add(-1 * time.Hour).Format(time.RFC3339)
+
+	req := httptest.NewRequest("GET", "/v1/glucose/stats?start="+start+"&end="+end, nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+// TestE2E_GetStatistics_LargeTimeRange tests that large time ranges work (no 90-day limit)
+func TestE2E_GetStatistics_LargeTimeRange(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	// Range > 90 days should now work (no limit)
+	start := time.Now().UTC().Add(-100 * 24 * time.Hour).Format(time.RFC3339)
+	end := time.Now().UTC().Format(time.RFC3339)
+
+	req := httptest.NewRequest("GET", "/v1/glucose/stats?start="+start+"&end="+end, nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// TestE2E_GetStatistics_AllTime tests statistics without time bounds (all time)
+func TestE2E_GetStatistics_AllTime(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	now := time.Now().UTC()
+
+	// Insert test measurement
+	measurement := &domain.GlucoseMeasurement{
+		FactoryTimestamp: now.Add(-1 * time.Hour),
+		Timestamp:        now.Add(-1 * time.Hour),
+		Value:            7.0,
+		ValueInMgPerDl:   126,
+		Type:             domain.GlucoseTypeCurrent,
+		GlucoseColor: domain.GlucoseColorNormal,
+	}
+	db.Create(measurement)
+
+	// Query without start/end (all time)
+	req := httptest.NewRequest("GET", "/v1/glucose/stats", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response api.StatisticsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	if response.Data.Statistics.Count != 1 {
+		t.Errorf("expected count 1, got %d", response.Data.Statistics.Count)
+	}
+
+	// Period should contain actual data bounds for all-time queries
+	if response.Data.Period.Start == "" || response.Data.Period.End == "" {
+		t.Errorf("expected period with actual data bounds, got start=%s end=%s",
+			response.Data.Period.Start, response.Data.Period.End)
+	}
+}
+
+// TestE2E_GlucoseQuality tests the daily data quality of a time range
+func TestE2E_GlucoseQuality(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	// Readings every 15 minutes over the last 2 hours
+	now := time.Now().UTC().Truncate(time.Minute)
+	for ts := now.Add(-2 * time.Hour); !ts.After(now); ts = ts.Add(15 * time.Minute) {
+		measurement := &domain.GlucoseMeasurement{
+			FactoryTimestamp: ts,
+			Timestamp:        ts,
+			ValueInMgPerDl:   110,
+			Type:             domain.GlucoseTypeHistorical,
+		}
+		if err := db.Create(measurement).Error; err != nil {
+			t.Fatalf("failed to insert measurement: %v", err)
+		}
+	}
+
+	start := now.Add(-2 * time.Hour).Format(time.RFC3339)
+	end := now.Format(time.RFC3339)
+	req := httptest.NewRequest("GET", "/v1/glucose/quality?start="+start+"&end="+end, nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response api.QualityResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(response.Data.Days) == 0 {
+		t.Fatal("expected at least one day")
+	}
+	summary := response.Data.Summary
+	if summary.CapturePercent != 100 || summary.Gaps != 0 || summary.Artifacts != 0 || summary.LowConfidence {
+		t.Errorf("expected complete data, got %+v", summary)
+	}
+
+	// Default range: last 14 days, mostly without data
+	req = httptest.NewRequest("GET", "/v1/glucose/quality", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	response = api.QualityResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(response.Data.Days) < 14 || !response.Data.Summary.LowConfidence {
+		t.Errorf("expected 14 low-confidence days, got %d days, %+v", len(response.Data.Days), response.Data.Summary)
+	}
+}
+
+// TestE2E_GetSensor tests sensor listing
+func TestE2E_GetSensor(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	now := time.Now().UTC()
+	endedAt := now.Add(-2 * 24 * time.Hour)
+
+	// Insert test sensors
+	sensors := []*domain.SensorConfig{
+		{
+			SerialNumber: "SENSOR001",
+			Activation:   now.Add(-22 * 24 * time.Hour),
+			ExpiresAt:    now.Add(-7 * 24 * time.Hour),
+			EndedAt:      &endedAt, // Ended sensor
+			SensorType:   4,
+			DurationDays: 15,
+			DetectedAt:   now.Add(-22 * 24 * time.Hour),
+		},
+		{
+			SerialNumber: "SENSOR002",
+			Activation:   now.Add(-2 * 24 * time.Hour),
+			ExpiresAt:    now.Add(13 * 24 * time.Hour),
+			EndedAt:      nil, // Current sensor
+			SensorType:   4,
+			DurationDays: 15,
+			DetectedAt:   now.Add(-2 * 24 * time.Hour),
+		},
+	}
+	for _, s := range sensors {
+		if err := db.Create(s).Error; err != nil {
+			t.Fatalf("failed to insert sensor: %v", err)
+		}
+	}
+
+	// GET sensors (paginated list)
+	req := httptest.NewRequest("GET", "/v1/sensor", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var response api.SensorListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	// Verify all sensors are returned
+	if len(response.Data) != 2 {
+		t.Errorf("expected 2 sensors, got %d", len(response.Data))
+	}
+
+	if response.Pagination.Total != 2 {
+		t.Errorf("expected total 2, got %d", response.Pagination.Total)
+	}
+}
+
+// TestE2E_GetLatestSensor tests getting the current sensor
+func TestE2E_GetLatestSensor(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	now := time.Now().UTC()
+
+	// Insert current sensor
+	sensor := &domain.SensorConfig{
+		SerialNumber: "SENSOR001",
+		Activation:   now.Add(-2 * 24 * time.Hour),
+		ExpiresAt:    now.Add(12 * 24 * time.Hour),
+		EndedAt:      nil, // Current sensor
+		SensorType:   4,
+		DurationDays: 14,
+		DetectedAt:   now.Add(-2 * 24 * time.Hour),
+	}
+	if err := db.Create(sensor).Error; err != nil {
+		t.Fatalf("failed to insert sensor: %v", err)
+	}
+
+	// GET latest sensor
+	req := httptest.NewRequest("GET", "/v1/sensor/latest", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var response api.LatestSensorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if response.Data == nil {
+		t.Fatal("expected sensor data, got nil")
+	}
+
+	if response.Data.SerialNumber != "SENSOR001" {
+		t.Errorf("expected SENSOR001, got %s", response.Data.SerialNumber)
+	}
+
+	if response.Data.Status != "running" {
+		t.Errorf("expected status running, got %s", response.Data.Status)
+	}
+}
+
+// TestE2E_GetLatestSensor_NotFound tests getting latest sensor when none exists
+func TestE2E_GetLatestSensor_NotFound(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/v1/sensor/latest", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+// TestE2E_SchemaVersion tests response schema negotiation with X-GLCMD-API-Version
+func TestE2E_SchemaVersion(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	now := time.Now().UTC()
+	lastMeasurement := now.Add(-time.Minute)
+
+	// Expired sensor still reporting: in grace period
+	sensor := &domain.SensorConfig{
+		SerialNumber:      "SENSOR001",
+		Activation:        now.Add(-15 * 24 * time.Hour),
+		ExpiresAt:         now.Add(-time.Hour),
+		LastMeasurementAt: &lastMeasurement,
+		SensorType:        4,
+		DurationDays:      15,
+		DetectedAt:        now.Add(-15 * 24 * time.Hour),
+	}
+	if err := db.Create(sensor).Error; err != nil {
+		t.Fatalf("failed to insert sensor: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		header      string
+		wantVersion string
+		wantStatus  string
+	}{
+		{"no header gets legacy schema", "", "1", "running"},
+		{"version 1", "1", "1", "running"},
+		{"version 2", "2", "2", "grace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v1/sensor/latest", nil)
+			if tt.header != "" {
+				req.Header.Set(glclient.APIVersionHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", w.Code)
+			}
+			if got := w.Header().Get(glclient.APIVersionHeader); got != tt.wantVersion {
+				t.Errorf("expected schema version %s, got %q", tt.wantVersion, got)
+			}
+
+			var response api.LatestSensorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if response.Data.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s", tt.wantStatus, response.Data.Status)
+			}
+		})
+	}
+
+	t.Run("unsupported version", func(t *testing.T) {
+		for _, header := range []string{"0", "99", "two"} {
+			req := httptest.NewRequest("GET", "/v1/sensor/latest", nil)
+			req.Header.Set(glclient.APIVersionHeader, header)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%q: expected status 400, got %d", header, w.Code)
+			}
+		}
+	})
+}
+
+// TestE2E_SensorSite tests recording sensor application sites and the site history
+func TestE2E_SensorSite(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	putSite := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PUT", "/v1/sensor/latest/site", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	// No active sensor yet
+	if w := putSite(`{"site":"left-arm"}`); w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 without sensor, got %d", w.Code)
+	}
+
+	now := time.Now().UTC()
+	endedAt := now.Add(-2 * 24 * time.Hour)
+	sensors := []*domain.SensorConfig{
+		{
+			SerialNumber:    "SENSOR001",
+			Activation:      now.Add(-17 * 24 * time.Hour),
+			ExpiresAt:       now.Add(-2 * 24 * time.Hour),
+			EndedAt:         &endedAt,
+			SensorType:      4,
+			DurationDays:    15,
+			DetectedAt:      now.Add(-17 * 24 * time.Hour),
+			ApplicationSite: domain.SensorSiteLeftArm,
+		},
+		{
+			SerialNumber: "SENSOR002",
+			Activation:   now.Add(-2 * 24 * time.Hour),
+			ExpiresAt:    now.Add(13 * 24 * time.Hour),
+			SensorType:   4,
+			DurationDays: 15,
+			DetectedAt:   now.Add(-2 * 24 * time.Hour),
+		},
+	}
+	for _, sensor := range sensors {
+		if err := db.Create(sensor).Error; err != nil {
+			t.Fatalf("failed to insert sensor: %v", err)
+		}
+	}
+
+	if w := putSite(`{"site":"elbow"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown site, got %d", w.Code)
+	}
+
+	// Same site as the previous sensor
+	w := putSite(`{"site":"left-arm"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response api.SensorSiteResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Data.Sensor.SerialNumber != "SENSOR002" || response.Data.Sensor.ApplicationSite != domain.SensorSiteLeftArm {
+		t.Errorf("expected left-arm recorded on SENSOR002, got %+v", response.Data.Sensor)
+	}
+	if !response.Data.SiteReused || response.Data.PreviousSite != domain.SensorSiteLeftArm || response.Data.Warning == "" {
+		t.Errorf("expected reuse warning, got %+v", response.Data)
+	}
+
+	// Rotated site
+	w = putSite(`{"site":"right-arm"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	response = api.SensorSiteResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Data.SiteReused || response.Data.Warning != "" {
+		t.Errorf("expected no reuse warning, got %+v", response.Data)
+	}
+
+	// History, newest first
+	req := httptest.NewRequest("GET", "/v1/sensor/sites", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var sites api.SensorSitesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &sites); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(sites.Data) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(sites.Data))
+	}
+	if sites.Data[0].SerialNumber != "SENSOR002" || sites.Data[0].Site != domain.SensorSiteRightArm || sites.Data[0].Reused {
+		t.Errorf("unexpected newest entry: %+v", sites.Data[0])
+	}
+	if sites.Data[1].Site != domain.SensorSiteLeftArm {
+		t.Errorf("unexpected oldest entry: %+v", sites.Data[1])
+	}
+}
+
+func TestE2E_SensorFeedback(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	patch := func(serial, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PATCH", "/v1/sensor/"+serial, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	now := time.Now().UTC()
+	endedAt := now.Add(-2 * 24 * time.Hour)
+	sensor := &domain.SensorConfig{
+		SerialNumber: "SENSOR001",
+		Activation:   now.Add(-16 * 24 * time.Hour),
+		ExpiresAt:    now.Add(-1 * 24 * time.Hour),
+		EndedAt:      &endedAt,
+		SensorType:   4,
+		DurationDays: 15,
+		DetectedAt:   now.Add(-16 * 24 * time.Hour),
+	}
+	if err := db.Create(sensor).Error; err != nil {
+		t.Fatalf("failed to insert sensor: %v", err)
+	}
+
+	if w := patch("UNKNOWN", `{"rating":3}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown sensor, got %d", w.Code)
+	}
+	for _, body := range []string{`{}`, `{"rating":6}`, `{"rating":-1}`, `{"note":"` + strings.Repeat("x", domain.MaxSensorNoteLength+1) + `"}`} {
+		if w := patch("SENSOR001", body); w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400 for %.20s, got %d", body, w.Code)
+		}
+	}
+
+	w := patch("SENSOR001", `{"note":"Lifted at the edges after a week","rating":2}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.LatestSensorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Data.Note != "Lifted at the edges after a week" || response.Data.Rating == nil || *response.Data.Rating != 2 {
+		t.Errorf("unexpected feedback in response: %+v", response.Data)
+	}
+
+	// Included in the sensor list
+	req := httptest.NewRequest("GET", "/v1/sensor", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var list api.SensorListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Rating == nil || *list.Data[0].Rating != 2 {
+		t.Errorf("expected the rating in the sensor list, got %s", w.Body.String())
+	}
+
+	// And in the statistics report
+	req = httptest.NewRequest("GET", "/v1/sensor/stats", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var stats api.SensorStatisticsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if stats.Data.Statistics.RatedSensors != 1 || stats.Data.Statistics.AvgRating == nil || *stats.Data.Statistics.AvgRating != 2 {
+		t.Errorf("expected 1 rated sensor averaging 2, got %+v", stats.Data.Statistics)
+	}
+	if len(stats.Data.Statistics.Sensors) != 1 || stats.Data.Statistics.Sensors[0].Note == "" {
+		t.Errorf("expected the note in the sensor report, got %s", w.Body.String())
+	}
+
+	// A zero rating clears it and keeps the note
+	w = patch("SENSOR001", `{"rating":0}`)
+	response = api.LatestSensorResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Data.Rating != nil || response.Data.Note == "" {
+		t.Errorf("expected rating cleared and note kept, got %+v", response.Data)
+	}
+}
+
+// TestE2E_SensorStatistics_EndedPeriod tests filtering sensor statistics on the end of the sensors
+func TestE2E_SensorStatistics_EndedPeriod(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, serial := range []string{"SENSOR001", "SENSOR002"} {
+		activation := now.Add(time.Duration(-30+15*i) * 24 * time.Hour)
+		endedAt := activation.Add(14 * 24 * time.Hour)
+		sensor := &domain.SensorConfig{
+			SerialNumber: serial,
+			Activation:   activation,
+			ExpiresAt:    activation.Add(15 * 24 * time.Hour),
+			EndedAt:      &endedAt,
+			SensorType:   4,
+			DurationDays: 15,
+			DetectedAt:   activation,
+		}
+		if err := db.Create(sensor).Error; err != nil {
+			t.Fatalf("failed to insert sensor: %v", err)
+		}
+	}
+
+	endedStart := now.Add(-5 * 24 * time.Hour).Format(time.RFC3339)
+	endedEnd := now.Format(time.RFC3339)
+	req := httptest.NewRequest("GET", "/v1/sensor/stats?endedStart="+endedStart+"&endedEnd="+endedEnd, nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var stats api.SensorStatisticsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if stats.Data.Statistics.TotalSensors != 1 || len(stats.Data.Statistics.Sensors) != 1 || stats.Data.Statistics.Sensors[0].SerialNumber != "SENSOR002" {
+		t.Errorf("expected only SENSOR002, got %s", w.Body.String())
+	}
+	if stats.Data.EndedPeriod == nil || stats.Data.EndedPeriod.Start != endedStart || stats.Data.Period != nil {
+		t.Errorf("expected the end period only, got %+v / %+v", stats.Data.Period, stats.Data.EndedPeriod)
+	}
+
+	// Both bounds are required
+	req = httptest.NewRequest("GET", "/v1/sensor/stats?endedStart="+endedStart, nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without endedEnd, got %d", w.Code)
+	}
+}
+
+// TestE2E_SensorAttachments tests uploading, listing, downloading and deleting sensor photos
+func TestE2E_SensorAttachments(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	now := time.Now().UTC()
+	sensor := &domain.SensorConfig{
+		SerialNumber: "SENSOR001",
+		Activation:   now.Add(-2 * 24 * time.Hour),
+		ExpiresAt:    now.Add(13 * 24 * time.Hour),
+		SensorType:   4,
+		DurationDays: 15,
+		DetectedAt:   now.Add(-2 * 24 * time.Hour),
+	}
+	if err := db.Create(sensor).Error; err != nil {
+		t.Fatalf("failed to insert sensor: %v", err)
+	}
+
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	upload := func(serial, token, content string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, _ := mw.CreateFormFile("file", "rash.png")
+		fw.Write([]byte(content))
+		mw.WriteField("caption", "Redness on day 2")
+		mw.Close()
+
+		req := httptest.NewRequest("POST", "/v1/sensor/"+serial+"/attachments", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	// Health photos require a token
+	if w := upload("SENSOR001", "", png); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+	if w := adminRequest(server, "GET", "/v1/sensor/SENSOR001/attachments", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+
+	if w := upload("UNKNOWN", testAdminToken, png); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown sensor, got %d", w.Code)
+	}
+	if w := upload("SENSOR001", testAdminToken, "<svg onload=alert(1)>"); w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status 415 for a non-image, got %d", w.Code)
+	}
+	if w := upload("SENSOR001", testAdminToken, png+strings.Repeat("x", domain.MaxAttachmentSize)); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413 for a large file, got %d", w.Code)
+	}
+
+	w := upload("SENSOR001", testAdminToken, png)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created api.SensorAttachmentResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if created.Data.ContentType != "image/png" || created.Data.Caption != "Redness on day 2" || created.Data.URL != fmt.Sprintf("/v1/attachments/%d", created.Data.ID) {
+		t.Errorf("unexpected attachment: %+v", created.Data)
+	}
+
+	w = adminRequest(server, "GET", "/v1/sensor/SENSOR001/attachments", testAdminToken, "")
+	var list api.SensorAttachmentsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].FileName != "rash.png" {
+		t.Errorf("expected the uploaded attachment listed, got %s", w.Body.String())
+	}
+
+	if w := adminRequest(server, "GET", created.Data.URL, "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+	w = adminRequest(server, "GET", created.Data.URL, testAdminToken, "")
+	if w.Code != http.StatusOK || w.Body.String() != png {
+		t.Fatalf("expected the stored file, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Type") != "image/png" || w.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("unexpected headers: %v", w.Header())
+	}
+
+	if w := adminRequest(server, "DELETE", created.Data.URL, testAdminToken, ""); w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+	if w := adminRequest(server, "GET", created.Data.URL, testAdminToken, ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 after deletion, got %d", w.Code)
+	}
+}
+
+// TestE2E_ExerciseMode tests starting, reporting and ending exercise mode
+func TestE2E_ExerciseMode(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	modeRequest := func(method, path string) (*httptest.ResponseRecorder, *domain.ModeStatus) {
+		req := httptest.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+
+		var response api.ModeResponse
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+		}
+		return w, response.Data
+	}
+
+	w, status := modeRequest("GET", "/v1/mode")
+	if w.Code != http.StatusOK || status.Mode != domain.ModeNormal {
+		t.Fatalf("expected normal mode, got %d %+v", w.Code, status)
+	}
+
+	for _, duration := range []string{"", "soon", "30s", "13h"} {
+		if w, _ := modeRequest("POST", "/v1/mode/exercise?duration="+duration); w.Code != http.StatusBadRequest {
+			t.Errorf("duration %q: expected status 400, got %d", duration, w.Code)
+		}
+	}
+
+	before := time.Now().UTC()
+	w, status = modeRequest("POST", "/v1/mode/exercise?duration=1h")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if status.Mode != domain.ModeExercise || status.EndsAt == nil || status.EndsAt.Before(before.Add(time.Hour)) {
+		t.Errorf("expected exercise mode for 1h, got %+v", status)
+	}
+	if status.Thresholds != domain.ExerciseAlertThresholds() {
+		t.Errorf("expected exercise thresholds, got %+v", status.Thresholds)
+	}
+
+	if _, status := modeRequest("GET", "/v1/mode"); status.Mode != domain.ModeExercise {
+		t.Errorf("expected exercise mode reported, got %s", status.Mode)
+	}
+
+	w, status = modeRequest("DELETE", "/v1/mode/exercise")
+	if w.Code != http.StatusOK || status.Mode != domain.ModeNormal || status.EndsAt != nil {
+		t.Errorf("expected normal mode after ending exercise, got %d %+v", w.Code, status)
+	}
+}
+
+// TestE2E_AlertHistory tests the alert history, weekly counts and acknowledgement
+func TestE2E_AlertHistory(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	now := time.Now().UTC()
+	alerts := []*domain.Alert{
+		{FiredAt: now.Add(-2 * time.Hour), Type: domain.AlertTypeLow, ValueMgDl: 62, ThresholdMgDl: 70, Mode: domain.ModeNormal},
+		{FiredAt: now.Add(-time.Hour), Type: domain.AlertTypeFalling, ValueMgDl: 95, ThresholdMgDl: 70, Mode: domain.ModeNormal},
+	}
+	for _, alert := range alerts {
+		if err := db.Create(alert).Error; err != nil {
+			t.Fatalf("failed to insert alert: %v", err)
+		}
+	}
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("GET", "/v1/alerts/history?type=low")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var history api.AlertListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(history.Data) != 1 || history.Pagination.Total != 1 || history.Data[0].ValueMgDl != 62 {
+		t.Errorf("expected the low alert only, got %+v", history)
+	}
+
+	for _, query := range []string{"type=high", "acknowledged=maybe"} {
+		if w := request("GET", "/v1/alerts/history?"+query); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+
+	path := fmt.Sprintf("/v1/alerts/%d/ack", alerts[0].ID)
+	if w := request("POST", path); w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := request("POST", path); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 when acknowledging twice, got %d", w.Code)
+	}
+
+	w = request("GET", "/v1/alerts/history?acknowledged=false")
+	history = api.AlertListResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(history.Data) != 1 || history.Data[0].Type != domain.AlertTypeFalling {
+		t.Errorf("expected the falling alert unacknowledged, got %+v", history.Data)
+	}
+
+	w = request("GET", "/v1/alerts/weekly?weeks=2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var weekly api.AlertWeeklyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &weekly); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	total := 0
+	for _, week := range weekly.Data {
+		total += week.Total
+	}
+	if len(weekly.Data) != 2 || total != 2 {
+		t.Errorf("expected 2 alerts over 2 weeks, got %+v", weekly.Data)
+	}
+
+	if w := request("GET", "/v1/alerts/weekly?weeks=0"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for weeks=0, got %d", w.Code)
+	}
+}
+
+// TestE2E_GlucoseEvents tests detecting low and high events and listing them
+func TestE2E_GlucoseEvents(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	targets := &domain.GlucoseTargets{TargetLow: 70, TargetHigh: 180, UnitOfMeasure: domain.GlucoseUnitsMgDl}
+	if err := db.Create(targets).Error; err != nil {
+		t.Fatalf("failed to insert targets: %v", err)
+	}
+
+	// A 30-minute low three hours ago, then a 20-minute high still going on
+	now := time.Now().UTC().Truncate(time.Minute)
+	values := map[time.Duration]int{
+		-3 * time.Hour: 65, -165 * time.Minute: 54, -150 * time.Minute: 66,
+		-2 * time.Hour: 110, -time.Hour: 150,
+		-20 * time.Minute: 195, -10 * time.Minute: 230, 0: 210,
+	}
+	for offset, value := range values {
+		ts := now.Add(offset)
+		m := &domain.GlucoseMeasurement{FactoryTimestamp: ts, Timestamp: ts, Value: float64(value) / 18, ValueInMgPerDl: value, GlucoseColor: domain.GlucoseColorNormal, Type: domain.GlucoseTypeHistorical}
+		if err := db.Create(m).Error; err != nil {
+			t.Fatalf("failed to insert measurement: %v", err)
+		}
+	}
+
+	detector := service.NewGlucoseEventService(repository.NewGlucoseEventRepository(db), repository.NewGlucoseRepository(db), repository.NewTargetsRepository(db), repository.NewUnitOfWork(db), slog.Default())
+	if result, err := detector.DetectEvents(context.Background()); err != nil || result.Events != 2 {
+		t.Fatalf("expected 2 events detected, got %+v (%v)", result, err)
+	}
+
+	request := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("/v1/glucose/events")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var list api.GlucoseEventListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 2 || list.Pagination.Total != 2 {
+		t.Fatalf("expected 2 events, got %+v", list)
+	}
+	if high := list.Data[0]; high.Type != domain.GlucoseEventTypeHigh || !high.Ongoing || high.ExtremeMgDl != 230 || high.DurationMinutes != 20 {
+		t.Errorf("expected the ongoing high first, got %+v", high)
+	}
+
+	w = request("/v1/glucose/events?type=low")
+	list = api.GlucoseEventListResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].ExtremeMgDl != 54 || list.Data[0].DurationMinutes != 30 || list.Data[0].Readings != 3 {
+		t.Errorf("expected the 30-minute low with a nadir of 54 mg/dL, got %+v", list.Data)
+	}
+
+	if w := request("/v1/glucose/events?type=falling"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid type, got %d", w.Code)
+	}
+}
+
+// TestE2E_GlucoseDaily tests summarizing the days and listing the summaries
+func TestE2E_GlucoseDaily(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	targets := &domain.GlucoseTargets{TargetLow: 70, TargetHigh: 180, UnitOfMeasure: domain.GlucoseUnitsMgDl}
+	if err := db.Create(targets).Error; err != nil {
+		t.Fatalf("failed to insert targets: %v", err)
+	}
+
+	// Readings around noon of two past days, one day low and high
+	today := time.Now()
+	noon := time.Date(today.Year(), today.Month(), today.Day(), 12, 0, 0, 0, time.Local)
+	values := map[time.Time][]int{
+		noon.AddDate(0, 0, -10): {60, 100, 120, 200},
+		noon.AddDate(0, 0, -9):  {110, 130},
+	}
+	for day, dayValues := range values {
+		for i, value := range dayValues {
+			ts := day.Add(time.Duration(i) * 15 * time.Minute).UTC()
+			m := &domain.GlucoseMeasurement{FactoryTimestamp: ts, Timestamp: ts, Value: float64(value) / 18, ValueInMgPerDl: value, GlucoseColor: domain.GlucoseColorNormal, Type: domain.GlucoseTypeHistorical}
+			if err := db.Create(m).Error; err != nil {
+				t.Fatalf("failed to insert measurement: %v", err)
+			}
+		}
+	}
+
+	summarizer := service.NewDailySummaryService(repository.NewDailySummaryRepository(db), repository.NewGlucoseRepository(db), repository.NewTargetsRepository(db), slog.Default())
+	if run, err := summarizer.Summarize(context.Background()); err != nil || run.Days != 2 {
+		t.Fatalf("expected 2 days summarized, got %+v (%v)", run, err)
+	}
+
+	request := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("/v1/glucose/daily")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var list api.DailySummaryListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 2 {
+		t.Fatalf("expected 2 daily summaries, got %+v", list.Data)
+	}
+	first := list.Data[0]
+	if first.Date != noon.AddDate(0, 0, -10).Format(time.DateOnly) || first.Count != 4 || first.AverageMgDl != 120 {
+		t.Errorf("expected the oldest day first, got %+v", first)
+	}
+	if first.TimeInRange != 50 || first.LowCount != 1 || first.HighCount != 1 || first.GMI != 6.18 {
+		t.Errorf("expected 50%% in range with one low and one high, got %+v", first)
+	}
+
+	start := noon.AddDate(0, 0, -9).UTC().Format(time.RFC3339)
+	w = request("/v1/glucose/daily?start=" + start)
+	list = api.DailySummaryListResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Count != 2 || list.Data[0].TimeInRange != 100 {
+		t.Errorf("expected the summary of the latest day, got %+v", list.Data)
+	}
+
+	if w := request("/v1/glucose/daily?start=yesterday"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid start, got %d", w.Code)
+	}
+}
+
+// TestE2E_TreatmentImport tests importing a pump export and reading treatments back
+func TestE2E_TreatmentImport(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	now := time.Now().Local().Truncate(time.Minute)
+	export := "Type,BolusType,BolusRequestID,CompletionDateTime,InsulinDelivered,CarbSize\n" +
+		"Bolus,Standard,1," + now.Add(-2*time.Hour).Format("2006-01-02T15:04:05") + ",4.5,45\n" +
+		"Bolus,Standard,2,garbage,1,0\n"
+
+	importExport := func(source string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/v1/treatments/import?source="+source, strings.NewReader(export))
+		req.Header.Set("Content-Type", "text/csv")
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	w := importExport(domain.TreatmentSourceTandem)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result api.TreatmentImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if result.Data.Imported != 1 || result.Data.Skipped != 1 {
+		t.Errorf("expected 1 imported and 1 skipped, got %+v", result.Data)
+	}
+
+	// Re-importing the same export creates no duplicates
+	w = importExport(domain.TreatmentSourceTandem)
+	result = api.TreatmentImportResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if result.Data.Imported != 0 || result.Data.Duplicates != 1 {
+		t.Errorf("expected 1 duplicate on re-import, got %+v", result.Data)
+	}
+
+	// Unknown source, or an export that does not match the source
+	for _, source := range []string{"medtronic", domain.TreatmentSourceOmnipod} {
+		if w := importExport(source); w.Code != http.StatusBadRequest {
+			t.Errorf("source %q: expected status 400, got %d", source, w.Code)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/v1/treatments", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var list api.TreatmentListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Insulin != 4.5 || list.Data[0].Carbs == nil || *list.Data[0].Carbs != 45 {
+		t.Errorf("expected the imported bolus, got %+v", list.Data)
+	}
+}
+
+// TestE2E_TreatmentImportJob tests an import run as a background job polled on /v1/jobs/{id}
+func TestE2E_TreatmentImportJob(t *testing.T) {
+	server := setupE2EJobServer(t)
+
+	now := time.Now().Local().Truncate(time.Minute)
+	export := "Type,BolusType,BolusRequestID,CompletionDateTime,InsulinDelivered,CarbSize\n" +
+		"Bolus,Standard,1," + now.Add(-3*time.Hour).Format("2006-01-02T15:04:05") + ",2,20\n" +
+		"Bolus,Standard,2," + now.Add(-1*time.Hour).Format("2006-01-02T15:04:05") + ",3,30\n" +
+		"Bolus,Standard,3,garbage,1,0\n"
+
+	req := httptest.NewRequest("POST", "/v1/treatments/import?source=tandem&async=true", strings.NewReader(export))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var started api.JobResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &started); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if started.Data.ID == "" || started.Data.Type != "treatmentImport" {
+		t.Fatalf("expected a treatment import job, got %+v", started.Data)
+	}
+	if location := w.Header().Get("Location"); location != "/v1/jobs/"+started.Data.ID {
+		t.Errorf("expected Location of the job, got %q", location)
+	}
+
+	// Poll until the job is finished
+	var job struct {
+		Data struct {
+			Status string                  `json:"status"`
+			Done   int                     `json:"done"`
+			Total  int                     `json:"total"`
+			Errors []string                `json:"errors"`
+			Result api.TreatmentImportData `json:"result"`
+		} `json:"data"`
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		req = httptest.NewRequest("GET", "/v1/jobs/"+started.Data.ID, nil)
+		w = httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		if (job.Data.Status != "pending" && job.Data.Status != "running") || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if job.Data.Status != "succeeded" {
+		t.Fatalf("expected succeeded, got %s", w.Body.String())
+	}
+	if job.Data.Done != 2 || job.Data.Total != 2 {
+		t.Errorf("expected 2/2 done, got %d/%d", job.Data.Done, job.Data.Total)
+	}
+	if job.Data.Result.Imported != 2 || job.Data.Result.Skipped != 1 {
+		t.Errorf("expected 2 imported and 1 skipped, got %+v", job.Data.Result)
+	}
+	if len(job.Data.Errors) != 1 {
+		t.Errorf("expected the skipped row reported, got %v", job.Data.Errors)
+	}
+
+	// Canceling a finished job leaves it unchanged
+	req = httptest.NewRequest("DELETE", "/v1/jobs/"+started.Data.ID, nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"succeeded"`) {
+		t.Errorf("expected the succeeded job, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The job is listed, with the daily cleanup among the schedules
+	req = httptest.NewRequest("GET", "/v1/jobs?type=treatmentImport&status=succeeded", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var list api.JobListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if w.Code != http.StatusOK || len(list.Data) != 1 || list.Data[0].ID != started.Data.ID || list.Pagination.Total != 1 {
+		t.Errorf("expected the import job listed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/v1/jobs?status=done", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for status=done, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/v1/jobs/schedules", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"type":"jobCleanup"`) {
+		t.Errorf("expected the cleanup schedule, got %d: %s", w.Code, w.Body.String())
+	}
+
+	for _, method := range []string{"GET", "DELETE"} {
+		req = httptest.NewRequest(method, "/v1/jobs/unknown", nil)
+		w = httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s unknown job: expected status 404, got %d", method, w.Code)
+		}
+	}
+
+	req = httptest.NewRequest("POST", "/v1/treatments/import?source=tandem&async=maybe", strings.NewReader(export))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for async=maybe, got %d", w.Code)
+	}
+}
+
+func TestE2E_TreatmentAnalysis(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	// A correction bolus 4 hours ago, with glucose dropping from 220 to 160
+	bolusAt := time.Now().UTC().Add(-4 * time.Hour).Truncate(time.Minute)
+	bolus := &domain.TreatmentEntry{
+		Timestamp: bolusAt,
+		Type:      domain.TreatmentTypeBolus,
+		Source:    domain.TreatmentSourceTandem,
+		Insulin:   2,
+	}
+	if err := db.Create(bolus).Error; err != nil {
+		t.Fatalf("failed to insert test treatment: %v", err)
+	}
+	for ts := bolusAt.Add(-5 * time.Minute); ts.Before(bolusAt.Add(3 * time.Hour)); ts = ts.Add(15 * time.Minute) {
+		value := 220
+		if ts.After(bolusAt.Add(time.Hour)) {
+			value = 160
+		}
+		measurement := &domain.GlucoseMeasurement{
+			FactoryTimestamp: ts,
+			Timestamp:        ts,
+			ValueInMgPerDl:   value,
+			Type:             domain.GlucoseTypeHistorical,
+		}
+		if err := db.Create(measurement).Error; err != nil {
+			t.Fatalf("failed to insert test measurement: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/v1/treatments/analysis", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response api.TreatmentAnalysisResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	c := response.Data.Corrections
+	if c == nil || c.Count != 1 || c.DropPerUnitMgDl != 30 {
+		t.Errorf("expected one correction lowering glucose by 30 mg/dL per unit, got %+v", c)
+	}
+	if len(response.Data.Insights) != 1 {
+		t.Errorf("expected the correction insight, got %q", response.Data.Insights)
+	}
+
+	req = httptest.NewRequest("GET", "/v1/treatments/analysis?start=invalid", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid start, got %d", w.Code)
+	}
+}
+
+// TestE2E_Health tests health endpoint
+func TestE2E_Health(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var response api.HealthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if response.Data.Status != "healthy" {
+		t.Errorf("expected status healthy, got %s", response.Data.Status)
+	}
+
+	if !response.Data.DatabaseConnected {
+		t.Error("expected database connected")
+	}
+
+	if !response.Data.DataFresh {
+		t.Error("expected DataFresh = true")
+	}
+}
+
+// TestE2E_HealthListener tests the separate health-only listener
+func TestE2E_HealthListener(t *testing.T) {
+	db := openE2EDatabase(t, ":memory:")
+	server, _ := newE2EServer(t, db, nil, nil)
+	if server.HealthHandler() != nil {
+		t.Fatal("expected no health listener by default")
+	}
+	server.SetHealthPort(8081)
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	w := httptest.NewRecorder()
+	server.HealthHandler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var response api.HealthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Data.Status != "healthy" || !response.Data.DatabaseConnected {
+		t.Errorf("expected the API health response, got %+v", response.Data)
+	}
+
+	// Only /health is served
+	for _, path := range []string{"/metrics", "/v1/glucose"} {
+		w := httptest.NewRecorder()
+		server.HealthHandler().ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404 for %s, got %d", path, w.Code)
+		}
+	}
+}
+
+// TestE2E_Metrics tests metrics endpoint
+func TestE2E_Metrics(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var response api.MetricsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if response.Data.Goroutines <= 0 {
+		t.Errorf("expected positive goroutines count, got %d", response.Data.Goroutines)
+	}
+
+	if response.Data.Runtime.Version == "" {
+		t.Error("expected runtime version, got empty string")
+	}
+
+	if response.Data.Process.PID <= 0 {
+		t.Errorf("expected positive PID, got %d", response.Data.Process.PID)
+	}
+
+	// SSE metrics (broker is nil in tests)
+	if response.Data.SSE.Enabled {
+		t.Error("expected SSE.Enabled = false (no broker)")
+	}
+
+	if response.Data.SSE.Subscribers != 0 {
+		t.Errorf("expected SSE.Subscribers = 0, got %d", response.Data.SSE.Subscribers)
+	}
+
+	// Database pool stats (nil callback in tests)
+	if response.Data.Database != nil {
+		t.Error("expected Database = nil (no callback)")
+	}
+}
+
+// TestE2E_Metrics_Prometheus tests the Prometheus text format of /metrics
+func TestE2E_Metrics_Prometheus(t *testing.T) {
+	server, db := setupE2ETest(t)
+	insertLatestMeasurement(t, db, time.Unix(1773144060, 0), 123)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text;version=1.0.0;q=0.75,text/plain;version=0.0.4;q=0.5,*/*;q=0.1")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", w.Header().Get("Content-Type"))
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"# TYPE glcmd_fetch_cycles_total counter\nglcmd_fetch_cycles_total 3\n",
+		"glcmd_fetch_errors_total 1\n",
+		"# TYPE glcmd_fetch_duration_seconds histogram\n",
+		`glcmd_fetch_duration_seconds_bucket{le="+Inf"} 0`,
+		"# TYPE glcmd_glucose_mgdl gauge\nglcmd_glucose_mgdl 123\n",
+		"glcmd_glucose_timestamp_seconds 1.77314406e+09\n",
+		"# TYPE go_goroutines gauge\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+	// Broker and pool stats are disabled in the test server
+	if strings.Contains(body, "glcmd_sse_subscribers") || strings.Contains(body, "glcmd_db_") {
+		t.Errorf("expected no SSE or database metrics, got:\n%s", body)
+	}
+
+	// Explicit format parameter; JSON clients keep the JSON response
+	req = httptest.NewRequest("GET", "/metrics?format=prometheus", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), "glcmd_uptime_seconds") {
+		t.Error("expected ?format=prometheus to return the text format")
+	}
+
+	req = httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Accept", "application/json, text/plain, */*")
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if !json.Valid(w.Body.Bytes()) {
+		t.Error("expected JSON when the client accepts application/json")
+	}
+}
+
+// TestE2E_CORS_Preflight tests CORS preflight request
+func TestE2E_CORS_Preflight(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("OPTIONS", "/v1/glucose/latest", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+
+	// Verify CORS headers
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("expected CORS origin *, got %s", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	if methods := w.Header().Get("Access-Control-Allow-Methods"); !strings.Contains(methods, "PATCH") {
+		t.Errorf("expected PATCH in Access-Control-Allow-Methods (PATCH /v1/sensor/{serial}), got %q", methods)
+	}
+}
+
+// TestE2E_CORS_Headers tests CORS headers on actual request
+func TestE2E_CORS_Headers(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	// Verify CORS headers are present
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("expected CORS origin *, got %s", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
+
+// TestE2E_SyncManifest tests per-day checksums in the sync manifest
+func TestE2E_SyncManifest(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	ts := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		m := &domain.GlucoseMeasurement{
+			FactoryTimestamp: ts.Add(time.Duration(i) * 5 * time.Minute),
+			Timestamp:        ts.Add(time.Duration(i) * 5 * time.Minute),
+			Value:            5.5,
+			ValueInMgPerDl:   99,
+			GlucoseColor:     domain.GlucoseColorNormal,
+			Type:             domain.GlucoseTypeHistorical,
+		}
+		if err := db.Create(m).Error; err != nil {
+			t.Fatalf("failed to insert test measurement: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/v1/sync/manifest?start=2026-01-14T00:00:00Z&end=2026-01-16T00:00:00Z", nil)
+	req.Header.Set("Authorization", "Bearer "+testSyncToken)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response api.SyncManifestResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if len(response.Data.Days) != 1 {
+		t.Fatalf("expected 1 day, got %d", len(response.Data.Days))
+	}
+
+	day := response.Data.Days[0]
+	if day.Date != "2026-01-15" {
+		t.Errorf("expected date 2026-01-15, got %s", day.Date)
+	}
+	if day.GlucoseCount != 3 {
+		t.Errorf("expected 3 measurements, got %d", day.GlucoseCount)
+	}
+	if len(day.Hash) != 64 {
+		t.Errorf("expected 64-char hex hash, got %q", day.Hash)
+	}
+}
+
+// TestE2E_SyncManifest_RangeTooLarge tests the manifest window limit
+func TestE2E_SyncManifest_RangeTooLarge(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/v1/sync/manifest?start=2024-01-01T00:00:00Z&end=2026-01-01T00:00:00Z", nil)
+	req.Header.Set("Authorization", "Bearer "+testSyncToken)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+// TestE2E_SyncManifest_RequiresToken tests that the sync manifest is authenticated
+func TestE2E_SyncManifest_RequiresToken(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/v1/sync/manifest", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/v1/sync/manifest", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 with wrong token, got %d", w.Code)
+	}
+}
+
+// TestE2E_SyncExport_RequiresToken tests that the sync export is authenticated
+func TestE2E_SyncExport_RequiresToken(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/v1/sync/export?date=2026-01-15", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/v1/sync/export?date=2026-01-15", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 with wrong token, got %d", w.Code)
+	}
+}
+
+// TestE2E_SyncExport tests exporting a single day
+func TestE2E_SyncExport(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	ts := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	other := time.Date(2026, 1, 16, 10, 0, 0, 0, time.UTC)
+	for _, at := range []time.Time{ts, ts.Add(5 * time.Minute), other} {
+		m := &domain.GlucoseMeasurement{
+			FactoryTimestamp: at,
+			Timestamp:        at,
+			Value:            5.5,
+			ValueInMgPerDl:   99,
+			GlucoseColor:     domain.GlucoseColorNormal,
+			Type:             domain.GlucoseTypeHistorical,
+		}
+		if err := db.Create(m).Error; err != nil {
+			t.Fatalf("failed to insert test measurement: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/v1/sync/export?date=2026-01-15", nil)
+	req.Header.Set("Authorization", "Bearer "+testSyncToken)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response api.SyncExportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if len(response.Data.Measurements) != 2 {
+		t.Errorf("expected 2 measurements, got %d", len(response.Data.Measurements))
+	}
+	if !response.Data.Measurements[0].FactoryTimestamp.Before(response.Data.Measurements[1].FactoryTimestamp) {
+		t.Error("expected measurements ordered oldest first")
+	}
+}
+
+// TestE2E_DashboardConfig_Default tests that the default layout is returned before any save
+func TestE2E_DashboardConfig_Default(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/v1/dashboard/config", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var response api.DashboardConfigResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if len(response.Data.Cards) != len(domain.DashboardCardTypes) {
+		t.Errorf("expected %d default cards, got %d", len(domain.DashboardCardTypes), len(response.Data.Cards))
+	}
+	if response.Data.ChartRange != "24h" {
+		t.Errorf("expected default chart range 24h, got %s", response.Data.ChartRange)
+	}
+}
+
+// TestE2E_DashboardConfig_SaveAndGet tests persisting a custom layout
+func TestE2E_DashboardConfig_SaveAndGet(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	body := `{"cards":[{"type":"latest"},{"type":"chart","title":"Week"}],"chartRange":"7d","showThresholds":false,"unit":"mgdl","refreshSeconds":30}`
+	req := httptest.NewRequest("PUT", "/v1/dashboard/config", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/v1/dashboard/config", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	var response api.DashboardConfigResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if len(response.Data.Cards) != 2 || response.Data.Cards[1].Title != "Week" {
+		t.Errorf("unexpected cards: %+v", response.Data.Cards)
+	}
+	if response.Data.ChartRange != "7d" || response.Data.Unit != "mgdl" || response.Data.ShowThresholds {
+		t.Errorf("unexpected config: %+v", response.Data)
+	}
+}
+
+// TestE2E_DashboardConfig_Invalid tests dashboard layout validation
+func TestE2E_DashboardConfig_Invalid(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown card", `{"cards":[{"type":"weather"}],"chartRange":"24h","unit":"mmol","refreshSeconds":60}`},
+		{"bad range", `{"cards":[{"type":"latest"}],"chartRange":"forever","unit":"mmol","refreshSeconds":60}`},
+		{"bad unit", `{"cards":[{"type":"latest"}],"chartRange":"24h","unit":"kelvin","refreshSeconds":60}`},
+		{"unknown field", `{"cards":[{"type":"latest"}],"chartRange":"24h","unit":"mmol","refreshSeconds":60,"theme":"dark"}`},
+		{"no cards", `{"cards":[],"chartRange":"24h","unit":"mmol","refreshSeconds":60}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/v1/dashboard/config", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+// TestE2E_DeviceConfig tests the LibreLinkUp app settings, with the alarm
+// rules round-tripping through the database
+func TestE2E_DeviceConfig(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/v1/config/device", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 before the first fetch, got %d", w.Code)
+	}
+
+	device := &domain.DeviceInfo{
+		DeviceID:      "device-1",
+		DeviceTypeID:  40068,
+		AlarmsEnabled: true,
+		HighLimit:     250,
+		LowLimit:      70,
+		FixedLowAlarm: domain.FixedLowAlarmValues{MgPerDl: 60, MmolPerL: 3.3},
+		AlarmRules: domain.AlarmRules{
+			Configured:        true,
+			High:              domain.AlarmRule{Enabled: false, MgPerDl: 250, MmolPerL: 13.9, RepeatMinutes: 1440},
+			Low:               domain.AlarmRule{Enabled: true, MgPerDl: 70, MmolPerL: 3.9, RepeatMinutes: 1440},
+			UrgentLow:         domain.AlarmRule{Enabled: true, MgPerDl: 55, MmolPerL: 3.1, RepeatMinutes: 5},
+			SignalLossMinutes: 20,
+		},
+	}
+	if err := repository.NewDeviceRepository(db).Save(context.Background(), device); err != nil {
+		t.Fatalf("failed to save device: %v", err)
+	}
+
+	req = httptest.NewRequest("GET", "/v1/config/device", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response api.DeviceConfigResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Data.FixedLowAlarm != device.FixedLowAlarm {
+		t.Errorf("expected urgent low level %+v, got %+v", device.FixedLowAlarm, response.Data.FixedLowAlarm)
+	}
+	if response.Data.AlarmRules != device.AlarmRules {
+		t.Errorf("expected alarm rules %+v, got %+v", device.AlarmRules, response.Data.AlarmRules)
+	}
+}
+
+// TestE2E_Preferences_SaveAndGet tests persisting display preferences and
+// their use by the latest reading, the default dashboard layout and statistics
+func TestE2E_Preferences_SaveAndGet(t *testing.T) {
+	server, db := setupE2ETest(t)
+	insertLatestMeasurement(t, db, time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC), 120)
+
+	req := httptest.NewRequest("GET", "/v1/preferences", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	var prefs api.PreferencesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &prefs); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if prefs.Data.Unit != domain.DisplayUnitMmol || !prefs.Data.Emoji || prefs.Data.TightHighMgDl != 140 {
+		t.Errorf("expected default preferences, got %+v", prefs.Data)
+	}
+
+	body := `{"unit":"mgdl","timezone":"Europe/Zurich","emoji":false,"tightLowMgDl":70,"tightHighMgDl":110,"chartRange":"12h"}`
+	req = httptest.NewRequest("PUT", "/v1/preferences", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/v1/preferences", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if err := json.Unmarshal(w.Body.Bytes(), &prefs); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if prefs.Data.Unit != domain.DisplayUnitMgDl || prefs.Data.Emoji || prefs.Data.Timezone != "Europe/Zurich" {
+		t.Errorf("unexpected preferences: %+v", prefs.Data)
+	}
+
+	// Convenience field of the latest reading
+	req = httptest.NewRequest("GET", "/v1/glucose/latest", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var latest api.GlucoseResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &latest); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if latest.Display == nil || latest.Display.Value != "120 mg/dL" || latest.Display.LocalTime != "13:30" {
+		t.Errorf("unexpected display: %+v", latest.Display)
+	}
+
+	// Default dashboard layout
+	req = httptest.NewRequest("GET", "/v1/dashboard/config", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var dashboard api.DashboardConfigResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &dashboard); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if dashboard.Data.Unit != "mgdl" || dashboard.Data.ChartRange != "12h" {
+		t.Errorf("expected the default layout to follow the preferences, got %+v", dashboard.Data)
+	}
+
+	// Tight range band of the statistics
+	req = httptest.NewRequest("GET", "/v1/glucose/stats", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var stats api.StatisticsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	bands := stats.Data.Statistics.TargetBands
+	if len(bands) != 1 || bands[0].Name != domain.TargetBandTight || bands[0].HighMgDl != 110 || bands[0].Percent != 0 {
+		t.Errorf("expected the 70-110 tight band, got %+v", bands)
+	}
+}
+
+// TestE2E_Preferences_Invalid tests display preferences validation
+func TestE2E_Preferences_Invalid(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"bad unit", `{"unit":"kelvin","emoji":true,"tightLowMgDl":70,"tightHighMgDl":140,"chartRange":"24h"}`},
+		{"bad timezone", `{"unit":"mmol","timezone":"Mars/Olympus","emoji":true,"tightLowMgDl":70,"tightHighMgDl":140,"chartRange":"24h"}`},
+		{"inverted band", `{"unit":"mmol","emoji":true,"tightLowMgDl":140,"tightHighMgDl":70,"chartRange":"24h"}`},
+		{"bad range", `{"unit":"mmol","emoji":true,"tightLowMgDl":70,"tightHighMgDl":140,"chartRange":"forever"}`},
+		{"unknown field", `{"unit":"mmol","emoji":true,"tightLowMgDl":70,"tightHighMgDl":140,"chartRange":"24h","theme":"dark"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/v1/preferences", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+// TestE2E_SavedViews tests saving, running and deleting a saved view
+func TestE2E_SavedViews(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	for i, mgdl := range []int{65, 110, 190, 250} {
+		ts := time.Now().UTC().Add(time.Duration(-i) * time.Hour)
+		insertLatestMeasurement(t, db, ts, mgdl)
+	}
+	// Outside the view period
+	insertLatestMeasurement(t, db, time.Now().UTC().Add(-72*time.Hour), 300)
+
+	body := `{"description":"Highs of the last day","query":"value_mgdl > 180","period":"24h","aggregation":"list"}`
+	req := httptest.NewRequest("PUT", "/v1/views/highs", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/v1/views/highs/run", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var run api.ViewRunResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &run); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(run.Data.Measurements) != 2 || run.Pagination == nil || run.Pagination.Total != 2 {
+		t.Errorf("expected 2 highs, got %+v", run)
+	}
+
+	// Replacing the view as statistics
+	body = `{"query":"value_mgdl > 180","period":"24h","aggregation":"stats"}`
+	req = httptest.NewRequest("PUT", "/v1/views/highs", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/v1/views/highs/run", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	run = api.ViewRunResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &run); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if run.Data.Statistics == nil || run.Data.Statistics.Statistics.Count != 2 || run.Data.Statistics.Statistics.MinMgDl != 190 {
+		t.Errorf("expected statistics of 2 highs, got %+v", run.Data.Statistics)
+	}
+
+	req = httptest.NewRequest("GET", "/v1/views", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var list api.ViewListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Aggregation != domain.ViewAggregationStats {
+		t.Errorf("expected the replaced view, got %+v", list.Data)
+	}
+
+	req = httptest.NewRequest("DELETE", "/v1/views/highs", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/v1/views/highs/run", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 after delete, got %d", w.Code)
+	}
+}
+
+// TestE2E_SavedViews_Invalid tests saved view validation
+func TestE2E_SavedViews_Invalid(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"bad name", "/v1/views/Nights_Out", `{"aggregation":"list"}`},
+		{"bad query", "/v1/views/nights", `{"query":"glucose > 180"}`},
+		{"bad period", "/v1/views/nights", `{"period":"forever"}`},
+		{"bad aggregation", "/v1/views/nights", `{"aggregation":"sum"}`},
+		{"unknown field", "/v1/views/nights", `{"aggregation":"list","limit":10}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestE2E_Notes tests creating, listing, replacing and deleting notes
+func TestE2E_Notes(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	create := func(body string) *domain.Note {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/v1/notes", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var response api.NoteResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		if location := w.Header().Get("Location"); location != fmt.Sprintf("/v1/notes/%d", response.Data.ID) {
+			t.Errorf("unexpected Location %q", location)
+		}
+		return response.Data
+	}
+
+	pasta := create(`{"timestamp":"2026-03-01T19:30:00+01:00","text":"Pasta, 6U","tags":["meal","insulin","meal"]}`)
+	if !pasta.Timestamp.Equal(time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)) || len(pasta.Tags) != 2 || pasta.Tags[0] != domain.NoteTagInsulin {
+		t.Errorf("expected a UTC timestamp and sorted tags, got %+v", pasta)
+	}
+	run := create(`{"timestamp":"2026-03-02T07:00:00Z","text":"Morning run","tags":["exercise"]}`)
+	now := create(`{"text":"  Feeling low  "}`)
+	if now.Text != "Feeling low" || time.Since(now.Timestamp) > time.Minute || now.Tags == nil {
+		t.Errorf("expected a trimmed note at the current time, got %+v", now)
+	}
+
+	req := httptest.NewRequest("GET", "/v1/notes?tag=meal", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var list api.NoteListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].ID != pasta.ID || list.Pagination.Total != 1 {
+		t.Errorf("expected the meal note, got %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/v1/notes?start=2026-03-01T00:00:00Z&end=2026-03-03T00:00:00Z", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	list = api.NoteListResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 2 || list.Data[0].ID != run.ID {
+		t.Errorf("expected the 2 notes of the range, newest first, got %s", w.Body.String())
+	}
+
+	body := `{"timestamp":"2026-03-02T07:00:00Z","text":"Morning run, 10 km","tags":["exercise","meal"]}`
+	req = httptest.NewRequest("PUT", fmt.Sprintf("/v1/notes/%d", run.ID), strings.NewReader(body))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var updated api.NoteResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &updated); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if updated.Data.Text != "Morning run, 10 km" || !updated.Data.CreatedAt.Equal(run.CreatedAt) {
+		t.Errorf("expected the replaced note with its creation time, got %+v", updated.Data)
+	}
+
+	req = httptest.NewRequest("DELETE", fmt.Sprintf("/v1/notes/%d", pasta.ID), nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+
+	for _, method := range []string{"GET", "DELETE"} {
+		req = httptest.NewRequest(method, fmt.Sprintf("/v1/notes/%d", pasta.ID), nil)
+		w = httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404 on %s after delete, got %d", method, w.Code)
+		}
+	}
+	req = httptest.NewRequest("PUT", fmt.Sprintf("/v1/notes/%d", pasta.ID), strings.NewReader(body))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 on PUT after delete, got %d", w.Code)
+	}
+}
+
+// TestE2E_Notes_Invalid tests note validation
+func TestE2E_Notes_Invalid(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"no text", "POST", "/v1/notes", `{"tags":["meal"]}`},
+		{"blank text", "POST", "/v1/notes", `{"text":"   "}`},
+		{"long text", "POST", "/v1/notes", `{"text":"` + strings.Repeat("a", domain.MaxNoteTextLength+1) + `"}`},
+		{"unknown tag", "POST", "/v1/notes", `{"text":"Coffee","tags":["coffee"]}`},
+		{"bad timestamp", "POST", "/v1/notes", `{"text":"Coffee","timestamp":"yesterday"}`},
+		{"unknown field", "POST", "/v1/notes", `{"text":"Coffee","carbs":20}`},
+		{"replace without timestamp", "PUT", "/v1/notes/1", `{"text":"Coffee"}`},
+		{"bad id", "PUT", "/v1/notes/first", `{"text":"Coffee","timestamp":"2026-03-01T08:00:00Z"}`},
+		{"bad tag filter", "GET", "/v1/notes?tag=coffee", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestE2E_Logbook tests logging insulin and carbs and their daily totals in the statistics
+func TestE2E_Logbook(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	post := func(path, body string) {
+		t.Helper()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	at := func(hours int) string {
+		return day.Add(time.Duration(hours) * time.Hour).Format(time.RFC3339)
+	}
+
+	post("/v1/insulin", fmt.Sprintf(`{"timestamp":%q,"units":4.5}`, at(8)))
+	post("/v1/insulin", fmt.Sprintf(`{"timestamp":%q,"units":16,"type":"long"}`, at(22)))
+	post("/v1/carbs", fmt.Sprintf(`{"timestamp":%q,"grams":45}`, at(8)))
+	post("/v1/carbs", fmt.Sprintf(`{"timestamp":%q,"grams":20}`, at(30)))
+
+	start := url.QueryEscape(day.Format(time.RFC3339))
+	end := url.QueryEscape(day.AddDate(0, 0, 2).Format(time.RFC3339))
+
+	req := httptest.NewRequest("GET", "/v1/insulin?start="+start+"&end="+end, nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var insulin api.InsulinListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &insulin); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(insulin.Data) != 2 || insulin.Data[0].Type != domain.InsulinTypeRapid || insulin.Data[1].Units != 16 {
+		t.Errorf("expected the 2 doses oldest first, got %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/v1/carbs?start="+start+"&end="+end, nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var carbs api.CarbListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &carbs); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(carbs.Data) != 2 || carbs.Data[0].Grams != 45 {
+		t.Errorf("expected the 2 intakes oldest first, got %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/v1/glucose/stats?start="+start+"&end="+end, nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var stats api.StatisticsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	totals := stats.Data.DailyTotals
+	if len(totals) != 2 {
+		t.Fatalf("expected 2 daily totals, got %s", w.Body.String())
+	}
+	if totals[0].InsulinUnits != 20.5 || totals[0].LongUnits != 16 || totals[0].CarbsGrams != 45 || totals[1].CarbsGrams != 20 {
+		t.Errorf("unexpected daily totals: %+v %+v", totals[0], totals[1])
+	}
+
+	invalid := []struct{ path, body string }{
+		{"/v1/insulin", `{"units":0}`},
+		{"/v1/insulin", `{"units":150}`},
+		{"/v1/insulin", `{"units":2,"type":"mixed"}`},
+		{"/v1/carbs", `{"grams":-10}`},
+		{"/v1/carbs", `{"grams":30,"note":"pizza"}`},
+	}
+	for _, tc := range invalid {
+		req := httptest.NewRequest("POST", tc.path, strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: expected status 400, got %d: %s", tc.path, tc.body, w.Code, w.Body.String())
+		}
+	}
+}
+
+// TestE2E_Capabilities tests feature discovery
+func TestE2E_Capabilities(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/v1/capabilities", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var response api.CapabilitiesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if response.Data.APIVersion != "v1" {
+		t.Errorf("expected apiVersion v1, got %q", response.Data.APIVersion)
+	}
+	if response.Data.SchemaVersion != glclient.SchemaVersion || len(response.Data.SchemaVersions) < 2 {
+		t.Errorf("expected schema version %d and a previous one, got %d %v",
+			glclient.SchemaVersion, response.Data.SchemaVersion, response.Data.SchemaVersions)
+	}
+
+	features := response.Data.Features
+	// Test server has no event broker but a sync token
+	if features[api.FeatureSSE].Enabled {
+		t.Error("expected sse to be disabled")
+	}
+	if !features[api.FeatureSyncExport].Enabled || features[api.FeatureSyncExport].Version != 1 {
+		t.Errorf("expected syncExport v1 enabled, got %+v", features[api.FeatureSyncExport])
+	}
+	if features[api.FeatureAuth].Enabled {
+		t.Error("expected auth to be disabled without API tokens")
+	}
+	for _, name := range []string{api.FeatureWebSocket, api.FeatureWebhooks, api.FeaturePredictions} {
+		capability, ok := features[name]
+		if !ok {
+			t.Errorf("expected feature %q to be listed", name)
+		}
+		if capability.Enabled {
+			t.Errorf("expected feature %q to be disabled", name)
+		}
+	}
+}
+
+func TestE2E_OpenAPI(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	req := httptest.NewRequest("GET", "/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var spec api.OpenAPISpec
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("failed to parse specification: %v", err)
+	}
+	if spec.OpenAPI != "3.0.3" {
+		t.Errorf("expected OpenAPI 3.0.3, got %q", spec.OpenAPI)
+	}
+
+	for path, method := range map[string]string{
+		"/health":                "get",
+		"/metrics":               "get",
+		"/v1/glucose":            "get",
+		"/v1/glucose/latest":     "get",
+		"/v1/sensor/stats":       "get",
+		"/v1/sensor/latest/site": "put",
+		"/v1/sensor/{serial}":    "patch",
+		"/v1/stream":             "get",
+	} {
+		if spec.Paths[path][method] == nil {
+			t.Errorf("expected %s %s to be described", method, path)
+		}
+	}
+
+	// Schemas follow the response types
+	stats := spec.Components.Schemas["SensorStatisticsData"]
+	if stats == nil || stats.Properties["endedPeriod"] == nil {
+		t.Fatalf("expected SensorStatisticsData with endedPeriod, got %+v", stats)
+	}
+	if slices.Contains(stats.Required, "endedPeriod") {
+		t.Error("expected the omitempty endedPeriod not to be required")
+	}
+	measurement := spec.Components.Schemas["GlucoseMeasurement"]
+	if measurement == nil || measurement.Properties["timestamp"] == nil || measurement.Properties["timestamp"].Format != "date-time" {
+		t.Errorf("expected GlucoseMeasurement with a date-time timestamp, got %+v", measurement)
+	}
+
+	// Every reference resolves
+	var refs []string
+	collectRefs(w.Body.Bytes(), &refs)
+	for _, ref := range refs {
+		if spec.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")] == nil {
+			t.Errorf("unresolved reference %s", ref)
+		}
+	}
+
+	req = httptest.NewRequest("GET", "/v1/docs", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "openapi.json") {
+		t.Errorf("expected the Swagger UI page, got %d", w.Code)
+	}
+}
+
+// collectRefs appends the $ref values of a JSON document to refs.
+func collectRefs(data []byte, refs *[]string) {
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, value := range v {
+				if ref, ok := value.(string); ok && key == "$ref" {
+					*refs = append(*refs, ref)
+				}
+				walk(value)
+			}
+		case []any:
+			for _, value := range v {
+				walk(value)
+			}
+		}
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err == nil {
+		walk(doc)
+	}
+}
+
+// adminRequest performs a request against the admin API with the given bearer token
+func adminRequest(server http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	return w
+}
+
+// TestE2E_APITokens tests the static API tokens protecting the data endpoints
+func TestE2E_APITokens(t *testing.T) {
+	const readToken = "dashboard-token-0123456789"
+	const writeToken = "scripts-token-0123456789"
+	server, _ := setupE2EServer(t, nil, map[string]string{
+		readToken:  domain.APIScopeRead,
+		writeToken: domain.APIScopeWrite,
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"missing token", "GET", "/v1/glucose", "", http.StatusUnauthorized},
+		{"unknown token", "GET", "/v1/glucose", "wrong-token-0123456789", http.StatusUnauthorized},
+		{"read token reads", "GET", "/v1/glucose", readToken, http.StatusOK},
+		{"read token writes", "POST", "/v1/mode/exercise?duration=1h", readToken, http.StatusForbidden},
+		{"write token reads", "GET", "/v1/mode", writeToken, http.StatusOK},
+		{"write token writes", "POST", "/v1/mode/exercise?duration=1h", writeToken, http.StatusOK},
+		{"admin token writes", "DELETE", "/v1/mode/exercise", testAdminToken, http.StatusOK},
+		{"long-poll needs token", "GET", "/v1/glucose/latest", "", http.StatusUnauthorized},
+		{"read token reads attachments", "GET", "/v1/attachments/999", readToken, http.StatusNotFound},
+		{"read token uploads attachments", "POST", "/v1/sensor/SENSOR001/attachments", readToken, http.StatusForbidden},
+		{"read token deletes attachments", "DELETE", "/v1/attachments/999", readToken, http.StatusForbidden},
+		{"write token deletes attachments", "DELETE", "/v1/attachments/999", writeToken, http.StatusNotFound},
+		{"discovery stays open", "GET", "/v1/capabilities", "", http.StatusOK},
+		{"health stays open", "GET", "/health", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := adminRequest(server, tt.method, tt.path, tt.token, ""); w.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// Issued read-scoped tokens keep working for reads
+	w := adminRequest(server, "POST", "/v1/admin/tokens", testAdminToken, `{"name":"kiosk","scope":"read"}`)
+	var created api.CreatedTokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if w := adminRequest(server, "GET", "/v1/glucose", created.Data.Token, ""); w.Code != http.StatusOK {
+		t.Errorf("expected issued read token to read, got %d", w.Code)
+	}
+	if w := adminRequest(server, "POST", "/v1/mode/exercise?duration=1h", created.Data.Token, ""); w.Code != http.StatusForbidden {
+		t.Errorf("expected issued read token to be denied writes, got %d", w.Code)
+	}
+	if w := adminRequest(server, "DELETE", "/v1/attachments/999", created.Data.Token, ""); w.Code != http.StatusForbidden {
+		t.Errorf("expected issued read token to be denied attachment deletion, got %d", w.Code)
+	}
+
+	w = adminRequest(server, "GET", "/v1/capabilities", "", "")
+	var capabilities api.CapabilitiesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &capabilities); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !capabilities.Data.Features[api.FeatureAuth].Enabled {
+		t.Error("expected auth to be enabled with API tokens")
+	}
+}
+
+// TestE2E_AdminTokens_RequireAuth tests that admin endpoints need an admin token
+func TestE2E_AdminTokens_RequireAuth(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	if w := adminRequest(server, "GET", "/v1/admin/tokens", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+	if w := adminRequest(server, "GET", "/v1/admin/tokens", "wrong", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 with wrong token, got %d", w.Code)
+	}
+	if w := adminRequest(server, "GET", "/v1/admin/tokens", testAdminToken, ""); w.Code != http.StatusOK {
+		t.Errorf("expected status 200 with admin token, got %d", w.Code)
+	}
+}
+
+// TestE2E_AdminTokens_Lifecycle tests creating, using, listing and revoking a token
+func TestE2E_AdminTokens_Lifecycle(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	// Create a sync-scoped token
+	w := adminRequest(server, "POST", "/v1/admin/tokens", testAdminToken, `{"name":"offsite","scope":"sync","expiresIn":"30d"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created api.CreatedTokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	token := created.Data.Token
+	if token == "" || created.Data.ExpiresAt == nil || created.Data.Scope != domain.TokenScopeSync {
+		t.Fatalf("unexpected created token: %s", w.Body.String())
+	}
+
+	// The issued token grants sync access but not admin access
+	if w := adminRequest(server, "GET", "/v1/sync/export?date=2026-01-01", token, ""); w.Code != http.StatusOK {
+		t.Errorf("expected sync export to accept the token, got %d", w.Code)
+	}
+	if w := adminRequest(server, "GET", "/v1/admin/tokens", token, ""); w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 for a sync token on admin API, got %d", w.Code)
+	}
+
+	// Listing shows the last use but never the token itself
+	w = adminRequest(server, "GET", "/v1/admin/tokens", testAdminToken, "")
+	if strings.Contains(w.Body.String(), token) {
+		t.Error("token list must not contain plaintext tokens")
+	}
+	var list api.TokenListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].LastUsedAt == nil {
+		t.Fatalf("expected 1 used token, got %s", w.Body.String())
+	}
+
+	// Revoke takes effect immediately
+	path := fmt.Sprintf("/v1/admin/tokens/%d", list.Data[0].ID)
+	if w := adminRequest(server, "DELETE", path, testAdminToken, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+	if w := adminRequest(server, "GET", "/v1/sync/export?date=2026-01-01", token, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected revoked token to be rejected, got %d", w.Code)
+	}
+	if w := adminRequest(server, "DELETE", path, testAdminToken, ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 when revoking twice, got %d", w.Code)
+	}
+}
+
+// TestE2E_AdminTokens_InvalidRequest tests token creation validation
+func TestE2E_AdminTokens_InvalidRequest(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	bodies := []string{
+		`{"name":"x","scope":"root"}`,
+		`{"scope":"read"}`,
+		`{"name":"x","scope":"read","expiresIn":"soon"}`,
+	}
+	for _, body := range bodies {
+		if w := adminRequest(server, "POST", "/v1/admin/tokens", testAdminToken, body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, w.Code)
+		}
+	}
+}
+
+// TestE2E_Deprecations tests the deprecations listed in the capabilities and
+// the CHANGELOG
+func TestE2E_Deprecations(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/v1/capabilities", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"deprecations":[`) {
+		t.Errorf("expected the deprecations as a list, got %s", w.Body.String())
+	}
+	var capabilities api.CapabilitiesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &capabilities); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	// Every deprecation is announced in the CHANGELOG
+	changelog, err := os.ReadFile(filepath.Join("..", "..", "CHANGELOG.md"))
+	if err != nil {
+		t.Fatalf("failed to read the CHANGELOG: %v", err)
+	}
+	for _, d := range capabilities.Data.Deprecations {
+		if d.Migration == "" || d.Removal == "" {
+			t.Errorf("expected a migration and a removal release, got %+v", d)
+		}
+		if !bytes.Contains(changelog, []byte("`"+d.ID+"`")) {
+			t.Errorf("expected deprecation %q in the CHANGELOG", d.ID)
+		}
+	}
+}
+
+// TestE2E_AdminLogs tests exporting recent logs from memory
+func TestE2E_AdminLogs(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	if w := adminRequest(server, "GET", "/v1/admin/logs", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+
+	// A logged request to find in the export
+	req := httptest.NewRequest("GET", "/v1/glucose/stats", nil)
+	server.ServeHTTP(httptest.NewRecorder(), req)
+
+	w := adminRequest(server, "GET", "/v1/admin/logs?since=5m&level=info", testAdminToken, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.LogsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	found := false
+	for _, entry := range response.Data {
+		if entry.Message == "api request" && entry.Attrs["path"] == "/v1/glucose/stats" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected the stats request in the logs, got %+v", response.Data)
+	}
+
+	for _, query := range []string{"since=-1h", "since=yesterday", "level=verbose"} {
+		if w := adminRequest(server, "GET", "/v1/admin/logs?"+query, testAdminToken, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+// TestE2E_FetchStats tests the fetch cycle counters in the admin endpoint and the metrics
+func TestE2E_FetchStats(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	if w := adminRequest(server, "GET", "/v1/admin/fetch-stats", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+
+	w := adminRequest(server, "GET", "/v1/admin/fetch-stats", testAdminToken, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.FetchStatsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Data.Cycles != 3 || response.Data.Failed != 1 || response.Data.Reauthentications != 1 {
+		t.Errorf("unexpected fetch stats: %+v", response.Data)
+	}
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	var metrics api.MetricsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &metrics); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if metrics.Data.Fetch == nil || metrics.Data.Fetch.MeasurementsStored != 2 {
+		t.Errorf("expected the fetch stats in the metrics, got %+v", metrics.Data.Fetch)
+	}
+}
+
+// TestE2E_AcceptTerms tests the admin endpoint accepting the LibreView terms
+func TestE2E_AcceptTerms(t *testing.T) {
+	server, _ := newE2EServer(t, openE2EDatabase(t, ":memory:"), nil, nil)
+	handler := server.HTTPHandler()
+	path := "/v1/admin/upstream/accept-terms"
+
+	if w := adminRequest(handler, "POST", path, testAdminToken, ""); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 without daemon, got %d", w.Code)
+	}
+
+	accepted := 0
+	pending := false
+	server.SetTermsAcceptor(func() error {
+		if !pending {
+			return daemon.ErrNoPendingTerms
+		}
+		accepted++
+		return nil
+	})
+
+	if w := adminRequest(handler, "POST", path, "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+	if w := adminRequest(handler, "POST", path, testAdminToken, ""); w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 without pending terms, got %d", w.Code)
+	}
+
+	pending = true
+	if w := adminRequest(handler, "POST", path, testAdminToken, ""); w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	if accepted != 1 {
+		t.Errorf("expected the terms to be accepted once, got %d", accepted)
+	}
+}
+
+// TestE2E_StartBackfill tests starting a history backfill job
+func TestE2E_StartBackfill(t *testing.T) {
+	server, jobManager := newE2EServer(t, openE2EDatabase(t, ":memory:"), nil, nil)
+	handler := server.HTTPHandler()
+	path := "/v1/admin/backfill"
+
+	if w := adminRequest(handler, "POST", path, testAdminToken, `{"days":30}`); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 without backfill job, got %d", w.Code)
+	}
+
+	jobManager.Register(backfill.JobType, func(ctx context.Context, job *domain.Job, p *jobs.Progress) (any, error) {
+		return nil, nil
+	}, jobs.Options{})
+
+	if w := adminRequest(handler, "POST", path, "", `{"days":30}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+	for _, body := range []string{`{"days":0}`, `{"days":366}`, `{"since":"2024-01-01"}`} {
+		if w := adminRequest(handler, "POST", path, testAdminToken, body); w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400 for %s, got %d", body, w.Code)
+		}
+	}
+
+	w := adminRequest(handler, "POST", path, testAdminToken, `{"days":30}`)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var started api.JobResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &started); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if started.Data.Type != backfill.JobType || started.Data.Status != domain.JobStatusPending {
+		t.Errorf("expected a pending backfill job, got %+v", started.Data)
+	}
+	if location := w.Header().Get("Location"); location != "/v1/jobs/"+started.Data.ID {
+		t.Errorf("expected Location of the job, got %q", location)
+	}
+}
+
+// TestE2E_StorageForecast tests the database growth forecast of the admin endpoint
+func TestE2E_StorageForecast(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	// Two days of readings every 15 minutes
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 1; i <= 192; i++ {
+		insertLatestMeasurement(t, db, now.Add(-time.Duration(i)*15*time.Minute), 110)
+	}
+
+	if w := adminRequest(server, "GET", "/v1/admin/storage", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+
+	w := adminRequest(server, "GET", "/v1/admin/storage", testAdminToken, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.StorageForecastResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	forecast := response.Data
+	// The rates are measured until the request, shortly after now
+	if forecast.Measurements != 192 || forecast.BytesPerRow != 1000 || forecast.MeasurementsPerDay != 96 || math.Abs(forecast.BytesPerDay-96000) > 100 {
+		t.Errorf("unexpected growth: %+v", forecast)
+	}
+	if len(forecast.Projections) != 2 || forecast.Projections[0].Years != 1 || forecast.Projections[0].Measurements != 192+96*365 {
+		t.Errorf("unexpected projections: %+v", forecast.Projections)
+	}
+}
+
+// TestE2E_PrivacyExportAndErase tests the personal data export and the confirmed erasure
+func TestE2E_PrivacyExportAndErase(t *testing.T) {
+	server, db := setupE2ETest(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	insertLatestMeasurement(t, db, now.Add(-time.Minute), 110)
+	insertLatestMeasurement(t, db, now, 120)
+
+	for _, path := range []string{"/v1/privacy/export", "/v1/privacy/erase/confirmation"} {
+		method := "GET"
+		if path != "/v1/privacy/export" {
+			method = "POST"
+		}
+		if w := adminRequest(server, method, path, "", ""); w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected status 401 without token, got %d", path, w.Code)
+		}
+	}
+
+	w := adminRequest(server, "GET", "/v1/privacy/export", testAdminToken, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var export api.PrivacyExportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &export); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if export.Data.Version != service.PrivacyExportVersion || len(export.Data.Measurements) != 2 {
+		t.Fatalf("expected 2 exported measurements, got %s", w.Body.String())
+	}
+	if export.Data.Measurements[0].ValueInMgPerDl != 110 {
+		t.Errorf("expected oldest measurement first, got %d", export.Data.Measurements[0].ValueInMgPerDl)
+	}
+
+	// Erasure requires a confirmation token
+	for _, body := range []string{`{}`, `{"confirmationToken":"guess"}`} {
+		if w := adminRequest(server, "POST", "/v1/privacy/erase", testAdminToken, body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, w.Code)
+		}
+	}
+
+	w = adminRequest(server, "POST", "/v1/privacy/erase/confirmation", testAdminToken, "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var confirmation api.ErasureConfirmationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &confirmation); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	w = adminRequest(server, "POST", "/v1/privacy/erase", testAdminToken, `{"confirmationToken":"`+confirmation.Data.Token+`"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var erased api.ErasureResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &erased); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if erased.Data.Deleted["measurements"] != 2 {
+		t.Errorf("expected 2 erased measurements, got %v", erased.Data.Deleted)
+	}
+
+	var count int64
+	db.Model(&domain.GlucoseMeasurement{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected no measurements after erasure, got %d", count)
+	}
+
+	// The admin token still works after erasure
+	if w := adminRequest(server, "GET", "/v1/privacy/export", testAdminToken, ""); w.Code != http.StatusOK {
+		t.Errorf("expected status 200 after erasure, got %d", w.Code)
+	}
+}
+
+// TestE2E_SigningKeys_Lifecycle tests signing key rotation through the admin API
+func TestE2E_SigningKeys_Lifecycle(t *testing.T) {
+	server, _ := setupE2ETest(t)
+
+	if w := adminRequest(server, "POST", "/v1/admin/keys", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+
+	w := adminRequest(server, "POST", "/v1/admin/keys", testAdminToken, "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created api.CreatedSigningKeyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if created.Data.Secret == "" || created.Data.KeyID == "" {
+		t.Fatalf("expected key id and secret, got %s", w.Body.String())
+	}
+
+	w = adminRequest(server, "GET", "/v1/admin/keys", testAdminToken, "")
+	if strings.Contains(w.Body.String(), created.Data.Secret) {
+		t.Error("key list must not contain secrets")
+	}
+	var list api.SigningKeyListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 1 {
+		t.Fatalf("expected 1 key, got %d", len(list.Data))
+	}
+
+	path := fmt.Sprintf("/v1/admin/keys/%d", created.Data.ID)
+	if w := adminRequest(server, "DELETE", path, testAdminToken, ""); w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+	if w := adminRequest(server, "DELETE", path, testAdminToken, ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 when retiring twice, got %d", w.Code)
+	}
+}
+
+// TestE2E_SSE_SignedEvents tests the optional signature field on SSE events
+func TestE2E_SSE_SignedEvents(t *testing.T) {
+	broker := events.NewBroker(10, slog.Default())
+	defer broker.Stop()
+
+	handler, _ := setupE2ETestWithBroker(t, broker)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	// Create a signing key
+	w := adminRequest(handler, "POST", "/v1/admin/keys", testAdminToken, "")
+	var created api.CreatedSigningKeyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL+"/v1/stream?types=glucose&signed=true", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to connect to stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	for broker.SubscriberCount() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	broker.Publish(events.Event{Type: events.EventTypeGlucose, Data: &domain.GlucoseMeasurement{ValueInMgPerDl: 120}})
+
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && scanner.Text() != "" {
+		name, value, _ := strings.Cut(scanner.Text(), ": ")
+		fields[name] = value
+	}
+
+	if fields["event"] != "glucose" || fields["signature"] == "" {
+		t.Fatalf("expected a signed glucose event, got %v", fields)
+	}
+	secrets := map[string]string{created.Data.KeyID: created.Data.Secret}
+	if err := glclient.Verify(fields["signature"], secrets, fields["event"], []byte(fields["data"]), time.Minute); err != nil {
+		t.Errorf("signature verification failed: %v", err)
+	}
+}
+
+// TestE2E_SSEClients tests listing and disconnecting event stream subscribers
+func TestE2E_SSEClients(t *testing.T) {
+	broker := events.NewBroker(10, slog.Default())
+	defer broker.Stop()
+
+	handler, _ := setupE2ETestWithBroker(t, broker)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL+"/v1/stream?types=glucose", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to connect to stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	for broker.SubscriberCount() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	broker.Publish(events.Event{Type: events.EventTypeGlucose, Data: &domain.GlucoseMeasurement{ValueInMgPerDl: 120}})
+
+	if w := adminRequest(handler, "GET", "/v1/admin/sse", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got %d", w.Code)
+	}
+
+	w := adminRequest(handler, "GET", "/v1/admin/sse", testAdminToken, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var list api.SSEClientListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Delivered != 1 || len(list.Data[0].Types) != 1 {
+		t.Fatalf("expected one glucose subscriber with 1 delivered event, got %s", w.Body.String())
+	}
+
+	// Disconnecting ends the stream
+	path := "/v1/admin/sse/" + list.Data[0].ID
+	if w := adminRequest(handler, "DELETE", path, testAdminToken, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		t.Errorf("expected the stream to end cleanly, got %v", err)
+	}
+	if w := adminRequest(handler, "DELETE", path, testAdminToken, ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for a disconnected client, got %d", w.Code)
 	}
 }
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// handleGetSensorAttachments handles GET /v1/sensor/{serial}/attachments
+// Returns the attachments of a sensor, oldest first.
+func (s *Server) handleGetSensorAttachments(w http.ResponseWriter, r *http.Request) {
+	if s.attachmentService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Sensor attachments not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	attachments, err := s.attachmentService.ListAttachments(ctx, chi.URLParam(r, "serial"))
+	if err != nil {
+		if errors.Is(err, persistence.ErrNotFound) {
+			writeJSONError(w, http.StatusNotFound, "Sensor not found")
+			return
+		}
+		handleError(w, err, s.logger)
+		return
+	}
+
+	data := make([]*AttachmentResponse, 0, len(attachments))
+	for _, a := range attachments {
+		data = append(data, NewAttachmentResponse(a))
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, SensorAttachmentsResponse{Data: data}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleUploadSensorAttachment handles POST /v1/sensor/{serial}/attachments
+// Stores a photo (JPEG, PNG or WebP) uploaded as multipart/form-data.
+func (s *Server) handleUploadSensorAttachment(w http.ResponseWriter, r *http.Request) {
+	if s.attachmentService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Sensor attachments not available")
+		return
+	}
+
+	upload, err := parseAttachmentUpload(w, r)
+	if err != nil {
+		s.handleAttachmentError(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	attachment, err := s.attachmentService.AddAttachment(ctx, chi.URLParam(r, "serial"), upload.FileName, upload.Caption, upload.Data)
+	if err != nil {
+		s.handleAttachmentError(w, err)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusCreated, SensorAttachmentResponse{Data: NewAttachmentResponse(attachment)}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetAttachment handles GET /v1/attachments/{id}
+// Serves the stored file.
+func (s *Server) handleGetAttachment(w http.ResponseWriter, r *http.Request) {
+	if s.attachmentService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Sensor attachments not available")
+		return
+	}
+
+	id, err := parseIDParam(chi.URLParam(r, "id"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	attachment, file, err := s.attachmentService.OpenAttachment(ctx, id)
+	if err != nil {
+		if errors.Is(err, persistence.ErrNotFound) {
+			writeJSONError(w, http.StatusNotFound, "Attachment not found")
+			return
+		}
+		handleError(w, err, s.logger)
+		return
+	}
+	defer file.Close()
+
+	// The stored type was detected at upload: never let the browser guess
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName}))
+	http.ServeContent(w, r, "", attachment.CreatedAt, file)
+}
+
+// handleDeleteAttachment handles DELETE /v1/attachments/{id}
+func (s *Server) handleDeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	if s.attachmentService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Sensor attachments not available")
+		return
+	}
+
+	id, err := parseIDParam(chi.URLParam(r, "id"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := s.attachmentService.DeleteAttachment(ctx, id); err != nil {
+		if errors.Is(err, persistence.ErrNotFound) {
+			writeJSONError(w, http.StatusNotFound, "Attachment not found")
+			return
+		}
+		handleError(w, err, s.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAttachmentError maps upload errors to their status code.
+func (s *Server) handleAttachmentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		writeJSONError(w, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, service.ErrAttachmentType):
+		writeJSONError(w, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, persistence.ErrNotFound):
+		writeJSONError(w, http.StatusNotFound, "Sensor not found")
+	default:
+		handleError(w, err, s.logger)
+	}
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+// bearerToken extracts the token of an "Authorization: Bearer <token>" header.
+func bearerToken(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token, ok && token != ""
+}
+
+// tokenAuthMiddleware requires a bearer token that is either staticToken
+// (configured through the environment) or an issued API token granting scope.
+// The protected endpoints are disabled when neither is available.
+func (s *Server) tokenAuthMiddleware(name, staticToken, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if staticToken == "" && s.tokenService == nil {
+				writeJSONError(w, http.StatusServiceUnavailable, fmt.Sprintf("%s not configured", name))
+				return
+			}
+
+			token, ok := bearerToken(r)
+			if !ok {
+				writeJSONError(w, http.StatusUnauthorized, "Missing bearer token")
+				return
+			}
+
+			if staticToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(staticToken)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if s.tokenService == nil {
+				writeJSONError(w, http.StatusUnauthorized, "Invalid token")
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+			defer cancel()
+
+			_, err := s.tokenService.Authenticate(ctx, token, scope)
+			switch {
+			case errors.Is(err, service.ErrTokenInvalid):
+				writeJSONError(w, http.StatusUnauthorized, "Invalid token")
+				return
+			case errors.Is(err, service.ErrTokenScope):
+				writeJSONError(w, http.StatusForbidden, fmt.Sprintf("Token scope does not allow %s access", scope))
+				return
+			case err != nil:
+				handleError(w, err, s.logger)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apiAuthMiddleware protects the data endpoints once static API tokens are
+// configured (GLCMD_API_TOKENS); without them the endpoints stay open.
+func (s *Server) apiAuthMiddleware(next http.Handler) http.Handler {
+	authenticated := s.dataAuth(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.apiTokens) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		authenticated.ServeHTTP(w, r)
+	})
+}
+
+// dataAuth requires a token allowing the request method on the data
+// endpoints. Reads (GET, HEAD) need a read or write token, other methods a
+// write token. GLCMD_ADMIN_TOKEN and issued tokens are accepted too: issued
+// read-scoped tokens grant reads, admin-scoped ones grant writes.
+func (s *Server) dataAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			writeJSONError(w, http.StatusUnauthorized, "Missing bearer token")
+			return
+		}
+
+		write := r.Method != http.MethodGet && r.Method != http.MethodHead
+
+		if scope, found := s.lookupAPIToken(token); found {
+			if write && scope != domain.APIScopeWrite {
+				writeJSONError(w, http.StatusForbidden, "Token scope does not allow write access")
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if s.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if s.tokenService == nil {
+			writeJSONError(w, http.StatusUnauthorized, "Invalid token")
+			return
+		}
+
+		scope := domain.TokenScopeRead
+		if write {
+			scope = domain.TokenScopeAdmin
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		_, err := s.tokenService.Authenticate(ctx, token, scope)
+		switch {
+		case errors.Is(err, service.ErrTokenInvalid):
+			writeJSONError(w, http.StatusUnauthorized, "Invalid token")
+			return
+		case errors.Is(err, service.ErrTokenScope):
+			writeJSONError(w, http.StatusForbidden, "Token scope does not allow write access")
+			return
+		case err != nil:
+			handleError(w, err, s.logger)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// lookupAPIToken returns the scope of a static API token. Every configured
+// token is compared in constant time so the lookup does not leak which
+// prefix matched.
+func (s *Server) lookupAPIToken(token string) (string, bool) {
+	var scope string
+	for candidate, candidateScope := range s.apiTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(candidate)) == 1 {
+			scope = candidateScope
+		}
+	}
+	return scope, scope != ""
+}
+
+// syncAuthMiddleware protects the sync manifest and export with GLCMD_SYNC_TOKEN or a sync-scoped token.
+func (s *Server) syncAuthMiddleware(next http.Handler) http.Handler {
+	return s.tokenAuthMiddleware("Sync export", s.syncToken, domain.TokenScopeSync)(next)
+}
+
+// adminAuthMiddleware protects admin endpoints with GLCMD_ADMIN_TOKEN or an admin-scoped token.
+func (s *Server) adminAuthMiddleware(next http.Handler) http.Handler {
+	return s.tokenAuthMiddleware("Admin API", s.adminToken, domain.TokenScopeAdmin)(next)
+}
+
+// attachmentAuthMiddleware protects sensor attachments (health photos) with a
+// token even when the other data endpoints are open. Tokens are checked as on
+// the data endpoints: uploads and deletions need a write token. The
+// attachments are disabled when no token can be configured.
+func (s *Server) attachmentAuthMiddleware(next http.Handler) http.Handler {
+	authenticated := s.dataAuth(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.adminToken == "" && s.tokenService == nil && len(s.apiTokens) == 0 {
+			writeJSONError(w, http.StatusServiceUnavailable, "Attachments not configured")
+			return
+		}
+		authenticated.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// bootstrapRecentWindow is the span of recent readings in the bootstrap response.
+const bootstrapRecentWindow = 3 * time.Hour
+
+// handleGetBootstrap handles GET /v1/bootstrap
+// Returns everything a dashboard or widget needs on startup in one response:
+// latest measurement, last 3h of readings, today's statistics, current sensor,
+// display preferences and health. Missing data (no measurement, no sensor) is null.
+func (s *Server) handleGetBootstrap(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	data := BootstrapData{}
+
+	latest, err := s.glucoseService.GetLatestMeasurement(ctx)
+	if err != nil && !errors.Is(err, persistence.ErrNotFound) {
+		handleError(w, err, s.logger)
+		return
+	}
+	data.Latest = latest
+
+	data.Recent, err = s.glucoseService.GetMeasurementsByTimeRange(ctx, now.Add(-bootstrapRecentWindow), now)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	// Today in glcore's local time, as the daily quality report
+	targets, err := s.configService.GetGlucoseTargets(ctx)
+	if err != nil && !errors.Is(err, persistence.ErrNotFound) {
+		handleError(w, err, s.logger)
+		return
+	}
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	stats, err := s.glucoseService.GetStatistics(ctx, &start, &now, targets)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+	data.Today = newStatisticsData(stats, &start, &now, targets)
+
+	sensor, err := s.sensorService.GetCurrentSensor(ctx)
+	switch {
+	case err == nil:
+		data.Sensor = s.newSensorResponse(r, sensor)
+	case !errors.Is(err, persistence.ErrNotFound):
+		handleError(w, err, s.logger)
+		return
+	}
+
+	data.Preferences, err = s.configService.GetDisplayPreferences(ctx)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	data.Health = s.getHealthStatus()
+	data.Health.DatabaseConnected = s.getDatabaseHealth()
+
+	response := BootstrapResponse{
+		Data: data,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
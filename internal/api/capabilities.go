@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+)
+
+// apiVersion is the current major version of the REST API
+const apiVersion = "v1"
+
+// Feature names reported by GET /v1/capabilities
+const (
+	FeatureSSE             = "sse"
+	FeatureWebSocket       = "websocket"
+	FeaturePrometheus      = "prometheus"
+	FeatureAuth            = "auth"
+	FeatureWebhooks        = "webhooks"
+	FeaturePredictions     = "predictions"
+	FeatureLongPoll        = "longPoll"
+	FeatureDeltaEncoding   = "deltaEncoding"
+	FeatureDashboardConfig = "dashboardConfig"
+	FeatureDeviceConfig    = "deviceConfig"
+	FeatureSyncManifest    = "syncManifest"
+	FeatureSyncExport      = "syncExport"
+	FeatureAdminTokens     = "adminTokens"
+	FeatureSignedEvents    = "signedEvents"
+	FeatureExerciseMode    = "exerciseMode"
+	FeatureAlertHistory    = "alertHistory"
+	FeatureTreatments      = "treatments"
+	FeatureDataQuality     = "dataQuality"
+	FeatureAdminLogs       = "adminLogs"
+	FeaturePrivacy         = "privacy"
+	FeatureViews           = "views"
+	FeatureNotes           = "notes"
+	FeatureLogbook         = "logbook"
+	FeatureHistogram       = "histogram"
+	FeaturePercentiles     = "percentiles"
+	FeatureConnection      = "connection"
+	FeatureUpstreamStatus  = "upstreamStatus"
+	FeatureFetchStats      = "fetchStats"
+	FeatureAttachments     = "sensorAttachments"
+	FeatureSSEClients      = "sseClients"
+	FeatureGlucoseExport   = "glucoseExport"
+	FeatureBootstrap       = "bootstrap"
+	FeatureJobs            = "jobs"
+	FeatureGlucoseEvents   = "glucoseEvents"
+	FeatureDailySummaries  = "dailySummaries"
+)
+
+// Capability describes whether a feature is available on this deployment.
+// Version is the feature's protocol version, bumped on incompatible changes;
+// it is omitted for features this build does not provide.
+type Capability struct {
+	Enabled bool `json:"enabled"`
+	Version int  `json:"version,omitempty"`
+}
+
+// Capabilities lists the API version, the response schema versions selectable
+// with the X-GLCMD-API-Version header, the features of this deployment and
+// the deprecated endpoints and parameters.
+type Capabilities struct {
+	APIVersion     string                `json:"apiVersion"`
+	SchemaVersion  int                   `json:"schemaVersion"`
+	SchemaVersions []int                 `json:"schemaVersions"`
+	Features       map[string]Capability `json:"features"`
+	Deprecations   []Deprecation         `json:"deprecations"`
+}
+
+// capabilities reports the features enabled by the server configuration.
+func (s *Server) capabilities() *Capabilities {
+	return &Capabilities{
+		APIVersion:     apiVersion,
+		SchemaVersion:  currentSchemaVersion,
+		SchemaVersions: supportedSchemaVersions,
+		Features: map[string]Capability{
+			FeatureSSE:             {Enabled: s.eventBroker != nil, Version: 1},
+			FeatureLongPoll:        {Enabled: true, Version: 1},
+			FeatureDeltaEncoding:   {Enabled: true, Version: 1},
+			FeatureDashboardConfig: {Enabled: s.configService != nil, Version: 1},
+			FeatureDeviceConfig:    {Enabled: s.configService != nil, Version: 1},
+			FeatureSyncManifest:    {Enabled: s.syncService != nil, Version: 1},
+			FeatureSyncExport:      {Enabled: s.syncService != nil && (s.syncToken != "" || s.tokenService != nil), Version: 1},
+			FeatureAdminTokens:     {Enabled: s.tokenService != nil, Version: 1},
+			FeatureSignedEvents:    {Enabled: s.eventBroker != nil && s.signingService != nil, Version: 1},
+			FeatureExerciseMode:    {Enabled: s.modeService != nil, Version: 1},
+			FeatureAlertHistory:    {Enabled: s.alertService != nil, Version: 1},
+			FeatureTreatments:      {Enabled: s.treatmentService != nil, Version: 1},
+			FeatureDataQuality:     {Enabled: true, Version: 1},
+			FeatureAdminLogs:       {Enabled: s.logRing != nil, Version: 1},
+			FeaturePrivacy:         {Enabled: s.privacyService != nil, Version: 1},
+			FeatureViews:           {Enabled: s.viewService != nil, Version: 1},
+			FeatureNotes:           {Enabled: s.noteService != nil, Version: 1},
+			FeatureLogbook:         {Enabled: s.logbookService != nil, Version: 1},
+			FeatureHistogram:       {Enabled: true, Version: 1},
+			FeaturePercentiles:     {Enabled: true, Version: 1},
+			FeatureConnection:      {Enabled: s.getConnectionInfo != nil, Version: 1},
+			FeatureUpstreamStatus:  {Enabled: s.upstreamService != nil, Version: 1},
+			FeatureFetchStats:      {Enabled: s.getFetchStats != nil, Version: 1},
+			FeatureAttachments:     {Enabled: s.attachmentService != nil, Version: 1},
+			FeatureAuth:            {Enabled: len(s.apiTokens) > 0, Version: 1},
+			FeatureSSEClients:      {Enabled: s.eventBroker != nil, Version: 1},
+			FeaturePrometheus:      {Enabled: true, Version: 1},
+			FeatureGlucoseExport:   {Enabled: true, Version: 1},
+			FeatureBootstrap:       {Enabled: true, Version: 1},
+			FeatureJobs:            {Enabled: s.jobManager != nil, Version: 1},
+			FeatureGlucoseEvents:   {Enabled: s.glucoseEventService != nil, Version: 1},
+			FeatureDailySummaries:  {Enabled: s.dailySummaryService != nil, Version: 1},
+
+			// Not provided by this build
+			FeatureWebSocket:   {Enabled: false},
+			FeatureWebhooks:    {Enabled: false},
+			FeaturePredictions: {Enabled: false},
+		},
+		Deprecations: s.deprecations,
+	}
+}
+
+// handleGetCapabilities handles GET /v1/capabilities
+// Lets clients adapt to the features enabled on this deployment.
+func (s *Server) handleGetCapabilities(w http.ResponseWriter, r *http.Request) {
+	response := CapabilitiesResponse{
+		Data: s.capabilities(),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
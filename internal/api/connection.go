@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+)
+
+// handleGetConnection handles GET /v1/connection
+// Returns the details of the upstream LibreLinkUp connection (source, patient
+// initials, country, sensor brand and data delay), so multi-source setups can
+// tell where the data comes from.
+func (s *Server) handleGetConnection(w http.ResponseWriter, r *http.Request) {
+	if s.getConnectionInfo == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Connection details are not available")
+		return
+	}
+
+	info := s.getConnectionInfo()
+	if info == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Not connected to LibreLinkUp yet")
+		return
+	}
+
+	response := ConnectionResponse{
+		Data: info,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// handleGetGlucoseDaily handles GET /v1/glucose/daily
+// Returns the summary (average, time in range, lows, highs, GMI) of each local
+// day within a time range (default: last 30 days), oldest first. Summaries are
+// precomputed by the daily summaries job, so long periods are charted without
+// reading the raw measurements.
+func (s *Server) handleGetGlucoseDaily(w http.ResponseWriter, r *http.Request) {
+	if s.dailySummaryService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Daily summaries not available")
+		return
+	}
+
+	start, end, err := parseDailySummaryRange(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	summaries, err := s.dailySummaryService.GetDailySummaries(ctx, start, end)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, DailySummaryListResponse{Data: summaries}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
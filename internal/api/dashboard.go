@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// handleGetDashboardConfig handles GET /v1/dashboard/config
+// Returns the saved dashboard layout, or the default layout if none was saved.
+func (s *Server) handleGetDashboardConfig(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	config, err := s.configService.GetDashboardConfig(ctx)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := DashboardConfigResponse{
+		Data: config,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handlePutDashboardConfig handles PUT /v1/dashboard/config
+// Replaces the whole dashboard layout.
+func (s *Server) handlePutDashboardConfig(w http.ResponseWriter, r *http.Request) {
+	config, err := parseDashboardConfig(w, r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := s.configService.SaveDashboardConfig(ctx, config); err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := DashboardConfigResponse{
+		Data: config,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// deprecationLogInterval limits the warnings logged per deprecation, so a
+// client polling a deprecated endpoint does not flood the logs.
+const deprecationLogInterval = time.Hour
+
+// Deprecation describes an endpoint or query parameter scheduled for removal.
+// Each entry mirrors a "Deprecated" item of the CHANGELOG naming its ID.
+type Deprecation struct {
+	ID        string   `json:"id"`
+	Method    string   `json:"method"`
+	Paths     []string `json:"paths"`
+	Param     string   `json:"param,omitempty"` // Query parameter (empty = the whole endpoint)
+	Date      string   `json:"date"`            // Day it was deprecated (YYYY-MM-DD)
+	Removal   string   `json:"removal"`         // Release it will be removed in
+	Migration string   `json:"migration"`       // How to stop using it
+}
+
+// deprecations lists the deprecated parts of the API. Nothing is deprecated
+// yet; an entry is added with the matching CHANGELOG item once the
+// maintainers decide to retire an endpoint or parameter.
+var deprecations = []Deprecation{}
+
+// matches reports whether r uses the deprecated endpoint or parameter.
+func (d Deprecation) matches(r *http.Request) bool {
+	if r.Method != d.Method || !slices.Contains(d.Paths, r.URL.Path) {
+		return false
+	}
+	return d.Param == "" || r.URL.Query().Has(d.Param)
+}
+
+// header returns the value of the Deprecation header (RFC 9745): the
+// deprecation date as a Unix timestamp.
+func (d Deprecation) header() string {
+	date, err := time.Parse(time.DateOnly, d.Date)
+	if err != nil {
+		return "@0"
+	}
+	return fmt.Sprintf("@%d", date.Unix())
+}
+
+// deprecationLog rate-limits the warnings logged per deprecation.
+type deprecationLog struct {
+	mu         sync.Mutex
+	lastLogged map[string]time.Time
+	suppressed map[string]int
+}
+
+func newDeprecationLog() *deprecationLog {
+	return &deprecationLog{
+		lastLogged: make(map[string]time.Time),
+		suppressed: make(map[string]int),
+	}
+}
+
+// allow reports whether a use of deprecation id at now should be logged,
+// with the number of uses not logged since the previous warning.
+func (l *deprecationLog) allow(id string, now time.Time) (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, ok := l.lastLogged[id]; ok && now.Sub(last) < deprecationLogInterval {
+		l.suppressed[id]++
+		return false, 0
+	}
+
+	suppressed := l.suppressed[id]
+	l.lastLogged[id] = now
+	l.suppressed[id] = 0
+	return true, suppressed
+}
+
+// deprecationMiddleware marks responses to deprecated endpoints and
+// parameters with a Deprecation header and logs a warning with the migration
+// to make, at most once per deprecationLogInterval for each deprecation.
+func (s *Server) deprecationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, d := range s.deprecations {
+			if !d.matches(r) {
+				continue
+			}
+
+			if w.Header().Get("Deprecation") == "" {
+				w.Header().Set("Deprecation", d.header())
+			}
+
+			if ok, suppressed := s.deprecationLog.allow(d.ID, time.Now()); ok {
+				s.logger.Warn("deprecated API used",
+					"id", d.ID,
+					"method", r.Method,
+					"path", r.URL.Path,
+					"param", d.Param,
+					"removal", d.Removal,
+					"migration", d.Migration,
+					"userAgent", r.UserAgent(),
+					"suppressed", suppressed,
+				)
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDeprecation is a deprecation of a parameter of /v1/glucose, as none is
+// registered yet
+var testDeprecation = Deprecation{
+	ID:        "test-param",
+	Method:    http.MethodGet,
+	Paths:     []string{"/v1/glucose"},
+	Param:     "old",
+	Date:      "2026-10-16",
+	Removal:   "1.0.0",
+	Migration: "use new instead",
+}
+
+func TestDeprecationMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	s := &Server{
+		deprecations:   []Deprecation{testDeprecation},
+		deprecationLog: newDeprecationLog(),
+		logger:         slog.New(slog.NewTextHandler(&logs, nil)),
+	}
+	handler := s.deprecationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	get := func(target string) string {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w.Header().Get("Deprecation")
+	}
+
+	deprecatedAt := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC).Unix()
+	for _, target := range []string{"/v1/glucose?old=1", "/v1/glucose?old=2"} {
+		if header := get(target); header != fmt.Sprintf("@%d", deprecatedAt) {
+			t.Errorf("%s: expected a Deprecation header, got %q", target, header)
+		}
+	}
+	for _, target := range []string{"/v1/glucose", "/v1/glucose?new=1", "/v1/glucose/export?old=1"} {
+		if header := get(target); header != "" {
+			t.Errorf("%s: expected no Deprecation header, got %q", target, header)
+		}
+	}
+
+	// One warning per deprecation and interval, with its migration
+	if warnings := strings.Count(logs.String(), "deprecated API used"); warnings != 1 {
+		t.Errorf("expected 1 warning, got %d: %s", warnings, logs.String())
+	}
+	if !strings.Contains(logs.String(), "migration=\"use new instead\"") {
+		t.Errorf("expected the migration in the warning, got %s", logs.String())
+	}
+}
+
+func TestDeprecationLog_Allow(t *testing.T) {
+	l := newDeprecationLog()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	if ok, _ := l.allow("a", now); !ok {
+		t.Fatal("expected the first use to be logged")
+	}
+	if ok, _ := l.allow("a", now.Add(time.Minute)); ok {
+		t.Error("expected a use within the interval not to be logged")
+	}
+	if ok, _ := l.allow("b", now.Add(time.Minute)); !ok {
+		t.Error("expected another deprecation to be logged")
+	}
+	if ok, suppressed := l.allow("a", now.Add(deprecationLogInterval)); !ok || suppressed != 1 {
+		t.Errorf("expected a warning with 1 suppressed use, got %v, %d", ok, suppressed)
+	}
+}
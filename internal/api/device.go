@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// handleGetDeviceConfig handles GET /v1/config/device
+// Returns the LibreLinkUp app settings of the followed patient: glucose
+// limits, urgent low level and alarm rules. 404 until the first fetch.
+func (s *Server) handleGetDeviceConfig(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	device, err := s.configService.GetDeviceInfo(ctx)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := DeviceConfigResponse{
+		Data: device,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// Glucose export formats
+const (
+	exportFormatCSV = "csv"
+	exportFormatTSV = "tsv"
+)
+
+// exportTimeout bounds the glucose export, which can read the whole history.
+const exportTimeout = 60 * time.Second
+
+// exportColumns is the header row of the glucose export.
+var exportColumns = []string{
+	"timestamp", "factory_timestamp", "value_mgdl", "value_mmol", "trend_arrow", "measurement_color", "type",
+}
+
+// handleExportGlucose handles GET /v1/glucose/export
+// Streams the measurements matching the glucose filters as CSV or TSV,
+// oldest first. Rows are written as they are read from the database.
+func (s *Server) handleExportGlucose(w http.ResponseWriter, r *http.Request) {
+	format, err := parseExportFormat(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	filters, err := parseGlucoseFilters(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), exportTimeout)
+	defer cancel()
+
+	// The export can be large: allow more than the server write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(exportTimeout)); err != nil {
+		s.logger.Warn("failed to extend write deadline for glucose export", "error", err)
+	}
+
+	cw := csv.NewWriter(w)
+	contentType := "text/csv; charset=utf-8"
+	if format == exportFormatTSV {
+		cw.Comma = '\t'
+		contentType = "text/tab-separated-values; charset=utf-8"
+	}
+
+	// Headers are sent with the first row, so that a query failing upfront
+	// still gets a JSON error response
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="glcmd-glucose.`+format+`"`)
+		w.WriteHeader(http.StatusOK)
+		return cw.Write(exportColumns)
+	}
+
+	record := make([]string, len(exportColumns))
+	err = s.glucoseService.StreamMeasurements(ctx, filters, func(m *domain.GlucoseMeasurement) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return cw.Write(exportRecord(record, m))
+	})
+	if err == nil && !started {
+		err = start() // No measurements: header row only
+	}
+	if err != nil {
+		if !started {
+			handleError(w, err, s.logger)
+			return
+		}
+		// Too late for an error response: the client gets a truncated file
+		s.logger.Error("failed to stream glucose export", "error", err)
+		return
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		s.logger.Error("failed to write glucose export", "error", err)
+	}
+}
+
+// exportRecord formats m as an export row, reusing record.
+func exportRecord(record []string, m *domain.GlucoseMeasurement) []string {
+	record[0] = m.Timestamp.UTC().Format(time.RFC3339)
+	record[1] = m.FactoryTimestamp.UTC().Format(time.RFC3339)
+	record[2] = strconv.Itoa(m.ValueInMgPerDl)
+	record[3] = strconv.FormatFloat(m.Value, 'f', -1, 64)
+	record[4] = ""
+	if m.TrendArrow != nil {
+		record[4] = strconv.Itoa(*m.TrendArrow)
+	}
+	record[5] = strconv.Itoa(m.GlucoseColor)
+	record[6] = strconv.Itoa(m.Type)
+	return record
+}
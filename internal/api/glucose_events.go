@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// handleGetGlucoseEvents handles GET /v1/glucose/events
+// Returns a paginated list of the detected low and high glucose events,
+// newest first, with optional time range and type filters.
+func (s *Server) handleGetGlucoseEvents(w http.ResponseWriter, r *http.Request) {
+	if s.glucoseEventService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Glucose events not available")
+		return
+	}
+
+	limit, offset, err := parsePaginationParams(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	filters, err := parseGlucoseEventFilters(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	list, total, err := s.glucoseEventService.GetEventsWithFilters(ctx, filters, limit, offset)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := GlucoseEventListResponse{
+		Data:       list,
+		Pagination: newPaginationMetadata(limit, offset, total),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
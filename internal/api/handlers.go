@@ -3,16 +3,43 @@ package api
 import (
 	"context"
 	"errors"
+	"math"
 	"net/http"
 	"os"
 	"runtime"
+	"runtime/debug"
 	"time"
 
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/events"
 	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/service"
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
 )
 
 // handleGetLatestGlucose handles GET /glucose/latest
 func (s *Server) handleGetLatestGlucose(w http.ResponseWriter, r *http.Request) {
+	wait, err := parseLongPollWait(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+	ifNoneMatch := r.Header.Get("If-None-Match")
+	debug, err := parseDebug(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	// Subscribe before reading the latest value so a measurement arriving in
+	// between is not missed
+	var subscription <-chan events.Event
+	if wait > 0 && ifNoneMatch != "" {
+		var unsubscribe func()
+		subscription, unsubscribe = s.subscribeGlucose(r.Context())
+		defer unsubscribe()
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
@@ -26,10 +53,40 @@ func (s *Server) handleGetLatestGlucose(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	response := MeasurementResponse{
-		Data: measurement,
+	if ifNoneMatch != "" && etagMatches(ifNoneMatch, glucoseETag(measurement)) {
+		if wait == 0 {
+			w.Header().Set("ETag", glucoseETag(measurement))
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		// Long-poll: hold the request past the server write timeout
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Now().Add(wait + 5*time.Second)); err != nil {
+			s.logger.Warn("failed to extend write deadline for long-poll", "error", err)
+		}
+
+		waitCtx, waitCancel := context.WithTimeout(r.Context(), wait)
+		defer waitCancel()
+
+		newer := s.waitForNewGlucose(waitCtx, measurement, subscription)
+		if newer == nil {
+			w.Header().Set("ETag", glucoseETag(measurement))
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		measurement = newer
+	}
+
+	var response any = MeasurementResponse{
+		Data:    measurement,
+		Display: s.glucoseDisplay(r.Context(), measurement),
+	}
+	if debug {
+		response = GlucoseDebugResponse{Data: newGlucoseDebug(measurement)}
 	}
 
+	w.Header().Set("ETag", glucoseETag(measurement))
 	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
 		s.logger.Error("failed to write response", "error", err)
 	}
@@ -51,6 +108,18 @@ func (s *Server) handleGetGlucose(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	encoding, err := parseEncoding(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	debug, err := parseDebug(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
@@ -61,6 +130,32 @@ func (s *Server) handleGetGlucose(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if encoding == glclient.EncodingDelta {
+		response := GlucoseDeltaListResponse{
+			Data:       newDeltaSeries(measurements),
+			Pagination: newPaginationMetadata(limit, offset, total),
+		}
+		if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+			s.logger.Error("failed to write response", "error", err)
+		}
+		return
+	}
+
+	if debug {
+		data := make([]*GlucoseDebug, len(measurements))
+		for i, m := range measurements {
+			data[i] = newGlucoseDebug(m)
+		}
+		response := GlucoseDebugListResponse{
+			Data:       data,
+			Pagination: newPaginationMetadata(limit, offset, total),
+		}
+		if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+			s.logger.Error("failed to write response", "error", err)
+		}
+		return
+	}
+
 	// Build response with pagination
 	response := MeasurementListResponse{
 		Data:       measurements,
@@ -99,6 +194,38 @@ func (s *Server) handleGetGlucoseStatistics(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	data := newStatisticsData(stats, start, end, targets)
+
+	// Annotate statistics of a bounded period with the quality of its data
+	if start != nil && end != nil {
+		days, err := s.glucoseService.GetDailyQuality(ctx, *start, *end)
+		if err != nil {
+			handleError(w, err, s.logger)
+			return
+		}
+		data.DataQuality = service.SummarizeQuality(days)
+
+		if s.logbookService != nil {
+			data.DailyTotals, err = s.logbookService.GetDailyTotals(ctx, *start, *end)
+			if err != nil {
+				handleError(w, err, s.logger)
+				return
+			}
+		}
+	}
+
+	response := StatisticsResponse{
+		Data: data,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// newStatisticsData builds the statistics response of a period (nil = all time).
+// Time in Range is included when targets are available.
+func newStatisticsData(stats *service.MeasurementStats, start, end *time.Time, targets *domain.GlucoseTargets) StatisticsData {
 	// Build response with period info
 	var periodInfo PeriodInfo
 	if start != nil && end != nil {
@@ -139,7 +266,39 @@ func (s *Server) handleGetGlucoseStatistics(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
-	response := StatisticsResponse{
+	return data
+}
+
+// handleGetGlucoseHistogram handles GET /glucose/histogram
+// Returns the number of readings per value bucket (default: 10 mg/dL) over a
+// period (default: all time), so clients can draw distribution charts without
+// downloading the readings.
+func (s *Server) handleGetGlucoseHistogram(w http.ResponseWriter, r *http.Request) {
+	filters, bucketMgDl, err := parseHistogramParams(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	// Use longer timeout for potentially large queries
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	histogram, err := s.glucoseService.GetHistogram(ctx, filters, bucketMgDl)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	data := HistogramData{Histogram: histogram}
+	if filters.StartTime != nil && filters.EndTime != nil {
+		data.Period = &PeriodInfo{
+			Start: filters.StartTime.Format(time.RFC3339),
+			End:   filters.EndTime.Format(time.RFC3339),
+		}
+	}
+
+	response := HistogramResponse{
 		Data: data,
 	}
 
@@ -148,6 +307,73 @@ func (s *Server) handleGetGlucoseStatistics(w http.ResponseWriter, r *http.Reque
 	}
 }
 
+// handleGetGlucosePercentiles handles GET /glucose/percentiles
+// Returns the median and percentile bands of the readings by time of day over
+// the last days (default: 14), for clients drawing AGP-style overlays.
+func (s *Server) handleGetGlucosePercentiles(w http.ResponseWriter, r *http.Request) {
+	start, end, bucket, err := parsePercentilesParams(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	// Use longer timeout for potentially large queries
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	percentiles, err := s.glucoseService.GetPercentiles(ctx, start, end, bucket)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := PercentilesResponse{
+		Data: PercentilesData{
+			Period: &PeriodInfo{
+				Start: start.Format(time.RFC3339),
+				End:   end.Format(time.RFC3339),
+			},
+			Days:        int(end.Sub(start).Hours() / 24),
+			Percentiles: percentiles,
+		},
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetGlucoseQuality handles GET /glucose/quality
+// Returns the data quality (capture, gaps, artifacts) of each day within a
+// time range (default: last 14 days), so statistics can be judged.
+func (s *Server) handleGetGlucoseQuality(w http.ResponseWriter, r *http.Request) {
+	start, end, err := parseQualityRange(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	days, err := s.glucoseService.GetDailyQuality(ctx, start, end)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := QualityResponse{
+		Data: QualityData{
+			Summary: service.SummarizeQuality(days),
+			Days:    days,
+		},
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
 // handleGetSensor handles GET /sensor
 // Returns a paginated list of sensors with optional filters
 func (s *Server) handleGetSensor(w http.ResponseWriter, r *http.Request) {
@@ -174,7 +400,7 @@ func (s *Server) handleGetSensor(w http.ResponseWriter, r *http.Request) {
 
 	data := make([]*SensorResponse, 0, len(sensors))
 	for _, sensor := range sensors {
-		data = append(data, NewSensorResponse(sensor))
+		data = append(data, s.newSensorResponse(r, sensor))
 	}
 
 	response := SensorListResponse{
@@ -204,7 +430,7 @@ func (s *Server) handleGetLatestSensor(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := LatestSensorResponse{
-		Data: NewSensorResponse(sensor),
+		Data: s.newSensorResponse(r, sensor),
 	}
 
 	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
@@ -214,8 +440,8 @@ func (s *Server) handleGetLatestSensor(w http.ResponseWriter, r *http.Request) {
 
 // handleGetSensorStatistics handles GET /sensor/stats
 func (s *Server) handleGetSensorStatistics(w http.ResponseWriter, r *http.Request) {
-	// Parse time range (optional)
-	start, end, err := parseStatisticsParams(r)
+	// Parse activation and end periods (optional)
+	filters, err := parseSensorStatisticsParams(r)
 	if err != nil {
 		handleError(w, err, s.logger)
 		return
@@ -224,7 +450,7 @@ func (s *Server) handleGetSensorStatistics(w http.ResponseWriter, r *http.Reques
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	stats, err := s.sensorService.GetStatistics(ctx, start, end)
+	stats, err := s.sensorService.GetStatisticsWithFilters(ctx, filters)
 	if err != nil {
 		handleError(w, err, s.logger)
 		return
@@ -234,23 +460,30 @@ func (s *Server) handleGetSensorStatistics(w http.ResponseWriter, r *http.Reques
 	var currentResp *SensorResponse
 	currentSensor, err := s.sensorService.GetCurrentSensor(ctx)
 	if err == nil && currentSensor != nil {
-		currentResp = NewSensorResponse(currentSensor)
+		currentResp = s.newSensorResponse(r, currentSensor)
 	}
 
 	// Build response with period info
-	var periodInfo *PeriodInfo
-	if start != nil && end != nil {
+	var periodInfo, endedPeriodInfo *PeriodInfo
+	if filters.StartTime != nil && filters.EndTime != nil {
 		periodInfo = &PeriodInfo{
-			Start: start.Format(time.RFC3339),
-			End:   end.Format(time.RFC3339),
+			Start: filters.StartTime.Format(time.RFC3339),
+			End:   filters.EndTime.Format(time.RFC3339),
+		}
+	}
+	if filters.EndedStart != nil && filters.EndedEnd != nil {
+		endedPeriodInfo = &PeriodInfo{
+			Start: filters.EndedStart.Format(time.RFC3339),
+			End:   filters.EndedEnd.Format(time.RFC3339),
 		}
 	}
 
 	response := SensorStatisticsResponse{
 		Data: SensorStatisticsData{
-			Period:     periodInfo,
-			Statistics: *stats,
-			Current:    currentResp,
+			Period:      periodInfo,
+			EndedPeriod: endedPeriodInfo,
+			Statistics:  *stats,
+			Current:     currentResp,
 		},
 	}
 
@@ -292,8 +525,14 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 }
 
 // handleMetrics handles GET /metrics
-// Returns runtime metrics including memory, goroutines, and system info
+// Returns runtime metrics including memory, goroutines, and system info.
+// Prometheus scrapers get the text exposition format instead of JSON.
 func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if wantsPrometheus(r) {
+		s.writePrometheusMetrics(w, r)
+		return
+	}
+
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
 
@@ -326,11 +565,22 @@ func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 		SSE: sseMetrics,
 	}
 
+	// debug.SetMemoryLimit with a negative value only reads the current limit
+	if limit := debug.SetMemoryLimit(-1); limit != math.MaxInt64 {
+		metricsData.Memory.LimitMB = uint64(limit) / 1024 / 1024
+	}
+
 	// Database pool stats
 	if s.getDatabasePoolStats != nil {
 		metricsData.Database = s.getDatabasePoolStats()
 	}
 
+	// Fetch cycle counters
+	if s.getFetchStats != nil {
+		stats := s.getFetchStats()
+		metricsData.Fetch = &stats
+	}
+
 	response := MetricsResponse{
 		Data: metricsData,
 	}
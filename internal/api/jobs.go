@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/jobs"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// jobTimeout bounds the database work of a background job.
+	jobTimeout = 10 * time.Minute
+	// treatmentImportAttempts is how many times an import is tried before it
+	// fails (the database may be busy). Imports are transactional, so a
+	// failed attempt leaves nothing behind.
+	treatmentImportAttempts = 3
+)
+
+// Job types reported in Job.Type
+const (
+	jobTypeTreatmentImport = "treatmentImport"
+)
+
+// treatmentImportPayload is the input of a treatment import job.
+type treatmentImportPayload struct {
+	Treatments []*domain.TreatmentEntry `json:"treatments"`
+	Skipped    int                      `json:"skipped"` // Rows that could not be parsed
+}
+
+// registerJobs registers the job types started by the API.
+func (s *Server) registerJobs() {
+	if s.jobManager == nil {
+		return
+	}
+	if s.treatmentService != nil {
+		s.jobManager.Register(jobTypeTreatmentImport, s.runTreatmentImport, jobs.Options{MaxAttempts: treatmentImportAttempts})
+	}
+}
+
+// runTreatmentImport imports the treatments of a job.
+func (s *Server) runTreatmentImport(ctx context.Context, job *domain.Job, p *jobs.Progress) (any, error) {
+	var payload treatmentImportPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("invalid treatment import payload: %w", err)
+	}
+
+	p.SetTotal(len(payload.Treatments))
+	if payload.Skipped > 0 {
+		p.Errorf("skipped %d row(s) that could not be parsed", payload.Skipped)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, jobTimeout)
+	defer cancel()
+
+	result, err := s.treatmentService.ImportTreatments(ctx, payload.Treatments, p.SetDone)
+	if err != nil {
+		return nil, err
+	}
+	return newTreatmentImportData(result, payload.Skipped), nil
+}
+
+// startJob enqueues a background job and answers 202 Accepted with the job,
+// its URL in the Location header.
+func (s *Server) startJob(w http.ResponseWriter, r *http.Request, jobType string, payload any) {
+	if s.jobManager == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Background jobs not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	job, err := s.jobManager.Enqueue(ctx, jobType, payload)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	w.Header().Set("Location", "/v1/jobs/"+job.ID)
+	if err := writeJSONResponse(w, http.StatusAccepted, JobResponse{Data: job}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetJobs handles GET /v1/jobs
+// Returns a paginated list of background jobs, newest first, with optional
+// type and status filters.
+func (s *Server) handleGetJobs(w http.ResponseWriter, r *http.Request) {
+	if s.jobManager == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Background jobs not available")
+		return
+	}
+
+	limit, offset, err := parsePaginationParams(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	filters, err := parseJobFilters(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	list, total, err := s.jobManager.List(ctx, filters, limit, offset)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := JobListResponse{
+		Data:       list,
+		Pagination: newPaginationMetadata(limit, offset, total),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetJobSchedules handles GET /v1/jobs/schedules
+// Returns the recurring jobs and their next run, soonest first.
+func (s *Server) handleGetJobSchedules(w http.ResponseWriter, r *http.Request) {
+	if s.jobManager == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Background jobs not available")
+		return
+	}
+
+	response := JobScheduleListResponse{
+		Data: s.jobManager.Schedules(),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetJob handles GET /v1/jobs/{id}
+// Returns the status and progress of a background job. Finished jobs are
+// kept for GLCMD_JOB_RETENTION.
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	if s.jobManager == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Background jobs not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	job, err := s.jobManager.Get(ctx, chi.URLParam(r, "id"))
+	if errors.Is(err, persistence.ErrNotFound) {
+		writeJSONError(w, http.StatusNotFound, "Job not found")
+		return
+	}
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, JobResponse{Data: job}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleCancelJob handles DELETE /v1/jobs/{id}
+// Cancels a pending job, or asks a running job to stop, and returns it. A
+// running job reports canceled once it has stopped; imports are rolled back.
+func (s *Server) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	if s.jobManager == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Background jobs not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	job, err := s.jobManager.Cancel(ctx, chi.URLParam(r, "id"))
+	if errors.Is(err, persistence.ErrNotFound) {
+		writeJSONError(w, http.StatusNotFound, "Job not found")
+		return
+	}
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, JobResponse{Data: job}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// handleGetInsulin handles GET /v1/insulin
+// Returns the insulin doses logged within a time range (default: last 24 hours).
+func (s *Server) handleGetInsulin(w http.ResponseWriter, r *http.Request) {
+	if s.logbookService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Logbook not available")
+		return
+	}
+
+	start, end, err := parseLogbookRange(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	doses, err := s.logbookService.GetInsulin(ctx, start, end)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, InsulinListResponse{Data: doses}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleLogInsulin handles POST /v1/insulin
+// Logs an insulin dose; its timestamp defaults to now.
+func (s *Server) handleLogInsulin(w http.ResponseWriter, r *http.Request) {
+	if s.logbookService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Logbook not available")
+		return
+	}
+
+	dose, err := parseInsulinRequest(w, r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	logged, err := s.logbookService.LogInsulin(ctx, dose)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusCreated, InsulinResponse{Data: logged}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetCarbs handles GET /v1/carbs
+// Returns the carbohydrate intakes logged within a time range (default: last 24 hours).
+func (s *Server) handleGetCarbs(w http.ResponseWriter, r *http.Request) {
+	if s.logbookService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Logbook not available")
+		return
+	}
+
+	start, end, err := parseLogbookRange(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	intakes, err := s.logbookService.GetCarbs(ctx, start, end)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, CarbListResponse{Data: intakes}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleLogCarbs handles POST /v1/carbs
+// Logs a carbohydrate intake; its timestamp defaults to now.
+func (s *Server) handleLogCarbs(w http.ResponseWriter, r *http.Request) {
+	if s.logbookService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Logbook not available")
+		return
+	}
+
+	intake, err := parseCarbRequest(w, r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	logged, err := s.logbookService.LogCarbs(ctx, intake)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusCreated, CarbResponse{Data: logged}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
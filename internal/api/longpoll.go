@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxLongPollWait caps the ?wait parameter of long-poll endpoints
+	maxLongPollWait = 60 * time.Second
+	// longPollInterval is how often the database is checked when SSE is disabled
+	longPollInterval = 5 * time.Second
+)
+
+// glucoseETag returns the entity tag identifying a measurement.
+// The factory timestamp is unique per reading, so it changes with every new value.
+func glucoseETag(m *domain.GlucoseMeasurement) string {
+	return fmt.Sprintf(`"%d"`, m.FactoryTimestamp.Unix())
+}
+
+// parseLongPollWait parses the optional wait query parameter (e.g. 30s).
+// Returns 0 when the parameter is absent.
+func parseLongPollWait(r *http.Request) (time.Duration, error) {
+	waitStr := r.URL.Query().Get("wait")
+	if waitStr == "" {
+		return 0, nil
+	}
+
+	wait, err := time.ParseDuration(waitStr)
+	if err != nil {
+		return 0, NewValidationError("invalid wait parameter (use a duration like 30s)")
+	}
+	if wait <= 0 || wait > maxLongPollWait {
+		return 0, NewValidationError(fmt.Sprintf("wait must be between 1s and %s", maxLongPollWait))
+	}
+
+	return wait, nil
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag.
+// Weak validators are compared by their opaque tag, as RFC 9110 requires for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForNewGlucose blocks until a measurement newer than current is available,
+// or ctx is done. Returns nil when no newer measurement arrived. Backfilled
+// historical readings are older and therefore ignored.
+// New readings are detected via the event broker when SSE is enabled, and by
+// polling the database otherwise.
+func (s *Server) waitForNewGlucose(ctx context.Context, current *domain.GlucoseMeasurement, subscription <-chan events.Event) *domain.GlucoseMeasurement {
+	var poll <-chan time.Time
+	if subscription == nil {
+		ticker := time.NewTicker(longPollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-subscription:
+			if !ok {
+				return nil
+			}
+			if m, ok := event.Data.(*domain.GlucoseMeasurement); ok && m.FactoryTimestamp.After(current.FactoryTimestamp) {
+				return m
+			}
+		case <-poll:
+			if m := s.fetchLatestGlucose(ctx); m != nil && m.FactoryTimestamp.After(current.FactoryTimestamp) {
+				return m
+			}
+		}
+	}
+}
+
+// fetchLatestGlucose returns the latest measurement, or nil on error.
+func (s *Server) fetchLatestGlucose(ctx context.Context) *domain.GlucoseMeasurement {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	m, err := s.glucoseService.GetLatestMeasurement(ctx)
+	if err != nil {
+		return nil
+	}
+	return m
+}
+
+// subscribeGlucose subscribes to the glucose events of the patient of ctx for
+// the duration of a long-poll.
+// Returns a nil channel and a no-op cleanup when SSE is disabled.
+func (s *Server) subscribeGlucose(ctx context.Context) (<-chan events.Event, func()) {
+	if s.eventBroker == nil {
+		return nil, func() {}
+	}
+
+	id := "longpoll-" + uuid.New().String()
+	ch := s.eventBroker.SubscribePatient(id, repository.PatientFromContext(ctx), []events.EventType{events.EventTypeGlucose})
+	return ch, func() { s.eventBroker.Unsubscribe(id) }
+}
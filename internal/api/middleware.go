@@ -3,7 +3,11 @@ package api
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/google/uuid"
 )
 
 // corsMiddleware adds CORS headers to allow cross-origin requests
@@ -11,8 +15,9 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Allow all origins for now (can be restricted later via config)
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-None-Match, X-GLCMD-API-Version")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag, X-GLCMD-API-Version, Deprecation")
 		w.Header().Set("Access-Control-Max-Age", "3600")
 
 		// Handle preflight OPTIONS request
@@ -50,6 +55,11 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap exposes the underlying ResponseWriter to http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // loggingMiddleware logs HTTP requests
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -102,3 +112,24 @@ func (s *Server) timeoutMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// patientMiddleware scopes the glucose and sensor data of a request to the
+// patient of the patientId parameter, or to the default patient when the
+// parameter is absent.
+func (s *Server) patientMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		patientID := s.defaultPatientID
+		if value := r.URL.Query().Get("patientId"); value != "" {
+			if _, err := uuid.Parse(value); err != nil {
+				handleError(w, NewValidationError("invalid patientId: must be a LibreLinkUp patient ID"), s.logger)
+				return
+			}
+			patientID = strings.ToLower(value)
+		}
+
+		if patientID != "" {
+			r = r.WithContext(repository.WithPatient(r.Context(), patientID))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+)
+
+// handleGetMode handles GET /v1/mode
+// Returns the current activity mode and the alert thresholds in effect.
+func (s *Server) handleGetMode(w http.ResponseWriter, r *http.Request) {
+	if s.modeService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Modes are not enabled")
+		return
+	}
+
+	response := ModeResponse{
+		Data: s.modeService.Current(),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleStartExercise handles POST /v1/mode/exercise?duration=1h
+// Adjusts alert thresholds for exercise until the duration elapses.
+func (s *Server) handleStartExercise(w http.ResponseWriter, r *http.Request) {
+	if s.modeService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Modes are not enabled")
+		return
+	}
+
+	duration, err := parseExerciseDuration(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := ModeResponse{
+		Data: s.modeService.StartExercise(duration),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleEndExercise handles DELETE /v1/mode/exercise
+// Ends exercise mode early.
+func (s *Server) handleEndExercise(w http.ResponseWriter, r *http.Request) {
+	if s.modeService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Modes are not enabled")
+		return
+	}
+
+	response := ModeResponse{
+		Data: s.modeService.EndExercise(),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// handleGetNotes handles GET /v1/notes
+// Returns a paginated list of notes, newest first, with optional time range
+// and tag filters.
+func (s *Server) handleGetNotes(w http.ResponseWriter, r *http.Request) {
+	if s.noteService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Notes not available")
+		return
+	}
+
+	limit, offset, err := parsePaginationParams(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	filters, err := parseNoteFilters(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	notes, total, err := s.noteService.GetNotesWithFilters(ctx, filters, limit, offset)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := NoteListResponse{
+		Data:       notes,
+		Pagination: newPaginationMetadata(limit, offset, total),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleCreateNote handles POST /v1/notes
+// Stores a note; its timestamp defaults to now.
+func (s *Server) handleCreateNote(w http.ResponseWriter, r *http.Request) {
+	if s.noteService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Notes not available")
+		return
+	}
+
+	note, err := parseNoteRequest(w, r, false)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	created, err := s.noteService.CreateNote(ctx, note)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/v1/notes/%d", created.ID))
+	if err := writeJSONResponse(w, http.StatusCreated, NoteResponse{Data: created}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetNote handles GET /v1/notes/{id}
+func (s *Server) handleGetNote(w http.ResponseWriter, r *http.Request) {
+	if s.noteService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Notes not available")
+		return
+	}
+
+	id, err := parseIDParam(chi.URLParam(r, "id"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	note, err := s.noteService.GetNote(ctx, id)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, NoteResponse{Data: note}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handlePutNote handles PUT /v1/notes/{id}
+// Replaces the timestamp, text and tags of a note.
+func (s *Server) handlePutNote(w http.ResponseWriter, r *http.Request) {
+	if s.noteService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Notes not available")
+		return
+	}
+
+	id, err := parseIDParam(chi.URLParam(r, "id"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	note, err := parseNoteRequest(w, r, true)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+	note.ID = id
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	updated, err := s.noteService.UpdateNote(ctx, note)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, NoteResponse{Data: updated}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleDeleteNote handles DELETE /v1/notes/{id}
+func (s *Server) handleDeleteNote(w http.ResponseWriter, r *http.Request) {
+	if s.noteService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Notes not available")
+		return
+	}
+
+	id, err := parseIDParam(chi.URLParam(r, "id"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := s.noteService.DeleteNote(ctx, id); err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,485 @@
+package api
+
+import (
+	_ "embed"
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/alerts"
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
+)
+
+// apiDocsPage is the Swagger UI page served at /v1/docs.
+//
+//go:embed openapi.html
+var apiDocsPage []byte
+
+// OpenAPISpec is an OpenAPI 3.0 document, limited to the objects glcmd uses.
+type OpenAPISpec struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+}
+
+// OpenAPIInfo describes the API.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+// OpenAPIOperation describes an endpoint.
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+}
+
+// OpenAPIParameter describes a query, path or header parameter.
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIRequestBody describes a JSON request body.
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse describes a response.
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType is the schema of a content type.
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIComponents holds the schemas referenced by the operations.
+type OpenAPIComponents struct {
+	Schemas         map[string]*OpenAPISchema         `json:"schemas"`
+	SecuritySchemes map[string]*OpenAPISecurityScheme `json:"securitySchemes"`
+}
+
+// OpenAPISecurityScheme describes an authentication method.
+type OpenAPISecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme"`
+	Description string `json:"description"`
+}
+
+// OpenAPISchema is a JSON schema, in the OpenAPI 3.0 dialect.
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Minimum              *int                      `json:"minimum,omitempty"`
+	Maximum              *int                      `json:"maximum,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*OpenAPISchema          `json:"oneOf,omitempty"`
+}
+
+// Authentication of an endpoint
+const (
+	authNone = iota // Open
+	authAPI         // API token once tokens are configured
+)
+
+// openAPIEndpoint describes an endpoint of the specification. The schemas of
+// body and response are generated from their Go types, so the specification
+// follows the responses.
+type openAPIEndpoint struct {
+	method      string
+	path        string
+	tag         string
+	summary     string
+	description string
+	params      []string // Keys of openAPIParameters
+	body        any      // Request body, nil if none
+	response    any      // JSON response, nil if contentType is set
+	contentType string   // Content type of a non-JSON response
+	auth        int
+}
+
+// openAPIParameters are the parameters shared by the endpoints, by key.
+var openAPIParameters = map[string]*OpenAPIParameter{
+	"start":       {Name: "start", In: "query", Description: "Start of the time range (RFC3339)", Schema: &OpenAPISchema{Type: "string", Format: "date-time"}},
+	"end":         {Name: "end", In: "query", Description: "End of the time range (RFC3339)", Schema: &OpenAPISchema{Type: "string", Format: "date-time"}},
+	"statsStart":  {Name: "start", In: "query", Description: "Start of the period (RFC3339), with end; all time if both are omitted", Schema: &OpenAPISchema{Type: "string", Format: "date-time"}},
+	"statsEnd":    {Name: "end", In: "query", Description: "End of the period (RFC3339), with start", Schema: &OpenAPISchema{Type: "string", Format: "date-time"}},
+	"endedStart":  {Name: "endedStart", In: "query", Description: "Start of the period the sensors ended in (RFC3339), with endedEnd", Schema: &OpenAPISchema{Type: "string", Format: "date-time"}},
+	"endedEnd":    {Name: "endedEnd", In: "query", Description: "End of the period the sensors ended in (RFC3339), with endedStart", Schema: &OpenAPISchema{Type: "string", Format: "date-time"}},
+	"limit":       {Name: "limit", In: "query", Description: "Maximum number of items", Schema: &OpenAPISchema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(maxLimit)}},
+	"offset":      {Name: "offset", In: "query", Description: "Number of items to skip", Schema: &OpenAPISchema{Type: "integer", Minimum: intPtr(0)}},
+	"patientId":   {Name: "patientId", In: "query", Description: "LibreLinkUp patient ID (default: the configured patient)", Schema: &OpenAPISchema{Type: "string"}},
+	"color":       {Name: "color", In: "query", Description: "Color: 1 (normal), 2 (warning) or 3 (critical)", Schema: &OpenAPISchema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(3)}},
+	"glucoseType": {Name: "type", In: "query", Description: "Measurement type: 0 (historical) or 1 (current)", Schema: &OpenAPISchema{Type: "integer", Minimum: intPtr(0), Maximum: intPtr(1)}},
+	"q":           {Name: "q", In: "query", Description: "Filter expression, e.g. `value_mgdl > 180 AND hour in 0..6`", Schema: &OpenAPISchema{Type: "string"}},
+	"debug":       {Name: "debug", In: "query", Description: "Include the fetch provenance of the measurements", Schema: &OpenAPISchema{Type: "boolean"}},
+	"encoding":    {Name: "encoding", In: "query", Description: "`delta` for the compact delta encoding", Schema: &OpenAPISchema{Type: "string", Enum: []string{"json", glclient.EncodingDelta}}},
+	"wait":        {Name: "wait", In: "query", Description: "Long poll: wait up to this duration (e.g. 30s) for a measurement newer than If-None-Match", Schema: &OpenAPISchema{Type: "string"}},
+	"bucketMgDl":  {Name: "bucketMgDl", In: "query", Description: "Bucket width in mg/dL", Schema: &OpenAPISchema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(maxBucketMgDl)}},
+	"days":        {Name: "days", In: "query", Description: "Number of days", Schema: &OpenAPISchema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(maxPercentileDays)}},
+	"bucket":      {Name: "bucket", In: "query", Description: "Time of day bucket dividing a day (e.g. 30m)", Schema: &OpenAPISchema{Type: "string"}},
+	"eventType":   {Name: "type", In: "query", Description: "Event type", Schema: &OpenAPISchema{Type: "string", Enum: domain.GlucoseEventTypes}},
+	"format":      {Name: "format", In: "query", Description: "Export format", Schema: &OpenAPISchema{Type: "string", Enum: []string{exportFormatCSV, exportFormatTSV}}},
+	"types":       {Name: "types", In: "query", Description: "Comma-separated event types to receive (default: all)", Schema: &OpenAPISchema{Type: "string"}},
+	"signed":      {Name: "signed", In: "query", Description: "Sign the event payloads", Schema: &OpenAPISchema{Type: "boolean"}},
+	"serial":      {Name: "serial", In: "path", Description: "Sensor serial number", Required: true, Schema: &OpenAPISchema{Type: "string"}},
+}
+
+// openAPIEndpoints are the endpoints described by the specification.
+var openAPIEndpoints = []openAPIEndpoint{
+	{method: "get", path: "/health", tag: "monitoring", summary: "Health check",
+		description: "Returns 200 when healthy, 503 when degraded or unhealthy, with the same body.",
+		response:    HealthResponse{}},
+	{method: "get", path: "/metrics", tag: "monitoring", summary: "Runtime metrics",
+		description: "Prometheus scrapers (Accept: text/plain or ?format=prometheus) get the text exposition format instead.",
+		response:    MetricsResponse{}},
+	{method: "get", path: "/v1/capabilities", tag: "monitoring", summary: "Features enabled on this deployment",
+		response: CapabilitiesResponse{}},
+
+	{method: "get", path: "/v1/glucose", tag: "glucose", summary: "Paginated glucose measurements",
+		description: "Newest first. With debug=true the measurements include their fetch provenance; with encoding=delta the data is a glclient.DeltaSeries.",
+		params:      []string{"start", "end", "color", "glucoseType", "q", "limit", "offset", "debug", "encoding", "patientId"},
+		response:    GlucoseListResponse{}, auth: authAPI},
+	{method: "get", path: "/v1/glucose/latest", tag: "glucose", summary: "Latest glucose measurement",
+		params:   []string{"wait", "debug", "patientId"},
+		response: GlucoseResponse{}, auth: authAPI},
+	{method: "get", path: "/v1/glucose/stats", tag: "glucose", summary: "Glucose statistics",
+		params:   []string{"statsStart", "statsEnd", "patientId"},
+		response: StatisticsResponse{}, auth: authAPI},
+	{method: "get", path: "/v1/glucose/quality", tag: "glucose", summary: "Data quality of a time range",
+		params:   []string{"start", "end", "patientId"},
+		response: QualityResponse{}, auth: authAPI},
+	{method: "get", path: "/v1/glucose/histogram", tag: "glucose", summary: "Distribution of the glucose values",
+		params:   []string{"statsStart", "statsEnd", "q", "bucketMgDl", "patientId"},
+		response: HistogramResponse{}, auth: authAPI},
+	{method: "get", path: "/v1/glucose/percentiles", tag: "glucose", summary: "Glucose percentiles by time of day",
+		params:   []string{"days", "bucket", "patientId"},
+		response: PercentilesResponse{}, auth: authAPI},
+	{method: "get", path: "/v1/glucose/events", tag: "glucose", summary: "Low and high glucose events",
+		params:   []string{"start", "end", "eventType", "limit", "offset", "patientId"},
+		response: GlucoseEventListResponse{}, auth: authAPI},
+	{method: "get", path: "/v1/glucose/export", tag: "glucose", summary: "Export the glucose measurements",
+		params:      []string{"start", "end", "color", "glucoseType", "q", "format", "patientId"},
+		contentType: "text/csv", auth: authAPI},
+
+	{method: "get", path: "/v1/sensor", tag: "sensor", summary: "Paginated sensor history",
+		params:   []string{"start", "end", "limit", "offset", "patientId"},
+		response: SensorListResponse{}, auth: authAPI},
+	{method: "get", path: "/v1/sensor/latest", tag: "sensor", summary: "Current sensor",
+		params:   []string{"patientId"},
+		response: LatestSensorResponse{}, auth: authAPI},
+	{method: "get", path: "/v1/sensor/stats", tag: "sensor", summary: "Sensor lifecycle statistics",
+		params:   []string{"statsStart", "statsEnd", "endedStart", "endedEnd", "patientId"},
+		response: SensorStatisticsResponse{}, auth: authAPI},
+	{method: "get", path: "/v1/sensor/sites", tag: "sensor", summary: "Sensor application site history",
+		params:   []string{"limit", "offset", "patientId"},
+		response: SensorSitesResponse{}, auth: authAPI},
+	{method: "put", path: "/v1/sensor/latest/site", tag: "sensor", summary: "Record the application site of the current sensor",
+		params: []string{"patientId"},
+		body:   SensorSiteRequest{}, response: SensorSiteResponse{}, auth: authAPI},
+	{method: "patch", path: "/v1/sensor/{serial}", tag: "sensor", summary: "Record a sensor note and rating",
+		params: []string{"serial", "patientId"},
+		body:   SensorFeedbackRequest{}, response: LatestSensorResponse{}, auth: authAPI},
+
+	{method: "get", path: "/v1/stream", tag: "events", summary: "Real-time event stream (SSE)",
+		description: "Server-Sent Events. The event field is the type (glucose, sensor, summary, config, alert or keepalive), the data field its JSON payload, described by the response schema.",
+		params:      []string{"types", "signed"},
+		contentType: "text/event-stream", auth: authAPI},
+}
+
+// openAPIEventPayloads are the payloads of the SSE events, by type.
+var openAPIEventPayloads = []any{
+	domain.GlucoseMeasurement{},
+	domain.SensorConfig{},
+	domain.MorningSummary{},
+	domain.ConfigChange{},
+	alerts.Alert{},
+}
+
+// buildOpenAPISpec builds the specification of openAPIEndpoints.
+func buildOpenAPISpec() *OpenAPISpec {
+	gen := newSchemaGenerator()
+	errorSchema := gen.schema(reflect.TypeOf(ErrorResponse{}))
+
+	spec := &OpenAPISpec{
+		OpenAPI: "3.0.3",
+		Info: OpenAPIInfo{
+			Title:       "glcmd API",
+			Description: "Glucose measurements, sensors and monitoring of a glcore instance. The X-GLCMD-API-Version header selects the response schema version.",
+			Version:     apiVersion,
+		},
+		Paths: map[string]map[string]*OpenAPIOperation{},
+		Components: OpenAPIComponents{
+			Schemas: gen.components,
+			SecuritySchemes: map[string]*OpenAPISecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", Description: "API token, required once tokens are configured"},
+			},
+		},
+	}
+
+	versionHeader := &OpenAPIParameter{
+		Name:        glclient.APIVersionHeader,
+		In:          "header",
+		Description: "Response schema version (default: 1)",
+		Schema:      &OpenAPISchema{Type: "integer", Minimum: intPtr(legacySchemaVersion), Maximum: intPtr(currentSchemaVersion)},
+	}
+
+	for _, endpoint := range openAPIEndpoints {
+		op := &OpenAPIOperation{
+			OperationID: operationID(endpoint.method, endpoint.path),
+			Summary:     endpoint.summary,
+			Description: endpoint.description,
+			Tags:        []string{endpoint.tag},
+			Parameters:  []*OpenAPIParameter{versionHeader},
+			Responses: map[string]*OpenAPIResponse{
+				"default": {Description: "Error", Content: map[string]OpenAPIMediaType{"application/json": {Schema: errorSchema}}},
+			},
+		}
+		for _, key := range endpoint.params {
+			op.Parameters = append(op.Parameters, openAPIParameters[key])
+		}
+
+		if endpoint.body != nil {
+			op.RequestBody = &OpenAPIRequestBody{
+				Required: true,
+				Content:  map[string]OpenAPIMediaType{"application/json": {Schema: gen.schema(reflect.TypeOf(endpoint.body))}},
+			}
+		}
+
+		var content map[string]OpenAPIMediaType
+		switch endpoint.contentType {
+		case "":
+			content = map[string]OpenAPIMediaType{"application/json": {Schema: gen.schema(reflect.TypeOf(endpoint.response))}}
+		case "text/event-stream":
+			payloads := &OpenAPISchema{}
+			for _, payload := range openAPIEventPayloads {
+				payloads.OneOf = append(payloads.OneOf, gen.schema(reflect.TypeOf(payload)))
+			}
+			content = map[string]OpenAPIMediaType{endpoint.contentType: {Schema: payloads}}
+		default:
+			content = map[string]OpenAPIMediaType{endpoint.contentType: {Schema: &OpenAPISchema{Type: "string"}}}
+		}
+		op.Responses["200"] = &OpenAPIResponse{Description: "OK", Content: content}
+
+		if endpoint.auth == authAPI {
+			op.Security = []map[string][]string{{}, {"bearerAuth": {}}}
+		}
+
+		if spec.Paths[endpoint.path] == nil {
+			spec.Paths[endpoint.path] = map[string]*OpenAPIOperation{}
+		}
+		spec.Paths[endpoint.path][endpoint.method] = op
+	}
+
+	return spec
+}
+
+// openAPISpec returns the specification, built once.
+var openAPISpec = sync.OnceValue(buildOpenAPISpec)
+
+// operationID derives an operation ID from the method and path, e.g.
+// getV1SensorStats for GET /v1/sensor/stats.
+func operationID(method, path string) string {
+	var sb strings.Builder
+	sb.WriteString(method)
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '{' || r == '}' }) {
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return sb.String()
+}
+
+// schemaGenerator converts Go types to schemas as encoding/json marshals
+// them. Named struct types become components referenced by name.
+type schemaGenerator struct {
+	components map[string]*OpenAPISchema
+	names      map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		components: map[string]*OpenAPISchema{},
+		names:      map[reflect.Type]string{},
+	}
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaNameCleaner removes the characters of generic type names that are not
+// allowed in component names.
+var schemaNameCleaner = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// schema returns the schema of t.
+func (g *schemaGenerator) schema(t reflect.Type) *OpenAPISchema {
+	if t.Kind() == reflect.Pointer {
+		s := g.schema(t.Elem())
+		if s.Ref != "" {
+			// Siblings of $ref are ignored in OpenAPI 3.0
+			return &OpenAPISchema{OneOf: []*OpenAPISchema{s}, Nullable: true}
+		}
+		s.Nullable = true
+		return s
+	}
+
+	switch {
+	case t == timeType:
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &OpenAPISchema{}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &OpenAPISchema{Description: "Free-form JSON"}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &OpenAPISchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+		return &OpenAPISchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return &OpenAPISchema{Ref: "#/components/schemas/" + g.component(t)}
+	default:
+		// Interfaces: any JSON value
+		return &OpenAPISchema{}
+	}
+}
+
+// component registers the schema of the named struct t and returns its name.
+// A name already used by a type of another package is prefixed with the
+// package name.
+func (g *schemaGenerator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := schemaNameCleaner.ReplaceAllString(t.Name(), "")
+	if _, taken := g.components[name]; taken {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+
+	// Registered before the fields, for recursive types
+	g.names[t] = name
+	g.components[name] = &OpenAPISchema{}
+	*g.components[name] = *g.object(t)
+	return name
+}
+
+// object returns the schema of the struct t: its exported fields by JSON
+// name, embedded structs flattened. Fields without omitempty are required.
+func (g *schemaGenerator) object(t reflect.Type) *OpenAPISchema {
+	s := &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{}}
+	g.addFields(s, t)
+	return s
+}
+
+func (g *schemaGenerator) addFields(s *OpenAPISchema, t reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(s, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := g.schema(field.Type)
+		if strings.Contains(opts, "string") {
+			fieldSchema = &OpenAPISchema{Type: "string"}
+		}
+		s.Properties[name] = fieldSchema
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// handleGetOpenAPI handles GET /v1/openapi.json
+// Serves the OpenAPI 3 specification of the glucose, sensor, monitoring and
+// event stream endpoints, generated from the response types.
+func (s *Server) handleGetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if err := writeJSONResponse(w, http.StatusOK, openAPISpec()); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetAPIDocs handles GET /v1/docs
+// Serves Swagger UI browsing /v1/openapi.json.
+func (s *Server) handleGetAPIDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(apiDocsPage); err != nil {
+		s.logger.Error("failed to write API docs page", "error", err)
+	}
+}
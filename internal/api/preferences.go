@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// handleGetPreferences handles GET /v1/preferences
+// Returns the saved display preferences, or the defaults if none were saved.
+func (s *Server) handleGetPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	prefs, err := s.configService.GetDisplayPreferences(ctx)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := PreferencesResponse{
+		Data: prefs,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handlePutPreferences handles PUT /v1/preferences
+// Replaces the display preferences. The tight range band applies to
+// statistics right away.
+func (s *Server) handlePutPreferences(w http.ResponseWriter, r *http.Request) {
+	prefs, err := parseDisplayPreferences(w, r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := s.configService.SaveDisplayPreferences(ctx, prefs); err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+	s.glucoseService.SetTightBand(prefs.TightBand())
+
+	response := PreferencesResponse{
+		Data: prefs,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// glucoseDisplay formats a measurement with the display preferences.
+// It returns nil when the preferences cannot be read: the convenience field
+// must not fail the request.
+func (s *Server) glucoseDisplay(ctx context.Context, m *domain.GlucoseMeasurement) *GlucoseDisplay {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	prefs, err := s.configService.GetDisplayPreferences(ctx)
+	if err != nil {
+		s.logger.Warn("failed to read display preferences", "error", err)
+		return nil
+	}
+
+	loc := prefs.Location()
+	return &GlucoseDisplay{
+		Value:     prefs.FormatValue(m),
+		LocalTime: m.Timestamp.In(loc).Format("15:04"),
+		Timezone:  loc.String(),
+	}
+}
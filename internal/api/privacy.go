@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+// privacyTimeout bounds the export and erasure, which touch every table.
+const privacyTimeout = 60 * time.Second
+
+// handleGetPrivacyExport handles GET /v1/privacy/export
+// Returns every stored personal record as a single JSON document.
+func (s *Server) handleGetPrivacyExport(w http.ResponseWriter, r *http.Request) {
+	if s.privacyService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Privacy endpoints not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), privacyTimeout)
+	defer cancel()
+
+	// The export can be large: allow more than the server write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(privacyTimeout)); err != nil {
+		s.logger.Warn("failed to extend write deadline for privacy export", "error", err)
+	}
+
+	export, err := s.privacyService.Export(ctx)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := PrivacyExportResponse{
+		Data: export,
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="glcmd-export.json"`)
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleRequestErasure handles POST /v1/privacy/erase/confirmation
+// Issues the confirmation token required by POST /v1/privacy/erase.
+func (s *Server) handleRequestErasure(w http.ResponseWriter, r *http.Request) {
+	if s.privacyService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Privacy endpoints not available")
+		return
+	}
+
+	confirmation, err := s.privacyService.RequestErasure()
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := ErasureConfirmationResponse{
+		Data: confirmation,
+	}
+
+	if err := writeJSONResponse(w, http.StatusCreated, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleErase handles POST /v1/privacy/erase
+// Deletes all personal data. Body: {"confirmationToken": "..."}
+func (s *Server) handleErase(w http.ResponseWriter, r *http.Request) {
+	if s.privacyService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Privacy endpoints not available")
+		return
+	}
+
+	req, err := parseEraseRequest(w, r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), privacyTimeout)
+	defer cancel()
+
+	result, err := s.privacyService.Erase(ctx, req.ConfirmationToken)
+	if errors.Is(err, service.ErrErasureConfirmation) {
+		handleError(w, NewValidationError("confirmationToken is invalid or expired (request a new one with POST /v1/privacy/erase/confirmation)"), s.logger)
+		return
+	}
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	// Erasure deletes the attachment rows: remove their files too
+	if s.attachmentService != nil {
+		if _, err := s.attachmentService.PruneFiles(ctx); err != nil {
+			s.logger.Error("failed to remove attachment files after erasure", "error", err)
+		}
+	}
+
+	response := ErasureResponse{
+		Data: result,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
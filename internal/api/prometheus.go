@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// prometheusContentType is the Prometheus text exposition format, version 0.0.4
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// wantsPrometheus reports whether GET /metrics should answer in the Prometheus
+// text format: with ?format=prometheus, or when the Accept header asks for
+// text (as Prometheus scrapers do) rather than JSON.
+func wantsPrometheus(r *http.Request) bool {
+	switch r.URL.Query().Get("format") {
+	case "prometheus":
+		return true
+	case "json":
+		return false
+	}
+
+	accept := r.Header.Get("Accept")
+	if strings.Contains(accept, "application/json") {
+		return false
+	}
+	return strings.Contains(accept, "text/plain") || strings.Contains(accept, "application/openmetrics-text")
+}
+
+// promWriter writes metric families in the Prometheus text format.
+// The first write error is kept and later writes are skipped.
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+// header writes the HELP and TYPE lines of a metric family.
+func (p *promWriter) header(name, typ, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (p *promWriter) gauge(name, help string, value float64) {
+	p.header(name, "gauge", help)
+	p.printf("%s %s\n", name, formatPromValue(value))
+}
+
+func (p *promWriter) counter(name, help string, value float64) {
+	p.header(name, "counter", help)
+	p.printf("%s %s\n", name, formatPromValue(value))
+}
+
+// histogram writes a histogram from cumulative bucket counts; count is the
+// +Inf bucket.
+func (p *promWriter) histogram(name, help string, les []float64, counts []int64, count int64, sum float64) {
+	p.header(name, "histogram", help)
+	for i, le := range les {
+		p.printf("%s_bucket{le=\"%s\"} %d\n", name, formatPromValue(le), counts[i])
+	}
+	p.printf("%s_bucket{le=\"+Inf\"} %d\n", name, count)
+	p.printf("%s_sum %s\n", name, formatPromValue(sum))
+	p.printf("%s_count %d\n", name, count)
+}
+
+// formatPromValue formats a sample value with the shortest exact representation
+func formatPromValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// writePrometheusMetrics answers GET /metrics in the Prometheus text format.
+// It covers the same data as the JSON response plus the latest glucose value,
+// with raw units (bytes, seconds) instead of rounded ones.
+func (s *Server) writePrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	w.Header().Set("Content-Type", prometheusContentType)
+	w.WriteHeader(http.StatusOK)
+	p := &promWriter{w: w}
+
+	// Process
+	p.gauge("glcmd_uptime_seconds", "Time since glcore started.", time.Since(s.startTime).Seconds())
+	p.gauge("go_goroutines", "Number of goroutines that currently exist.", float64(runtime.NumGoroutine()))
+	p.gauge("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", float64(m.Alloc))
+	p.gauge("go_memstats_sys_bytes", "Number of bytes obtained from the system.", float64(m.Sys))
+	p.counter("go_gc_cycles_total", "Number of completed GC cycles.", float64(m.NumGC))
+
+	// Fetch cycles
+	if s.getFetchStats != nil {
+		stats := s.getFetchStats()
+		p.counter("glcmd_fetch_cycles_total", "Periodic fetch cycles.", float64(stats.Cycles))
+		p.counter("glcmd_fetch_errors_total", "Periodic fetch cycles that failed.", float64(stats.Failed))
+		p.counter("glcmd_fetch_measurements_stored_total", "New measurements saved.", float64(stats.MeasurementsStored))
+		p.counter("glcmd_fetch_duplicates_skipped_total", "Fetched measurements already stored.", float64(stats.DuplicatesSkipped))
+		p.counter("glcmd_fetch_reauthentications_total", "Expired LibreView tokens renewed during a fetch.", float64(stats.Reauthentications))
+		p.counter("glcmd_fetch_watchdog_recoveries_total", "Stuck fetch cycles aborted by the watchdog.", float64(stats.WatchdogRecoveries))
+
+		les := make([]float64, len(stats.Duration.Buckets))
+		counts := make([]int64, len(stats.Duration.Buckets))
+		for i, bucket := range stats.Duration.Buckets {
+			les[i] = bucket.LeSeconds
+			counts[i] = bucket.Count
+		}
+		p.histogram("glcmd_fetch_duration_seconds", "Duration of the periodic fetch cycles.",
+			les, counts, stats.Duration.Count, stats.Duration.SumSeconds)
+	}
+
+	// SSE
+	if s.eventBroker != nil {
+		p.gauge("glcmd_sse_subscribers", "Connected event stream subscribers.", float64(s.eventBroker.SubscriberCount()))
+	}
+
+	// Database pool
+	if s.getDatabasePoolStats != nil {
+		if stats := s.getDatabasePoolStats(); stats != nil {
+			p.gauge("glcmd_db_open_connections", "Open database connections.", float64(stats.OpenConnections))
+			p.gauge("glcmd_db_in_use_connections", "Database connections currently in use.", float64(stats.InUse))
+			p.gauge("glcmd_db_idle_connections", "Idle database connections.", float64(stats.Idle))
+			p.counter("glcmd_db_wait_count_total", "Database connections waited for.", float64(stats.WaitCount))
+			if wait, err := time.ParseDuration(stats.WaitDuration); err == nil {
+				p.counter("glcmd_db_wait_duration_seconds_total", "Time blocked waiting for a database connection.", wait.Seconds())
+			}
+		}
+	}
+
+	// Latest glucose value
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+	latest, err := s.glucoseService.GetLatestMeasurement(ctx)
+	switch {
+	case err == nil:
+		p.gauge("glcmd_glucose_mgdl", "Latest glucose value in mg/dL.", float64(latest.ValueInMgPerDl))
+		p.gauge("glcmd_glucose_timestamp_seconds", "Unix time of the latest glucose value.", float64(latest.Timestamp.Unix()))
+	case !errors.Is(err, persistence.ErrNotFound):
+		s.logger.Warn("failed to read latest glucose for metrics", "error", err)
+	}
+
+	if p.err != nil {
+		s.logger.Error("failed to write metrics response", "error", p.err)
+	}
+}
@@ -1,18 +1,76 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/R4yL-dev/glcmd/internal/backfill"
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/pumpcsv"
 	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/internal/service"
+	"github.com/R4yL-dev/glcmd/internal/utils/filterexpr"
+	"github.com/R4yL-dev/glcmd/internal/utils/periodparser"
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
 )
 
 const (
 	defaultLimit  = 100
 	maxLimit      = 1000
 	defaultOffset = 0
+
+	// maxBodyBytes limits the size of JSON request bodies
+	maxBodyBytes = 64 * 1024
+	// maxImportBytes limits the size of uploaded pump exports
+	maxImportBytes = 10 << 20
+	// maxAttachmentCaptionLength limits the caption of sensor attachments
+	maxAttachmentCaptionLength = 500
+	// defaultTreatmentRange is the period returned when no time range is given
+	defaultTreatmentRange = 24 * time.Hour
+	// defaultLogbookRange is the period of logged insulin and carbs returned when no time range is given
+	defaultLogbookRange = 24 * time.Hour
+	// defaultDailySummaryRange is the period of daily summaries returned when no time range is given
+	defaultDailySummaryRange = 30 * 24 * time.Hour
+	// defaultAnalysisRange is the period analyzed when no time range is given
+	defaultAnalysisRange = 14 * 24 * time.Hour
+	// defaultQualityRange is the period scored when no time range is given
+	defaultQualityRange = 14 * 24 * time.Hour
+	// defaultLogsSince is how far back logs are returned when since is not given
+	defaultLogsSince = time.Hour
+	// maxDashboardCards limits the number of cards in a dashboard layout
+	maxDashboardCards = 20
+	// maxTokenNameLength limits the length of API token names
+	maxTokenNameLength = 100
+	// maxViewNameLength and maxViewDescriptionLength limit saved view fields
+	maxViewNameLength        = 64
+	maxViewDescriptionLength = 255
+	// defaultBucketMgDl and maxBucketMgDl bound the glucose histogram bucket size
+	defaultBucketMgDl = 10
+	maxBucketMgDl     = 100
+	// defaultPercentileDays and maxPercentileDays bound the glucose percentiles period
+	defaultPercentileDays = 14
+	maxPercentileDays     = 90
+	// defaultPercentileBucket, minPercentileBucket and maxPercentileBucket
+	// bound the time-of-day bucket of the glucose percentiles
+	defaultPercentileBucket = 30 * time.Minute
+	minPercentileBucket     = 5 * time.Minute
+	maxPercentileBucket     = 2 * time.Hour
+	// defaultAlertWeeks and maxAlertWeeks bound the weekly alert counts
+	defaultAlertWeeks = 4
+	maxAlertWeeks     = 52
+	// defaultOutageDays and maxOutageDays bound the upstream outage history
+	defaultOutageDays = 30
+	maxOutageDays     = 365
 )
 
 // parsePaginationParams parses limit and offset from query parameters
@@ -51,6 +109,59 @@ func parsePaginationParams(r *http.Request) (limit, offset int, err error) {
 	return limit, offset, nil
 }
 
+// parseEncoding parses the optional encoding query parameter.
+// Returns "" for the default JSON representation.
+func parseEncoding(r *http.Request) (string, error) {
+	switch encoding := r.URL.Query().Get("encoding"); encoding {
+	case "", "json":
+		return "", nil
+	case glclient.EncodingDelta:
+		return encoding, nil
+	default:
+		return "", NewValidationError(fmt.Sprintf("invalid encoding %q (use json or delta)", encoding))
+	}
+}
+
+// parseDebug parses the optional debug query parameter, which adds the fetch
+// provenance of each glucose measurement to the response.
+func parseDebug(r *http.Request) (bool, error) {
+	debugStr := r.URL.Query().Get("debug")
+	if debugStr == "" {
+		return false, nil
+	}
+	debug, err := strconv.ParseBool(debugStr)
+	if err != nil {
+		return false, NewValidationError("invalid debug parameter (use true or false)")
+	}
+	return debug, nil
+}
+
+// parseAsync parses the optional async query parameter, which runs an
+// import as a background job.
+func parseAsync(r *http.Request) (bool, error) {
+	asyncStr := r.URL.Query().Get("async")
+	if asyncStr == "" {
+		return false, nil
+	}
+	async, err := strconv.ParseBool(asyncStr)
+	if err != nil {
+		return false, NewValidationError("invalid async parameter (use true or false)")
+	}
+	return async, nil
+}
+
+// parseExportFormat parses the optional format query parameter of the glucose export (default csv).
+func parseExportFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "", exportFormatCSV:
+		return exportFormatCSV, nil
+	case exportFormatTSV:
+		return format, nil
+	default:
+		return "", NewValidationError(fmt.Sprintf("invalid format %q (use csv or tsv)", format))
+	}
+}
+
 // parseTimeRange parses optional start/end query parameters as RFC3339 timestamps
 // and validates that end is after start when both are provided.
 func parseTimeRange(r *http.Request) (start, end *time.Time, err error) {
@@ -114,6 +225,15 @@ func parseGlucoseFilters(r *http.Request) (repository.GlucoseFilters, error) {
 		filters.Type = &measurementType
 	}
 
+	// Parse filter expression (e.g. "value_mgdl > 180 AND hour in 0..6")
+	if q := r.URL.Query().Get("q"); q != "" {
+		expr, err := filterexpr.Parse(q, repository.GlucoseQueryFields)
+		if err != nil {
+			return filters, NewValidationError(fmt.Sprintf("invalid q parameter: %v", err))
+		}
+		filters.Query = expr
+	}
+
 	return filters, nil
 }
 
@@ -131,6 +251,222 @@ func parseSensorFilters(r *http.Request) (repository.SensorFilters, error) {
 	return filters, nil
 }
 
+// parseAlertFilters parses filter parameters for alert history queries
+func parseAlertFilters(r *http.Request) (repository.AlertFilters, error) {
+	filters := repository.AlertFilters{}
+
+	start, end, err := parseTimeRange(r)
+	if err != nil {
+		return filters, err
+	}
+	filters.StartTime = start
+	filters.EndTime = end
+
+	if alertType := r.URL.Query().Get("type"); alertType != "" {
+		if !slices.Contains(domain.AlertTypes, alertType) {
+			return filters, NewValidationError(fmt.Sprintf("invalid type %q (use low or falling)", alertType))
+		}
+		filters.Type = &alertType
+	}
+
+	if ackStr := r.URL.Query().Get("acknowledged"); ackStr != "" {
+		acknowledged, err := strconv.ParseBool(ackStr)
+		if err != nil {
+			return filters, NewValidationError("invalid acknowledged parameter (use true or false)")
+		}
+		filters.Acknowledged = &acknowledged
+	}
+
+	return filters, nil
+}
+
+// parseGlucoseEventFilters parses the time range and type query parameters
+// of the glucose event list.
+func parseGlucoseEventFilters(r *http.Request) (repository.GlucoseEventFilters, error) {
+	filters := repository.GlucoseEventFilters{}
+
+	start, end, err := parseTimeRange(r)
+	if err != nil {
+		return filters, err
+	}
+	filters.StartTime = start
+	filters.EndTime = end
+
+	if eventType := r.URL.Query().Get("type"); eventType != "" {
+		if !slices.Contains(domain.GlucoseEventTypes, eventType) {
+			return filters, NewValidationError(fmt.Sprintf("invalid type %q (use low or high)", eventType))
+		}
+		filters.Type = &eventType
+	}
+
+	return filters, nil
+}
+
+// parseNoteFilters parses the time range and tag query parameters of the note list.
+func parseNoteFilters(r *http.Request) (repository.NoteFilters, error) {
+	filters := repository.NoteFilters{}
+
+	start, end, err := parseTimeRange(r)
+	if err != nil {
+		return filters, err
+	}
+	filters.StartTime = start
+	filters.EndTime = end
+
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		if !slices.Contains(domain.NoteTags, tag) {
+			return filters, NewValidationError(fmt.Sprintf("invalid tag %q (use %s)", tag, strings.Join(domain.NoteTags, ", ")))
+		}
+		filters.Tag = &tag
+	}
+
+	return filters, nil
+}
+
+// parseJobFilters parses the type and status query parameters of the job list.
+func parseJobFilters(r *http.Request) (repository.JobFilters, error) {
+	filters := repository.JobFilters{}
+
+	if jobType := r.URL.Query().Get("type"); jobType != "" {
+		filters.Type = &jobType
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		if !slices.Contains(domain.JobStatuses, status) {
+			return filters, NewValidationError(fmt.Sprintf("invalid status %q (use %s)", status, strings.Join(domain.JobStatuses, ", ")))
+		}
+		filters.Status = &status
+	}
+
+	return filters, nil
+}
+
+// parseLogsParams parses the since and level query parameters of the log export.
+// since is a duration before now (default 1h); level is the minimum level (default debug).
+func parseLogsParams(r *http.Request) (since time.Time, level slog.Level, err error) {
+	window := defaultLogsSince
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		window, err = time.ParseDuration(sinceStr)
+		if err != nil || window <= 0 {
+			return time.Time{}, 0, NewValidationError(fmt.Sprintf("invalid since %q (use a positive duration, e.g. 1h)", sinceStr))
+		}
+	}
+
+	level = slog.LevelDebug
+	if levelStr := r.URL.Query().Get("level"); levelStr != "" {
+		if err := level.UnmarshalText([]byte(levelStr)); err != nil {
+			return time.Time{}, 0, NewValidationError(fmt.Sprintf("invalid level %q (use debug, info, warn or error)", levelStr))
+		}
+	}
+
+	return time.Now().Add(-window), level, nil
+}
+
+// parseAlertWeeks parses the optional weeks query parameter of the weekly alert counts.
+func parseAlertWeeks(r *http.Request) (int, error) {
+	weeksStr := r.URL.Query().Get("weeks")
+	if weeksStr == "" {
+		return defaultAlertWeeks, nil
+	}
+
+	weeks, err := strconv.Atoi(weeksStr)
+	if err != nil {
+		return 0, NewValidationError("invalid weeks parameter")
+	}
+	if weeks < 1 || weeks > maxAlertWeeks {
+		return 0, NewValidationError(fmt.Sprintf("weeks must be between 1 and %d", maxAlertWeeks))
+	}
+	return weeks, nil
+}
+
+// parseOutageDays parses the number of days of upstream outage history to return.
+func parseOutageDays(r *http.Request) (int, error) {
+	daysStr := r.URL.Query().Get("days")
+	if daysStr == "" {
+		return defaultOutageDays, nil
+	}
+
+	days, err := strconv.Atoi(daysStr)
+	if err != nil {
+		return 0, NewValidationError("invalid days parameter")
+	}
+	if days < 1 || days > maxOutageDays {
+		return 0, NewValidationError(fmt.Sprintf("days must be between 1 and %d", maxOutageDays))
+	}
+	return days, nil
+}
+
+// parseTreatmentRange parses the optional start/end of a treatment query.
+// Defaults to the last 24 hours; a missing bound is derived from the other one.
+func parseTreatmentRange(r *http.Request) (start, end time.Time, err error) {
+	return parseBoundedRange(r, defaultTreatmentRange)
+}
+
+// parseLogbookRange parses the optional start/end of an insulin or carbs query.
+// Defaults to the last 24 hours; a missing bound is derived from the other one.
+func parseLogbookRange(r *http.Request) (start, end time.Time, err error) {
+	return parseBoundedRange(r, defaultLogbookRange)
+}
+
+// parseDailySummaryRange parses the optional start/end of a daily summaries
+// query. Defaults to the last 30 days; a missing bound is derived from the
+// other one.
+func parseDailySummaryRange(r *http.Request) (start, end time.Time, err error) {
+	return parseBoundedRange(r, defaultDailySummaryRange)
+}
+
+// parseAnalysisRange parses the optional start/end of a treatment analysis.
+// Defaults to the last 14 days; a missing bound is derived from the other one.
+func parseAnalysisRange(r *http.Request) (start, end time.Time, err error) {
+	return parseBoundedRange(r, defaultAnalysisRange)
+}
+
+// parseQualityRange parses the optional start/end of a data quality query.
+// Defaults to the last 14 days; a missing bound is derived from the other one.
+func parseQualityRange(r *http.Request) (start, end time.Time, err error) {
+	return parseBoundedRange(r, defaultQualityRange)
+}
+
+// parseBoundedRange parses an optional start/end, filling missing bounds so
+// that the range spans period.
+func parseBoundedRange(r *http.Request, period time.Duration) (start, end time.Time, err error) {
+	startPtr, endPtr, err := parseTimeRange(r)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	switch {
+	case startPtr != nil && endPtr != nil:
+		return *startPtr, *endPtr, nil
+	case startPtr != nil:
+		return *startPtr, startPtr.Add(period), nil
+	case endPtr != nil:
+		return endPtr.Add(-period), *endPtr, nil
+	default:
+		end = time.Now().UTC()
+		return end.Add(-period), end, nil
+	}
+}
+
+// parseTreatmentImport parses a pump CSV export from the request body.
+// The source query parameter selects the export format.
+func parseTreatmentImport(w http.ResponseWriter, r *http.Request) (*pumpcsv.Result, error) {
+	source := r.URL.Query().Get("source")
+	if !slices.Contains(domain.TreatmentSources, source) {
+		return nil, NewValidationError(fmt.Sprintf("invalid source %q (use %s)", source, strings.Join(domain.TreatmentSources, " or ")))
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	// Pump exports use the pump's local time, assumed to be the server's
+	result, err := pumpcsv.Parse(source, r.Body, time.Local)
+	if err != nil {
+		return nil, NewValidationError(fmt.Sprintf("invalid %s export: %v", source, err))
+	}
+
+	return result, nil
+}
+
 // parseStatisticsParams parses and validates statistics request parameters.
 // Returns nil for start/end if not provided (all time query).
 // Both parameters must be provided together or not at all.
@@ -150,3 +486,558 @@ func parseStatisticsParams(r *http.Request) (start, end *time.Time, err error) {
 
 	return parseTimeRange(r)
 }
+
+// parseSensorStatisticsParams parses the activation period (start and end) and
+// the end period (endedStart and endedEnd) of sensor statistics. Each period
+// is optional, its two bounds must be provided together.
+func parseSensorStatisticsParams(r *http.Request) (filters repository.SensorStatisticsFilters, err error) {
+	filters.StartTime, filters.EndTime, err = parseStatisticsParams(r)
+	if err != nil {
+		return filters, err
+	}
+
+	endedStartStr := r.URL.Query().Get("endedStart")
+	endedEndStr := r.URL.Query().Get("endedEnd")
+	if (endedStartStr == "") != (endedEndStr == "") {
+		return filters, NewValidationError("both endedStart and endedEnd must be provided, or neither")
+	}
+	if endedStartStr == "" {
+		return filters, nil
+	}
+
+	endedStart, err := time.Parse(time.RFC3339, endedStartStr)
+	if err != nil {
+		return filters, NewValidationError("invalid endedStart time format (use RFC3339)")
+	}
+	endedEnd, err := time.Parse(time.RFC3339, endedEndStr)
+	if err != nil {
+		return filters, NewValidationError("invalid endedEnd time format (use RFC3339)")
+	}
+	if endedEnd.Before(endedStart) {
+		return filters, NewValidationError("endedEnd must be after endedStart")
+	}
+	filters.EndedStart, filters.EndedEnd = &endedStart, &endedEnd
+
+	return filters, nil
+}
+
+// parseHistogramParams parses the period (start and end, or neither for all
+// time), the optional q filter expression and bucketMgDl of a histogram.
+func parseHistogramParams(r *http.Request) (filters repository.GlucoseFilters, bucketMgDl int, err error) {
+	filters.StartTime, filters.EndTime, err = parseStatisticsParams(r)
+	if err != nil {
+		return filters, 0, err
+	}
+
+	if q := r.URL.Query().Get("q"); q != "" {
+		expr, err := filterexpr.Parse(q, repository.GlucoseQueryFields)
+		if err != nil {
+			return filters, 0, NewValidationError(fmt.Sprintf("invalid q parameter: %v", err))
+		}
+		filters.Query = expr
+	}
+
+	bucketMgDl = defaultBucketMgDl
+	if bucketStr := r.URL.Query().Get("bucketMgDl"); bucketStr != "" {
+		bucketMgDl, err = strconv.Atoi(bucketStr)
+		if err != nil || bucketMgDl < 1 || bucketMgDl > maxBucketMgDl {
+			return filters, 0, NewValidationError(fmt.Sprintf("bucketMgDl must be between 1 and %d", maxBucketMgDl))
+		}
+	}
+
+	return filters, bucketMgDl, nil
+}
+
+// parsePercentilesParams parses the optional days (period ending now) and
+// bucket (time-of-day bucket, a duration dividing a day) of glucose percentiles.
+func parsePercentilesParams(r *http.Request) (start, end time.Time, bucket time.Duration, err error) {
+	days := defaultPercentileDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days < 1 || days > maxPercentileDays {
+			return time.Time{}, time.Time{}, 0, NewValidationError(fmt.Sprintf("days must be between 1 and %d", maxPercentileDays))
+		}
+	}
+
+	bucket = defaultPercentileBucket
+	if bucketStr := r.URL.Query().Get("bucket"); bucketStr != "" {
+		bucket, err = time.ParseDuration(bucketStr)
+		if err != nil || bucket < minPercentileBucket || bucket > maxPercentileBucket ||
+			bucket%time.Minute != 0 || (24*time.Hour)%bucket != 0 {
+			return time.Time{}, time.Time{}, 0, NewValidationError(
+				fmt.Sprintf("bucket must be a duration between %s and %s dividing a day (e.g. 30m)", minPercentileBucket, maxPercentileBucket))
+		}
+	}
+
+	end = time.Now().UTC()
+	return end.AddDate(0, 0, -days), end, bucket, nil
+}
+
+// decodeJSONBody decodes a JSON request body into dst, rejecting unknown fields
+// and bodies larger than maxBodyBytes.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return NewValidationError(fmt.Sprintf("invalid JSON body: %v", err))
+	}
+
+	return nil
+}
+
+// parseDashboardConfig parses and validates a dashboard layout from the request body.
+func parseDashboardConfig(w http.ResponseWriter, r *http.Request) (*domain.DashboardConfig, error) {
+	var config domain.DashboardConfig
+	if err := decodeJSONBody(w, r, &config); err != nil {
+		return nil, err
+	}
+
+	if len(config.Cards) == 0 {
+		return nil, NewValidationError("cards must contain at least one card")
+	}
+	if len(config.Cards) > maxDashboardCards {
+		return nil, NewValidationError(fmt.Sprintf("cards must not exceed %d entries", maxDashboardCards))
+	}
+	for i, card := range config.Cards {
+		if !slices.Contains(domain.DashboardCardTypes, card.Type) {
+			return nil, NewValidationError(fmt.Sprintf("cards[%d]: unknown type %q", i, card.Type))
+		}
+		if card.Period != "" {
+			if _, err := periodparser.ParseDuration(card.Period); err != nil {
+				return nil, NewValidationError(fmt.Sprintf("cards[%d]: %v", i, err))
+			}
+		}
+	}
+
+	if _, err := periodparser.ParseDuration(config.ChartRange); err != nil {
+		return nil, NewValidationError(fmt.Sprintf("chartRange: %v", err))
+	}
+
+	if config.Unit != "mmol" && config.Unit != "mgdl" {
+		return nil, NewValidationError("unit must be mmol or mgdl")
+	}
+
+	if config.RefreshSeconds < 10 || config.RefreshSeconds > 3600 {
+		return nil, NewValidationError("refreshSeconds must be between 10 and 3600")
+	}
+
+	return &config, nil
+}
+
+// parseDisplayPreferences parses and validates display preferences from the request body.
+func parseDisplayPreferences(w http.ResponseWriter, r *http.Request) (*domain.DisplayPreferences, error) {
+	var prefs domain.DisplayPreferences
+	if err := decodeJSONBody(w, r, &prefs); err != nil {
+		return nil, err
+	}
+
+	if prefs.Unit != domain.DisplayUnitMmol && prefs.Unit != domain.DisplayUnitMgDl {
+		return nil, NewValidationError("unit must be mmol or mgdl")
+	}
+
+	if prefs.Timezone != "" {
+		if _, err := time.LoadLocation(prefs.Timezone); err != nil {
+			return nil, NewValidationError(fmt.Sprintf("timezone: unknown time zone %q", prefs.Timezone))
+		}
+	}
+
+	if prefs.TightLowMgDl < 20 || prefs.TightHighMgDl > 500 || prefs.TightLowMgDl >= prefs.TightHighMgDl {
+		return nil, NewValidationError("tightLowMgDl and tightHighMgDl must satisfy 20 <= low < high <= 500")
+	}
+
+	if _, err := periodparser.ParseDuration(prefs.ChartRange); err != nil {
+		return nil, NewValidationError(fmt.Sprintf("chartRange: %v", err))
+	}
+
+	return &prefs, nil
+}
+
+// parseExerciseDuration parses the required duration query parameter (e.g. 45m, 1h30m).
+func parseExerciseDuration(r *http.Request) (time.Duration, error) {
+	value := r.URL.Query().Get("duration")
+	if value == "" {
+		return 0, NewValidationError("duration parameter is required")
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, NewValidationError(fmt.Sprintf("invalid duration %q, expected e.g. 45m or 1h", value))
+	}
+	if duration < time.Minute || duration > service.MaxExerciseDuration {
+		return 0, NewValidationError(fmt.Sprintf("duration must be between 1m and %s", service.MaxExerciseDuration))
+	}
+
+	return duration, nil
+}
+
+// SensorSiteRequest is the body of PUT /v1/sensor/latest/site
+type SensorSiteRequest struct {
+	Site string `json:"site"`
+}
+
+// parseSensorSiteRequest decodes and validates a sensor application site.
+func parseSensorSiteRequest(w http.ResponseWriter, r *http.Request) (string, error) {
+	var req SensorSiteRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		return "", err
+	}
+
+	if !slices.Contains(domain.SensorApplicationSites, req.Site) {
+		return "", NewValidationError(fmt.Sprintf("site must be one of %v", domain.SensorApplicationSites))
+	}
+
+	return req.Site, nil
+}
+
+// SensorFeedbackRequest is the body of PATCH /v1/sensor/{serial}.
+// Omitted fields are left unchanged; an empty note or a zero rating clears it.
+type SensorFeedbackRequest struct {
+	Note   *string `json:"note"`
+	Rating *int    `json:"rating"`
+}
+
+// parseSensorFeedbackRequest decodes and validates a sensor note and rating update.
+func parseSensorFeedbackRequest(w http.ResponseWriter, r *http.Request) (service.SensorFeedback, error) {
+	var req SensorFeedbackRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		return service.SensorFeedback{}, err
+	}
+
+	if req.Note == nil && req.Rating == nil {
+		return service.SensorFeedback{}, NewValidationError("note or rating is required")
+	}
+	if req.Note != nil {
+		note := strings.TrimSpace(*req.Note)
+		if utf8.RuneCountInString(note) > domain.MaxSensorNoteLength {
+			return service.SensorFeedback{}, NewValidationError(fmt.Sprintf("note must not exceed %d characters", domain.MaxSensorNoteLength))
+		}
+		req.Note = &note
+	}
+	if req.Rating != nil && *req.Rating != 0 &&
+		(*req.Rating < domain.MinSensorRating || *req.Rating > domain.MaxSensorRating) {
+		return service.SensorFeedback{}, NewValidationError(fmt.Sprintf("rating must be between %d and %d (0 clears it)", domain.MinSensorRating, domain.MaxSensorRating))
+	}
+
+	return service.SensorFeedback{Note: req.Note, Rating: req.Rating}, nil
+}
+
+// CreateTokenRequest is the body of POST /v1/admin/tokens
+type CreateTokenRequest struct {
+	Name      string `json:"name"`
+	Scope     string `json:"scope"`
+	ExpiresIn string `json:"expiresIn,omitempty"` // Period like 30d or 12h; empty = never expires
+}
+
+// parseCreateTokenRequest decodes and validates a token creation request.
+// Returns the request and the computed expiry (nil = never expires).
+func parseCreateTokenRequest(w http.ResponseWriter, r *http.Request) (*CreateTokenRequest, *time.Time, error) {
+	var req CreateTokenRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		return nil, nil, err
+	}
+
+	if req.Name == "" {
+		return nil, nil, NewValidationError("name is required")
+	}
+	if len(req.Name) > maxTokenNameLength {
+		return nil, nil, NewValidationError(fmt.Sprintf("name must not exceed %d characters", maxTokenNameLength))
+	}
+
+	if !slices.Contains(domain.TokenScopes, req.Scope) {
+		return nil, nil, NewValidationError(fmt.Sprintf("scope must be one of %v", domain.TokenScopes))
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresIn != "" {
+		duration, err := periodparser.ParseDuration(req.ExpiresIn)
+		if err != nil {
+			return nil, nil, NewValidationError(fmt.Sprintf("expiresIn: %v", err))
+		}
+		expiry := time.Now().UTC().Add(duration)
+		expiresAt = &expiry
+	}
+
+	return &req, expiresAt, nil
+}
+
+// EraseRequest is the body of POST /v1/privacy/erase
+type EraseRequest struct {
+	ConfirmationToken string `json:"confirmationToken"`
+}
+
+// parseEraseRequest decodes and validates an erasure request.
+func parseEraseRequest(w http.ResponseWriter, r *http.Request) (*EraseRequest, error) {
+	var req EraseRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		return nil, err
+	}
+
+	if req.ConfirmationToken == "" {
+		return nil, NewValidationError("confirmationToken is required (request one with POST /v1/privacy/erase/confirmation)")
+	}
+
+	return &req, nil
+}
+
+// parseBackfillRequest decodes and validates a backfill request
+// (POST /v1/admin/backfill).
+func parseBackfillRequest(w http.ResponseWriter, r *http.Request) (*backfill.Payload, error) {
+	var req backfill.Payload
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Days < 1 || req.Days > backfill.MaxDays {
+		return nil, NewValidationError(fmt.Sprintf("days must be between 1 and %d", backfill.MaxDays))
+	}
+
+	return &req, nil
+}
+
+// viewNamePattern matches saved view names: lowercase words separated by dashes.
+var viewNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// SaveViewRequest represents the body of PUT /v1/views/{name}
+type SaveViewRequest struct {
+	Description string `json:"description"`
+	Query       string `json:"query"`       // Filter expression (empty = all measurements)
+	Period      string `json:"period"`      // e.g. "30d", "today" (empty = all time)
+	Aggregation string `json:"aggregation"` // "list" or "stats"
+}
+
+// parseViewName validates a saved view name from the URL.
+func parseViewName(value string) (string, error) {
+	if len(value) > maxViewNameLength || !viewNamePattern.MatchString(value) {
+		return "", NewValidationError(fmt.Sprintf("view name must be lowercase letters, digits and dashes (at most %d characters)", maxViewNameLength))
+	}
+	return value, nil
+}
+
+// parseSaveViewRequest parses and validates a saved view definition.
+// The query and period are checked now so a stored view always runs.
+func parseSaveViewRequest(w http.ResponseWriter, r *http.Request, name string) (*domain.SavedView, error) {
+	var req SaveViewRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		return nil, err
+	}
+
+	if len(req.Description) > maxViewDescriptionLength {
+		return nil, NewValidationError(fmt.Sprintf("description must not exceed %d characters", maxViewDescriptionLength))
+	}
+	if req.Query != "" {
+		if _, err := filterexpr.Parse(req.Query, repository.GlucoseQueryFields); err != nil {
+			return nil, NewValidationError(fmt.Sprintf("invalid query: %v", err))
+		}
+	}
+	if req.Period != "" {
+		if _, _, err := periodparser.Parse(req.Period); err != nil {
+			return nil, NewValidationError(fmt.Sprintf("invalid period: %v", err))
+		}
+	}
+	if req.Aggregation == "" {
+		req.Aggregation = domain.ViewAggregationList
+	}
+	if !slices.Contains(domain.ViewAggregations, req.Aggregation) {
+		return nil, NewValidationError(fmt.Sprintf("aggregation must be one of %s", strings.Join(domain.ViewAggregations, ", ")))
+	}
+
+	return &domain.SavedView{
+		Name:        name,
+		Description: req.Description,
+		Query:       req.Query,
+		Period:      req.Period,
+		Aggregation: req.Aggregation,
+	}, nil
+}
+
+// NoteRequest is the body of POST /v1/notes and PUT /v1/notes/{id}
+type NoteRequest struct {
+	Timestamp *time.Time `json:"timestamp"` // Time the note refers to (POST default: now)
+	Text      string     `json:"text"`
+	Tags      []string   `json:"tags"` // Subset of domain.NoteTags
+}
+
+// parseNoteRequest decodes and validates a note. A note replaced with PUT
+// must give its timestamp (required); a new one defaults to now.
+func parseNoteRequest(w http.ResponseWriter, r *http.Request, timestampRequired bool) (*domain.Note, error) {
+	var req NoteRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, NewValidationError("text is required")
+	}
+	if utf8.RuneCountInString(text) > domain.MaxNoteTextLength {
+		return nil, NewValidationError(fmt.Sprintf("text must not exceed %d characters", domain.MaxNoteTextLength))
+	}
+	for _, tag := range req.Tags {
+		if !slices.Contains(domain.NoteTags, tag) {
+			return nil, NewValidationError(fmt.Sprintf("invalid tag %q (use %s)", tag, strings.Join(domain.NoteTags, ", ")))
+		}
+	}
+
+	timestamp := time.Now()
+	switch {
+	case req.Timestamp != nil:
+		timestamp = *req.Timestamp
+	case timestampRequired:
+		return nil, NewValidationError("timestamp is required")
+	}
+
+	return &domain.Note{
+		Timestamp: timestamp,
+		Text:      text,
+		Tags:      req.Tags,
+	}, nil
+}
+
+// InsulinRequest is the body of POST /v1/insulin
+type InsulinRequest struct {
+	Timestamp *time.Time `json:"timestamp"` // Time of the injection (default: now)
+	Units     float64    `json:"units"`
+	Type      string     `json:"type"` // One of domain.InsulinTypes (default: rapid)
+}
+
+// parseInsulinRequest decodes and validates an insulin dose.
+func parseInsulinRequest(w http.ResponseWriter, r *http.Request) (*domain.InsulinDose, error) {
+	var req InsulinRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Units <= 0 || req.Units > domain.MaxInsulinUnits {
+		return nil, NewValidationError(fmt.Sprintf("units must be greater than 0 and at most %g", domain.MaxInsulinUnits))
+	}
+	if req.Type == "" {
+		req.Type = domain.InsulinTypeRapid
+	}
+	if !slices.Contains(domain.InsulinTypes, req.Type) {
+		return nil, NewValidationError(fmt.Sprintf("invalid type %q (use %s)", req.Type, strings.Join(domain.InsulinTypes, ", ")))
+	}
+
+	timestamp := time.Now()
+	if req.Timestamp != nil {
+		timestamp = *req.Timestamp
+	}
+
+	return &domain.InsulinDose{
+		Timestamp: timestamp,
+		Units:     req.Units,
+		Type:      req.Type,
+	}, nil
+}
+
+// CarbRequest is the body of POST /v1/carbs
+type CarbRequest struct {
+	Timestamp *time.Time `json:"timestamp"` // Time of the meal or snack (default: now)
+	Grams     float64    `json:"grams"`
+}
+
+// parseCarbRequest decodes and validates a carbohydrate intake.
+func parseCarbRequest(w http.ResponseWriter, r *http.Request) (*domain.CarbIntake, error) {
+	var req CarbRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Grams <= 0 || req.Grams > domain.MaxCarbsGrams {
+		return nil, NewValidationError(fmt.Sprintf("grams must be greater than 0 and at most %g", domain.MaxCarbsGrams))
+	}
+
+	timestamp := time.Now()
+	if req.Timestamp != nil {
+		timestamp = *req.Timestamp
+	}
+
+	return &domain.CarbIntake{
+		Timestamp: timestamp,
+		Grams:     req.Grams,
+	}, nil
+}
+
+// parseIDParam parses a positive numeric ID from a URL path segment
+// AttachmentUpload is a file uploaded to POST /v1/sensor/{serial}/attachments
+// as multipart/form-data, with the file in the "file" field and an optional
+// "caption" field.
+type AttachmentUpload struct {
+	FileName string
+	Caption  string
+	Data     []byte
+}
+
+// parseAttachmentUpload reads an attachment upload.
+// Returns service.ErrAttachmentTooLarge when the file exceeds domain.MaxAttachmentSize.
+func parseAttachmentUpload(w http.ResponseWriter, r *http.Request) (*AttachmentUpload, error) {
+	// Room for the multipart headers and the caption
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxAttachmentSize+maxBodyBytes)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, NewValidationError("body must be multipart/form-data")
+	}
+
+	var upload AttachmentUpload
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, multipartError(err)
+		}
+
+		switch part.FormName() {
+		case "file":
+			upload.FileName = part.FileName()
+			upload.Data, err = io.ReadAll(io.LimitReader(part, domain.MaxAttachmentSize+1))
+			if err != nil {
+				return nil, multipartError(err)
+			}
+			if len(upload.Data) > domain.MaxAttachmentSize {
+				return nil, service.ErrAttachmentTooLarge
+			}
+		case "caption":
+			caption, err := io.ReadAll(io.LimitReader(part, maxBodyBytes))
+			if err != nil {
+				return nil, multipartError(err)
+			}
+			upload.Caption = strings.TrimSpace(string(caption))
+		}
+	}
+
+	if upload.Data == nil {
+		return nil, NewValidationError("file is required")
+	}
+	if utf8.RuneCountInString(upload.Caption) > maxAttachmentCaptionLength {
+		return nil, NewValidationError(fmt.Sprintf("caption must not exceed %d characters", maxAttachmentCaptionLength))
+	}
+	if upload.FileName == "" {
+		upload.FileName = "attachment"
+	}
+	if utf8.RuneCountInString(upload.FileName) > 255 {
+		upload.FileName = string([]rune(upload.FileName)[:255])
+	}
+
+	return &upload, nil
+}
+
+// multipartError converts a multipart read error to the error to report.
+func multipartError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return service.ErrAttachmentTooLarge
+	}
+	return NewValidationError(fmt.Sprintf("invalid multipart body: %v", err))
+}
+
+func parseIDParam(value string) (uint, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, NewValidationError("invalid id")
+	}
+	return uint(id), nil
+}
@@ -2,11 +2,17 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/R4yL-dev/glcmd/internal/daemon"
 	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/jobs"
+	"github.com/R4yL-dev/glcmd/internal/logger"
 	"github.com/R4yL-dev/glcmd/internal/service"
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
 )
 
 // PaginationMetadata contains pagination information
@@ -23,9 +29,48 @@ type GlucoseListResponse struct {
 	Pagination PaginationMetadata           `json:"pagination"`
 }
 
+// GlucoseDeltaListResponse represents a paginated list of glucose measurements
+// in the compact delta encoding (?encoding=delta)
+type GlucoseDeltaListResponse struct {
+	Data       *glclient.DeltaSeries `json:"data"`
+	Pagination PaginationMetadata    `json:"pagination"`
+}
+
 // GlucoseResponse represents a single glucose measurement response
 type GlucoseResponse struct {
-	Data *domain.GlucoseMeasurement `json:"data"`
+	Data    *domain.GlucoseMeasurement `json:"data"`
+	Display *GlucoseDisplay            `json:"display,omitempty"`
+}
+
+// GlucoseDisplay is a glucose measurement formatted with the display
+// preferences, for clients that print it as is (widgets, status bars).
+type GlucoseDisplay struct {
+	Value     string `json:"value"`     // Value in the preferred unit (e.g. "5.4 mmol/L")
+	LocalTime string `json:"localTime"` // Time in the preferred time zone (e.g. "14:05")
+	Timezone  string `json:"timezone"`  // Time zone of localTime
+}
+
+// GlucoseDebug is a glucose measurement with its fetch provenance (?debug=true).
+// FetchCycleID and FetchedAt are absent for rows not stored by a daemon poll
+// (imports, replication, rows older than provenance tracking). FetchSource is
+// only set on current measurements.
+type GlucoseDebug struct {
+	*domain.GlucoseMeasurement
+	FetchCycleID string     `json:"fetchCycleId,omitempty"`
+	FetchedAt    *time.Time `json:"fetchedAt,omitempty"`
+	FetchSource  string     `json:"fetchSource,omitempty"`
+}
+
+// GlucoseDebugListResponse represents a paginated list of glucose measurements
+// with their fetch provenance
+type GlucoseDebugListResponse struct {
+	Data       []*GlucoseDebug    `json:"data"`
+	Pagination PaginationMetadata `json:"pagination"`
+}
+
+// GlucoseDebugResponse represents a single glucose measurement with its fetch provenance
+type GlucoseDebugResponse struct {
+	Data *GlucoseDebug `json:"data"`
 }
 
 // MeasurementListResponse is an alias for GlucoseListResponse (backwards compatibility)
@@ -45,6 +90,42 @@ type StatisticsData struct {
 	Statistics  service.MeasurementStats  `json:"statistics"`
 	TimeInRange *TimeInRangeData          `json:"timeInRange,omitempty"`
 	Distribution DistributionData         `json:"distribution"`
+	DataQuality *service.QualitySummary   `json:"dataQuality,omitempty"` // Only for a bounded period
+	DailyTotals []*service.DailyLogTotals `json:"dailyTotals,omitempty"` // Logged insulin and carbs per day, only for a bounded period
+}
+
+// QualityResponse represents the data quality of a time range
+type QualityResponse struct {
+	Data QualityData `json:"data"`
+}
+
+// QualityData contains the overall and per-day data quality
+type QualityData struct {
+	Summary *service.QualitySummary `json:"summary"`
+	Days    []*service.DayQuality   `json:"days"`
+}
+
+// HistogramResponse represents the distribution of glucose readings
+type HistogramResponse struct {
+	Data HistogramData `json:"data"`
+}
+
+// HistogramData contains a glucose histogram and its period (omitted for all time)
+type HistogramData struct {
+	Period *PeriodInfo `json:"period,omitempty"`
+	*service.Histogram
+}
+
+// PercentilesResponse represents the glucose percentiles by time of day
+type PercentilesResponse struct {
+	Data PercentilesData `json:"data"`
+}
+
+// PercentilesData contains the glucose percentiles and their period
+type PercentilesData struct {
+	Period *PeriodInfo `json:"period"`
+	Days   int         `json:"days"`
+	*service.Percentiles
 }
 
 // PeriodInfo contains the time period for statistics
@@ -95,6 +176,10 @@ type SensorResponse struct {
 	DaysElapsed       float64  `json:"daysElapsed"`
 	ActualDays        *float64 `json:"actualDays,omitempty"`
 	Status            string   `json:"status"`
+	ApplicationSite   string   `json:"applicationSite,omitempty"`
+	Note              string   `json:"note,omitempty"`
+	Rating            *int     `json:"rating,omitempty"`
+	PatientID         string   `json:"patientId,omitempty"`
 }
 
 // SensorListResponse represents a paginated list of sensors
@@ -115,21 +200,27 @@ type SensorStatisticsResponse struct {
 
 // SensorStatisticsData contains sensor lifecycle statistics
 type SensorStatisticsData struct {
-	Period     *PeriodInfo         `json:"period,omitempty"`
-	Statistics service.SensorStats `json:"statistics"`
-	Current    *SensorResponse     `json:"current,omitempty"`
+	Period      *PeriodInfo         `json:"period,omitempty"`      // Activation period
+	EndedPeriod *PeriodInfo         `json:"endedPeriod,omitempty"` // End period
+	Statistics  service.SensorStats `json:"statistics"`
+	Current     *SensorResponse     `json:"current,omitempty"`
 }
 
-// NewSensorResponse creates a SensorResponse from a domain.SensorConfig
-func NewSensorResponse(s *domain.SensorConfig) *SensorResponse {
+// NewSensorResponse creates a SensorResponse from a domain.SensorConfig.
+// gracePeriod distinguishes an expired sensor still reporting ("grace") from an ended one ("stopped").
+func NewSensorResponse(s *domain.SensorConfig, gracePeriod time.Duration) *SensorResponse {
 	resp := &SensorResponse{
-		SerialNumber: s.SerialNumber,
-		Activation:   s.Activation.Format("2006-01-02T15:04:05Z"),
-		ExpiresAt:    s.ExpiresAt.Format("2006-01-02T15:04:05Z"),
-		SensorType:   s.SensorType,
-		DurationDays: s.DurationDays,
-		DaysElapsed:  s.ElapsedDays(),
-		Status:       string(s.Status()),
+		SerialNumber:    s.SerialNumber,
+		Activation:      s.Activation.Format("2006-01-02T15:04:05Z"),
+		ExpiresAt:       s.ExpiresAt.Format("2006-01-02T15:04:05Z"),
+		SensorType:      s.SensorType,
+		DurationDays:    s.DurationDays,
+		DaysElapsed:     s.ElapsedDays(),
+		Status:          string(s.StatusAt(time.Now(), gracePeriod)),
+		ApplicationSite: s.ApplicationSite,
+		Note:            s.Note,
+		Rating:          s.Rating,
+		PatientID:       s.PatientID,
 	}
 
 	if s.EndedAt != nil {
@@ -149,6 +240,250 @@ func NewSensorResponse(s *domain.SensorConfig) *SensorResponse {
 	return resp
 }
 
+// SensorSiteResponse represents the result of recording a sensor application site
+type SensorSiteResponse struct {
+	Data SensorSiteData `json:"data"`
+}
+
+// SensorSiteData contains the updated sensor and the rotation check
+type SensorSiteData struct {
+	Sensor       *SensorResponse `json:"sensor"`
+	PreviousSite string          `json:"previousSite,omitempty"`
+	SiteReused   bool            `json:"siteReused"`
+	Warning      string          `json:"warning,omitempty"`
+}
+
+// SensorSitesResponse represents the application site history
+type SensorSitesResponse struct {
+	Data []*service.SiteUse `json:"data"`
+}
+
+// AttachmentResponse represents a sensor attachment
+type AttachmentResponse struct {
+	ID           uint      `json:"id"`
+	SerialNumber string    `json:"serialNumber"`
+	FileName     string    `json:"fileName"`
+	ContentType  string    `json:"contentType"`
+	Size         int64     `json:"size"`
+	Caption      string    `json:"caption,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	URL          string    `json:"url"` // Download URL, requires a token like the other attachment endpoints
+}
+
+// NewAttachmentResponse creates an AttachmentResponse from a domain.SensorAttachment.
+func NewAttachmentResponse(a *domain.SensorAttachment) *AttachmentResponse {
+	return &AttachmentResponse{
+		ID:           a.ID,
+		SerialNumber: a.SerialNumber,
+		FileName:     a.FileName,
+		ContentType:  a.ContentType,
+		Size:         a.Size,
+		Caption:      a.Caption,
+		CreatedAt:    a.CreatedAt,
+		URL:          fmt.Sprintf("/v1/attachments/%d", a.ID),
+	}
+}
+
+// SensorAttachmentResponse represents a stored sensor attachment
+type SensorAttachmentResponse struct {
+	Data *AttachmentResponse `json:"data"`
+}
+
+// SensorAttachmentsResponse represents the attachments of a sensor, oldest first
+type SensorAttachmentsResponse struct {
+	Data []*AttachmentResponse `json:"data"`
+}
+
+// UpstreamStatusResponse represents the availability of LibreView
+type UpstreamStatusResponse struct {
+	Data *service.UpstreamStatus `json:"data"`
+}
+
+// ConnectionResponse represents the upstream connection details
+type ConnectionResponse struct {
+	Data *domain.ConnectionInfo `json:"data"`
+}
+
+// ModeResponse represents the activity mode response
+type ModeResponse struct {
+	Data *domain.ModeStatus `json:"data"`
+}
+
+// AlertListResponse represents a paginated list of alerts
+type AlertListResponse struct {
+	Data       []*domain.Alert    `json:"data"`
+	Pagination PaginationMetadata `json:"pagination"`
+}
+
+// GlucoseEventListResponse represents a paginated list of glucose events
+type GlucoseEventListResponse struct {
+	Data       []*domain.GlucoseEvent `json:"data"`
+	Pagination PaginationMetadata     `json:"pagination"`
+}
+
+// DailySummaryListResponse represents the summaries of the days within a
+// time range, oldest first
+type DailySummaryListResponse struct {
+	Data []*domain.DailySummary `json:"data"`
+}
+
+// AlertWeeklyResponse represents alert counts per week, oldest first
+type AlertWeeklyResponse struct {
+	Data []*service.AlertWeek `json:"data"`
+}
+
+// TreatmentListResponse represents treatments within a time range, oldest first
+type TreatmentListResponse struct {
+	Data []*domain.TreatmentEntry `json:"data"`
+}
+
+// TreatmentImportResponse represents the outcome of a pump export import
+type TreatmentImportResponse struct {
+	Data TreatmentImportData `json:"data"`
+}
+
+// TreatmentImportData contains the import counts
+type TreatmentImportData struct {
+	Imported   int `json:"imported"`
+	Duplicates int `json:"duplicates"` // Already imported from an earlier export
+	Skipped    int `json:"skipped"`    // Rows that could not be parsed
+}
+
+// TreatmentAnalysisResponse represents the correlation between boluses and glucose
+type TreatmentAnalysisResponse struct {
+	Data *service.TreatmentAnalysis `json:"data"`
+}
+
+// FetchStatsResponse represents the fetch cycle counters of the daemon
+type FetchStatsResponse struct {
+	Data daemon.FetchStats `json:"data"`
+}
+
+// StorageForecastResponse represents the database growth forecast
+type StorageForecastResponse struct {
+	Data *service.StorageForecast `json:"data"`
+}
+
+// SSEClientListResponse represents the connected event stream subscribers
+type SSEClientListResponse struct {
+	Data []events.SubscriberStats `json:"data"`
+}
+
+// LogsResponse represents recent log records, oldest first
+type LogsResponse struct {
+	Data []logger.Entry `json:"data"`
+}
+
+// DashboardConfigResponse represents the dashboard layout response
+type DashboardConfigResponse struct {
+	Data *domain.DashboardConfig `json:"data"`
+}
+
+// DeviceConfigResponse represents the LibreLinkUp app settings response
+type DeviceConfigResponse struct {
+	Data *domain.DeviceInfo `json:"data"`
+}
+
+// NoteResponse represents a single note
+type NoteResponse struct {
+	Data *domain.Note `json:"data"`
+}
+
+// NoteListResponse represents a paginated list of notes
+type NoteListResponse struct {
+	Data       []*domain.Note     `json:"data"`
+	Pagination PaginationMetadata `json:"pagination"`
+}
+
+// InsulinResponse represents a logged insulin dose
+type InsulinResponse struct {
+	Data *domain.InsulinDose `json:"data"`
+}
+
+// InsulinListResponse represents insulin doses within a time range, oldest first
+type InsulinListResponse struct {
+	Data []*domain.InsulinDose `json:"data"`
+}
+
+// CarbResponse represents a logged carbohydrate intake
+type CarbResponse struct {
+	Data *domain.CarbIntake `json:"data"`
+}
+
+// CarbListResponse represents carbohydrate intakes within a time range, oldest first
+type CarbListResponse struct {
+	Data []*domain.CarbIntake `json:"data"`
+}
+
+// PreferencesResponse represents the display preferences response
+type PreferencesResponse struct {
+	Data *domain.DisplayPreferences `json:"data"`
+}
+
+// ViewListResponse represents the list of saved views
+type ViewListResponse struct {
+	Data []*domain.SavedView `json:"data"`
+}
+
+// ViewResponse represents a single saved view
+type ViewResponse struct {
+	Data *domain.SavedView `json:"data"`
+}
+
+// ViewRunResponse represents the result of running a saved view.
+// Pagination is only set for list views.
+type ViewRunResponse struct {
+	Data       ViewRunData         `json:"data"`
+	Pagination *PaginationMetadata `json:"pagination,omitempty"`
+}
+
+// ViewRunData holds a saved view and its result: measurements for list
+// views, statistics for stats views.
+type ViewRunData struct {
+	View         *domain.SavedView            `json:"view"`
+	Measurements []*domain.GlucoseMeasurement `json:"measurements,omitempty"`
+	Statistics   *StatisticsData              `json:"statistics,omitempty"`
+}
+
+// CapabilitiesResponse represents the capabilities response
+type CapabilitiesResponse struct {
+	Data *Capabilities `json:"data"`
+}
+
+// TokenListResponse represents the API token list response
+type TokenListResponse struct {
+	Data []*domain.APIToken `json:"data"`
+}
+
+// CreatedToken is an issued API token including its plaintext value,
+// which is returned only once at creation
+type CreatedToken struct {
+	*domain.APIToken
+	Token string `json:"token"`
+}
+
+// CreatedTokenResponse represents the token creation response
+type CreatedTokenResponse struct {
+	Data *CreatedToken `json:"data"`
+}
+
+// SigningKeyListResponse represents the signing key list response
+type SigningKeyListResponse struct {
+	Data []*domain.SigningKey `json:"data"`
+}
+
+// CreatedSigningKey is a new signing key including its secret,
+// which is returned only once at creation
+type CreatedSigningKey struct {
+	*domain.SigningKey
+	Secret string `json:"secret"`
+}
+
+// CreatedSigningKeyResponse represents the signing key creation response
+type CreatedSigningKeyResponse struct {
+	Data *CreatedSigningKey `json:"data"`
+}
+
 // writeJSONResponse writes a JSON response
 func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) error {
 	w.Header().Set("Content-Type", "application/json")
@@ -156,6 +491,34 @@ func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{})
 	return json.NewEncoder(w).Encode(data)
 }
 
+// BootstrapData contains the startup data of a dashboard or widget
+type BootstrapData struct {
+	Latest *domain.GlucoseMeasurement   `json:"latest"` // null when no measurement is stored
+	Recent []*domain.GlucoseMeasurement `json:"recent"` // Last 3 hours, newest first
+	Today  StatisticsData               `json:"today"`  // Since local midnight
+	Sensor *SensorResponse              `json:"sensor"` // null when no sensor is active
+	Health daemon.HealthStatus          `json:"health"`
+
+	Preferences *domain.DisplayPreferences `json:"preferences"` // Display preferences (defaults if none saved)
+}
+
+// BootstrapResponse represents the dashboard bootstrap response
+type BootstrapResponse struct {
+	Data BootstrapData `json:"data"`
+}
+
+// StatusSummary is the flat response of GET /v1/status, for status bars and
+// shell scripts. It has no data envelope and its fields never change meaning:
+// new fields may be added, existing ones are kept in every schema version.
+type StatusSummary struct {
+	Glucose        *float64 `json:"glucose"`        // Latest value in unit (null without reading)
+	Unit           string   `json:"unit"`           // Preferred unit: "mmol" or "mgdl"
+	Trend          string   `json:"trend"`          // "falling-rapidly", "falling", "stable", "rising", "rising-rapidly" or "" (unknown)
+	AgeSeconds     *int64   `json:"ageSeconds"`     // Age of the latest reading (null without reading)
+	SensorDaysLeft *float64 `json:"sensorDaysLeft"` // Days until the current sensor expires (null without sensor)
+	ServiceState   string   `json:"serviceState"`   // "ok", "degraded" or "down"
+}
+
 // HealthResponse represents health endpoint response
 type HealthResponse struct {
 	Data daemon.HealthStatus `json:"data"`
@@ -175,6 +538,7 @@ type MetricsData struct {
 	Process    ProcessInfo        `json:"process"`
 	SSE        SSEMetrics         `json:"sse"`
 	Database   *DatabasePoolStats `json:"database,omitempty"`
+	Fetch      *daemon.FetchStats `json:"fetch,omitempty"`
 }
 
 // SSEMetrics contains Server-Sent Events metrics
@@ -198,6 +562,7 @@ type MemoryStats struct {
 	TotalAllocMB uint64 `json:"totalAllocMB"`
 	SysMB        uint64 `json:"sysMB"`
 	NumGC        uint32 `json:"numGC"`
+	LimitMB      uint64 `json:"limitMB,omitempty"` // Soft memory limit (GOMEMLIMIT or low-memory mode), omitted when unset
 }
 
 // RuntimeInfo contains Go runtime information
@@ -212,6 +577,31 @@ type ProcessInfo struct {
 	PID int `json:"pid"`
 }
 
+// newDeltaSeries encodes measurements in the compact delta encoding
+func newDeltaSeries(measurements []*domain.GlucoseMeasurement) *glclient.DeltaSeries {
+	points := make([]glclient.Point, len(measurements))
+	for i, m := range measurements {
+		points[i] = glclient.Point{
+			Timestamp:      m.Timestamp,
+			ValueInMgPerDl: m.ValueInMgPerDl,
+		}
+		if m.TrendArrow != nil {
+			points[i].TrendArrow = *m.TrendArrow
+		}
+	}
+	return glclient.EncodeDelta(points)
+}
+
+// newGlucoseDebug adds the fetch provenance to a measurement
+func newGlucoseDebug(m *domain.GlucoseMeasurement) *GlucoseDebug {
+	return &GlucoseDebug{
+		GlucoseMeasurement: m,
+		FetchCycleID:       m.FetchCycleID,
+		FetchedAt:          m.FetchedAt,
+		FetchSource:        m.FetchSource,
+	}
+}
+
 // newPaginationMetadata creates pagination metadata
 func newPaginationMetadata(limit, offset int, total int64) PaginationMetadata {
 	hasMore := int64(offset+limit) < total
@@ -222,3 +612,34 @@ func newPaginationMetadata(limit, offset int, total int64) PaginationMetadata {
 		HasMore: hasMore,
 	}
 }
+
+// PrivacyExportResponse represents the personal data export response
+type PrivacyExportResponse struct {
+	Data *service.PrivacyExport `json:"data"`
+}
+
+// ErasureConfirmationResponse represents the erasure confirmation response
+type ErasureConfirmationResponse struct {
+	Data *service.ErasureConfirmation `json:"data"`
+}
+
+// ErasureResponse represents the erasure result response
+type ErasureResponse struct {
+	Data *service.ErasureResult `json:"data"`
+}
+
+// JobResponse represents a background job
+type JobResponse struct {
+	Data *domain.Job `json:"data"`
+}
+
+// JobListResponse represents a paginated list of background jobs
+type JobListResponse struct {
+	Data       []*domain.Job      `json:"data"`
+	Pagination PaginationMetadata `json:"pagination"`
+}
+
+// JobScheduleListResponse represents the recurring jobs, soonest first
+type JobScheduleListResponse struct {
+	Data []jobs.ScheduleInfo `json:"data"`
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
+)
+
+// Response schema versions negotiated with the X-GLCMD-API-Version header.
+// Requests without the header predate negotiation and get the legacy schema,
+// so upgrading glcore does not break older glcli binaries and widgets.
+const (
+	legacySchemaVersion  = 1
+	currentSchemaVersion = glclient.SchemaVersion
+)
+
+// supportedSchemaVersions lists the schema versions the server can produce.
+var supportedSchemaVersions = []int{legacySchemaVersion, currentSchemaVersion}
+
+// schemaVersionKey is the context key of the negotiated schema version.
+type schemaVersionKey struct{}
+
+// schemaVersionMiddleware negotiates the response schema version.
+// Unsupported versions are rejected with 400 so clients fail loudly instead
+// of misreading responses.
+func (s *Server) schemaVersionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := legacySchemaVersion
+
+		if header := r.Header.Get(glclient.APIVersionHeader); header != "" {
+			v, err := strconv.Atoi(header)
+			if err != nil || !slices.Contains(supportedSchemaVersions, v) {
+				writeJSONError(w, http.StatusBadRequest,
+					fmt.Sprintf("unsupported %s %q (supported: %v)", glclient.APIVersionHeader, header, supportedSchemaVersions))
+				return
+			}
+			version = v
+		}
+
+		w.Header().Set(glclient.APIVersionHeader, strconv.Itoa(version))
+		w.Header().Add("Vary", glclient.APIVersionHeader)
+
+		ctx := context.WithValue(r.Context(), schemaVersionKey{}, version)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// schemaVersion returns the schema version negotiated for the request.
+func schemaVersion(ctx context.Context) int {
+	if v, ok := ctx.Value(schemaVersionKey{}).(int); ok {
+		return v
+	}
+	return currentSchemaVersion
+}
+
+// newSensorResponse builds a SensorResponse in the schema version of the request.
+func (s *Server) newSensorResponse(r *http.Request, sensor *domain.SensorConfig) *SensorResponse {
+	resp := NewSensorResponse(sensor, s.sensorService.GracePeriod())
+
+	// Schema 1 knows no grace status: an expired sensor still reporting is running
+	if schemaVersion(r.Context()) < 2 && resp.Status == string(domain.SensorStatusGrace) {
+		resp.Status = string(domain.SensorStatusRunning)
+	}
+
+	return resp
+}
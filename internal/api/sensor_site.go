@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/go-chi/chi/v5"
+)
+
+// handlePutSensorSite handles PUT /v1/sensor/latest/site
+// Records where the current sensor is applied and warns when the previous
+// sensor used the same site.
+func (s *Server) handlePutSensorSite(w http.ResponseWriter, r *http.Request) {
+	site, err := parseSensorSiteRequest(w, r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	assignment, err := s.sensorService.SetApplicationSite(ctx, site)
+	if err != nil {
+		if errors.Is(err, persistence.ErrNotFound) {
+			writeJSONError(w, http.StatusNotFound, "No active sensor found")
+			return
+		}
+		handleError(w, err, s.logger)
+		return
+	}
+
+	data := SensorSiteData{
+		Sensor:       s.newSensorResponse(r, assignment.Sensor),
+		PreviousSite: assignment.PreviousSite,
+		SiteReused:   assignment.Reused,
+	}
+	if assignment.Reused {
+		data.Warning = fmt.Sprintf("previous sensor was also applied to %s, consider rotating sites", site)
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, SensorSiteResponse{Data: data}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetSensorSites handles GET /v1/sensor/sites
+// Returns the application site of the most recent sensors, newest first.
+func (s *Server) handleGetSensorSites(w http.ResponseWriter, r *http.Request) {
+	limit, _, err := parsePaginationParams(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	history, err := s.sensorService.GetSiteHistory(ctx, limit)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, SensorSitesResponse{Data: history}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handlePatchSensor handles PATCH /v1/sensor/{serial}
+// Records a free-text note and a 1-5 rating on a sensor.
+func (s *Server) handlePatchSensor(w http.ResponseWriter, r *http.Request) {
+	feedback, err := parseSensorFeedbackRequest(w, r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	sensor, err := s.sensorService.UpdateFeedback(ctx, chi.URLParam(r, "serial"), feedback)
+	if err != nil {
+		if errors.Is(err, persistence.ErrNotFound) {
+			writeJSONError(w, http.StatusNotFound, "Sensor not found")
+			return
+		}
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, LatestSensorResponse{Data: s.newSensorResponse(r, sensor)}); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
@@ -9,55 +9,148 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/R4yL-dev/glcmd/internal/daemon"
+	"github.com/R4yL-dev/glcmd/internal/domain"
 	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/jobs"
+	"github.com/R4yL-dev/glcmd/internal/logger"
 	"github.com/R4yL-dev/glcmd/internal/service"
 )
 
 // Server represents the HTTP API server
 type Server struct {
 	httpServer           *http.Server
+	healthServer         *http.Server // Separate listener serving /health only (nil = disabled)
 	port                 int
 	glucoseService       service.GlucoseService
 	sensorService        service.SensorService
 	configService        service.ConfigService
+	syncService          service.SyncService
+	syncToken            string
+	tokenService         service.TokenService
+	adminToken           string
+	apiTokens            map[string]string
+	signingService       service.SigningService
 	eventBroker          *events.Broker
+	modeService          service.ModeService
+	alertService         service.AlertService
+	glucoseEventService  service.GlucoseEventService
+	dailySummaryService  service.DailySummaryService
+	treatmentService     service.TreatmentService
+	privacyService       service.PrivacyService
+	viewService          service.ViewService
+	noteService          service.NoteService
+	logbookService       service.LogbookService
+	upstreamService      service.UpstreamService
+	attachmentService    service.AttachmentService
+	storageService       service.StorageService
+	logRing              *logger.Ring
+	deprecations         []Deprecation
+	deprecationLog       *deprecationLog
 	logger               *slog.Logger
 	getHealthStatus      func() daemon.HealthStatus
+	getConnectionInfo    func() *domain.ConnectionInfo
+	getFetchStats        func() daemon.FetchStats
 	getDatabaseHealth    func() bool
 	getDatabasePoolStats func() *DatabasePoolStats
+	acceptTerms          func() error // Accepts the pending LibreView terms (nil = not available)
+	defaultPatientID     string
+	jobManager           *jobs.Manager
 	startTime            time.Time
 }
 
+// Options holds the settings and dependencies of the API server.
+// GlucoseService, SensorService, ConfigService, GetHealthStatus and
+// GetDatabaseHealth are required; the other services and callbacks can be
+// left nil, which disables their endpoints.
+type Options struct {
+	Port int
+
+	// SyncToken protects the sync export endpoint; empty disables the export
+	// unless TokenService is set.
+	SyncToken string
+	// AdminToken protects the admin endpoints in addition to admin-scoped tokens.
+	AdminToken string
+	// APITokens maps static API tokens to their scope; empty leaves the data
+	// endpoints open.
+	APITokens map[string]string
+	// DefaultPatientID scopes the glucose and sensor data of requests without
+	// a patientId parameter; empty returns the data of every stored patient.
+	DefaultPatientID string
+
+	GlucoseService      service.GlucoseService
+	SensorService       service.SensorService
+	ConfigService       service.ConfigService
+	SyncService         service.SyncService         // Sync endpoints
+	TokenService        service.TokenService        // Issued API tokens
+	SigningService      service.SigningService      // Signed SSE events
+	EventBroker         *events.Broker              // SSE streaming
+	ModeService         service.ModeService         // Exercise mode
+	AlertService        service.AlertService        // Alert history
+	GlucoseEventService service.GlucoseEventService // Glucose events
+	DailySummaryService service.DailySummaryService // Daily summaries
+	TreatmentService    service.TreatmentService    // Treatment import
+	PrivacyService      service.PrivacyService      // Data export and erasure
+	ViewService         service.ViewService         // Saved views
+	NoteService         service.NoteService         // Notes
+	LogbookService      service.LogbookService      // Insulin and carb logging
+	UpstreamService     service.UpstreamService     // Upstream status
+	AttachmentService   service.AttachmentService   // Sensor attachments
+	StorageService      service.StorageService      // Storage forecast
+	JobManager          *jobs.Manager               // Background jobs and async imports
+	LogRing             *logger.Ring                // Log export
+
+	GetHealthStatus      func() daemon.HealthStatus
+	GetConnectionInfo    func() *domain.ConnectionInfo // Connection details
+	GetFetchStats        func() daemon.FetchStats      // Fetch statistics
+	GetDatabaseHealth    func() bool
+	GetDatabasePoolStats func() *DatabasePoolStats // Database pool metrics
+}
+
 // NewServer creates a new API server instance.
-// eventBroker is optional and can be nil (disables SSE streaming).
-func NewServer(
-	port int,
-	glucoseService service.GlucoseService,
-	sensorService service.SensorService,
-	configService service.ConfigService,
-	eventBroker *events.Broker,
-	getHealthStatus func() daemon.HealthStatus,
-	getDatabaseHealth func() bool,
-	getDatabasePoolStats func() *DatabasePoolStats,
-	logger *slog.Logger,
-) *Server {
+func NewServer(opts Options, logger *slog.Logger) *Server {
 	s := &Server{
-		port:                 port,
-		glucoseService:       glucoseService,
-		sensorService:        sensorService,
-		configService:        configService,
-		eventBroker:          eventBroker,
-		getHealthStatus:      getHealthStatus,
-		getDatabaseHealth:    getDatabaseHealth,
-		getDatabasePoolStats: getDatabasePoolStats,
+		port:                 opts.Port,
+		glucoseService:       opts.GlucoseService,
+		sensorService:        opts.SensorService,
+		configService:        opts.ConfigService,
+		syncService:          opts.SyncService,
+		syncToken:            opts.SyncToken,
+		tokenService:         opts.TokenService,
+		adminToken:           opts.AdminToken,
+		apiTokens:            opts.APITokens,
+		signingService:       opts.SigningService,
+		eventBroker:          opts.EventBroker,
+		modeService:          opts.ModeService,
+		alertService:         opts.AlertService,
+		glucoseEventService:  opts.GlucoseEventService,
+		dailySummaryService:  opts.DailySummaryService,
+		treatmentService:     opts.TreatmentService,
+		privacyService:       opts.PrivacyService,
+		viewService:          opts.ViewService,
+		noteService:          opts.NoteService,
+		logbookService:       opts.LogbookService,
+		upstreamService:      opts.UpstreamService,
+		attachmentService:    opts.AttachmentService,
+		storageService:       opts.StorageService,
+		logRing:              opts.LogRing,
+		deprecations:         deprecations,
+		deprecationLog:       newDeprecationLog(),
+		getHealthStatus:      opts.GetHealthStatus,
+		getConnectionInfo:    opts.GetConnectionInfo,
+		getFetchStats:        opts.GetFetchStats,
+		getDatabaseHealth:    opts.GetDatabaseHealth,
+		getDatabasePoolStats: opts.GetDatabasePoolStats,
+		defaultPatientID:     opts.DefaultPatientID,
+		jobManager:           opts.JobManager,
 		startTime:            time.Now(),
 		logger:               logger,
 	}
 
+	s.registerJobs()
 	router := s.setupRouter()
 
 	s.httpServer = &http.Server{
-		Addr:         fmt.Sprintf(":%d", port),
+		Addr:         fmt.Sprintf(":%d", opts.Port),
 		Handler:      router,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
@@ -74,6 +167,8 @@ func (s *Server) setupRouter() *chi.Mux {
 	// Global middleware (applied to all routes)
 	r.Use(s.corsMiddleware) // CORS must be first for preflight requests
 	r.Use(s.recoveryMiddleware)
+	r.Use(s.schemaVersionMiddleware)
+	r.Use(s.deprecationMiddleware)
 
 	// Monitoring endpoints with logging + timeout
 	r.Group(func(r chi.Router) {
@@ -81,6 +176,7 @@ func (s *Server) setupRouter() *chi.Mux {
 		r.Use(s.timeoutMiddleware)
 		r.Get("/health", s.handleHealth)
 		r.Get("/metrics", s.handleMetrics)
+		r.Get("/status", s.handleStatusPage)
 	})
 
 	// API v1 routes
@@ -90,38 +186,220 @@ func (s *Server) setupRouter() *chi.Mux {
 			r.Use(s.loggingMiddleware)
 			r.Use(s.timeoutMiddleware)
 
-			// Glucose routes
-			r.Get("/glucose", s.handleGetGlucose)
-			r.Get("/glucose/latest", s.handleGetLatestGlucose)
-			r.Get("/glucose/stats", s.handleGetGlucoseStatistics)
+			// Discovery
+			r.Get("/capabilities", s.handleGetCapabilities)
+			r.Get("/openapi.json", s.handleGetOpenAPI)
+			r.Get("/docs", s.handleGetAPIDocs)
+
+			// Data routes (static API tokens required once configured)
+			r.Group(func(r chi.Router) {
+				r.Use(s.apiAuthMiddleware)
+				r.Use(s.patientMiddleware)
+
+				// Dashboard startup data
+				r.Get("/bootstrap", s.handleGetBootstrap)
+
+				// Status bar summary
+				r.Get("/status", s.handleGetStatusSummary)
+
+				// Glucose routes
+				r.Get("/glucose", s.handleGetGlucose)
+				r.Get("/glucose/stats", s.handleGetGlucoseStatistics)
+				r.Get("/glucose/quality", s.handleGetGlucoseQuality)
+				r.Get("/glucose/histogram", s.handleGetGlucoseHistogram)
+				r.Get("/glucose/percentiles", s.handleGetGlucosePercentiles)
+				r.Get("/glucose/events", s.handleGetGlucoseEvents)
+				r.Get("/glucose/daily", s.handleGetGlucoseDaily)
+
+				// Sensor routes
+				r.Get("/sensor", s.handleGetSensor)
+				r.Get("/sensor/latest", s.handleGetLatestSensor)
+				r.Get("/sensor/stats", s.handleGetSensorStatistics)
+				r.Get("/sensor/sites", s.handleGetSensorSites)
+				r.Put("/sensor/latest/site", s.handlePutSensorSite)
+				r.Patch("/sensor/{serial}", s.handlePatchSensor)
+
+				// Upstream connection
+				r.Get("/connection", s.handleGetConnection)
+				r.Get("/upstream/status", s.handleGetUpstreamStatus)
+
+				// Mode routes
+				r.Get("/mode", s.handleGetMode)
+				r.Post("/mode/exercise", s.handleStartExercise)
+				r.Delete("/mode/exercise", s.handleEndExercise)
 
-			// Sensor routes
-			r.Get("/sensor", s.handleGetSensor)
-			r.Get("/sensor/latest", s.handleGetLatestSensor)
-			r.Get("/sensor/stats", s.handleGetSensorStatistics)
+				// Alert routes
+				r.Get("/alerts/history", s.handleGetAlertHistory)
+				r.Get("/alerts/weekly", s.handleGetAlertWeekly)
+				r.Post("/alerts/{id}/ack", s.handleAcknowledgeAlert)
+
+				// Treatment routes
+				r.Get("/treatments", s.handleGetTreatments)
+				r.Post("/treatments/import", s.handleImportTreatments)
+				r.Get("/treatments/analysis", s.handleGetTreatmentAnalysis)
+
+				// Background job routes
+				r.Get("/jobs", s.handleGetJobs)
+				r.Get("/jobs/schedules", s.handleGetJobSchedules)
+				r.Get("/jobs/{id}", s.handleGetJob)
+				r.Delete("/jobs/{id}", s.handleCancelJob)
+
+				// Device config routes
+				r.Get("/config/device", s.handleGetDeviceConfig)
+
+				// Dashboard routes
+				r.Get("/dashboard/config", s.handleGetDashboardConfig)
+				r.Put("/dashboard/config", s.handlePutDashboardConfig)
+
+				// Display preference routes
+				r.Get("/preferences", s.handleGetPreferences)
+				r.Put("/preferences", s.handlePutPreferences)
+
+				// Saved view routes
+				r.Get("/views", s.handleGetViews)
+				r.Get("/views/{name}", s.handleGetView)
+				r.Put("/views/{name}", s.handlePutView)
+				r.Delete("/views/{name}", s.handleDeleteView)
+				r.Get("/views/{name}/run", s.handleRunView)
+
+				// Note routes
+				r.Get("/notes", s.handleGetNotes)
+				r.Post("/notes", s.handleCreateNote)
+				r.Get("/notes/{id}", s.handleGetNote)
+				r.Put("/notes/{id}", s.handlePutNote)
+				r.Delete("/notes/{id}", s.handleDeleteNote)
+
+				// Logbook routes
+				r.Get("/insulin", s.handleGetInsulin)
+				r.Post("/insulin", s.handleLogInsulin)
+				r.Get("/carbs", s.handleGetCarbs)
+				r.Post("/carbs", s.handleLogCarbs)
+			})
+
+			// Sensor attachment routes (health photos: token required)
+			r.Group(func(r chi.Router) {
+				r.Use(s.attachmentAuthMiddleware)
+				r.Get("/sensor/{serial}/attachments", s.handleGetSensorAttachments)
+				r.Post("/sensor/{serial}/attachments", s.handleUploadSensorAttachment)
+				r.Get("/attachments/{id}", s.handleGetAttachment)
+				r.Delete("/attachments/{id}", s.handleDeleteAttachment)
+			})
+
+			// Sync routes (GLCMD_SYNC_TOKEN or sync-scoped token)
+			r.Group(func(r chi.Router) {
+				r.Use(s.syncAuthMiddleware)
+				r.Get("/sync/manifest", s.handleGetSyncManifest)
+				r.Get("/sync/export", s.handleGetSyncExport)
+			})
+
+			// Admin routes
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(s.adminAuthMiddleware)
+				r.Get("/tokens", s.handleListTokens)
+				r.Post("/tokens", s.handleCreateToken)
+				r.Delete("/tokens/{id}", s.handleRevokeToken)
+				r.Get("/keys", s.handleListSigningKeys)
+				r.Post("/keys", s.handleCreateSigningKey)
+				r.Delete("/keys/{id}", s.handleRetireSigningKey)
+				r.Get("/logs", s.handleGetLogs)
+				r.Get("/fetch-stats", s.handleGetFetchStats)
+				r.Get("/storage", s.handleGetStorageForecast)
+				r.Get("/sse", s.handleListSSEClients)
+				r.Delete("/sse/{id}", s.handleDisconnectSSEClient)
+				r.Post("/upstream/accept-terms", s.handleAcceptTerms)
+				r.Post("/backfill", s.handleStartBackfill)
+			})
+		})
+
+		// Privacy endpoints with logging, no timeout
+		// (the handlers allow more time to read or delete every table)
+		r.Route("/privacy", func(r chi.Router) {
+			r.Use(s.loggingMiddleware)
+			r.Use(s.adminAuthMiddleware)
+			r.Get("/export", s.handleGetPrivacyExport)
+			r.Post("/erase/confirmation", s.handleRequestErasure)
+			r.Post("/erase", s.handleErase)
+		})
+
+		// Export endpoints with logging, no timeout
+		// (the handler allows more time to stream the whole history)
+		r.Group(func(r chi.Router) {
+			r.Use(s.loggingMiddleware)
+			r.Use(s.apiAuthMiddleware)
+			r.Use(s.patientMiddleware)
+			r.Get("/glucose/export", s.handleExportGlucose)
+		})
+
+		// Long-poll endpoints with logging, no timeout
+		// (the handler bounds database queries and the wait itself)
+		r.Group(func(r chi.Router) {
+			r.Use(s.loggingMiddleware)
+			r.Use(s.apiAuthMiddleware)
+			r.Use(s.patientMiddleware)
+			r.Get("/glucose/latest", s.handleGetLatestGlucose)
 		})
 
 		// SSE endpoint (no logging middleware, no timeout)
 		// Logging is handled directly in the SSE handler
-		r.Get("/stream", s.handleSSEStream)
+		r.With(s.apiAuthMiddleware, s.patientMiddleware).Get("/stream", s.handleSSEStream)
 	})
 
 	return r
 }
 
-// Start starts the HTTP server in a goroutine
+// SetHealthPort also serves /health on a separate port, e.g. for a liveness
+// probe on a port that is not exposed. The response is the one of the API
+// server. Must be called before Start.
+func (s *Server) SetHealthPort(port int) {
+	r := chi.NewRouter()
+	r.Use(s.recoveryMiddleware)
+	r.Use(s.schemaVersionMiddleware)
+	r.Use(s.loggingMiddleware)
+	r.Use(s.timeoutMiddleware)
+	r.Get("/health", s.handleHealth)
+
+	s.healthServer = &http.Server{
+		Addr:         fmt.Sprintf(":%d", port),
+		Handler:      r,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	}
+}
+
+// SetTermsAcceptor enables POST /v1/admin/upstream/accept-terms, which calls
+// accept to accept the terms LibreView requires before a login succeeds.
+// accept returns daemon.ErrNoPendingTerms when none are required. Must be
+// called before Start.
+func (s *Server) SetTermsAcceptor(accept func() error) {
+	s.acceptTerms = accept
+}
+
+// Start starts the HTTP server, and the health listener if any, in goroutines
 func (s *Server) Start() error {
 	go func() {
 		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			s.logger.Error("API server error", "error", err)
 		}
 	}()
+	if s.healthServer != nil {
+		go func() {
+			if err := s.healthServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				s.logger.Error("health server error", "error", err)
+			}
+		}()
+	}
 	return nil
 }
 
-// Stop gracefully stops the HTTP server
+// Stop gracefully stops the HTTP server and the health listener
 func (s *Server) Stop(ctx context.Context) error {
 	s.logger.Info("stopping API server")
+	if s.healthServer != nil {
+		if err := s.healthServer.Shutdown(ctx); err != nil {
+			s.logger.Warn("failed to stop health server", "error", err)
+		}
+	}
 	return s.httpServer.Shutdown(ctx)
 }
 
@@ -129,3 +407,12 @@ func (s *Server) Stop(ctx context.Context) error {
 func (s *Server) HTTPHandler() http.Handler {
 	return s.httpServer.Handler
 }
+
+// HealthHandler returns the handler of the health listener for testing
+// purposes (nil without SetHealthPort)
+func (s *Server) HealthHandler() http.Handler {
+	if s.healthServer == nil {
+		return nil
+	}
+	return s.healthServer.Handler
+}
@@ -9,10 +9,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/repository"
 )
 
 // handleSSEStream handles GET /v1/stream
-// Query params: types=glucose,sensor (optional, default = all)
+// Query params: types=glucose,sensor,summary,config (optional, default = all)
+//
+//	signed=true (optional, adds a "signature:" field to each event)
 func (s *Server) handleSSEStream(w http.ResponseWriter, r *http.Request) {
 	// Check if SSE is enabled (broker is set)
 	if s.eventBroker == nil {
@@ -36,6 +39,22 @@ func (s *Server) handleSSEStream(w http.ResponseWriter, r *http.Request) {
 	// Parse type filter from query params
 	types := parseEventTypes(r.URL.Query().Get("types"))
 
+	// Optional payload signatures (ignored by clients that don't know the field)
+	var sign signFunc
+	if r.URL.Query().Get("signed") == "true" {
+		if s.signingService == nil {
+			writeJSONError(w, http.StatusServiceUnavailable, "Event signing not available")
+			return
+		}
+		sign = func(eventType string, data []byte) string {
+			signature, err := s.signingService.Sign(r.Context(), eventType, data)
+			if err != nil {
+				s.logger.Warn("failed to sign SSE event", "error", err)
+			}
+			return signature
+		}
+	}
+
 	// Generate client ID
 	clientID := uuid.New().String()
 	start := time.Now()
@@ -54,8 +73,8 @@ func (s *Server) handleSSEStream(w http.ResponseWriter, r *http.Request) {
 		"subscribers", s.eventBroker.SubscriberCount()+1,
 	)
 
-	// Subscribe to events
-	eventCh := s.eventBroker.Subscribe(clientID, types)
+	// Subscribe to the events of the request's patient
+	eventCh := s.eventBroker.SubscribePatient(clientID, repository.PatientFromContext(r.Context()), types)
 	defer func() {
 		s.eventBroker.Unsubscribe(clientID)
 		s.logger.Info("SSE client disconnected",
@@ -73,10 +92,11 @@ func (s *Server) handleSSEStream(w http.ResponseWriter, r *http.Request) {
 		select {
 		case event, ok := <-eventCh:
 			if !ok {
-				// Channel closed, broker stopped
+				// Channel closed: broker stopped or client disconnected
+				// through DELETE /v1/admin/sse/{id}
 				return
 			}
-			if err := writeSSEEvent(w, flusher, event); err != nil {
+			if err := writeSSEEvent(w, flusher, event, sign); err != nil {
 				// Client disconnected
 				return
 			}
@@ -103,6 +123,12 @@ func parseEventTypes(typesParam string) []events.EventType {
 			types = append(types, events.EventTypeGlucose)
 		case "sensor":
 			types = append(types, events.EventTypeSensor)
+		case "summary":
+			types = append(types, events.EventTypeSummary)
+		case "config":
+			types = append(types, events.EventTypeConfig)
+		case "alert":
+			types = append(types, events.EventTypeAlert)
 		case "keepalive":
 			types = append(types, events.EventTypeKeepalive)
 		}
@@ -111,8 +137,12 @@ func parseEventTypes(typesParam string) []events.EventType {
 	return types
 }
 
-// writeSSEEvent writes a single SSE event to the response
-func writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, event events.Event) error {
+// signFunc returns the signature of an event payload ("" = unsigned)
+type signFunc func(eventType string, data []byte) string
+
+// writeSSEEvent writes a single SSE event to the response.
+// When sign is set, a "signature:" field is added before the data.
+func writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, event events.Event, sign signFunc) error {
 	var data []byte
 	var err error
 
@@ -127,9 +157,17 @@ func writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, event events.Eve
 
 	// Write event in SSE format:
 	// event: <type>
+	// signature: <signature> (optional)
 	// data: <json>
 	// (blank line)
-	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	signatureField := ""
+	if sign != nil {
+		if signature := sign(string(event.Type), data); signature != "" {
+			signatureField = "signature: " + signature + "\n"
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\n%sdata: %s\n\n", event.Type, signatureField, data)
 	if err != nil {
 		return err
 	}
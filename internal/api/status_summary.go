@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// Service states of the status summary
+const (
+	serviceStateOK       = "ok"
+	serviceStateDegraded = "degraded"
+	serviceStateDown     = "down"
+)
+
+// handleGetStatusSummary handles GET /v1/status
+// Returns a flat summary for status bars and shell scripts. It always answers
+// 200: missing data is null and failures only change serviceState, so the
+// clients never have to handle errors.
+func (s *Server) handleGetStatusSummary(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	health := s.getHealthStatus()
+	summary := StatusSummary{
+		Unit:         domain.DisplayUnitMmol,
+		ServiceState: serviceStateOK,
+	}
+	switch {
+	case !s.getDatabaseHealth() || health.Status == "unhealthy":
+		summary.ServiceState = serviceStateDown
+	case health.Status != "healthy":
+		summary.ServiceState = serviceStateDegraded
+	}
+
+	prefs, err := s.configService.GetDisplayPreferences(ctx)
+	if err != nil {
+		prefs = domain.DefaultDisplayPreferences()
+	}
+	summary.Unit = prefs.Unit
+
+	if m, err := s.glucoseService.GetLatestMeasurement(ctx); err == nil {
+		glucose := math.Round(m.Value*10) / 10
+		if prefs.Unit == domain.DisplayUnitMgDl {
+			glucose = float64(m.ValueInMgPerDl)
+		}
+		age := int64(time.Since(m.Timestamp).Seconds())
+		summary.Glucose = &glucose
+		summary.AgeSeconds = &age
+		if m.TrendArrow != nil {
+			summary.Trend = trendName(*m.TrendArrow)
+		}
+	}
+
+	if sensor, err := s.sensorService.GetCurrentSensor(ctx); err == nil {
+		days := math.Round(sensor.RemainingDays()*10) / 10
+		summary.SensorDaysLeft = &days
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, summary); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// trendName returns the name of a trend arrow in the status summary.
+func trendName(arrow int) string {
+	switch arrow {
+	case domain.TrendArrowFallingRapidly:
+		return "falling-rapidly"
+	case domain.TrendArrowFalling:
+		return "falling"
+	case domain.TrendArrowStable:
+		return "stable"
+	case domain.TrendArrowRising:
+		return "rising"
+	case domain.TrendArrowRisingRapidly:
+		return "rising-rapidly"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+const (
+	// defaultManifestDays is the manifest window when no start is provided
+	defaultManifestDays = 30
+	// maxManifestDays bounds the manifest window to keep hashing cheap
+	maxManifestDays = 366
+)
+
+// SyncManifestResponse represents the sync manifest response
+type SyncManifestResponse struct {
+	Data *service.SyncManifest `json:"data"`
+}
+
+// SyncExportResponse represents the sync export response
+type SyncExportResponse struct {
+	Data *service.SyncExport `json:"data"`
+}
+
+// handleGetSyncManifest handles GET /v1/sync/manifest
+// Query params: start, end (RFC3339, optional). Defaults to the last 30 days.
+func (s *Server) handleGetSyncManifest(w http.ResponseWriter, r *http.Request) {
+	if s.syncService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Sync not available")
+		return
+	}
+
+	start, end, err := parseManifestRange(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	// Use longer timeout: hashing reads every measurement in the range
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	manifest, err := s.syncService.GetManifest(ctx, start, end)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := SyncManifestResponse{
+		Data: manifest,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// parseManifestRange parses the manifest time range, applying defaults
+// (end = now, start = end - 30 days) and enforcing the maximum window.
+func parseManifestRange(r *http.Request) (start, end time.Time, err error) {
+	startPtr, endPtr, err := parseTimeRange(r)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	end = time.Now().UTC()
+	if endPtr != nil {
+		end = *endPtr
+	}
+
+	start = end.AddDate(0, 0, -defaultManifestDays)
+	if startPtr != nil {
+		start = *startPtr
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, NewValidationError("end time must be after start time")
+	}
+
+	if end.Sub(start) > maxManifestDays*24*time.Hour {
+		return time.Time{}, time.Time{}, NewValidationError(fmt.Sprintf("range must not exceed %d days", maxManifestDays))
+	}
+
+	return start, end, nil
+}
+
+// handleGetSyncExport handles GET /v1/sync/export
+// Query params: date (YYYY-MM-DD, required). Requires the sync token.
+func (s *Server) handleGetSyncExport(w http.ResponseWriter, r *http.Request) {
+	if s.syncService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Sync not available")
+		return
+	}
+
+	dateStr := r.URL.Query().Get("date")
+	if dateStr == "" {
+		handleError(w, NewValidationError("date parameter is required"), s.logger)
+		return
+	}
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		handleError(w, NewValidationError("invalid date format (use YYYY-MM-DD)"), s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	export, err := s.syncService.ExportDay(ctx, date)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := SyncExportResponse{
+		Data: export,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+// handleGetTreatments handles GET /v1/treatments
+// Returns imported insulin treatments within a time range (default: last 24 hours).
+func (s *Server) handleGetTreatments(w http.ResponseWriter, r *http.Request) {
+	if s.treatmentService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Treatments not available")
+		return
+	}
+
+	start, end, err := parseTreatmentRange(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	treatments, err := s.treatmentService.GetTreatments(ctx, start, end)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := TreatmentListResponse{
+		Data: treatments,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetTreatmentAnalysis handles GET /v1/treatments/analysis
+// Returns how glucose responded to boluses within a time range (default: last 14 days),
+// grouped by time of day, with short insights.
+func (s *Server) handleGetTreatmentAnalysis(w http.ResponseWriter, r *http.Request) {
+	if s.treatmentService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Treatments not available")
+		return
+	}
+
+	start, end, err := parseAnalysisRange(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	analysis, err := s.treatmentService.AnalyzeTreatments(ctx, start, end)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := TreatmentAnalysisResponse{
+		Data: analysis,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleImportTreatments handles POST /v1/treatments/import?source=tandem
+// Imports boluses and basal segments from a pump CSV export sent as the body.
+// Importing the same export again only reports duplicates.
+// With async=true the import runs as a background job (202 Accepted) whose
+// progress is polled on /v1/jobs/{id}.
+func (s *Server) handleImportTreatments(w http.ResponseWriter, r *http.Request) {
+	if s.treatmentService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Treatments not available")
+		return
+	}
+
+	async, err := parseAsync(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	parsed, err := parseTreatmentImport(w, r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	if async {
+		s.startJob(w, r, jobTypeTreatmentImport, treatmentImportPayload{
+			Treatments: parsed.Treatments,
+			Skipped:    parsed.Skipped,
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	result, err := s.treatmentService.ImportTreatments(ctx, parsed.Treatments, nil)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := TreatmentImportResponse{
+		Data: newTreatmentImportData(result, parsed.Skipped),
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// newTreatmentImportData builds the import counts from the service result
+// and the rows skipped while parsing.
+func newTreatmentImportData(result *service.TreatmentImport, skipped int) TreatmentImportData {
+	return TreatmentImportData{
+		Imported:   result.Imported,
+		Duplicates: result.Duplicates,
+		Skipped:    skipped,
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"time"
+)
+
+// statusPage is the self-contained HTML status page served at /status.
+//
+//go:embed status.html
+var statusPage []byte
+
+// handleGetUpstreamStatus handles GET /v1/upstream/status
+// Returns the availability of LibreView: current state, uptime over the last
+// 24h, 7d and 30d, and the outages of the last days days (default 30).
+func (s *Server) handleGetUpstreamStatus(w http.ResponseWriter, r *http.Request) {
+	if s.upstreamService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Upstream status not available")
+		return
+	}
+
+	days, err := parseOutageDays(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	status, err := s.upstreamService.GetStatus(ctx, days)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := UpstreamStatusResponse{
+		Data: status,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleStatusPage handles GET /status
+// Serves a status page showing at a glance whether LibreView or the local
+// setup is at fault. The page reads /health and /v1/upstream/status.
+func (s *Server) handleStatusPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(statusPage); err != nil {
+		s.logger.Error("failed to write status page", "error", err)
+	}
+}
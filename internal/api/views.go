@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/internal/utils/filterexpr"
+	"github.com/R4yL-dev/glcmd/internal/utils/periodparser"
+)
+
+// handleGetViews handles GET /v1/views
+// Returns all saved views, sorted by name.
+func (s *Server) handleGetViews(w http.ResponseWriter, r *http.Request) {
+	if s.viewService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Saved views not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	views, err := s.viewService.GetViews(ctx)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := ViewListResponse{
+		Data: views,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleGetView handles GET /v1/views/{name}
+// Returns the definition of a saved view.
+func (s *Server) handleGetView(w http.ResponseWriter, r *http.Request) {
+	if s.viewService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Saved views not available")
+		return
+	}
+
+	name, err := parseViewName(chi.URLParam(r, "name"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	view, err := s.viewService.GetView(ctx, name)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := ViewResponse{
+		Data: view,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handlePutView handles PUT /v1/views/{name}
+// Creates the view or replaces its definition.
+func (s *Server) handlePutView(w http.ResponseWriter, r *http.Request) {
+	if s.viewService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Saved views not available")
+		return
+	}
+
+	name, err := parseViewName(chi.URLParam(r, "name"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	view, err := parseSaveViewRequest(w, r, name)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	saved, err := s.viewService.SaveView(ctx, view)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	response := ViewResponse{
+		Data: saved,
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// handleDeleteView handles DELETE /v1/views/{name}
+func (s *Server) handleDeleteView(w http.ResponseWriter, r *http.Request) {
+	if s.viewService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Saved views not available")
+		return
+	}
+
+	name, err := parseViewName(chi.URLParam(r, "name"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := s.viewService.DeleteView(ctx, name); err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRunView handles GET /v1/views/{name}/run
+// Runs a saved view: its period is resolved relative to now, then the
+// matching measurements (list views, paginated) or their statistics
+// (stats views) are returned.
+func (s *Server) handleRunView(w http.ResponseWriter, r *http.Request) {
+	if s.viewService == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "Saved views not available")
+		return
+	}
+
+	name, err := parseViewName(chi.URLParam(r, "name"))
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	limit, offset, err := parsePaginationParams(r)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	// Use longer timeout for potentially large queries
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	view, err := s.viewService.GetView(ctx, name)
+	if err != nil {
+		handleError(w, err, s.logger)
+		return
+	}
+
+	// Definitions are validated when saved; a failure here means the
+	// accepted syntax changed since
+	var query *filterexpr.Expr
+	if view.Query != "" {
+		if query, err = filterexpr.Parse(view.Query, repository.GlucoseQueryFields); err != nil {
+			handleError(w, NewValidationError("saved view has an invalid query: "+err.Error()), s.logger)
+			return
+		}
+	}
+	var start, end *time.Time
+	if view.Period != "" {
+		if start, end, err = periodparser.Parse(view.Period); err != nil {
+			handleError(w, NewValidationError("saved view has an invalid period: "+err.Error()), s.logger)
+			return
+		}
+	}
+
+	response := ViewRunResponse{
+		Data: ViewRunData{View: view},
+	}
+
+	switch view.Aggregation {
+	case domain.ViewAggregationStats:
+		// Get glucose targets for Time in Range calculation
+		targets, err := s.configService.GetGlucoseTargets(ctx)
+		if err != nil && !errors.Is(err, persistence.ErrNotFound) {
+			handleError(w, err, s.logger)
+			return
+		}
+
+		filters := repository.GlucoseStatisticsFilters{StartTime: start, EndTime: end, Query: query}
+		if targets != nil {
+			filters.TargetLowMgDl = &targets.TargetLow
+			filters.TargetHighMgDl = &targets.TargetHigh
+		}
+
+		stats, err := s.glucoseService.GetStatisticsWithFilters(ctx, filters)
+		if err != nil {
+			handleError(w, err, s.logger)
+			return
+		}
+
+		data := newStatisticsData(stats, start, end, targets)
+		response.Data.Statistics = &data
+
+	default:
+		filters := repository.GlucoseFilters{StartTime: start, EndTime: end, Query: query}
+		measurements, total, err := s.glucoseService.GetMeasurementsWithFilters(ctx, filters, limit, offset)
+		if err != nil {
+			handleError(w, err, s.logger)
+			return
+		}
+
+		pagination := newPaginationMetadata(limit, offset, total)
+		response.Data.Measurements = measurements
+		response.Pagination = &pagination
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, response); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,337 @@
+// Package backfill imports the LibreView history older than the last 12
+// hours, which is all /graph returns.
+//
+// A backfill logs in with its own session and pages through the logbook of
+// each patient one day at a time, from today backwards. Saving is idempotent
+// (measurements are deduplicated by factory timestamp), so overlapping days
+// and repeated backfills only insert the readings that are missing. LibreView
+// keeps a limited history: the backfill stops for a patient after a few
+// consecutive empty days. With the retention policy enabled, the imported
+// history is rolled up once the backfill completes.
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/libreclient"
+	"github.com/R4yL-dev/glcmd/internal/logger"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/internal/service"
+	"github.com/R4yL-dev/glcmd/internal/utils/timeparser"
+)
+
+const (
+	// JobType is the background job running a backfill (see Payload).
+	JobType = "backfill"
+
+	// MaxDays is the longest history a backfill may request.
+	MaxDays = 365
+
+	// maxEmptyPeriods is how many consecutive empty days end the backfill of
+	// a patient: LibreView has no older history.
+	maxEmptyPeriods = 3
+	// defaultInterval spaces the logbook requests, as LibreView rate-limits
+	// the account.
+	defaultInterval = 2 * time.Second
+	// requestTimeout bounds each LibreView request.
+	requestTimeout = 30 * time.Second
+	// storeTimeout bounds the storage of each reading.
+	storeTimeout = 5 * time.Second
+)
+
+// Payload is the input of a backfill job.
+type Payload struct {
+	Days int `json:"days"`
+}
+
+// Progress is notified of the days backfilled. *jobs.Progress implements it.
+type Progress interface {
+	SetTotal(total int)
+	SetDone(done int)
+}
+
+// Result summarizes a backfill.
+type Result struct {
+	Days     int        `json:"days"`             // Days requested per patient
+	Patients int        `json:"patients"`         // Patients backfilled
+	Periods  int        `json:"periods"`          // Days fetched from LibreView, all patients included
+	Inserted int        `json:"inserted"`         // New measurements
+	Skipped  int        `json:"skipped"`          // Measurements already stored
+	Oldest   *time.Time `json:"oldest,omitempty"` // Oldest reading returned by LibreView
+	Rollups  int        `json:"rollups"`          // Rollups computed again from the imported history
+}
+
+// Backfiller imports the logbook history of the LibreView account.
+type Backfiller struct {
+	client         *libreclient.Client
+	email          string
+	password       string
+	patientID      string // Patient to backfill (empty = the first connection)
+	allPatients    bool   // Backfill every connection (multi-patient mode)
+	glucoseService service.GlucoseService
+	retention      service.RetentionService // Rolls up the imported history, nil without retention policy
+	logger         *slog.Logger
+	interval       time.Duration
+}
+
+// New creates a Backfiller logging in with email and password. patientID and
+// allPatients select the patients as GLCMD_PATIENT_ID and GLCMD_MULTI_PATIENT
+// do for the daemon. client may be nil to use the default LibreView client,
+// retention nil when no retention policy is configured.
+func New(
+	client *libreclient.Client,
+	email string,
+	password string,
+	patientID string,
+	allPatients bool,
+	glucoseService service.GlucoseService,
+	retention service.RetentionService,
+	logger *slog.Logger,
+) *Backfiller {
+	if client == nil {
+		client = libreclient.NewClient(nil)
+	}
+	return &Backfiller{
+		client:         client,
+		email:          email,
+		password:       password,
+		patientID:      patientID,
+		allPatients:    allPatients,
+		glucoseService: glucoseService,
+		retention:      retention,
+		logger:         logger,
+		interval:       defaultInterval,
+	}
+}
+
+// Run backfills the last days of history. progress may be nil.
+func (b *Backfiller) Run(ctx context.Context, days int, progress Progress) (*Result, error) {
+	if days < 1 || days > MaxDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", MaxDays)
+	}
+
+	loginCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	session, err := b.client.Login(loginCtx, b.email, b.password)
+	cancel()
+	var terms *libreclient.TermsError
+	if errors.As(err, &terms) {
+		return nil, fmt.Errorf("%w: accept them before backfilling", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+
+	patients, err := b.patients(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Days: days, Patients: len(patients)}
+	total := days * len(patients)
+	if progress != nil {
+		progress.SetTotal(total)
+	}
+
+	end := time.Now().UTC()
+	for i, patientID := range patients {
+		if err := b.backfillPatient(ctx, session, patientID, end, days, result, func(day int) {
+			if progress != nil {
+				progress.SetDone(i*days + day)
+			}
+		}); err != nil {
+			return result, err
+		}
+	}
+
+	// The imported readings are older than the latest rollup: the retention
+	// run rolls them up from the earliest one, before pruning them
+	if b.retention != nil && result.Inserted > 0 {
+		run, err := b.retention.Apply(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to roll up the imported history: %w", err)
+		}
+		result.Rollups = run.Rollups
+	}
+
+	b.logger.Info("backfill completed",
+		"days", days,
+		"patients", result.Patients,
+		"inserted", result.Inserted,
+		"skipped", result.Skipped,
+		"rollups", result.Rollups,
+	)
+	return result, nil
+}
+
+// patients returns the patients to backfill.
+func (b *Backfiller) patients(ctx context.Context, session *libreclient.Session) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	resp, err := b.client.GetConnections(ctx, session.Token, session.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections: %w", err)
+	}
+	if len(resp.Data) == 0 {
+		return nil, errors.New("no patient data in connections response")
+	}
+
+	var patients []string
+	for _, conn := range resp.Data {
+		switch {
+		case b.allPatients:
+			patients = append(patients, conn.PatientID)
+		case b.patientID == "" || strings.EqualFold(conn.PatientID, b.patientID):
+			return []string{conn.PatientID}, nil
+		}
+	}
+	if len(patients) == 0 {
+		return nil, fmt.Errorf("patient %s is not followed by the account (%d connections)",
+			logger.RedactSensitive(b.patientID), len(resp.Data))
+	}
+	return patients, nil
+}
+
+// backfillPatient fetches the logbook of a patient one day at a time, from
+// end backwards, and stores its readings. done is called with the number of
+// days processed.
+func (b *Backfiller) backfillPatient(ctx context.Context, session *libreclient.Session, patientID string, end time.Time, days int, result *Result, done func(day int)) error {
+	empty := 0
+	for day := 0; day < days; day++ {
+		if day > 0 {
+			if err := wait(ctx, b.interval); err != nil {
+				return err
+			}
+		}
+
+		to := end.AddDate(0, 0, -day)
+		from := to.AddDate(0, 0, -1)
+		entries, err := b.fetchPeriod(ctx, session, patientID, from, to)
+		if err != nil {
+			return fmt.Errorf("failed to get the logbook of %s: %w", from.Format(time.DateOnly), err)
+		}
+		result.Periods++
+
+		if err := b.store(ctx, patientID, entries, result); err != nil {
+			return err
+		}
+
+		if len(entries) == 0 {
+			empty++
+		} else {
+			empty = 0
+		}
+		if empty >= maxEmptyPeriods {
+			b.logger.Info("no older LibreView history",
+				"patientID", logger.RedactSensitive(patientID),
+				"before", from,
+			)
+			done(days)
+			return nil
+		}
+		done(day + 1)
+	}
+	return nil
+}
+
+// fetchPeriod returns the logbook entries of a patient between from and to.
+func (b *Backfiller) fetchPeriod(ctx context.Context, session *libreclient.Session, patientID string, from, to time.Time) ([]libreclient.LogbookEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	resp, err := b.client.GetLogbook(ctx, session.Token, session.AccountID, patientID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// store saves the readings of a logbook period, as one fetch cycle.
+func (b *Backfiller) store(ctx context.Context, patientID string, entries []libreclient.LogbookEntry, result *Result) error {
+	cycleID := uuid.New().String()
+	fetchedAt := time.Now().UTC()
+
+	for _, entry := range entries {
+		measurement, err := newMeasurement(patientID, &entry)
+		if err != nil {
+			return err
+		}
+		measurement.FetchCycleID = cycleID
+		measurement.FetchedAt = &fetchedAt
+
+		storeCtx, cancel := context.WithTimeout(repository.WithPatient(ctx, patientID), storeTimeout)
+		inserted, err := b.glucoseService.SaveMeasurement(storeCtx, measurement)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to store measurement: %w", err)
+		}
+
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Skipped++
+		}
+		if result.Oldest == nil || measurement.Timestamp.Before(*result.Oldest) {
+			oldest := measurement.Timestamp
+			result.Oldest = &oldest
+		}
+	}
+	return nil
+}
+
+// newMeasurement converts a logbook entry of a patient to a historical measurement.
+func newMeasurement(patientID string, entry *libreclient.LogbookEntry) (*domain.GlucoseMeasurement, error) {
+	factoryTimestamp, err := timeparser.ParseLibreViewTimestamp(entry.FactoryTimestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse factory timestamp: %w", err)
+	}
+
+	timestamp, err := timeparser.ParseLibreViewTimestamp(entry.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+
+	return &domain.GlucoseMeasurement{
+		PatientID:        patientID,
+		FactoryTimestamp: factoryTimestamp,
+		Timestamp:        timestamp,
+		Value:            entry.Value,
+		ValueInMgPerDl:   entry.ValueInMgPerDl,
+		TrendArrow:       entry.TrendArrow,
+		TrendMessage:     trendMessage(entry.TrendMessage),
+		GlucoseColor:     entry.MeasurementColor,
+		GlucoseUnits:     entry.GlucoseUnits,
+		IsHigh:           entry.IsHigh,
+		IsLow:            entry.IsLow,
+		Type:             domain.GlucoseTypeHistorical,
+	}, nil
+}
+
+// trendMessage returns the trend message of an entry, nil when empty.
+func trendMessage(message *string) *string {
+	if message == nil || *message == "" {
+		return nil
+	}
+	return message
+}
+
+// wait pauses for d, or until ctx is done.
+func wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,251 @@
+package backfill
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/libreclient"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+// redirectTransport sends all requests to a test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// progress records the reported progress.
+type progress struct {
+	total, done int
+}
+
+func (p *progress) SetTotal(total int) { p.total = total }
+func (p *progress) SetDone(done int)   { p.done = done }
+
+// historyDays is how many days of logbook the fake LibreView server keeps.
+const historyDays = 2
+
+// newLibreView returns a fake LibreView server following patient-a and
+// patient-b. The logbook of each period holds two readings, during the last
+// historyDays only, and the requested periods are recorded.
+func newLibreView(t *testing.T) (*libreclient.Client, *[]string) {
+	var periods []string
+	oldest := time.Now().UTC().AddDate(0, 0, -historyDays)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/llu/auth/login":
+			w.Write([]byte(`{"status":0,"data":{"user":{"id":"user"},"authTicket":{"token":"token"}}}`))
+		case r.URL.Path == "/llu/connections":
+			w.Write([]byte(`{"data":[{"patientId":"patient-a"},{"patientId":"patient-b"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/logbook"):
+			patientID := strings.Split(r.URL.Path, "/")[3]
+			from, _ := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+			start := time.Unix(from, 0).UTC()
+			periods = append(periods, patientID+" "+start.Format(time.DateOnly))
+
+			entries := []map[string]any{}
+			if !start.Before(oldest.Add(-time.Minute)) {
+				for _, offset := range []time.Duration{time.Hour, 2 * time.Hour} {
+					ts := start.Add(offset).Format("1/2/2006 3:04:05 PM")
+					entries = append(entries, map[string]any{
+						"FactoryTimestamp": ts,
+						"Timestamp":        ts,
+						"ValueInMgPerDl":   120,
+						"Value":            6.7,
+						"TrendArrow":       3,
+						"MeasurementColor": 1,
+						"GlucoseUnits":     0,
+					})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": entries})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	target, _ := url.Parse(server.URL)
+	return libreclient.NewClient(&http.Client{Transport: redirectTransport{target: target}}), &periods
+}
+
+// newGlucoseService returns a glucose service on an in-memory database.
+func newGlucoseService(t *testing.T) (*service.GlucoseServiceImpl, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.GlucoseMeasurement{}); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	return service.NewGlucoseService(repository.NewGlucoseRepository(db), nil, slog.Default(), nil), db
+}
+
+func newBackfiller(client *libreclient.Client, glucoseService service.GlucoseService, patientID string, allPatients bool) *Backfiller {
+	b := New(client, "user@example.com", "secret", patientID, allPatients, glucoseService, nil, slog.Default())
+	b.interval = 0
+	return b
+}
+
+func TestRun_StopsAtOldestHistory(t *testing.T) {
+	client, periods := newLibreView(t)
+	glucoseService, db := newGlucoseService(t)
+	p := &progress{}
+
+	result, err := newBackfiller(client, glucoseService, "", false).Run(context.Background(), 30, p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 2 days with readings, then 3 empty days
+	if result.Patients != 1 || result.Periods != historyDays+maxEmptyPeriods {
+		t.Errorf("expected 1 patient and %d periods, got %d and %d (%v)", historyDays+maxEmptyPeriods, result.Patients, result.Periods, *periods)
+	}
+	if result.Inserted != 2*historyDays || result.Skipped != 0 {
+		t.Errorf("expected %d inserted readings, got %d inserted and %d skipped", 2*historyDays, result.Inserted, result.Skipped)
+	}
+	if result.Oldest == nil {
+		t.Error("expected the oldest reading")
+	}
+	if p.total != 30 || p.done != 30 {
+		t.Errorf("expected the progress to reach 30/30, got %d/%d", p.done, p.total)
+	}
+
+	var stored []domain.GlucoseMeasurement
+	if err := db.Find(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range stored {
+		if m.PatientID != "patient-a" || m.Type != domain.GlucoseTypeHistorical || m.TrendArrow == nil || m.FetchCycleID == "" {
+			t.Errorf("unexpected measurement %+v", m)
+		}
+	}
+	for _, period := range *periods {
+		if !strings.HasPrefix(period, "patient-a ") {
+			t.Errorf("expected only the first patient to be backfilled, got %s", period)
+		}
+	}
+}
+
+func TestRun_Idempotent(t *testing.T) {
+	client, _ := newLibreView(t)
+	glucoseService, _ := newGlucoseService(t)
+	b := newBackfiller(client, glucoseService, "", false)
+
+	if _, err := b.Run(context.Background(), 2, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := b.Run(context.Background(), 2, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Inserted != 0 || result.Skipped != 4 {
+		t.Errorf("expected the readings to be skipped, got %d inserted and %d skipped", result.Inserted, result.Skipped)
+	}
+}
+
+// retention counts the retention runs.
+type retention struct {
+	runs int
+}
+
+func (r *retention) Apply(ctx context.Context) (*service.RetentionRun, error) {
+	r.runs++
+	return &service.RetentionRun{Rollups: 4}, nil
+}
+
+func TestRun_RollsUpHistory(t *testing.T) {
+	client, _ := newLibreView(t)
+	glucoseService, _ := newGlucoseService(t)
+	r := &retention{}
+	b := newBackfiller(client, glucoseService, "", false)
+	b.retention = r
+
+	result, err := b.Run(context.Background(), 2, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.runs != 1 || result.Rollups != 4 {
+		t.Errorf("expected the imported history to be rolled up, got %d runs and %d rollups", r.runs, result.Rollups)
+	}
+
+	// Nothing new to roll up
+	if _, err := b.Run(context.Background(), 2, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.runs != 1 {
+		t.Errorf("expected no retention run without new readings, got %d", r.runs)
+	}
+}
+
+func TestRun_Patients(t *testing.T) {
+	tests := []struct {
+		name        string
+		patientID   string
+		allPatients bool
+		want        []string
+		wantErr     bool
+	}{
+		{name: "selected patient", patientID: "PATIENT-B", want: []string{"patient-b"}},
+		{name: "all patients", allPatients: true, want: []string{"patient-a", "patient-b"}},
+		{name: "unknown patient", patientID: "patient-c", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, periods := newLibreView(t)
+			glucoseService, _ := newGlucoseService(t)
+
+			result, err := newBackfiller(client, glucoseService, tt.patientID, tt.allPatients).Run(context.Background(), 1, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, period := range *periods {
+				got = append(got, strings.Fields(period)[0])
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) || result.Patients != len(tt.want) {
+				t.Errorf("expected %v to be backfilled, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRun_InvalidDays(t *testing.T) {
+	for _, days := range []int{0, MaxDays + 1} {
+		if _, err := New(nil, "user@example.com", "secret", "", false, nil, nil, slog.Default()).Run(context.Background(), days, nil); err == nil {
+			t.Errorf("expected an error for %d days", days)
+		}
+	}
+}
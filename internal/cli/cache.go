@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache stores the last successful API responses on disk so that glcli can
+// still display something when the server is unreachable.
+// Entries are namespaced by server URL.
+type Cache struct {
+	dir string
+}
+
+// cacheEntry is the on-disk format of a cached response.
+type cacheEntry struct {
+	SavedAt time.Time       `json:"savedAt"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// NewCache creates a cache storing entries for baseURL under dir.
+func NewCache(dir, baseURL string) *Cache {
+	sum := sha256.Sum256([]byte(baseURL))
+	return &Cache{dir: filepath.Join(dir, hex.EncodeToString(sum[:8]))}
+}
+
+// DefaultCacheDir returns the glcli cache directory:
+// $XDG_CACHE_HOME/glcli on Linux (~/.cache/glcli by default).
+func DefaultCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "glcli"), nil
+}
+
+// Save stores v under key.
+func (c *Cache) Save(key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	entry, err := json.Marshal(cacheEntry{SavedAt: time.Now(), Data: data})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+
+	// Write to a temp file then rename, so a reader never sees a partial entry
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(entry); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// Load decodes the entry stored under key into v and returns when it was saved.
+func (c *Cache) Load(key string, v interface{}) (time.Time, error) {
+	raw, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return time.Time{}, fmt.Errorf("corrupted cache entry %s: %w", key, err)
+	}
+	if err := json.Unmarshal(entry.Data, v); err != nil {
+		return time.Time{}, fmt.Errorf("corrupted cache entry %s: %w", key, err)
+	}
+
+	return entry.SavedAt, nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// FetchCached calls fetch and caches its result under key.
+// When the server is unreachable and allowStale is true, the cached value is
+// returned instead, along with its age. A zero age means the result is fresh.
+// cache may be nil (caching disabled).
+func FetchCached[T any](cache *Cache, key string, allowStale bool, fetch func() (*T, error)) (*T, time.Duration, error) {
+	result, err := fetch()
+	if err == nil {
+		if cache != nil {
+			// Best effort: a read-only cache dir must not break the command
+			_ = cache.Save(key, result)
+		}
+		return result, 0, nil
+	}
+
+	if cache == nil || !allowStale || !errors.Is(err, ErrUnreachable) {
+		return nil, 0, err
+	}
+
+	var cached T
+	savedAt, loadErr := cache.Load(key, &cached)
+	if loadErr != nil {
+		// Nothing usable in cache: report the original connection error
+		return nil, 0, err
+	}
+
+	return &cached, time.Since(savedAt), nil
+}
+
+// FormatStaleMarker returns the marker shown next to cached values, e.g. "(stale, 12m old)".
+func FormatStaleMarker(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "(stale, <1m old)"
+	case age < time.Hour:
+		return fmt.Sprintf("(stale, %dm old)", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("(stale, %dh%02dm old)", int(age.Hours()), int(age.Minutes())%60)
+	default:
+		return fmt.Sprintf("(stale, %dd old)", int(age.Hours()/24))
+	}
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCache_SaveAndLoad(t *testing.T) {
+	cache := NewCache(t.TempDir(), "http://localhost:8080")
+
+	if err := cache.Save("glucose-latest", &GlucoseReading{ValueInMgPerDl: 110}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	var reading GlucoseReading
+	savedAt, err := cache.Load("glucose-latest", &reading)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if reading.ValueInMgPerDl != 110 {
+		t.Errorf("expected 110 mg/dL, got %d", reading.ValueInMgPerDl)
+	}
+	if time.Since(savedAt) > time.Minute {
+		t.Errorf("unexpected savedAt %v", savedAt)
+	}
+}
+
+func TestCache_NamespacedByURL(t *testing.T) {
+	dir := t.TempDir()
+	NewCache(dir, "http://a:8080").Save("glucose-latest", &GlucoseReading{ValueInMgPerDl: 110})
+
+	var reading GlucoseReading
+	if _, err := NewCache(dir, "http://b:8080").Load("glucose-latest", &reading); err == nil {
+		t.Error("expected cache miss for another server URL")
+	}
+}
+
+func TestFetchCached_StaleOnUnreachable(t *testing.T) {
+	cache := NewCache(t.TempDir(), "http://localhost:8080")
+
+	fresh, age, err := FetchCached(cache, "k", true, func() (*GlucoseReading, error) {
+		return &GlucoseReading{ValueInMgPerDl: 120}, nil
+	})
+	if err != nil || age != 0 || fresh.ValueInMgPerDl != 120 {
+		t.Fatalf("unexpected fresh result: %v, %v, %v", fresh, age, err)
+	}
+
+	unreachable := func() (*GlucoseReading, error) {
+		return nil, fmt.Errorf("%w at http://localhost:8080: connection refused", ErrUnreachable)
+	}
+
+	stale, age, err := FetchCached(cache, "k", true, unreachable)
+	if err != nil {
+		t.Fatalf("expected stale result, got error: %v", err)
+	}
+	if stale.ValueInMgPerDl != 120 || age <= 0 {
+		t.Errorf("unexpected stale result: %v (age %v)", stale, age)
+	}
+
+	// Without --allow-stale the error is returned
+	if _, _, err := FetchCached(cache, "k", false, unreachable); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got %v", err)
+	}
+}
+
+func TestFetchCached_OtherErrorsNotMasked(t *testing.T) {
+	cache := NewCache(t.TempDir(), "http://localhost:8080")
+	cache.Save("k", &GlucoseReading{ValueInMgPerDl: 120})
+
+	_, _, err := FetchCached(cache, "k", true, func() (*GlucoseReading, error) {
+		return nil, errors.New("API returned status 500")
+	})
+	if err == nil {
+		t.Error("expected server error to be returned, got nil")
+	}
+}
+
+func TestFormatStaleMarker(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{30 * time.Second, "(stale, <1m old)"},
+		{12 * time.Minute, "(stale, 12m old)"},
+		{2*time.Hour + 5*time.Minute, "(stale, 2h05m old)"},
+		{50 * time.Hour, "(stale, 2d old)"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatStaleMarker(tt.age); got != tt.want {
+			t.Errorf("FormatStaleMarker(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
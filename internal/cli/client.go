@@ -1,26 +1,71 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
+
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
 )
 
 // Client wraps HTTP calls to the glcore API
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	config     ClientConfig
+}
+
+// ClientConfig holds timeout and retry settings for the CLI client.
+type ClientConfig struct {
+	Timeout      time.Duration // Timeout of a single attempt (connection + response)
+	Retries      int           // Additional attempts after a transient failure
+	RetryBackoff time.Duration // Delay before the first retry, doubled on each retry
+	Token        string        // Bearer token sent with every request (empty = none)
+	Profile      string        // glcli profile the settings come from, reported in authentication errors
+	PatientID    string        // Patient the glucose and sensor data is scoped to (empty = server default)
+}
+
+// maxRetryBackoff caps the exponential backoff between retries
+const maxRetryBackoff = 5 * time.Second
+
+// DefaultClientConfig returns the default client settings.
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		Timeout:      10 * time.Second,
+		Retries:      2,
+		RetryBackoff: 500 * time.Millisecond,
+	}
+}
+
+// MaxDuration returns the worst-case duration of a request including every
+// retry and backoff delay.
+func (c ClientConfig) MaxDuration() time.Duration {
+	total := c.Timeout * time.Duration(c.Retries+1)
+	backoff := c.RetryBackoff
+	for i := 0; i < c.Retries; i++ {
+		total += backoff
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+	return total
 }
 
-// NewClient creates a new CLI client
+// NewClient creates a new CLI client with default settings
 func NewClient(baseURL string) *Client {
+	return NewClientWithConfig(baseURL, DefaultClientConfig())
+}
+
+// NewClientWithConfig creates a new CLI client with custom timeout and retry settings
+func NewClientWithConfig(baseURL string, config ClientConfig) *Client {
 	return &Client{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		baseURL:    baseURL,
+		httpClient: &http.Client{},
+		config:     config,
 	}
 }
 
@@ -49,21 +94,24 @@ type SensorInfo struct {
 	DaysElapsed       float64  `json:"daysElapsed"`
 	ActualDays        *float64 `json:"actualDays,omitempty"`
 	Status            string   `json:"status"`
+	ApplicationSite   string   `json:"applicationSite,omitempty"`
+	Note              string   `json:"note,omitempty"`
+	Rating            *int     `json:"rating,omitempty"`
 }
 
 // GetLatestGlucose fetches the latest glucose reading
 func (c *Client) GetLatestGlucose(ctx context.Context) (*GlucoseReading, error) {
 	resp, err := c.get(ctx, "/v1/glucose/latest")
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect to glcore at %s: %w", c.baseURL, err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("no glucose readings available")
+		return nil, ErrNoReadings
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, newHTTPError(resp)
 	}
 
 	var result struct {
@@ -74,7 +122,7 @@ func (c *Client) GetLatestGlucose(ctx context.Context) (*GlucoseReading, error)
 	}
 
 	if result.Data == nil {
-		return nil, fmt.Errorf("no glucose readings available")
+		return nil, ErrNoReadings
 	}
 
 	return result.Data, nil
@@ -84,7 +132,7 @@ func (c *Client) GetLatestGlucose(ctx context.Context) (*GlucoseReading, error)
 func (c *Client) GetLatestSensor(ctx context.Context) (*SensorInfo, error) {
 	resp, err := c.get(ctx, "/v1/sensor/latest")
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect to glcore at %s: %w", c.baseURL, err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -92,7 +140,7 @@ func (c *Client) GetLatestSensor(ctx context.Context) (*SensorInfo, error) {
 		return nil, fmt.Errorf("no active sensor found")
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, newHTTPError(resp)
 	}
 
 	var result struct {
@@ -124,6 +172,9 @@ func (c *Client) GetGlucose(ctx context.Context, params GlucoseParams) (*Glucose
 	if params.Limit > 0 {
 		queryParts = append(queryParts, fmt.Sprintf("limit=%d", params.Limit))
 	}
+	if params.Query != "" {
+		queryParts = append(queryParts, "q="+url.QueryEscape(params.Query))
+	}
 
 	for i, part := range queryParts {
 		if i > 0 {
@@ -134,12 +185,12 @@ func (c *Client) GetGlucose(ctx context.Context, params GlucoseParams) (*Glucose
 
 	resp, err := c.get(ctx, path)
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect to glcore at %s: %w", c.baseURL, err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, newHTTPError(resp)
 	}
 
 	var result GlucoseListResponse
@@ -175,12 +226,12 @@ func (c *Client) GetGlucoseStatistics(ctx context.Context, start, end *time.Time
 
 	resp, err := c.get(ctx, path)
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect to glcore at %s: %w", c.baseURL, err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, newHTTPError(resp)
 	}
 
 	var result StatisticsResponse
@@ -191,6 +242,95 @@ func (c *Client) GetGlucoseStatistics(ctx context.Context, start, end *time.Time
 	return &result, nil
 }
 
+// GetGlucoseQuality fetches the daily data quality within a time range
+func (c *Client) GetGlucoseQuality(ctx context.Context, start, end time.Time) (*QualityReport, error) {
+	query := url.Values{}
+	query.Set("start", start.UTC().Format(time.RFC3339))
+	query.Set("end", end.UTC().Format(time.RFC3339))
+
+	resp, err := c.get(ctx, "/v1/glucose/quality?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data *QualityReport `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// GetGlucoseHistogram fetches reading counts per value bucket of bucketMgDl
+// within a time range (nil = all time)
+func (c *Client) GetGlucoseHistogram(ctx context.Context, start, end *time.Time, bucketMgDl int) (*Histogram, error) {
+	query := url.Values{}
+	if start != nil && end != nil {
+		query.Set("start", start.UTC().Format(time.RFC3339))
+		query.Set("end", end.UTC().Format(time.RFC3339))
+	}
+	if bucketMgDl > 0 {
+		query.Set("bucketMgDl", strconv.Itoa(bucketMgDl))
+	}
+
+	resp, err := c.get(ctx, "/v1/glucose/histogram?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data *Histogram `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// GetGlucosePercentiles fetches the glucose percentiles by time of day over
+// the last days, in buckets of bucket (0 = server defaults)
+func (c *Client) GetGlucosePercentiles(ctx context.Context, days int, bucket time.Duration) (*Percentiles, error) {
+	query := url.Values{}
+	if days > 0 {
+		query.Set("days", strconv.Itoa(days))
+	}
+	if bucket > 0 {
+		query.Set("bucket", bucket.String())
+	}
+
+	resp, err := c.get(ctx, "/v1/glucose/percentiles?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data *Percentiles `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
 // GetSensor fetches sensor history with optional filtering
 func (c *Client) GetSensor(ctx context.Context, params SensorParams) (*SensorListResponse, error) {
 	path := "/v1/sensor?"
@@ -215,12 +355,12 @@ func (c *Client) GetSensor(ctx context.Context, params SensorParams) (*SensorLis
 
 	resp, err := c.get(ctx, path)
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect to glcore at %s: %w", c.baseURL, err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, newHTTPError(resp)
 	}
 
 	var result SensorListResponse
@@ -231,8 +371,10 @@ func (c *Client) GetSensor(ctx context.Context, params SensorParams) (*SensorLis
 	return &result, nil
 }
 
-// GetSensorStatistics fetches sensor lifecycle statistics
-func (c *Client) GetSensorStatistics(ctx context.Context, start, end *time.Time) (*SensorStatisticsResponse, error) {
+// GetSensorStatistics fetches sensor lifecycle statistics of the sensors
+// activated between start and end and ended between endedStart and endedEnd
+// (nil for no filter)
+func (c *Client) GetSensorStatistics(ctx context.Context, start, end, endedStart, endedEnd *time.Time) (*SensorStatisticsResponse, error) {
 	path := "/v1/sensor/stats"
 	queryParts := []string{}
 
@@ -242,6 +384,12 @@ func (c *Client) GetSensorStatistics(ctx context.Context, start, end *time.Time)
 	if end != nil {
 		queryParts = append(queryParts, fmt.Sprintf("end=%s", end.UTC().Format(time.RFC3339)))
 	}
+	if endedStart != nil {
+		queryParts = append(queryParts, fmt.Sprintf("endedStart=%s", endedStart.UTC().Format(time.RFC3339)))
+	}
+	if endedEnd != nil {
+		queryParts = append(queryParts, fmt.Sprintf("endedEnd=%s", endedEnd.UTC().Format(time.RFC3339)))
+	}
 
 	if len(queryParts) > 0 {
 		path += "?"
@@ -255,12 +403,12 @@ func (c *Client) GetSensorStatistics(ctx context.Context, start, end *time.Time)
 
 	resp, err := c.get(ctx, path)
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect to glcore at %s: %w", c.baseURL, err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, newHTTPError(resp)
 	}
 
 	var result SensorStatisticsResponse
@@ -271,11 +419,655 @@ func (c *Client) GetSensorStatistics(ctx context.Context, start, end *time.Time)
 	return &result, nil
 }
 
+// SetSensorSite records the application site of the current sensor.
+// Writes are not retried.
+func (c *Client) SetSensorSite(ctx context.Context, site string) (*SensorSiteResult, error) {
+	body, err := json.Marshal(map[string]string{"site": site})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, "/v1/sensor/latest/site", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("no active sensor found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data SensorSiteResult `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result.Data, nil
+}
+
+// UpdateSensorFeedback records the note and rating of a sensor.
+// Nil fields are left unchanged; an empty note or a zero rating clears it.
+// Writes are not retried.
+func (c *Client) UpdateSensorFeedback(ctx context.Context, serial string, note *string, rating *int) (*SensorInfo, error) {
+	body, err := json.Marshal(struct {
+		Note   *string `json:"note,omitempty"`
+		Rating *int    `json:"rating,omitempty"`
+	}{note, rating})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, http.MethodPatch, "/v1/sensor/"+url.PathEscape(serial), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("sensor %s not found", serial)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data SensorInfo `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result.Data, nil
+}
+
+// GetSensorSites fetches the application site history, newest first
+func (c *Client) GetSensorSites(ctx context.Context, limit int) ([]SensorSite, error) {
+	resp, err := c.get(ctx, fmt.Sprintf("/v1/sensor/sites?limit=%d", limit))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data []SensorSite `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// GetMode fetches the current activity mode
+func (c *Client) GetMode(ctx context.Context) (*ModeStatus, error) {
+	resp, err := c.get(ctx, "/v1/mode")
+	if err != nil {
+		return nil, err
+	}
+	return decodeMode(resp)
+}
+
+// StartExercise switches glcore to exercise mode for duration
+func (c *Client) StartExercise(ctx context.Context, duration time.Duration) (*ModeStatus, error) {
+	resp, err := c.do(ctx, http.MethodPost, "/v1/mode/exercise?duration="+duration.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMode(resp)
+}
+
+// EndExercise reverts glcore to normal mode
+func (c *Client) EndExercise(ctx context.Context) (*ModeStatus, error) {
+	resp, err := c.do(ctx, http.MethodDelete, "/v1/mode/exercise", nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMode(resp)
+}
+
+// decodeMode decodes and closes a mode response
+func decodeMode(resp *http.Response) (*ModeStatus, error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data *ModeStatus `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// GetConnection fetches the details of the upstream connection
+func (c *Client) GetConnection(ctx context.Context) (*ConnectionInfo, error) {
+	resp, err := c.get(ctx, "/v1/connection")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data *ConnectionInfo `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// GetPreferences fetches the display preferences
+func (c *Client) GetPreferences(ctx context.Context) (*Preferences, error) {
+	resp, err := c.get(ctx, "/v1/preferences")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data *Preferences `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// GetUpstreamStatus fetches the availability of LibreView and the outages of the last days days
+func (c *Client) GetUpstreamStatus(ctx context.Context, days int) (*UpstreamStatus, error) {
+	query := url.Values{}
+	query.Set("days", strconv.Itoa(days))
+
+	resp, err := c.get(ctx, "/v1/upstream/status?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data *UpstreamStatus `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// GetAlerts fetches the alert history, newest first
+func (c *Client) GetAlerts(ctx context.Context, params AlertParams) (*AlertListResponse, error) {
+	query := url.Values{}
+	if params.Type != "" {
+		query.Set("type", params.Type)
+	}
+	if params.Unacknowledged {
+		query.Set("acknowledged", "false")
+	}
+	if params.Limit > 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+
+	resp, err := c.get(ctx, "/v1/alerts/history?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result AlertListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetAlertWeekly fetches alert counts for the last weeks weeks, oldest first
+func (c *Client) GetAlertWeekly(ctx context.Context, weeks int) ([]AlertWeek, error) {
+	resp, err := c.get(ctx, fmt.Sprintf("/v1/alerts/weekly?weeks=%d", weeks))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data []AlertWeek `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// AcknowledgeAlert marks an alert as acknowledged
+func (c *Client) AcknowledgeAlert(ctx context.Context, id uint) error {
+	resp, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/v1/alerts/%d/ack", id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newHTTPError(resp)
+	}
+	return nil
+}
+
+// GetTreatments fetches insulin treatments within a time range, oldest first
+func (c *Client) GetTreatments(ctx context.Context, start, end time.Time) ([]Treatment, error) {
+	query := url.Values{}
+	query.Set("start", start.UTC().Format(time.RFC3339))
+	query.Set("end", end.UTC().Format(time.RFC3339))
+
+	resp, err := c.get(ctx, "/v1/treatments?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data []Treatment `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// GetTreatmentAnalysis fetches how glucose responded to boluses within a time range
+func (c *Client) GetTreatmentAnalysis(ctx context.Context, start, end time.Time) (*TreatmentAnalysis, error) {
+	query := url.Values{}
+	query.Set("start", start.UTC().Format(time.RFC3339))
+	query.Set("end", end.UTC().Format(time.RFC3339))
+
+	resp, err := c.get(ctx, "/v1/treatments/analysis?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data *TreatmentAnalysis `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// ImportTreatments uploads a pump CSV export of the given source (omnipod, tandem)
+func (c *Client) ImportTreatments(ctx context.Context, source string, export io.Reader) (*TreatmentImportResult, error) {
+	resp, err := c.doContent(ctx, http.MethodPost, "/v1/treatments/import?source="+url.QueryEscape(source), "text/csv", export)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data *TreatmentImportResult `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// StartTreatmentImport uploads a pump CSV export and imports it as a
+// background job on glcore, whose progress is followed with WaitForJob
+func (c *Client) StartTreatmentImport(ctx context.Context, source string, export io.Reader) (*Job, error) {
+	resp, err := c.doContent(ctx, http.MethodPost, "/v1/treatments/import?async=true&source="+url.QueryEscape(source), "text/csv", export)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJob(resp, http.StatusAccepted)
+}
+
+// GetJobs fetches background jobs, newest first
+func (c *Client) GetJobs(ctx context.Context, params JobParams) (*JobListResponse, error) {
+	query := url.Values{}
+	if params.Type != "" {
+		query.Set("type", params.Type)
+	}
+	if params.Status != "" {
+		query.Set("status", params.Status)
+	}
+	if params.Limit > 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+
+	resp, err := c.get(ctx, "/v1/jobs?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result JobListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetJobSchedules fetches the recurring jobs, soonest first
+func (c *Client) GetJobSchedules(ctx context.Context) ([]JobSchedule, error) {
+	resp, err := c.get(ctx, "/v1/jobs/schedules")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data []JobSchedule `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// GetJob fetches the status and progress of a background job
+func (c *Client) GetJob(ctx context.Context, id string) (*Job, error) {
+	resp, err := c.get(ctx, "/v1/jobs/"+url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	return decodeJob(resp, http.StatusOK)
+}
+
+// CancelJob cancels a pending background job or asks a running one to stop
+func (c *Client) CancelJob(ctx context.Context, id string) (*Job, error) {
+	resp, err := c.do(ctx, http.MethodDelete, "/v1/jobs/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJob(resp, http.StatusOK)
+}
+
+// decodeJob decodes and closes a job response with the expected status
+func decodeJob(resp *http.Response, status int) (*Job, error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != status {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data *Job `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// GetViews fetches the saved views, sorted by name
+func (c *Client) GetViews(ctx context.Context) ([]SavedView, error) {
+	resp, err := c.get(ctx, "/v1/views")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data []SavedView `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// SaveView creates a saved view or replaces the view with the same name
+func (c *Client) SaveView(ctx context.Context, view SavedView) (*SavedView, error) {
+	body, err := json.Marshal(struct {
+		Description string `json:"description"`
+		Query       string `json:"query"`
+		Period      string `json:"period"`
+		Aggregation string `json:"aggregation"`
+	}{view.Description, view.Query, view.Period, view.Aggregation})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, "/v1/views/"+url.PathEscape(view.Name), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result struct {
+		Data *SavedView `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// DeleteView removes a saved view
+func (c *Client) DeleteView(ctx context.Context, name string) error {
+	resp, err := c.do(ctx, http.MethodDelete, "/v1/views/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newHTTPError(resp)
+	}
+	return nil
+}
+
+// RunView runs a saved view; limit applies to list views (0 = server default)
+func (c *Client) RunView(ctx context.Context, name string, limit int) (*ViewRunResponse, error) {
+	path := "/v1/views/" + url.PathEscape(name) + "/run"
+	if limit > 0 {
+		path += fmt.Sprintf("?limit=%d", limit)
+	}
+
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(resp)
+	}
+
+	var result ViewRunResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// get performs a GET request, retrying transient failures (network errors,
+// 429/502/503/504) with exponential backoff. Transport errors are returned as
+// *ConnectionError. A retryable status on the last attempt is returned as is.
 func (c *Client) get(ctx context.Context, path string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	backoff := c.config.RetryBackoff
+	var lastErr error
+
+	for attempt := 0; attempt <= c.config.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, classifyError(c.baseURL, ctx.Err())
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, maxRetryBackoff)
+		}
+
+		resp, err := c.doGet(ctx, path)
+		if err != nil {
+			lastErr = err
+			if !isRetryable(err) || ctx.Err() != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if isRetryableStatus(resp.StatusCode) && attempt < c.config.Retries {
+			resp.Body.Close()
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, lastErr
+}
+
+// doGet performs a single GET attempt bounded by the configured timeout.
+// The timeout covers reading the body, which is released on Body.Close().
+func (c *Client) doGet(ctx context.Context, path string) (*http.Response, error) {
+	return c.do(ctx, http.MethodGet, path, nil)
+}
+
+// do performs a single request attempt bounded by the configured timeout.
+// A non-nil body is sent as JSON.
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	return c.doContent(ctx, method, path, "application/json", body)
+}
+
+// doContent is like do, sending a non-nil body with the given content type.
+func (c *Client) doContent(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Response, error) {
+	attemptCtx, cancel := context.WithCancel(ctx)
+	if c.config.Timeout > 0 {
+		attemptCtx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+	}
+
+	req, err := http.NewRequestWithContext(attemptCtx, method, c.baseURL+path, body)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
+	if c.config.PatientID != "" {
+		query := req.URL.Query()
+		query.Set("patientId", c.config.PatientID)
+		req.URL.RawQuery = query.Encode()
+	}
 	req.Header.Set("Accept", "application/json")
-	return c.httpClient.Do(req)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set(glclient.APIVersionHeader, strconv.Itoa(glclient.SchemaVersion))
+	c.setAuthorization(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, classifyError(c.baseURL, err)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		defer cancel()
+		defer resp.Body.Close()
+		return nil, c.newAuthError(resp)
+	}
+
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// newAuthError builds an AuthError from a 401 response.
+func (c *Client) newAuthError(resp *http.Response) *AuthError {
+	return &AuthError{
+		URL:       c.baseURL,
+		Profile:   c.config.Profile,
+		TokenSent: c.config.Token != "",
+		Message:   newHTTPError(resp).Message,
+	}
+}
+
+// setAuthorization adds the configured bearer token to req.
+func (c *Client) setAuthorization(req *http.Request) {
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+}
+
+// cancelOnClose releases the attempt context when the response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
 }
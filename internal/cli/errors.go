@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// ErrUnreachable is matched (via errors.Is) by every ConnectionError.
+var ErrUnreachable = errors.New("cannot connect to glcore")
+
+// ErrUnauthorized is matched (via errors.Is) by every AuthError.
+var ErrUnauthorized = errors.New("glcore rejected the request: authentication required")
+
+// ErrNoReadings is returned when the server has no glucose reading yet.
+var ErrNoReadings = errors.New("no glucose readings available")
+
+// Connection failure reasons
+const (
+	ReasonDNS     = "DNS lookup failed"
+	ReasonRefused = "connection refused"
+	ReasonTLS     = "TLS error"
+	ReasonTimeout = "request timed out"
+	ReasonNetwork = "network error"
+)
+
+// ConnectionError represents a failure to reach the glcore server.
+type ConnectionError struct {
+	URL    string
+	Reason string // One of the Reason* constants
+	Err    error
+}
+
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("cannot connect to glcore at %s: %s: %v", e.URL, e.Reason, e.Err)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrUnreachable) true for any ConnectionError.
+func (e *ConnectionError) Is(target error) bool {
+	return target == ErrUnreachable
+}
+
+// HTTPError represents a non-2xx response from the glcore server.
+type HTTPError struct {
+	StatusCode int
+	Message    string // Message from the API error body, if any
+}
+
+func (e *HTTPError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API returned status %d", e.StatusCode)
+}
+
+// AuthError represents a 401 response: the server requires an API token and
+// none, or an invalid one, was sent.
+type AuthError struct {
+	URL       string
+	Profile   string // glcli profile in use ("" = not reported)
+	TokenSent bool
+	Message   string // Message from the API error body, if any
+}
+
+func (e *AuthError) Error() string {
+	profile := ""
+	if e.Profile != "" {
+		profile = fmt.Sprintf(" (profile %q)", e.Profile)
+	}
+
+	if !e.TokenSent {
+		return fmt.Sprintf("glcore at %s requires an API token%s: set it with --token, GLCMD_API_TOKEN or the token key of the profile", e.URL, profile)
+	}
+
+	reason := e.Message
+	if reason == "" {
+		reason = "Invalid token"
+	}
+	return fmt.Sprintf("glcore at %s rejected the API token%s: %s", e.URL, profile, reason)
+}
+
+// Is makes errors.Is(err, ErrUnauthorized) true for any AuthError.
+func (e *AuthError) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+// newHTTPError builds an HTTPError from a response, extracting the API error message.
+func newHTTPError(resp *http.Response) *HTTPError {
+	httpErr := &HTTPError{StatusCode: resp.StatusCode}
+
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&body); err == nil {
+		httpErr.Message = body.Error.Message
+	}
+
+	return httpErr
+}
+
+// classifyError converts a transport error into a ConnectionError with a
+// human-readable reason. Context cancellation by the caller is returned as is.
+func classifyError(baseURL string, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	reason := ReasonNetwork
+
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var recordErr tls.RecordHeaderError
+	var netErr net.Error
+
+	switch {
+	case errors.As(err, &dnsErr):
+		reason = ReasonDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		reason = ReasonRefused
+	case errors.As(err, &certErr), errors.As(err, &unknownAuthErr),
+		errors.As(err, &hostnameErr), errors.As(err, &recordErr):
+		reason = ReasonTLS
+	case errors.Is(err, context.DeadlineExceeded):
+		reason = ReasonTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		reason = ReasonTimeout
+	}
+
+	return &ConnectionError{URL: baseURL, Reason: reason, Err: err}
+}
+
+// isRetryable reports whether a request failing with err may succeed if retried.
+// TLS errors are permanent (certificate problems do not fix themselves).
+func isRetryable(err error) bool {
+	var connErr *ConnectionError
+	if !errors.As(err, &connErr) {
+		return false
+	}
+	return connErr.Reason != ReasonTLS
+}
+
+// isRetryableStatus reports whether an HTTP status indicates a transient failure.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testClient(url string, retries int) *Client {
+	return NewClientWithConfig(url, ClientConfig{
+		Timeout:      time.Second,
+		Retries:      retries,
+		RetryBackoff: time.Millisecond,
+	})
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		reason string
+	}{
+		{"dns", &net.DNSError{Err: "no such host", Name: "glcore.invalid", IsNotFound: true}, ReasonDNS},
+		{"timeout", context.DeadlineExceeded, ReasonTimeout},
+		{"other", errors.New("broken pipe"), ReasonNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError("http://glcore", tt.err)
+
+			var connErr *ConnectionError
+			if !errors.As(err, &connErr) {
+				t.Fatalf("expected ConnectionError, got %T", err)
+			}
+			if connErr.Reason != tt.reason {
+				t.Errorf("expected reason %q, got %q", tt.reason, connErr.Reason)
+			}
+			if !errors.Is(err, ErrUnreachable) {
+				t.Error("expected error to match ErrUnreachable")
+			}
+		})
+	}
+}
+
+func TestClassifyError_Canceled(t *testing.T) {
+	if err := classifyError("http://glcore", context.Canceled); !errors.Is(err, context.Canceled) || errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected bare context.Canceled, got %v", err)
+	}
+}
+
+func TestClient_ConnectionRefused(t *testing.T) {
+	// Reserve a port then close it so nothing listens there
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://" + listener.Addr().String()
+	listener.Close()
+
+	_, err = testClient(url, 1).GetLatestGlucose(context.Background())
+
+	var connErr *ConnectionError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("expected ConnectionError, got %v", err)
+	}
+	if connErr.Reason != ReasonRefused {
+		t.Errorf("expected reason %q, got %q", ReasonRefused, connErr.Reason)
+	}
+}
+
+func TestClient_RetriesTransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"valueInMgPerDl":110}}`)
+	}))
+	defer server.Close()
+
+	reading, err := testClient(server.URL, 2).GetLatestGlucose(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reading.ValueInMgPerDl != 110 {
+		t.Errorf("expected 110 mg/dL, got %d", reading.ValueInMgPerDl)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestClient_HTTPErrorNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"message":"invalid limit"}}`)
+	}))
+	defer server.Close()
+
+	_, err := testClient(server.URL, 2).GetGlucose(context.Background(), GlucoseParams{})
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected HTTPError, got %v", err)
+	}
+	if httpErr.StatusCode != http.StatusBadRequest || httpErr.Message != "invalid limit" {
+		t.Errorf("unexpected HTTPError: %+v", httpErr)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestClient_BearerToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer dashboard-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":{"message":"Invalid token"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"valueInMgPerDl":110}}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{"valid token", "dashboard-token", ""},
+		{"missing token", "", `requires an API token (profile "home")`},
+		{"wrong token", "wrong", `rejected the API token (profile "home"): Invalid token`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClientWithConfig(server.URL, ClientConfig{Timeout: time.Second, Retries: 2, Token: tt.token, Profile: "home"})
+			_, err := c.GetLatestGlucose(context.Background())
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnauthorized) {
+				t.Fatalf("expected ErrUnauthorized, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestClient_PerRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	c := NewClientWithConfig(server.URL, ClientConfig{Timeout: 20 * time.Millisecond})
+	_, err := c.GetLatestGlucose(context.Background())
+
+	var connErr *ConnectionError
+	if !errors.As(err, &connErr) || connErr.Reason != ReasonTimeout {
+		t.Fatalf("expected timeout ConnectionError, got %v", err)
+	}
+}
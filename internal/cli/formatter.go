@@ -29,38 +29,49 @@ func TrendArrowText(arrow *int) string {
 	}
 }
 
-// FormatGlucoseShort formats a glucose reading as two lines with emoji status
-func FormatGlucoseShort(g *GlucoseReading) string {
+// FormatGlucoseShort formats a glucose reading as two lines with emoji status.
+// prefs selects the unit order, time zone and emoji (nil = defaults).
+func FormatGlucoseShort(g *GlucoseReading, prefs *Preferences) string {
+	if prefs == nil {
+		prefs = DefaultPreferences()
+	}
 	var sb strings.Builder
 
 	// Line 1: value + trend
-	trend := TrendArrowText(g.TrendArrow)
+	value := formatReadingValue(prefs, g.Value, g.ValueInMgPerDl)
+	if prefs.Emoji {
+		value = "🩸 " + value
+	}
+	trend := formatTrend(prefs, TrendArrowText(g.TrendArrow))
 	if trend != "" {
-		sb.WriteString(fmt.Sprintf("🩸 %.1f mmol/L (%d mg/dL) %s", g.Value, g.ValueInMgPerDl, trend))
+		sb.WriteString(fmt.Sprintf("%s %s", value, trend))
 	} else {
-		sb.WriteString(fmt.Sprintf("🩸 %.1f mmol/L (%d mg/dL)", g.Value, g.ValueInMgPerDl))
+		sb.WriteString(value)
 	}
 
 	// Line 2: colored status + time
-	status := formatStatus(g.IsLow, g.IsHigh)
-	timeStr := g.Timestamp.Local().Format("15:04")
+	status := formatReadingStatus(prefs, g.IsLow, g.IsHigh)
+	timeStr := g.Timestamp.In(prefs.location()).Format("15:04")
 	sb.WriteString(fmt.Sprintf("\n   %s | %s", status, timeStr))
 
 	return sb.String()
 }
 
-// FormatGlucose formats a glucose reading with full details
-func FormatGlucose(g *GlucoseReading) string {
+// FormatGlucose formats a glucose reading with full details.
+// prefs selects the unit order, time zone and emoji (nil = defaults).
+func FormatGlucose(g *GlucoseReading, prefs *Preferences) string {
+	if prefs == nil {
+		prefs = DefaultPreferences()
+	}
 	var sb strings.Builder
 
 	// Main value line with trend
-	trend := TrendArrowText(g.TrendArrow)
+	value := formatReadingValue(prefs, g.Value, g.ValueInMgPerDl)
+	trend := formatTrend(prefs, TrendArrowText(g.TrendArrow))
 	if trend != "" {
-		sb.WriteString(fmt.Sprintf("Glucose: %.1f mmol/L (%d mg/dL) %s\n",
-			g.Value, g.ValueInMgPerDl, trend))
+		sb.WriteString(fmt.Sprintf("Glucose: %s %s\n", value, trend))
 	} else {
-		sb.WriteString(fmt.Sprintf("Glucose: %.1f mmol/L (%d mg/dL)\n",
-			g.Value, g.ValueInMgPerDl))
+		sb.WriteString(fmt.Sprintf("Glucose: %s\n", value))
 	}
 
 	// Status line
@@ -73,11 +84,35 @@ func FormatGlucose(g *GlucoseReading) string {
 	sb.WriteString(fmt.Sprintf("Status: %s\n", status))
 
 	// Timestamp
-	sb.WriteString(fmt.Sprintf("Time: %s", g.Timestamp.Local().Format("15:04:05")))
+	sb.WriteString(fmt.Sprintf("Time: %s", g.Timestamp.In(prefs.location()).Format("15:04:05")))
 
 	return sb.String()
 }
 
+// formatReadingValue formats a value in both units, the preferred one first
+func formatReadingValue(prefs *Preferences, mmol float64, mgdl int) string {
+	if prefs.Unit == "mgdl" {
+		return fmt.Sprintf("%d mg/dL (%.1f mmol/L)", mgdl, mmol)
+	}
+	return fmt.Sprintf("%.1f mmol/L (%d mg/dL)", mmol, mgdl)
+}
+
+// formatTrend drops the arrows of a trend text when emoji are disabled
+func formatTrend(prefs *Preferences, trend string) string {
+	if prefs.Emoji {
+		return trend
+	}
+	if _, text, ok := strings.Cut(trend, " "); ok {
+		return strings.TrimSpace(text)
+	}
+	return trend
+}
+
+// formatReadingStatus returns the status with or without its colored emoji
+func formatReadingStatus(prefs *Preferences, isLow, isHigh bool) string {
+	return formatTrend(prefs, formatStatus(isLow, isHigh))
+}
+
 // FormatSensor formats sensor info for human display
 // Priority: most important info first (remaining time for active, status for problematic)
 func FormatSensor(s *SensorInfo) string {
@@ -123,6 +158,11 @@ func FormatSensor(s *SensorInfo) string {
 		}
 		sb.WriteString(fmt.Sprintf("   Expires: %s", expiresDateTime))
 
+	case "grace":
+		sb.WriteString(fmt.Sprintf("⏳ Sensor %s\n", s.SerialNumber))
+		sb.WriteString(fmt.Sprintf("   Expired %s, still reporting (grace period)\n", expiresDateTime))
+		sb.WriteString(fmt.Sprintf("   %.1f / %.1f days", s.DaysElapsed, float64(s.DurationDays)))
+
 	case "stopped":
 		sb.WriteString(fmt.Sprintf("Stopped | %.1f / %.1f days\n", s.DaysElapsed, float64(s.DurationDays)))
 		sb.WriteString(fmt.Sprintf("Sensor: %s", s.SerialNumber))
@@ -138,6 +178,10 @@ func FormatSensor(s *SensorInfo) string {
 		}
 	}
 
+	if s.ApplicationSite != "" && s.Status != "stopped" {
+		sb.WriteString(fmt.Sprintf("\n   Site: %s", s.ApplicationSite))
+	}
+
 	return sb.String()
 }
 
@@ -162,25 +206,37 @@ func FormatJSON(v interface{}) (string, error) {
 	return string(data), nil
 }
 
-// FormatMeasurementTable formats a list of measurements as a table
-func FormatMeasurementTable(measurements []GlucoseReading, total int) string {
+// FormatMeasurementTable formats a list of measurements as a table.
+// prefs selects the unit order, time zone and emoji (nil = defaults).
+func FormatMeasurementTable(measurements []GlucoseReading, total int, prefs *Preferences) string {
 	if len(measurements) == 0 {
 		return "No measurements found"
 	}
+	if prefs == nil {
+		prefs = DefaultPreferences()
+	}
 
 	var sb strings.Builder
 
 	// Table header
+	unitHeader := "mmol/L (mg/dL)"
+	if prefs.Unit == "mgdl" {
+		unitHeader = "mg/dL (mmol/L)"
+	}
 	sb.WriteString("┌─────────────────────┬───────────────┬──────────────────┬───────────┐\n")
-	sb.WriteString("│ Date                │ mmol/L (mg/dL)│ Trend            │ Status    │\n")
+	sb.WriteString(fmt.Sprintf("│ Date                │ %s│ Trend            │ Status    │\n", unitHeader))
 	sb.WriteString("├─────────────────────┼───────────────┼──────────────────┼───────────┤\n")
 
 	// Table rows
+	loc := prefs.location()
 	for _, m := range measurements {
-		date := m.Timestamp.Local().Format("02/01 15:04")
+		date := m.Timestamp.In(loc).Format("02/01 15:04")
 		glucose := fmt.Sprintf("%.1f (%d)", m.Value, m.ValueInMgPerDl)
-		trend := formatTrendShort(m.TrendArrow)
-		status := formatStatus(m.IsLow, m.IsHigh)
+		if prefs.Unit == "mgdl" {
+			glucose = fmt.Sprintf("%d (%.1f)", m.ValueInMgPerDl, m.Value)
+		}
+		trend := formatTrend(prefs, formatTrendShort(m.TrendArrow))
+		status := formatReadingStatus(prefs, m.IsLow, m.IsHigh)
 
 		sb.WriteString(fmt.Sprintf("│ %-19s │ %-13s │ %-16s │ %-8s │\n",
 			date, glucose, trend, status))
@@ -197,9 +253,11 @@ func FormatMeasurementTable(measurements []GlucoseReading, total int) string {
 	}
 
 	// Legend for status symbols
-	sb.WriteString("Status: 🟢 Normal | 🟡 LOW | 🔴 HIGH")
+	if prefs.Emoji {
+		sb.WriteString("Status: 🟢 Normal | 🟡 LOW | 🔴 HIGH")
+	}
 
-	return sb.String()
+	return strings.TrimSuffix(sb.String(), "\n")
 }
 
 // formatTrendShort returns a short trend representation for table display
@@ -253,9 +311,13 @@ func FormatStatistics(stats *StatisticsData) string {
 		stats.Statistics.Min, stats.Statistics.Max,
 		stats.Statistics.MinMgDl, stats.Statistics.MaxMgDl))
 	sb.WriteString(fmt.Sprintf("   Std Dev:      %.1f mmol/L\n", stats.Statistics.StdDev))
+	sb.WriteString(fmt.Sprintf("   CV:           %.1f%%\n", stats.Statistics.CV))
 	if stats.Statistics.GMI != nil {
 		sb.WriteString(fmt.Sprintf("   GMI:          %.1f%%\n", *stats.Statistics.GMI))
 	}
+	if stats.Statistics.EstimatedA1c != nil {
+		sb.WriteString(fmt.Sprintf("   Est. A1c:     %.1f%%\n", *stats.Statistics.EstimatedA1c))
+	}
 	sb.WriteString("\n")
 
 	// Distribution section - calculate percentages from counts
@@ -288,6 +350,101 @@ func FormatStatistics(stats *StatisticsData) string {
 	} else {
 		sb.WriteString("   No glucose targets configured")
 	}
+	for _, band := range stats.Statistics.TargetBands {
+		label := band.Name
+		if label == "tight" {
+			label = "TITR"
+		}
+		sb.WriteString(fmt.Sprintf("\n   %s (%d-%d mg/dL): %.1f%%", label, band.LowMgDl, band.HighMgDl, band.Percent))
+	}
+
+	if q := stats.DataQuality; q != nil {
+		sb.WriteString("\n\n🧪 Data Quality\n")
+		sb.WriteString(fmt.Sprintf("   Capture: %.1f%%  |  Gaps: %d  |  Artifacts: %d  |  Score: %d/100",
+			q.CapturePercent, q.Gaps, q.Artifacts, q.Score))
+		if q.LowConfidence {
+			sb.WriteString("\n   ⚠️  Low confidence: too little data for these statistics to be representative")
+		}
+	}
+
+	return sb.String()
+}
+
+// histogramWidth is the length of the longest histogram bar, in characters
+const histogramWidth = 40
+
+// FormatHistogram formats a glucose histogram as horizontal bars, scaled so
+// the largest bucket spans histogramWidth characters
+func FormatHistogram(h *Histogram) string {
+	if h.Total == 0 {
+		return "No readings found"
+	}
+
+	var largest int64
+	for _, b := range h.Buckets {
+		largest = max(largest, b.Count)
+	}
+
+	var sb strings.Builder
+	for _, b := range h.Buckets {
+		bar := strings.Repeat("█", int(b.Count*histogramWidth/largest))
+		if bar == "" && b.Count > 0 {
+			bar = "▏"
+		}
+		sb.WriteString(fmt.Sprintf("%3d-%-3d │ %-*s %5.1f%% (%d)\n",
+			b.StartMgDl, b.EndMgDl-1, histogramWidth, bar, b.Percent, b.Count))
+	}
+	sb.WriteString(fmt.Sprintf("%d readings, %d mg/dL buckets", h.Total, h.BucketMgDl))
+
+	return sb.String()
+}
+
+// FormatPercentiles formats the glucose percentiles by time of day as a table
+func FormatPercentiles(p *Percentiles) string {
+	if len(p.Buckets) == 0 {
+		return "No readings found"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(" Time  │  P5  │ P25  │ Median │ P75  │ P95  │ Readings\n")
+	sb.WriteString("───────┼──────┼──────┼────────┼──────┼──────┼─────────\n")
+	for _, b := range p.Buckets {
+		sb.WriteString(fmt.Sprintf(" %s │ %4.0f │ %4.0f │  %4.0f  │ %4.0f │ %4.0f │ %d\n",
+			b.Time, b.P5, b.P25, b.P50, b.P75, b.P95, b.Count))
+	}
+	sb.WriteString(fmt.Sprintf("mg/dL over the last %d days, %d minute buckets", p.Days, p.BucketMinutes))
+
+	return sb.String()
+}
+
+// FormatQuality formats the daily data quality as a table with a summary
+func FormatQuality(report *QualityReport) string {
+	if len(report.Days) == 0 {
+		return "No data quality to report"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("┌────────────┬──────────┬─────────┬──────┬───────────┬───────┐\n")
+	sb.WriteString("│ Date       │ Readings │ Capture │ Gaps │ Artifacts │ Score │\n")
+	sb.WriteString("├────────────┼──────────┼─────────┼──────┼───────────┼───────┤\n")
+
+	for _, d := range report.Days {
+		marker := "  "
+		if d.LowConfidence {
+			marker = "⚠️"
+		}
+		sb.WriteString(fmt.Sprintf("│ %-10s │ %8d │ %6.1f%% │ %4d │ %9d │ %3d %s│\n",
+			d.Date, d.Readings, d.CapturePercent, d.Gaps, d.Artifacts, d.Score, marker))
+	}
+
+	sb.WriteString("└────────────┴──────────┴─────────┴──────┴───────────┴───────┘\n")
+
+	q := report.Summary
+	sb.WriteString(fmt.Sprintf("Overall: %.1f%% capture, score %d/100", q.CapturePercent, q.Score))
+	if q.LowConfidenceDays > 0 {
+		sb.WriteString(fmt.Sprintf(", %d low-confidence day(s)", q.LowConfidenceDays))
+	}
 
 	return sb.String()
 }
@@ -303,6 +460,9 @@ func FormatSensorStats(data *SensorStatisticsData) string {
 	sb.WriteString("📈 Summary\n")
 	sb.WriteString(fmt.Sprintf("   Total sensors:  %d\n", data.Statistics.TotalSensors))
 	sb.WriteString(fmt.Sprintf("   Completed:      %d\n", data.Statistics.CompletedSensors))
+	if data.Statistics.AvgRating != nil {
+		sb.WriteString(fmt.Sprintf("   Avg rating:     %.1f / 5 (%d rated)\n", *data.Statistics.AvgRating, data.Statistics.RatedSensors))
+	}
 	sb.WriteString("\n")
 
 	// Duration section (only if there are completed sensors)
@@ -316,6 +476,26 @@ func FormatSensorStats(data *SensorStatisticsData) string {
 		sb.WriteString("\n")
 	}
 
+	// Capture section: readings received vs expected over each sensor's wear time
+	if len(data.Statistics.Sensors) > 0 {
+		sb.WriteString("📶 Capture rate\n")
+		for _, c := range data.Statistics.Sensors {
+			line := fmt.Sprintf("   %-14s %5.1f%%  (%d / %d readings, %.1f days)",
+				c.SerialNumber, c.CaptureRate, c.Readings, c.ExpectedReadings, c.WearDays)
+			if c.Rating != nil {
+				line += fmt.Sprintf("  %d/5", *c.Rating)
+			}
+			if c.PoorConnectivity {
+				line += "  ⚠️ poor connectivity"
+			}
+			sb.WriteString(line + "\n")
+			if c.Note != "" {
+				sb.WriteString(fmt.Sprintf("   %-14s 📝 %s\n", "", c.Note))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
 	// Current sensor section
 	if data.Current != nil {
 		sb.WriteString("🔋 Current Sensor\n")
@@ -379,6 +559,367 @@ func FormatSensorTable(sensors []SensorInfo, total int) string {
 	return sb.String()
 }
 
+// FormatSensorSites formats the application site history as a table
+func FormatSensorSites(sites []SensorSite) string {
+	if len(sites) == 0 {
+		return "No sensors found"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("┌──────────────┬─────────────────────┬───────────────┬────────┐\n")
+	sb.WriteString("│ Serial       │ Activation          │ Site          │ Reused │\n")
+	sb.WriteString("├──────────────┼─────────────────────┼───────────────┼────────┤\n")
+
+	reused := 0
+	for _, s := range sites {
+		site := s.Site
+		if site == "" {
+			site = "-"
+		}
+		mark := ""
+		if s.Reused {
+			mark = "yes"
+			reused++
+		}
+		sb.WriteString(fmt.Sprintf("│ %-12s │ %-19s │ %-13s │ %-6s │\n",
+			s.SerialNumber, s.Activation.Local().Format("2006-01-02 15:04"), site, mark))
+	}
+
+	sb.WriteString("└──────────────┴─────────────────────┴───────────────┴────────┘")
+
+	if reused > 0 {
+		sb.WriteString(fmt.Sprintf("\n⚠️  %d sensor(s) applied to the same site as the previous one", reused))
+	}
+
+	return sb.String()
+}
+
+// FormatMode formats the activity mode and its alert thresholds
+func FormatMode(m *ModeStatus) string {
+	var sb strings.Builder
+
+	switch m.Mode {
+	case "exercise":
+		sb.WriteString("🏃 Exercise mode")
+		if m.EndsAt != nil {
+			sb.WriteString(fmt.Sprintf(" until %s (%s left)",
+				m.EndsAt.Local().Format("15:04"), time.Until(*m.EndsAt).Round(time.Minute)))
+		}
+	default:
+		sb.WriteString("Normal mode")
+	}
+
+	fall := "falling rapidly"
+	if m.Thresholds.FallTrend >= 2 {
+		fall = "falling"
+	}
+	sb.WriteString(fmt.Sprintf("\n   Low alert below %d mg/dL (%.1f mmol/L), fall alert when %s",
+		m.Thresholds.LowMgDl, float64(m.Thresholds.LowMgDl)/18.0182, fall))
+
+	return sb.String()
+}
+
+// FormatConnection formats the upstream connection details
+func FormatConnection(c *ConnectionInfo) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("🔗 %s (%s, %s)\n", c.Source, c.PatientInitials, c.Country))
+	sb.WriteString(fmt.Sprintf("   Sensor:     %s\n", c.SensorBrand))
+	sb.WriteString(fmt.Sprintf("   Connected:  %s\n", c.ConnectedAt.Local().Format("2006-01-02 15:04")))
+	sb.WriteString(fmt.Sprintf("   Last fetch: %s\n", c.LastFetchAt.Local().Format("15:04:05")))
+	sb.WriteString(fmt.Sprintf("   Readings every %ds (history every %dm)\n",
+		c.CurrentIntervalSeconds, c.HistoricalIntervalSeconds/60))
+	sb.WriteString(fmt.Sprintf("   Delay: %.0fs last, %.0fs average", c.LastDelaySeconds, c.AverageDelaySeconds))
+
+	// Accounts following several patients: list their IDs for GLCMD_PATIENT_ID and --patient
+	if len(c.Patients) > 1 {
+		sb.WriteString("\n\n   Patients:")
+		for _, p := range c.Patients {
+			marker := " "
+			if p.PatientID == c.PatientID {
+				marker = "*"
+			}
+			stored := ""
+			if p.Stored {
+				stored = "  stored"
+			}
+			sb.WriteString(fmt.Sprintf("\n   %s %-6s %s%s", marker, p.Initials, p.PatientID, stored))
+		}
+	}
+
+	return sb.String()
+}
+
+// FormatUpstreamStatus formats the availability of LibreView and its outages
+func FormatUpstreamStatus(s *UpstreamStatus) string {
+	var sb strings.Builder
+
+	switch {
+	case s.Current != nil && s.Current.Cause == "auth":
+		sb.WriteString(fmt.Sprintf("🔴 LibreView rejects the credentials since %s (check your setup)\n",
+			s.Current.StartedAt.Local().Format("2006-01-02 15:04")))
+	case s.Current != nil:
+		sb.WriteString(fmt.Sprintf("🔴 LibreView down since %s (%s)\n",
+			s.Current.StartedAt.Local().Format("2006-01-02 15:04"), s.Current.Cause))
+	case s.Status == "degraded":
+		sb.WriteString(fmt.Sprintf("🟡 LibreView degraded (%d failed fetches)\n", s.ConsecutiveFailures))
+	default:
+		sb.WriteString("🟢 LibreView up\n")
+	}
+
+	uptime := make([]string, 0, len(s.Uptime))
+	for _, w := range s.Uptime {
+		uptime = append(uptime, fmt.Sprintf("%s %.2f%%", w.Window, w.Percent))
+	}
+	sb.WriteString("   Uptime: " + strings.Join(uptime, ", "))
+
+	if len(s.Outages) == 0 {
+		sb.WriteString("\n   No outages")
+		return sb.String()
+	}
+
+	sb.WriteString("\n\n   Outages:")
+	for _, o := range s.Outages {
+		duration := "ongoing"
+		if o.EndedAt != nil {
+			duration = o.EndedAt.Sub(o.StartedAt).Round(time.Minute).String()
+		}
+		sb.WriteString(fmt.Sprintf("\n   %s  %-9s  %-10s  %d failures",
+			o.StartedAt.Local().Format("2006-01-02 15:04"), o.Cause, duration, o.Failures))
+	}
+
+	return sb.String()
+}
+
+// FormatAlerts formats the alert history as a table
+func FormatAlerts(alerts []Alert, total int) string {
+	if len(alerts) == 0 {
+		return "No alerts found"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("┌───────┬──────────────────┬─────────┬────────────┬──────────┬──────────┐\n")
+	sb.WriteString("│ ID    │ Time             │ Type    │ Value      │ Mode     │ Acked    │\n")
+	sb.WriteString("├───────┼──────────────────┼─────────┼────────────┼──────────┼──────────┤\n")
+
+	for _, a := range alerts {
+		acked := "-"
+		if a.AcknowledgedAt != nil {
+			acked = a.AcknowledgedAt.Local().Format("15:04")
+		}
+		sb.WriteString(fmt.Sprintf("│ %-5d │ %-16s │ %-7s │ %4d mg/dL │ %-8s │ %-8s │\n",
+			a.ID, a.FiredAt.Local().Format("2006-01-02 15:04"), a.Type, a.ValueMgDl, a.Mode, acked))
+	}
+
+	sb.WriteString("└───────┴──────────────────┴─────────┴────────────┴──────────┴──────────┘")
+
+	if total > len(alerts) {
+		sb.WriteString(fmt.Sprintf("\nShowing %d of %d alerts", len(alerts), total))
+	}
+
+	return sb.String()
+}
+
+// FormatAlertWeekly formats weekly alert counts as a table
+func FormatAlertWeekly(weeks []AlertWeek) string {
+	var sb strings.Builder
+
+	sb.WriteString("┌────────────┬───────┬───────┬─────────┬───────┐\n")
+	sb.WriteString("│ Week of    │ Total │ Low   │ Falling │ Acked │\n")
+	sb.WriteString("├────────────┼───────┼───────┼─────────┼───────┤\n")
+
+	for _, w := range weeks {
+		sb.WriteString(fmt.Sprintf("│ %-10s │ %5d │ %5d │ %7d │ %5d │\n",
+			w.Start.Local().Format("2006-01-02"), w.Total, w.Low, w.Falling, w.Acknowledged))
+	}
+
+	sb.WriteString("└────────────┴───────┴───────┴─────────┴───────┘")
+
+	return sb.String()
+}
+
+// FormatViews formats saved views as a list, one view per line.
+// Queries vary in length, so views are not drawn as a table.
+func FormatViews(views []SavedView) string {
+	if len(views) == 0 {
+		return "No saved views"
+	}
+
+	width := 0
+	for _, v := range views {
+		width = max(width, len(v.Name))
+	}
+
+	var sb strings.Builder
+	for i, v := range views {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		period := v.Period
+		if period == "" {
+			period = "all"
+		}
+		query := v.Query
+		if query == "" {
+			query = "(all measurements)"
+		}
+		sb.WriteString(fmt.Sprintf("%-*s  %-5s  %-5s  %s", width, v.Name, v.Aggregation, period, query))
+		if v.Description != "" {
+			sb.WriteString(fmt.Sprintf("\n%-*s  %s", width, "", v.Description))
+		}
+	}
+
+	return sb.String()
+}
+
+// FormatTreatments formats insulin treatments as a table with totals
+func FormatTreatments(treatments []Treatment) string {
+	if len(treatments) == 0 {
+		return "No treatments found"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("┌──────────────────┬───────┬──────────┬──────────────┬─────────┐\n")
+	sb.WriteString("│ Time             │ Type  │ Insulin  │ Basal rate   │ Carbs   │\n")
+	sb.WriteString("├──────────────────┼───────┼──────────┼──────────────┼─────────┤\n")
+
+	var bolus, basal float64
+	for _, t := range treatments {
+		rate := "-"
+		if t.BasalRate != nil {
+			rate = fmt.Sprintf("%.2f U/h", *t.BasalRate)
+		}
+		carbs := "-"
+		if t.Carbs != nil {
+			carbs = fmt.Sprintf("%.0f g", *t.Carbs)
+		}
+		if t.Type == "basal" {
+			basal += t.Insulin
+		} else {
+			bolus += t.Insulin
+		}
+		sb.WriteString(fmt.Sprintf("│ %-16s │ %-5s │ %6.2f U │ %-12s │ %-7s │\n",
+			t.Timestamp.Local().Format("2006-01-02 15:04"), t.Type, t.Insulin, rate, carbs))
+	}
+
+	sb.WriteString("└──────────────────┴───────┴──────────┴──────────────┴─────────┘")
+	sb.WriteString(fmt.Sprintf("\nTotal: %.2f U bolus, %.2f U basal", bolus, basal))
+
+	return sb.String()
+}
+
+// FormatJobProgress formats a job's progress on one line, with a progress
+// bar once the number of items is known
+func FormatJobProgress(job *Job) string {
+	if job.Total <= 0 {
+		return fmt.Sprintf("%s: %s, %d done", job.Type, job.Status, job.Done)
+	}
+	percent := float64(job.Done) / float64(job.Total) * 100
+	return fmt.Sprintf("%s [%s] %3.0f%% %d/%d", job.Type, formatProgressBar(percent, 30), percent, job.Done, job.Total)
+}
+
+// FormatJob formats a background job with its progress and errors
+func FormatJob(job *Job) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Job %s (%s): %s\n", job.ID, job.Type, job.Status))
+	sb.WriteString(FormatJobProgress(job) + "\n")
+	if job.MaxAttempts > 1 {
+		sb.WriteString(fmt.Sprintf("Attempt %d of %d", job.Attempts, job.MaxAttempts))
+		if job.Status == "pending" && job.Attempts > 0 {
+			sb.WriteString(", next at " + job.RunAt.Local().Format("15:04:05"))
+		}
+		sb.WriteString("\n")
+	}
+	if job.Error != "" {
+		sb.WriteString("❌ " + job.Error + "\n")
+	}
+	for _, e := range job.Errors {
+		sb.WriteString("⚠️  " + e + "\n")
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// FormatJobs formats background jobs as a table
+func FormatJobs(jobs []Job, total int) string {
+	if len(jobs) == 0 {
+		return "No jobs found"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("┌──────────────────────────────────────┬──────────────────┬──────────────────┬───────────┬──────────┬──────────┐\n")
+	sb.WriteString("│ ID                                   │ Created          │ Type             │ Status    │ Attempts │ Duration │\n")
+	sb.WriteString("├──────────────────────────────────────┼──────────────────┼──────────────────┼───────────┼──────────┼──────────┤\n")
+
+	for _, j := range jobs {
+		duration := "-"
+		if j.StartedAt != nil && j.FinishedAt != nil {
+			duration = j.FinishedAt.Sub(*j.StartedAt).Round(time.Second).String()
+		}
+		sb.WriteString(fmt.Sprintf("│ %-36s │ %-16s │ %-16.16s │ %-9s │ %8s │ %8s │\n",
+			j.ID, j.CreatedAt.Local().Format("2006-01-02 15:04"), j.Type, j.Status,
+			fmt.Sprintf("%d/%d", j.Attempts, j.MaxAttempts), duration))
+	}
+
+	sb.WriteString("└──────────────────────────────────────┴──────────────────┴──────────────────┴───────────┴──────────┴──────────┘")
+
+	if total > len(jobs) {
+		sb.WriteString(fmt.Sprintf("\nShowing %d of %d jobs", len(jobs), total))
+	}
+
+	return sb.String()
+}
+
+// FormatJobSchedules formats the recurring jobs and their next run
+func FormatJobSchedules(schedules []JobSchedule) string {
+	if len(schedules) == 0 {
+		return "No scheduled jobs"
+	}
+
+	var sb strings.Builder
+	for _, s := range schedules {
+		sb.WriteString(fmt.Sprintf("%-16s %-12s next %s\n", s.Type, s.Spec, s.NextRun.Local().Format("2006-01-02 15:04")))
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// FormatTreatmentAnalysis formats the glucose response to meals and corrections
+func FormatTreatmentAnalysis(a *TreatmentAnalysis) string {
+	if len(a.Meals) == 0 && a.Corrections == nil {
+		return "No boluses with enough glucose data to analyze"
+	}
+
+	var sb strings.Builder
+
+	if len(a.Meals) > 0 {
+		sb.WriteString("┌──────────┬───────┬─────────┬─────────┬────────────┬──────────────┬────────────┐\n")
+		sb.WriteString("│ Meals    │ Count │ Carbs   │ Insulin │ Rise       │ Rise / 10 g  │ Peak       │\n")
+		sb.WriteString("├──────────┼───────┼─────────┼─────────┼────────────┼──────────────┼────────────┤\n")
+		for _, m := range a.Meals {
+			sb.WriteString(fmt.Sprintf("│ %-8s │ %5d │ %5.0f g │ %5.1f U │ %4.0f mg/dL │ %6.1f mg/dL │ %4.0f mg/dL │\n",
+				m.TimeOfDay, m.Count, m.AvgCarbs, m.AvgInsulin, m.AvgRiseMgDl, m.RisePer10gCarbs, m.AvgPeakMgDl))
+		}
+		sb.WriteString("└──────────┴───────┴─────────┴─────────┴────────────┴──────────────┴────────────┘\n")
+	}
+
+	if c := a.Corrections; c != nil {
+		sb.WriteString(fmt.Sprintf("Corrections: %d, %.1f U on average, -%.0f mg/dL (%.0f mg/dL per unit)\n",
+			c.Count, c.AvgInsulin, c.AvgDropMgDl, c.DropPerUnitMgDl))
+	}
+
+	for _, insight := range a.Insights {
+		sb.WriteString("💡 " + insight + "\n")
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
 // GMIPeriodResult holds GMI data for a single period
 type GMIPeriodResult struct {
 	Label        string   `json:"label"`
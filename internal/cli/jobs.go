@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"context"
+	"time"
+)
+
+// WaitForJob polls a background job every interval until it is finished or
+// ctx is done, calling progress (optional) with each snapshot.
+// Returns the finished job whatever its status; ctx.Err() when the context
+// expires first.
+func (c *Client) WaitForJob(ctx context.Context, id string, interval time.Duration, progress func(*Job)) (*Job, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		job, err := c.GetJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if progress != nil {
+			progress(job)
+		}
+		if job.Finished() {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForJob(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/jobs/job-1" {
+			http.NotFound(w, r)
+			return
+		}
+		// Pending, then running, then succeeded
+		switch n := polls.Add(1); n {
+		case 1:
+			fmt.Fprint(w, `{"data":{"id":"job-1","status":"pending"}}`)
+			return
+		case 2:
+			fmt.Fprintf(w, `{"data":{"id":"job-1","status":"running","done":%d,"total":4}}`, n)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":"job-1","status":"succeeded","done":4,"total":4,"result":{"imported":4}}}`)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	var seen []int
+	job, err := c.WaitForJob(context.Background(), "job-1", time.Millisecond, func(j *Job) {
+		seen = append(seen, j.Done)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Status != "succeeded" || string(job.Result) != `{"imported":4}` {
+		t.Errorf("expected the succeeded job with its result, got %+v", job)
+	}
+	if len(seen) != 3 || seen[0] != 0 || seen[1] != 2 || seen[2] != 4 {
+		t.Errorf("expected progress 0, 2, 4, got %v", seen)
+	}
+}
+
+func TestWaitForJob_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"job-1","status":"running"}}`)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := NewClient(server.URL)
+	if _, err := c.WaitForJob(ctx, "job-1", 10*time.Millisecond, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
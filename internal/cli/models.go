@@ -1,6 +1,9 @@
 package cli
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // GlucoseListResponse represents the API response for glucose list
 type GlucoseListResponse struct {
@@ -26,6 +29,7 @@ type StatisticsData struct {
 	Statistics   StatsDetails      `json:"statistics"`
 	Distribution StatsDistribution `json:"distribution"`
 	TimeInRange  *StatsTimeInRange `json:"timeInRange,omitempty"`
+	DataQuality  *DataQuality      `json:"dataQuality,omitempty"`
 }
 
 // StatsPeriod represents the time period for statistics
@@ -44,6 +48,7 @@ type StatsDetails struct {
 	Max            float64 `json:"max"`
 	MaxMgDl        int     `json:"maxMgDl"`
 	StdDev         float64 `json:"stdDev"`
+	CV             float64 `json:"cv"`
 	LowCount       int     `json:"lowCount"`
 	NormalCount    int     `json:"normalCount"`
 	HighCount      int     `json:"highCount"`
@@ -51,6 +56,16 @@ type StatsDetails struct {
 	TimeBelowRange float64 `json:"timeBelowRange"`
 	TimeAboveRange float64  `json:"timeAboveRange"`
 	GMI            *float64 `json:"gmi,omitempty"`
+	EstimatedA1c   *float64 `json:"estimatedA1c,omitempty"`
+	TargetBands    []StatsTargetBand `json:"targetBands,omitempty"`
+}
+
+// StatsTargetBand contains the time spent in a secondary target band
+type StatsTargetBand struct {
+	Name     string  `json:"name"`
+	LowMgDl  int     `json:"lowMgDl"`
+	HighMgDl int     `json:"highMgDl"`
+	Percent  float64 `json:"percent"`
 }
 
 // StatsDistribution contains distribution statistics
@@ -71,11 +86,74 @@ type StatsTimeInRange struct {
 	AboveRange     float64 `json:"aboveRange"`
 }
 
+// DataQuality summarizes how complete and reliable the data of a period is
+type DataQuality struct {
+	CapturePercent    float64 `json:"capturePercent"`
+	Gaps              int     `json:"gaps"`
+	Artifacts         int     `json:"artifacts"`
+	Score             int     `json:"score"`
+	LowConfidence     bool    `json:"lowConfidence"`
+	LowConfidenceDays int     `json:"lowConfidenceDays"`
+}
+
+// Histogram is the distribution of glucose readings in value buckets
+type Histogram struct {
+	Period     *StatsPeriod      `json:"period,omitempty"`
+	BucketMgDl int               `json:"bucketMgDl"`
+	Total      int64             `json:"total"`
+	Buckets    []HistogramBucket `json:"buckets"`
+}
+
+// HistogramBucket is the number of readings in [StartMgDl, EndMgDl)
+type HistogramBucket struct {
+	StartMgDl int     `json:"startMgDl"`
+	EndMgDl   int     `json:"endMgDl"`
+	Count     int64   `json:"count"`
+	Percent   float64 `json:"percent"`
+}
+
+// Percentiles is the glucose distribution by time of day
+type Percentiles struct {
+	Period        *StatsPeriod       `json:"period"`
+	Days          int                `json:"days"`
+	BucketMinutes int                `json:"bucketMinutes"`
+	Buckets       []PercentileBucket `json:"buckets"`
+}
+
+// PercentileBucket contains the glucose percentiles (mg/dL) of a time-of-day bucket
+type PercentileBucket struct {
+	Time  string  `json:"time"`
+	Count int     `json:"count"`
+	P5    float64 `json:"p5"`
+	P25   float64 `json:"p25"`
+	P50   float64 `json:"p50"`
+	P75   float64 `json:"p75"`
+	P95   float64 `json:"p95"`
+}
+
+// QualityReport contains the overall and per-day data quality
+type QualityReport struct {
+	Summary DataQuality  `json:"summary"`
+	Days    []QualityDay `json:"days"`
+}
+
+// QualityDay is the data quality of one local day
+type QualityDay struct {
+	Date           string  `json:"date"`
+	Readings       int     `json:"readings"`
+	CapturePercent float64 `json:"capturePercent"`
+	Gaps           int     `json:"gaps"`
+	Artifacts      int     `json:"artifacts"`
+	Score          int     `json:"score"`
+	LowConfidence  bool    `json:"lowConfidence"`
+}
+
 // GlucoseParams contains parameters for fetching glucose measurements
 type GlucoseParams struct {
 	Start *time.Time
 	End   *time.Time
 	Limit int
+	Query string // Filter expression, e.g. "value_mgdl > 180 AND hour in 0..6"
 }
 
 // SensorListResponse represents the API response for sensors list
@@ -91,6 +169,203 @@ type SensorParams struct {
 	Limit int
 }
 
+// SensorSiteResult is the result of recording the current sensor application site
+type SensorSiteResult struct {
+	Sensor       SensorInfo `json:"sensor"`
+	PreviousSite string     `json:"previousSite,omitempty"`
+	SiteReused   bool       `json:"siteReused"`
+	Warning      string     `json:"warning,omitempty"`
+}
+
+// SensorSite is one entry of the application site history
+type SensorSite struct {
+	SerialNumber string    `json:"serialNumber"`
+	Activation   time.Time `json:"activation"`
+	Site         string    `json:"site,omitempty"`
+	Reused       bool      `json:"reused"`
+}
+
+// AlertListResponse represents the API response for the alert history
+type AlertListResponse struct {
+	Data       []Alert        `json:"data"`
+	Pagination PaginationInfo `json:"pagination"`
+}
+
+// Alert is a fired alert from the alert history
+type Alert struct {
+	ID             uint       `json:"id"`
+	FiredAt        time.Time  `json:"firedAt"`
+	Type           string     `json:"type"`
+	ValueMgDl      int        `json:"valueMgDl"`
+	TrendArrow     *int       `json:"trendArrow,omitempty"`
+	ThresholdMgDl  int        `json:"thresholdMgDl"`
+	Mode           string     `json:"mode"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+}
+
+// AlertParams contains parameters for fetching the alert history
+type AlertParams struct {
+	Type           string // "" = all types
+	Unacknowledged bool
+	Limit          int
+}
+
+// AlertWeek counts the alerts fired during one week
+type AlertWeek struct {
+	Start        time.Time `json:"start"`
+	Total        int       `json:"total"`
+	Low          int       `json:"low"`
+	Falling      int       `json:"falling"`
+	Acknowledged int       `json:"acknowledged"`
+}
+
+// Treatment is an insulin delivery imported from a pump export
+type Treatment struct {
+	ID              uint      `json:"id"`
+	Timestamp       time.Time `json:"timestamp"`
+	Type            string    `json:"type"`
+	Source          string    `json:"source"`
+	Insulin         float64   `json:"insulin"`
+	BasalRate       *float64  `json:"basalRate,omitempty"`
+	DurationMinutes int       `json:"durationMinutes,omitempty"`
+	Carbs           *float64  `json:"carbs,omitempty"`
+}
+
+// TreatmentImportResult reports the outcome of a pump export import
+type TreatmentImportResult struct {
+	Imported   int `json:"imported"`
+	Duplicates int `json:"duplicates"`
+	Skipped    int `json:"skipped"`
+}
+
+// Job is a background job on glcore: an import, maintenance, a report or replication
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Schedule    string          `json:"schedule,omitempty"` // Empty when started on demand
+	Status      string          `json:"status"`             // pending, running, succeeded, failed or canceled
+	RunAt       time.Time       `json:"runAt"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	Done        int             `json:"done"`
+	Total       int             `json:"total"` // 0 = unknown
+	Errors      []string        `json:"errors,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	StartedAt   *time.Time      `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time      `json:"finishedAt,omitempty"`
+}
+
+// Finished reports whether the job succeeded, failed or was canceled
+func (j *Job) Finished() bool {
+	return j.Status == "succeeded" || j.Status == "failed" || j.Status == "canceled"
+}
+
+// JobListResponse represents the API response for the job list
+type JobListResponse struct {
+	Data       []Job          `json:"data"`
+	Pagination PaginationInfo `json:"pagination"`
+}
+
+// JobParams contains parameters for listing background jobs
+type JobParams struct {
+	Type   string // "" = all types
+	Status string // "" = all statuses
+	Limit  int
+}
+
+// JobSchedule is a recurring job and its next run
+type JobSchedule struct {
+	Type    string    `json:"type"`
+	Spec    string    `json:"spec"`
+	NextRun time.Time `json:"nextRun"`
+}
+
+// TreatmentAnalysis correlates boluses with the glucose that follows them
+type TreatmentAnalysis struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Meals []struct {
+		TimeOfDay       string  `json:"timeOfDay"`
+		Count           int     `json:"count"`
+		AvgCarbs        float64 `json:"avgCarbs"`
+		AvgInsulin      float64 `json:"avgInsulin"`
+		AvgRiseMgDl     float64 `json:"avgRiseMgDl"`
+		RisePer10gCarbs float64 `json:"risePer10gCarbs"`
+		AvgPeakMgDl     float64 `json:"avgPeakMgDl"`
+	} `json:"meals"`
+	Corrections *struct {
+		Count           int     `json:"count"`
+		AvgInsulin      float64 `json:"avgInsulin"`
+		AvgDropMgDl     float64 `json:"avgDropMgDl"`
+		DropPerUnitMgDl float64 `json:"dropPerUnitMgDl"`
+	} `json:"corrections,omitempty"`
+	Insights []string `json:"insights"`
+}
+
+// ConnectionInfo describes the upstream connection glucose data is fetched from
+type ConnectionInfo struct {
+	Source                    string    `json:"source"`
+	PatientID                 string    `json:"patientId"`
+	PatientInitials           string    `json:"patientInitials"`
+	Country                   string    `json:"country"`
+	SensorBrand               string    `json:"sensorBrand"`
+	SensorType                int       `json:"sensorType"`
+	ConnectedAt               time.Time `json:"connectedAt"`
+	LastFetchAt               time.Time `json:"lastFetchAt"`
+	CurrentIntervalSeconds    int       `json:"currentIntervalSeconds"`
+	HistoricalIntervalSeconds int       `json:"historicalIntervalSeconds"`
+	LastDelaySeconds          float64   `json:"lastDelaySeconds"`
+	AverageDelaySeconds       float64   `json:"averageDelaySeconds"`
+
+	Patients []ConnectionPatient `json:"patients"`
+}
+
+// ConnectionPatient is a patient followed by the LibreLinkUp account
+type ConnectionPatient struct {
+	PatientID string `json:"patientId"`
+	Initials  string `json:"initials"`
+	Stored    bool   `json:"stored"`
+}
+
+// UpstreamOutage is a period during which glcore could not fetch from LibreView
+type UpstreamOutage struct {
+	ID        uint       `json:"id"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+	Cause     string     `json:"cause"`
+	LastError string     `json:"lastError,omitempty"`
+	Failures  int        `json:"failures"`
+}
+
+// UptimeWindow is the share of a period during which LibreView was reachable
+type UptimeWindow struct {
+	Window  string  `json:"window"`
+	Percent float64 `json:"percent"`
+	Outages int     `json:"outages"`
+}
+
+// UpstreamStatus is the availability of LibreView reported by the API
+type UpstreamStatus struct {
+	Status              string            `json:"status"`
+	LastSuccessAt       *time.Time        `json:"lastSuccessAt,omitempty"`
+	ConsecutiveFailures int               `json:"consecutiveFailures"`
+	Current             *UpstreamOutage   `json:"current,omitempty"`
+	Uptime              []UptimeWindow    `json:"uptime"`
+	Outages             []*UpstreamOutage `json:"outages"`
+}
+
+// ModeStatus is the activity mode reported by the API
+type ModeStatus struct {
+	Mode       string     `json:"mode"`
+	EndsAt     *time.Time `json:"endsAt,omitempty"`
+	Thresholds struct {
+		LowMgDl   int `json:"lowMgDl"`
+		FallTrend int `json:"fallTrend"`
+	} `json:"thresholds"`
+}
+
 // SensorStatisticsResponse represents the API response for sensor statistics
 type SensorStatisticsResponse struct {
 	Data SensorStatisticsData `json:"data"`
@@ -98,9 +373,10 @@ type SensorStatisticsResponse struct {
 
 // SensorStatisticsData contains sensor statistics
 type SensorStatisticsData struct {
-	Period     *StatsPeriod       `json:"period,omitempty"`
-	Statistics SensorStatsDetails `json:"statistics"`
-	Current    *SensorInfo        `json:"current,omitempty"`
+	Period      *StatsPeriod       `json:"period,omitempty"`
+	EndedPeriod *StatsPeriod       `json:"endedPeriod,omitempty"`
+	Statistics  SensorStatsDetails `json:"statistics"`
+	Current     *SensorInfo        `json:"current,omitempty"`
 }
 
 // SensorStatsDetails contains detailed sensor lifecycle statistics
@@ -112,4 +388,108 @@ type SensorStatsDetails struct {
 	MaxDuration   float64 `json:"maxDuration"`
 	AvgExpected   float64 `json:"avgExpected"`
 	AvgDifference float64 `json:"avgDifference"`
+	RatedSensors  int      `json:"ratedSensors"`
+	AvgRating     *float64 `json:"avgRating,omitempty"`
+	Sensors       []SensorCapture `json:"sensors"`
+}
+
+// SensorCapture compares the readings of a sensor with those expected over its wear time
+type SensorCapture struct {
+	SerialNumber     string    `json:"serialNumber"`
+	Activation       time.Time `json:"activation"`
+	WearDays         float64   `json:"wearDays"`
+	Readings         int       `json:"readings"`
+	ExpectedReadings int       `json:"expectedReadings"`
+	CaptureRate      float64   `json:"captureRate"`
+	PoorConnectivity bool      `json:"poorConnectivity"`
+	Note             string    `json:"note,omitempty"`
+	Rating           *int      `json:"rating,omitempty"`
+}
+
+// ConfigChange represents LibreLink app settings changes pushed on the event stream
+type ConfigChange struct {
+	Device *struct {
+		AlarmsEnabled bool `json:"alarmsEnabled"`
+		LowLimit      int  `json:"lowLimit"`
+		HighLimit     int  `json:"highLimit"`
+	} `json:"device,omitempty"`
+	Targets *struct {
+		TargetLow  int `json:"targetLow"`
+		TargetHigh int `json:"targetHigh"`
+	} `json:"targets,omitempty"`
+}
+
+// MorningSummary represents an overnight glucose summary pushed on the event stream
+type MorningSummary struct {
+	Start            string          `json:"start"`
+	End              string          `json:"end"`
+	Count            int             `json:"count"`
+	Min              float64         `json:"min"`
+	MinMgDl          int             `json:"minMgDl"`
+	Max              float64         `json:"max"`
+	MaxMgDl          int             `json:"maxMgDl"`
+	TimeLowMinutes   int             `json:"timeLowMinutes"`
+	LowThresholdMgDl int             `json:"lowThresholdMgDl"`
+	Current          *GlucoseReading `json:"current,omitempty"`
+}
+
+// SavedView is a named glucose analysis stored by glcore
+type SavedView struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Query       string    `json:"query,omitempty"`
+	Period      string    `json:"period,omitempty"`
+	Aggregation string    `json:"aggregation"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// ViewRunResponse represents the result of running a saved view.
+// Pagination is only set for list views.
+type ViewRunResponse struct {
+	Data struct {
+		View         SavedView        `json:"view"`
+		Measurements []GlucoseReading `json:"measurements,omitempty"`
+		Statistics   *StatisticsData  `json:"statistics,omitempty"`
+	} `json:"data"`
+	Pagination *PaginationInfo `json:"pagination,omitempty"`
+}
+
+// StatusReport gathers what glcli status shows: the latest reading, the
+// current sensor and the statistics of the last 24 hours. Parts the server
+// could not provide are nil.
+type StatusReport struct {
+	Glucose   *GlucoseReading `json:"glucose"`
+	Sensor    *SensorInfo     `json:"sensor"`
+	Stats     *StatisticsData `json:"stats"`
+	Error     string          `json:"error,omitempty"` // Why the latest reading is missing
+	CheckedAt time.Time       `json:"checkedAt"`
+}
+
+// Preferences are the display preferences stored by glcore
+type Preferences struct {
+	Unit          string `json:"unit"` // "mmol" or "mgdl"
+	Timezone      string `json:"timezone,omitempty"`
+	Emoji         bool   `json:"emoji"`
+	TightLowMgDl  int    `json:"tightLowMgDl"`
+	TightHighMgDl int    `json:"tightHighMgDl"`
+	ChartRange    string `json:"chartRange"`
+}
+
+// DefaultPreferences returns the preferences used when glcore has none
+// (servers predating display preferences)
+func DefaultPreferences() *Preferences {
+	return &Preferences{Unit: "mmol", Emoji: true, TightLowMgDl: 70, TightHighMgDl: 140, ChartRange: "24h"}
+}
+
+// location returns the preferred time zone, or the local one when unset or unknown
+func (p *Preferences) location() *time.Location {
+	if p.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
 }
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultProfile is the profile used when none is selected. It holds the
+// settings written before the first [section] of the config file.
+const DefaultProfile = "default"
+
+// Profile holds the connection settings of a glcore server.
+type Profile struct {
+	Name   string
+	APIURL string // Empty = not set in the config file
+	Token  string // Empty = not set in the config file
+}
+
+// DefaultConfigPath returns the glcli config file:
+// $XDG_CONFIG_HOME/glcli/config on Linux (~/.config/glcli/config by default).
+func DefaultConfigPath() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "glcli", "config"), nil
+}
+
+// LoadProfile reads the named profile from the config file at path.
+// The file has key = value lines (api_url, token) grouped in [name] sections;
+// lines before the first section belong to the default profile.
+// A missing file or default profile is not an error, an unknown named
+// profile is.
+func LoadProfile(path, name string) (Profile, error) {
+	profile := Profile{Name: name}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && name == DefaultProfile {
+		return profile, nil
+	}
+	if err != nil {
+		return profile, fmt.Errorf("profile %q: %w", name, err)
+	}
+	defer f.Close()
+
+	section := DefaultProfile
+	found := name == DefaultProfile
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return profile, fmt.Errorf("%s:%d: expected [profile]", path, line)
+			}
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			if section == name {
+				found = true
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return profile, fmt.Errorf("%s:%d: expected key = value", path, line)
+		}
+		if section != name {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		switch key {
+		case "api_url":
+			profile.APIURL = strings.TrimRight(value, "/")
+		case "token":
+			profile.Token = value
+		default:
+			return profile, fmt.Errorf("%s:%d: unknown key %q (expected api_url or token)", path, line, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return profile, err
+	}
+
+	if !found {
+		return profile, fmt.Errorf("profile %q not found in %s", name, path)
+	}
+	return profile, nil
+}
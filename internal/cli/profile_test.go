@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := writeConfig(t, `# glcli profiles
+api_url = http://localhost:8080
+
+[home]
+api_url = https://glcore.example.com/
+token = home-token
+`)
+
+	p, err := LoadProfile(path, DefaultProfile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.APIURL != "http://localhost:8080" || p.Token != "" {
+		t.Errorf("unexpected default profile: %+v", p)
+	}
+
+	p, err = LoadProfile(path, "home")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Name != "home" || p.APIURL != "https://glcore.example.com" || p.Token != "home-token" {
+		t.Errorf("unexpected home profile: %+v", p)
+	}
+
+	if _, err := LoadProfile(path, "work"); err == nil {
+		t.Error("expected error for an unknown profile")
+	}
+}
+
+func TestLoadProfile_MissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	if p, err := LoadProfile(path, DefaultProfile); err != nil || p.APIURL != "" || p.Token != "" {
+		t.Errorf("expected an empty default profile, got %+v, %v", p, err)
+	}
+	if _, err := LoadProfile(path, "home"); err == nil {
+		t.Error("expected error for a named profile without config file")
+	}
+}
+
+func TestLoadProfile_Invalid(t *testing.T) {
+	for _, content := range []string{
+		"[home\ntoken = x\n",
+		"token\n",
+		"[home]\npassword = x\n",
+	} {
+		if _, err := LoadProfile(writeConfig(t, content), "home"); err == nil {
+			t.Errorf("%q: expected error", content)
+		}
+	}
+}
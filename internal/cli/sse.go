@@ -5,7 +5,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
 )
 
 // SSEEvent represents a parsed SSE event
@@ -46,18 +49,28 @@ func (c *Client) streamEvents(ctx context.Context, types []string, events chan<-
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
+	if c.config.PatientID != "" {
+		query := req.URL.Query()
+		query.Set("patientId", c.config.PatientID)
+		req.URL.RawQuery = query.Encode()
+	}
 	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(glclient.APIVersionHeader, strconv.Itoa(glclient.SchemaVersion))
+	c.setAuthorization(req)
 
 	// Use a client without timeout for streaming
 	streamClient := &http.Client{} // No timeout for SSE
 	resp, err := streamClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("cannot connect to glcore at %s: %w", c.baseURL, err)
+		return classifyError(c.baseURL, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return c.newAuthError(resp)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("SSE endpoint returned status %d", resp.StatusCode)
+		return fmt.Errorf("SSE endpoint: %w", newHTTPError(resp))
 	}
 
 	// Read SSE events
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// TmuxFadeAfter is the default age after which the tmux status starts fading
+// to grey.
+const TmuxFadeAfter = 5 * time.Minute
+
+// tmux status colours (24-bit, tmux 3.0 or later)
+var (
+	tmuxNormal = [3]int{0x5f, 0xd7, 0x5f}
+	tmuxHigh   = [3]int{0xff, 0xaf, 0x00}
+	tmuxLow    = [3]int{0xff, 0x5f, 0x5f}
+	tmuxGrey   = [3]int{0x80, 0x80, 0x80}
+)
+
+// FormatTmux formats a status report as a tmux status-right snippet, e.g.
+// "#[fg=#5fd75f]6.2 ➡️ #[fg=#808080]2m#[default]". The reading is coloured by
+// status and fades linearly to grey between fadeAfter and staleAfter.
+// prefs selects the unit (nil = defaults).
+func FormatTmux(r *StatusReport, prefs *Preferences, fadeAfter, staleAfter time.Duration) string {
+	if prefs == nil {
+		prefs = DefaultPreferences()
+	}
+	if r.Glucose == nil {
+		return tmuxStyle(tmuxGrey) + "--#[default]"
+	}
+
+	g := r.Glucose
+	color := tmuxNormal
+	switch {
+	case g.IsLow:
+		color = tmuxLow
+	case g.IsHigh:
+		color = tmuxHigh
+	}
+
+	age := r.CheckedAt.Sub(g.Timestamp)
+	color = fadeColor(color, tmuxGrey, age, fadeAfter, staleAfter)
+
+	return tmuxStyle(color) + statusValue(g, prefs) + " " + tmuxStyle(tmuxGrey) + formatAge(age) + "#[default]"
+}
+
+// fadeColor blends from into to as age goes from fadeAfter to staleAfter.
+func fadeColor(from, to [3]int, age, fadeAfter, staleAfter time.Duration) [3]int {
+	switch {
+	case age <= fadeAfter:
+		return from
+	case age >= staleAfter:
+		return to
+	}
+
+	ratio := float64(age-fadeAfter) / float64(staleAfter-fadeAfter)
+	var blended [3]int
+	for i := range blended {
+		blended[i] = from[i] + int(math.Round(float64(to[i]-from[i])*ratio))
+	}
+	return blended
+}
+
+// tmuxStyle returns the tmux style setting the foreground colour.
+func tmuxStyle(c [3]int) string {
+	return fmt.Sprintf("#[fg=#%02x%02x%02x]", c[0], c[1], c[2])
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTmux(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	arrow := 3
+	report := &StatusReport{
+		Glucose:   &GlucoseReading{Value: 6.2, ValueInMgPerDl: 112, TrendArrow: &arrow, Timestamp: now.Add(-2 * time.Minute)},
+		CheckedAt: now,
+	}
+	prefs := &Preferences{Unit: "mmol", Timezone: "UTC"}
+
+	tests := []struct {
+		name    string
+		checked time.Duration // after now
+		low     bool
+		want    string
+	}{
+		{"fresh", 0, false, "#[fg=#5fd75f]6.2 ➡️ #[fg=#808080]2m#[default]"},
+		{"fresh low", 0, true, "#[fg=#ff5f5f]6.2 ➡️ #[fg=#808080]2m#[default]"},
+		{"fading", 8 * time.Minute, false, "#[fg=#70ab70]6.2 ➡️ #[fg=#808080]10m#[default]"},
+		{"stale", 20 * time.Minute, false, "#[fg=#808080]6.2 ➡️ #[fg=#808080]22m#[default]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report.CheckedAt = now.Add(tt.checked)
+			report.Glucose.IsLow = tt.low
+			if got := FormatTmux(report, prefs, TmuxFadeAfter, StatusStaleAfter); got != tt.want {
+				t.Errorf("FormatTmux() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Unreachable server
+	if got := FormatTmux(&StatusReport{CheckedAt: now}, prefs, TmuxFadeAfter, StatusStaleAfter); got != "#[fg=#808080]--#[default]" {
+		t.Errorf("unexpected output without a reading: %q", got)
+	}
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GlucoseCondition is a threshold condition on glucose readings, in mg/dL.
+// A nil bound is ignored. When both bounds are set, either one satisfies the condition.
+type GlucoseCondition struct {
+	BelowMgDl *float64
+	AboveMgDl *float64
+}
+
+// Met reports whether the reading satisfies the condition.
+func (c GlucoseCondition) Met(r *GlucoseReading) bool {
+	value := float64(r.ValueInMgPerDl)
+	if c.BelowMgDl != nil && value < *c.BelowMgDl {
+		return true
+	}
+	if c.AboveMgDl != nil && value > *c.AboveMgDl {
+		return true
+	}
+	return false
+}
+
+// String returns a human-readable description of the condition.
+func (c GlucoseCondition) String() string {
+	var parts []string
+	if c.BelowMgDl != nil {
+		parts = append(parts, fmt.Sprintf("below %.0f mg/dL", *c.BelowMgDl))
+	}
+	if c.AboveMgDl != nil {
+		parts = append(parts, fmt.Sprintf("above %.0f mg/dL", *c.AboveMgDl))
+	}
+	return strings.Join(parts, " or ")
+}
+
+// WaitForGlucose blocks until a glucose reading satisfies cond or ctx is done.
+//
+// The latest reading is checked first. New readings are then received through
+// the SSE stream; if streaming is unavailable or the stream drops, it falls back
+// to polling the latest reading every pollInterval.
+// Returns ctx.Err() when the context expires before the condition is met.
+func (c *Client) WaitForGlucose(ctx context.Context, cond GlucoseCondition, pollInterval time.Duration) (*GlucoseReading, error) {
+	reading, err := c.GetLatestGlucose(ctx)
+	if err != nil && !errors.Is(err, ErrNoReadings) {
+		return nil, err
+	}
+	if reading != nil && cond.Met(reading) {
+		return reading, nil
+	}
+
+	// Cancel the stream as soon as we return
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, errs := c.Stream(streamCtx, []string{"glucose"})
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			var r GlucoseReading
+			if err := json.Unmarshal(event.Data, &r); err != nil {
+				continue
+			}
+			if cond.Met(&r) {
+				return &r, nil
+			}
+		case <-errs:
+			// Stream unavailable or dropped: fall back to polling
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return c.pollForGlucose(ctx, cond, pollInterval)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// pollForGlucose polls the latest reading until it satisfies cond or ctx is done.
+func (c *Client) pollForGlucose(ctx context.Context, cond GlucoseCondition, pollInterval time.Duration) (*GlucoseReading, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+			reading, err := c.GetLatestGlucose(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				return nil, err
+			}
+			if cond.Met(reading) {
+				return reading, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestGlucoseCondition_Met(t *testing.T) {
+	tests := []struct {
+		name  string
+		cond  GlucoseCondition
+		value int
+		want  bool
+	}{
+		{"below met", GlucoseCondition{BelowMgDl: floatPtr(70)}, 65, true},
+		{"below not met", GlucoseCondition{BelowMgDl: floatPtr(70)}, 70, false},
+		{"above met", GlucoseCondition{AboveMgDl: floatPtr(180)}, 200, true},
+		{"above not met", GlucoseCondition{AboveMgDl: floatPtr(180)}, 120, false},
+		{"either bound", GlucoseCondition{BelowMgDl: floatPtr(70), AboveMgDl: floatPtr(250)}, 260, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.cond.Met(&GlucoseReading{ValueInMgPerDl: tt.value})
+			if got != tt.want {
+				t.Errorf("Met(%d) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForGlucose_AlreadyMet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"valueInMgPerDl":60}}`)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	reading, err := c.WaitForGlucose(context.Background(), GlucoseCondition{BelowMgDl: floatPtr(70)}, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reading.ValueInMgPerDl != 60 {
+		t.Errorf("expected 60 mg/dL, got %d", reading.ValueInMgPerDl)
+	}
+}
+
+func TestWaitForGlucose_FromStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/glucose/latest":
+			fmt.Fprint(w, `{"data":{"valueInMgPerDl":120}}`)
+		case "/v1/stream":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: glucose\ndata: {\"valueInMgPerDl\":100}\n\n")
+			fmt.Fprint(w, "event: glucose\ndata: {\"valueInMgPerDl\":65}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	reading, err := c.WaitForGlucose(context.Background(), GlucoseCondition{BelowMgDl: floatPtr(70)}, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reading.ValueInMgPerDl != 65 {
+		t.Errorf("expected 65 mg/dL, got %d", reading.ValueInMgPerDl)
+	}
+}
+
+func TestWaitForGlucose_FallbackToPolling(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/glucose/latest":
+			if calls.Add(1) < 3 {
+				fmt.Fprint(w, `{"data":{"valueInMgPerDl":120}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":{"valueInMgPerDl":200}}`)
+		case "/v1/stream":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	reading, err := c.WaitForGlucose(context.Background(), GlucoseCondition{AboveMgDl: floatPtr(180)}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reading.ValueInMgPerDl != 200 {
+		t.Errorf("expected 200 mg/dL, got %d", reading.ValueInMgPerDl)
+	}
+}
+
+func TestWaitForGlucose_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/glucose/latest":
+			fmt.Fprint(w, `{"data":{"valueInMgPerDl":120}}`)
+		case "/v1/stream":
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := NewClient(server.URL)
+	_, err := c.WaitForGlucose(ctx, GlucoseCondition{BelowMgDl: floatPtr(70)}, time.Second)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StatusStaleAfter is the age after which the latest reading is shown as stale
+const StatusStaleAfter = 15 * time.Minute
+
+// FormatStatusLine formats a status report on a single line for status bars,
+// e.g. "🟢 6.2 ➡️ 2m ago · sensor 4.2d".
+// prefs selects the unit, time zone and emoji (nil = defaults).
+func FormatStatusLine(r *StatusReport, prefs *Preferences) string {
+	if prefs == nil {
+		prefs = DefaultPreferences()
+	}
+	if r.Glucose == nil {
+		return "glcore unavailable"
+	}
+
+	parts := []string{statusTitle(r, prefs)}
+	parts = append(parts, formatAge(r.CheckedAt.Sub(r.Glucose.Timestamp))+" ago")
+	if r.Sensor != nil && r.Sensor.DaysRemaining != nil {
+		parts = append(parts, fmt.Sprintf("sensor %.1fd", *r.Sensor.DaysRemaining))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// FormatXbar formats a status report in the xbar/SwiftBar plugin format: the
+// first line is shown in the menu bar, the lines after "---" in the dropdown.
+// statusURL is linked from the dropdown (empty = no link).
+func FormatXbar(r *StatusReport, prefs *Preferences, statusURL string) string {
+	if prefs == nil {
+		prefs = DefaultPreferences()
+	}
+	loc := prefs.location()
+	var sb strings.Builder
+
+	if r.Glucose == nil {
+		sb.WriteString("🩸 --\n---\n")
+		if r.Error != "" {
+			sb.WriteString(xbarItem("⚠️ "+r.Error, "color=red"))
+		}
+	} else {
+		g := r.Glucose
+		sb.WriteString(statusTitle(r, prefs) + "\n---\n")
+
+		trend := TrendArrowText(g.TrendArrow)
+		value := formatReadingValue(prefs, g.Value, g.ValueInMgPerDl)
+		if trend != "" {
+			value += " " + trend
+		}
+		sb.WriteString(xbarItem(value, ""))
+		sb.WriteString(xbarItem(formatStatus(g.IsLow, g.IsHigh), ""))
+
+		age := r.CheckedAt.Sub(g.Timestamp)
+		reading := fmt.Sprintf("Reading at %s (%s ago)", g.Timestamp.In(loc).Format("15:04"), formatAge(age))
+		if age >= StatusStaleAfter {
+			sb.WriteString(xbarItem("⚠️ "+reading, "color=orange"))
+		} else {
+			sb.WriteString(xbarItem(reading, ""))
+		}
+	}
+
+	if s := r.Stats; s != nil && s.Statistics.Count > 0 {
+		sb.WriteString("---\n")
+		sb.WriteString(xbarItem("Last 24 hours", ""))
+		sb.WriteString(xbarItem("Average: "+formatReadingValue(prefs, s.Statistics.Average, int(s.Statistics.AverageMgDl+0.5)), ""))
+		if s.TimeInRange != nil {
+			sb.WriteString(xbarItem(fmt.Sprintf("In range: %.0f%% (below %.0f%%, above %.0f%%)",
+				s.TimeInRange.InRange, s.TimeInRange.BelowRange, s.TimeInRange.AboveRange), ""))
+		}
+		sb.WriteString(xbarItem(fmt.Sprintf("CV: %.0f%%", s.Statistics.CV), ""))
+	}
+
+	if s := r.Sensor; s != nil {
+		sb.WriteString("---\n")
+		sb.WriteString(xbarItem("Sensor "+s.SerialNumber, ""))
+		if s.DaysRemaining != nil {
+			sb.WriteString(xbarItem(fmt.Sprintf("%.1f days remaining (expires %s)", *s.DaysRemaining, formatDateTime(s.ExpiresAt)), ""))
+		} else {
+			sb.WriteString(xbarItem(fmt.Sprintf("%s, %.1f days", s.Status, s.DaysElapsed), ""))
+		}
+		if s.ApplicationSite != "" {
+			sb.WriteString(xbarItem("Site: "+s.ApplicationSite, ""))
+		}
+	}
+
+	sb.WriteString("---\n")
+	sb.WriteString(xbarItem("Updated "+r.CheckedAt.In(loc).Format("15:04:05"), ""))
+	if statusURL != "" {
+		sb.WriteString(xbarItem("Open status page", "href="+statusURL))
+	}
+	sb.WriteString(xbarItem("Refresh", "refresh=true"))
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// statusTitle returns the status emoji, the value and the trend arrow of the
+// latest reading, with a warning when it is stale.
+func statusTitle(r *StatusReport, prefs *Preferences) string {
+	g := r.Glucose
+	emoji, _, _ := strings.Cut(formatStatus(g.IsLow, g.IsHigh), " ")
+	if r.CheckedAt.Sub(g.Timestamp) >= StatusStaleAfter {
+		emoji = "⚠️"
+	}
+
+	return emoji + " " + statusValue(g, prefs)
+}
+
+// statusValue returns the value in the preferred unit and the trend arrow of
+// a reading, e.g. "6.2 ➡️".
+func statusValue(g *GlucoseReading, prefs *Preferences) string {
+	value := fmt.Sprintf("%.1f", g.Value)
+	if prefs.Unit == "mgdl" {
+		value = fmt.Sprintf("%d", g.ValueInMgPerDl)
+	}
+	if arrow, _, _ := strings.Cut(TrendArrowText(g.TrendArrow), " "); arrow != "" {
+		value += " " + arrow
+	}
+	return value
+}
+
+// xbarItem formats a dropdown line with its parameters. A "|" in the text
+// would start the parameters, so it is replaced.
+func xbarItem(text, params string) string {
+	text = strings.ReplaceAll(text, "|", "/")
+	if params == "" {
+		return text + "\n"
+	}
+	return text + " | " + params + "\n"
+}
+
+// formatAge formats the age of a reading, e.g. "45s", "3m" or "2h".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", max(int(d.Seconds()), 0))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatXbar(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	arrow := 3
+	report := &StatusReport{
+		Glucose: &GlucoseReading{Value: 6.2, ValueInMgPerDl: 112, TrendArrow: &arrow, Timestamp: now.Add(-2 * time.Minute)},
+		Sensor:  &SensorInfo{SerialNumber: "3MH001", ExpiresAt: "2026-03-05T10:00:00Z", DaysRemaining: floatPtr(4.0), Status: "running"},
+		Stats: &StatisticsData{
+			Statistics:  StatsDetails{Count: 96, Average: 7.1, AverageMgDl: 128, CV: 31},
+			TimeInRange: &StatsTimeInRange{InRange: 78, BelowRange: 2, AboveRange: 20},
+		},
+		CheckedAt: now,
+	}
+	prefs := &Preferences{Unit: "mmol", Timezone: "UTC", Emoji: true}
+
+	out := FormatXbar(report, prefs, "http://localhost:8080/status")
+	lines := strings.Split(out, "\n")
+	if lines[0] != "🟢 6.2 ➡️" || lines[1] != "---" {
+		t.Errorf("unexpected menu bar line: %q", lines[:2])
+	}
+	for _, want := range []string{
+		"6.2 mmol/L (112 mg/dL) ➡️ Stable",
+		"Reading at 10:28 (2m ago)",
+		"In range: 78% (below 2%, above 20%)",
+		"Sensor 3MH001",
+		"Open status page | href=http://localhost:8080/status",
+		"Refresh | refresh=true",
+	} {
+		if !strings.Contains(out, want+"\n") && !strings.HasSuffix(out, want) {
+			t.Errorf("expected line %q in:\n%s", want, out)
+		}
+	}
+
+	// Stale reading
+	report.CheckedAt = now.Add(20 * time.Minute)
+	if title := strings.SplitN(FormatXbar(report, prefs, ""), "\n", 2)[0]; title != "⚠️ 6.2 ➡️" {
+		t.Errorf("expected a stale warning in the menu bar, got %q", title)
+	}
+
+	// Unreachable server: still valid plugin output
+	out = FormatXbar(&StatusReport{Error: "connection refused | retry", CheckedAt: now}, prefs, "")
+	if !strings.HasPrefix(out, "🩸 --\n---\n⚠️ connection refused / retry | color=red\n") {
+		t.Errorf("unexpected output without a reading:\n%s", out)
+	}
+}
+
+func TestFormatStatusLine(t *testing.T) {
+	now := time.Now()
+	report := &StatusReport{
+		Glucose:   &GlucoseReading{Value: 3.6, ValueInMgPerDl: 65, IsLow: true, Timestamp: now.Add(-5 * time.Minute)},
+		Sensor:    &SensorInfo{DaysRemaining: floatPtr(4.25)},
+		CheckedAt: now,
+	}
+
+	got := FormatStatusLine(report, &Preferences{Unit: "mgdl"})
+	if got != "🟡 65 · 5m ago · sensor 4.2d" {
+		t.Errorf("FormatStatusLine() = %q", got)
+	}
+}
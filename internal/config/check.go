@@ -0,0 +1,184 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// knownVariables lists the GLCMD_* variables read by glcore and glcli, which
+// often share an environment.
+var knownVariables = []string{
+	"GLCMD_EMAIL", "GLCMD_PASSWORD", "GLCMD_SECONDARY_EMAIL", "GLCMD_SECONDARY_PASSWORD",
+	"GLCMD_PATIENT_ID", "GLCMD_MULTI_PATIENT", "GLCMD_ACCEPT_TERMS",
+	"GLCMD_API_PORT", "GLCMD_HEALTH_PORT", "GLCMD_ADMIN_TOKEN", "GLCMD_API_TOKENS", "GLCMD_ATTACHMENTS_DIR",
+	"GLCMD_ENV_FILE", "GLCMD_LOW_MEM", "GLCMD_STRICT_CONFIG",
+	"GLCMD_API_URL", "GLCMD_API_TOKEN", "GLCMD_PROFILE",
+	"GLCMD_LOG_FORMAT", "GLCMD_LOG_LEVEL", "GLCMD_LOG_SAMPLING",
+	"GLCMD_DB_TYPE", "GLCMD_DB_PATH", "GLCMD_DB_SECURE_FILES", "GLCMD_DB_MAX_OPEN_CONNS",
+	"GLCMD_DB_MAX_IDLE_CONNS", "GLCMD_DB_LOG_LEVEL", "GLCMD_DB_BACKEND",
+	"GLCMD_DB_HOST", "GLCMD_DB_PORT", "GLCMD_DB_NAME", "GLCMD_DB_USER", "GLCMD_DB_PASSWORD", "GLCMD_DB_SSL_MODE",
+	"GLCMD_SYNC_TOKEN", "GLCMD_SYNC_PRIMARY_URL", "GLCMD_SYNC_INTERVAL", "GLCMD_SYNC_DAYS",
+	"GLCMD_SENSOR_GRACE_PERIOD", "GLCMD_TARGET_BANDS",
+	"GLCMD_MORNING_SUMMARY_TIME", "GLCMD_MORNING_SUMMARY_NIGHT",
+	"GLCMD_HEARTBEAT_URL", "GLCMD_NIGHTSCOUT_URL", "GLCMD_NIGHTSCOUT_API_SECRET",
+	"GLCMD_PLUGINS", "GLCMD_PLUGIN_TIMEOUT", "GLCMD_PLUGIN_CONCURRENCY",
+	"GLCMD_ALERT_RULES", "GLCMD_ALERT_HIGH_MGDL", "GLCMD_ALERT_STALE_AFTER", "GLCMD_ALERT_SENSOR_REMINDERS",
+	"GLCMD_ALERT_WEBHOOK_URL", "GLCMD_ALERT_SMTP_HOST", "GLCMD_ALERT_SMTP_PORT", "GLCMD_ALERT_SMTP_USERNAME",
+	"GLCMD_ALERT_SMTP_PASSWORD", "GLCMD_ALERT_EMAIL_FROM", "GLCMD_ALERT_EMAIL_TO",
+	"GLCMD_ALERT_TELEGRAM_TOKEN", "GLCMD_ALERT_TELEGRAM_CHAT_ID", "GLCMD_ALERT_TELEGRAM_COMMANDS", "GLCMD_ALERT_PUSHOVER_TOKEN", "GLCMD_ALERT_PUSHOVER_USER",
+	"GLCMD_ALERT_SOUND", "GLCMD_ALERT_ESCALATE_AFTER", "GLCMD_ALERT_ESCALATION_TELEGRAM_CHAT_ID", "GLCMD_ALERT_ESCALATION_WEBHOOK_URL",
+	"GLCMD_JOB_WORKERS", "GLCMD_JOB_RETENTION",
+	"GLCMD_RETENTION_DOWNSAMPLE_DAYS", "GLCMD_RETENTION_RAW_DAYS",
+	"GLCMD_FAULT_INJECT",
+	"GLCMD_EVENT_TYPE", // Set by glcore for plugins
+}
+
+// secretVariables can also be read from a file named by <name>_FILE (see secretEnv).
+var secretVariables = []string{
+	"GLCMD_PASSWORD", "GLCMD_SECONDARY_PASSWORD", "GLCMD_DB_PASSWORD", "GLCMD_SYNC_TOKEN",
+	"GLCMD_ADMIN_TOKEN", "GLCMD_API_TOKENS", "GLCMD_HEARTBEAT_URL", "GLCMD_NIGHTSCOUT_API_SECRET",
+	"GLCMD_ALERT_WEBHOOK_URL", "GLCMD_ALERT_SMTP_PASSWORD", "GLCMD_ALERT_TELEGRAM_TOKEN",
+	"GLCMD_ALERT_PUSHOVER_TOKEN", "GLCMD_ALERT_PUSHOVER_USER", "GLCMD_ALERT_ESCALATION_WEBHOOK_URL",
+}
+
+// removedVariables are no longer read, with the version that removed them.
+var removedVariables = map[string]string{
+	"GLCMD_DISPLAY_INTERVAL": "v0.5.0",
+	"GLCMD_ENABLE_EMOJIS":    "v0.5.0",
+}
+
+// valueChecks validates the variables whose invalid values are silently
+// replaced by a default rather than rejected at startup.
+var valueChecks = map[string]func(string) error{
+	"GLCMD_DB_TYPE":           oneOf("sqlite", "postgres"),
+	"GLCMD_DB_LOG_LEVEL":      oneOf("silent", "error", "warn", "info"),
+	"GLCMD_DB_SSL_MODE":       oneOf("disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+	"GLCMD_DB_PORT":           isInteger,
+	"GLCMD_DB_MAX_OPEN_CONNS": isInteger,
+	"GLCMD_DB_MAX_IDLE_CONNS": isInteger,
+	"GLCMD_LOG_FORMAT":        oneOf("text", "json"),
+	"GLCMD_LOG_LEVEL": func(value string) error {
+		return oneOf("debug", "info", "warn", "warning", "error")(strings.ToLower(value))
+	},
+}
+
+// maxSuggestionDistance is the edit distance under which an unknown variable
+// is reported as a likely typo of a known one.
+const maxSuggestionDistance = 2
+
+// CheckEnvironment returns the problems of the GLCMD_* variables of environ
+// (KEY=VALUE entries, as returned by os.Environ): unknown or removed
+// variables, values replaced by a default, and values with surrounding
+// quotes or spaces. Sorted by variable name.
+func CheckEnvironment(environ []string) []string {
+	var problems []string
+	for _, entry := range environ {
+		name, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, "GLCMD_") {
+			continue
+		}
+
+		if problem := checkVariable(name, value); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+// checkVariable returns the problem of a GLCMD_* variable, empty if none.
+func checkVariable(name, value string) string {
+	if version, ok := removedVariables[name]; ok {
+		return fmt.Sprintf("%s was removed in %s and is ignored", name, version)
+	}
+	base, isFile := strings.CutSuffix(name, "_FILE")
+	if !slices.Contains(knownVariables, name) && !(isFile && slices.Contains(secretVariables, base)) {
+		if suggestion := suggestVariable(name); suggestion != "" {
+			return fmt.Sprintf("unknown variable %s (did you mean %s?)", name, suggestion)
+		}
+		return fmt.Sprintf("unknown variable %s", name)
+	}
+
+	if value != strings.TrimSpace(value) {
+		return fmt.Sprintf("%s has leading or trailing spaces", name)
+	}
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return fmt.Sprintf("%s is quoted: the quotes are part of the value", name)
+	}
+	if check, ok := valueChecks[name]; ok && value != "" {
+		if err := check(value); err != nil {
+			return fmt.Sprintf("invalid %s: %s (%v), the default is used", name, value, err)
+		}
+	}
+	return ""
+}
+
+// suggestVariable returns the known variable closest to name, empty if none
+// is close enough to be a typo.
+func suggestVariable(name string) string {
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, known := range knownVariables {
+		if d := editDistance(name, known); d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// oneOf returns a check accepting the given values only.
+func oneOf(values ...string) func(string) error {
+	return func(value string) error {
+		if !slices.Contains(values, value) {
+			return fmt.Errorf("must be %s", strings.Join(values, ", "))
+		}
+		return nil
+	}
+}
+
+// isInteger checks that value is an integer.
+func isInteger(value string) error {
+	if _, err := strconv.Atoi(value); err != nil {
+		return fmt.Errorf("must be an integer")
+	}
+	return nil
+}
+
+// strictConfig returns true if GLCMD_STRICT_CONFIG asks to fail on
+// configuration problems rather than warn.
+func strictConfig() (bool, error) {
+	strictStr := os.Getenv("GLCMD_STRICT_CONFIG")
+	if strictStr == "" {
+		return false, nil
+	}
+	strict, err := strconv.ParseBool(strictStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid GLCMD_STRICT_CONFIG: %s (must be a boolean)", strictStr)
+	}
+	return strict, nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckEnvironment(t *testing.T) {
+	environ := []string{
+		"HOME=/root",
+		"GLCMD_EMAIL=test@example.com",
+		"GLCMD_PASSWORD_FILE=/run/secrets/password",
+		"GLCMD_API_PROT=8080",
+		"GLCMD_FETCH_INTERVALL=5m",
+		"GLCMD_EMAIL_FILE=/run/secrets/email",
+		"GLCMD_ENABLE_EMOJIS=1",
+		"GLCMD_DB_TYPE=postgresql",
+		"GLCMD_DB_PORT=five",
+		"GLCMD_LOG_LEVEL=DEBUG",
+		"GLCMD_LOG_FORMAT=jsn",
+		`GLCMD_SYNC_DAYS="7"`,
+		"GLCMD_PLUGINS=/usr/bin/notify ",
+	}
+
+	want := []string{
+		"GLCMD_ENABLE_EMOJIS was removed in v0.5.0 and is ignored",
+		"GLCMD_PLUGINS has leading or trailing spaces",
+		"GLCMD_SYNC_DAYS is quoted: the quotes are part of the value",
+		"invalid GLCMD_DB_PORT: five (must be an integer), the default is used",
+		"invalid GLCMD_DB_TYPE: postgresql (must be sqlite, postgres), the default is used",
+		"invalid GLCMD_LOG_FORMAT: jsn (must be text, json), the default is used",
+		"unknown variable GLCMD_API_PROT (did you mean GLCMD_API_PORT?)",
+		"unknown variable GLCMD_EMAIL_FILE",
+		"unknown variable GLCMD_FETCH_INTERVALL",
+	}
+	if got := CheckEnvironment(environ); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected problems:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestLoad_StrictConfig(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	os.Setenv("GLCMD_JOB_WORKER", "4")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_JOB_WORKER")
+		os.Unsetenv("GLCMD_STRICT_CONFIG")
+	}()
+
+	// Problems are warnings by default
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "did you mean GLCMD_JOB_WORKERS?") {
+		t.Errorf("expected a warning for the typo, got %q", cfg.Warnings)
+	}
+
+	os.Setenv("GLCMD_STRICT_CONFIG", "1")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "GLCMD_JOB_WORKER") {
+		t.Errorf("expected strict mode to fail on the typo, got %v", err)
+	}
+
+	os.Setenv("GLCMD_STRICT_CONFIG", "yes please")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "GLCMD_STRICT_CONFIG") {
+		t.Errorf("expected an error for an invalid GLCMD_STRICT_CONFIG, got %v", err)
+	}
+}
@@ -3,23 +3,70 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/R4yL-dev/glcmd/internal/alerts"
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/faultinject"
 	"github.com/R4yL-dev/glcmd/internal/persistence"
 )
 
+// minAdminTokenLength rejects admin and API tokens too short to resist guessing.
+const minAdminTokenLength = 16
+
+// Low-memory mode settings (GLCMD_LOW_MEM), for Pi Zero and router deployments.
+const (
+	defaultEventBufferSize = 10
+	lowMemEventBufferSize  = 2
+	lowMemMemoryLimit      = 48 << 20 // Soft heap limit applied when GOMEMLIMIT is unset
+	lowMemMaxOpenConns     = 2
+	lowMemMaxIdleConns     = 1
+)
+
+// Plugin invocation defaults (GLCMD_PLUGIN_TIMEOUT, GLCMD_PLUGIN_CONCURRENCY).
+const (
+	defaultPluginTimeout     = 10 * time.Second
+	defaultPluginConcurrency = 2
+)
+
+const (
+	defaultJobWorkers   = 2
+	defaultJobRetention = 7 * 24 * time.Hour
+)
+
 // Config holds all application configuration.
 type Config struct {
 	Database    DatabaseConfig
 	API         APIConfig
 	Credentials CredentialsConfig
+	Sync        SyncConfig
+	Sensor      SensorConfig
+	Summary     SummaryConfig
+	Statistics  StatisticsConfig
+	Heartbeat   HeartbeatConfig
+	Nightscout  NightscoutConfig
+	Plugins     PluginsConfig
+	Alerts      AlertsConfig
+	Jobs        JobsConfig
+	Retention   RetentionConfig
+	Runtime     RuntimeConfig
+	Faults      faultinject.Config // Developer mode, see GLCMD_FAULT_INJECT
+	Warnings    []string           // Configuration problems to log, see CheckEnvironment
 }
 
 // DatabaseConfig holds database configuration.
 type DatabaseConfig struct {
 	Type            string
+	Backend         string
+	LowMemory       bool
 	SQLitePath      string
+	SecureFiles     bool // Restrict permissions of existing SQLite files to 0600
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
@@ -35,26 +82,193 @@ type DatabaseConfig struct {
 }
 
 // APIConfig holds API server configuration.
+// AdminToken protects the admin endpoints; it is needed to issue the first API token.
+// Tokens maps the static API tokens to their scope (domain.APIScopeRead or
+// domain.APIScopeWrite); empty leaves the data endpoints open.
+// AttachmentsDir stores the sensor attachment files (photos).
+// HealthPort serves /health on a separate listener (0 = disabled).
 type APIConfig struct {
-	Port int
+	Port           int
+	HealthPort     int
+	AdminToken     string
+	Tokens         map[string]string
+	AttachmentsDir string
 }
 
-// CredentialsConfig holds LibreView credentials.
+// CredentialsConfig holds LibreView credentials and the patients to follow.
+// The secondary account is optional: the daemon fails over to it when the
+// primary account is rejected or rate-limited.
+// PatientID selects the patient among the account's connections (empty = the
+// first one); with MultiPatient, the readings of all the connections are
+// stored and PatientID is the one alerts, targets and health follow.
 type CredentialsConfig struct {
-	Email    string
-	Password string
+	Email             string
+	Password          string
+	SecondaryEmail    string
+	SecondaryPassword string
+	PatientID         string
+	MultiPatient      bool
+	AcceptTerms       bool // Accept the documents LibreView requires at login
+}
+
+// SyncConfig holds replication configuration.
+// Token protects the export endpoint on a primary and authenticates a secondary.
+// PrimaryURL is set only on a secondary instance.
+type SyncConfig struct {
+	Token      string
+	PrimaryURL string
+	Interval   time.Duration
+	Days       int
+}
+
+// SensorConfig holds sensor lifecycle configuration.
+// GracePeriod is how long an expired sensor may keep reporting before it is considered ended.
+type SensorConfig struct {
+	GracePeriod time.Duration
+}
+
+// SummaryConfig holds the morning summary schedule.
+// MorningAt is the local time of day as an offset from midnight; Night is the
+// window summarized before it. The summary is disabled unless Enabled is set.
+type SummaryConfig struct {
+	Enabled   bool
+	MorningAt time.Duration
+	Night     time.Duration
+}
+
+// StatisticsConfig holds the secondary target bands whose time in range is
+// reported alongside the standard Time in Range.
+type StatisticsConfig struct {
+	TargetBands []domain.TargetBand
+}
+
+// HeartbeatConfig holds the external monitoring ping.
+// URL is pinged after each successful fetch (empty = disabled), so a monitor
+// such as healthchecks.io or an Uptime Kuma push monitor alerts when pings stop.
+type HeartbeatConfig struct {
+	URL string
+}
+
+// NightscoutConfig holds the Nightscout upload.
+// When URL is set, the measurements fetched by the daemon are pushed to the
+// Nightscout instance, authenticated with its API secret (empty URL = disabled).
+type NightscoutConfig struct {
+	URL       string
+	APISecret string
+}
+
+// PluginsConfig holds the plugin executables invoked on each event
+// (none = disabled). Each invocation is killed after Timeout, and at most
+// Concurrency invocations run at once.
+type PluginsConfig struct {
+	Commands    []string
+	Timeout     time.Duration
+	Concurrency int
+}
+
+// AlertsConfig holds the alert rules and notification channels.
+// Rules are evaluated only when at least one channel is configured (see
+// Enabled); an empty WebhookURL, SMTP.Host, TelegramToken, PushoverToken or
+// Sound disables that channel.
+type AlertsConfig struct {
+	Rules         alerts.Config
+	WebhookURL    string
+	SMTP          alerts.SMTPConfig
+	TelegramToken string
+	TelegramChat  string
+	PushoverToken string
+	PushoverUser  string
+
+	// Sound plays urgent alerts on the server: a command with its arguments,
+	// or alerts.SoundBeep.
+	Sound string
+
+	// TelegramCommands makes the Telegram bot answer /glucose and /sensor
+	// in the alert chat.
+	TelegramCommands bool
+
+	// Low alerts not acknowledged within EscalateAfter are forwarded to the
+	// escalation channels: EscalationTelegramChat (with the alert bot) and
+	// EscalationWebhookURL. EscalateAfter is 0 when no channel is set.
+	EscalateAfter          time.Duration
+	EscalationTelegramChat string
+	EscalationWebhookURL   string
+}
+
+// Escalates reports whether unacknowledged low alerts are escalated.
+func (c AlertsConfig) Escalates() bool {
+	return c.EscalateAfter > 0
+}
+
+// Enabled reports whether at least one notification channel is configured.
+func (c AlertsConfig) Enabled() bool {
+	return c.WebhookURL != "" || c.SMTP.Host != "" || c.TelegramToken != "" || c.PushoverToken != "" || c.Sound != ""
+}
+
+// JobsConfig holds the background jobs (imports, maintenance, reports and
+// replication): Workers run the jobs, and finished jobs are deleted after
+// Retention.
+type JobsConfig struct {
+	Workers   int
+	Retention time.Duration
+}
+
+// RetentionConfig holds the retention of the measurements: those older than
+// DownsampleDays are averaged into 15-minute rollups, and those older than
+// RawDays are deleted (0 = disabled).
+type RetentionConfig struct {
+	DownsampleDays int
+	RawDays        int
+}
+
+// Enabled reports whether the retention job runs.
+func (c RetentionConfig) Enabled() bool {
+	return c.DownsampleDays > 0
+}
+
+// RuntimeConfig holds process tuning.
+// LowMemory trades throughput for a smaller footprint; MemoryLimit is the soft
+// heap limit to apply in bytes (0 = leave the Go runtime default or GOMEMLIMIT).
+type RuntimeConfig struct {
+	LowMemory       bool
+	MemoryLimit     int64
+	EventBufferSize int
 }
 
 // Load loads all application configuration from environment variables.
-// Returns error if any required configuration is missing or invalid.
+// Returns error if any required configuration is missing or invalid, or if
+// GLCMD_STRICT_CONFIG is set and the environment has problems (otherwise
+// reported in Warnings).
 func Load() (*Config, error) {
 	config := &Config{}
 
+	// Check for typos first, as they can make the rest fail confusingly
+	strict, err := strictConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Warnings = CheckEnvironment(os.Environ())
+	if strict && len(config.Warnings) > 0 {
+		return nil, fmt.Errorf("strict config: %s", strings.Join(config.Warnings, "; "))
+	}
+
+	// Load runtime config first: low-memory mode adjusts the database pool
+	runtimeCfg, err := loadRuntimeConfig()
+	if err != nil {
+		return nil, fmt.Errorf("runtime config: %w", err)
+	}
+	config.Runtime = runtimeCfg
+
 	// Load database config
 	dbCfg, err := loadDatabaseConfig()
 	if err != nil {
 		return nil, fmt.Errorf("database config: %w", err)
 	}
+	if runtimeCfg.LowMemory {
+		dbCfg.LowMemory = true
+		dbCfg.MaxOpenConns = min(dbCfg.MaxOpenConns, lowMemMaxOpenConns)
+		dbCfg.MaxIdleConns = min(dbCfg.MaxIdleConns, lowMemMaxIdleConns)
+	}
 	config.Database = dbCfg
 
 	// Load API config
@@ -71,6 +285,82 @@ func Load() (*Config, error) {
 	}
 	config.Credentials = credsCfg
 
+	// Load sync config
+	syncCfg, err := loadSyncConfig()
+	if err != nil {
+		return nil, fmt.Errorf("sync config: %w", err)
+	}
+	config.Sync = syncCfg
+
+	// Load sensor config
+	sensorCfg, err := loadSensorConfig()
+	if err != nil {
+		return nil, fmt.Errorf("sensor config: %w", err)
+	}
+	config.Sensor = sensorCfg
+
+	// Load morning summary config
+	summaryCfg, err := loadSummaryConfig()
+	if err != nil {
+		return nil, fmt.Errorf("summary config: %w", err)
+	}
+	config.Summary = summaryCfg
+
+	// Load statistics config
+	statisticsCfg, err := loadStatisticsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("statistics config: %w", err)
+	}
+	config.Statistics = statisticsCfg
+
+	// Load heartbeat config
+	heartbeatCfg, err := loadHeartbeatConfig()
+	if err != nil {
+		return nil, fmt.Errorf("heartbeat config: %w", err)
+	}
+	config.Heartbeat = heartbeatCfg
+
+	// Load Nightscout upload config
+	nightscoutCfg, err := loadNightscoutConfig()
+	if err != nil {
+		return nil, fmt.Errorf("nightscout config: %w", err)
+	}
+	config.Nightscout = nightscoutCfg
+
+	// Load plugin config
+	pluginsCfg, err := loadPluginsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("plugins config: %w", err)
+	}
+	config.Plugins = pluginsCfg
+
+	// Load alerts config
+	alertsCfg, err := loadAlertsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("alerts config: %w", err)
+	}
+	config.Alerts = alertsCfg
+
+	// Load background jobs config
+	jobsCfg, err := loadJobsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("jobs config: %w", err)
+	}
+	config.Jobs = jobsCfg
+
+	retentionCfg, err := loadRetentionConfig()
+	if err != nil {
+		return nil, fmt.Errorf("retention config: %w", err)
+	}
+	config.Retention = retentionCfg
+
+	// Load fault injection (developer mode)
+	faults, err := faultinject.Parse(os.Getenv("GLCMD_FAULT_INJECT"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GLCMD_FAULT_INJECT: %w", err)
+	}
+	config.Faults = faults
+
 	return config, nil
 }
 
@@ -79,14 +369,37 @@ func loadDatabaseConfig() (DatabaseConfig, error) {
 	// Use existing persistence package loader
 	cfg := persistence.LoadDatabaseConfigFromEnv()
 
+	if cfg.Type == "postgres" {
+		password, err := secretEnv("GLCMD_DB_PASSWORD")
+		if err != nil {
+			return DatabaseConfig{}, err
+		}
+		cfg.Password = password
+	}
+
 	// Add validation for PostgreSQL
 	if cfg.Type == "postgres" && cfg.Password == "" {
 		return DatabaseConfig{}, fmt.Errorf("GLCMD_DB_PASSWORD is required for PostgreSQL")
 	}
 
+	if cfg.Backend != persistence.BackendGORM && cfg.Backend != persistence.BackendSQL {
+		return DatabaseConfig{}, fmt.Errorf("invalid GLCMD_DB_BACKEND: %s (must be %s or %s)", cfg.Backend, persistence.BackendGORM, persistence.BackendSQL)
+	}
+
+	var secureFiles bool
+	if secureStr := os.Getenv("GLCMD_DB_SECURE_FILES"); secureStr != "" {
+		secure, err := strconv.ParseBool(secureStr)
+		if err != nil {
+			return DatabaseConfig{}, fmt.Errorf("invalid GLCMD_DB_SECURE_FILES: %s (must be a boolean)", secureStr)
+		}
+		secureFiles = secure
+	}
+
 	return DatabaseConfig{
 		Type:            cfg.Type,
+		Backend:         cfg.Backend,
 		SQLitePath:      cfg.SQLitePath,
+		SecureFiles:     secureFiles,
 		MaxOpenConns:    cfg.MaxOpenConns,
 		MaxIdleConns:    cfg.MaxIdleConns,
 		ConnMaxLifetime: cfg.ConnMaxLifetime,
@@ -115,7 +428,72 @@ func loadAPIConfig() (APIConfig, error) {
 		port = parsedPort
 	}
 
-	return APIConfig{Port: port}, nil
+	var healthPort int
+	if portStr := os.Getenv("GLCMD_HEALTH_PORT"); portStr != "" {
+		parsedPort, err := strconv.Atoi(portStr)
+		if err != nil {
+			return APIConfig{}, fmt.Errorf("invalid GLCMD_HEALTH_PORT: %w (must be a number)", err)
+		}
+		if parsedPort < 1 || parsedPort > 65535 {
+			return APIConfig{}, fmt.Errorf("invalid GLCMD_HEALTH_PORT: %d (must be between 1 and 65535)", parsedPort)
+		}
+		if parsedPort == port {
+			return APIConfig{}, fmt.Errorf("invalid GLCMD_HEALTH_PORT: %d is the API port", parsedPort)
+		}
+		healthPort = parsedPort
+	}
+
+	adminToken, err := secretEnv("GLCMD_ADMIN_TOKEN")
+	if err != nil {
+		return APIConfig{}, err
+	}
+	if adminToken != "" && len(adminToken) < minAdminTokenLength {
+		return APIConfig{}, fmt.Errorf("invalid GLCMD_ADMIN_TOKEN: must be at least %d characters", minAdminTokenLength)
+	}
+
+	tokensStr, err := secretEnv("GLCMD_API_TOKENS")
+	if err != nil {
+		return APIConfig{}, err
+	}
+	var tokens map[string]string
+	if tokensStr != "" {
+		tokens, err = parseAPITokens(tokensStr)
+		if err != nil {
+			return APIConfig{}, fmt.Errorf("invalid GLCMD_API_TOKENS: %w", err)
+		}
+	}
+
+	attachmentsDir := os.Getenv("GLCMD_ATTACHMENTS_DIR")
+	if attachmentsDir == "" {
+		attachmentsDir = "./data/attachments"
+	}
+
+	return APIConfig{Port: port, HealthPort: healthPort, AdminToken: adminToken, Tokens: tokens, AttachmentsDir: attachmentsDir}, nil
+}
+
+// parseAPITokens parses a comma-separated list of token:scope entries.
+// Errors never include the token itself, only its position in the list.
+func parseAPITokens(s string) (map[string]string, error) {
+	tokens := make(map[string]string)
+
+	for i, entry := range strings.Split(s, ",") {
+		token, scope, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || token == "" {
+			return nil, fmt.Errorf("entry %d: expected token:scope (e.g. <token>:read)", i+1)
+		}
+		if scope != domain.APIScopeRead && scope != domain.APIScopeWrite {
+			return nil, fmt.Errorf("entry %d: scope must be %q or %q", i+1, domain.APIScopeRead, domain.APIScopeWrite)
+		}
+		if len(token) < minAdminTokenLength {
+			return nil, fmt.Errorf("entry %d: token must be at least %d characters", i+1, minAdminTokenLength)
+		}
+		if _, dup := tokens[token]; dup {
+			return nil, fmt.Errorf("entry %d: duplicate token", i+1)
+		}
+		tokens[token] = scope
+	}
+
+	return tokens, nil
 }
 
 // loadCredentialsConfig loads LibreView credentials with validation.
@@ -125,22 +503,629 @@ func loadCredentialsConfig() (CredentialsConfig, error) {
 		return CredentialsConfig{}, fmt.Errorf("GLCMD_EMAIL environment variable is required")
 	}
 
-	password := os.Getenv("GLCMD_PASSWORD")
+	password, err := secretEnv("GLCMD_PASSWORD")
+	if err != nil {
+		return CredentialsConfig{}, err
+	}
 	if password == "" {
 		return CredentialsConfig{}, fmt.Errorf("GLCMD_PASSWORD environment variable is required")
 	}
 
+	secondaryEmail := os.Getenv("GLCMD_SECONDARY_EMAIL")
+	secondaryPassword, err := secretEnv("GLCMD_SECONDARY_PASSWORD")
+	if err != nil {
+		return CredentialsConfig{}, err
+	}
+	if (secondaryEmail == "") != (secondaryPassword == "") {
+		return CredentialsConfig{}, fmt.Errorf("GLCMD_SECONDARY_EMAIL and GLCMD_SECONDARY_PASSWORD must be set together")
+	}
+	if secondaryEmail != "" && strings.EqualFold(secondaryEmail, email) {
+		return CredentialsConfig{}, fmt.Errorf("GLCMD_SECONDARY_EMAIL must differ from GLCMD_EMAIL")
+	}
+
+	patientID := strings.ToLower(strings.TrimSpace(os.Getenv("GLCMD_PATIENT_ID")))
+	if patientID != "" {
+		if _, err := uuid.Parse(patientID); err != nil {
+			return CredentialsConfig{}, fmt.Errorf("invalid GLCMD_PATIENT_ID: %s (must be a LibreLinkUp patient ID, see glcli connection)", patientID)
+		}
+	}
+
+	var multiPatient bool
+	if multiStr := os.Getenv("GLCMD_MULTI_PATIENT"); multiStr != "" {
+		if multiPatient, err = strconv.ParseBool(multiStr); err != nil {
+			return CredentialsConfig{}, fmt.Errorf("invalid GLCMD_MULTI_PATIENT: %s (must be a boolean)", multiStr)
+		}
+		if multiPatient && patientID == "" {
+			return CredentialsConfig{}, fmt.Errorf("GLCMD_MULTI_PATIENT requires GLCMD_PATIENT_ID (the patient alerts and the API default to)")
+		}
+	}
+
+	var acceptTerms bool
+	if acceptStr := os.Getenv("GLCMD_ACCEPT_TERMS"); acceptStr != "" {
+		if acceptTerms, err = strconv.ParseBool(acceptStr); err != nil {
+			return CredentialsConfig{}, fmt.Errorf("invalid GLCMD_ACCEPT_TERMS: %s (must be a boolean)", acceptStr)
+		}
+	}
+
 	return CredentialsConfig{
-		Email:    email,
-		Password: password,
+		Email:             email,
+		Password:          password,
+		SecondaryEmail:    secondaryEmail,
+		SecondaryPassword: secondaryPassword,
+		PatientID:         patientID,
+		MultiPatient:      multiPatient,
+		AcceptTerms:       acceptTerms,
 	}, nil
 }
 
+// loadSyncConfig loads replication configuration with validation.
+func loadSyncConfig() (SyncConfig, error) {
+	token, err := secretEnv("GLCMD_SYNC_TOKEN")
+	if err != nil {
+		return SyncConfig{}, err
+	}
+
+	cfg := SyncConfig{
+		Token:      token,
+		PrimaryURL: strings.TrimRight(os.Getenv("GLCMD_SYNC_PRIMARY_URL"), "/"),
+		Interval:   5 * time.Minute,
+		Days:       7,
+	}
+
+	if intervalStr := os.Getenv("GLCMD_SYNC_INTERVAL"); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil {
+			return SyncConfig{}, fmt.Errorf("invalid GLCMD_SYNC_INTERVAL: %w", err)
+		}
+		if interval < time.Minute {
+			return SyncConfig{}, fmt.Errorf("invalid GLCMD_SYNC_INTERVAL: %s (must be at least 1m)", interval)
+		}
+		cfg.Interval = interval
+	}
+
+	if daysStr := os.Getenv("GLCMD_SYNC_DAYS"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil {
+			return SyncConfig{}, fmt.Errorf("invalid GLCMD_SYNC_DAYS: %w (must be a number)", err)
+		}
+		if days < 1 || days > 366 {
+			return SyncConfig{}, fmt.Errorf("invalid GLCMD_SYNC_DAYS: %d (must be between 1 and 366)", days)
+		}
+		cfg.Days = days
+	}
+
+	if cfg.PrimaryURL != "" {
+		if !strings.HasPrefix(cfg.PrimaryURL, "http://") && !strings.HasPrefix(cfg.PrimaryURL, "https://") {
+			return SyncConfig{}, fmt.Errorf("invalid GLCMD_SYNC_PRIMARY_URL: must start with http:// or https://")
+		}
+		if cfg.Token == "" {
+			return SyncConfig{}, fmt.Errorf("GLCMD_SYNC_TOKEN is required when GLCMD_SYNC_PRIMARY_URL is set")
+		}
+	}
+
+	return cfg, nil
+}
+
+// loadSensorConfig loads sensor lifecycle configuration with validation.
+func loadSensorConfig() (SensorConfig, error) {
+	cfg := SensorConfig{
+		GracePeriod: domain.DefaultSensorGracePeriod,
+	}
+
+	if graceStr := os.Getenv("GLCMD_SENSOR_GRACE_PERIOD"); graceStr != "" {
+		grace, err := time.ParseDuration(graceStr)
+		if err != nil {
+			return SensorConfig{}, fmt.Errorf("invalid GLCMD_SENSOR_GRACE_PERIOD: %w", err)
+		}
+		if grace < 0 || grace > 48*time.Hour {
+			return SensorConfig{}, fmt.Errorf("invalid GLCMD_SENSOR_GRACE_PERIOD: %s (must be between 0 and 48h)", grace)
+		}
+		cfg.GracePeriod = grace
+	}
+
+	return cfg, nil
+}
+
+// loadSummaryConfig loads the morning summary schedule with validation.
+func loadSummaryConfig() (SummaryConfig, error) {
+	cfg := SummaryConfig{
+		Night: 8 * time.Hour,
+	}
+
+	if atStr := os.Getenv("GLCMD_MORNING_SUMMARY_TIME"); atStr != "" {
+		at, err := time.Parse("15:04", atStr)
+		if err != nil {
+			return SummaryConfig{}, fmt.Errorf("invalid GLCMD_MORNING_SUMMARY_TIME: %s (expected HH:MM)", atStr)
+		}
+		cfg.Enabled = true
+		cfg.MorningAt = time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute
+	}
+
+	if nightStr := os.Getenv("GLCMD_MORNING_SUMMARY_NIGHT"); nightStr != "" {
+		night, err := time.ParseDuration(nightStr)
+		if err != nil {
+			return SummaryConfig{}, fmt.Errorf("invalid GLCMD_MORNING_SUMMARY_NIGHT: %w", err)
+		}
+		if night < time.Hour || night > 16*time.Hour {
+			return SummaryConfig{}, fmt.Errorf("invalid GLCMD_MORNING_SUMMARY_NIGHT: %s (must be between 1h and 16h)", night)
+		}
+		cfg.Night = night
+	}
+
+	return cfg, nil
+}
+
+// maxTargetBands limits the number of secondary target bands.
+const maxTargetBands = 5
+
+// loadStatisticsConfig loads the secondary target bands with validation.
+// GLCMD_TARGET_BANDS is a comma-separated list of name:low-high bands in mg/dL
+// (e.g. "tight:70-140,pregnancy:63-140"); "none" disables them.
+func loadStatisticsConfig() (StatisticsConfig, error) {
+	bandsStr := os.Getenv("GLCMD_TARGET_BANDS")
+	if bandsStr == "" {
+		return StatisticsConfig{TargetBands: domain.DefaultTargetBands()}, nil
+	}
+	if bandsStr == "none" {
+		return StatisticsConfig{}, nil
+	}
+
+	bands, err := parseTargetBands(bandsStr)
+	if err != nil {
+		return StatisticsConfig{}, fmt.Errorf("invalid GLCMD_TARGET_BANDS: %w", err)
+	}
+
+	return StatisticsConfig{TargetBands: bands}, nil
+}
+
+// parseTargetBands parses a comma-separated list of name:low-high bands.
+func parseTargetBands(s string) ([]domain.TargetBand, error) {
+	var bands []domain.TargetBand
+	seen := make(map[string]bool)
+
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		name, rangeStr, ok := strings.Cut(entry, ":")
+		lowStr, highStr, okRange := strings.Cut(rangeStr, "-")
+		if !ok || !okRange || name == "" {
+			return nil, fmt.Errorf("%q (expected name:low-high, e.g. tight:70-140)", entry)
+		}
+
+		low, errLow := strconv.Atoi(lowStr)
+		high, errHigh := strconv.Atoi(highStr)
+		if errLow != nil || errHigh != nil {
+			return nil, fmt.Errorf("%q: bounds must be integers in mg/dL", entry)
+		}
+		if low < 20 || high > 500 || low >= high {
+			return nil, fmt.Errorf("%q: bounds must satisfy 20 <= low < high <= 500", entry)
+		}
+
+		name = strings.ToLower(name)
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate band %q", name)
+		}
+		seen[name] = true
+
+		bands = append(bands, domain.TargetBand{Name: name, LowMgDl: low, HighMgDl: high})
+	}
+
+	if len(bands) > maxTargetBands {
+		return nil, fmt.Errorf("at most %d bands are supported", maxTargetBands)
+	}
+
+	return bands, nil
+}
+
+// loadHeartbeatConfig loads the external monitoring ping configuration.
+func loadHeartbeatConfig() (HeartbeatConfig, error) {
+	// The URL of push monitors embeds their secret
+	url, err := secretEnv("GLCMD_HEARTBEAT_URL")
+	if err != nil {
+		return HeartbeatConfig{}, err
+	}
+	cfg := HeartbeatConfig{URL: url}
+
+	if cfg.URL != "" && !strings.HasPrefix(cfg.URL, "http://") && !strings.HasPrefix(cfg.URL, "https://") {
+		return HeartbeatConfig{}, fmt.Errorf("invalid GLCMD_HEARTBEAT_URL: must start with http:// or https://")
+	}
+
+	return cfg, nil
+}
+
+// loadNightscoutConfig loads the Nightscout upload configuration.
+func loadNightscoutConfig() (NightscoutConfig, error) {
+	secret, err := secretEnv("GLCMD_NIGHTSCOUT_API_SECRET")
+	if err != nil {
+		return NightscoutConfig{}, err
+	}
+	cfg := NightscoutConfig{
+		URL:       strings.TrimSpace(os.Getenv("GLCMD_NIGHTSCOUT_URL")),
+		APISecret: secret,
+	}
+
+	if cfg.URL == "" {
+		if cfg.APISecret != "" {
+			return NightscoutConfig{}, fmt.Errorf("GLCMD_NIGHTSCOUT_API_SECRET is set without GLCMD_NIGHTSCOUT_URL")
+		}
+		return cfg, nil
+	}
+	if !strings.HasPrefix(cfg.URL, "http://") && !strings.HasPrefix(cfg.URL, "https://") {
+		return NightscoutConfig{}, fmt.Errorf("invalid GLCMD_NIGHTSCOUT_URL: must start with http:// or https://")
+	}
+	if cfg.APISecret == "" {
+		return NightscoutConfig{}, fmt.Errorf("GLCMD_NIGHTSCOUT_API_SECRET is required when GLCMD_NIGHTSCOUT_URL is set")
+	}
+
+	return cfg, nil
+}
+
+// loadPluginsConfig loads the plugin executables with validation.
+// GLCMD_PLUGINS is a comma-separated list of absolute paths.
+func loadPluginsConfig() (PluginsConfig, error) {
+	cfg := PluginsConfig{
+		Timeout:     defaultPluginTimeout,
+		Concurrency: defaultPluginConcurrency,
+	}
+
+	if pluginsStr := os.Getenv("GLCMD_PLUGINS"); pluginsStr != "" {
+		for _, command := range strings.Split(pluginsStr, ",") {
+			command = strings.TrimSpace(command)
+			if command == "" {
+				continue
+			}
+			if !filepath.IsAbs(command) {
+				return PluginsConfig{}, fmt.Errorf("invalid GLCMD_PLUGINS: %s (must be an absolute path)", command)
+			}
+			if slices.Contains(cfg.Commands, command) {
+				return PluginsConfig{}, fmt.Errorf("invalid GLCMD_PLUGINS: %s is listed twice", command)
+			}
+			cfg.Commands = append(cfg.Commands, command)
+		}
+	}
+
+	if timeoutStr := os.Getenv("GLCMD_PLUGIN_TIMEOUT"); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return PluginsConfig{}, fmt.Errorf("invalid GLCMD_PLUGIN_TIMEOUT: %w", err)
+		}
+		if timeout < time.Second || timeout > 5*time.Minute {
+			return PluginsConfig{}, fmt.Errorf("invalid GLCMD_PLUGIN_TIMEOUT: %s (must be between 1s and 5m)", timeout)
+		}
+		cfg.Timeout = timeout
+	}
+
+	if concurrencyStr := os.Getenv("GLCMD_PLUGIN_CONCURRENCY"); concurrencyStr != "" {
+		concurrency, err := strconv.Atoi(concurrencyStr)
+		if err != nil || concurrency < 1 || concurrency > 16 {
+			return PluginsConfig{}, fmt.Errorf("invalid GLCMD_PLUGIN_CONCURRENCY: %s (must be between 1 and 16)", concurrencyStr)
+		}
+		cfg.Concurrency = concurrency
+	}
+
+	return cfg, nil
+}
+
+// loadAlertsConfig loads the alert rules and notification channels with validation.
+func loadAlertsConfig() (AlertsConfig, error) {
+	cfg := AlertsConfig{Rules: alerts.DefaultConfig()}
+
+	if rulesStr := os.Getenv("GLCMD_ALERT_RULES"); rulesStr != "" {
+		rules, err := parseAlertRules(rulesStr)
+		if err != nil {
+			return AlertsConfig{}, fmt.Errorf("invalid GLCMD_ALERT_RULES: %w", err)
+		}
+		cfg.Rules.Rules = rules
+	}
+
+	if highStr := os.Getenv("GLCMD_ALERT_HIGH_MGDL"); highStr != "" {
+		high, err := strconv.Atoi(highStr)
+		if err != nil || high < 120 || high > 400 {
+			return AlertsConfig{}, fmt.Errorf("invalid GLCMD_ALERT_HIGH_MGDL: %s (must be between 120 and 400)", highStr)
+		}
+		cfg.Rules.HighMgDl = high
+	}
+
+	if staleStr := os.Getenv("GLCMD_ALERT_STALE_AFTER"); staleStr != "" {
+		stale, err := time.ParseDuration(staleStr)
+		if err != nil {
+			return AlertsConfig{}, fmt.Errorf("invalid GLCMD_ALERT_STALE_AFTER: %w", err)
+		}
+		if stale < 5*time.Minute || stale > 24*time.Hour {
+			return AlertsConfig{}, fmt.Errorf("invalid GLCMD_ALERT_STALE_AFTER: %s (must be between 5m and 24h)", stale)
+		}
+		cfg.Rules.StaleAfter = stale
+	}
+
+	if remindersStr := os.Getenv("GLCMD_ALERT_SENSOR_REMINDERS"); remindersStr != "" {
+		reminders, err := parseSensorReminders(remindersStr)
+		if err != nil {
+			return AlertsConfig{}, fmt.Errorf("invalid GLCMD_ALERT_SENSOR_REMINDERS: %w", err)
+		}
+		cfg.Rules.SensorReminders = reminders
+	}
+
+	// Webhook URLs (Slack, Discord, ntfy...) usually embed their secret
+	webhookURL, err := secretEnv("GLCMD_ALERT_WEBHOOK_URL")
+	if err != nil {
+		return AlertsConfig{}, err
+	}
+	if webhookURL != "" && !strings.HasPrefix(webhookURL, "http://") && !strings.HasPrefix(webhookURL, "https://") {
+		return AlertsConfig{}, fmt.Errorf("invalid GLCMD_ALERT_WEBHOOK_URL: must start with http:// or https://")
+	}
+	cfg.WebhookURL = webhookURL
+
+	smtpCfg, err := loadSMTPConfig()
+	if err != nil {
+		return AlertsConfig{}, err
+	}
+	cfg.SMTP = smtpCfg
+
+	if cfg.TelegramToken, err = secretEnv("GLCMD_ALERT_TELEGRAM_TOKEN"); err != nil {
+		return AlertsConfig{}, err
+	}
+	cfg.TelegramChat = os.Getenv("GLCMD_ALERT_TELEGRAM_CHAT_ID")
+	if (cfg.TelegramToken == "") != (cfg.TelegramChat == "") {
+		return AlertsConfig{}, fmt.Errorf("GLCMD_ALERT_TELEGRAM_TOKEN and GLCMD_ALERT_TELEGRAM_CHAT_ID must be set together")
+	}
+
+	if commandsStr := os.Getenv("GLCMD_ALERT_TELEGRAM_COMMANDS"); commandsStr != "" {
+		commands, err := strconv.ParseBool(commandsStr)
+		if err != nil {
+			return AlertsConfig{}, fmt.Errorf("invalid GLCMD_ALERT_TELEGRAM_COMMANDS: %s (must be a boolean)", commandsStr)
+		}
+		if commands && cfg.TelegramToken == "" {
+			return AlertsConfig{}, fmt.Errorf("GLCMD_ALERT_TELEGRAM_COMMANDS requires GLCMD_ALERT_TELEGRAM_TOKEN and GLCMD_ALERT_TELEGRAM_CHAT_ID")
+		}
+		if _, err := strconv.ParseInt(cfg.TelegramChat, 10, 64); commands && err != nil {
+			return AlertsConfig{}, fmt.Errorf("invalid GLCMD_ALERT_TELEGRAM_CHAT_ID: %s (must be a numeric chat ID to receive commands)", cfg.TelegramChat)
+		}
+		cfg.TelegramCommands = commands
+	}
+
+	if cfg.PushoverToken, err = secretEnv("GLCMD_ALERT_PUSHOVER_TOKEN"); err != nil {
+		return AlertsConfig{}, err
+	}
+	if cfg.PushoverUser, err = secretEnv("GLCMD_ALERT_PUSHOVER_USER"); err != nil {
+		return AlertsConfig{}, err
+	}
+	if (cfg.PushoverToken == "") != (cfg.PushoverUser == "") {
+		return AlertsConfig{}, fmt.Errorf("GLCMD_ALERT_PUSHOVER_TOKEN and GLCMD_ALERT_PUSHOVER_USER must be set together")
+	}
+
+	cfg.Sound = strings.TrimSpace(os.Getenv("GLCMD_ALERT_SOUND"))
+
+	if err := loadEscalationConfig(&cfg); err != nil {
+		return AlertsConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+// loadEscalationConfig loads the escalation of unacknowledged low alerts
+// (disabled unless an escalation channel is set).
+func loadEscalationConfig(cfg *AlertsConfig) error {
+	cfg.EscalationTelegramChat = os.Getenv("GLCMD_ALERT_ESCALATION_TELEGRAM_CHAT_ID")
+	if cfg.EscalationTelegramChat != "" && cfg.TelegramToken == "" {
+		return fmt.Errorf("GLCMD_ALERT_ESCALATION_TELEGRAM_CHAT_ID requires GLCMD_ALERT_TELEGRAM_TOKEN (the escalation is sent by the alert bot)")
+	}
+
+	webhookURL, err := secretEnv("GLCMD_ALERT_ESCALATION_WEBHOOK_URL")
+	if err != nil {
+		return err
+	}
+	if webhookURL != "" && !strings.HasPrefix(webhookURL, "http://") && !strings.HasPrefix(webhookURL, "https://") {
+		return fmt.Errorf("invalid GLCMD_ALERT_ESCALATION_WEBHOOK_URL: must start with http:// or https://")
+	}
+	cfg.EscalationWebhookURL = webhookURL
+
+	afterStr := os.Getenv("GLCMD_ALERT_ESCALATE_AFTER")
+	if cfg.EscalationTelegramChat == "" && cfg.EscalationWebhookURL == "" {
+		if afterStr != "" {
+			return fmt.Errorf("GLCMD_ALERT_ESCALATE_AFTER requires GLCMD_ALERT_ESCALATION_TELEGRAM_CHAT_ID or GLCMD_ALERT_ESCALATION_WEBHOOK_URL")
+		}
+		return nil
+	}
+	if !slices.Contains(cfg.Rules.Rules, alerts.RuleLow) {
+		return fmt.Errorf("alert escalation requires the low rule in GLCMD_ALERT_RULES")
+	}
+
+	cfg.EscalateAfter = alerts.DefaultEscalateAfter
+	if afterStr != "" {
+		after, err := time.ParseDuration(afterStr)
+		if err != nil {
+			return fmt.Errorf("invalid GLCMD_ALERT_ESCALATE_AFTER: %w", err)
+		}
+		if after < 5*time.Minute || after > 2*time.Hour {
+			return fmt.Errorf("invalid GLCMD_ALERT_ESCALATE_AFTER: %s (must be between 5m and 2h)", after)
+		}
+		cfg.EscalateAfter = after
+	}
+
+	return nil
+}
+
+// maxSensorReminders is the maximum number of sensor expiry reminders.
+const maxSensorReminders = 5
+
+// parseSensorReminders parses a comma-separated list of lead times before
+// sensor expiry (e.g. "72h,24h,2h").
+func parseSensorReminders(s string) ([]time.Duration, error) {
+	var reminders []time.Duration
+	for _, part := range strings.Split(s, ",") {
+		lead, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		if lead < time.Hour || lead > 7*24*time.Hour {
+			return nil, fmt.Errorf("%s (must be between 1h and 168h)", lead)
+		}
+		if !slices.Contains(reminders, lead) {
+			reminders = append(reminders, lead)
+		}
+	}
+	if len(reminders) > maxSensorReminders {
+		return nil, fmt.Errorf("at most %d reminders", maxSensorReminders)
+	}
+	return reminders, nil
+}
+
+// parseAlertRules parses a comma-separated list of alert rules ("none" disables them all).
+func parseAlertRules(s string) ([]alerts.Rule, error) {
+	if s == "none" {
+		return nil, nil
+	}
+
+	var rules []alerts.Rule
+	for _, name := range strings.Split(s, ",") {
+		rule := alerts.Rule(strings.ToLower(strings.TrimSpace(name)))
+		if !slices.Contains(alerts.Rules, rule) {
+			return nil, fmt.Errorf("unknown rule %q (must be one of %s)", name, joinRules(alerts.Rules))
+		}
+		if !slices.Contains(rules, rule) {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// joinRules returns the comma-separated names of rules.
+func joinRules(rules []alerts.Rule) string {
+	names := make([]string, len(rules))
+	for i, rule := range rules {
+		names[i] = string(rule)
+	}
+	return strings.Join(names, ", ")
+}
+
+// loadSMTPConfig loads the email alert channel (disabled unless GLCMD_ALERT_SMTP_HOST is set).
+func loadSMTPConfig() (alerts.SMTPConfig, error) {
+	password, err := secretEnv("GLCMD_ALERT_SMTP_PASSWORD")
+	if err != nil {
+		return alerts.SMTPConfig{}, err
+	}
+	cfg := alerts.SMTPConfig{
+		Host:     os.Getenv("GLCMD_ALERT_SMTP_HOST"),
+		Port:     587,
+		Username: os.Getenv("GLCMD_ALERT_SMTP_USERNAME"),
+		Password: password,
+		From:     os.Getenv("GLCMD_ALERT_EMAIL_FROM"),
+	}
+	for _, to := range strings.Split(os.Getenv("GLCMD_ALERT_EMAIL_TO"), ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			cfg.To = append(cfg.To, to)
+		}
+	}
+
+	if cfg.Host == "" {
+		return alerts.SMTPConfig{}, nil
+	}
+
+	if portStr := os.Getenv("GLCMD_ALERT_SMTP_PORT"); portStr != "" {
+		port, err := strconv.Atoi(portStr)
+		if err != nil || port < 1 || port > 65535 {
+			return alerts.SMTPConfig{}, fmt.Errorf("invalid GLCMD_ALERT_SMTP_PORT: %s (must be between 1 and 65535)", portStr)
+		}
+		cfg.Port = port
+	}
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return alerts.SMTPConfig{}, fmt.Errorf("GLCMD_ALERT_EMAIL_FROM and GLCMD_ALERT_EMAIL_TO are required when GLCMD_ALERT_SMTP_HOST is set")
+	}
+	if cfg.Password != "" && cfg.Username == "" {
+		return alerts.SMTPConfig{}, fmt.Errorf("GLCMD_ALERT_SMTP_PASSWORD is set without GLCMD_ALERT_SMTP_USERNAME")
+	}
+
+	return cfg, nil
+}
+
+// loadJobsConfig loads the background jobs settings with validation.
+func loadJobsConfig() (JobsConfig, error) {
+	cfg := JobsConfig{
+		Workers:   defaultJobWorkers,
+		Retention: defaultJobRetention,
+	}
+
+	if workersStr := os.Getenv("GLCMD_JOB_WORKERS"); workersStr != "" {
+		workers, err := strconv.Atoi(workersStr)
+		if err != nil || workers < 1 || workers > 16 {
+			return JobsConfig{}, fmt.Errorf("invalid GLCMD_JOB_WORKERS: %s (must be between 1 and 16)", workersStr)
+		}
+		cfg.Workers = workers
+	}
+
+	if retentionStr := os.Getenv("GLCMD_JOB_RETENTION"); retentionStr != "" {
+		retention, err := time.ParseDuration(retentionStr)
+		if err != nil {
+			return JobsConfig{}, fmt.Errorf("invalid GLCMD_JOB_RETENTION: %w", err)
+		}
+		if retention < time.Hour {
+			return JobsConfig{}, fmt.Errorf("invalid GLCMD_JOB_RETENTION: %s (must be at least 1h)", retention)
+		}
+		cfg.Retention = retention
+	}
+
+	return cfg, nil
+}
+
+// loadRetentionConfig loads the measurement retention settings with validation.
+func loadRetentionConfig() (RetentionConfig, error) {
+	var cfg RetentionConfig
+
+	days := map[string]*int{
+		"GLCMD_RETENTION_DOWNSAMPLE_DAYS": &cfg.DownsampleDays,
+		"GLCMD_RETENTION_RAW_DAYS":        &cfg.RawDays,
+	}
+	for name, value := range days {
+		str := os.Getenv(name)
+		if str == "" {
+			continue
+		}
+		n, err := strconv.Atoi(str)
+		if err != nil || n < 0 {
+			return RetentionConfig{}, fmt.Errorf("invalid %s: %s (must be a number of days, 0 to disable)", name, str)
+		}
+		*value = n
+	}
+
+	// Raw measurements are only deleted once rolled up
+	if cfg.RawDays > 0 && (cfg.DownsampleDays == 0 || cfg.RawDays < cfg.DownsampleDays) {
+		return RetentionConfig{}, fmt.Errorf("invalid GLCMD_RETENTION_RAW_DAYS: %d (requires GLCMD_RETENTION_DOWNSAMPLE_DAYS, and must not be lower)", cfg.RawDays)
+	}
+
+	return cfg, nil
+}
+
+// loadRuntimeConfig loads process tuning with validation.
+func loadRuntimeConfig() (RuntimeConfig, error) {
+	cfg := RuntimeConfig{EventBufferSize: defaultEventBufferSize}
+
+	if lowMemStr := os.Getenv("GLCMD_LOW_MEM"); lowMemStr != "" {
+		lowMem, err := strconv.ParseBool(lowMemStr)
+		if err != nil {
+			return RuntimeConfig{}, fmt.Errorf("invalid GLCMD_LOW_MEM: %s (must be 1, 0, true or false)", lowMemStr)
+		}
+		cfg.LowMemory = lowMem
+	}
+
+	if cfg.LowMemory {
+		cfg.EventBufferSize = lowMemEventBufferSize
+		// An explicit GOMEMLIMIT is applied by the Go runtime and takes precedence
+		if os.Getenv("GOMEMLIMIT") == "" {
+			cfg.MemoryLimit = lowMemMemoryLimit
+		}
+	}
+
+	return cfg, nil
+}
+
 // ToPersistenceConfig converts DatabaseConfig to persistence.DatabaseConfig for backward compatibility.
 func (c *DatabaseConfig) ToPersistenceConfig() *persistence.DatabaseConfig {
 	return &persistence.DatabaseConfig{
 		Type:            c.Type,
+		Backend:         c.Backend,
+		LowMemory:       c.LowMemory,
 		SQLitePath:      c.SQLitePath,
+		SecureFiles:     c.SecureFiles,
 		MaxOpenConns:    c.MaxOpenConns,
 		MaxIdleConns:    c.MaxIdleConns,
 		ConnMaxLifetime: c.ConnMaxLifetime,
@@ -2,7 +2,13 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/alerts"
+	"github.com/R4yL-dev/glcmd/internal/domain"
 )
 
 func TestLoad_Success(t *testing.T) {
@@ -31,6 +37,9 @@ func TestLoad_Success(t *testing.T) {
 	if cfg.API.Port != 8080 {
 		t.Errorf("expected API port 8080, got %d", cfg.API.Port)
 	}
+	if cfg.API.AttachmentsDir != "./data/attachments" {
+		t.Errorf("expected attachments dir ./data/attachments, got %s", cfg.API.AttachmentsDir)
+	}
 
 	// Verify credentials
 	if cfg.Credentials.Email != "test@example.com" {
@@ -65,6 +74,104 @@ func TestLoad_MissingPassword(t *testing.T) {
 	}
 }
 
+func TestLoad_SecondaryAccount(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_SECONDARY_EMAIL")
+		os.Unsetenv("GLCMD_SECONDARY_PASSWORD")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Credentials.SecondaryEmail != "" {
+		t.Errorf("expected no secondary account by default, got %q", cfg.Credentials.SecondaryEmail)
+	}
+
+	os.Setenv("GLCMD_SECONDARY_EMAIL", "backup@example.com")
+	os.Setenv("GLCMD_SECONDARY_PASSWORD", "backuppassword")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Credentials.SecondaryEmail != "backup@example.com" || cfg.Credentials.SecondaryPassword != "backuppassword" {
+		t.Errorf("unexpected secondary account: %+v", cfg.Credentials)
+	}
+
+	os.Unsetenv("GLCMD_SECONDARY_PASSWORD")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for a secondary email without password")
+	}
+
+	os.Setenv("GLCMD_SECONDARY_EMAIL", "Test@example.com")
+	os.Setenv("GLCMD_SECONDARY_PASSWORD", "backuppassword")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for a secondary account identical to the primary")
+	}
+}
+
+func TestLoad_Patients(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_PATIENT_ID")
+		os.Unsetenv("GLCMD_MULTI_PATIENT")
+	}()
+
+	os.Setenv("GLCMD_MULTI_PATIENT", "true")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for multi-patient mode without patient ID, got nil")
+	}
+
+	os.Setenv("GLCMD_PATIENT_ID", " 6C3E1F1A-2B4D-4E5F-8A9B-0C1D2E3F4A5B ")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Credentials.PatientID != "6c3e1f1a-2b4d-4e5f-8a9b-0c1d2e3f4a5b" || !cfg.Credentials.MultiPatient {
+		t.Errorf("unexpected patient config: %+v", cfg.Credentials)
+	}
+
+	os.Setenv("GLCMD_PATIENT_ID", "john")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for an invalid patient ID, got nil")
+	}
+}
+
+func TestLoad_AcceptTerms(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_ACCEPT_TERMS")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Credentials.AcceptTerms {
+		t.Error("expected new terms not to be accepted by default")
+	}
+
+	os.Setenv("GLCMD_ACCEPT_TERMS", "true")
+	if cfg, err = Load(); err != nil || !cfg.Credentials.AcceptTerms {
+		t.Errorf("expected new terms to be accepted, got %v (%v)", cfg.Credentials.AcceptTerms, err)
+	}
+
+	os.Setenv("GLCMD_ACCEPT_TERMS", "sure")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for an invalid GLCMD_ACCEPT_TERMS, got nil")
+	}
+}
+
 func TestLoad_InvalidAPIPort(t *testing.T) {
 	os.Setenv("GLCMD_EMAIL", "test@example.com")
 	os.Setenv("GLCMD_PASSWORD", "testpassword")
@@ -97,6 +204,32 @@ func TestLoad_APIPortOutOfRange(t *testing.T) {
 	}
 }
 
+func TestLoad_HealthPort(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_HEALTH_PORT")
+	}()
+
+	os.Setenv("GLCMD_HEALTH_PORT", "8081")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.API.HealthPort != 8081 {
+		t.Errorf("expected health port 8081, got %d", cfg.API.HealthPort)
+	}
+
+	for _, value := range []string{"invalid", "0", "8080"} {
+		os.Setenv("GLCMD_HEALTH_PORT", value)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for GLCMD_HEALTH_PORT=%s, got nil", value)
+		}
+	}
+}
+
 func TestLoad_PostgreSQLMissingPassword(t *testing.T) {
 	os.Setenv("GLCMD_EMAIL", "test@example.com")
 	os.Setenv("GLCMD_PASSWORD", "testpassword")
@@ -119,11 +252,13 @@ func TestLoad_CustomValues(t *testing.T) {
 	os.Setenv("GLCMD_PASSWORD", "custompassword")
 	os.Setenv("GLCMD_API_PORT", "9090")
 	os.Setenv("GLCMD_DB_PATH", "/custom/path/db.sqlite")
+	os.Setenv("GLCMD_ATTACHMENTS_DIR", "/custom/path/attachments")
 	defer func() {
 		os.Unsetenv("GLCMD_EMAIL")
 		os.Unsetenv("GLCMD_PASSWORD")
 		os.Unsetenv("GLCMD_API_PORT")
 		os.Unsetenv("GLCMD_DB_PATH")
+		os.Unsetenv("GLCMD_ATTACHMENTS_DIR")
 	}()
 
 	cfg, err := Load()
@@ -141,6 +276,9 @@ func TestLoad_CustomValues(t *testing.T) {
 	if cfg.Database.SQLitePath != "/custom/path/db.sqlite" {
 		t.Errorf("expected SQLite path /custom/path/db.sqlite, got %s", cfg.Database.SQLitePath)
 	}
+	if cfg.API.AttachmentsDir != "/custom/path/attachments" {
+		t.Errorf("expected attachments dir /custom/path/attachments, got %s", cfg.API.AttachmentsDir)
+	}
 }
 
 func TestToPersistenceConfig(t *testing.T) {
@@ -160,3 +298,790 @@ func TestToPersistenceConfig(t *testing.T) {
 	}
 }
 
+
+func TestLoad_SyncDefaults(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if cfg.Sync.PrimaryURL != "" {
+		t.Errorf("expected empty primary URL, got %s", cfg.Sync.PrimaryURL)
+	}
+	if cfg.Sync.Interval != 5*time.Minute {
+		t.Errorf("expected sync interval 5m, got %s", cfg.Sync.Interval)
+	}
+	if cfg.Sync.Days != 7 {
+		t.Errorf("expected sync days 7, got %d", cfg.Sync.Days)
+	}
+}
+
+func TestLoad_SyncPrimaryRequiresToken(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	os.Setenv("GLCMD_SYNC_PRIMARY_URL", "https://home.example.com")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_SYNC_PRIMARY_URL")
+	}()
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected error for missing GLCMD_SYNC_TOKEN, got nil")
+	}
+}
+
+func TestLoad_AdminTokenTooShort(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	os.Setenv("GLCMD_ADMIN_TOKEN", "short")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_ADMIN_TOKEN")
+	}()
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected error for short GLCMD_ADMIN_TOKEN, got nil")
+	}
+}
+
+func TestLoad_APITokens(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_API_TOKENS")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cfg.API.Tokens) != 0 {
+		t.Errorf("expected no API tokens by default, got %d", len(cfg.API.Tokens))
+	}
+
+	os.Setenv("GLCMD_API_TOKENS", "dashboard-0123456789:read, scripts-0123456789:write")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.API.Tokens["dashboard-0123456789"] != "read" || cfg.API.Tokens["scripts-0123456789"] != "write" {
+		t.Errorf("unexpected API tokens: %v", cfg.API.Tokens)
+	}
+
+	for _, value := range []string{
+		"dashboard-0123456789",                                 // missing scope
+		"dashboard-0123456789:admin",                           // unknown scope
+		"short:read",                                           // too short
+		"dashboard-0123456789:read,dashboard-0123456789:write", // duplicate
+	} {
+		os.Setenv("GLCMD_API_TOKENS", value)
+		_, err := Load()
+		if err == nil {
+			t.Errorf("%q: expected error, got nil", value)
+			continue
+		}
+		if strings.Contains(err.Error(), "dashboard-0123456789") {
+			t.Errorf("%q: error must not contain the token: %v", value, err)
+		}
+	}
+}
+
+func TestLoad_SensorGracePeriod(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_SENSOR_GRACE_PERIOD")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Sensor.GracePeriod != 12*time.Hour {
+		t.Errorf("expected default grace period 12h, got %s", cfg.Sensor.GracePeriod)
+	}
+
+	os.Setenv("GLCMD_SENSOR_GRACE_PERIOD", "6h")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Sensor.GracePeriod != 6*time.Hour {
+		t.Errorf("expected grace period 6h, got %s", cfg.Sensor.GracePeriod)
+	}
+
+	os.Setenv("GLCMD_SENSOR_GRACE_PERIOD", "72h")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for grace period above 48h, got nil")
+	}
+}
+
+func TestLoad_MorningSummary(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_MORNING_SUMMARY_TIME")
+		os.Unsetenv("GLCMD_MORNING_SUMMARY_NIGHT")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Summary.Enabled {
+		t.Error("expected morning summary disabled by default")
+	}
+
+	os.Setenv("GLCMD_MORNING_SUMMARY_TIME", "07:30")
+	os.Setenv("GLCMD_MORNING_SUMMARY_NIGHT", "9h")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.Summary.Enabled || cfg.Summary.MorningAt != 7*time.Hour+30*time.Minute {
+		t.Errorf("expected summary at 07:30, got enabled=%v at=%s", cfg.Summary.Enabled, cfg.Summary.MorningAt)
+	}
+	if cfg.Summary.Night != 9*time.Hour {
+		t.Errorf("expected night 9h, got %s", cfg.Summary.Night)
+	}
+
+	os.Setenv("GLCMD_MORNING_SUMMARY_TIME", "7am")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for invalid summary time, got nil")
+	}
+
+	os.Setenv("GLCMD_MORNING_SUMMARY_TIME", "07:30")
+	os.Setenv("GLCMD_MORNING_SUMMARY_NIGHT", "20h")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for night above 16h, got nil")
+	}
+}
+
+func TestLoad_TargetBands(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_TARGET_BANDS")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cfg.Statistics.TargetBands) != 1 || cfg.Statistics.TargetBands[0] != (domain.TargetBand{Name: "tight", LowMgDl: 70, HighMgDl: 140}) {
+		t.Errorf("expected the tight range by default, got %+v", cfg.Statistics.TargetBands)
+	}
+
+	os.Setenv("GLCMD_TARGET_BANDS", "tight:70-140, Pregnancy:63-140")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cfg.Statistics.TargetBands) != 2 || cfg.Statistics.TargetBands[1] != (domain.TargetBand{Name: "pregnancy", LowMgDl: 63, HighMgDl: 140}) {
+		t.Errorf("unexpected bands: %+v", cfg.Statistics.TargetBands)
+	}
+
+	os.Setenv("GLCMD_TARGET_BANDS", "none")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cfg.Statistics.TargetBands) != 0 {
+		t.Errorf("expected no bands, got %+v", cfg.Statistics.TargetBands)
+	}
+
+	for _, invalid := range []string{"tight", "tight:140-70", "tight:70-abc", "tight:70-140,tight:63-140", "tight:10-140"} {
+		os.Setenv("GLCMD_TARGET_BANDS", invalid)
+		if _, err := Load(); err == nil {
+			t.Errorf("%s: expected error, got nil", invalid)
+		}
+	}
+}
+
+func TestLoad_Heartbeat(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_HEARTBEAT_URL")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Heartbeat.URL != "" {
+		t.Errorf("expected heartbeat disabled by default, got %q", cfg.Heartbeat.URL)
+	}
+
+	os.Setenv("GLCMD_HEARTBEAT_URL", "https://hc-ping.com/abc")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Heartbeat.URL != "https://hc-ping.com/abc" {
+		t.Errorf("expected heartbeat URL, got %q", cfg.Heartbeat.URL)
+	}
+
+	os.Setenv("GLCMD_HEARTBEAT_URL", "hc-ping.com/abc")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for heartbeat URL without scheme, got nil")
+	}
+}
+
+func TestLoad_Plugins(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_PLUGINS")
+		os.Unsetenv("GLCMD_PLUGIN_TIMEOUT")
+		os.Unsetenv("GLCMD_PLUGIN_CONCURRENCY")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cfg.Plugins.Commands) != 0 || cfg.Plugins.Timeout != 10*time.Second || cfg.Plugins.Concurrency != 2 {
+		t.Errorf("expected plugins disabled with defaults, got %+v", cfg.Plugins)
+	}
+
+	os.Setenv("GLCMD_PLUGINS", "/opt/glcmd/mqtt.sh, /usr/local/bin/notify")
+	os.Setenv("GLCMD_PLUGIN_TIMEOUT", "30s")
+	os.Setenv("GLCMD_PLUGIN_CONCURRENCY", "4")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cfg.Plugins.Commands) != 2 || cfg.Plugins.Commands[1] != "/usr/local/bin/notify" ||
+		cfg.Plugins.Timeout != 30*time.Second || cfg.Plugins.Concurrency != 4 {
+		t.Errorf("unexpected plugins config: %+v", cfg.Plugins)
+	}
+
+	invalid := map[string]string{
+		"GLCMD_PLUGINS":            "plugins/mqtt.sh",
+		"GLCMD_PLUGIN_TIMEOUT":     "10m",
+		"GLCMD_PLUGIN_CONCURRENCY": "0",
+	}
+	for key, value := range invalid {
+		previous := os.Getenv(key)
+		os.Setenv(key, value)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for %s=%s, got nil", key, value)
+		}
+		os.Setenv(key, previous)
+	}
+
+	os.Setenv("GLCMD_PLUGINS", "/opt/a,/opt/a")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for duplicate plugin, got nil")
+	}
+}
+
+func TestLoad_Jobs(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_JOB_WORKERS")
+		os.Unsetenv("GLCMD_JOB_RETENTION")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Jobs.Workers != 2 || cfg.Jobs.Retention != 168*time.Hour {
+		t.Errorf("expected default jobs config, got %+v", cfg.Jobs)
+	}
+
+	os.Setenv("GLCMD_JOB_WORKERS", "4")
+	os.Setenv("GLCMD_JOB_RETENTION", "24h")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Jobs.Workers != 4 || cfg.Jobs.Retention != 24*time.Hour {
+		t.Errorf("unexpected jobs config: %+v", cfg.Jobs)
+	}
+
+	invalid := map[string]string{
+		"GLCMD_JOB_WORKERS":   "17",
+		"GLCMD_JOB_RETENTION": "30m",
+	}
+	for key, value := range invalid {
+		previous := os.Getenv(key)
+		os.Setenv(key, value)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for %s=%s, got nil", key, value)
+		}
+		os.Setenv(key, previous)
+	}
+}
+
+func TestLoad_Retention(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_RETENTION_DOWNSAMPLE_DAYS")
+		os.Unsetenv("GLCMD_RETENTION_RAW_DAYS")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Retention.Enabled() {
+		t.Errorf("expected retention disabled by default, got %+v", cfg.Retention)
+	}
+
+	os.Setenv("GLCMD_RETENTION_DOWNSAMPLE_DAYS", "90")
+	os.Setenv("GLCMD_RETENTION_RAW_DAYS", "365")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.Retention.Enabled() || cfg.Retention.DownsampleDays != 90 || cfg.Retention.RawDays != 365 {
+		t.Errorf("unexpected retention config: %+v", cfg.Retention)
+	}
+
+	invalid := []struct{ downsample, raw string }{
+		{"-1", ""},
+		{"90", "30"}, // Raw measurements deleted before they are rolled up
+		{"", "365"},
+		{"90", "a year"},
+	}
+	for _, tt := range invalid {
+		os.Setenv("GLCMD_RETENTION_DOWNSAMPLE_DAYS", tt.downsample)
+		os.Setenv("GLCMD_RETENTION_RAW_DAYS", tt.raw)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for downsample %q and raw %q days, got nil", tt.downsample, tt.raw)
+		}
+	}
+}
+
+func TestLoad_Nightscout(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_NIGHTSCOUT_URL")
+		os.Unsetenv("GLCMD_NIGHTSCOUT_API_SECRET")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Nightscout.URL != "" {
+		t.Errorf("expected Nightscout upload disabled by default, got %q", cfg.Nightscout.URL)
+	}
+
+	os.Setenv("GLCMD_NIGHTSCOUT_URL", "https://ns.example.com")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for Nightscout URL without API secret, got nil")
+	}
+
+	os.Setenv("GLCMD_NIGHTSCOUT_API_SECRET", "averylongsecret")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Nightscout.URL != "https://ns.example.com" || cfg.Nightscout.APISecret != "averylongsecret" {
+		t.Errorf("unexpected Nightscout config: %+v", cfg.Nightscout)
+	}
+
+	os.Setenv("GLCMD_NIGHTSCOUT_URL", "ns.example.com")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for Nightscout URL without scheme, got nil")
+	}
+
+	os.Unsetenv("GLCMD_NIGHTSCOUT_URL")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for API secret without Nightscout URL, got nil")
+	}
+}
+
+func TestLoad_Alerts(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_ALERT_RULES")
+		os.Unsetenv("GLCMD_ALERT_HIGH_MGDL")
+		os.Unsetenv("GLCMD_ALERT_SENSOR_REMINDERS")
+		os.Unsetenv("GLCMD_ALERT_TELEGRAM_TOKEN")
+		os.Unsetenv("GLCMD_ALERT_TELEGRAM_CHAT_ID")
+		os.Unsetenv("GLCMD_ALERT_TELEGRAM_COMMANDS")
+		os.Unsetenv("GLCMD_ALERT_SMTP_HOST")
+		os.Unsetenv("GLCMD_ALERT_EMAIL_FROM")
+		os.Unsetenv("GLCMD_ALERT_EMAIL_TO")
+		os.Unsetenv("GLCMD_ALERT_SOUND")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Alerts.Enabled() {
+		t.Error("expected alert notifications disabled by default")
+	}
+	if len(cfg.Alerts.Rules.Rules) != len(alerts.Rules) || cfg.Alerts.Rules.HighMgDl != alerts.DefaultHighMgDl {
+		t.Errorf("expected all rules with default thresholds, got %+v", cfg.Alerts.Rules)
+	}
+
+	os.Setenv("GLCMD_ALERT_RULES", "low, stale,low")
+	os.Setenv("GLCMD_ALERT_HIGH_MGDL", "200")
+	os.Setenv("GLCMD_ALERT_TELEGRAM_TOKEN", "123:secret")
+	os.Setenv("GLCMD_ALERT_TELEGRAM_CHAT_ID", "42")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.Alerts.Enabled() || cfg.Alerts.TelegramChat != "42" {
+		t.Errorf("expected Telegram notifications enabled, got %+v", cfg.Alerts)
+	}
+	if got := cfg.Alerts.Rules.Rules; len(got) != 2 || got[0] != alerts.RuleLow || got[1] != alerts.RuleStale {
+		t.Errorf("expected rules [low stale], got %v", got)
+	}
+	if cfg.Alerts.Rules.HighMgDl != 200 {
+		t.Errorf("expected high threshold 200, got %d", cfg.Alerts.Rules.HighMgDl)
+	}
+
+	os.Setenv("GLCMD_ALERT_RULES", "low,hypo")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for unknown rule, got nil")
+	}
+	os.Unsetenv("GLCMD_ALERT_RULES")
+
+	os.Setenv("GLCMD_ALERT_SENSOR_REMINDERS", "48h, 12h,48h")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if got := cfg.Alerts.Rules.SensorReminders; len(got) != 2 || got[0] != 48*time.Hour || got[1] != 12*time.Hour {
+		t.Errorf("expected sensor reminders [48h 12h], got %v", got)
+	}
+	os.Setenv("GLCMD_ALERT_SENSOR_REMINDERS", "30m")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for a reminder under an hour, got nil")
+	}
+	os.Unsetenv("GLCMD_ALERT_SENSOR_REMINDERS")
+
+	os.Setenv("GLCMD_ALERT_TELEGRAM_COMMANDS", "true")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.Alerts.TelegramCommands {
+		t.Error("expected Telegram commands enabled")
+	}
+	os.Setenv("GLCMD_ALERT_TELEGRAM_CHAT_ID", "@glcmd_alerts")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for Telegram commands with a channel username, got nil")
+	}
+	os.Setenv("GLCMD_ALERT_TELEGRAM_CHAT_ID", "42")
+	os.Unsetenv("GLCMD_ALERT_TELEGRAM_COMMANDS")
+
+	os.Unsetenv("GLCMD_ALERT_TELEGRAM_CHAT_ID")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for Telegram token without chat ID, got nil")
+	}
+	os.Unsetenv("GLCMD_ALERT_TELEGRAM_TOKEN")
+
+	os.Setenv("GLCMD_ALERT_SMTP_HOST", "smtp.example.com")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for SMTP host without addresses, got nil")
+	}
+	os.Setenv("GLCMD_ALERT_EMAIL_FROM", "glcmd@example.com")
+	os.Setenv("GLCMD_ALERT_EMAIL_TO", "me@example.com, carer@example.com")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Alerts.SMTP.Port != 587 || len(cfg.Alerts.SMTP.To) != 2 {
+		t.Errorf("unexpected SMTP config: %+v", cfg.Alerts.SMTP)
+	}
+	os.Unsetenv("GLCMD_ALERT_SMTP_HOST")
+
+	os.Setenv("GLCMD_ALERT_SOUND", " aplay -q /home/pi/alarm.wav ")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.Alerts.Enabled() || cfg.Alerts.Sound != "aplay -q /home/pi/alarm.wav" {
+		t.Errorf("expected the sound channel enabled, got %q", cfg.Alerts.Sound)
+	}
+}
+
+func TestLoad_AlertEscalation(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_ALERT_RULES")
+		os.Unsetenv("GLCMD_ALERT_TELEGRAM_TOKEN")
+		os.Unsetenv("GLCMD_ALERT_TELEGRAM_CHAT_ID")
+		os.Unsetenv("GLCMD_ALERT_ESCALATE_AFTER")
+		os.Unsetenv("GLCMD_ALERT_ESCALATION_TELEGRAM_CHAT_ID")
+		os.Unsetenv("GLCMD_ALERT_ESCALATION_WEBHOOK_URL")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Alerts.Escalates() {
+		t.Error("expected escalation disabled by default")
+	}
+
+	os.Setenv("GLCMD_ALERT_ESCALATE_AFTER", "10m")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for an escalation delay without channel, got nil")
+	}
+
+	os.Setenv("GLCMD_ALERT_ESCALATION_TELEGRAM_CHAT_ID", "43")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for an escalation chat without bot token, got nil")
+	}
+
+	os.Setenv("GLCMD_ALERT_TELEGRAM_TOKEN", "123:secret")
+	os.Setenv("GLCMD_ALERT_TELEGRAM_CHAT_ID", "42")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.Alerts.Escalates() || cfg.Alerts.EscalateAfter != 10*time.Minute || cfg.Alerts.EscalationTelegramChat != "43" {
+		t.Errorf("expected escalation to chat 43 after 10m, got %+v", cfg.Alerts)
+	}
+
+	os.Unsetenv("GLCMD_ALERT_ESCALATE_AFTER")
+	os.Setenv("GLCMD_ALERT_ESCALATION_WEBHOOK_URL", "https://ntfy.sh/partner")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Alerts.EscalateAfter != alerts.DefaultEscalateAfter || cfg.Alerts.EscalationWebhookURL == "" {
+		t.Errorf("expected the default escalation delay with a webhook, got %+v", cfg.Alerts)
+	}
+
+	os.Setenv("GLCMD_ALERT_ESCALATE_AFTER", "3h")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for an escalation delay over 2h, got nil")
+	}
+	os.Unsetenv("GLCMD_ALERT_ESCALATE_AFTER")
+
+	os.Setenv("GLCMD_ALERT_RULES", "high,stale")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for escalation without the low rule, got nil")
+	}
+}
+
+func TestLoad_FaultInjection(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_FAULT_INJECT")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Faults.Enabled() {
+		t.Errorf("expected fault injection disabled by default, got %+v", cfg.Faults)
+	}
+
+	os.Setenv("GLCMD_FAULT_INJECT", "upstream=0.2,dblock=0.05")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Faults.UpstreamRate != 0.2 || cfg.Faults.DBLockRate != 0.05 {
+		t.Errorf("unexpected faults: %+v", cfg.Faults)
+	}
+
+	os.Setenv("GLCMD_FAULT_INJECT", "upstream=2")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for rate above 1, got nil")
+	}
+}
+
+func TestLoad_DatabaseBackend(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_DB_BACKEND")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Database.Backend != "gorm" {
+		t.Errorf("expected default backend gorm, got %s", cfg.Database.Backend)
+	}
+
+	os.Setenv("GLCMD_DB_BACKEND", "sql")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Database.ToPersistenceConfig().Backend != "sql" {
+		t.Errorf("expected backend sql, got %s", cfg.Database.ToPersistenceConfig().Backend)
+	}
+
+	os.Setenv("GLCMD_DB_BACKEND", "sqlc")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unknown GLCMD_DB_BACKEND, got nil")
+	}
+}
+
+func TestLoad_DBSecureFiles(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_DB_SECURE_FILES")
+	}()
+
+	os.Setenv("GLCMD_DB_SECURE_FILES", "true")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.Database.SecureFiles || !cfg.Database.ToPersistenceConfig().SecureFiles {
+		t.Error("expected secure files enabled")
+	}
+
+	os.Setenv("GLCMD_DB_SECURE_FILES", "sometimes")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for invalid GLCMD_DB_SECURE_FILES, got nil")
+	}
+}
+
+func TestLoad_LowMemory(t *testing.T) {
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD", "testpassword")
+	os.Setenv("GLCMD_LOW_MEM", "1")
+	os.Setenv("GLCMD_DB_TYPE", "postgres")
+	os.Setenv("GLCMD_DB_PASSWORD", "secret")
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_LOW_MEM")
+		os.Unsetenv("GLCMD_DB_TYPE")
+		os.Unsetenv("GLCMD_DB_PASSWORD")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if !cfg.Runtime.LowMemory || !cfg.Database.LowMemory {
+		t.Error("expected low memory mode enabled")
+	}
+	if cfg.Runtime.EventBufferSize != lowMemEventBufferSize {
+		t.Errorf("expected event buffer %d, got %d", lowMemEventBufferSize, cfg.Runtime.EventBufferSize)
+	}
+	if os.Getenv("GOMEMLIMIT") == "" && cfg.Runtime.MemoryLimit != lowMemMemoryLimit {
+		t.Errorf("expected memory limit %d, got %d", lowMemMemoryLimit, cfg.Runtime.MemoryLimit)
+	}
+	if cfg.Database.MaxOpenConns != lowMemMaxOpenConns || cfg.Database.MaxIdleConns != lowMemMaxIdleConns {
+		t.Errorf("expected pool %d/%d, got %d/%d", lowMemMaxOpenConns, lowMemMaxIdleConns,
+			cfg.Database.MaxOpenConns, cfg.Database.MaxIdleConns)
+	}
+
+	os.Setenv("GLCMD_LOW_MEM", "maybe")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for invalid GLCMD_LOW_MEM, got nil")
+	}
+}
+
+func TestLoad_SecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	secret := func(name, value string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(value), 0600); err != nil {
+			t.Fatalf("failed to write secret: %v", err)
+		}
+		return path
+	}
+
+	os.Setenv("GLCMD_EMAIL", "test@example.com")
+	os.Setenv("GLCMD_PASSWORD_FILE", secret("password", "s3cret\n"))
+	os.Setenv("GLCMD_ADMIN_TOKEN_FILE", secret("admin", "0123456789abcdef0123"))
+	os.Setenv("GLCMD_SYNC_TOKEN_FILE", secret("sync", "sync-token\r\n"))
+	os.Setenv("GLCMD_DB_TYPE", "postgres")
+	os.Setenv("GLCMD_DB_PASSWORD_FILE", secret("db", "db-password"))
+	defer func() {
+		os.Unsetenv("GLCMD_EMAIL")
+		os.Unsetenv("GLCMD_PASSWORD")
+		os.Unsetenv("GLCMD_PASSWORD_FILE")
+		os.Unsetenv("GLCMD_ADMIN_TOKEN_FILE")
+		os.Unsetenv("GLCMD_SYNC_TOKEN_FILE")
+		os.Unsetenv("GLCMD_DB_TYPE")
+		os.Unsetenv("GLCMD_DB_PASSWORD_FILE")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Credentials.Password != "s3cret" {
+		t.Errorf("expected password from file without newline, got %q", cfg.Credentials.Password)
+	}
+	if cfg.API.AdminToken != "0123456789abcdef0123" || cfg.Sync.Token != "sync-token" {
+		t.Errorf("unexpected tokens: admin %q, sync %q", cfg.API.AdminToken, cfg.Sync.Token)
+	}
+	if cfg.Database.Password != "db-password" {
+		t.Errorf("expected database password from file, got %q", cfg.Database.Password)
+	}
+
+	// Both the variable and its file
+	os.Setenv("GLCMD_PASSWORD", "other")
+	if _, err := Load(); err == nil {
+		t.Error("expected error when GLCMD_PASSWORD and GLCMD_PASSWORD_FILE are both set")
+	}
+	os.Unsetenv("GLCMD_PASSWORD")
+
+	// Missing file
+	os.Setenv("GLCMD_PASSWORD_FILE", filepath.Join(dir, "missing"))
+	if _, err := Load(); err == nil {
+		t.Error("expected error for a missing secret file")
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultEnvFile is the configuration file glcore reads from its working
+// directory, and the one written by glcore init.
+const DefaultEnvFile = "glcore.env"
+
+// LoadEnvFile sets the variables of an env file (KEY=VALUE lines, # comments)
+// that are not already set: the environment takes precedence over the file.
+// Values may be double-quoted, as written by WriteEnvFile. The file uses the
+// syntax of systemd's EnvironmentFile, so one file serves both.
+// Returns an error wrapping os.ErrNotExist if the file does not exist.
+func LoadEnvFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(text, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("%s:%d: expected KEY=VALUE", path, line)
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return fmt.Errorf("%s:%d: invalid quoted value for %s", path, line, key)
+			}
+			value = unquoted
+		}
+
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// WriteEnvFile writes values as an env file readable by LoadEnvFile and
+// systemd, sorted by key. The file holds credentials, so it is only readable
+// by its owner.
+func WriteEnvFile(path, header string, values map[string]string) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	if header != "" {
+		for _, line := range strings.Split(strings.TrimSpace(header), "\n") {
+			sb.WriteString("# " + line + "\n")
+		}
+	}
+	for _, key := range keys {
+		fmt.Fprintf(&sb, "%s=%s\n", key, strconv.Quote(values[key]))
+	}
+
+	if err := os.WriteFile(path, []byte(sb.String()), 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(path, 0600)
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvFile_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultEnvFile)
+	values := map[string]string{
+		"GLCMD_TEST_EMAIL":    "user@example.com",
+		"GLCMD_TEST_PASSWORD": `pa ss"w\rd#1`,
+		"GLCMD_TEST_PORT":     "9090",
+	}
+	if err := WriteEnvFile(path, "Written by a test", values); err != nil {
+		t.Fatalf("WriteEnvFile failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	// The environment takes precedence over the file
+	t.Setenv("GLCMD_TEST_PORT", "8081")
+	for key := range values {
+		if key != "GLCMD_TEST_PORT" {
+			t.Setenv(key, "")
+			os.Unsetenv(key)
+		}
+	}
+
+	if err := LoadEnvFile(path); err != nil {
+		t.Fatalf("LoadEnvFile failed: %v", err)
+	}
+	if got := os.Getenv("GLCMD_TEST_EMAIL"); got != values["GLCMD_TEST_EMAIL"] {
+		t.Errorf("expected email %q, got %q", values["GLCMD_TEST_EMAIL"], got)
+	}
+	if got := os.Getenv("GLCMD_TEST_PASSWORD"); got != values["GLCMD_TEST_PASSWORD"] {
+		t.Errorf("expected password %q, got %q", values["GLCMD_TEST_PASSWORD"], got)
+	}
+	if got := os.Getenv("GLCMD_TEST_PORT"); got != "8081" {
+		t.Errorf("expected the environment port 8081, got %q", got)
+	}
+}
+
+func TestLoadEnvFile_Syntax(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "plain.env")
+	os.WriteFile(path, []byte("# comment\n\nexport GLCMD_TEST_PLAIN = value with spaces\n"), 0600)
+	t.Setenv("GLCMD_TEST_PLAIN", "")
+	os.Unsetenv("GLCMD_TEST_PLAIN")
+	if err := LoadEnvFile(path); err != nil {
+		t.Fatalf("LoadEnvFile failed: %v", err)
+	}
+	if got := os.Getenv("GLCMD_TEST_PLAIN"); got != "value with spaces" {
+		t.Errorf("expected unquoted value, got %q", got)
+	}
+
+	for name, content := range map[string]string{
+		"nokey.env": "just a line\n",
+		"quote.env": "GLCMD_TEST_BAD=\"unterminated\n",
+		"empty.env": "=value\n",
+	} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0600)
+		if err := LoadEnvFile(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if err := LoadEnvFile(filepath.Join(dir, "missing.env")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist for a missing file, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// secretEnv returns the value of a secret variable, read either from name or
+// from the file named by name_FILE, as mounted by Docker and Kubernetes
+// secrets. The value then never appears in the environment. Trailing
+// newlines of the file are removed. Setting both variables is an error.
+func secretEnv(name string) (string, error) {
+	value := os.Getenv(name)
+	path := os.Getenv(name + "_FILE")
+	if path == "" {
+		return value, nil
+	}
+	if value != "" {
+		return "", fmt.Errorf("%s and %s_FILE are both set (use only one)", name, name)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s_FILE: %w", name, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
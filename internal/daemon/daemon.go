@@ -13,11 +13,20 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/R4yL-dev/glcmd/internal/domain"
 	"github.com/R4yL-dev/glcmd/internal/libreclient"
 	"github.com/R4yL-dev/glcmd/internal/logger"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
 	"github.com/R4yL-dev/glcmd/internal/service"
 	"github.com/R4yL-dev/glcmd/internal/utils/timeparser"
 )
@@ -30,6 +39,39 @@ const (
 	maxPollRetries      = 4                // Max retries before falling back to full interval
 )
 
+// termsRetryInterval is how often the daemon tries to log in while LibreView
+// requires a step first, in case it was completed in the LibreLinkUp app.
+const termsRetryInterval = 15 * time.Minute
+
+// ErrNoPendingTerms is returned by AcceptTerms when LibreView requires no
+// document to be accepted.
+var ErrNoPendingTerms = errors.New("no LibreView terms to accept")
+
+// Account names reported in the health status
+const (
+	accountPrimary   = "primary"
+	accountSecondary = "secondary"
+)
+
+// account is a LibreLinkUp follower account the daemon can authenticate with.
+type account struct {
+	name     string
+	email    string
+	password string
+}
+
+// fetchCycle identifies the poll that produced a measurement, so stored rows
+// can be traced back to the LibreView response they came from.
+type fetchCycle struct {
+	id string
+	at time.Time // When the response was received
+}
+
+// newFetchCycle starts a fetch cycle for a response received now.
+func newFetchCycle() fetchCycle {
+	return fetchCycle{id: uuid.New().String(), at: time.Now().UTC()}
+}
+
 // Daemon represents the background service that continuously fetches
 // glucose data from the LibreView API.
 //
@@ -42,23 +84,60 @@ type Daemon struct {
 	glucoseService       service.GlucoseService
 	sensorService        service.SensorService
 	configService        service.ConfigService
+	modeService          service.ModeService
+	alertService         service.AlertService
+	upstreamService      service.UpstreamService
+	sessionService       service.SessionService // Stores the LibreView session across restarts (nil disables warm starts)
+	heartbeat            func() // Called after each successful fetch (nil = no external monitoring)
 	ctx                  context.Context
 	cancel               context.CancelFunc
 	timer                *time.Timer
 	client               *libreclient.Client
-	email                string
-	password             string
+	accounts             []account // Primary account first
+	activeAccount        int       // Index of the account in use
+	failovers            int       // Number of switches between accounts
 	token                string
+	tokenExpiresAt       time.Time // Zero if unknown
 	accountID            string
-	patientID            string
+	patientID            string    // Patient alerts, targets and health follow
+	selectedPatientID    string    // Patient to follow (empty = first connection)
+	multiPatient         bool      // Store the readings of all the connections
+	patientsAssigned     bool      // Rows stored without a patient were assigned to patientID
+	patientsSeen         map[string]bool // Other patients whose history has been fetched
 	consecutiveErrors    int       // Counter for consecutive fetch errors
 	maxConsecutiveErrors int       // Max allowed consecutive errors before alerting
 	lastFetchError       string    // Last fetch error message (empty if no error)
 	lastFetchTime        time.Time // Last successful fetch time
 	startTime            time.Time // Daemon start time
 	lastTargets          *domain.GlucoseTargets // Cache to avoid redundant saves
+	lastDevice           *domain.DeviceInfo     // Cache to avoid redundant saves
 	sensorExpiresAt      time.Time              // Expiration time of the current sensor
+	sensorGracePeriod    time.Duration          // How long the sensor may keep reporting after expiry
+	sensorLastReadingAt  time.Time              // Timestamp of the last current measurement
+	sensorStatus         domain.SensorStatus    // Last observed expiry status, to alert on transitions only
 	retryCount           int                    // Consecutive retry counter for duplicates
+	glucoseLow           bool                   // Last reading was below the low alert threshold
+	glucoseFalling       bool                   // Last reading was falling fast enough to alert
+
+	connMu     sync.Mutex            // Guards the connection details, read by the API
+	connection domain.ConnectionInfo // Zero until the first successful fetch
+	delayTotal time.Duration         // Sum of the delays of new current readings
+	delayCount int                   // Number of delays in delayTotal
+
+	fetchStats *fetchRecorder // Fetch cycle counters, read by the API
+
+	conns           *connTracker      // Connections to LibreView, closed by the watchdog (nil with SetTransport)
+	transport       http.RoundTripper // Transport set with SetTransport (nil = tracked default)
+	fetchStartedAt  atomic.Int64      // Start of the fetch cycle in flight, in Unix nanoseconds (0 = none)
+	watchdogHandled int64             // fetchStartedAt of the last cycle aborted by the watchdog
+	clientStale     atomic.Bool       // The watchdog aborted a fetch: recreate the client once it returns
+
+	acceptTerms     bool          // Accept the documents LibreView requires at login
+	acceptRequested atomic.Bool   // Accept the pending document at the next login (AcceptTerms)
+	termsWake       chan struct{} // Signaled by AcceptTerms to log in right away
+	termsMu         sync.Mutex    // Guards pendingTerms, read by the API
+	pendingTerms    string        // Step LibreView requires before a login succeeds (empty = none)
+	termsCheckedAt  time.Time     // Last login refused for pendingTerms
 }
 
 // New creates a new Daemon instance.
@@ -67,8 +146,13 @@ type Daemon struct {
 //   - glucoseService: Service for glucose measurement business logic
 //   - sensorService: Service for sensor management business logic
 //   - configService: Service for configuration management
+//   - modeService: Service for the activity mode glucose alerts are evaluated in
+//   - alertService: Service recording fired alerts (nil disables the alert history)
+//   - upstreamService: Service tracking LibreView availability (nil disables outage tracking)
+//   - heartbeat: Called after each successful fetch cycle, must not block (nil disables it)
 //   - email: LibreView email for authentication
 //   - password: LibreView password for authentication
+//   - secondaryEmail, secondaryPassword: Follower account to fail over to (empty disables failover)
 //
 // The daemon is created with a background context that can be cancelled
 // via the Stop() method for graceful shutdown.
@@ -76,8 +160,14 @@ func New(
 	glucoseService service.GlucoseService,
 	sensorService service.SensorService,
 	configService service.ConfigService,
+	modeService service.ModeService,
+	alertService service.AlertService,
+	upstreamService service.UpstreamService,
+	heartbeat func(),
 	email string,
 	password string,
+	secondaryEmail string,
+	secondaryPassword string,
 ) (*Daemon, error) {
 	if email == "" {
 		return nil, fmt.Errorf("email cannot be empty")
@@ -86,45 +176,140 @@ func New(
 		return nil, fmt.Errorf("password cannot be empty")
 	}
 
+	accounts := []account{{name: accountPrimary, email: email, password: password}}
+	if secondaryEmail != "" {
+		accounts = append(accounts, account{name: accountSecondary, email: secondaryEmail, password: secondaryPassword})
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
+	conns := newConnTracker()
 
 	return &Daemon{
 		glucoseService:       glucoseService,
 		sensorService:        sensorService,
 		configService:        configService,
+		modeService:          modeService,
+		alertService:         alertService,
+		upstreamService:      upstreamService,
+		heartbeat:            heartbeat,
 		ctx:                  ctx,
 		cancel:               cancel,
-		client:               libreclient.NewClient(nil),
-		email:                email,
-		password:             password,
+		client:               libreclient.NewClient(newHTTPClient(conns)),
+		conns:                conns,
+		accounts:             accounts,
 		maxConsecutiveErrors: 5, // Alert after 5 consecutive errors
 		startTime:            time.Now(),
+		sensorGracePeriod:    sensorService.GracePeriod(),
+		patientsSeen:         make(map[string]bool),
+		fetchStats:           newFetchRecorder(),
+		termsWake:            make(chan struct{}, 1),
 	}, nil
 }
 
+// SetPatients selects the patient to follow among the connections of the
+// account (empty = the first one) and whether the readings of the other
+// patients are stored too. Must be called before Run.
+func (d *Daemon) SetPatients(patientID string, multiPatient bool) {
+	d.selectedPatientID = patientID
+	d.multiPatient = multiPatient
+}
+
+// SetSessionService enables warm starts: the LibreView session is stored,
+// encrypted, and reused after a restart while valid instead of logging in
+// again. Must be called before Run.
+func (d *Daemon) SetSessionService(sessionService service.SessionService) {
+	d.sessionService = sessionService
+}
+
+// SetAcceptTerms makes the daemon accept the documents (terms of use, privacy
+// policy) LibreView requires before a login succeeds, instead of waiting for
+// them to be accepted in the LibreLinkUp app or with AcceptTerms. Must be
+// called before Run.
+func (d *Daemon) SetAcceptTerms(accept bool) {
+	d.acceptTerms = accept
+}
+
+// AcceptTerms accepts the document LibreView requires at the next login,
+// attempted right away. Returns ErrNoPendingTerms when none is required.
+func (d *Daemon) AcceptTerms() error {
+	if d.PendingTerms() == "" {
+		return ErrNoPendingTerms
+	}
+	d.acceptRequested.Store(true)
+	select {
+	case d.termsWake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// PendingTerms returns the step LibreView requires before a login succeeds
+// ("tou", "pp", ...), or "" when none.
+func (d *Daemon) PendingTerms() string {
+	d.termsMu.Lock()
+	defer d.termsMu.Unlock()
+	return d.pendingTerms
+}
+
+// setPendingTerms records the step LibreView requires ("" = none).
+func (d *Daemon) setPendingTerms(step string) {
+	d.termsMu.Lock()
+	defer d.termsMu.Unlock()
+	d.pendingTerms = step
+}
+
 // Run starts the daemon's main loop.
 //
 // This method blocks until the daemon is stopped via Stop() or an
 // unrecoverable error occurs.
 //
 // The main loop:
-//   - Authenticates with LibreView API
+//   - Authenticates with LibreView API, or reuses the stored session
 //   - Performs an initial fetch to populate historical data (12h)
 //   - Polls every ~61s (1m measurement cadence + 1s safety buffer)
 //   - Waits for context cancellation to stop gracefully
 //
 // Returns an error if the daemon cannot start or encounters a fatal error.
 func (d *Daemon) Run() error {
-	// Step 1: Authenticate
-	authStart := time.Now()
-	if err := d.authenticate(); err != nil {
-		return fmt.Errorf("authentication failed: %w", err)
+	// Step 1: Authenticate, unless the stored session is still valid
+	restored := d.restoreSession()
+	if !restored {
+		authStart := time.Now()
+		if err := d.authenticateOrWait(); err != nil {
+			if d.ctx.Err() != nil {
+				return nil
+			}
+			switched, failoverErr := d.failover(err)
+			if !switched {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+			if failoverErr != nil {
+				return fmt.Errorf("authentication failed with all accounts: %w", failoverErr)
+			}
+		}
+		slog.Info("authenticated", "duration", time.Since(authStart))
 	}
-	slog.Info("authenticated", "duration", time.Since(authStart))
 
 	// Step 2: Initial fetch (historical data from /graph)
-	if err := d.initialFetch(); err != nil {
-		return fmt.Errorf("initial fetch failed: %w", err)
+	err := d.initialFetch()
+	var authErr *libreclient.AuthError
+	if restored && errors.As(err, &authErr) {
+		// The stored token was revoked: log in as on a cold start
+		slog.Warn("stored LibreView session rejected, authenticating")
+		d.forgetSession()
+		if err = d.authenticateOrWait(); err == nil {
+			err = d.initialFetch()
+		} else if d.ctx.Err() != nil {
+			return nil
+		}
+	}
+	if err != nil {
+		if switched, failoverErr := d.failover(err); !switched || failoverErr != nil {
+			return fmt.Errorf("initial fetch failed: %w", err)
+		}
+		if err := d.initialFetch(); err != nil {
+			return fmt.Errorf("initial fetch failed: %w", err)
+		}
 	}
 
 	// Step 3: Start polling timer
@@ -134,12 +319,20 @@ func (d *Daemon) Run() error {
 
 	slog.Info("ready", "nextPollIn", initialWait)
 
+	go d.watchdog()
+
 	// Step 4: Main loop - fetch and schedule next poll
 	for {
 		select {
 		case <-d.timer.C:
 			start := time.Now()
+			d.fetchStartedAt.Store(start.UnixNano())
 			inserted, err := d.fetch()
+			d.fetchStartedAt.Store(0)
+			d.fetchStats.recordCycle(start, time.Since(start), err)
+			if d.clientStale.Swap(false) {
+				d.resetClient()
+			}
 			if err != nil {
 				d.consecutiveErrors++
 				d.lastFetchError = err.Error()
@@ -148,6 +341,7 @@ func (d *Daemon) Run() error {
 					"error", err,
 					"duration", time.Since(start),
 				)
+				d.recordUpstreamFailure(err)
 
 				// Circuit breaker: alert after max consecutive errors
 				if d.consecutiveErrors >= d.maxConsecutiveErrors {
@@ -167,12 +361,23 @@ func (d *Daemon) Run() error {
 				d.consecutiveErrors = 0
 				d.lastFetchError = ""
 				d.lastFetchTime = time.Now()
+				d.recordUpstreamSuccess()
 
 				slog.Info("measurement fetched", "inserted", inserted, "duration", duration)
 
+				if d.heartbeat != nil {
+					d.heartbeat()
+				}
+
 				d.scheduleNextPoll(inserted)
 			}
 
+			d.checkSensorExpiry()
+
+		case <-d.termsWake:
+			// Accept the pending terms at the re-authentication of the next fetch
+			d.timer.Reset(0)
+
 		case <-d.ctx.Done():
 			return nil
 		}
@@ -180,7 +385,7 @@ func (d *Daemon) Run() error {
 }
 
 // GetHealthStatus returns the current health status of the daemon.
-// This is used by the healthcheck HTTP endpoint.
+// This is used by the /health endpoint of the API server.
 func (d *Daemon) GetHealthStatus() HealthStatus {
 	status := "healthy"
 
@@ -199,12 +404,20 @@ func (d *Daemon) GetHealthStatus() HealthStatus {
 		status = "degraded"
 	}
 
-	// Check sensor expiration: degrade if sensor is expired (but don't upgrade from unhealthy)
-	sensorExpired := !d.sensorExpiresAt.IsZero() && time.Now().After(d.sensorExpiresAt)
+	// Check sensor expiration: degrade if sensor is expired (but don't upgrade from unhealthy).
+	// A sensor still reporting within its grace period is not considered expired.
+	sensorStatus := d.sensorExpiryStatus(time.Now())
+	sensorExpired := sensorStatus == domain.SensorStatusStopped
 	if sensorExpired && status == "healthy" {
 		status = "degraded"
 	}
 
+	// No data can be fetched until the pending step is completed
+	termsRequired := d.PendingTerms()
+	if termsRequired != "" {
+		status = "unhealthy"
+	}
+
 	return HealthStatus{
 		Status:            status,
 		Timestamp:         time.Now(),
@@ -214,11 +427,24 @@ func (d *Daemon) GetHealthStatus() HealthStatus {
 		LastFetchTime:     d.lastFetchTime,
 		DataFresh:         dataFresh,
 		SensorExpired:     sensorExpired,
+		SensorInGrace:     sensorStatus == domain.SensorStatusGrace,
+		Mode:              d.currentMode(),
+		Account:           d.activeAccountName(),
+		Failovers:         d.failovers,
+		TermsRequired:     termsRequired,
 	}
 }
 
+// activeAccountName returns the name of the account in use ("" before New).
+func (d *Daemon) activeAccountName() string {
+	if len(d.accounts) == 0 {
+		return ""
+	}
+	return d.accounts[d.activeAccount].name
+}
+
 // HealthStatus represents the daemon's health status.
-// This is exported for use by the healthcheck package.
+// This is exported for use by the API server.
 type HealthStatus struct {
 	Status            string    `json:"status"`
 	Timestamp         time.Time `json:"timestamp"`
@@ -229,6 +455,133 @@ type HealthStatus struct {
 	DatabaseConnected bool      `json:"databaseConnected"`
 	DataFresh         bool      `json:"dataFresh"`
 	SensorExpired     bool      `json:"sensorExpired"`
+	SensorInGrace     bool      `json:"sensorInGrace"`
+
+	// Mode is the activity mode and the alert thresholds in effect
+	Mode *domain.ModeStatus `json:"mode,omitempty"`
+
+	// Account is the LibreLinkUp account in use (primary or secondary) and
+	// Failovers the number of switches between accounts since startup
+	Account   string `json:"account,omitempty"`
+	Failovers int    `json:"failovers"`
+
+	// TermsRequired is the step LibreView requires before the account can
+	// log in ("tou" for new terms of use, "pp" for a new privacy policy):
+	// no data is fetched until it is completed
+	TermsRequired string `json:"termsRequired,omitempty"`
+}
+
+// currentMode returns the current activity mode, or nil without mode service.
+func (d *Daemon) currentMode() *domain.ModeStatus {
+	if d.modeService == nil {
+		return nil
+	}
+	return d.modeService.Current()
+}
+
+// checkGlucoseAlerts evaluates a current measurement against the thresholds
+// of the current mode and logs alerts when a condition starts.
+func (d *Daemon) checkGlucoseAlerts(m *domain.GlucoseMeasurement) {
+	mode := d.currentMode()
+	if mode == nil {
+		return
+	}
+
+	low := mode.Thresholds.IsLow(m)
+	if low && !d.glucoseLow {
+		slog.Warn("low glucose alert",
+			"valueMgDl", m.ValueInMgPerDl,
+			"thresholdMgDl", mode.Thresholds.LowMgDl,
+			"mode", mode.Mode,
+		)
+		d.recordAlert(domain.AlertTypeLow, m, mode)
+	}
+	d.glucoseLow = low
+
+	falling := mode.Thresholds.IsFalling(m)
+	if falling && !d.glucoseFalling {
+		slog.Warn("falling glucose alert",
+			"valueMgDl", m.ValueInMgPerDl,
+			"trendArrow", *m.TrendArrow,
+			"mode", mode.Mode,
+		)
+		d.recordAlert(domain.AlertTypeFalling, m, mode)
+	}
+	d.glucoseFalling = falling
+}
+
+// recordAlert stores a fired alert in the alert history.
+func (d *Daemon) recordAlert(alertType string, m *domain.GlucoseMeasurement, mode *domain.ModeStatus) {
+	if d.alertService == nil {
+		return
+	}
+
+	alert := &domain.Alert{
+		FiredAt:       m.Timestamp,
+		Type:          alertType,
+		ValueMgDl:     m.ValueInMgPerDl,
+		TrendArrow:    m.TrendArrow,
+		ThresholdMgDl: mode.Thresholds.LowMgDl,
+		Mode:          mode.Mode,
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, 5*time.Second)
+	defer cancel()
+
+	if err := d.alertService.RecordAlert(ctx, alert); err != nil {
+		slog.Warn("failed to record alert", "type", alertType, "error", err)
+	}
+}
+
+// sensorExpiryStatus returns the status of the current sensor at now.
+// Returns an empty status when no sensor has been seen yet.
+func (d *Daemon) sensorExpiryStatus(now time.Time) domain.SensorStatus {
+	if d.sensorExpiresAt.IsZero() {
+		return ""
+	}
+
+	sensor := &domain.SensorConfig{ExpiresAt: d.sensorExpiresAt}
+	if !d.sensorLastReadingAt.IsZero() {
+		lastReadingAt := d.sensorLastReadingAt
+		sensor.LastMeasurementAt = &lastReadingAt
+	}
+	return sensor.StatusAt(now, d.sensorGracePeriod)
+}
+
+// checkSensorExpiry logs sensor expiry transitions once.
+// An expired sensor that keeps reporting is only flagged as expired when its
+// readings stop or the grace period lapses.
+func (d *Daemon) checkSensorExpiry() {
+	status := d.sensorExpiryStatus(time.Now())
+	if status == d.sensorStatus {
+		return
+	}
+	d.sensorStatus = status
+
+	switch status {
+	case domain.SensorStatusGrace:
+		slog.Info("sensor expired, still reporting during grace period",
+			"expiresAt", d.sensorExpiresAt,
+			"graceEndsAt", d.sensorExpiresAt.Add(d.sensorGracePeriod),
+		)
+	case domain.SensorStatusStopped:
+		slog.Warn("sensor expired, replace it",
+			"expiresAt", d.sensorExpiresAt,
+			"lastReadingAt", d.sensorLastReadingAt,
+		)
+	}
+}
+
+// SetTransport replaces the HTTP transport used to reach LibreView, e.g. to
+// inject faults. The watchdog cannot close the connections of such a
+// transport: it only recreates the client around it. Must be called before Run.
+func (d *Daemon) SetTransport(transport http.RoundTripper) {
+	d.transport = transport
+	d.conns = nil
+	d.client = libreclient.NewClient(&http.Client{
+		Timeout:   libreclient.DefaultTimeout,
+		Transport: transport,
+	})
 }
 
 // Stop initiates a graceful shutdown of the daemon.
@@ -251,21 +604,238 @@ func (d *Daemon) authenticate() error {
 	ctx, cancel := context.WithTimeout(d.ctx, 30*time.Second)
 	defer cancel()
 
-	token, userID, accountID, err := d.client.Authenticate(ctx, d.email, d.password)
+	acc := d.accounts[d.activeAccount]
+	session, err := d.client.Login(ctx, acc.email, acc.password)
+	var terms *libreclient.TermsError
+	if errors.As(err, &terms) {
+		session, err = d.completeTerms(ctx, acc, terms)
+	}
 	if err != nil {
-		slog.Error("authentication failed", "error", err)
+		slog.Error("authentication failed", "account", acc.name, "error", err)
 		return fmt.Errorf("authentication failed: %w", err)
 	}
+	d.setPendingTerms("")
 
-	d.token = token
-	d.accountID = accountID
+	d.token = session.Token
+	d.tokenExpiresAt = session.ExpiresAt
+	d.accountID = session.AccountID
 	// userID is not the same as patientID, we'll get patientID from /connections
-	_ = userID
 
-	slog.Debug("authentication successful", "accountID", logger.RedactSensitive(accountID))
+	slog.Debug("authentication successful", "accountID", logger.RedactSensitive(d.accountID))
+	d.saveSession()
 	return nil
 }
 
+// completeTerms accepts the documents LibreView requires before issuing a
+// session, when enabled (SetAcceptTerms) or requested (AcceptTerms).
+// Otherwise the step is recorded for the health status and returned.
+func (d *Daemon) completeTerms(ctx context.Context, acc account, terms *libreclient.TermsError) (*libreclient.Session, error) {
+	accept := d.acceptTerms || d.acceptRequested.Swap(false)
+	// LibreView asks for each document in turn (terms of use, then privacy policy)
+	for range 5 {
+		if !accept || !terms.Acceptable {
+			break
+		}
+
+		slog.Info("accepting the new LibreView document", "account", acc.name, "document", terms.Document())
+		session, err := d.client.AcceptTerms(ctx, terms)
+		if !errors.As(err, &terms) {
+			return session, err
+		}
+	}
+
+	d.setPendingTerms(terms.Step)
+	d.termsCheckedAt = time.Now()
+	if terms.Acceptable {
+		slog.Error("LibreView requires accepting new terms: accept them in the LibreLinkUp app, with POST /v1/admin/upstream/accept-terms or by setting GLCMD_ACCEPT_TERMS=true",
+			"account", acc.name, "document", terms.Document())
+	}
+	return nil, terms
+}
+
+// authenticateOrWait authenticates, waiting while LibreView requires a step
+// first: a login is attempted every termsRetryInterval, in case the step was
+// completed in the LibreLinkUp app, and right away after AcceptTerms.
+// Returns the context error once the daemon is stopped.
+func (d *Daemon) authenticateOrWait() error {
+	for {
+		err := d.authenticate()
+		var terms *libreclient.TermsError
+		if !errors.As(err, &terms) {
+			return err
+		}
+
+		slog.Warn("waiting for the LibreView step to be completed", "step", terms.Step, "retryIn", termsRetryInterval)
+		select {
+		case <-time.After(termsRetryInterval):
+		case <-d.termsWake:
+		case <-d.ctx.Done():
+			return d.ctx.Err()
+		}
+	}
+}
+
+// restoreSession reuses the stored session of the active account, if any and
+// still valid. Returns false when the daemon must log in.
+func (d *Daemon) restoreSession() bool {
+	if d.sessionService == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, 5*time.Second)
+	defer cancel()
+
+	acc := d.accounts[d.activeAccount]
+	creds, err := d.sessionService.Load(ctx, acc.name, acc.email, acc.password)
+	if err != nil {
+		if !errors.Is(err, persistence.ErrNotFound) {
+			slog.Warn("failed to load the stored session", "account", acc.name, "error", err)
+		}
+		return false
+	}
+
+	if err := d.client.SetRegion(creds.Region); err != nil {
+		slog.Warn("ignoring the stored session", "account", acc.name, "error", err)
+		return false
+	}
+
+	d.token = creds.Token
+	d.tokenExpiresAt = creds.ExpiresAt
+	d.accountID = creds.AccountID
+	d.patientID = creds.PatientID
+	slog.Info("reusing stored LibreView session", "account", acc.name, "region", creds.Region, "expiresAt", creds.ExpiresAt)
+	return true
+}
+
+// saveSession stores the session of the active account. Failures are logged:
+// the next start logs in again.
+func (d *Daemon) saveSession() {
+	if d.sessionService == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, 5*time.Second)
+	defer cancel()
+
+	acc := d.accounts[d.activeAccount]
+	creds := &domain.UpstreamCredentials{
+		Token:     d.token,
+		AccountID: d.accountID,
+		PatientID: d.patientID,
+		Region:    d.client.Region(),
+		ExpiresAt: d.tokenExpiresAt,
+	}
+	if err := d.sessionService.Save(ctx, acc.name, acc.email, acc.password, creds); err != nil {
+		slog.Warn("failed to store the session", "account", acc.name, "error", err)
+	}
+}
+
+// forgetSession deletes the stored session of the active account.
+func (d *Daemon) forgetSession() {
+	if d.sessionService == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, 5*time.Second)
+	defer cancel()
+
+	acc := d.accounts[d.activeAccount]
+	if err := d.sessionService.Forget(ctx, acc.name); err != nil {
+		slog.Warn("failed to delete the stored session", "account", acc.name, "error", err)
+	}
+}
+
+// upstreamCause classifies a fetch error by where it comes from. Returns an
+// empty cause for errors that do not involve LibreView (e.g. database errors).
+func upstreamCause(err error) string {
+	var networkErr *libreclient.NetworkError
+	var authErr *libreclient.AuthError
+	var rateLimitErr *libreclient.RateLimitError
+	var serverErr *libreclient.ServerError
+	var httpErr *libreclient.HTTPError
+	var termsErr *libreclient.TermsError
+
+	switch {
+	case errors.As(err, &authErr), errors.As(err, &termsErr):
+		return domain.OutageCauseAuth
+	case errors.As(err, &rateLimitErr):
+		return domain.OutageCauseRateLimit
+	case errors.As(err, &serverErr), errors.As(err, &httpErr):
+		return domain.OutageCauseServer
+	case errors.As(err, &networkErr):
+		return domain.OutageCauseNetwork
+	default:
+		return ""
+	}
+}
+
+// recordUpstreamFailure records a failed fetch in the upstream availability
+// history, if the error comes from LibreView.
+func (d *Daemon) recordUpstreamFailure(err error) {
+	if d.upstreamService == nil {
+		return
+	}
+	cause := upstreamCause(err)
+	if cause == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, 5*time.Second)
+	defer cancel()
+
+	if err := d.upstreamService.RecordFailure(ctx, cause, err); err != nil {
+		slog.Warn("failed to record upstream failure", "error", err)
+	}
+}
+
+// recordUpstreamSuccess records a successful fetch in the upstream availability history.
+func (d *Daemon) recordUpstreamSuccess() {
+	if d.upstreamService == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, 5*time.Second)
+	defer cancel()
+
+	if err := d.upstreamService.RecordSuccess(ctx); err != nil {
+		slog.Warn("failed to record upstream success", "error", err)
+	}
+}
+
+// isAccountFailure reports whether err is specific to the account in use
+// (rejected credentials or rate limiting), so another account may succeed.
+func isAccountFailure(err error) bool {
+	var authErr *libreclient.AuthError
+	var rateLimitErr *libreclient.RateLimitError
+	return errors.As(err, &authErr) || errors.As(err, &rateLimitErr)
+}
+
+// failover switches to the next configured account after an account failure
+// and authenticates with it. Returns false when there is no other account to
+// switch to or cause is not an account failure.
+func (d *Daemon) failover(cause error) (bool, error) {
+	if len(d.accounts) < 2 || !isAccountFailure(cause) {
+		return false, nil
+	}
+
+	previous := d.accounts[d.activeAccount].name
+	d.activeAccount = (d.activeAccount + 1) % len(d.accounts)
+	d.failovers++
+	d.token = ""
+	d.tokenExpiresAt = time.Time{}
+	d.accountID = ""
+	// The other account may be homed in another region: start from the global endpoint
+	d.client.SetRegion("")
+
+	slog.Warn("failing over to another LibreLinkUp account",
+		"from", previous,
+		"to", d.accounts[d.activeAccount].name,
+		"cause", cause,
+	)
+
+	return true, d.authenticate()
+}
+
 // initialFetch performs the initial data fetch from /connections and /graph.
 func (d *Daemon) initialFetch() error {
 	start := time.Now()
@@ -280,32 +850,81 @@ func (d *Daemon) initialFetch() error {
 		return fmt.Errorf("failed to get connections: %w", err)
 	}
 
-	if len(connectionsResp.Data) == 0 {
-		return fmt.Errorf("no patient data in connections response")
+	conn, err := d.selectConnection(connectionsResp)
+	if err != nil {
+		return err
+	}
+
+	cycle := newFetchCycle()
+
+	storedPatientID := d.patientID
+	d.patientID = conn.PatientID
+	slog.Debug("patient ID obtained", "patientID", logger.RedactSensitive(d.patientID), "connections", len(connectionsResp.Data))
+	if d.patientID != storedPatientID {
+		d.saveSession()
 	}
 
-	d.patientID = connectionsResp.Data[0].PatientID
-	slog.Debug("patient ID obtained", "patientID", logger.RedactSensitive(d.patientID))
+	// Rows stored before multi-patient support belong to the followed patient
+	if !d.patientsAssigned {
+		if err := d.sensorService.AssignPatient(ctx, d.patientID); err != nil {
+			return fmt.Errorf("failed to assign stored data to the patient: %w", err)
+		}
+		d.patientsAssigned = true
+	}
 
 	// Store current measurement from /connections
-	if _, err := d.storeCurrentMeasurement(&connectionsResp.Data[0].GlucoseMeasurement); err != nil {
+	if _, err := d.storeCurrentMeasurement(d.patientID, conn, cycle); err != nil {
 		return fmt.Errorf("failed to store current measurement: %w", err)
 	}
 
 	// Now fetch historical data from /graph
-	slog.Debug("fetching historical data from /graph")
-	graphResp, err := d.client.GetGraph(ctx, d.token, d.accountID, d.patientID)
+	newCount, skippedCount, sensor, err := d.fetchHistory(ctx, d.patientID, &cycle)
+	if err != nil {
+		return err
+	}
+
+	// Store sensor configuration
+	if err := d.storeSensor(d.patientID, sensor); err != nil {
+		return fmt.Errorf("failed to store sensor: %w", err)
+	}
+
+	// Store glucose targets and app alarm settings from /connections response
+	d.storeTargets(conn)
+	d.storeDevice(conn)
+	d.updateConnection(connectionsResp, conn)
+
+	if d.multiPatient {
+		d.storeOtherPatients(connectionsResp, conn, cycle)
+	}
+
+	slog.Info("initial fetch completed",
+		"new", newCount,
+		"skipped", skippedCount,
+		"fetchCycle", cycle.id,
+		"duration", time.Since(start),
+	)
+
+	return nil
+}
+
+// fetchHistory fetches the historical data of a patient from /graph and
+// stores it. Returns the number of new and skipped measurements, and the
+// sensor reported with the history.
+func (d *Daemon) fetchHistory(ctx context.Context, patientID string, cycle *fetchCycle) (int, int, *libreclient.SensorData, error) {
+	slog.Debug("fetching historical data from /graph", "patientID", logger.RedactSensitive(patientID))
+	graphResp, err := d.client.GetGraph(ctx, d.token, d.accountID, patientID)
 	if err != nil {
-		return fmt.Errorf("failed to get graph data: %w", err)
+		return 0, 0, nil, fmt.Errorf("failed to get graph data: %w", err)
 	}
+	cycle.at = time.Now().UTC() // Same cycle, received after /connections
 
 	// Store historical measurements and count new vs skipped
 	newCount := 0
 	skippedCount := 0
 	for _, point := range graphResp.Data.GraphData {
-		inserted, err := d.storeHistoricalMeasurement(&point)
+		inserted, err := d.storeHistoricalMeasurement(patientID, &point, *cycle)
 		if err != nil {
-			return fmt.Errorf("failed to store historical measurement: %w", err)
+			return 0, 0, nil, fmt.Errorf("failed to store historical measurement: %w", err)
 		}
 		if inserted {
 			newCount++
@@ -314,21 +933,74 @@ func (d *Daemon) initialFetch() error {
 		}
 	}
 
-	// Store sensor configuration
-	sensor := &graphResp.Data.Connection.Sensor
-	if err := d.storeSensor(sensor); err != nil {
+	return newCount, skippedCount, &graphResp.Data.Connection.Sensor, nil
+}
+
+// selectConnection returns the connection of the patient to follow: the
+// selected one, or the first one when none is selected.
+func (d *Daemon) selectConnection(resp *libreclient.ConnectionsResponse) (*libreclient.Connection, error) {
+	if len(resp.Data) == 0 {
+		return nil, fmt.Errorf("no patient data in connections response")
+	}
+	if d.selectedPatientID == "" {
+		return &resp.Data[0], nil
+	}
+
+	for i := range resp.Data {
+		if strings.EqualFold(resp.Data[i].PatientID, d.selectedPatientID) {
+			return &resp.Data[i], nil
+		}
+	}
+	return nil, fmt.Errorf("patient %s is not followed by the account (%d connections)",
+		logger.RedactSensitive(d.selectedPatientID), len(resp.Data))
+}
+
+// storeOtherPatients stores the current reading and sensor of the patients
+// other than the followed one (multi-patient mode). The history of a patient
+// is fetched the first time it is seen. Failures are logged but do not fail
+// the fetch, which is about the followed patient.
+func (d *Daemon) storeOtherPatients(resp *libreclient.ConnectionsResponse, followed *libreclient.Connection, cycle fetchCycle) {
+	for i := range resp.Data {
+		conn := &resp.Data[i]
+		if conn.PatientID == followed.PatientID {
+			continue
+		}
+
+		if err := d.storeOtherPatient(conn, cycle); err != nil {
+			slog.Warn("failed to store patient data",
+				"patientID", logger.RedactSensitive(conn.PatientID),
+				"error", err,
+			)
+		}
+	}
+}
+
+// storeOtherPatient stores the data of a patient other than the followed one.
+func (d *Daemon) storeOtherPatient(conn *libreclient.Connection, cycle fetchCycle) error {
+	if err := d.storeSensor(conn.PatientID, &conn.Sensor); err != nil {
 		return fmt.Errorf("failed to store sensor: %w", err)
 	}
+	if _, err := d.storeCurrentMeasurement(conn.PatientID, conn, cycle); err != nil {
+		return fmt.Errorf("failed to store current measurement: %w", err)
+	}
 
-	// Store glucose targets from /connections response
-	d.storeTargets(connectionsResp)
+	if d.patientsSeen[conn.PatientID] {
+		return nil
+	}
 
-	slog.Info("initial fetch completed",
+	ctx, cancel := context.WithTimeout(d.ctx, 30*time.Second)
+	defer cancel()
+
+	newCount, _, _, err := d.fetchHistory(ctx, conn.PatientID, &cycle)
+	if err != nil {
+		return err
+	}
+	d.patientsSeen[conn.PatientID] = true
+
+	slog.Info("patient history fetched",
+		"patientID", logger.RedactSensitive(conn.PatientID),
 		"new", newCount,
-		"skipped", skippedCount,
-		"duration", time.Since(start),
 	)
-
 	return nil
 }
 
@@ -336,6 +1008,11 @@ func (d *Daemon) initialFetch() error {
 // Returns (inserted, error): inserted indicates if a new measurement was stored.
 // If authentication fails (401), automatically re-authenticates with retry logic.
 func (d *Daemon) fetch() (bool, error) {
+	// While LibreView requires a step, only log in again every termsRetryInterval
+	if step := d.PendingTerms(); step != "" && !d.acceptRequested.Load() && time.Since(d.termsCheckedAt) < termsRetryInterval {
+		return false, fmt.Errorf("waiting for the LibreView %q step to be completed", step)
+	}
+
 	ctx, cancel := context.WithTimeout(d.ctx, 30*time.Second)
 	defer cancel()
 
@@ -359,6 +1036,12 @@ func (d *Daemon) fetch() (bool, error) {
 						"error", err,
 					)
 
+					// Retrying does not help until the step is completed
+					var terms *libreclient.TermsError
+					if errors.As(err, &terms) {
+						break
+					}
+
 					// Exponential backoff: wait before retrying
 					if attempt < maxRetries {
 						backoff := time.Duration(attempt*attempt) * time.Second
@@ -369,6 +1052,7 @@ func (d *Daemon) fetch() (bool, error) {
 				}
 
 				// Re-authentication successful, retry the fetch
+				d.fetchStats.recordReauthentication()
 				ctx, cancel := context.WithTimeout(d.ctx, 30*time.Second)
 				defer cancel()
 
@@ -382,10 +1066,17 @@ func (d *Daemon) fetch() (bool, error) {
 				break
 			}
 
-			// If all retry attempts failed
+			// If all retry attempts failed, try the other account
 			if lastErr != nil && connectionsResp == nil {
 				slog.Error("re-authentication failed after all retries", "attempts", maxRetries, "error", lastErr)
-				return false, fmt.Errorf("re-authentication failed after %d attempts: %w", maxRetries, lastErr)
+				if connectionsResp, err = d.failoverConnections(lastErr); err != nil {
+					return false, fmt.Errorf("re-authentication failed after %d attempts: %w", maxRetries, err)
+				}
+			}
+		} else if isAccountFailure(err) {
+			slog.Warn("account rate-limited during periodic fetch", "error", err)
+			if connectionsResp, err = d.failoverConnections(err); err != nil {
+				return false, fmt.Errorf("failed to get connections: %w", err)
 			}
 		} else {
 			slog.Error("failed to get connections during periodic fetch", "error", err)
@@ -393,19 +1084,21 @@ func (d *Daemon) fetch() (bool, error) {
 		}
 	}
 
-	if len(connectionsResp.Data) == 0 {
-		return false, fmt.Errorf("no patient data in connections response")
+	conn, err := d.selectConnection(connectionsResp)
+	if err != nil {
+		return false, err
 	}
 
-	gm := &connectionsResp.Data[0].GlucoseMeasurement
+	cycle := newFetchCycle()
 
 	// Store the measurement
-	inserted, err := d.storeCurrentMeasurement(gm)
+	inserted, err := d.storeCurrentMeasurement(d.patientID, conn, cycle)
 	if err != nil {
 		return false, err
 	}
 
 	// Debug: log all measurement data
+	gm, source := conn.LatestGlucose()
 	slog.Debug("measurement",
 		"value", gm.Value,
 		"valueInMgPerDl", gm.ValueInMgPerDl,
@@ -413,35 +1106,61 @@ func (d *Daemon) fetch() (bool, error) {
 		"measurementColor", gm.MeasurementColor,
 		"factoryTimestamp", gm.FactoryTimestamp,
 		"timestamp", gm.Timestamp,
+		"source", source,
+		"fetchCycle", cycle.id,
 	)
 
 	// Also store/update the sensor
-	sensor := &connectionsResp.Data[0].Sensor
-	if err := d.storeSensor(sensor); err != nil {
+	if err := d.storeSensor(d.patientID, &conn.Sensor); err != nil {
 		// Log but don't fail the fetch for sensor errors
 		slog.Warn("failed to store sensor", "error", err)
 	}
 
-	// Store glucose targets
-	d.storeTargets(connectionsResp)
+	// Store glucose targets and app alarm settings, so changes made in the
+	// LibreLink app propagate within a poll
+	d.storeTargets(conn)
+	d.storeDevice(conn)
+	d.updateConnection(connectionsResp, conn)
+
+	if d.multiPatient {
+		d.storeOtherPatients(connectionsResp, conn, cycle)
+	}
 
 	return inserted, nil
 }
 
-// storeCurrentMeasurement stores a current measurement (from /connections).
+// failoverConnections switches to the other account after an account failure
+// and fetches /connections with it. Returns cause when failover is not possible.
+func (d *Daemon) failoverConnections(cause error) (*libreclient.ConnectionsResponse, error) {
+	switched, err := d.failover(cause)
+	if !switched {
+		return nil, cause
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, 30*time.Second)
+	defer cancel()
+
+	return d.client.GetConnections(ctx, d.token, d.accountID)
+}
+
+// storeCurrentMeasurement stores the current measurement (from /connections)
+// of a patient: the fresher of glucoseMeasurement and glucoseItem.
+// Alerts and delays are only tracked for the followed patient.
 // Returns (inserted, error).
-func (d *Daemon) storeCurrentMeasurement(gm *struct {
-	ValueInMgPerDl   int     `json:"ValueInMgPerDl"`
-	Value            float64 `json:"Value"`
-	TrendArrow       int     `json:"TrendArrow"`
-	TrendMessage     string  `json:"TrendMessage"`
-	MeasurementColor int     `json:"MeasurementColor"`
-	GlucoseUnits     int     `json:"GlucoseUnits"`
-	FactoryTimestamp string  `json:"FactoryTimestamp"`
-	Timestamp        string  `json:"Timestamp"`
-	IsHigh           bool    `json:"isHigh"`
-	IsLow            bool    `json:"isLow"`
-}) (bool, error) {
+func (d *Daemon) storeCurrentMeasurement(patientID string, conn *libreclient.Connection, cycle fetchCycle) (bool, error) {
+	gm, source := conn.LatestGlucose()
+	if item := conn.GlucoseItem; item != nil && *item != conn.GlucoseMeasurement {
+		slog.Debug("glucoseMeasurement and glucoseItem differ",
+			"patientID", logger.RedactSensitive(patientID),
+			"measurementTimestamp", conn.GlucoseMeasurement.FactoryTimestamp,
+			"itemTimestamp", item.FactoryTimestamp,
+			"source", source,
+		)
+	}
+
 	factoryTimestamp, err := timeparser.ParseLibreViewTimestamp(gm.FactoryTimestamp)
 	if err != nil {
 		return false, fmt.Errorf("failed to parse factory timestamp: %w", err)
@@ -459,6 +1178,7 @@ func (d *Daemon) storeCurrentMeasurement(gm *struct {
 	}
 
 	measurement := &domain.GlucoseMeasurement{
+		PatientID:        patientID,
 		FactoryTimestamp: factoryTimestamp,
 		Timestamp:        timestamp,
 		Value:            gm.Value,
@@ -470,27 +1190,42 @@ func (d *Daemon) storeCurrentMeasurement(gm *struct {
 		IsHigh:           gm.IsHigh,
 		IsLow:            gm.IsLow,
 		Type:             domain.GlucoseTypeCurrent,
+		FetchCycleID:     cycle.id,
+		FetchedAt:        &cycle.at,
+		FetchSource:      source,
 	}
 
-	ctx, cancel := context.WithTimeout(d.ctx, 5*time.Second)
+	ctx, cancel := context.WithTimeout(repository.WithPatient(d.ctx, patientID), 5*time.Second)
 	defer cancel()
 
 	inserted, err := d.glucoseService.SaveMeasurement(ctx, measurement)
 	if err != nil {
 		return false, err
 	}
+	d.fetchStats.recordStore(inserted)
 
 	// Update LastMeasurementAt on the current sensor
 	if err := d.sensorService.UpdateLastMeasurementIfNewer(ctx, measurement.Timestamp); err != nil {
 		slog.Warn("failed to update sensor LastMeasurementAt", "error", err)
 	}
+	if patientID != d.patientID {
+		return inserted, nil
+	}
+	if measurement.Timestamp.After(d.sensorLastReadingAt) {
+		d.sensorLastReadingAt = measurement.Timestamp
+	}
+
+	if inserted {
+		d.recordDelay(measurement.Timestamp)
+		d.checkGlucoseAlerts(measurement)
+	}
 
 	return inserted, nil
 }
 
-// storeHistoricalMeasurement stores a historical measurement (from /graph).
+// storeHistoricalMeasurement stores a historical measurement (from /graph) of a patient.
 // Returns (true, nil) if inserted, (false, nil) if duplicate.
-func (d *Daemon) storeHistoricalMeasurement(point *struct {
+func (d *Daemon) storeHistoricalMeasurement(patientID string, point *struct {
 	FactoryTimestamp string  `json:"FactoryTimestamp"`
 	Timestamp        string  `json:"Timestamp"`
 	ValueInMgPerDl   int     `json:"ValueInMgPerDl"`
@@ -500,7 +1235,7 @@ func (d *Daemon) storeHistoricalMeasurement(point *struct {
 	IsHigh           bool    `json:"isHigh"`
 	IsLow            bool    `json:"isLow"`
 	Type             int     `json:"type"`
-}) (bool, error) {
+}, cycle fetchCycle) (bool, error) {
 	factoryTimestamp, err := timeparser.ParseLibreViewTimestamp(point.FactoryTimestamp)
 	if err != nil {
 		return false, fmt.Errorf("failed to parse factory timestamp: %w", err)
@@ -512,6 +1247,7 @@ func (d *Daemon) storeHistoricalMeasurement(point *struct {
 	}
 
 	measurement := &domain.GlucoseMeasurement{
+		PatientID:        patientID,
 		FactoryTimestamp: factoryTimestamp,
 		Timestamp:        timestamp,
 		Value:            point.Value,
@@ -522,15 +1258,18 @@ func (d *Daemon) storeHistoricalMeasurement(point *struct {
 		IsHigh:           point.IsHigh,
 		IsLow:            point.IsLow,
 		Type:             point.Type,
+		FetchCycleID:     cycle.id,
+		FetchedAt:        &cycle.at,
 	}
 
-	ctx, cancel := context.WithTimeout(d.ctx, 5*time.Second)
+	ctx, cancel := context.WithTimeout(repository.WithPatient(d.ctx, patientID), 5*time.Second)
 	defer cancel()
 
 	inserted, err := d.glucoseService.SaveMeasurement(ctx, measurement)
 	if err != nil {
 		return false, err
 	}
+	d.fetchStats.recordStore(inserted)
 
 	// Update LastMeasurementAt on the current sensor
 	if err := d.sensorService.UpdateLastMeasurementIfNewer(ctx, measurement.Timestamp); err != nil {
@@ -540,10 +1279,11 @@ func (d *Daemon) storeHistoricalMeasurement(point *struct {
 	return inserted, nil
 }
 
-// storeSensor stores sensor configuration and handles sensor changes.
+// storeSensor stores the sensor configuration of a patient and handles sensor changes.
 // The sensor change detection logic (setting EndedAt on old sensor)
 // is handled by SensorService.HandleSensorChange() within a transaction.
-func (d *Daemon) storeSensor(sensor *libreclient.SensorData) error {
+// Expiry is only tracked for the followed patient.
+func (d *Daemon) storeSensor(patientID string, sensor *libreclient.SensorData) error {
 	start := time.Now()
 
 	// Convert Unix timestamp to time.Time (sensor.A is activation time)
@@ -554,6 +1294,7 @@ func (d *Daemon) storeSensor(sensor *libreclient.SensorData) error {
 	expiresAt := activationTime.AddDate(0, 0, durationDays)
 
 	sensorConfig := &domain.SensorConfig{
+		PatientID:    patientID,
 		SerialNumber: sensor.SN,
 		Activation:   activationTime,
 		ExpiresAt:    expiresAt,
@@ -562,15 +1303,21 @@ func (d *Daemon) storeSensor(sensor *libreclient.SensorData) error {
 		DetectedAt:   time.Now().UTC(),
 	}
 
-	ctx, cancel := context.WithTimeout(d.ctx, 5*time.Second)
+	ctx, cancel := context.WithTimeout(repository.WithPatient(d.ctx, patientID), 5*time.Second)
 	defer cancel()
 
 	// HandleSensorChange manages sensor change detection atomically
 	if err := d.sensorService.HandleSensorChange(ctx, sensorConfig); err != nil {
 		return err
 	}
+	if patientID != d.patientID {
+		return nil
+	}
 
-	// Track sensor expiration for health checks
+	// Track sensor expiration for health checks; a new sensor resets the expiry alert
+	if !expiresAt.Equal(d.sensorExpiresAt) {
+		d.sensorStatus = ""
+	}
 	d.sensorExpiresAt = expiresAt
 
 	// Debug: log all sensor data (same pattern as measurements in fetch())
@@ -585,14 +1332,9 @@ func (d *Daemon) storeSensor(sensor *libreclient.SensorData) error {
 	return nil
 }
 
-// storeTargets extracts glucose targets from a ConnectionsResponse and saves them.
+// storeTargets extracts glucose targets from the connection of the followed patient and saves them.
 // Uses in-memory cache to avoid redundant saves when values haven't changed.
-func (d *Daemon) storeTargets(resp *libreclient.ConnectionsResponse) {
-	if len(resp.Data) == 0 {
-		return
-	}
-
-	data := &resp.Data[0]
+func (d *Daemon) storeTargets(data *libreclient.Connection) {
 	if data.TargetHigh == 0 && data.TargetLow == 0 {
 		return
 	}
@@ -623,6 +1365,134 @@ func (d *Daemon) storeTargets(resp *libreclient.ConnectionsResponse) {
 	d.lastTargets = targets
 }
 
+// storeDevice extracts the LibreLink app alarm settings from the connection of the
+// followed patient, saves them and applies the app's low alarm level to glucose alerts.
+// Uses in-memory cache to avoid redundant saves when values haven't changed.
+func (d *Daemon) storeDevice(conn *libreclient.Connection) {
+	pd := &conn.PatientDevice
+	if pd.DID == "" {
+		return
+	}
+
+	device := &domain.DeviceInfo{
+		DeviceID:          pd.DID,
+		DeviceTypeID:      pd.DTID,
+		AppVersion:        pd.V,
+		AlarmsEnabled:     pd.Alarms,
+		HighLimit:         pd.HL,
+		LowLimit:          pd.LL,
+		FixedLowThreshold: pd.FixedLowThreshold,
+		FixedLowAlarm: domain.FixedLowAlarmValues{
+			MgPerDl:  pd.FixedLowAlarmValues.MgDl,
+			MmolPerL: pd.FixedLowAlarmValues.MmolL,
+		},
+		LastUpdate:   time.Unix(pd.U, 0).UTC(),
+		LimitEnabled: pd.L,
+	}
+	if rules := conn.AlarmRules; rules != nil {
+		device.AlarmRules = domain.AlarmRules{
+			Configured:        rules.C,
+			High:              newAlarmRule(rules.H),
+			Low:               newAlarmRule(rules.L),
+			UrgentLow:         newAlarmRule(rules.F),
+			SignalLossMinutes: rules.ND.I,
+		}
+	}
+
+	if d.lastDevice != nil && d.lastDevice.SameAlarmSettings(device) {
+		return // Unchanged, skip save
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, 5*time.Second)
+	defer cancel()
+
+	if err := d.configService.SaveDeviceInfo(ctx, device); err != nil {
+		slog.Warn("failed to store device info", "error", err)
+		return
+	}
+
+	if d.modeService != nil {
+		d.modeService.SetLowAlert(device.LowAlertMgDl())
+	}
+
+	// Update cache on successful save
+	d.lastDevice = device
+}
+
+// newAlarmRule converts an alarm of the LibreView alarm rules.
+func newAlarmRule(rule libreclient.AlarmRule) domain.AlarmRule {
+	return domain.AlarmRule{
+		Enabled:       rule.Enabled(),
+		MgPerDl:       rule.TH,
+		MmolPerL:      rule.THMM,
+		RepeatMinutes: rule.D,
+	}
+}
+
+// updateConnection records the details of the LibreLinkUp connection of the
+// followed patient and the patients the account follows. Only the patients'
+// initials are kept.
+func (d *Daemon) updateConnection(resp *libreclient.ConnectionsResponse, data *libreclient.Connection) {
+	now := time.Now().UTC()
+
+	patients := make([]domain.ConnectionPatient, 0, len(resp.Data))
+	for _, conn := range resp.Data {
+		patients = append(patients, domain.ConnectionPatient{
+			PatientID: conn.PatientID,
+			Initials:  domain.Initials(conn.FirstName, conn.LastName),
+			Stored:    d.multiPatient || conn.PatientID == data.PatientID,
+		})
+	}
+
+	d.connMu.Lock()
+	defer d.connMu.Unlock()
+
+	if d.connection.ConnectedAt.IsZero() {
+		d.connection.ConnectedAt = now
+	}
+	d.connection.Source = domain.ConnectionSourceLibreLinkUp
+	d.connection.PatientID = data.PatientID
+	d.connection.PatientInitials = domain.Initials(data.FirstName, data.LastName)
+	d.connection.Patients = patients
+	d.connection.Country = data.Country
+	d.connection.SensorType = data.Sensor.PT
+	d.connection.SensorBrand = domain.SensorBrand(data.Sensor.PT)
+	d.connection.LastFetchAt = now
+	d.connection.CurrentIntervalSeconds = int(domain.CurrentReadingInterval.Seconds())
+	d.connection.HistoricalIntervalSeconds = int(domain.HistoricalReadingInterval.Seconds())
+}
+
+// recordDelay records how old a new current reading was when fetched.
+func (d *Daemon) recordDelay(timestamp time.Time) {
+	delay := max(time.Since(timestamp), 0)
+
+	d.connMu.Lock()
+	defer d.connMu.Unlock()
+
+	d.delayTotal += delay
+	d.delayCount++
+	d.connection.LastDelaySeconds = math.Round(delay.Seconds())
+	d.connection.AverageDelaySeconds = math.Round((d.delayTotal / time.Duration(d.delayCount)).Seconds())
+}
+
+// GetConnectionInfo returns the details of the upstream connection,
+// or nil before the first successful fetch.
+func (d *Daemon) GetConnectionInfo() *domain.ConnectionInfo {
+	d.connMu.Lock()
+	defer d.connMu.Unlock()
+
+	if d.connection.Source == "" {
+		return nil
+	}
+	info := d.connection
+	return &info
+}
+
+// GetFetchStats returns the fetch cycle counters since startup.
+func (d *Daemon) GetFetchStats() FetchStats {
+	return d.fetchStats.snapshot()
+}
+
 // scheduleNextPoll schedules the next polling timer.
 // If a new measurement was inserted, waits for the next expected measurement.
 // If a duplicate was received, retries after a short delay.
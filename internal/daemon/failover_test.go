@@ -0,0 +1,115 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/libreclient"
+)
+
+// redirectTransport sends all requests to a test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestFailover_SwitchesAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var creds libreclient.AuthCredentials
+		json.NewDecoder(r.Body).Decode(&creds)
+
+		// The primary account is rate-limited
+		if creds.Email == "primary@example.com" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		var resp libreclient.AuthResponse
+		resp.Data.User.ID = "secondary-user"
+		resp.Data.AuthTicket.Token = "secondary-token"
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	d := &Daemon{
+		ctx:    context.Background(),
+		client: libreclient.NewClient(&http.Client{Transport: redirectTransport{target: target}}),
+		accounts: []account{
+			{name: accountPrimary, email: "primary@example.com", password: "p"},
+			{name: accountSecondary, email: "secondary@example.com", password: "s"},
+		},
+	}
+
+	err := d.authenticate()
+	if !isAccountFailure(err) {
+		t.Fatalf("expected a rate limit failure, got %v", err)
+	}
+
+	switched, err := d.failover(err)
+	if !switched || err != nil {
+		t.Fatalf("expected failover to succeed, got switched=%v err=%v", switched, err)
+	}
+	if d.token != "secondary-token" {
+		t.Errorf("expected the secondary token, got %q", d.token)
+	}
+
+	status := d.GetHealthStatus()
+	if status.Account != accountSecondary || status.Failovers != 1 {
+		t.Errorf("expected secondary account after 1 failover, got %q (%d)", status.Account, status.Failovers)
+	}
+}
+
+func TestFailover_RequiresSecondaryAccount(t *testing.T) {
+	d := &Daemon{
+		ctx:      context.Background(),
+		accounts: []account{{name: accountPrimary, email: "primary@example.com", password: "p"}},
+	}
+
+	if switched, _ := d.failover(&libreclient.AuthError{StatusCode: http.StatusUnauthorized}); switched {
+		t.Error("expected no failover without a secondary account")
+	}
+
+	d.accounts = append(d.accounts, account{name: accountSecondary, email: "secondary@example.com", password: "s"})
+	if switched, _ := d.failover(&libreclient.NetworkError{}); switched {
+		t.Error("expected no failover on network errors")
+	}
+	if d.GetHealthStatus().Account != accountPrimary {
+		t.Error("expected the primary account to stay active")
+	}
+}
+
+func TestUpstreamCause(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"network", &libreclient.NetworkError{Err: context.DeadlineExceeded}, domain.OutageCauseNetwork},
+		{"server", fmt.Errorf("failed to fetch: %w", &libreclient.ServerError{StatusCode: http.StatusBadGateway}), domain.OutageCauseServer},
+		{"unexpected status", &libreclient.HTTPError{StatusCode: http.StatusNotFound}, domain.OutageCauseServer},
+		{"auth", &libreclient.AuthError{StatusCode: http.StatusUnauthorized}, domain.OutageCauseAuth},
+		{"rate limit", &libreclient.RateLimitError{StatusCode: http.StatusTooManyRequests}, domain.OutageCauseRateLimit},
+		{"database", errors.New("failed to save measurement: database is locked"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := upstreamCause(tt.err); got != tt.want {
+				t.Errorf("upstreamCause() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
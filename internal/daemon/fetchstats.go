@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"sync"
+	"time"
+)
+
+// fetchDurationBuckets are the upper bounds of the fetch duration histogram.
+// A fetch is one request to LibreView plus a few database writes, so most
+// cycles should fall in the lower buckets; re-authentication adds seconds.
+var fetchDurationBuckets = []time.Duration{
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// FetchDurationBucket counts the fetch cycles that took at most LeSeconds.
+// Buckets are cumulative, as in a Prometheus histogram.
+type FetchDurationBucket struct {
+	LeSeconds float64 `json:"leSeconds"`
+	Count     int64   `json:"count"`
+}
+
+// FetchDurationHistogram is the distribution of fetch cycle durations.
+// Count includes the cycles slower than the last bucket.
+type FetchDurationHistogram struct {
+	Buckets     []FetchDurationBucket `json:"buckets"`
+	Count       int64                 `json:"count"`
+	SumSeconds  float64               `json:"sumSeconds"`
+	MaxSeconds  float64               `json:"maxSeconds"`
+	LastSeconds float64               `json:"lastSeconds"`
+}
+
+// FetchStats are counters of the periodic fetch cycles since startup.
+// The initial historical fetch is not a cycle, but the measurements it
+// stores and skips are counted.
+type FetchStats struct {
+	Cycles             int64                  `json:"cycles"`
+	Succeeded          int64                  `json:"succeeded"`
+	Failed             int64                  `json:"failed"`
+	MeasurementsStored int64                  `json:"measurementsStored"`
+	DuplicatesSkipped  int64                  `json:"duplicatesSkipped"`
+	Reauthentications  int64                  `json:"reauthentications"`  // Expired tokens renewed during a fetch
+	WatchdogRecoveries int64                  `json:"watchdogRecoveries"` // Fetch cycles stuck past the ceiling, aborted by the watchdog
+	LastCycleAt        *time.Time             `json:"lastCycleAt,omitempty"`
+	Duration           FetchDurationHistogram `json:"duration"`
+}
+
+// fetchRecorder accumulates FetchStats. It is written by the daemon loop and
+// read by the API, so it has its own lock.
+type fetchRecorder struct {
+	mu    sync.Mutex
+	stats FetchStats
+}
+
+func newFetchRecorder() *fetchRecorder {
+	buckets := make([]FetchDurationBucket, len(fetchDurationBuckets))
+	for i, le := range fetchDurationBuckets {
+		buckets[i].LeSeconds = le.Seconds()
+	}
+	return &fetchRecorder{stats: FetchStats{Duration: FetchDurationHistogram{Buckets: buckets}}}
+}
+
+// recordCycle records a periodic fetch cycle.
+func (r *fetchRecorder) recordCycle(at time.Time, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stats.Cycles++
+	if err != nil {
+		r.stats.Failed++
+	} else {
+		r.stats.Succeeded++
+	}
+	r.stats.LastCycleAt = &at
+
+	seconds := duration.Seconds()
+	h := &r.stats.Duration
+	h.Count++
+	h.SumSeconds += seconds
+	h.LastSeconds = seconds
+	if seconds > h.MaxSeconds {
+		h.MaxSeconds = seconds
+	}
+	for i, le := range fetchDurationBuckets {
+		if duration <= le {
+			h.Buckets[i].Count++
+		}
+	}
+}
+
+// recordStore records a measurement saved (inserted) or skipped as duplicate.
+func (r *fetchRecorder) recordStore(inserted bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if inserted {
+		r.stats.MeasurementsStored++
+	} else {
+		r.stats.DuplicatesSkipped++
+	}
+}
+
+// recordReauthentication records an expired token renewed during a fetch.
+func (r *fetchRecorder) recordReauthentication() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stats.Reauthentications++
+}
+
+// recordWatchdogRecovery records a stuck fetch cycle aborted by the watchdog.
+func (r *fetchRecorder) recordWatchdogRecovery() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stats.WatchdogRecoveries++
+}
+
+// snapshot returns a copy of the stats.
+func (r *fetchRecorder) snapshot() FetchStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.stats
+	stats.Duration.Buckets = append([]FetchDurationBucket(nil), r.stats.Duration.Buckets...)
+	if r.stats.LastCycleAt != nil {
+		at := *r.stats.LastCycleAt
+		stats.LastCycleAt = &at
+	}
+	return stats
+}
@@ -0,0 +1,50 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFetchRecorder(t *testing.T) {
+	r := newFetchRecorder()
+	at := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	r.recordCycle(at, 300*time.Millisecond, nil)
+	r.recordCycle(at.Add(time.Minute), 4*time.Second, nil)
+	r.recordCycle(at.Add(2*time.Minute), time.Minute, errors.New("server error"))
+	r.recordStore(true)
+	r.recordStore(false)
+	r.recordStore(false)
+	r.recordReauthentication()
+
+	stats := r.snapshot()
+	if stats.Cycles != 3 || stats.Succeeded != 2 || stats.Failed != 1 {
+		t.Errorf("expected 3 cycles, 2 succeeded and 1 failed, got %+v", stats)
+	}
+	if stats.MeasurementsStored != 1 || stats.DuplicatesSkipped != 2 || stats.Reauthentications != 1 {
+		t.Errorf("unexpected counters: %+v", stats)
+	}
+	if stats.LastCycleAt == nil || !stats.LastCycleAt.Equal(at.Add(2*time.Minute)) {
+		t.Errorf("expected last cycle at %v, got %v", at.Add(2*time.Minute), stats.LastCycleAt)
+	}
+
+	h := stats.Duration
+	if h.Count != 3 || h.MaxSeconds != 60 || h.LastSeconds != 60 || h.SumSeconds != 64.3 {
+		t.Errorf("unexpected histogram: %+v", h)
+	}
+
+	// Buckets are cumulative, the 1 min cycle is past the last one
+	want := map[float64]int64{0.25: 0, 0.5: 1, 1: 1, 2: 1, 5: 2, 10: 2, 30: 2}
+	for _, b := range h.Buckets {
+		if b.Count != want[b.LeSeconds] {
+			t.Errorf("bucket le=%v: expected %d, got %d", b.LeSeconds, want[b.LeSeconds], b.Count)
+		}
+	}
+
+	// Snapshots do not share the buckets
+	stats.Duration.Buckets[0].Count = 42
+	if r.snapshot().Duration.Buckets[0].Count != 0 {
+		t.Error("expected the snapshot to be a copy")
+	}
+}
@@ -2,8 +2,13 @@ package daemon
 
 import (
 	"context"
+	"log/slog"
 	"testing"
 	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/libreclient"
+	"github.com/R4yL-dev/glcmd/internal/service"
 )
 
 func TestGetHealthStatus_Healthy(t *testing.T) {
@@ -384,3 +389,200 @@ func TestGetHealthStatus_SensorExpiresAt_ZeroValue(t *testing.T) {
 		t.Error("expected SensorExpired = false for zero sensorExpiresAt")
 	}
 }
+
+func TestGetHealthStatus_SensorInGrace_NotExpired(t *testing.T) {
+	d := &Daemon{
+		ctx:                  context.Background(),
+		consecutiveErrors:    0,
+		maxConsecutiveErrors: 5,
+		lastFetchTime:        time.Now(),
+		startTime:            time.Now().Add(-1 * time.Hour),
+		sensorExpiresAt:      time.Now().Add(-1 * time.Hour), // Expired 1 hour ago
+		sensorGracePeriod:    12 * time.Hour,
+		sensorLastReadingAt:  time.Now().Add(-1 * time.Minute), // Still reporting
+	}
+
+	status := d.GetHealthStatus()
+
+	if status.Status != "healthy" {
+		t.Errorf("expected status = healthy (sensor in grace period), got %s", status.Status)
+	}
+
+	if status.SensorExpired {
+		t.Error("expected SensorExpired = false while reporting in grace period")
+	}
+
+	if !status.SensorInGrace {
+		t.Error("expected SensorInGrace = true")
+	}
+}
+
+func TestGetHealthStatus_SensorInGrace_ReadingsStopped(t *testing.T) {
+	d := &Daemon{
+		ctx:                  context.Background(),
+		consecutiveErrors:    0,
+		maxConsecutiveErrors: 5,
+		lastFetchTime:        time.Now(),
+		startTime:            time.Now().Add(-2 * time.Hour),
+		sensorExpiresAt:      time.Now().Add(-1 * time.Hour),
+		sensorGracePeriod:    12 * time.Hour,
+		sensorLastReadingAt:  time.Now().Add(-30 * time.Minute), // Readings stopped
+	}
+
+	status := d.GetHealthStatus()
+
+	if status.Status != "degraded" {
+		t.Errorf("expected status = degraded (sensor ended), got %s", status.Status)
+	}
+
+	if !status.SensorExpired {
+		t.Error("expected SensorExpired = true once readings stop")
+	}
+
+	if status.SensorInGrace {
+		t.Error("expected SensorInGrace = false")
+	}
+}
+
+func TestGetHealthStatus_Mode(t *testing.T) {
+	d := &Daemon{
+		ctx:                  context.Background(),
+		maxConsecutiveErrors: 5,
+		lastFetchTime:        time.Now(),
+		startTime:            time.Now(),
+	}
+
+	// No mode service: mode is omitted
+	if status := d.GetHealthStatus(); status.Mode != nil {
+		t.Errorf("expected no mode, got %+v", status.Mode)
+	}
+
+	modeService := service.NewModeService(slog.Default())
+	modeService.StartExercise(time.Hour)
+	d.modeService = modeService
+
+	status := d.GetHealthStatus()
+	if status.Mode == nil || status.Mode.Mode != domain.ModeExercise {
+		t.Fatalf("expected exercise mode, got %+v", status.Mode)
+	}
+	if status.Mode.Thresholds.LowMgDl != domain.DefaultLowAlertMgDl+domain.ExerciseLowAlertRaiseMgDl {
+		t.Errorf("expected raised low threshold, got %d", status.Mode.Thresholds.LowMgDl)
+	}
+}
+
+func TestCheckGlucoseAlerts_Exercise(t *testing.T) {
+	modeService := service.NewModeService(slog.Default())
+	d := &Daemon{modeService: modeService}
+
+	falling := domain.TrendArrowFalling
+	m := &domain.GlucoseMeasurement{ValueInMgPerDl: 90, TrendArrow: &falling}
+
+	d.checkGlucoseAlerts(m)
+	if d.glucoseLow || d.glucoseFalling {
+		t.Errorf("expected no alert in normal mode, got low=%v falling=%v", d.glucoseLow, d.glucoseFalling)
+	}
+
+	modeService.StartExercise(time.Hour)
+	d.checkGlucoseAlerts(m)
+	if !d.glucoseLow || !d.glucoseFalling {
+		t.Errorf("expected low and falling alerts in exercise mode, got low=%v falling=%v", d.glucoseLow, d.glucoseFalling)
+	}
+}
+
+// recordingAlertService keeps recorded alerts in memory.
+type recordingAlertService struct {
+	service.AlertService
+	alerts []*domain.Alert
+}
+
+func (r *recordingAlertService) RecordAlert(ctx context.Context, alert *domain.Alert) error {
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func TestCheckGlucoseAlerts_RecordsHistory(t *testing.T) {
+	alerts := &recordingAlertService{}
+	d := &Daemon{
+		ctx:          context.Background(),
+		modeService:  service.NewModeService(slog.Default()),
+		alertService: alerts,
+	}
+
+	steady := domain.TrendArrowStable
+	low := &domain.GlucoseMeasurement{Timestamp: time.Now(), ValueInMgPerDl: 60, TrendArrow: &steady}
+
+	// A condition that persists is recorded once
+	d.checkGlucoseAlerts(low)
+	d.checkGlucoseAlerts(low)
+
+	if len(alerts.alerts) != 1 {
+		t.Fatalf("expected 1 recorded alert, got %d", len(alerts.alerts))
+	}
+	if alerts.alerts[0].Type != domain.AlertTypeLow || alerts.alerts[0].ValueMgDl != 60 {
+		t.Errorf("unexpected alert: %+v", alerts.alerts[0])
+	}
+}
+
+func TestGetConnectionInfo(t *testing.T) {
+	d := &Daemon{}
+	if info := d.GetConnectionInfo(); info != nil {
+		t.Fatalf("expected no connection before the first fetch, got %+v", info)
+	}
+
+	resp := &libreclient.ConnectionsResponse{}
+	resp.Data = make([]libreclient.Connection, 1)
+	resp.Data[0].PatientID = "6a1f3a4e-0000-4000-8000-000000000001"
+	resp.Data[0].FirstName = "jane"
+	resp.Data[0].LastName = "Doe"
+	resp.Data[0].Country = "CH"
+	resp.Data[0].Sensor.PT = 4
+
+	d.updateConnection(resp, &resp.Data[0])
+	d.recordDelay(time.Now().Add(-2 * time.Minute))
+	d.recordDelay(time.Now().Add(-4 * time.Minute))
+
+	info := d.GetConnectionInfo()
+	if info == nil {
+		t.Fatal("expected connection details")
+	}
+	if info.PatientInitials != "J.D." || info.Country != "CH" || info.SensorBrand != "FreeStyle Libre 3 Plus" {
+		t.Errorf("unexpected connection details: %+v", info)
+	}
+	if info.PatientID != resp.Data[0].PatientID || len(info.Patients) != 1 || !info.Patients[0].Stored || info.Patients[0].Initials != "J.D." {
+		t.Errorf("unexpected patients: %+v", info.Patients)
+	}
+	if info.LastDelaySeconds != 240 || info.AverageDelaySeconds != 180 {
+		t.Errorf("expected delays of 240s (last) and 180s (average), got %+v", info)
+	}
+	if info.CurrentIntervalSeconds != 60 || info.HistoricalIntervalSeconds != 900 {
+		t.Errorf("unexpected reading intervals: %+v", info)
+	}
+}
+
+func TestSelectConnection(t *testing.T) {
+	resp := &libreclient.ConnectionsResponse{}
+	d := &Daemon{}
+	if _, err := d.selectConnection(resp); err == nil {
+		t.Error("expected an error without connections")
+	}
+
+	resp.Data = make([]libreclient.Connection, 2)
+	resp.Data[0].PatientID = "6a1f3a4e-0000-4000-8000-000000000001"
+	resp.Data[1].PatientID = "6A1F3A4E-0000-4000-8000-000000000002"
+
+	conn, err := d.selectConnection(resp)
+	if err != nil || conn.PatientID != resp.Data[0].PatientID {
+		t.Errorf("expected the first connection without selection, got %+v (%v)", conn, err)
+	}
+
+	d.SetPatients("6a1f3a4e-0000-4000-8000-000000000002", false)
+	conn, err = d.selectConnection(resp)
+	if err != nil || conn.PatientID != resp.Data[1].PatientID {
+		t.Errorf("expected the selected connection, got %+v (%v)", conn, err)
+	}
+
+	d.SetPatients("6a1f3a4e-0000-4000-8000-000000000003", false)
+	if _, err := d.selectConnection(resp); err == nil {
+		t.Error("expected an error for a patient not followed by the account")
+	}
+}
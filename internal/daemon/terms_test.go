@@ -0,0 +1,117 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/libreclient"
+)
+
+// newTermsServer returns a LibreView server requiring the terms of use to be
+// accepted before issuing a session, and the number of acceptances.
+func newTermsServer(t *testing.T) (*httptest.Server, *int) {
+	accepted := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/auth/continue/tou":
+			accepted++
+			w.Write([]byte(`{"status":0,"data":{"user":{"id":"user"},"authTicket":{"token":"session-token"}}}`))
+		case accepted > 0:
+			w.Write([]byte(`{"status":0,"data":{"user":{"id":"user"},"authTicket":{"token":"session-token"}}}`))
+		default:
+			w.Write([]byte(`{"status":4,"data":{"step":{"type":"tou","componentName":"AcceptDocument"},"user":{"id":"user"},"authTicket":{"token":"tou-token"}}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &accepted
+}
+
+// newTermsDaemon returns a daemon logging in to server.
+func newTermsDaemon(server *httptest.Server) *Daemon {
+	target, _ := url.Parse(server.URL)
+	return &Daemon{
+		ctx:       context.Background(),
+		client:    libreclient.NewClient(&http.Client{Transport: redirectTransport{target: target}}),
+		accounts:  []account{{name: accountPrimary, email: "primary@example.com", password: "p"}},
+		termsWake: make(chan struct{}, 1),
+	}
+}
+
+func TestAuthenticate_TermsPending(t *testing.T) {
+	server, accepted := newTermsServer(t)
+	d := newTermsDaemon(server)
+
+	if err := d.AcceptTerms(); !errors.Is(err, ErrNoPendingTerms) {
+		t.Errorf("expected ErrNoPendingTerms before any login, got %v", err)
+	}
+
+	err := d.authenticate()
+	var terms *libreclient.TermsError
+	if !errors.As(err, &terms) {
+		t.Fatalf("expected a terms error, got %v", err)
+	}
+	if *accepted != 0 {
+		t.Error("expected the terms not to be accepted without consent")
+	}
+	if upstreamCause(err) != domain.OutageCauseAuth {
+		t.Errorf("expected an auth outage cause, got %q", upstreamCause(err))
+	}
+
+	status := d.GetHealthStatus()
+	if status.Status != "unhealthy" || status.TermsRequired != "tou" {
+		t.Errorf("expected unhealthy with the terms of use required, got %q (%q)", status.Status, status.TermsRequired)
+	}
+
+	// Accepted at the next login, which is attempted right away
+	if err := d.AcceptTerms(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-d.termsWake:
+	default:
+		t.Error("expected the daemon to be woken up")
+	}
+
+	if err := d.authenticate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *accepted != 1 || d.token != "session-token" {
+		t.Errorf("expected the session issued after acceptance, got %d acceptances and token %q", *accepted, d.token)
+	}
+	if status := d.GetHealthStatus(); status.TermsRequired != "" {
+		t.Errorf("expected no pending terms, got %q", status.TermsRequired)
+	}
+}
+
+func TestAuthenticate_AcceptTermsEnabled(t *testing.T) {
+	server, accepted := newTermsServer(t)
+	d := newTermsDaemon(server)
+	d.SetAcceptTerms(true)
+
+	if err := d.authenticate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *accepted != 1 || d.token != "session-token" {
+		t.Errorf("expected the terms to be accepted at login, got %d acceptances and token %q", *accepted, d.token)
+	}
+}
+
+func TestAuthenticateOrWait_Stopped(t *testing.T) {
+	server, _ := newTermsServer(t)
+	d := newTermsDaemon(server)
+	ctx, cancel := context.WithCancel(context.Background())
+	d.ctx = ctx
+
+	done := make(chan error)
+	go func() { done <- d.authenticateOrWait() }()
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the wait to end with the daemon, got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package daemon
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/libreclient"
+)
+
+// Every request to LibreView has a timeout, yet on flaky networks (NAT tables
+// dropping idle flows, broken keepalives) a TLS connection has been seen to
+// hang past it, blocking the poll loop for good. The watchdog detects a fetch
+// cycle in flight beyond fetchCeiling and closes the connections of the
+// LibreView transport so the blocked request fails; the loop then starts over
+// with a new HTTP client.
+const (
+	fetchCeiling     = 3 * time.Minute  // A fetch cycle is a few requests of at most 30s
+	watchdogInterval = 15 * time.Second // How often the watchdog checks the fetch in flight
+)
+
+// connTracker records the open connections of a transport, so they can be
+// closed while in use: http.Transport.CloseIdleConnections leaves them open.
+type connTracker struct {
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{conns: make(map[net.Conn]struct{})}
+}
+
+// dialContext returns a dial function recording the connections of dialer.
+func (t *connTracker) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		tracked := &trackedConn{Conn: conn, tracker: t}
+		t.mu.Lock()
+		t.conns[tracked] = struct{}{}
+		t.mu.Unlock()
+		return tracked, nil
+	}
+}
+
+// closeAll closes the open connections and returns how many there were.
+func (t *connTracker) closeAll() int {
+	t.mu.Lock()
+	conns := make([]net.Conn, 0, len(t.conns))
+	for conn := range t.conns {
+		conns = append(conns, conn)
+	}
+	t.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.Close()
+	}
+	return len(conns)
+}
+
+// count returns the number of open connections.
+func (t *connTracker) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.conns)
+}
+
+// trackedConn removes itself from its tracker when closed.
+type trackedConn struct {
+	net.Conn
+	tracker *connTracker
+}
+
+func (c *trackedConn) Close() error {
+	c.tracker.mu.Lock()
+	delete(c.tracker.conns, c)
+	c.tracker.mu.Unlock()
+	return c.Conn.Close()
+}
+
+// newHTTPClient returns a client to reach LibreView whose connections are
+// recorded in conns.
+func newHTTPClient(conns *connTracker) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = conns.dialContext(dialer)
+	return &http.Client{
+		Timeout:   libreclient.DefaultTimeout,
+		Transport: transport,
+	}
+}
+
+// watchdog checks the fetch cycle in flight every watchdogInterval until the
+// daemon stops.
+func (d *Daemon) watchdog() {
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			d.checkFetch(now)
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+// checkFetch aborts the fetch cycle in flight if it started more than
+// fetchCeiling before now, once per cycle. Returns true if it did.
+func (d *Daemon) checkFetch(now time.Time) bool {
+	started := d.fetchStartedAt.Load()
+	if started == 0 || started == d.watchdogHandled {
+		return false
+	}
+	inFlight := now.Sub(time.Unix(0, started))
+	if inFlight <= fetchCeiling {
+		return false
+	}
+	d.watchdogHandled = started
+
+	closed := 0
+	if d.conns != nil {
+		closed = d.conns.closeAll()
+	}
+	d.clientStale.Store(true)
+	d.fetchStats.recordWatchdogRecovery()
+
+	slog.Error("fetch stuck, closing LibreView connections",
+		"inFlight", inFlight.Round(time.Second),
+		"ceiling", fetchCeiling,
+		"connectionsClosed", closed,
+		"goroutines", runtime.NumGoroutine(),
+		"stack", fetchStack(),
+	)
+	return true
+}
+
+// resetClient replaces the LibreView client, keeping the region in use, after
+// the watchdog aborted a fetch.
+func (d *Daemon) resetClient() {
+	region := d.client.Region()
+	if d.transport != nil {
+		d.client = libreclient.NewClient(&http.Client{
+			Timeout:   libreclient.DefaultTimeout,
+			Transport: d.transport,
+		})
+	} else {
+		d.client = libreclient.NewClient(newHTTPClient(d.conns))
+	}
+	// The region was validated when first set
+	_ = d.client.SetRegion(region)
+
+	slog.Warn("LibreView client recreated after a stuck fetch", "region", region)
+}
+
+// fetchStack returns the stack of the goroutine running the fetch cycle, to
+// tell where it is stuck.
+func fetchStack() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	var stacks []string
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		if strings.Contains(stack, "(*Daemon).fetch(") {
+			stacks = append(stacks, stack)
+		}
+	}
+	return strings.Join(stacks, "\n\n")
+}
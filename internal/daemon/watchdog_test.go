@@ -0,0 +1,102 @@
+package daemon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/libreclient"
+)
+
+func TestConnTracker_CloseAllUnblocksRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release // Never answers, like a hung connection
+	}))
+	defer server.Close()
+	defer close(release)
+
+	conns := newConnTracker()
+	client := newHTTPClient(conns)
+
+	done := make(chan error, 1)
+	go func() {
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		done <- err
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for conns.count() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the connection to be tracked")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if closed := conns.closeAll(); closed != 1 {
+		t.Errorf("expected 1 connection closed, got %d", closed)
+	}
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected the request to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the request to be unblocked")
+	}
+	if conns.count() != 0 {
+		t.Errorf("expected no connection left, got %d", conns.count())
+	}
+}
+
+func TestCheckFetch(t *testing.T) {
+	d := &Daemon{
+		client:     libreclient.NewClient(nil),
+		conns:      newConnTracker(),
+		fetchStats: newFetchRecorder(),
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	if d.checkFetch(now) {
+		t.Error("expected no recovery without a fetch in flight")
+	}
+
+	d.fetchStartedAt.Store(now.Add(-time.Minute).UnixNano())
+	if d.checkFetch(now) {
+		t.Error("expected no recovery under the ceiling")
+	}
+
+	d.fetchStartedAt.Store(now.Add(-fetchCeiling - time.Second).UnixNano())
+	if !d.checkFetch(now) || !d.clientStale.Load() {
+		t.Fatal("expected the stuck fetch to be aborted")
+	}
+	if d.checkFetch(now.Add(watchdogInterval)) {
+		t.Error("expected a stuck fetch to be aborted once")
+	}
+	if stats := d.fetchStats.snapshot(); stats.WatchdogRecoveries != 1 {
+		t.Errorf("expected 1 watchdog recovery, got %d", stats.WatchdogRecoveries)
+	}
+}
+
+func TestResetClient_KeepsRegion(t *testing.T) {
+	d := &Daemon{
+		client: libreclient.NewClient(nil),
+		conns:  newConnTracker(),
+	}
+	if err := d.client.SetRegion("eu2"); err != nil {
+		t.Fatal(err)
+	}
+	previous := d.client
+
+	d.resetClient()
+	if d.client == previous {
+		t.Fatal("expected a new client")
+	}
+	if region := d.client.Region(); region != "eu2" {
+		t.Errorf("expected region eu2, got %q", region)
+	}
+}
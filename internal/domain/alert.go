@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Alert types
+const (
+	AlertTypeLow     = "low"     // Glucose below the low threshold
+	AlertTypeFalling = "falling" // Glucose falling fast
+)
+
+// AlertTypes lists the valid alert types.
+var AlertTypes = []string{AlertTypeLow, AlertTypeFalling}
+
+// Alert records an alert fired by the daemon, so alert frequency can be audited.
+// Alerts are delivered through the log only; there are no channel results to record.
+type Alert struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	FiredAt        time.Time  `gorm:"type:datetime;not null;index:idx_alert_fired_at" json:"firedAt"` // Timestamp of the measurement that fired the alert
+	Type           string     `gorm:"type:varchar(20);not null;index:idx_alert_type" json:"type"`     // One of AlertTypes
+	ValueMgDl      int        `gorm:"type:integer;not null" json:"valueMgDl"`
+	TrendArrow     *int       `gorm:"type:integer" json:"trendArrow,omitempty"`
+	ThresholdMgDl  int        `gorm:"type:integer;not null" json:"thresholdMgDl"` // Low threshold in effect
+	Mode           Mode       `gorm:"type:varchar(20);not null" json:"mode"`      // Mode the alert was evaluated in
+	AcknowledgedAt *time.Time `gorm:"type:datetime" json:"acknowledgedAt,omitempty"`
+}
+
+// TableName specifies the table name for GORM.
+func (Alert) TableName() string {
+	return "alerts"
+}
+
+// IsAcknowledged returns true if the alert has been acknowledged.
+func (a *Alert) IsAcknowledged() bool {
+	return a.AcknowledgedAt != nil
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// MaxAttachmentSize is the maximum size of a sensor attachment (5 MiB).
+const MaxAttachmentSize = 5 << 20
+
+// AttachmentContentTypes lists the accepted attachment types, detected from the content.
+var AttachmentContentTypes = []string{"image/jpeg", "image/png", "image/webp"}
+
+// SensorAttachment is a small file linked to a sensor, such as a photo of the
+// application site or of a skin reaction. The file is stored on disk under
+// StorageKey; only its metadata is in the database.
+type SensorAttachment struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	SerialNumber string `gorm:"type:varchar(50);not null;index:idx_attachment_serial" json:"serialNumber"` // Sensor the file is linked to
+	FileName     string `gorm:"type:varchar(255);not null" json:"fileName"`                                // Name of the uploaded file
+	ContentType  string `gorm:"type:varchar(50);not null" json:"contentType"`                              // One of AttachmentContentTypes
+	Size         int64  `gorm:"type:integer;not null" json:"size"`                                         // Bytes
+	Caption      string `gorm:"type:varchar(500)" json:"caption,omitempty"`                                // Free text (e.g. "redness on day 3")
+	StorageKey   string `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`                            // Random file name in the attachment directory
+}
+
+// TableName specifies the table name for GORM.
+func (SensorAttachment) TableName() string {
+	return "sensor_attachments"
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ConnectionSourceLibreLinkUp identifies data fetched from LibreLinkUp.
+const ConnectionSourceLibreLinkUp = "librelinkup"
+
+// LibreLinkUp data cadence
+const (
+	// CurrentReadingInterval is the interval between current readings.
+	CurrentReadingInterval = time.Minute
+	// HistoricalReadingInterval is the interval between historical readings.
+	HistoricalReadingInterval = 15 * time.Minute
+)
+
+// ConnectionInfo describes the upstream connection glucose data is fetched from.
+// Source: /llu/connections → the connection of the followed patient
+// Note: This is not persisted to the database, it's kept by the daemon
+type ConnectionInfo struct {
+	Source          string    `json:"source"`          // Upstream the data comes from (librelinkup)
+	PatientID       string    `json:"patientId"`       // patientId: Followed patient
+	PatientInitials string    `json:"patientInitials"` // Initials only, the full name is never exposed
+	Country         string    `json:"country"`         // country: Country code of the account
+	SensorBrand     string    `json:"sensorBrand"`     // Derived from the sensor type
+	SensorType      int       `json:"sensorType"`      // pt: Sensor type
+	ConnectedAt     time.Time `json:"connectedAt"`     // First successful fetch
+	LastFetchAt     time.Time `json:"lastFetchAt"`
+
+	// Data delay characteristics
+	CurrentIntervalSeconds    int     `json:"currentIntervalSeconds"`    // Interval between current readings
+	HistoricalIntervalSeconds int     `json:"historicalIntervalSeconds"` // Interval between historical readings
+	LastDelaySeconds          float64 `json:"lastDelaySeconds"`          // Age of the latest reading when fetched
+	AverageDelaySeconds       float64 `json:"averageDelaySeconds"`       // Average age of new readings when fetched
+
+	// Patients followed by the account, in upstream order
+	Patients []ConnectionPatient `json:"patients"`
+}
+
+// ConnectionPatient is a patient followed by the LibreLinkUp account.
+type ConnectionPatient struct {
+	PatientID string `json:"patientId"`
+	Initials  string `json:"initials"` // Initials only, the full name is never exposed
+	Stored    bool   `json:"stored"`   // Readings are stored (followed patient, or all patients in multi-patient mode)
+}
+
+// Initials returns the uppercase initials of a first and last name (e.g. "J.D.").
+func Initials(firstName, lastName string) string {
+	var sb strings.Builder
+	for _, name := range []string{firstName, lastName} {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		r, _ := utf8.DecodeRuneInString(name)
+		sb.WriteString(strings.ToUpper(string(r)))
+		sb.WriteString(".")
+	}
+	return sb.String()
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// DailySummary aggregates the measurements of a patient over one local day,
+// so long periods can be charted without reading the raw measurements.
+// Summaries are kept once the raw measurements are pruned by the retention
+// job.
+type DailySummary struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	UpdatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"` // Last time the day was computed
+
+	// LibreLinkUp patient the measurements belong to (empty for rows stored before multi-patient support)
+	PatientID string `gorm:"type:varchar(64);not null;default:'';uniqueIndex:idx_daily_summary_date,priority:1" json:"patientId,omitempty"`
+	Date      string `gorm:"type:varchar(10);not null;uniqueIndex:idx_daily_summary_date,priority:2" json:"date"` // YYYY-MM-DD, local time
+
+	Count          int     `gorm:"type:integer;not null" json:"count"`             // Measurements summarized
+	Average        float64 `gorm:"type:decimal(10,2);not null" json:"average"`     // mmol/L
+	AverageMgDl    float64 `gorm:"type:decimal(10,1);not null" json:"averageMgDl"` // mg/dL
+	MinMgDl        int     `gorm:"type:integer;not null" json:"minMgDl"`
+	MaxMgDl        int     `gorm:"type:integer;not null" json:"maxMgDl"`
+	TimeInRange    float64 `gorm:"type:decimal(5,1);not null" json:"timeInRange"`    // % of readings within the targets
+	TimeBelowRange float64 `gorm:"type:decimal(5,1);not null" json:"timeBelowRange"` // % of readings below the low target
+	TimeAboveRange float64 `gorm:"type:decimal(5,1);not null" json:"timeAboveRange"` // % of readings above the high target
+	LowCount       int     `gorm:"type:integer;not null" json:"lowCount"`            // Readings below the low target
+	HighCount      int     `gorm:"type:integer;not null" json:"highCount"`           // Readings above the high target
+	GMI            float64 `gorm:"type:decimal(5,2);not null" json:"gmi"`            // Glucose Management Indicator (%) of the day's average
+	TargetLowMgDl  int     `gorm:"type:integer;not null" json:"targetLowMgDl"`       // Targets in effect
+	TargetHighMgDl int     `gorm:"type:integer;not null" json:"targetHighMgDl"`
+}
+
+// TableName specifies the table name for GORM.
+func (DailySummary) TableName() string {
+	return "daily_summaries"
+}
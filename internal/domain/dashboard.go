@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Dashboard card types
+const (
+	DashboardCardLatest      = "latest"      // Latest reading with trend arrow
+	DashboardCardChart       = "chart"       // Glucose chart over ChartRange
+	DashboardCardStatistics  = "statistics"  // Average, min/max, standard deviation
+	DashboardCardTimeInRange = "timeInRange" // Time in range distribution
+	DashboardCardGMI         = "gmi"         // Glucose Management Indicator
+	DashboardCardSensor      = "sensor"      // Current sensor lifetime
+)
+
+// DashboardCardTypes lists the valid dashboard card types in their default order.
+var DashboardCardTypes = []string{
+	DashboardCardLatest,
+	DashboardCardChart,
+	DashboardCardStatistics,
+	DashboardCardTimeInRange,
+	DashboardCardGMI,
+	DashboardCardSensor,
+}
+
+// DashboardConfig represents the layout of the embedded dashboard.
+// This is a singleton: only one record is expected.
+type DashboardConfig struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	UpdatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
+
+	Cards          DashboardCards `gorm:"type:text;not null" json:"cards"`             // Cards displayed, in order (stored as JSON)
+	ChartRange     string         `gorm:"type:varchar(10);not null" json:"chartRange"` // Default chart period (e.g. "24h", "7d")
+	ShowThresholds bool           `gorm:"type:boolean;not null" json:"showThresholds"` // Draw target low/high lines on charts
+	Unit           string         `gorm:"type:varchar(10);not null" json:"unit"`       // Display unit: "mmol" or "mgdl"
+	RefreshSeconds int            `gorm:"type:integer;not null" json:"refreshSeconds"` // Polling interval when SSE is unavailable
+}
+
+// TableName specifies the table name for GORM.
+func (DashboardConfig) TableName() string {
+	return "dashboard_configs"
+}
+
+// DashboardCard is a single card of the dashboard.
+type DashboardCard struct {
+	Type   string `json:"type"`             // One of DashboardCardTypes
+	Title  string `json:"title,omitempty"`  // Optional custom title
+	Period string `json:"period,omitempty"` // Optional period override (e.g. "14d" for statistics)
+}
+
+// DefaultDashboardConfig returns the layout used until a custom one is saved.
+func DefaultDashboardConfig() *DashboardConfig {
+	cards := make(DashboardCards, 0, len(DashboardCardTypes))
+	for _, t := range DashboardCardTypes {
+		cards = append(cards, DashboardCard{Type: t})
+	}
+
+	return &DashboardConfig{
+		Cards:          cards,
+		ChartRange:     "24h",
+		ShowThresholds: true,
+		Unit:           "mmol",
+		RefreshSeconds: 60,
+	}
+}
+
+// DashboardCards is a custom type for storing []DashboardCard as JSON in the database.
+type DashboardCards []DashboardCard
+
+// Scan implements the sql.Scanner interface for reading from the database.
+func (c *DashboardCards) Scan(value interface{}) error {
+	if value == nil {
+		*c = DashboardCards{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to unmarshal DashboardCards value")
+	}
+
+	return json.Unmarshal(bytes, c)
+}
+
+// Value implements the driver.Valuer interface for writing to the database.
+func (c DashboardCards) Value() (driver.Value, error) {
+	if len(c) == 0 {
+		return "[]", nil
+	}
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
 
 // DeviceInfo represents patient device information and configuration.
 // Source: /llu/connections → data[0].patientDevice
@@ -18,6 +23,8 @@ type DeviceInfo struct {
 	HighLimit        int       `gorm:"type:integer" json:"highLimit"`                                // hl: High glucose limit threshold
 	LowLimit         int       `gorm:"type:integer" json:"lowLimit"`                                 // ll: Low glucose limit threshold
 	FixedLowThreshold int      `gorm:"type:integer" json:"fixedLowThreshold"`                        // fixedLowThreshold: Fixed low threshold value
+	FixedLowAlarm    FixedLowAlarmValues `gorm:"embedded;embeddedPrefix:fixed_low_alarm_" json:"fixedLowAlarm"` // fixedLowAlarmValues: Urgent low alarm level
+	AlarmRules       AlarmRules `gorm:"type:text" json:"alarmRules"`                                   // alarmRules: Alarms set in the LibreLinkUp app (Source: data[0].alarmRules)
 
 	// Additional metadata
 	LastUpdate       time.Time `gorm:"type:datetime" json:"lastUpdate"`                              // u: Last update timestamp (Unix)
@@ -29,10 +36,89 @@ func (DeviceInfo) TableName() string {
 	return "device_info"
 }
 
+// SameAlarmSettings reports whether o describes the same device with the same
+// alarm settings. Database fields and the update timestamp are ignored.
+func (d *DeviceInfo) SameAlarmSettings(o *DeviceInfo) bool {
+	return d.DeviceID == o.DeviceID &&
+		d.DeviceTypeID == o.DeviceTypeID &&
+		d.AppVersion == o.AppVersion &&
+		d.AlarmsEnabled == o.AlarmsEnabled &&
+		d.HighLimit == o.HighLimit &&
+		d.LowLimit == o.LowLimit &&
+		d.FixedLowThreshold == o.FixedLowThreshold &&
+		d.FixedLowAlarm == o.FixedLowAlarm &&
+		d.AlarmRules == o.AlarmRules &&
+		d.LimitEnabled == o.LimitEnabled
+}
+
+// LowAlertMgDl returns the low glucose alarm level set in the LibreLink app,
+// or 0 when the app alarms are disabled.
+func (d *DeviceInfo) LowAlertMgDl() int {
+	if !d.AlarmsEnabled {
+		return 0
+	}
+	return d.LowLimit
+}
+
+// ConfigChange is published when settings made in the LibreLink app or the
+// display preferences change. Only the changed part is set.
+type ConfigChange struct {
+	Device      *DeviceInfo         `json:"device,omitempty"`
+	Targets     *GlucoseTargets     `json:"targets,omitempty"`
+	Preferences *DisplayPreferences `json:"preferences,omitempty"`
+}
+
 // FixedLowAlarmValues represents fixed alarm threshold values in both units.
 // Source: /llu/connections → data[0].patientDevice.fixedLowAlarmValues
-// Note: This is not persisted to the database, it's a transient value from the API
+// Zero when the app does not report it.
 type FixedLowAlarmValues struct {
-	MgPerDl  int     // mgdl: Threshold in mg/dL
-	MmolPerL float64 // mmoll: Threshold in mmol/L
+	MgPerDl  int     `gorm:"type:integer" json:"mgPerDl"` // mgdl: Threshold in mg/dL
+	MmolPerL float64 `gorm:"type:real" json:"mmolPerL"`   // mmoll: Threshold in mmol/L
+}
+
+// AlarmRule is a glucose alarm set in the LibreLinkUp app.
+type AlarmRule struct {
+	Enabled       bool    `json:"enabled"`       // on: Whether the alarm is on
+	MgPerDl       int     `json:"mgPerDl"`       // th: Threshold in mg/dL
+	MmolPerL      float64 `json:"mmolPerL"`      // thmm: Threshold in mmol/L
+	RepeatMinutes int     `json:"repeatMinutes"` // d: Minutes before the alarm sounds again
+}
+
+// AlarmRules are the alarms set in the LibreLinkUp app, stored as JSON.
+// Source: /llu/connections → data[0].alarmRules
+type AlarmRules struct {
+	Configured        bool      `json:"configured"`        // c: Whether the follower configured the alarms
+	High              AlarmRule `json:"high"`              // h: High glucose alarm
+	Low               AlarmRule `json:"low"`               // l: Low glucose alarm
+	UrgentLow         AlarmRule `json:"urgentLow"`         // f: Fixed (urgent) low alarm
+	SignalLossMinutes int       `json:"signalLossMinutes"` // nd.i: Minutes without data before the signal loss alarm
+}
+
+// Scan implements the sql.Scanner interface for reading from the database.
+func (a *AlarmRules) Scan(value interface{}) error {
+	if value == nil {
+		*a = AlarmRules{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to unmarshal AlarmRules value")
+	}
+
+	return json.Unmarshal(bytes, a)
+}
+
+// Value implements the driver.Valuer interface for writing to the database.
+func (a AlarmRules) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
 }
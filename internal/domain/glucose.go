@@ -42,8 +42,11 @@ type GlucoseMeasurement struct {
 	ID        uint      `gorm:"primaryKey" json:"-"`
 	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
 
+	// LibreLinkUp patient the measurement belongs to (empty for rows stored before multi-patient support)
+	PatientID string `gorm:"type:varchar(64);not null;default:'';uniqueIndex:idx_unique_patient_factory_ts,priority:1" json:"patientId,omitempty"`
+
 	// Timestamps
-	FactoryTimestamp time.Time `gorm:"type:datetime;not null;uniqueIndex:idx_unique_factory_ts" json:"factoryTimestamp"` // Timestamp from the sensor (factory time), used for deduplication
+	FactoryTimestamp time.Time `gorm:"type:datetime;not null;uniqueIndex:idx_unique_patient_factory_ts,priority:2" json:"factoryTimestamp"` // Timestamp from the sensor (factory time), used for deduplication per patient
 	Timestamp        time.Time `gorm:"type:datetime;not null;index:idx_timestamp" json:"timestamp"` // Real timestamp (phone time), stored in UTC
 
 	// Glucose values
@@ -60,6 +63,11 @@ type GlucoseMeasurement struct {
 	IsHigh           bool `gorm:"type:boolean;not null;default:false" json:"isHigh"`             // Above high threshold
 	IsLow            bool `gorm:"type:boolean;not null;default:false" json:"isLow"`              // Below low threshold
 	Type             int  `gorm:"type:integer;not null;index:idx_type" json:"type"`              // 0=historical, 1=current measurement
+
+	// Fetch provenance (only exposed with ?debug=true)
+	FetchCycleID string     `gorm:"type:text;index:idx_fetch_cycle" json:"-"` // ID of the daemon poll that stored the measurement, empty for imported rows
+	FetchedAt    *time.Time `gorm:"type:datetime" json:"-"`                   // When that poll received the measurement from LibreView
+	FetchSource  string     `gorm:"type:text" json:"-"`                       // Object of the /connections payload a current measurement was read from (glucoseMeasurement or glucoseItem)
 }
 
 // TableName specifies the table name for GORM.
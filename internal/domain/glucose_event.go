@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Glucose event types
+const (
+	GlucoseEventTypeLow  = "low"  // Readings below the target range (hypoglycemia)
+	GlucoseEventTypeHigh = "high" // Readings above the target range (hyperglycemia)
+)
+
+// GlucoseEventTypes lists the valid glucose event types.
+var GlucoseEventTypes = []string{GlucoseEventTypeLow, GlucoseEventTypeHigh}
+
+// GlucoseEvent is an episode of consecutive readings below or above the
+// target range, detected from the stored measurements.
+//
+// Extreme is the nadir of a low event and the peak of a high event.
+type GlucoseEvent struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	PatientID       string    `gorm:"type:varchar(64);not null;default:'';uniqueIndex:idx_glucose_event_patient_start,priority:1" json:"patientId,omitempty"` // LibreLinkUp patient of the readings
+	Type            string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_glucose_event_patient_start,priority:3" json:"type"`                           // One of GlucoseEventTypes
+	StartTime       time.Time `gorm:"type:datetime;not null;uniqueIndex:idx_glucose_event_patient_start,priority:2" json:"startTime"`                         // First reading out of range
+	EndTime         time.Time `gorm:"type:datetime;not null;index:idx_glucose_event_end" json:"endTime"`                                                      // Last reading out of range
+	DurationMinutes int       `gorm:"type:integer;not null" json:"durationMinutes"`
+	Readings        int       `gorm:"type:integer;not null" json:"readings"`
+	Extreme         float64   `gorm:"type:decimal(10,2);not null" json:"extreme"` // mmol/L
+	ExtremeMgDl     int       `gorm:"type:integer;not null" json:"extremeMgDl"`
+	ExtremeTime     time.Time `gorm:"type:datetime;not null" json:"extremeTime"`
+	ThresholdMgDl   int       `gorm:"type:integer;not null" json:"thresholdMgDl"`         // Target bound in effect
+	Ongoing         bool      `gorm:"type:boolean;not null;default:false" json:"ongoing"` // The latest reading is still out of range
+}
+
+// TableName specifies the table name for GORM.
+func (GlucoseEvent) TableName() string {
+	return "glucose_events"
+}
@@ -10,3 +10,15 @@ func CalculateGMI(averageMgDl float64) *float64 {
 	gmi := 3.31 + 0.02392*averageMgDl
 	return &gmi
 }
+
+// CalculateEstimatedA1c computes the estimated A1c from average glucose in mg/dL,
+// using the ADAG regression (Nathan et al., 2008).
+// Formula: eA1c(%) = ([mean glucose in mg/dL] + 46.7) / 28.7
+// Returns nil if averageMgDl <= 0.
+func CalculateEstimatedA1c(averageMgDl float64) *float64 {
+	if averageMgDl <= 0 {
+		return nil
+	}
+	a1c := (averageMgDl + 46.7) / 28.7
+	return &a1c
+}
@@ -37,3 +37,36 @@ func TestCalculateGMI(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateEstimatedA1c(t *testing.T) {
+	tests := []struct {
+		name        string
+		averageMgDl float64
+		wantNil     bool
+		wantValue   float64
+	}{
+		{"typical value 154 mg/dL", 154, false, 7.0},
+		{"low value 126 mg/dL", 126, false, 6.02},
+		{"high value 240 mg/dL", 240, false, 9.99},
+		{"zero returns nil", 0, true, 0},
+		{"negative returns nil", -10, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateEstimatedA1c(tt.averageMgDl)
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("CalculateEstimatedA1c(%v) = %v, want nil", tt.averageMgDl, *got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("CalculateEstimatedA1c(%v) = nil, want %v", tt.averageMgDl, tt.wantValue)
+			}
+			if math.Abs(*got-tt.wantValue) > 0.01 {
+				t.Errorf("CalculateEstimatedA1c(%v) = %v, want ~%v", tt.averageMgDl, *got, tt.wantValue)
+			}
+		})
+	}
+}
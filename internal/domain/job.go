@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Job statuses
+const (
+	JobStatusPending   = "pending"   // Waiting for a worker: new, or retried after a failed attempt
+	JobStatusRunning   = "running"   // Claimed by a worker
+	JobStatusSucceeded = "succeeded" // Completed
+	JobStatusFailed    = "failed"    // Failed on its last attempt
+	JobStatusCanceled  = "canceled"  // Canceled before completing
+)
+
+// JobStatuses lists the valid job statuses.
+var JobStatuses = []string{JobStatusPending, JobStatusRunning, JobStatusSucceeded, JobStatusFailed, JobStatusCanceled}
+
+// Job is a unit of background work run by the job workers: imports,
+// scheduled maintenance, reports and integrations. Jobs are stored so they
+// survive restarts and can be observed after they finished.
+type Job struct {
+	// Database fields
+	ID        string    `gorm:"primaryKey;type:varchar(36)" json:"id"` // UUID
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	Type        string     `gorm:"type:varchar(50);not null;index:idx_job_type" json:"type"`
+	Schedule    string     `gorm:"type:varchar(100)" json:"schedule,omitempty"`                                    // Schedule that enqueued the job, empty when on demand
+	Status      string     `gorm:"type:varchar(20);not null;index:idx_job_status_run_at,priority:1" json:"status"` // One of JobStatuses
+	RunAt       time.Time  `gorm:"type:datetime;not null;index:idx_job_status_run_at,priority:2" json:"runAt"`     // Earliest start (later for retries)
+	Attempts    int        `gorm:"type:integer;not null;default:0" json:"attempts"`                                // Attempts started so far
+	MaxAttempts int        `gorm:"type:integer;not null;default:1" json:"maxAttempts"`                             // Attempts before the job fails
+	StartedAt   *time.Time `gorm:"type:datetime" json:"startedAt,omitempty"`                                       // Start of the last attempt
+	FinishedAt  *time.Time `gorm:"type:datetime;index:idx_job_finished_at" json:"finishedAt,omitempty"`            // Set once succeeded, failed or canceled
+	Done        int        `gorm:"type:integer;not null;default:0" json:"done"`                                    // Items processed
+	Total       int        `gorm:"type:integer;not null;default:0" json:"total"`                                   // Items to process, 0 if unknown
+	Errors      JobErrors  `gorm:"type:text" json:"errors,omitempty"`                                              // Non-fatal errors (e.g. skipped rows)
+	Error       string     `gorm:"type:text" json:"error,omitempty"`                                               // Error of the last failed attempt
+	Payload     JobData    `gorm:"type:text" json:"-"`                                                             // Input of the job, as JSON
+	Result      JobData    `gorm:"type:text" json:"result,omitempty"`                                              // Output of the job once succeeded, as JSON
+}
+
+// TableName specifies the table name for GORM.
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// IsFinished returns true if the job succeeded, failed or was canceled.
+func (j *Job) IsFinished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed || j.Status == JobStatusCanceled
+}
+
+// JobErrors is a custom type for storing []string as JSON in the database.
+type JobErrors []string
+
+// Scan implements the sql.Scanner interface for reading from the database.
+func (e *JobErrors) Scan(value interface{}) error {
+	if value == nil {
+		*e = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to unmarshal JobErrors value")
+	}
+
+	return json.Unmarshal(bytes, e)
+}
+
+// Value implements the driver.Valuer interface for writing to the database.
+func (e JobErrors) Value() (driver.Value, error) {
+	if len(e) == 0 {
+		return nil, nil
+	}
+	bytes, err := json.Marshal([]string(e))
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
+
+// JobData is a JSON document stored as text (job payloads and results).
+type JobData json.RawMessage
+
+// MarshalJSON returns the document as is.
+func (d JobData) MarshalJSON() ([]byte, error) {
+	if len(d) == 0 {
+		return []byte("null"), nil
+	}
+	return d, nil
+}
+
+// UnmarshalJSON keeps a copy of the document.
+func (d *JobData) UnmarshalJSON(data []byte) error {
+	*d = append((*d)[:0], data...)
+	return nil
+}
+
+// Scan implements the sql.Scanner interface for reading from the database.
+func (d *JobData) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = nil
+	case []byte:
+		*d = append(JobData(nil), v...)
+	case string:
+		*d = JobData(v)
+	default:
+		return errors.New("failed to scan JobData value")
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface for writing to the database.
+func (d JobData) Value() (driver.Value, error) {
+	if len(d) == 0 {
+		return nil, nil
+	}
+	return string(d), nil
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// Insulin types
+const (
+	InsulinTypeRapid = "rapid" // Rapid-acting: meal and correction boluses
+	InsulinTypeLong  = "long"  // Long-acting: basal injections
+)
+
+// InsulinTypes lists the valid insulin types.
+var InsulinTypes = []string{InsulinTypeRapid, InsulinTypeLong}
+
+// Logbook limits: larger values are typos rather than doses
+const (
+	MaxInsulinUnits = 100.0 // Units per dose
+	MaxCarbsGrams   = 500.0 // Grams per intake
+)
+
+// InsulinDose is an insulin injection logged by the user. Pump deliveries
+// are imported as TreatmentEntry instead.
+type InsulinDose struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	Timestamp time.Time `gorm:"type:datetime;not null;index:idx_insulin_timestamp" json:"timestamp"` // Time of the injection, stored in UTC
+	Units     float64   `gorm:"type:decimal(10,2);not null" json:"units"`                            // Units injected
+	Type      string    `gorm:"type:varchar(10);not null" json:"type"`                               // One of InsulinTypes
+}
+
+// TableName specifies the table name for GORM.
+func (InsulinDose) TableName() string {
+	return "insulin_doses"
+}
+
+// CarbIntake is a carbohydrate intake logged by the user.
+type CarbIntake struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	Timestamp time.Time `gorm:"type:datetime;not null;index:idx_carb_timestamp" json:"timestamp"` // Time of the meal or snack, stored in UTC
+	Grams     float64   `gorm:"type:decimal(10,1);not null" json:"grams"`                         // Grams of carbohydrates
+}
+
+// TableName specifies the table name for GORM.
+func (CarbIntake) TableName() string {
+	return "carb_intakes"
+}
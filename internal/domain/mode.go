@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// Mode is the activity mode glucose alerts are evaluated in.
+type Mode string
+
+const (
+	// ModeNormal evaluates alerts against the default thresholds.
+	ModeNormal Mode = "normal"
+	// ModeExercise raises the low threshold and alerts on slower falls, since
+	// glucose drops faster and hypoglycemia is harder to notice during exercise.
+	ModeExercise Mode = "exercise"
+)
+
+// Alert threshold defaults and exercise adjustments
+const (
+	// DefaultLowAlertMgDl is the low glucose alert threshold (level 1 hypoglycemia).
+	DefaultLowAlertMgDl = 70
+	// ExerciseLowAlertRaiseMgDl is added to the low alert threshold in exercise mode.
+	ExerciseLowAlertRaiseMgDl = 30
+)
+
+// AlertThresholds are the limits glucose alerts are evaluated against.
+type AlertThresholds struct {
+	LowMgDl   int `json:"lowMgDl"`   // Alert below this value
+	FallTrend int `json:"fallTrend"` // Alert on trend arrows at or below this one (TrendArrowFallingRapidly or TrendArrowFalling)
+}
+
+// DefaultAlertThresholds returns the thresholds of ModeNormal.
+func DefaultAlertThresholds() AlertThresholds {
+	return AlertThresholds{
+		LowMgDl:   DefaultLowAlertMgDl,
+		FallTrend: TrendArrowFallingRapidly,
+	}
+}
+
+// ExerciseAlertThresholds returns the thresholds of ModeExercise.
+func ExerciseAlertThresholds() AlertThresholds {
+	return AlertThresholds{
+		LowMgDl:   DefaultLowAlertMgDl + ExerciseLowAlertRaiseMgDl,
+		FallTrend: TrendArrowFalling,
+	}
+}
+
+// IsLow reports whether a measurement is below the low alert threshold.
+func (t AlertThresholds) IsLow(m *GlucoseMeasurement) bool {
+	return m.ValueInMgPerDl < t.LowMgDl
+}
+
+// IsFalling reports whether a measurement falls fast enough to alert.
+// Historical measurements without a trend arrow never do.
+func (t AlertThresholds) IsFalling(m *GlucoseMeasurement) bool {
+	return m.TrendArrow != nil && *m.TrendArrow <= t.FallTrend
+}
+
+// ModeStatus is the current mode and the thresholds it applies.
+type ModeStatus struct {
+	Mode       Mode            `json:"mode"`
+	EndsAt     *time.Time      `json:"endsAt,omitempty"` // When a temporary mode reverts to ModeNormal
+	Thresholds AlertThresholds `json:"thresholds"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "testing"
+
+func TestAlertThresholds(t *testing.T) {
+	falling := TrendArrowFalling
+	stable := TrendArrowStable
+
+	tests := []struct {
+		name        string
+		thresholds  AlertThresholds
+		measurement GlucoseMeasurement
+		wantLow     bool
+		wantFalling bool
+	}{
+		{"normal in range", DefaultAlertThresholds(), GlucoseMeasurement{ValueInMgPerDl: 90, TrendArrow: &stable}, false, false},
+		{"normal low", DefaultAlertThresholds(), GlucoseMeasurement{ValueInMgPerDl: 65, TrendArrow: &stable}, true, false},
+		{"normal falling", DefaultAlertThresholds(), GlucoseMeasurement{ValueInMgPerDl: 90, TrendArrow: &falling}, false, false},
+		{"exercise low", ExerciseAlertThresholds(), GlucoseMeasurement{ValueInMgPerDl: 90, TrendArrow: &stable}, true, false},
+		{"exercise falling", ExerciseAlertThresholds(), GlucoseMeasurement{ValueInMgPerDl: 140, TrendArrow: &falling}, false, true},
+		{"no trend arrow", ExerciseAlertThresholds(), GlucoseMeasurement{ValueInMgPerDl: 140}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.thresholds.IsLow(&tt.measurement); got != tt.wantLow {
+				t.Errorf("IsLow = %v, want %v", got, tt.wantLow)
+			}
+			if got := tt.thresholds.IsFalling(&tt.measurement); got != tt.wantFalling {
+				t.Errorf("IsFalling = %v, want %v", got, tt.wantFalling)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Note tags: what a note is about
+const (
+	NoteTagMeal     = "meal"
+	NoteTagInsulin  = "insulin"
+	NoteTagExercise = "exercise"
+	NoteTagSleep    = "sleep"
+	NoteTagIllness  = "illness"
+	NoteTagStress   = "stress"
+	NoteTagOther    = "other"
+)
+
+// NoteTags lists the valid note tags.
+var NoteTags = []string{NoteTagMeal, NoteTagInsulin, NoteTagExercise, NoteTagSleep, NoteTagIllness, NoteTagStress, NoteTagOther}
+
+// MaxNoteTextLength limits the text of a note, in characters.
+const MaxNoteTextLength = 1000
+
+// Note is an annotation recorded by the user at a point in time, such as a
+// meal or a workout, to explain glucose excursions.
+type Note struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
+
+	Timestamp time.Time `gorm:"type:datetime;not null;index:idx_note_timestamp" json:"timestamp"` // Time the note refers to, stored in UTC
+	Text      string    `gorm:"type:text;not null" json:"text"`                                   // Free text (at most MaxNoteTextLength characters)
+	Tags      TagList   `gorm:"type:text" json:"tags"`                                            // Subset of NoteTags, sorted (stored as JSON)
+}
+
+// TableName specifies the table name for GORM.
+func (Note) TableName() string {
+	return "notes"
+}
+
+// TagList is a custom type for storing []string as JSON in the database.
+type TagList []string
+
+// Scan implements the sql.Scanner interface for reading from the database.
+func (l *TagList) Scan(value interface{}) error {
+	if value == nil {
+		*l = TagList{}
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return errors.New("failed to unmarshal TagList value")
+	}
+}
+
+// Value implements the driver.Valuer interface for writing to the database.
+func (l TagList) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return "[]", nil
+	}
+	bytes, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
+
+// MarshalJSON writes an empty list instead of null.
+func (l TagList) MarshalJSON() ([]byte, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(l))
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// Upstream failure causes
+const (
+	OutageCauseNetwork   = "network"   // LibreView unreachable (DNS, connection refused, timeout)
+	OutageCauseServer    = "server"    // LibreView answered with a server error
+	OutageCauseRateLimit = "rateLimit" // LibreView rate-limited the account
+	OutageCauseAuth      = "auth"      // Credentials rejected: most likely a setup problem
+)
+
+// UpstreamOutage records a period during which fetching from LibreView failed.
+// An outage without EndedAt is still ongoing.
+type UpstreamOutage struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"-"`
+
+	StartedAt time.Time  `gorm:"type:datetime;not null;index:idx_outage_started_at" json:"startedAt"` // First failed fetch
+	EndedAt   *time.Time `gorm:"type:datetime" json:"endedAt,omitempty"`                              // First successful fetch after the outage
+	Cause     string     `gorm:"type:varchar(20);not null" json:"cause"`                              // Cause of the first failure
+	LastError string     `gorm:"type:varchar(255)" json:"lastError,omitempty"`
+	Failures  int        `gorm:"type:integer;not null" json:"failures"` // Failed fetches during the outage
+}
+
+// TableName specifies the table name for GORM.
+func (UpstreamOutage) TableName() string {
+	return "upstream_outages"
+}
+
+// IsOngoing returns true if the outage has not ended yet.
+func (o *UpstreamOutage) IsOngoing() bool {
+	return o.EndedAt == nil
+}
+
+// IsSetupProblem returns true if the outage is most likely caused by the local
+// setup (rejected credentials) rather than by LibreView being unavailable.
+func (o *UpstreamOutage) IsSetupProblem() bool {
+	return o.Cause == OutageCauseAuth
+}
+
+// Duration returns the length of the outage, up to now if it is still ongoing.
+func (o *UpstreamOutage) Duration(now time.Time) time.Duration {
+	if o.EndedAt != nil {
+		return o.EndedAt.Sub(o.StartedAt)
+	}
+	return now.Sub(o.StartedAt)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Display units
+const (
+	DisplayUnitMmol = "mmol" // mmol/L
+	DisplayUnitMgDl = "mgdl" // mg/dL
+)
+
+// DisplayPreferences are the display settings shared by the API convenience
+// fields, glcli and the dashboard, so they are set once on the server.
+// This is a singleton: only one record is expected.
+type DisplayPreferences struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	UpdatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
+
+	Unit          string `gorm:"type:varchar(10);not null" json:"unit"`       // DisplayUnitMmol or DisplayUnitMgDl
+	Timezone      string `gorm:"type:varchar(64)" json:"timezone"`            // IANA name (e.g. "Europe/Zurich"), empty = local time of the client
+	Emoji         bool   `gorm:"type:boolean;not null" json:"emoji"`          // Show emoji status and trend indicators
+	TightLowMgDl  int    `gorm:"type:integer;not null" json:"tightLowMgDl"`   // Lower bound of the tight range band (Time in Tight Range)
+	TightHighMgDl int    `gorm:"type:integer;not null" json:"tightHighMgDl"`  // Upper bound of the tight range band
+	ChartRange    string `gorm:"type:varchar(10);not null" json:"chartRange"` // Default chart period of the dashboard (e.g. "24h")
+}
+
+// TableName specifies the table name for GORM.
+func (DisplayPreferences) TableName() string {
+	return "display_preferences"
+}
+
+// DefaultDisplayPreferences returns the preferences used until some are saved.
+func DefaultDisplayPreferences() *DisplayPreferences {
+	tight := DefaultTargetBands()[0]
+	return &DisplayPreferences{
+		Unit:          DisplayUnitMmol,
+		Emoji:         true,
+		TightLowMgDl:  tight.LowMgDl,
+		TightHighMgDl: tight.HighMgDl,
+		ChartRange:    "24h",
+	}
+}
+
+// TightBand returns the tight range band of the preferences.
+func (p *DisplayPreferences) TightBand() TargetBand {
+	return TargetBand{Name: TargetBandTight, LowMgDl: p.TightLowMgDl, HighMgDl: p.TightHighMgDl}
+}
+
+// Location returns the time zone of the preferences, or time.Local when
+// none is set or it is unknown.
+func (p *DisplayPreferences) Location() *time.Location {
+	if p.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// FormatValue formats a glucose value in the preferred unit (e.g. "5.4 mmol/L").
+func (p *DisplayPreferences) FormatValue(m *GlucoseMeasurement) string {
+	if p.Unit == DisplayUnitMgDl {
+		return fmt.Sprintf("%d mg/dL", m.ValueInMgPerDl)
+	}
+	return fmt.Sprintf("%.1f mmol/L", m.Value)
+}
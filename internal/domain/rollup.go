@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// GlucoseRollupInterval is the span of the measurements averaged in a rollup.
+const GlucoseRollupInterval = 15 * time.Minute
+
+// GlucoseRollup averages the measurements of a patient over
+// GlucoseRollupInterval. Rollups keep the history at a lower resolution once
+// the raw measurements are pruned by the retention job.
+type GlucoseRollup struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"-"` // Start of the retention run that last computed it
+
+	// LibreLinkUp patient the measurements belong to (empty for rows stored before multi-patient support)
+	PatientID string    `gorm:"type:varchar(64);not null;default:'';uniqueIndex:idx_glucose_rollup_start,priority:1" json:"patientId,omitempty"`
+	StartTime time.Time `gorm:"type:datetime;not null;uniqueIndex:idx_glucose_rollup_start,priority:2" json:"startTime"` // Start of the interval, in UTC
+
+	Count          int     `gorm:"type:integer;not null" json:"count"`          // Measurements averaged
+	Value          float64 `gorm:"type:decimal(10,2);not null" json:"value"`    // Average in mmol/L
+	ValueInMgPerDl int     `gorm:"type:integer;not null" json:"valueInMgPerDl"` // Average in mg/dL, rounded
+	MinMgDl        int     `gorm:"type:integer;not null" json:"minMgDl"`        // Lowest measurement
+	MaxMgDl        int     `gorm:"type:integer;not null" json:"maxMgDl"`        // Highest measurement
+}
+
+// TableName specifies the table name for GORM.
+func (GlucoseRollup) TableName() string {
+	return "glucose_rollups"
+}
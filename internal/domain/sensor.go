@@ -12,12 +12,45 @@ const (
 	SensorStatusStopped SensorStatus = "stopped"
 	// SensorStatusUnresponsive indicates the sensor is not sending data (no measurement for > 20 min).
 	SensorStatusUnresponsive SensorStatus = "unresponsive"
+	// SensorStatusGrace indicates the sensor has expired but still reports within its grace period.
+	SensorStatusGrace SensorStatus = "grace"
 )
 
 // UnresponsiveThreshold is the duration after which a sensor is considered unresponsive
 // if no measurements have been received.
 const UnresponsiveThreshold = 20 * time.Minute
 
+// DefaultSensorGracePeriod is how long a Libre sensor keeps reporting after its
+// nominal expiry. Libre sensors end their session about 12 hours past ExpiresAt.
+const DefaultSensorGracePeriod = 12 * time.Hour
+
+// Sensor application sites (where on the body the sensor is applied)
+const (
+	SensorSiteLeftArm      = "left-arm"
+	SensorSiteRightArm     = "right-arm"
+	SensorSiteLeftAbdomen  = "left-abdomen"
+	SensorSiteRightAbdomen = "right-abdomen"
+	SensorSiteLeftThigh    = "left-thigh"
+	SensorSiteRightThigh   = "right-thigh"
+)
+
+// SensorApplicationSites lists the valid sensor application sites.
+var SensorApplicationSites = []string{
+	SensorSiteLeftArm,
+	SensorSiteRightArm,
+	SensorSiteLeftAbdomen,
+	SensorSiteRightAbdomen,
+	SensorSiteLeftThigh,
+	SensorSiteRightThigh,
+}
+
+// Sensor feedback limits (free-text note and 1-5 rating recorded by the user)
+const (
+	MaxSensorNoteLength = 1000
+	MinSensorRating     = 1
+	MaxSensorRating     = 5
+)
+
 // SensorConfig represents glucose sensor information from the LibreView API.
 // Source: /llu/connections → data[0].sensor
 type SensorConfig struct {
@@ -26,14 +59,18 @@ type SensorConfig struct {
 	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
 	UpdatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
 
-	SerialNumber      string     `gorm:"type:varchar(50);uniqueIndex:idx_serial;not null" json:"serialNumber"` // sn: Serial number of the sensor
-	Activation        time.Time  `gorm:"type:datetime;not null;index:idx_activation" json:"activation"`        // a: Activation timestamp
-	ExpiresAt         time.Time  `gorm:"type:datetime;not null" json:"expiresAt"`                              // Calculated: Activation + DurationDays
-	EndedAt           *time.Time `gorm:"type:datetime" json:"endedAt"`                                         // When sensor was replaced (nil = current sensor)
-	LastMeasurementAt *time.Time `gorm:"type:datetime" json:"lastMeasurementAt"`                               // Timestamp of the last received measurement
-	SensorType        int        `gorm:"type:integer;not null" json:"sensorType"`                              // pt: Sensor type (4 = Libre 3 Plus)
-	DurationDays      int        `gorm:"type:integer;not null" json:"durationDays"`                            // Expected duration in days (15 for Libre 3 Plus)
-	DetectedAt        time.Time  `gorm:"type:datetime;not null" json:"detectedAt"`                             // When this sensor was first detected by the daemon
+	PatientID         string     `gorm:"type:varchar(64);not null;default:'';index:idx_sensor_patient" json:"patientId,omitempty"` // LibreLinkUp patient wearing the sensor (empty for rows stored before multi-patient support)
+	SerialNumber      string     `gorm:"type:varchar(50);uniqueIndex:idx_serial;not null" json:"serialNumber"`                     // sn: Serial number of the sensor
+	Activation        time.Time  `gorm:"type:datetime;not null;index:idx_activation" json:"activation"`                            // a: Activation timestamp
+	ExpiresAt         time.Time  `gorm:"type:datetime;not null" json:"expiresAt"`                                                  // Calculated: Activation + DurationDays
+	EndedAt           *time.Time `gorm:"type:datetime" json:"endedAt"`                                                             // When sensor was replaced (nil = current sensor)
+	LastMeasurementAt *time.Time `gorm:"type:datetime" json:"lastMeasurementAt"`                                                   // Timestamp of the last received measurement
+	SensorType        int        `gorm:"type:integer;not null" json:"sensorType"`                                                  // pt: Sensor type (4 = Libre 3 Plus)
+	DurationDays      int        `gorm:"type:integer;not null" json:"durationDays"`                                                // Expected duration in days (15 for Libre 3 Plus)
+	DetectedAt        time.Time  `gorm:"type:datetime;not null" json:"detectedAt"`                                                 // When this sensor was first detected by the daemon
+	ApplicationSite   string     `gorm:"type:varchar(20)" json:"applicationSite,omitempty"`                                        // Body site the sensor is applied to (one of SensorApplicationSites, empty = not recorded)
+	Note              string     `gorm:"type:text" json:"note,omitempty"`                                                          // Free-text note (adhesion issues, accuracy impressions)
+	Rating            *int       `gorm:"type:integer" json:"rating,omitempty"`                                                     // User rating from 1 to 5 (nil = not rated)
 }
 
 // TableName specifies the table name for GORM.
@@ -55,6 +92,20 @@ func SensorDurationDays(sensorType int) int {
 	}
 }
 
+// SensorBrand returns the product name of a given sensor type.
+func SensorBrand(sensorType int) string {
+	switch sensorType {
+	case 0:
+		return "FreeStyle Libre"
+	case 3:
+		return "FreeStyle Libre 2"
+	case 4:
+		return "FreeStyle Libre 3 Plus"
+	default:
+		return "Unknown"
+	}
+}
+
 // IsActive returns true if the sensor is currently active (not ended).
 func (s *SensorConfig) IsActive() bool {
 	return s.EndedAt == nil
@@ -95,19 +146,47 @@ func (s *SensorConfig) ActualDays() *float64 {
 	return &days
 }
 
-// Status returns the current operational status of the sensor.
-//   - "stopped": Sensor has been replaced (EndedAt set) or expired (now > ExpiresAt)
+// Status returns the current operational status of the sensor,
+// using the default grace period.
+func (s *SensorConfig) Status() SensorStatus {
+	return s.StatusAt(time.Now(), DefaultSensorGracePeriod)
+}
+
+// StatusAt returns the operational status of the sensor at now.
+//   - "stopped": Sensor has been replaced (EndedAt set), or expired and no longer
+//     reporting (grace period lapsed or no measurement for > 20 min)
+//   - "grace": Sensor has expired but is still reporting within the grace period
 //   - "unresponsive": Sensor is active but not sending data (no measurement for > 20 min)
 //   - "running": Sensor is active and within its lifetime
-func (s *SensorConfig) Status() SensorStatus {
+func (s *SensorConfig) StatusAt(now time.Time, grace time.Duration) SensorStatus {
 	if s.EndedAt != nil {
 		return SensorStatusStopped
 	}
-	if time.Now().After(s.ExpiresAt) {
+	if now.After(s.ExpiresAt) {
+		if s.isReportingInGrace(now, grace) {
+			return SensorStatusGrace
+		}
 		return SensorStatusStopped
 	}
-	if s.LastMeasurementAt != nil && time.Since(*s.LastMeasurementAt) > UnresponsiveThreshold {
+	if s.LastMeasurementAt != nil && now.Sub(*s.LastMeasurementAt) > UnresponsiveThreshold {
 		return SensorStatusUnresponsive
 	}
 	return SensorStatusRunning
 }
+
+// GraceEndsAt returns the time at which the grace period of the sensor lapses.
+func (s *SensorConfig) GraceEndsAt(grace time.Duration) time.Time {
+	return s.ExpiresAt.Add(grace)
+}
+
+// isReportingInGrace returns true if the expired sensor is within its grace period
+// and has sent a measurement since it expired, no more than 20 min ago.
+func (s *SensorConfig) isReportingInGrace(now time.Time, grace time.Duration) bool {
+	if grace <= 0 || now.After(s.GraceEndsAt(grace)) {
+		return false
+	}
+	if s.LastMeasurementAt == nil || !s.LastMeasurementAt.After(s.ExpiresAt) {
+		return false
+	}
+	return now.Sub(*s.LastMeasurementAt) <= UnresponsiveThreshold
+}
@@ -71,6 +71,57 @@ func TestStatus_Running_NoLastMeasurement(t *testing.T) {
 	}
 }
 
+func TestStatusAt_Grace(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(-2 * time.Hour)
+
+	tests := []struct {
+		name            string
+		lastMeasurement *time.Time
+		grace           time.Duration
+		want            SensorStatus
+	}{
+		{"reporting within grace", ptrTime(now.Add(-5 * time.Minute)), 12 * time.Hour, SensorStatusGrace},
+		{"readings stopped", ptrTime(now.Add(-30 * time.Minute)), 12 * time.Hour, SensorStatusStopped},
+		{"last reading before expiry", ptrTime(expiresAt.Add(-time.Minute)), 12 * time.Hour, SensorStatusStopped},
+		{"no reading", nil, 12 * time.Hour, SensorStatusStopped},
+		{"grace lapsed", ptrTime(now.Add(-5 * time.Minute)), time.Hour, SensorStatusStopped},
+		{"grace disabled", ptrTime(now.Add(-5 * time.Minute)), 0, SensorStatusStopped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SensorConfig{
+				Activation:        expiresAt.Add(-15 * 24 * time.Hour),
+				ExpiresAt:         expiresAt,
+				LastMeasurementAt: tt.lastMeasurement,
+			}
+			if got := s.StatusAt(now, tt.grace); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStatusAt_EndedDuringGrace_Stopped(t *testing.T) {
+	now := time.Now()
+	lastMeasurement := now.Add(-time.Minute)
+	endedAt := now.Add(-time.Minute)
+	s := &SensorConfig{
+		ExpiresAt:         now.Add(-time.Hour),
+		EndedAt:           &endedAt,
+		LastMeasurementAt: &lastMeasurement,
+	}
+
+	if got := s.StatusAt(now, 12*time.Hour); got != SensorStatusStopped {
+		t.Errorf("expected stopped, got %s", got)
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
 func TestElapsedDays_Expired_BoundedToExpiresAt(t *testing.T) {
 	activation := time.Now().Add(-20 * 24 * time.Hour)
 	expiresAt := activation.Add(15 * 24 * time.Hour)
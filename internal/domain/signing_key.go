@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// SigningKey represents an HMAC key used to sign pushed payloads (SSE events).
+// The newest non-retired key signs; consumers keep older keys to verify
+// payloads signed before a rotation.
+type SigningKey struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	KeyID     string     `gorm:"type:text;not null;uniqueIndex:idx_signing_key_id" json:"keyId"` // Public identifier sent with each signature
+	Secret    string     `gorm:"type:text;not null" json:"-"`                                    // HMAC secret, returned once at creation
+	RetiredAt *time.Time `gorm:"type:datetime" json:"retiredAt,omitempty"`                       // nil = usable for signing
+}
+
+// TableName specifies the table name for GORM.
+func (SigningKey) TableName() string {
+	return "signing_keys"
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// MorningSummary summarizes overnight glucose, published at wake time.
+type MorningSummary struct {
+	Start            time.Time           `json:"start"` // Beginning of the night
+	End              time.Time           `json:"end"`   // Summary time
+	Count            int                 `json:"count"` // Measurements during the night
+	Min              float64             `json:"min"`   // mmol/L
+	MinMgDl          int                 `json:"minMgDl"`
+	Max              float64             `json:"max"` // mmol/L
+	MaxMgDl          int                 `json:"maxMgDl"`
+	TimeLowMinutes   int                 `json:"timeLowMinutes"` // Time spent below LowThresholdMgDl
+	LowThresholdMgDl int                 `json:"lowThresholdMgDl"`
+	Current          *GlucoseMeasurement `json:"current,omitempty"` // Latest measurement (nil if none)
+}
@@ -20,3 +20,21 @@ type GlucoseTargets struct {
 func (GlucoseTargets) TableName() string {
 	return "glucose_targets"
 }
+
+// TargetBandTight is the name of the tight range band, reported as
+// Time in Tight Range (TITR).
+const TargetBandTight = "tight"
+
+// TargetBand is a secondary glucose range whose time in range is reported
+// alongside the standard Time in Range (e.g. tight range 70-140 mg/dL, or
+// pregnancy targets 63-140 mg/dL). Bounds are inclusive.
+type TargetBand struct {
+	Name     string `json:"name"`
+	LowMgDl  int    `json:"lowMgDl"`
+	HighMgDl int    `json:"highMgDl"`
+}
+
+// DefaultTargetBands returns the bands reported when none are configured.
+func DefaultTargetBands() []TargetBand {
+	return []TargetBand{{Name: TargetBandTight, LowMgDl: 70, HighMgDl: 140}}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// API token scopes, from least to most privileged.
+// A scope grants access to its own endpoints and to those of lower scopes.
+const (
+	TokenScopeRead  = "read"  // Read-only data endpoints
+	TokenScopeSync  = "sync"  // Sync export (replication)
+	TokenScopeAdmin = "admin" // Token management and other admin endpoints
+)
+
+// TokenScopes lists the valid scopes, from least to most privileged.
+var TokenScopes = []string{TokenScopeRead, TokenScopeSync, TokenScopeAdmin}
+
+// Scopes of the static API tokens configured with GLCMD_API_TOKENS.
+// The write scope includes read access.
+const (
+	APIScopeRead  = "read"  // GET requests on the data endpoints
+	APIScopeWrite = "write" // Every request on the data endpoints
+)
+
+// APIToken represents an API token issued through the admin API.
+// Only a SHA-256 hash of the token is stored; the plaintext value is shown
+// once at creation time.
+type APIToken struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	Name       string     `gorm:"type:text;not null" json:"name"`                         // Human-readable label (e.g. "kitchen display")
+	Prefix     string     `gorm:"type:text;not null" json:"prefix"`                       // First characters of the token, to identify it in lists
+	TokenHash  string     `gorm:"type:text;not null;uniqueIndex:idx_token_hash" json:"-"` // Hex-encoded SHA-256 of the token
+	Scope      string     `gorm:"type:text;not null" json:"scope"`                        // One of TokenScopes
+	ExpiresAt  *time.Time `gorm:"type:datetime" json:"expiresAt,omitempty"`               // nil = never expires
+	LastUsedAt *time.Time `gorm:"type:datetime" json:"lastUsedAt,omitempty"`              // Last successful authentication
+	RevokedAt  *time.Time `gorm:"type:datetime" json:"revokedAt,omitempty"`               // nil = not revoked
+}
+
+// TableName specifies the table name for GORM.
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
+
+// IsActive returns true if the token is neither revoked nor expired at now.
+func (t *APIToken) IsActive(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
+
+// Allows returns true if the token's scope grants access to the required scope.
+func (t *APIToken) Allows(required string) bool {
+	have := slices.Index(TokenScopes, t.Scope)
+	need := slices.Index(TokenScopes, required)
+	return have >= 0 && need >= 0 && have >= need
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAPIToken_IsActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		token APIToken
+		want  bool
+	}{
+		{"no expiry", APIToken{}, true},
+		{"not yet expired", APIToken{ExpiresAt: &future}, true},
+		{"expired", APIToken{ExpiresAt: &past}, false},
+		{"revoked", APIToken{RevokedAt: &past}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.token.IsActive(now); got != tt.want {
+				t.Errorf("IsActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIToken_Allows(t *testing.T) {
+	tests := []struct {
+		scope    string
+		required string
+		want     bool
+	}{
+		{TokenScopeRead, TokenScopeRead, true},
+		{TokenScopeRead, TokenScopeSync, false},
+		{TokenScopeSync, TokenScopeRead, true},
+		{TokenScopeSync, TokenScopeAdmin, false},
+		{TokenScopeAdmin, TokenScopeSync, true},
+		{"unknown", TokenScopeRead, false},
+	}
+
+	for _, tt := range tests {
+		token := &APIToken{Scope: tt.scope}
+		if got := token.Allows(tt.required); got != tt.want {
+			t.Errorf("%s.Allows(%s) = %v, want %v", tt.scope, tt.required, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// Treatment types
+const (
+	TreatmentTypeBolus = "bolus" // Insulin bolus, optionally with the carbs it covers
+	TreatmentTypeBasal = "basal" // Basal delivery segment at a constant rate
+)
+
+// Treatment sources: the pump export a treatment was imported from
+const (
+	TreatmentSourceOmnipod = "omnipod" // Omnipod export (Glooko CSV)
+	TreatmentSourceTandem  = "tandem"  // Tandem t:connect CSV export
+)
+
+// TreatmentSources lists the supported import sources.
+var TreatmentSources = []string{TreatmentSourceOmnipod, TreatmentSourceTandem}
+
+// TreatmentEntry represents an insulin delivery imported from a pump export.
+// Entries are unique per source, type and timestamp, so the same export can
+// be imported again without creating duplicates.
+type TreatmentEntry struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+
+	Timestamp       time.Time `gorm:"type:datetime;not null;uniqueIndex:idx_unique_treatment;index:idx_treatment_timestamp" json:"timestamp"` // Start of the delivery, stored in UTC
+	Type            string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_unique_treatment" json:"type"`                                 // One of TreatmentTypeBolus, TreatmentTypeBasal
+	Source          string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_unique_treatment" json:"source"`                               // One of TreatmentSources
+	Insulin         float64   `gorm:"type:decimal(10,3);not null" json:"insulin"`                                                             // Units delivered
+	BasalRate       *float64  `gorm:"type:decimal(10,3)" json:"basalRate,omitempty"`                                                          // U/h (basal only)
+	DurationMinutes int       `gorm:"type:integer;not null;default:0" json:"durationMinutes,omitempty"`                                       // Basal segment length (0 if unknown or bolus)
+	Carbs           *float64  `gorm:"type:decimal(10,1)" json:"carbs,omitempty"`                                                              // Grams entered with a bolus
+}
+
+// TableName specifies the table name for GORM.
+func (TreatmentEntry) TableName() string {
+	return "treatments"
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// UpstreamSession is the LibreView session of an account, stored so a
+// restarted daemon reuses it instead of logging in again. The token, account
+// and patient IDs are sealed with a key derived from the account password:
+// the stored session is unreadable without the credentials, and unusable once
+// they change.
+type UpstreamSession struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	UpdatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"-"`
+
+	Account   string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_upstream_session_account" json:"-"` // primary or secondary
+	ExpiresAt *time.Time `gorm:"type:datetime" json:"-"`                                                      // Token expiry, nil if unknown
+	Salt      string     `gorm:"type:text;not null" json:"-"`                                                 // Base64 key derivation salt
+	Sealed    string     `gorm:"type:text;not null" json:"-"`                                                 // Base64 AES-GCM nonce and ciphertext
+}
+
+// TableName specifies the table name for GORM.
+func (UpstreamSession) TableName() string {
+	return "upstream_sessions"
+}
+
+// UpstreamCredentials are the sealed fields of an UpstreamSession.
+type UpstreamCredentials struct {
+	Token     string    `json:"token"`
+	AccountID string    `json:"accountId"`
+	PatientID string    `json:"patientId,omitempty"` // Followed patient, empty before the first fetch
+	Region    string    `json:"region,omitempty"`    // LibreView region of the account, empty for the global endpoint
+	ExpiresAt time.Time `json:"-"`                   // Zero if unknown
+}
@@ -50,12 +50,15 @@ func (a *IntArray) Scan(value interface{}) error {
 		return nil
 	}
 
-	bytes, ok := value.([]byte)
-	if !ok {
+	// Empty arrays are stored as text, others as JSON bytes
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
 		return errors.New("failed to unmarshal IntArray value")
 	}
-
-	return json.Unmarshal(bytes, a)
 }
 
 // Value implements the driver.Valuer interface for writing to the database.
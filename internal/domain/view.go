@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Saved view aggregations: what running a view returns
+const (
+	ViewAggregationList  = "list"  // Matching measurements, newest first
+	ViewAggregationStats = "stats" // Statistics of the matching measurements
+)
+
+// ViewAggregations lists the valid saved view aggregations.
+var ViewAggregations = []string{ViewAggregationList, ViewAggregationStats}
+
+// SavedView is a named glucose analysis: a filter expression, a period
+// relative to the time it is run and an aggregation.
+type SavedView struct {
+	// Database fields
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP" json:"updatedAt"`
+
+	Name        string `gorm:"type:varchar(64);not null;uniqueIndex" json:"name"` // Lowercase slug (e.g. "nights-last-month")
+	Description string `gorm:"type:varchar(255)" json:"description,omitempty"`    // Free text shown in listings
+	Query       string `gorm:"type:varchar(500)" json:"query,omitempty"`          // Filter expression (empty = all measurements)
+	Period      string `gorm:"type:varchar(20)" json:"period,omitempty"`          // Period such as "30d" (empty = all time)
+	Aggregation string `gorm:"type:varchar(10);not null" json:"aggregation"`      // One of ViewAggregations
+}
+
+// TableName specifies the table name for GORM.
+func (SavedView) TableName() string {
+	return "saved_views"
+}
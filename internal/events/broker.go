@@ -3,7 +3,9 @@ package events
 import (
 	"context"
 	"log/slog"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -13,20 +15,38 @@ type EventType string
 const (
 	EventTypeGlucose   EventType = "glucose"
 	EventTypeSensor    EventType = "sensor"
+	EventTypeSummary   EventType = "summary"
+	EventTypeConfig    EventType = "config"
+	EventTypeAlert     EventType = "alert"
 	EventTypeKeepalive EventType = "keepalive"
 )
 
 // Event represents a generic event
 type Event struct {
-	Type EventType
-	Data interface{} // *domain.GlucoseMeasurement or *domain.SensorConfig
+	Type      EventType
+	Data      interface{} // *domain.GlucoseMeasurement, *domain.SensorConfig, *domain.MorningSummary, *domain.ConfigChange or alerts.Alert
+	PatientID string      // LibreLinkUp patient the data belongs to ("" = not patient data, e.g. config changes)
 }
 
-// Subscriber represents a subscriber with optional type filtering
+// Subscriber represents a subscriber with optional type and patient filtering
 type Subscriber struct {
-	ID      string
-	Channel chan Event
-	Types   []EventType // Types to receive (empty = all)
+	ID          string
+	Channel     chan Event
+	Types       []EventType // Types to receive (empty = all)
+	PatientID   string      // Patient whose events to receive ("" = all patients)
+	ConnectedAt time.Time
+
+	delivered atomic.Uint64 // Events queued on Channel
+	dropped   atomic.Uint64 // Events dropped because Channel was full
+}
+
+// SubscriberStats is a snapshot of a subscriber's delivery counters
+type SubscriberStats struct {
+	ID          string      `json:"id"`
+	Types       []EventType `json:"types"` // Empty = all types
+	ConnectedAt time.Time   `json:"connectedAt"`
+	Delivered   uint64      `json:"delivered"`
+	Dropped     uint64      `json:"dropped"`
 }
 
 // wantsEvent returns true if the subscriber wants events of the given type
@@ -42,6 +62,12 @@ func (s *Subscriber) wantsEvent(eventType EventType) bool {
 	return false
 }
 
+// wantsPatient returns true if the subscriber wants events of the given
+// patient. Events without a patient are sent to every subscriber.
+func (s *Subscriber) wantsPatient(patientID string) bool {
+	return s.PatientID == "" || patientID == "" || s.PatientID == patientID
+}
+
 // Broker manages subscriptions and event distribution
 type Broker struct {
 	subscribers map[string]*Subscriber
@@ -68,19 +94,29 @@ func NewBroker(bufferSize int, logger *slog.Logger) *Broker {
 // Subscribe registers a new subscriber and returns the event channel.
 // types specifies which event types to receive (empty = all types).
 func (b *Broker) Subscribe(id string, types []EventType) <-chan Event {
+	return b.SubscribePatient(id, "", types)
+}
+
+// SubscribePatient registers a new subscriber receiving only the events of
+// one patient (LibreLinkUp patient ID, "" = all patients) and those without
+// a patient, and returns the event channel.
+func (b *Broker) SubscribePatient(id string, patientID string, types []EventType) <-chan Event {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
 	ch := make(chan Event, b.bufferSize)
 	b.subscribers[id] = &Subscriber{
-		ID:      id,
-		Channel: ch,
-		Types:   types,
+		ID:          id,
+		Channel:     ch,
+		Types:       types,
+		PatientID:   patientID,
+		ConnectedAt: time.Now(),
 	}
 
 	b.logger.Debug("subscriber added",
 		"clientID", id,
 		"types", types,
+		"patientId", patientID,
 		"subscribers", len(b.subscribers),
 	)
 
@@ -89,18 +125,28 @@ func (b *Broker) Subscribe(id string, types []EventType) <-chan Event {
 
 // Unsubscribe removes a subscriber and closes its channel
 func (b *Broker) Unsubscribe(id string) {
+	b.Disconnect(id)
+}
+
+// Disconnect removes a subscriber and closes its channel, which ends the
+// subscriber's stream. Returns false if no subscriber has this ID.
+func (b *Broker) Disconnect(id string) bool {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if sub, ok := b.subscribers[id]; ok {
-		close(sub.Channel)
-		delete(b.subscribers, id)
-
-		b.logger.Debug("subscriber removed",
-			"clientID", id,
-			"subscribers", len(b.subscribers),
-		)
+	sub, ok := b.subscribers[id]
+	if !ok {
+		return false
 	}
+
+	close(sub.Channel)
+	delete(b.subscribers, id)
+
+	b.logger.Debug("subscriber removed",
+		"clientID", id,
+		"subscribers", len(b.subscribers),
+	)
+	return true
 }
 
 // Publish sends an event to all matching subscribers.
@@ -110,15 +156,16 @@ func (b *Broker) Publish(event Event) {
 	defer b.mu.RUnlock()
 
 	for _, sub := range b.subscribers {
-		if !sub.wantsEvent(event.Type) {
+		if !sub.wantsEvent(event.Type) || !sub.wantsPatient(event.PatientID) {
 			continue
 		}
 
 		select {
 		case sub.Channel <- event:
-			// Event sent successfully
+			sub.delivered.Add(1)
 		default:
 			// Channel full, subscriber too slow
+			sub.dropped.Add(1)
 			b.logger.Warn("SSE subscriber slow, event dropped",
 				"clientID", sub.ID,
 				"eventType", event.Type,
@@ -161,6 +208,31 @@ func (b *Broker) SubscriberCount() int {
 	return len(b.subscribers)
 }
 
+// Subscribers returns the delivery counters of every subscriber, oldest
+// connection first
+func (b *Broker) Subscribers() []SubscriberStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	stats := make([]SubscriberStats, 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		stats = append(stats, SubscriberStats{
+			ID:          sub.ID,
+			Types:       sub.Types,
+			ConnectedAt: sub.ConnectedAt,
+			Delivered:   sub.delivered.Load(),
+			Dropped:     sub.dropped.Load(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if !stats[i].ConnectedAt.Equal(stats[j].ConnectedAt) {
+			return stats[i].ConnectedAt.Before(stats[j].ConnectedAt)
+		}
+		return stats[i].ID < stats[j].ID
+	})
+	return stats
+}
+
 // heartbeatLoop sends keepalive events every 30 seconds
 func (b *Broker) heartbeatLoop() {
 	defer b.wg.Done()
@@ -155,6 +155,44 @@ func TestBroker_PublishWithTypeFilter(t *testing.T) {
 	broker.Unsubscribe("client3")
 }
 
+func TestBroker_PublishWithPatientFilter(t *testing.T) {
+	broker := NewBroker(10, slog.Default())
+
+	// client1 wants patient a, client2 patient b, client3 all patients
+	ch1 := broker.SubscribePatient("client1", "a", nil)
+	ch2 := broker.SubscribePatient("client2", "b", nil)
+	ch3 := broker.Subscribe("client3", nil)
+	defer broker.Unsubscribe("client1")
+	defer broker.Unsubscribe("client2")
+	defer broker.Unsubscribe("client3")
+
+	broker.Publish(Event{Type: EventTypeGlucose, Data: "glucose", PatientID: "a"})
+	broker.Publish(Event{Type: EventTypeConfig, Data: "config"})
+
+	received := func(ch <-chan Event) []EventType {
+		var types []EventType
+		for {
+			select {
+			case e := <-ch:
+				types = append(types, e.Type)
+			case <-time.After(50 * time.Millisecond):
+				return types
+			}
+		}
+	}
+
+	// Patient events reach their patient, events without a patient everyone
+	if got := received(ch1); len(got) != 2 {
+		t.Errorf("client1: expected glucose and config, got %v", got)
+	}
+	if got := received(ch2); len(got) != 1 || got[0] != EventTypeConfig {
+		t.Errorf("client2: expected only config, got %v", got)
+	}
+	if got := received(ch3); len(got) != 2 {
+		t.Errorf("client3: expected glucose and config, got %v", got)
+	}
+}
+
 func TestBroker_NonBlockingPublish(t *testing.T) {
 	// Create broker with small buffer
 	broker := NewBroker(2, slog.Default())
@@ -186,6 +224,47 @@ func TestBroker_NonBlockingPublish(t *testing.T) {
 	broker.Unsubscribe("slow-client")
 }
 
+func TestBroker_SubscribersAndDisconnect(t *testing.T) {
+	broker := NewBroker(1, slog.Default())
+
+	ch := broker.Subscribe("dashboard", []EventType{EventTypeGlucose})
+	broker.Subscribe("other", nil)
+
+	// One event queued, one dropped on the full buffer, one filtered out
+	broker.Publish(Event{Type: EventTypeGlucose, Data: "1"})
+	broker.Publish(Event{Type: EventTypeGlucose, Data: "2"})
+	broker.Publish(Event{Type: EventTypeSensor, Data: "3"})
+
+	stats := broker.Subscribers()
+	if len(stats) != 2 || stats[0].ID != "dashboard" {
+		t.Fatalf("expected dashboard then other, got %+v", stats)
+	}
+	if stats[0].Delivered != 1 || stats[0].Dropped != 1 {
+		t.Errorf("expected 1 delivered and 1 dropped, got %+v", stats[0])
+	}
+	if len(stats[0].Types) != 1 || stats[0].ConnectedAt.IsZero() {
+		t.Errorf("unexpected subscriber details: %+v", stats[0])
+	}
+
+	if !broker.Disconnect("dashboard") {
+		t.Fatal("expected Disconnect to find the subscriber")
+	}
+	<-ch
+	if _, ok := <-ch; ok {
+		t.Error("expected channel to be closed after Disconnect")
+	}
+	if broker.Disconnect("dashboard") {
+		t.Error("expected second Disconnect to report a missing subscriber")
+	}
+
+	// Unsubscribing after a forced disconnect is a no-op
+	broker.Unsubscribe("dashboard")
+	if broker.SubscriberCount() != 1 {
+		t.Errorf("expected 1 subscriber, got %d", broker.SubscriberCount())
+	}
+	broker.Unsubscribe("other")
+}
+
 func TestBroker_ConcurrentAccess(t *testing.T) {
 	broker := NewBroker(100, slog.Default())
 
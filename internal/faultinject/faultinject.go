@@ -0,0 +1,193 @@
+// Package faultinject randomly injects failures for resilience testing.
+//
+// It is a developer mode enabled with GLCMD_FAULT_INJECT: LibreView requests
+// fail as network or server errors, database statements fail with a SQLite
+// lock error or are delayed, at configurable rates. This exercises the fetch
+// retries, the upstream outage tracking and the database retry logic without
+// waiting for real failures. Never enable it in production.
+package faultinject
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultSlowQueryDelay is the delay of slow queries when none is given.
+const DefaultSlowQueryDelay = 2 * time.Second
+
+// ErrInjectedLock mimics SQLite's busy error, so it is retried like a real one.
+var ErrInjectedLock = errors.New("database is locked (injected fault)")
+
+// Config holds the fault rates, each between 0 (never) and 1 (always).
+type Config struct {
+	UpstreamRate   float64       // LibreView requests failing
+	DBLockRate     float64       // Database statements failing with a lock error
+	SlowQueryRate  float64       // Database statements delayed by SlowQueryDelay
+	SlowQueryDelay time.Duration // Delay of slow statements
+}
+
+// Enabled returns true if any fault is injected.
+func (c Config) Enabled() bool {
+	return c.UpstreamRate > 0 || c.DBLockRate > 0 || c.SlowQueryRate > 0
+}
+
+// Parse parses a comma-separated list of fault=rate pairs, e.g.
+// "upstream=0.2,dblock=0.05,slowquery=0.1,slowdelay=3s".
+// An empty spec disables fault injection.
+func Parse(spec string) (Config, error) {
+	cfg := Config{SlowQueryDelay: DefaultSlowQueryDelay}
+	if strings.TrimSpace(spec) == "" {
+		return cfg, nil
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Config{}, fmt.Errorf("invalid fault %q: expected name=value", part)
+		}
+
+		if name == "slowdelay" {
+			delay, err := time.ParseDuration(value)
+			if err != nil || delay <= 0 {
+				return Config{}, fmt.Errorf("invalid slowdelay %q: must be a positive duration", value)
+			}
+			cfg.SlowQueryDelay = delay
+			continue
+		}
+
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return Config{}, fmt.Errorf("invalid %s rate %q: must be between 0 and 1", name, value)
+		}
+		switch name {
+		case "upstream":
+			cfg.UpstreamRate = rate
+		case "dblock":
+			cfg.DBLockRate = rate
+		case "slowquery":
+			cfg.SlowQueryRate = rate
+		default:
+			return Config{}, fmt.Errorf("unknown fault %q (must be upstream, dblock, slowquery or slowdelay)", name)
+		}
+	}
+
+	return cfg, nil
+}
+
+// Injector decides which operations fail.
+type Injector struct {
+	cfg    Config
+	logger *slog.Logger
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewInjector creates a new Injector for cfg.
+func NewInjector(cfg Config, logger *slog.Logger) *Injector {
+	return &Injector{
+		cfg:    cfg,
+		logger: logger,
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// roll returns true with probability rate.
+func (i *Injector) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rng.Float64() < rate
+}
+
+// Transport wraps base so that requests fail at the upstream rate, half as
+// network errors and half as 503 responses. A nil base uses http.DefaultTransport.
+func (i *Injector) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{injector: i, base: base}
+}
+
+type transport struct {
+	injector *Injector
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !t.injector.roll(t.injector.cfg.UpstreamRate) {
+		return t.base.RoundTrip(r)
+	}
+
+	if t.injector.roll(0.5) {
+		t.injector.logger.Debug("injected upstream network error", "path", r.URL.Path)
+		return nil, errors.New("connection reset by peer (injected fault)")
+	}
+
+	t.injector.logger.Debug("injected upstream server error", "path", r.URL.Path)
+	return &http.Response{
+		Status:     "503 Service Unavailable",
+		StatusCode: http.StatusServiceUnavailable,
+		Proto:      r.Proto,
+		ProtoMajor: r.ProtoMajor,
+		ProtoMinor: r.ProtoMinor,
+		Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		Body:       io.NopCloser(strings.NewReader("injected fault")),
+		Request:    r,
+	}, nil
+}
+
+// RegisterGORM adds callbacks to db that fail or delay statements at the
+// database rates. Statements failed this way are not executed.
+func (i *Injector) RegisterGORM(db *gorm.DB) error {
+	if i.cfg.DBLockRate <= 0 && i.cfg.SlowQueryRate <= 0 {
+		return nil
+	}
+
+	cb := db.Callback()
+	registrations := []struct {
+		name     string
+		register func(string, func(*gorm.DB)) error
+	}{
+		{"faultinject:create", cb.Create().Before("gorm:create").Register},
+		{"faultinject:query", cb.Query().Before("gorm:query").Register},
+		{"faultinject:update", cb.Update().Before("gorm:update").Register},
+		{"faultinject:delete", cb.Delete().Before("gorm:delete").Register},
+		{"faultinject:row", cb.Row().Before("gorm:row").Register},
+		{"faultinject:raw", cb.Raw().Before("gorm:raw").Register},
+	}
+	for _, r := range registrations {
+		if err := r.register(r.name, i.beforeStatement); err != nil {
+			return fmt.Errorf("failed to register %s callback: %w", r.name, err)
+		}
+	}
+	return nil
+}
+
+// beforeStatement injects database faults before a statement runs.
+func (i *Injector) beforeStatement(db *gorm.DB) {
+	if i.roll(i.cfg.SlowQueryRate) {
+		i.logger.Debug("injected slow query", "table", db.Statement.Table, "delay", i.cfg.SlowQueryDelay)
+		select {
+		case <-time.After(i.cfg.SlowQueryDelay):
+		case <-db.Statement.Context.Done():
+		}
+	}
+
+	if i.roll(i.cfg.DBLockRate) {
+		i.logger.Debug("injected database lock", "table", db.Statement.Table)
+		db.AddError(ErrInjectedLock)
+	}
+}
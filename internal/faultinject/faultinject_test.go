@@ -0,0 +1,124 @@
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse("upstream=0.2, dblock=0.05,slowquery=1,slowdelay=500ms")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := Config{UpstreamRate: 0.2, DBLockRate: 0.05, SlowQueryRate: 1, SlowQueryDelay: 500 * time.Millisecond}
+	if cfg != want {
+		t.Errorf("Parse() = %+v, want %+v", cfg, want)
+	}
+
+	cfg, err = Parse("")
+	if err != nil || cfg.Enabled() || cfg.SlowQueryDelay != DefaultSlowQueryDelay {
+		t.Errorf("expected disabled defaults, got %+v, %v", cfg, err)
+	}
+
+	for _, spec := range []string{"upstream", "upstream=-0.1", "upstream=1.5", "disk=0.1", "slowdelay=0s", "dblock=abc"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("expected error for %q, got nil", spec)
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	// Never fails
+	client := &http.Client{Transport: NewInjector(Config{}, slog.Default()).Transport(nil)}
+	resp, err := client.Get(upstream.URL)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected a passthrough request, got %v, %v", resp, err)
+	}
+	resp.Body.Close()
+
+	// Always fails, as a network error or a server error
+	client = &http.Client{Transport: NewInjector(Config{UpstreamRate: 1}, slog.Default()).Transport(nil)}
+	for range 20 {
+		resp, err := client.Get(upstream.URL)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusServiceUnavailable {
+				t.Fatalf("expected an injected failure, got status %d", resp.StatusCode)
+			}
+		}
+	}
+}
+
+type record struct {
+	ID   uint
+	Name string
+}
+
+func TestRegisterGORM(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create in-memory database: %v", err)
+	}
+	if err := db.AutoMigrate(&record{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	injector := NewInjector(Config{DBLockRate: 1}, slog.Default())
+	if err := injector.RegisterGORM(db); err != nil {
+		t.Fatalf("RegisterGORM failed: %v", err)
+	}
+
+	err = db.Create(&record{Name: "a"}).Error
+	if !errors.Is(err, ErrInjectedLock) {
+		t.Fatalf("expected an injected lock error, got %v", err)
+	}
+	if !persistence.IsRetryable(err) {
+		t.Error("expected the injected lock error to be retryable")
+	}
+
+	var count int64
+	injector.cfg.DBLockRate = 0
+	if err := db.Model(&record{}).Count(&count).Error; err != nil || count != 0 {
+		t.Errorf("expected the failed insert not to be executed, got %d rows, %v", count, err)
+	}
+}
+
+func TestRegisterGORM_SlowQuery(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create in-memory database: %v", err)
+	}
+	if err := db.AutoMigrate(&record{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	injector := NewInjector(Config{SlowQueryRate: 1, SlowQueryDelay: time.Hour}, slog.Default())
+	if err := injector.RegisterGORM(db); err != nil {
+		t.Fatalf("RegisterGORM failed: %v", err)
+	}
+
+	// The delay gives up with the statement's context
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	var records []record
+	db.WithContext(ctx).Find(&records)
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > 10*time.Second {
+		t.Errorf("expected the query to be delayed until the context expired, took %v", elapsed)
+	}
+}
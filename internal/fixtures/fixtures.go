@@ -0,0 +1,186 @@
+// Package fixtures turns live data into test fixtures.
+//
+// glcore gen-fixtures captures the LibreView payloads of the account and a
+// snapshot of the recent measurements and sensors of the database, so parsing
+// edge cases met in production can be replayed in regression tests. The
+// Anonymizer replaces the identifying fields (patient and user IDs, names,
+// e-mails, serial and device numbers) with stable pseudonyms, consistent
+// between the payloads and the snapshot. Timestamps and glucose values are
+// kept: they are what the edge cases are usually about.
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// Snapshot is the database part of the fixtures.
+type Snapshot struct {
+	GeneratedAt  time.Time                    `json:"generatedAt"`
+	Days         int                          `json:"days"`
+	Anonymized   bool                         `json:"anonymized"`
+	Measurements []*domain.GlucoseMeasurement `json:"measurements"`
+	Sensors      []*domain.SensorConfig       `json:"sensors"`
+}
+
+// LoadSnapshot reads a snapshot written by glcore gen-fixtures.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+// pseudonym kinds: values of the same kind share one numbering
+const (
+	kindID     = "id"
+	kindName   = "name"
+	kindEmail  = "email"
+	kindSerial = "serial"
+	kindDevice = "device"
+)
+
+// payloadFields are the LibreView payload keys replaced by the Anonymizer, by kind.
+// Secrets (tokens, tickets) are replaced with a fixed value.
+var payloadFields = map[string]string{
+	"id":        kindID,
+	"patientId": kindID,
+	"userId":    kindID,
+	"accountId": kindID,
+	"firstName": kindName,
+	"lastName":  kindName,
+	"email":     kindEmail,
+	"sn":        kindSerial,
+	"deviceId":  kindDevice,
+	"did":       kindDevice,
+	"token":     "",
+}
+
+// redacted replaces the secrets of the payloads.
+const redacted = "redacted"
+
+// Anonymizer replaces identifying values with pseudonyms. The same value
+// always gets the same pseudonym, so the patient of a payload is still the
+// patient of the snapshot.
+type Anonymizer struct {
+	pseudonyms map[string]string // kind + value -> pseudonym
+	counts     map[string]int    // pseudonyms given per kind
+}
+
+// NewAnonymizer creates an Anonymizer.
+func NewAnonymizer() *Anonymizer {
+	return &Anonymizer{
+		pseudonyms: make(map[string]string),
+		counts:     make(map[string]int),
+	}
+}
+
+// pseudonym returns the pseudonym of value. It keeps the shape of the value
+// where parsing may depend on it: UUIDs stay UUIDs and serial numbers keep
+// their length.
+func (a *Anonymizer) pseudonym(kind, value string) string {
+	if value == "" {
+		return ""
+	}
+	key := kind + "\x00" + value
+	if p, ok := a.pseudonyms[key]; ok {
+		return p
+	}
+
+	a.counts[kind]++
+	n := a.counts[kind]
+	var p string
+	switch kind {
+	case kindID, kindDevice:
+		if _, err := uuid.Parse(value); err == nil {
+			p = fmt.Sprintf("00000000-0000-4000-8000-%012d", n)
+		} else {
+			p = fmt.Sprintf("%s-%d", kind, n)
+		}
+	case kindName:
+		p = fmt.Sprintf("Name%d", n)
+	case kindEmail:
+		p = fmt.Sprintf("patient%d@example.com", n)
+	case kindSerial:
+		p = fmt.Sprintf("SN%0*d", max(len(value)-2, 1), n)
+	}
+	a.pseudonyms[key] = p
+	return p
+}
+
+// Payload returns a LibreView JSON payload with its identifying fields replaced.
+// Numbers are kept as written.
+func (a *Anonymizer) Payload(raw []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	return json.Marshal(a.value("", v))
+}
+
+// value anonymizes v, found under key.
+func (a *Anonymizer) value(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		// Sorted, so pseudonyms are numbered the same on every run
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			v[k] = a.value(k, v[k])
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = a.value(key, child)
+		}
+		return v
+	case string:
+		kind, ok := payloadFields[key]
+		if !ok {
+			return v
+		}
+		if kind == "" {
+			return redacted
+		}
+		return a.pseudonym(kind, v)
+	case json.Number:
+		// Numeric IDs are replaced by their pseudonym number
+		if kind, ok := payloadFields[key]; ok && kind == kindID {
+			return json.Number(strings.TrimPrefix(a.pseudonym(kind, v.String()), kind+"-"))
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// Measurements anonymizes measurements in place.
+func (a *Anonymizer) Measurements(measurements []*domain.GlucoseMeasurement) {
+	for _, m := range measurements {
+		m.PatientID = a.pseudonym(kindID, m.PatientID)
+	}
+}
+
+// Sensors anonymizes sensors in place. Notes are free text and are dropped.
+func (a *Anonymizer) Sensors(sensors []*domain.SensorConfig) {
+	for _, s := range sensors {
+		s.PatientID = a.pseudonym(kindID, s.PatientID)
+		s.SerialNumber = a.pseudonym(kindSerial, s.SerialNumber)
+		s.Note = ""
+	}
+}
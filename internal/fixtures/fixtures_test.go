@@ -0,0 +1,160 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+const livePayload = `{
+  "data": [
+    {
+      "id": "6f1b7e5a-2c1d-4e0b-9d3f-2b8a4c7e9f10",
+      "patientId": "9a2c4e6f-1b3d-4f5a-8c7e-0d2f4a6b8c9e",
+      "firstName": "Jane",
+      "lastName": "Doe",
+      "sensor": {"sn": "0K1234ABCD", "a": 1767430800, "pt": 4},
+      "patientDevice": {"did": "device-serial-42", "ll": 70},
+      "glucoseMeasurement": {"Value": 7.0, "ValueInMgPerDl": 126, "Timestamp": "1/4/2026 12:59:30 PM"}
+    }
+  ],
+  "user": {"id": 1234, "email": "jane.doe@example.org"},
+  "ticket": {"token": "eyJhbGciOi.secret", "expires": 1783000000}
+}`
+
+func TestAnonymizer_Payload(t *testing.T) {
+	a := NewAnonymizer()
+
+	out, err := a.Payload([]byte(livePayload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := string(out)
+
+	for _, secret := range []string{"6f1b7e5a", "9a2c4e6f", "Jane", "Doe", "0K1234ABCD", "device-serial-42", "jane.doe", "eyJhbGciOi", "1234"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("expected %q to be anonymized, got %s", secret, got)
+		}
+	}
+
+	var payload struct {
+		Data []struct {
+			ID        string `json:"id"`
+			PatientID string `json:"patientId"`
+			FirstName string `json:"firstName"`
+			Sensor    struct {
+				SN string `json:"sn"`
+				A  int    `json:"a"`
+			} `json:"sensor"`
+			GlucoseMeasurement struct {
+				Value     json.Number `json:"Value"`
+				Timestamp string      `json:"Timestamp"`
+			} `json:"glucoseMeasurement"`
+		} `json:"data"`
+		User struct {
+			ID    json.Number `json:"id"`
+			Email string      `json:"email"`
+		} `json:"user"`
+		Ticket struct {
+			Token string `json:"token"`
+		} `json:"ticket"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("anonymized payload is not valid JSON: %v", err)
+	}
+	c := payload.Data[0]
+
+	if c.ID != "00000000-0000-4000-8000-000000000001" || c.PatientID != "00000000-0000-4000-8000-000000000002" {
+		t.Errorf("expected UUIDs to stay UUIDs, got %q and %q", c.ID, c.PatientID)
+	}
+	if c.FirstName != "Name1" {
+		t.Errorf("expected firstName Name1, got %q", c.FirstName)
+	}
+	if c.Sensor.SN != "SN00000001" {
+		t.Errorf("expected the serial number to keep its length, got %q", c.Sensor.SN)
+	}
+	if c.Sensor.A != 1767430800 || c.GlucoseMeasurement.Timestamp != "1/4/2026 12:59:30 PM" {
+		t.Errorf("expected timestamps to be kept, got %d and %q", c.Sensor.A, c.GlucoseMeasurement.Timestamp)
+	}
+	if c.GlucoseMeasurement.Value != "7.0" {
+		t.Errorf("expected numbers to be kept as written, got %s", c.GlucoseMeasurement.Value)
+	}
+	if payload.User.ID != "3" {
+		t.Errorf("expected a numeric user ID pseudonym, got %s", payload.User.ID)
+	}
+	if payload.User.Email != "patient1@example.com" {
+		t.Errorf("expected an example e-mail, got %q", payload.User.Email)
+	}
+	if payload.Ticket.Token != redacted {
+		t.Errorf("expected the token to be redacted, got %q", payload.Ticket.Token)
+	}
+}
+
+func TestAnonymizer_ConsistentWithSnapshot(t *testing.T) {
+	a := NewAnonymizer()
+
+	out, err := a.Payload([]byte(livePayload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	measurements := []*domain.GlucoseMeasurement{{PatientID: "9a2c4e6f-1b3d-4f5a-8c7e-0d2f4a6b8c9e"}}
+	sensors := []*domain.SensorConfig{{
+		PatientID:    "9a2c4e6f-1b3d-4f5a-8c7e-0d2f4a6b8c9e",
+		SerialNumber: "0K1234ABCD",
+		Note:         "Fell off at the gym",
+	}}
+	a.Measurements(measurements)
+	a.Sensors(sensors)
+
+	if !strings.Contains(string(out), measurements[0].PatientID) {
+		t.Errorf("expected the measurement patient %q to match the payload: %s", measurements[0].PatientID, out)
+	}
+	if sensors[0].PatientID != measurements[0].PatientID {
+		t.Errorf("expected the same patient pseudonym, got %q and %q", sensors[0].PatientID, measurements[0].PatientID)
+	}
+	if sensors[0].SerialNumber != "SN00000001" {
+		t.Errorf("expected the serial pseudonym of the payload, got %q", sensors[0].SerialNumber)
+	}
+	if sensors[0].Note != "" {
+		t.Errorf("expected the note to be dropped, got %q", sensors[0].Note)
+	}
+}
+
+func TestAnonymizer_InvalidPayload(t *testing.T) {
+	if _, err := NewAnonymizer().Payload([]byte("<html>")); err == nil {
+		t.Error("expected an error for a non-JSON payload")
+	}
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	snapshot, err := LoadSnapshot(filepath.Join("..", "..", "testdata", "fixtures", "db", "snapshot.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !snapshot.Anonymized {
+		t.Error("expected the committed snapshot to be anonymized")
+	}
+	if len(snapshot.Measurements) == 0 || len(snapshot.Sensors) == 0 {
+		t.Fatalf("expected measurements and sensors, got %d and %d", len(snapshot.Measurements), len(snapshot.Sensors))
+	}
+	for _, m := range snapshot.Measurements {
+		if m.Timestamp.IsZero() || m.ValueInMgPerDl == 0 {
+			t.Errorf("incomplete measurement %+v", m)
+		}
+	}
+}
+
+func TestLoadSnapshot_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSnapshot(path); err == nil {
+		t.Error("expected an error for an invalid snapshot")
+	}
+}
@@ -0,0 +1,81 @@
+// Package heartbeat pings an external monitor after each successful fetch.
+//
+// A dead man's switch such as healthchecks.io or an Uptime Kuma push monitor
+// expects a ping at a regular interval and alerts when pings stop, which also
+// covers the whole server going down, when /health cannot be reached at all.
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// pingTimeout bounds a single ping so a slow monitor does not delay the next one.
+const pingTimeout = 10 * time.Second
+
+// Pinger sends pings to a monitoring URL.
+type Pinger struct {
+	url     string
+	client  *http.Client
+	logger  *slog.Logger
+	pending chan struct{}
+}
+
+// NewPinger creates a new Pinger for url.
+func NewPinger(url string, logger *slog.Logger) *Pinger {
+	return &Pinger{
+		url:     url,
+		client:  &http.Client{Timeout: pingTimeout},
+		logger:  logger,
+		pending: make(chan struct{}, 1),
+	}
+}
+
+// Notify requests a ping without blocking the caller.
+// Requests made while a ping is pending are coalesced into it.
+func (p *Pinger) Notify() {
+	select {
+	case p.pending <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends the requested pings until ctx is cancelled.
+// Failed pings are logged and not retried: the next fetch pings again.
+func (p *Pinger) Run(ctx context.Context) {
+	for {
+		select {
+		case <-p.pending:
+			if err := p.ping(ctx); err != nil {
+				p.logger.Warn("heartbeat ping failed", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ping sends a single GET request to the monitoring URL.
+// The URL is not logged: monitoring URLs usually embed a secret check ID.
+func (p *Pinger) ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("monitor returned status %d", resp.StatusCode)
+	}
+
+	p.logger.Debug("heartbeat ping sent")
+	return nil
+}
@@ -0,0 +1,56 @@
+package heartbeat
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPinger_Run(t *testing.T) {
+	var pings atomic.Int32
+	monitor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+	}))
+	defer monitor.Close()
+
+	pinger := NewPinger(monitor.URL, slog.Default())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Requests made before Run are coalesced into a single ping
+	pinger.Notify()
+	pinger.Notify()
+	go pinger.Run(ctx)
+
+	waitFor(t, func() bool { return pings.Load() == 1 })
+
+	pinger.Notify()
+	waitFor(t, func() bool { return pings.Load() == 2 })
+}
+
+func TestPinger_PingError(t *testing.T) {
+	monitor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer monitor.Close()
+
+	pinger := NewPinger(monitor.URL, slog.Default())
+	if err := pinger.ping(context.Background()); err == nil {
+		t.Fatal("expected error for unknown check, got nil")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for ping")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
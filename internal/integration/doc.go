@@ -0,0 +1,11 @@
+// Package integration holds the end-to-end test suite running the full
+// daemon and API against a fake LibreView server.
+//
+// The tests are guarded by the integration build tag so unit tests stay fast:
+//
+//	go test -tags integration ./internal/integration/...
+//
+// They use a temporary SQLite database, or PostgreSQL when GLCMD_DB_TYPE=postgres
+// and the GLCMD_DB_* variables point to a server (make test-integration starts
+// one with Docker).
+package integration
@@ -0,0 +1,170 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/api"
+	"github.com/R4yL-dev/glcmd/internal/daemon"
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// TestFetchStoreServe verifies the whole flow: the daemon fetches from
+// LibreView, stores the readings and the sensor, and the API serves them over
+// REST and SSE.
+func TestFetchStoreServe(t *testing.T) {
+	h := newHarness(t)
+	h.libreView.SetReadings(142, 110, 118, 125, 131)
+	stream := h.subscribe(t, "glucose,sensor")
+	h.start(t)
+
+	// SSE: one glucose event per stored reading, and the new sensor
+	var glucoseValues []int
+	sensorSeen := false
+	timeout := time.After(10 * time.Second)
+	for len(glucoseValues) < 5 || !sensorSeen {
+		select {
+		case event, ok := <-stream:
+			if !ok {
+				t.Fatal("event stream closed")
+			}
+			switch event.Type {
+			case "glucose":
+				var m domain.GlucoseMeasurement
+				if err := json.Unmarshal([]byte(event.Data), &m); err != nil {
+					t.Fatalf("failed to decode glucose event: %v", err)
+				}
+				glucoseValues = append(glucoseValues, m.ValueInMgPerDl)
+			case "sensor":
+				sensorSeen = true
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for events: %d glucose, sensor %v", len(glucoseValues), sensorSeen)
+		}
+	}
+	if glucoseValues[0] != 142 {
+		t.Errorf("expected the current reading first, got %v", glucoseValues)
+	}
+
+	// REST: latest reading and history
+	var latest api.GlucoseResponse
+	if status := h.get(t, "/v1/glucose/latest", &latest); status != http.StatusOK {
+		t.Fatalf("expected status 200 for the latest reading, got %d", status)
+	}
+	if latest.Data.ValueInMgPerDl != 142 || latest.Data.Type != domain.GlucoseTypeCurrent {
+		t.Errorf("unexpected latest reading: %+v", latest.Data)
+	}
+
+	var list api.GlucoseListResponse
+	h.get(t, "/v1/glucose?limit=10", &list)
+	if list.Pagination.Total != 5 {
+		t.Errorf("expected 5 stored readings, got %d", list.Pagination.Total)
+	}
+
+	var sensor api.LatestSensorResponse
+	if status := h.get(t, "/v1/sensor/latest", &sensor); status != http.StatusOK {
+		t.Fatalf("expected status 200 for the sensor, got %d", status)
+	}
+	if sensor.Data.SerialNumber != "FAKE0001" {
+		t.Errorf("unexpected sensor: %+v", sensor.Data)
+	}
+
+	// Connection details and daemon health
+	var connection api.ConnectionResponse
+	h.get(t, "/v1/connection", &connection)
+	if connection.Data == nil || connection.Data.PatientInitials != "J.D." {
+		t.Errorf("unexpected connection: %+v", connection.Data)
+	}
+
+	var health struct {
+		Data daemon.HealthStatus `json:"data"`
+	}
+	waitFor(t, 5*time.Second, "a healthy daemon", func() bool {
+		return h.get(t, "/health", &health) == http.StatusOK
+	})
+	if !health.Data.DatabaseConnected {
+		t.Error("expected the database to be connected")
+	}
+}
+
+// TestRestartDeduplicates verifies that a second daemon fetching the same
+// readings stores nothing twice.
+func TestRestartDeduplicates(t *testing.T) {
+	h := newHarness(t)
+	h.libreView.SetReadings(120, 100, 105)
+	h.start(t)
+
+	var list api.GlucoseListResponse
+	waitFor(t, 10*time.Second, "the initial fetch", func() bool {
+		h.get(t, "/v1/glucose", &list)
+		return list.Pagination.Total == 3
+	})
+
+	// The restarted daemon fetches the same readings again
+	h.stop(t)
+	h.daemon = h.newDaemon(t)
+	h.start(t)
+	waitFor(t, 10*time.Second, "the fetch after the restart", func() bool {
+		return h.get(t, "/v1/connection", nil) == http.StatusOK
+	})
+
+	h.get(t, "/v1/glucose", &list)
+	if list.Pagination.Total != 3 {
+		t.Errorf("expected 3 readings after the restart, got %d", list.Pagination.Total)
+	}
+}
+
+// TestRestartReusesSession verifies that a restarted daemon reuses the stored
+// LibreView session instead of logging in, and logs in again once the stored
+// token is rejected.
+func TestRestartReusesSession(t *testing.T) {
+	h := newHarness(t)
+	h.libreView.SetReadings(120, 100, 105)
+	h.start(t)
+
+	restart := func(step string) {
+		h.stop(t)
+		h.daemon = h.newDaemon(t)
+		h.start(t)
+		waitFor(t, 10*time.Second, step, func() bool {
+			return h.get(t, "/v1/connection", nil) == http.StatusOK
+		})
+	}
+
+	waitFor(t, 10*time.Second, "the initial fetch", func() bool {
+		return h.get(t, "/v1/connection", nil) == http.StatusOK
+	})
+
+	restart("the fetch after the restart")
+	if logins := h.libreView.Logins(); logins != 1 {
+		t.Errorf("expected the stored session to be reused, got %d logins", logins)
+	}
+
+	h.libreView.RevokeTokens()
+	restart("the fetch after the token was revoked")
+	if logins := h.libreView.Logins(); logins != 2 {
+		t.Errorf("expected a login after the token was revoked, got %d logins", logins)
+	}
+}
+
+// TestAuthenticationFailure verifies that the daemon stops when LibreView
+// rejects the credentials.
+func TestAuthenticationFailure(t *testing.T) {
+	h := newHarness(t)
+	h.libreView.FailWith(http.StatusUnauthorized)
+	h.start(t)
+
+	if err := h.waitDaemon(10 * time.Second); err == nil || err == errDaemonRunning {
+		t.Fatalf("expected the daemon to fail authenticating, got %v", err)
+	}
+
+	var list api.GlucoseListResponse
+	h.get(t, "/v1/glucose", &list)
+	if list.Pagination.Total != 0 {
+		t.Errorf("expected no readings, got %d", list.Pagination.Total)
+	}
+}
@@ -0,0 +1,281 @@
+//go:build integration
+
+package integration
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/api"
+	"github.com/R4yL-dev/glcmd/internal/daemon"
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+// models are the tables created for each test, as glcore migrates them.
+var models = []interface{}{
+	&domain.GlucoseMeasurement{},
+	&domain.SensorConfig{},
+	&domain.UserPreferences{},
+	&domain.DeviceInfo{},
+	&domain.GlucoseTargets{},
+	&domain.DashboardConfig{},
+	&domain.DisplayPreferences{},
+	&domain.SavedView{},
+	&domain.APIToken{},
+	&domain.SigningKey{},
+	&domain.Alert{},
+	&domain.TreatmentEntry{},
+	&domain.UpstreamOutage{},
+	&domain.UpstreamSession{},
+	&domain.SensorAttachment{},
+	&domain.Job{},
+	&domain.GlucoseEvent{},
+	&domain.GlucoseRollup{},
+	&domain.DailySummary{},
+}
+
+// harness is a glcore instance wired as in cmd/glcore: the daemon fetching
+// from a fake LibreView server and the API served over HTTP.
+type harness struct {
+	libreView *fakeLibreView
+	database  *persistence.Database
+	daemon    *daemon.Daemon
+	api       *httptest.Server
+	done      chan error // Result of daemon.Run
+
+	glucoseService  service.GlucoseService
+	sensorService   service.SensorService
+	configService   service.ConfigService
+	modeService     service.ModeService
+	alertService    service.AlertService
+	upstreamService service.UpstreamService
+	sessionService  service.SessionService
+}
+
+// newHarness creates the database, services, daemon and API. The daemon is
+// not started: the test sets the fake readings first, then calls start.
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	h := &harness{libreView: newFakeLibreView(t)}
+
+	database := openDatabase(t)
+	h.database = database
+
+	db := database.DB()
+	glucoseRepo := repository.NewGlucoseRepository(db)
+	sensorRepo := repository.NewSensorRepository(db)
+	uow := repository.NewUnitOfWork(db)
+
+	eventBroker := events.NewBroker(100, slog.Default())
+	eventBroker.Start()
+	t.Cleanup(eventBroker.Stop)
+
+	h.glucoseService = service.NewGlucoseService(glucoseRepo, domain.DefaultTargetBands(), slog.Default(), eventBroker)
+	h.sensorService = service.NewSensorService(sensorRepo, glucoseRepo, uow, domain.DefaultSensorGracePeriod, slog.Default(), eventBroker)
+	h.configService = service.NewConfigService(repository.NewUserRepository(db), repository.NewDeviceRepository(db), repository.NewTargetsRepository(db), repository.NewDashboardRepository(db), repository.NewPreferencesRepository(db), slog.Default(), eventBroker)
+	h.modeService = service.NewModeService(slog.Default())
+	h.alertService = service.NewAlertService(repository.NewAlertRepository(db), slog.Default())
+	h.upstreamService = service.NewUpstreamService(repository.NewUpstreamRepository(db), slog.Default())
+	h.sessionService = service.NewSessionService(repository.NewUpstreamSessionRepository(db))
+	h.daemon = h.newDaemon(t)
+
+	server := api.NewServer(api.Options{
+		GlucoseService:    h.glucoseService,
+		SensorService:     h.sensorService,
+		ConfigService:     h.configService,
+		EventBroker:       eventBroker,
+		ModeService:       h.modeService,
+		AlertService:      h.alertService,
+		UpstreamService:   h.upstreamService,
+		GetHealthStatus:   func() daemon.HealthStatus { return h.daemon.GetHealthStatus() },
+		GetConnectionInfo: func() *domain.ConnectionInfo { return h.daemon.GetConnectionInfo() },
+		GetFetchStats:     func() daemon.FetchStats { return h.daemon.GetFetchStats() },
+		GetDatabaseHealth: func() bool { return database.Ping(context.Background()) == nil },
+	}, slog.Default())
+	h.api = httptest.NewServer(server.HTTPHandler())
+	t.Cleanup(h.api.Close)
+
+	return h
+}
+
+// openDatabase opens the test database with empty, migrated tables:
+// PostgreSQL when configured through the GLCMD_DB_* variables, else a
+// temporary SQLite database.
+func openDatabase(t *testing.T) *persistence.Database {
+	t.Helper()
+
+	dbConfig := persistence.LoadDatabaseConfigFromEnv()
+	if dbConfig.Type == "sqlite" {
+		dbConfig.SQLitePath = filepath.Join(t.TempDir(), "glcmd.db")
+	}
+	dbConfig.LogLevel = "silent"
+	database, err := persistence.NewDatabase(dbConfig)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	// Start from empty tables, the PostgreSQL database is shared between tests
+	if err := database.DB().Migrator().DropTable(models...); err != nil {
+		t.Fatalf("failed to drop tables: %v", err)
+	}
+	if err := database.DB().AutoMigrate(models...); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	return database
+}
+
+// newDaemon creates a daemon fetching from the fake LibreView server.
+func (h *harness) newDaemon(t *testing.T) *daemon.Daemon {
+	t.Helper()
+
+	d, err := daemon.New(h.glucoseService, h.sensorService, h.configService, h.modeService, h.alertService, h.upstreamService, nil, "patient@example.com", "password", "", "")
+	if err != nil {
+		t.Fatalf("failed to create daemon: %v", err)
+	}
+	d.SetTransport(h.libreView.Transport())
+	d.SetSessionService(h.sessionService)
+	return d
+}
+
+// start runs the daemon in the background until stop or the end of the test.
+func (h *harness) start(t *testing.T) {
+	t.Helper()
+
+	d, done := h.daemon, make(chan error, 1)
+	h.done = done
+	go func() { done <- d.Run() }()
+	t.Cleanup(func() { stopDaemon(t, d, done) })
+}
+
+// stop stops the running daemon and waits for it.
+func (h *harness) stop(t *testing.T) {
+	t.Helper()
+	stopDaemon(t, h.daemon, h.done)
+}
+
+// stopDaemon stops d and waits for its Run to return on done.
+func stopDaemon(t *testing.T, d *daemon.Daemon, done chan error) {
+	t.Helper()
+
+	d.Stop()
+	select {
+	case err := <-done:
+		done <- err // Keep it for later calls
+	case <-time.After(10 * time.Second):
+		t.Error("daemon did not stop")
+	}
+}
+
+// get requests path from the API and decodes the JSON response into result.
+// Returns the HTTP status.
+func (h *harness) get(t *testing.T, path string, result interface{}) int {
+	t.Helper()
+
+	resp, err := http.Get(h.api.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK && result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			t.Fatalf("failed to decode %s: %v", path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// sseEvent is an event read from the SSE stream.
+type sseEvent struct {
+	Type string
+	Data string
+}
+
+// subscribe opens the SSE stream and returns the events received until the
+// end of the test.
+func (h *harness) subscribe(t *testing.T, types string) <-chan sseEvent {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, h.api.URL+"/v1/stream?types="+types, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open the event stream: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		t.Fatalf("expected status 200 for the event stream, got %d", resp.StatusCode)
+	}
+
+	ch := make(chan sseEvent, 100)
+	go func() {
+		defer resp.Body.Close()
+		defer close(ch)
+
+		scanner := bufio.NewScanner(resp.Body)
+		var event sseEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event.Type = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.Data = strings.TrimPrefix(line, "data: ")
+			case line == "" && event.Type != "":
+				ch <- event
+				event = sseEvent{}
+			}
+		}
+	}()
+
+	// Wait for the subscription to be registered before events are published
+	time.Sleep(100 * time.Millisecond)
+	return ch
+}
+
+// waitFor calls check until it returns true or timeout elapses.
+func waitFor(t *testing.T, timeout time.Duration, what string, check func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if check() {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+// errDaemonRunning is returned by waitDaemon when the daemon is still running.
+var errDaemonRunning = errors.New("daemon still running")
+
+// waitDaemon returns the result of daemon.Run, or errDaemonRunning if it is
+// still running after timeout.
+func (h *harness) waitDaemon(timeout time.Duration) error {
+	select {
+	case err := <-h.done:
+		h.done <- err // Keep it for the cleanup
+		return err
+	case <-time.After(timeout):
+		return errDaemonRunning
+	}
+}
@@ -0,0 +1,212 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/libreclient"
+)
+
+// libreViewLayout is the timestamp format of the LibreView API.
+const libreViewLayout = "1/2/2006 3:04:05 PM"
+
+const fakePatientID = "fake-patient"
+
+// fakeLibreView serves the LibreLinkUp endpoints used by the daemon with a
+// fixed patient whose readings are set by the test.
+type fakeLibreView struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	current   int       // Current reading (mg/dL)
+	currentAt time.Time // Time of the current reading
+	history   []int     // Historical readings, oldest first, 15 minutes apart before currentAt
+	failWith  int       // HTTP status returned by every request (0 = none)
+	logins    int       // Successful logins
+	revoked   int       // Revocations: the tokens issued before are rejected
+}
+
+// newFakeLibreView starts a fake LibreView server, closed at the end of the test.
+func newFakeLibreView(t *testing.T) *fakeLibreView {
+	t.Helper()
+
+	f := &fakeLibreView{currentAt: time.Now().UTC().Truncate(time.Minute)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /llu/auth/login", f.handleLogin)
+	mux.HandleFunc("GET /llu/connections", f.authorized(f.handleConnections))
+	mux.HandleFunc("GET /llu/connections/{patientId}/graph", f.authorized(f.handleGraph))
+	f.server = httptest.NewServer(f.failing(mux))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// Transport returns a transport sending the LibreView requests to the fake server.
+func (f *fakeLibreView) Transport() http.RoundTripper {
+	target, _ := url.Parse(f.server.URL)
+	return redirectTransport{target: target}
+}
+
+// SetReadings sets the current reading and the historical readings.
+func (f *fakeLibreView) SetReadings(current int, history ...int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.current = current
+	f.history = history
+}
+
+// Logins returns the number of logins.
+func (f *fakeLibreView) Logins() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.logins
+}
+
+// RevokeTokens rejects the tokens issued so far, as LibreView does when the
+// password changes or the session is closed from the app.
+func (f *fakeLibreView) RevokeTokens() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.revoked++
+}
+
+// token returns the token issued by a login now. Must be called with mu held.
+func (f *fakeLibreView) token() string {
+	return fmt.Sprintf("fake-token-%d", f.revoked)
+}
+
+// authorized rejects the requests without a valid token.
+func (f *fakeLibreView) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		valid := r.Header.Get("Authorization") == "Bearer "+f.token()
+		f.mu.Unlock()
+		if !valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// FailWith makes every request answer status (0 restores normal answers).
+func (f *fakeLibreView) FailWith(status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failWith = status
+}
+
+func (f *fakeLibreView) failing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		status := f.failWith
+		f.mu.Unlock()
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *fakeLibreView) handleLogin(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logins++
+
+	var resp libreclient.AuthResponse
+	resp.Data.User.ID = "fake-user"
+	resp.Data.AuthTicket.Token = f.token()
+	resp.Data.AuthTicket.Expires = time.Now().Add(180 * 24 * time.Hour).Unix()
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (f *fakeLibreView) handleConnections(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var resp libreclient.ConnectionsResponse
+	resp.Data = make([]libreclient.Connection, 1)
+
+	c := &resp.Data[0]
+	c.PatientID = fakePatientID
+	c.FirstName = "Jane"
+	c.LastName = "Doe"
+	c.Country = "CH"
+	c.GlucoseMeasurement.ValueInMgPerDl = f.current
+	c.GlucoseMeasurement.Value = float64(f.current) / 18
+	c.GlucoseMeasurement.TrendArrow = 3
+	c.GlucoseMeasurement.MeasurementColor = 1
+	c.GlucoseMeasurement.FactoryTimestamp = f.currentAt.Format(libreViewLayout)
+	c.GlucoseMeasurement.Timestamp = f.currentAt.Format(libreViewLayout)
+	c.Sensor = f.sensor()
+	c.PatientDevice = libreclient.PatientDevice{DID: "fake-phone", LL: 70, HL: 250, Alarms: true}
+	c.TargetLow = 70
+	c.TargetHigh = 180
+	c.Uom = 1
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (f *fakeLibreView) handleGraph(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("patientId") != fakePatientID {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var resp libreclient.GraphResponse
+	resp.Data.Connection.Sensor = f.sensor()
+	for i, value := range f.history {
+		ts := f.currentAt.Add(-time.Duration(len(f.history)-i) * 15 * time.Minute).Format(libreViewLayout)
+		resp.Data.GraphData = append(resp.Data.GraphData, struct {
+			FactoryTimestamp string  `json:"FactoryTimestamp"`
+			Timestamp        string  `json:"Timestamp"`
+			ValueInMgPerDl   int     `json:"ValueInMgPerDl"`
+			Value            float64 `json:"Value"`
+			MeasurementColor int     `json:"MeasurementColor"`
+			GlucoseUnits     int     `json:"GlucoseUnits"`
+			IsHigh           bool    `json:"isHigh"`
+			IsLow            bool    `json:"isLow"`
+			Type             int     `json:"type"`
+		}{
+			FactoryTimestamp: ts,
+			Timestamp:        ts,
+			ValueInMgPerDl:   value,
+			Value:            float64(value) / 18,
+			MeasurementColor: 1,
+		})
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+// sensor returns a Libre 3 Plus sensor activated two days before the current reading.
+// Must be called with f.mu held.
+func (f *fakeLibreView) sensor() libreclient.SensorData {
+	return libreclient.SensorData{
+		SN: "FAKE0001",
+		A:  int(f.currentAt.Add(-48 * time.Hour).Unix()),
+		PT: 4,
+	}
+}
+
+// redirectTransport sends all requests to a test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
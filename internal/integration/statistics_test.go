@@ -0,0 +1,164 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/internal/utils/filterexpr"
+)
+
+// TestGlucoseStatistics verifies the statistics computed by SQL on the
+// configured database, with both glucose repository implementations.
+func TestGlucoseStatistics(t *testing.T) {
+	db := openDatabase(t).DB()
+	ctx := context.Background()
+
+	sqlRepo, err := repository.NewGlucoseRepositorySQL(db)
+	if err != nil {
+		t.Fatalf("failed to create SQL repository: %v", err)
+	}
+	t.Cleanup(func() { sqlRepo.Close() })
+
+	// One low, two normal and one high reading, at 02:00, 08:00, 14:00 and
+	// 20:00 local time
+	readings := []struct {
+		hour  int
+		mgdl  int
+		mmol  float64
+		color int
+		low   bool
+		high  bool
+	}{
+		{2, 60, 3.3, 3, true, false},
+		{8, 99, 5.5, 1, false, false},
+		{14, 119, 6.6, 1, false, false},
+		{20, 250, 13.9, 2, false, true},
+	}
+	for _, r := range readings {
+		ts := time.Date(2026, 3, 10, r.hour, 0, 0, 0, time.Local).UTC()
+		m := &domain.GlucoseMeasurement{
+			FactoryTimestamp: ts,
+			Timestamp:        ts,
+			Value:            r.mmol,
+			ValueInMgPerDl:   r.mgdl,
+			GlucoseColor:     r.color,
+			IsLow:            r.low,
+			IsHigh:           r.high,
+		}
+		if _, err := sqlRepo.Save(ctx, m); err != nil {
+			t.Fatalf("failed to save measurement: %v", err)
+		}
+	}
+
+	afternoon, err := filterexpr.Parse("hour >= 12", repository.GlucoseQueryFields)
+	if err != nil {
+		t.Fatalf("failed to parse query: %v", err)
+	}
+	low, high := 70, 180
+
+	repos := map[string]repository.GlucoseRepository{
+		"gorm": repository.NewGlucoseRepository(db),
+		"sql":  sqlRepo,
+	}
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			stats, err := repo.GetStatistics(ctx, repository.GlucoseStatisticsFilters{
+				TargetLowMgDl:  &low,
+				TargetHighMgDl: &high,
+				Bands:          []domain.TargetBand{{Name: "tight", LowMgDl: 70, HighMgDl: 140}},
+			})
+			if err != nil {
+				t.Fatalf("failed to get statistics: %v", err)
+			}
+
+			if stats.Count != 4 {
+				t.Fatalf("expected 4 measurements, got %d", stats.Count)
+			}
+			if math.Abs(stats.Average-7.325) > 1e-6 || math.Abs(stats.AverageMgDl-132) > 1e-6 {
+				t.Errorf("expected average 7.325 (132 mg/dL), got %v (%v)", stats.Average, stats.AverageMgDl)
+			}
+			if stats.MinMgDl != 60 || stats.MaxMgDl != 250 {
+				t.Errorf("expected min 60 and max 250 mg/dL, got %d and %d", stats.MinMgDl, stats.MaxMgDl)
+			}
+			if stats.LowCount != 1 || stats.NormalCount != 2 || stats.HighCount != 1 {
+				t.Errorf("expected 1 low, 2 normal and 1 high, got %d, %d and %d", stats.LowCount, stats.NormalCount, stats.HighCount)
+			}
+			if stats.BelowRangeCount != 1 || stats.InRangeCount != 2 || stats.AboveRangeCount != 1 {
+				t.Errorf("expected 1 below, 2 in and 1 above range, got %d, %d and %d", stats.BelowRangeCount, stats.InRangeCount, stats.AboveRangeCount)
+			}
+			if len(stats.BandCounts) != 1 || stats.BandCounts[0] != 2 {
+				t.Errorf("expected 2 measurements in the tight band, got %v", stats.BandCounts)
+			}
+			first := time.Date(2026, 3, 10, 2, 0, 0, 0, time.Local)
+			if stats.FirstTimestamp == nil || !stats.FirstTimestamp.Equal(first) {
+				t.Errorf("expected first timestamp %v, got %v", first, stats.FirstTimestamp)
+			}
+
+			// The hour field is evaluated in local time
+			stats, err = repo.GetStatistics(ctx, repository.GlucoseStatisticsFilters{Query: afternoon})
+			if err != nil {
+				t.Fatalf("failed to get filtered statistics: %v", err)
+			}
+			if stats.Count != 2 || stats.MinMgDl != 119 {
+				t.Errorf("expected the 2 afternoon measurements, got %d (min %d)", stats.Count, stats.MinMgDl)
+			}
+		})
+	}
+}
+
+// TestSensorStatistics verifies the sensor durations computed by SQL on the
+// configured database.
+func TestSensorStatistics(t *testing.T) {
+	db := openDatabase(t).DB()
+	repo := repository.NewSensorRepository(db)
+	ctx := context.Background()
+
+	// Two sensors worn 10 and 14.5 days, and the current one
+	start := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	sensors := []struct {
+		serial string
+		worn   time.Duration
+	}{
+		{"INTEGRATION_A", 240 * time.Hour},
+		{"INTEGRATION_B", 348 * time.Hour},
+		{"INTEGRATION_C", 0},
+	}
+	for i, s := range sensors {
+		activation := start.AddDate(0, 0, 20*i)
+		sensor := &domain.SensorConfig{
+			SerialNumber: s.serial,
+			Activation:   activation,
+			ExpiresAt:    activation.AddDate(0, 0, 15),
+			SensorType:   4,
+			DurationDays: 15,
+			DetectedAt:   activation,
+		}
+		if s.worn > 0 {
+			endedAt := activation.Add(s.worn)
+			sensor.EndedAt = &endedAt
+		}
+		if err := repo.Save(ctx, sensor); err != nil {
+			t.Fatalf("failed to save sensor: %v", err)
+		}
+	}
+
+	stats, err := repo.GetStatistics(ctx, repository.SensorStatisticsFilters{})
+	if err != nil {
+		t.Fatalf("failed to get statistics: %v", err)
+	}
+	if stats.TotalSensors != 3 || stats.CompletedSensors != 2 {
+		t.Errorf("expected 3 sensors, 2 completed, got %d, %d", stats.TotalSensors, stats.CompletedSensors)
+	}
+	if math.Abs(stats.MinDuration-10) > 1e-6 || math.Abs(stats.AvgDuration-12.25) > 1e-6 || math.Abs(stats.MaxDuration-14.5) > 1e-6 {
+		t.Errorf("expected durations 10/12.25/14.5 days, got %v/%v/%v", stats.MinDuration, stats.AvgDuration, stats.MaxDuration)
+	}
+	if stats.AvgExpected != 15 {
+		t.Errorf("expected 15 expected days, got %v", stats.AvgExpected)
+	}
+}
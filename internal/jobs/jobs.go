@@ -0,0 +1,572 @@
+// Package jobs runs background work: imports, scheduled maintenance, reports
+// and integrations.
+//
+// Jobs are stored in the jobs table and run by a pool of workers, so they
+// survive restarts and can be observed (and canceled) by ID after they were
+// enqueued. A job whose function fails is retried with an exponential backoff
+// until it runs out of attempts. Recurring jobs are enqueued by schedules
+// (see ParseSchedule); a schedule skips a run while a job of its type is
+// still pending or running.
+//
+// Progress is kept in memory while a job runs and stored with its outcome,
+// so workers do not write to the database while another job may hold a
+// long transaction.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+const (
+	// maxErrors is how many non-fatal errors a job keeps.
+	maxErrors = 100
+	// defaultPollInterval is how often idle workers look for due jobs
+	// (retries). Enqueued jobs wake them immediately.
+	defaultPollInterval = 5 * time.Second
+	// storeTimeout bounds the database calls recording a job's outcome.
+	storeTimeout = 10 * time.Second
+	// defaultBackoff is the delay before the first retry of a failed job.
+	defaultBackoff = time.Minute
+
+	// TypeCleanup is the built-in job deleting the jobs finished more than
+	// the retention ago. It runs daily.
+	TypeCleanup = "jobCleanup"
+)
+
+// ErrUnknownType is returned by Enqueue and Schedule for unregistered job types.
+var ErrUnknownType = errors.New("unknown job type")
+
+// Func is the work of a job. It should stop when ctx is canceled and report
+// its progress through p. job holds the payload and the attempt number. The
+// returned value, encoded as JSON, becomes the job's result.
+type Func func(ctx context.Context, job *domain.Job, p *Progress) (any, error)
+
+// Options configures the jobs of a type.
+type Options struct {
+	MaxAttempts int           // Attempts before the job fails (default 1: no retry)
+	Backoff     time.Duration // Delay before the first retry, doubled after each (default 1m)
+}
+
+// ScheduleInfo describes a recurring job.
+type ScheduleInfo struct {
+	Type    string    `json:"type"`
+	Spec    string    `json:"spec"`
+	NextRun time.Time `json:"nextRun"`
+}
+
+// Progress reports the progress of a running job.
+type Progress struct {
+	m  *Manager
+	id string
+}
+
+// SetTotal sets the number of items the job will process.
+func (p *Progress) SetTotal(total int) {
+	p.m.update(p.id, func(j *domain.Job) { j.Total = total })
+}
+
+// SetDone sets the number of items processed so far.
+func (p *Progress) SetDone(done int) {
+	p.m.update(p.id, func(j *domain.Job) { j.Done = done })
+}
+
+// Errorf records a non-fatal error. Only the first errors are kept.
+func (p *Progress) Errorf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	p.m.update(p.id, func(j *domain.Job) {
+		if len(j.Errors) < maxErrors {
+			j.Errors = append(j.Errors, msg)
+		}
+	})
+}
+
+// handler is the function and options of a job type.
+type handler struct {
+	fn   Func
+	opts Options
+}
+
+// schedule enqueues a job type at the times of a spec.
+type schedule struct {
+	jobType string
+	spec    string
+	sched   Schedule
+	next    time.Time
+}
+
+// runningJob is a job being run by a worker: its live progress and the
+// cancel function of its context.
+type runningJob struct {
+	job      domain.Job
+	cancel   context.CancelFunc
+	canceled bool
+}
+
+// Manager stores, runs and schedules background jobs.
+type Manager struct {
+	store     repository.JobRepository
+	workers   int
+	retention time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+	poll      time.Duration
+
+	mu        sync.Mutex
+	handlers  map[string]handler
+	schedules []*schedule
+	running   map[string]*runningJob
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewManager creates a Manager running jobs with the given number of workers
+// and deleting finished jobs after retention.
+func NewManager(store repository.JobRepository, workers int, retention time.Duration, logger *slog.Logger) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		store:     store,
+		workers:   workers,
+		retention: retention,
+		logger:    logger,
+		now:       time.Now,
+		poll:      defaultPollInterval,
+		handlers:  make(map[string]handler),
+		running:   make(map[string]*runningJob),
+		wake:      make(chan struct{}, 1),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	m.Register(TypeCleanup, m.cleanup, Options{})
+	if err := m.Schedule("@daily", TypeCleanup); err != nil {
+		panic(err) // The spec is a constant
+	}
+
+	return m
+}
+
+// Register sets the function running the jobs of a type. Register the types
+// before calling Start.
+func (m *Manager) Register(jobType string, fn Func, opts Options) {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultBackoff
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[jobType] = handler{fn: fn, opts: opts}
+}
+
+// Registered reports whether jobs of a type can be enqueued.
+func (m *Manager) Registered(jobType string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.handlers[jobType]
+	return ok
+}
+
+// Schedule enqueues a job of a registered type at every time of spec (see
+// ParseSchedule). Add the schedules before calling Start.
+func (m *Manager) Schedule(spec, jobType string) error {
+	sched, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.handlers[jobType]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownType, jobType)
+	}
+	m.schedules = append(m.schedules, &schedule{
+		jobType: jobType,
+		spec:    spec,
+		sched:   sched,
+		next:    sched.Next(m.now()),
+	})
+	return nil
+}
+
+// Schedules returns the recurring jobs, soonest first.
+func (m *Manager) Schedules() []ScheduleInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	infos := make([]ScheduleInfo, 0, len(m.schedules))
+	for _, s := range m.schedules {
+		infos = append(infos, ScheduleInfo{Type: s.jobType, Spec: s.spec, NextRun: s.next})
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].NextRun.Before(infos[j].NextRun)
+	})
+	return infos
+}
+
+// Start returns the jobs interrupted by a previous stop to the queue, then
+// starts the workers and the schedules.
+func (m *Manager) Start(ctx context.Context) error {
+	requeued, err := m.store.RequeueRunning(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to requeue interrupted jobs: %w", err)
+	}
+	if requeued > 0 {
+		m.logger.Info("interrupted jobs requeued", "count", requeued)
+	}
+
+	m.wg.Add(m.workers + 1)
+	for range m.workers {
+		go m.work()
+	}
+	go m.runSchedules()
+
+	m.logger.Info("job workers started", "workers", m.workers, "schedules", len(m.schedules))
+	return nil
+}
+
+// Enqueue stores a new job of a registered type, due now. payload is encoded
+// as JSON and available to the job's function.
+func (m *Manager) Enqueue(ctx context.Context, jobType string, payload any) (*domain.Job, error) {
+	return m.enqueue(ctx, jobType, "", m.now(), payload)
+}
+
+// enqueue stores a new job due at runAt.
+func (m *Manager) enqueue(ctx context.Context, jobType, spec string, runAt time.Time, payload any) (*domain.Job, error) {
+	m.mu.Lock()
+	h, ok := m.handlers[jobType]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, jobType)
+	}
+
+	job := &domain.Job{
+		ID:          uuid.NewString(),
+		CreatedAt:   m.now().UTC(),
+		Type:        jobType,
+		Schedule:    spec,
+		Status:      domain.JobStatusPending,
+		RunAt:       runAt.UTC(),
+		MaxAttempts: h.opts.MaxAttempts,
+	}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode job payload: %w", err)
+		}
+		job.Payload = data
+	}
+
+	if err := m.store.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	m.logger.Debug("job enqueued", "jobID", job.ID, "type", jobType, "schedule", spec)
+
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+// Get returns a job, with its live progress if it is running.
+// Returns persistence.ErrNotFound for unknown job IDs.
+func (m *Manager) Get(ctx context.Context, id string) (*domain.Job, error) {
+	job, err := m.store.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	m.withProgress(job)
+	return job, nil
+}
+
+// List returns the jobs matching filters, newest first, and their total count.
+func (m *Manager) List(ctx context.Context, filters repository.JobFilters, limit, offset int) ([]*domain.Job, int64, error) {
+	jobs, err := m.store.FindWithFilters(ctx, filters, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := m.store.CountWithFilters(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, job := range jobs {
+		m.withProgress(job)
+	}
+	return jobs, total, nil
+}
+
+// Cancel cancels a pending job, or asks a running job to stop, and returns
+// it. A running job is canceled once its function returns with an error; a
+// job that completes anyway succeeds. Canceling a finished job does nothing.
+// Returns persistence.ErrNotFound for unknown job IDs.
+func (m *Manager) Cancel(ctx context.Context, id string) (*domain.Job, error) {
+	m.mu.Lock()
+	if r, ok := m.running[id]; ok {
+		r.canceled = true
+		r.cancel()
+		m.mu.Unlock()
+		m.logger.Info("job cancel requested", "jobID", id)
+		return m.Get(ctx, id)
+	}
+	m.mu.Unlock()
+
+	canceled, err := m.store.CancelPending(ctx, id, m.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if canceled {
+		m.logger.Info("job canceled", "jobID", id)
+	}
+	return m.Get(ctx, id)
+}
+
+// Shutdown stops the schedules and the workers and waits for the running
+// jobs to stop, or for ctx to be done. Interrupted jobs return to the queue
+// and run again after the next Start.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work runs due jobs until the manager stops.
+func (m *Manager) work() {
+	defer m.wg.Done()
+
+	for m.ctx.Err() == nil {
+		job, err := m.store.ClaimNext(m.ctx, m.now().UTC())
+		if err == nil {
+			m.run(job)
+			continue
+		}
+		if !errors.Is(err, persistence.ErrNotFound) && m.ctx.Err() == nil {
+			m.logger.Warn("failed to claim job", "error", err)
+		}
+
+		timer := time.NewTimer(m.poll)
+		select {
+		case <-m.ctx.Done():
+		case <-m.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// run executes a claimed job and records its outcome.
+func (m *Manager) run(job *domain.Job) {
+	m.mu.Lock()
+	h, ok := m.handlers[job.Type]
+	m.mu.Unlock()
+
+	// Each attempt reports its own progress
+	job.Done, job.Total, job.Errors, job.Error = 0, 0, nil, ""
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	r := &runningJob{job: *job, cancel: cancel}
+	m.mu.Lock()
+	m.running[job.ID] = r
+	m.mu.Unlock()
+
+	m.logger.Info("job started", "jobID", job.ID, "type", job.Type, "attempt", job.Attempts)
+
+	var result any
+	var err error
+	if ok {
+		result, err = m.call(ctx, h.fn, job)
+	} else {
+		err = fmt.Errorf("%w: %s", ErrUnknownType, job.Type)
+	}
+
+	m.mu.Lock()
+	delete(m.running, job.ID)
+	job.Done, job.Total, job.Errors = r.job.Done, r.job.Total, r.job.Errors
+	canceled := r.canceled
+	m.mu.Unlock()
+	cancel()
+
+	now := m.now().UTC()
+	job.FinishedAt = &now
+	switch {
+	case err == nil:
+		job.Status = domain.JobStatusSucceeded
+		if result != nil {
+			if job.Result, err = json.Marshal(result); err != nil {
+				job.Status = domain.JobStatusFailed
+				job.Error = fmt.Sprintf("failed to encode job result: %v", err)
+			}
+		}
+	case canceled:
+		job.Status = domain.JobStatusCanceled
+	case m.ctx.Err() != nil:
+		// Stopping: run the attempt again after the next start
+		job.Status = domain.JobStatusPending
+		job.Attempts--
+		job.FinishedAt = nil
+	case ok && job.Attempts < job.MaxAttempts:
+		job.Status = domain.JobStatusPending
+		job.Error = err.Error()
+		job.RunAt = now.Add(h.opts.Backoff << (job.Attempts - 1))
+		job.FinishedAt = nil
+	default:
+		job.Status = domain.JobStatusFailed
+		job.Error = err.Error()
+	}
+
+	storeCtx, storeCancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer storeCancel()
+	if err := m.store.Update(storeCtx, job); err != nil {
+		m.logger.Error("failed to save job", "jobID", job.ID, "error", err)
+	}
+
+	attrs := []any{
+		"jobID", job.ID,
+		"type", job.Type,
+		"status", job.Status,
+		"attempt", job.Attempts,
+		"duration", now.Sub(*job.StartedAt),
+	}
+	switch {
+	case job.Status == domain.JobStatusPending && job.Error != "":
+		m.logger.Warn("job failed, will retry", append(attrs, "error", job.Error, "retryAt", job.RunAt)...)
+	case job.Status == domain.JobStatusFailed:
+		m.logger.Warn("job failed", append(attrs, "error", job.Error)...)
+	default:
+		m.logger.Info("job finished", attrs...)
+	}
+}
+
+// call runs fn, turning a panic into an error so a faulty job cannot stop
+// its worker.
+func (m *Manager) call(ctx context.Context, fn Func, job *domain.Job) (result any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+	return fn(ctx, job, &Progress{m: m, id: job.ID})
+}
+
+// runSchedules enqueues the recurring jobs when they are due until the
+// manager stops.
+func (m *Manager) runSchedules() {
+	defer m.wg.Done()
+
+	for {
+		m.mu.Lock()
+		var next time.Time
+		for _, s := range m.schedules {
+			if !s.next.IsZero() && (next.IsZero() || s.next.Before(next)) {
+				next = s.next
+			}
+		}
+		m.mu.Unlock()
+		if next.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-m.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		m.enqueueDue()
+	}
+}
+
+// enqueueDue enqueues the scheduled jobs that are due, unless a job of the
+// same type is still pending or running.
+func (m *Manager) enqueueDue() {
+	now := m.now()
+
+	m.mu.Lock()
+	var due []schedule
+	for _, s := range m.schedules {
+		if !s.next.IsZero() && !s.next.After(now) {
+			due = append(due, *s)
+			s.next = s.sched.Next(now)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, s := range due {
+		active, err := m.store.CountActive(m.ctx, s.jobType)
+		if err != nil {
+			m.logger.Warn("failed to check active jobs", "type", s.jobType, "error", err)
+			continue
+		}
+		if active > 0 {
+			m.logger.Info("scheduled job skipped, previous run not finished", "type", s.jobType)
+			continue
+		}
+
+		if _, err := m.enqueue(m.ctx, s.jobType, s.spec, s.next, nil); err != nil {
+			m.logger.Warn("failed to enqueue scheduled job", "type", s.jobType, "error", err)
+		}
+	}
+}
+
+// cleanup deletes the jobs finished more than the retention ago.
+func (m *Manager) cleanup(ctx context.Context, _ *domain.Job, _ *Progress) (any, error) {
+	deleted, err := m.store.DeleteFinishedBefore(ctx, m.now().Add(-m.retention).UTC())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int64{"deleted": deleted}, nil
+}
+
+// update applies fn to the live progress of a running job.
+func (m *Manager) update(id string, fn func(*domain.Job)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r, ok := m.running[id]; ok {
+		fn(&r.job)
+	}
+}
+
+// withProgress copies the live progress of a running job into job.
+func (m *Manager) withProgress(job *domain.Job) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r, ok := m.running[job.ID]; ok && job.Status == domain.JobStatusRunning {
+		job.Done, job.Total = r.job.Done, r.job.Total
+		job.Errors = append(domain.JobErrors(nil), r.job.Errors...)
+	}
+}
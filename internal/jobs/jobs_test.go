@@ -0,0 +1,270 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// newTestManager creates a Manager on a file database, as workers and API
+// handlers use separate connections.
+func newTestManager(t *testing.T) (*Manager, *repository.JobRepositoryGORM) {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "jobs.db") + "?_journal_mode=WAL&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.Job{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	store := repository.NewJobRepository(db)
+	m := NewManager(store, 2, time.Hour, slog.Default())
+	m.poll = 10 * time.Millisecond
+	return m, store
+}
+
+// start starts m and stops it at the end of the test.
+func start(t *testing.T, m *Manager) {
+	t.Helper()
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		m.Shutdown(ctx)
+	})
+}
+
+// waitStatus polls a job until it has the given status.
+func waitStatus(t *testing.T, m *Manager, id, status string) *domain.Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		job, err := m.Get(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected job %s, got %+v", status, job)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManager_Succeeded(t *testing.T) {
+	m, _ := newTestManager(t)
+	m.Register("test", func(ctx context.Context, job *domain.Job, p *Progress) (any, error) {
+		p.SetTotal(3)
+		p.Errorf("row %d skipped", 2)
+		p.SetDone(3)
+		return map[string]string{"payload": string(job.Payload)}, nil
+	}, Options{})
+	start(t, m)
+
+	job, err := m.Enqueue(context.Background(), "test", map[string]int{"n": 1})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if job.Status != domain.JobStatusPending || job.MaxAttempts != 1 {
+		t.Errorf("expected a pending job with one attempt, got %+v", job)
+	}
+
+	job = waitStatus(t, m, job.ID, domain.JobStatusSucceeded)
+	if job.Done != 3 || job.Total != 3 || len(job.Errors) != 1 || job.Attempts != 1 || job.FinishedAt == nil {
+		t.Errorf("expected progress and errors stored, got %+v", job)
+	}
+	if string(job.Result) != `{"payload":"{\"n\":1}"}` {
+		t.Errorf("expected the result with the payload, got %s", job.Result)
+	}
+
+	if _, err := m.Enqueue(context.Background(), "unknown", nil); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("expected ErrUnknownType, got %v", err)
+	}
+}
+
+func TestManager_Retry(t *testing.T) {
+	m, _ := newTestManager(t)
+	m.Register("flaky", func(ctx context.Context, job *domain.Job, p *Progress) (any, error) {
+		if job.Attempts < 2 {
+			return nil, errors.New("upstream unavailable")
+		}
+		return nil, nil
+	}, Options{MaxAttempts: 3, Backoff: time.Millisecond})
+	m.Register("broken", func(ctx context.Context, job *domain.Job, p *Progress) (any, error) {
+		return nil, errors.New("always fails")
+	}, Options{MaxAttempts: 2, Backoff: time.Millisecond})
+	start(t, m)
+
+	flaky, _ := m.Enqueue(context.Background(), "flaky", nil)
+	broken, _ := m.Enqueue(context.Background(), "broken", nil)
+
+	job := waitStatus(t, m, flaky.ID, domain.JobStatusSucceeded)
+	if job.Attempts != 2 || job.Error != "" {
+		t.Errorf("expected success on the second attempt, got %+v", job)
+	}
+
+	job = waitStatus(t, m, broken.ID, domain.JobStatusFailed)
+	if job.Attempts != 2 || job.Error != "always fails" {
+		t.Errorf("expected failure after 2 attempts, got %+v", job)
+	}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	m, _ := newTestManager(t)
+	started := make(chan struct{})
+	m.Register("slow", func(ctx context.Context, job *domain.Job, p *Progress) (any, error) {
+		p.SetDone(1)
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, Options{MaxAttempts: 3})
+
+	// A pending job is canceled right away
+	pending, _ := m.Enqueue(context.Background(), "slow", nil)
+	job, err := m.Cancel(context.Background(), pending.ID)
+	if err != nil || job.Status != domain.JobStatusCanceled {
+		t.Fatalf("expected the pending job canceled, got %+v (%v)", job, err)
+	}
+
+	start(t, m)
+	running, _ := m.Enqueue(context.Background(), "slow", nil)
+	<-started
+
+	job = waitStatus(t, m, running.ID, domain.JobStatusRunning)
+	if job.Done != 1 {
+		t.Errorf("expected the live progress, got %+v", job)
+	}
+
+	// A canceled job is not retried
+	if _, err := m.Cancel(context.Background(), running.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	waitStatus(t, m, running.ID, domain.JobStatusCanceled)
+
+	if _, err := m.Cancel(context.Background(), "unknown"); err == nil {
+		t.Error("expected an error for an unknown job")
+	}
+}
+
+func TestManager_ShutdownRequeues(t *testing.T) {
+	m, store := newTestManager(t)
+	started := make(chan struct{})
+	m.Register("slow", func(ctx context.Context, job *domain.Job, p *Progress) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, Options{})
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	job, _ := m.Enqueue(context.Background(), "slow", nil)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	// The interrupted attempt does not count
+	stored, err := store.FindByID(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if stored.Status != domain.JobStatusPending || stored.Attempts != 0 {
+		t.Errorf("expected the job back in the queue, got %+v", stored)
+	}
+}
+
+func TestManager_Schedules(t *testing.T) {
+	m, store := newTestManager(t)
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.Local)
+	m.now = func() time.Time { return now }
+
+	var runs atomic.Int32
+	m.Register("report", func(ctx context.Context, job *domain.Job, p *Progress) (any, error) {
+		runs.Add(1)
+		return nil, nil
+	}, Options{})
+	if err := m.Schedule("@every 1h", "report"); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	if err := m.Schedule("@hourly", "unknown"); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("expected ErrUnknownType, got %v", err)
+	}
+
+	schedules := m.Schedules()
+	if len(schedules) != 2 || schedules[0].Type != "report" || !schedules[0].NextRun.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected the report first, then the cleanup, got %+v", schedules)
+	}
+	if schedules[1].Type != TypeCleanup || schedules[1].Spec != "@daily" {
+		t.Errorf("expected the daily cleanup, got %+v", schedules[1])
+	}
+
+	// Due: enqueued with the scheduled time, then skipped while still pending
+	now = now.Add(time.Hour)
+	m.enqueueDue()
+	now = now.Add(time.Hour)
+	m.enqueueDue()
+
+	report := "report"
+	jobs, err := store.FindWithFilters(context.Background(), repository.JobFilters{Type: &report}, 10, 0)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("expected 1 scheduled job, got %d (%v)", len(jobs), err)
+	}
+	if jobs[0].Schedule != "@every 1h" || !jobs[0].RunAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("expected the job of the first run, got %+v", jobs[0])
+	}
+	if next := m.Schedules()[0].NextRun; !next.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected the next run in an hour, got %v", next)
+	}
+}
+
+func TestManager_Cleanup(t *testing.T) {
+	m, store := newTestManager(t)
+	ctx := context.Background()
+
+	old := time.Now().Add(-2 * time.Hour).UTC()
+	recent := time.Now().UTC()
+	for id, finished := range map[string]time.Time{"old": old, "recent": recent} {
+		job := &domain.Job{ID: id, Type: "test", Status: domain.JobStatusSucceeded, RunAt: finished, MaxAttempts: 1, FinishedAt: &finished}
+		if err := store.Create(ctx, job); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	result, err := m.cleanup(ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	if deleted := result.(map[string]int64)["deleted"]; deleted != 1 {
+		t.Errorf("expected 1 deleted job, got %d", deleted)
+	}
+	if _, err := store.FindByID(ctx, "recent"); err != nil {
+		t.Errorf("expected the recent job kept: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, time.Local) // March 1st 2026 is a Sunday
+	}
+
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"0 7 * * *", at(1, 6, 59), at(1, 7, 0)},
+		{"0 7 * * *", at(1, 7, 0), at(2, 7, 0)},
+		{"30 7 * * *", at(1, 22, 0), at(2, 7, 30)},
+		{"*/15 * * * *", at(1, 10, 7), at(1, 10, 15)},
+		{"0 9-17/4 * * *", at(1, 10, 0), at(1, 13, 0)},
+		{"0 0 * * 1-5", at(1, 12, 0), at(2, 0, 0)}, // Next weekday is Monday 2nd
+		{"0 0 * * 7", at(2, 12, 0), at(8, 0, 0)},   // 7 is Sunday
+		{"0 0 15 * 1", at(1, 12, 0), at(2, 0, 0)},  // Day of month or Monday
+		{"0 3 1 4 *", at(1, 12, 0), time.Date(2026, 4, 1, 3, 0, 0, 0, time.Local)},
+		{"@hourly", at(1, 10, 30), at(1, 11, 0)},
+		{"@daily", at(1, 10, 30), at(2, 0, 0)},
+		{"@weekly", at(1, 10, 30), at(8, 0, 0)},
+		{"@every 6h", at(1, 10, 30), at(1, 16, 30)},
+	}
+
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := s.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%q: Next(%v) = %v, want %v", tt.spec, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestParseSchedule_Never(t *testing.T) {
+	s, err := ParseSchedule("0 0 30 2 *") // February 30th
+	if err != nil {
+		t.Fatalf("ParseSchedule: %v", err)
+	}
+	if next := s.Next(time.Now()); !next.IsZero() {
+		t.Errorf("expected no next run, got %v", next)
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@yearly",
+		"@every 30s",
+		"@every soon",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q): expected an error", spec)
+		}
+	}
+}
@@ -4,12 +4,27 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
 )
 
 // AuthResponse represents the authentication response from LibreView API.
+// An account homed in another region gets Redirect and Region instead of a
+// ticket, and must log in again against the regional endpoint. An account
+// with a pending Step (e.g. new terms of use) gets a ticket only valid to
+// complete that step.
 type AuthResponse struct {
-	Data struct {
-		User struct {
+	Status int `json:"status"`
+	Data   struct {
+		Redirect bool      `json:"redirect"`
+		Region   string    `json:"region"`
+		Step     *AuthStep `json:"step,omitempty"`
+		User     struct {
 			ID string `json:"id"`
 		} `json:"user"`
 		AuthTicket struct {
@@ -20,30 +35,153 @@ type AuthResponse struct {
 	} `json:"data"`
 }
 
+// AuthStep is an action LibreView requires before issuing a session.
+type AuthStep struct {
+	Type          string `json:"type"`          // "tou" (terms of use), "pp" (privacy policy), ...
+	ComponentName string `json:"componentName"` // "AcceptDocument" for documents to accept
+}
+
+// acceptDocumentComponent is the component of the steps AcceptTerms can complete.
+const acceptDocumentComponent = "AcceptDocument"
+
 // AuthCredentials holds the credentials for authentication.
 type AuthCredentials struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
+// Session is an authenticated LibreView session.
+type Session struct {
+	Token     string
+	UserID    string
+	AccountID string    // SHA-256 of UserID, sent with authenticated requests
+	ExpiresAt time.Time // Zero if LibreView did not report it
+	Region    string    // Region the account is homed in, empty for the global endpoint
+}
+
 // Authenticate authenticates with the LibreView API and returns the auth token and user ID.
 func (c *Client) Authenticate(ctx context.Context, email, password string) (token, userID, accountID string, err error) {
+	session, err := c.Login(ctx, email, password)
+	if err != nil {
+		return "", "", "", err
+	}
+	return session.Token, session.UserID, session.AccountID, nil
+}
+
+// Login authenticates with the LibreView API and returns the session, with
+// the expiry of its token. When LibreView redirects the account to its region,
+// the client switches to the regional endpoint and logs in again there; the
+// following requests use that endpoint. When LibreView requires a step first,
+// such as accepting new terms of use, Login returns a *TermsError.
+func (c *Client) Login(ctx context.Context, email, password string) (*Session, error) {
 	creds := AuthCredentials{
 		Email:    email,
 		Password: password,
 	}
 
+	resp, err := c.login(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Data.Redirect {
+		region := strings.ToLower(resp.Data.Region)
+		if region == "" {
+			return nil, fmt.Errorf("login redirected without a region")
+		}
+		if err := c.SetRegion(region); err != nil {
+			return nil, err
+		}
+		slog.Info("LibreView account homed in another region, switching endpoint", "region", region, "baseURL", c.baseURL)
+
+		if resp, err = c.login(ctx, creds); err != nil {
+			return nil, err
+		}
+		if resp.Data.Redirect {
+			return nil, fmt.Errorf("login redirected again from region %s to %q", region, resp.Data.Region)
+		}
+	}
+
+	return c.session(resp)
+}
+
+// AcceptTerms accepts the document LibreView requires before a login
+// succeeds, and returns the session issued once it is accepted. LibreView may
+// require another document, returned as another TermsError.
+func (c *Client) AcceptTerms(ctx context.Context, terms *TermsError) (*Session, error) {
+	if !terms.Acceptable {
+		return nil, terms
+	}
+
+	var resp AuthResponse
+	path := "/auth/continue/" + url.PathEscape(terms.Step)
+	if err := c.doRequest(ctx, "POST", path, nil, &resp, terms.token, terms.accountID); err != nil {
+		if !stepResponse(err, &resp) {
+			return nil, err
+		}
+	}
+	return c.session(&resp)
+}
+
+// login posts the credentials. A refused login describing a pending step is
+// returned as its response, like the steps LibreView reports with HTTP 200.
+func (c *Client) login(ctx context.Context, creds AuthCredentials) (*AuthResponse, error) {
 	var resp AuthResponse
 	// No auth needed for login endpoint (empty strings for token/accountID)
 	if err := c.doRequest(ctx, "POST", "/llu/auth/login", creds, &resp, "", ""); err != nil {
-		return "", "", "", err
+		if !stepResponse(err, &resp) {
+			return nil, err
+		}
+	}
+	return &resp, nil
+}
+
+// stepResponse decodes into resp the body of an HTTP error describing a
+// pending step. Returns false for any other error.
+func stepResponse(err error, resp *AuthResponse) bool {
+	var body []byte
+	var authErr *AuthError
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &authErr):
+		body = authErr.Body
+	case errors.As(err, &httpErr):
+		body = httpErr.Body
+	default:
+		return false
+	}
+	return json.Unmarshal(body, resp) == nil && resp.Data.Step != nil
+}
+
+// session returns the session of a login response, or a TermsError when
+// LibreView requires a step first.
+func (c *Client) session(resp *AuthResponse) (*Session, error) {
+	accountID := accountIDOf(resp.Data.User.ID)
+
+	if step := resp.Data.Step; step != nil {
+		return nil, &TermsError{
+			Step:       step.Type,
+			Acceptable: step.ComponentName == acceptDocumentComponent,
+			token:      resp.Data.AuthTicket.Token,
+			accountID:  accountID,
+		}
 	}
 
-	// Calculate account ID (SHA256 hash of user ID)
-	hasher := sha256.New()
-	hasher.Write([]byte(resp.Data.User.ID))
-	hashBytes := hasher.Sum(nil)
-	accountID = hex.EncodeToString(hashBytes)
+	session := &Session{
+		Token:     resp.Data.AuthTicket.Token,
+		UserID:    resp.Data.User.ID,
+		AccountID: accountID,
+		Region:    c.region,
+	}
+	if resp.Data.AuthTicket.Expires > 0 {
+		session.ExpiresAt = time.Unix(resp.Data.AuthTicket.Expires, 0).UTC()
+	}
+	return session, nil
+}
 
-	return resp.Data.AuthTicket.Token, resp.Data.User.ID, accountID, nil
+// accountIDOf returns the account ID sent with authenticated requests: the
+// SHA-256 of the user ID.
+func accountIDOf(userID string) string {
+	hash := sha256.Sum256([]byte(userID))
+	return hex.EncodeToString(hash[:])
 }
@@ -17,6 +17,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"regexp"
 	"time"
 
 	"github.com/R4yL-dev/glcmd/internal/logger"
@@ -26,6 +27,9 @@ const (
 	// BaseURL is the LibreView API base URL (global endpoint)
 	BaseURL = "https://api.libreview.io"
 
+	// regionalBaseURL is the base URL of a regional endpoint (eu2, us, ap...)
+	regionalBaseURL = "https://api-%s.libreview.io"
+
 	// Default timeout for HTTP requests
 	DefaultTimeout = 30 * time.Second
 )
@@ -34,6 +38,7 @@ const (
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
+	region     string // Empty for the global endpoint
 	userAgent  string
 	version    string
 	product    string
@@ -58,6 +63,42 @@ func NewClient(httpClient *http.Client) *Client {
 	}
 }
 
+// regionPattern validates the region names sent by LibreView, which become
+// part of a host name.
+var regionPattern = regexp.MustCompile(`^[a-z0-9]{2,8}$`)
+
+// regionalBaseURLs are the regions whose endpoint does not follow
+// regionalBaseURL.
+var regionalBaseURLs = map[string]string{
+	"ru": "https://api.libreview.ru",
+}
+
+// Region returns the region of the endpoint in use, empty for the global one.
+func (c *Client) Region() string {
+	return c.region
+}
+
+// SetRegion switches the client to the endpoint of a region (empty = the
+// global endpoint), e.g. the region of a stored session. Login switches
+// automatically when LibreView redirects the account. Not safe to call
+// concurrently with requests.
+func (c *Client) SetRegion(region string) error {
+	if region == "" {
+		c.baseURL, c.region = BaseURL, ""
+		return nil
+	}
+	if !regionPattern.MatchString(region) {
+		return fmt.Errorf("invalid LibreView region %q", region)
+	}
+
+	baseURL, ok := regionalBaseURLs[region]
+	if !ok {
+		baseURL = fmt.Sprintf(regionalBaseURL, region)
+	}
+	c.baseURL, c.region = baseURL, region
+	return nil
+}
+
 // executeRequest performs the common HTTP request logic: builds the request,
 // sets headers, executes it, reads the response body, and handles status codes.
 //
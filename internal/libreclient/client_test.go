@@ -3,8 +3,10 @@ package libreclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -109,6 +111,171 @@ func TestAuthenticate_InvalidCredentials(t *testing.T) {
 	}
 }
 
+func TestLogin_Expiry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := AuthResponse{}
+		response.Data.User.ID = "test-user-123"
+		response.Data.AuthTicket.Token = "test-token-456"
+		response.Data.AuthTicket.Expires = 1234567890
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.baseURL = server.URL
+
+	session, err := client.Login(context.Background(), "test@example.com", "password123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Token != "test-token-456" || session.AccountID == "" {
+		t.Errorf("unexpected session: %+v", session)
+	}
+	if !session.ExpiresAt.Equal(time.Unix(1234567890, 0)) {
+		t.Errorf("expected the ticket expiry, got %v", session.ExpiresAt)
+	}
+}
+
+// hostRecorder sends all requests to a test server, recording the hosts they
+// were meant for
+type hostRecorder struct {
+	target string
+	hosts  []string
+}
+
+func (h *hostRecorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	h.hosts = append(h.hosts, r.URL.Host)
+	r.URL.Scheme = "http"
+	r.URL.Host = h.target
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestLogin_RegionRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := AuthResponse{}
+		if r.URL.Path == "/llu/auth/login" && r.Host == "api.libreview.io" {
+			response.Data.Redirect = true
+			response.Data.Region = "EU2"
+		} else {
+			response.Data.User.ID = "test-user-123"
+			response.Data.AuthTicket.Token = "test-token-456"
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	recorder := &hostRecorder{target: strings.TrimPrefix(server.URL, "http://")}
+	client := NewClient(&http.Client{Transport: recorder})
+
+	session, err := client.Login(context.Background(), "test@example.com", "password123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Token != "test-token-456" || session.Region != "eu2" {
+		t.Errorf("expected the regional session, got %+v", session)
+	}
+
+	// Following requests stay on the regional endpoint
+	client.GetConnections(context.Background(), session.Token, session.AccountID)
+	want := []string{"api.libreview.io", "api-eu2.libreview.io", "api-eu2.libreview.io"}
+	if strings.Join(recorder.hosts, " ") != strings.Join(want, " ") {
+		t.Errorf("expected requests to %v, got %v", want, recorder.hosts)
+	}
+}
+
+func TestSetRegion(t *testing.T) {
+	client := NewClient(nil)
+
+	if err := client.SetRegion("ru"); err != nil || client.baseURL != "https://api.libreview.ru" {
+		t.Errorf("expected the Russian endpoint, got %s (%v)", client.baseURL, err)
+	}
+	if err := client.SetRegion("evil.example.com/"); err == nil {
+		t.Error("expected error for an invalid region")
+	}
+	if err := client.SetRegion(""); err != nil || client.baseURL != BaseURL || client.Region() != "" {
+		t.Errorf("expected the global endpoint, got %s (%v)", client.baseURL, err)
+	}
+}
+
+func TestLogin_TermsSteps(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/llu/auth/login":
+			w.Write([]byte(`{"status":4,"data":{"step":{"type":"tou","componentName":"AcceptDocument"},"user":{"id":"test-user-123"},"authTicket":{"token":"tou-token"}}}`))
+		case "/auth/continue/tou":
+			if r.Header.Get("Authorization") != "Bearer tou-token" || r.Header.Get("account-id") != accountIDOf("test-user-123") {
+				t.Errorf("expected the ticket of the step, got %q", r.Header.Get("Authorization"))
+			}
+			w.Write([]byte(`{"status":4,"data":{"step":{"type":"pp","componentName":"AcceptDocument"},"user":{"id":"test-user-123"},"authTicket":{"token":"pp-token"}}}`))
+		case "/auth/continue/pp":
+			w.Write([]byte(`{"status":0,"data":{"user":{"id":"test-user-123"},"authTicket":{"token":"session-token","expires":1783000000}}}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.baseURL = server.URL
+
+	_, err := client.Login(context.Background(), "test@example.com", "password123")
+	var terms *TermsError
+	if !errors.As(err, &terms) || terms.Step != "tou" || !terms.Acceptable {
+		t.Fatalf("expected the terms of use step, got %v", err)
+	}
+	if err.Error() != "LibreView requires accepting the new terms of use" {
+		t.Errorf("unexpected message: %v", err)
+	}
+
+	// A second document may follow the first one
+	_, err = client.AcceptTerms(context.Background(), terms)
+	if !errors.As(err, &terms) || terms.Step != "pp" {
+		t.Fatalf("expected the privacy policy step, got %v", err)
+	}
+
+	session, err := client.AcceptTerms(context.Background(), terms)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Token != "session-token" || session.ExpiresAt.IsZero() {
+		t.Errorf("expected the session issued after acceptance, got %+v", session)
+	}
+
+	want := "/llu/auth/login /auth/continue/tou /auth/continue/pp"
+	if got := strings.Join(paths, " "); got != want {
+		t.Errorf("expected requests to %s, got %s", want, got)
+	}
+}
+
+func TestLogin_StepInErrorResponse(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"status":4,"data":{"step":{"type":"pwdreset","componentName":"ResetPassword"}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.baseURL = server.URL
+
+	_, err := client.Login(context.Background(), "test@example.com", "password123")
+	var terms *TermsError
+	if !errors.As(err, &terms) || terms.Step != "pwdreset" || terms.Acceptable {
+		t.Fatalf("expected a step only the app can complete, got %v", err)
+	}
+
+	// Nothing to accept: the step is not sent
+	if _, err := client.AcceptTerms(context.Background(), terms); !errors.As(err, &terms) {
+		t.Errorf("expected the step error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
 func TestGetConnections_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/llu/connections" {
@@ -121,25 +288,7 @@ func TestGetConnections_Success(t *testing.T) {
 		}
 
 		response := ConnectionsResponse{}
-		response.Data = append(response.Data, struct {
-			PatientID string `json:"patientId"`
-			GlucoseMeasurement struct {
-				ValueInMgPerDl   int     `json:"ValueInMgPerDl"`
-				Value            float64 `json:"Value"`
-				TrendArrow       int     `json:"TrendArrow"`
-				TrendMessage     string  `json:"TrendMessage"`
-				MeasurementColor int     `json:"MeasurementColor"`
-				GlucoseUnits     int     `json:"GlucoseUnits"`
-				FactoryTimestamp string  `json:"FactoryTimestamp"`
-				Timestamp        string  `json:"Timestamp"`
-				IsHigh           bool    `json:"isHigh"`
-				IsLow            bool    `json:"isLow"`
-			} `json:"glucoseMeasurement"`
-			Sensor     SensorData `json:"sensor"`
-			TargetHigh int        `json:"targetHigh"`
-			TargetLow  int        `json:"targetLow"`
-			Uom        int        `json:"uom"`
-		}{
+		response.Data = append(response.Data, Connection{
 			PatientID: "patient-123",
 		})
 		response.Data[0].GlucoseMeasurement.Value = 5.5
@@ -172,6 +321,85 @@ func TestGetConnections_Success(t *testing.T) {
 	}
 }
 
+func TestConnection_AlarmSettings(t *testing.T) {
+	payload := `{
+		"patientId": "patient-123",
+		"patientDevice": {"did": "device-1", "fixedLowAlarmValues": {"mgdl": 60, "mmoll": 3.3}},
+		"alarmRules": {
+			"c": true,
+			"h": {"on": false, "th": 250, "thmm": 13.9, "d": 1440},
+			"l": {"on": true, "th": 70, "thmm": 3.9, "d": 1440},
+			"f": {"th": 55, "thmm": 3.1, "d": 5},
+			"nd": {"i": 20}
+		}
+	}`
+
+	var conn Connection
+	if err := json.Unmarshal([]byte(payload), &conn); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	if conn.PatientDevice.FixedLowAlarmValues.MgDl != 60 || conn.PatientDevice.FixedLowAlarmValues.MmolL != 3.3 {
+		t.Errorf("unexpected urgent low level: %+v", conn.PatientDevice.FixedLowAlarmValues)
+	}
+	if conn.AlarmRules == nil {
+		t.Fatal("expected alarm rules")
+	}
+	if conn.AlarmRules.H.Enabled() {
+		t.Error("expected the high alarm to be off")
+	}
+	if !conn.AlarmRules.L.Enabled() || conn.AlarmRules.L.TH != 70 {
+		t.Errorf("unexpected low alarm: %+v", conn.AlarmRules.L)
+	}
+	// The urgent low alarm cannot be turned off and has no "on" field
+	if !conn.AlarmRules.F.Enabled() {
+		t.Error("expected the urgent low alarm to be on")
+	}
+	if conn.AlarmRules.ND.I != 20 {
+		t.Errorf("expected a 20 minute signal loss alarm, got %d", conn.AlarmRules.ND.I)
+	}
+
+	var bare Connection
+	if err := json.Unmarshal([]byte(`{"patientId": "patient-123"}`), &bare); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if bare.AlarmRules != nil {
+		t.Error("expected no alarm rules when not reported")
+	}
+}
+
+func TestConnection_LatestGlucose(t *testing.T) {
+	reading := func(factoryTimestamp string, value int) CurrentGlucose {
+		return CurrentGlucose{FactoryTimestamp: factoryTimestamp, ValueInMgPerDl: value}
+	}
+
+	tests := []struct {
+		name        string
+		measurement CurrentGlucose
+		item        *CurrentGlucose
+		wantValue   int
+		wantSource  string
+	}{
+		{"no item", reading("1/4/2026 11:59:30 AM", 112), nil, 112, SourceGlucoseMeasurement},
+		{"same reading", reading("1/4/2026 11:59:30 AM", 112), &CurrentGlucose{FactoryTimestamp: "1/4/2026 11:59:30 AM", ValueInMgPerDl: 112}, 112, SourceGlucoseMeasurement},
+		{"fresher item", reading("1/4/2026 11:54:30 AM", 112), &CurrentGlucose{FactoryTimestamp: "1/4/2026 11:59:30 AM", ValueInMgPerDl: 118}, 118, SourceGlucoseItem},
+		{"stale item", reading("1/4/2026 11:59:30 AM", 112), &CurrentGlucose{FactoryTimestamp: "1/4/2026 11:49:30 AM", ValueInMgPerDl: 104}, 112, SourceGlucoseMeasurement},
+		{"same time, other value", reading("1/4/2026 11:59:30 AM", 112), &CurrentGlucose{FactoryTimestamp: "1/4/2026 11:59:30 AM", ValueInMgPerDl: 115}, 112, SourceGlucoseMeasurement},
+		{"invalid item timestamp", reading("1/4/2026 11:59:30 AM", 112), &CurrentGlucose{FactoryTimestamp: "soon", ValueInMgPerDl: 118}, 112, SourceGlucoseMeasurement},
+		{"invalid measurement timestamp", reading("", 0), &CurrentGlucose{FactoryTimestamp: "1/4/2026 11:59:30 AM", ValueInMgPerDl: 118}, 118, SourceGlucoseItem},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := Connection{GlucoseMeasurement: tt.measurement, GlucoseItem: tt.item}
+			got, source := conn.LatestGlucose()
+			if got.ValueInMgPerDl != tt.wantValue || source != tt.wantSource {
+				t.Errorf("expected %d from %s, got %d from %s", tt.wantValue, tt.wantSource, got.ValueInMgPerDl, source)
+			}
+		})
+	}
+}
+
 func TestGetGraph_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		expectedPath := "/llu/connections/patient-123/graph"
@@ -229,6 +457,45 @@ func TestGetGraph_Success(t *testing.T) {
 	}
 }
 
+func TestGetLogbook_FiltersPeriod(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/llu/connections/patient-123/logbook" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("from") != "1767225600" || r.URL.Query().Get("to") != "1767312000" {
+			t.Errorf("unexpected period: %s", r.URL.RawQuery)
+		}
+
+		// The whole logbook, as an upstream ignoring the period returns it
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[
+			{"FactoryTimestamp":"12/31/2025 11:00:00 PM","Value":5.1},
+			{"FactoryTimestamp":"1/1/2026 12:00:00 AM","Value":5.2},
+			{"FactoryTimestamp":"1/1/2026 1:00:00 PM","Value":5.8},
+			{"FactoryTimestamp":"1/2/2026 1:00:00 AM","Value":6.4},
+			{"FactoryTimestamp":"","Value":7.0}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.baseURL = server.URL
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	result, err := client.GetLogbook(context.Background(), "test-token", "test-account", "patient-123", from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var values []float64
+	for _, entry := range result.Data {
+		values = append(values, entry.Value)
+	}
+	if len(values) != 3 || values[0] != 5.2 || values[1] != 5.8 || values[2] != 7.0 {
+		t.Errorf("expected the readings of the period and the unparsable one, got %v", values)
+	}
+}
+
 func TestContextCancellation(t *testing.T) {
 	// Create server with delay
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -54,3 +54,33 @@ type HTTPError struct {
 func (e *HTTPError) Error() string {
 	return fmt.Sprintf("HTTP error: %d", e.StatusCode)
 }
+
+// TermsError is returned when LibreView requires a step before issuing a
+// session: accepting new terms of use or privacy policy, which AcceptTerms
+// does, or another step only the LibreLinkUp app can complete.
+type TermsError struct {
+	Step       string // Step type: "tou" (terms of use), "pp" (privacy policy), ...
+	Acceptable bool   // The step is a document AcceptTerms can accept
+
+	token     string // Ticket of the step, only valid to complete it
+	accountID string
+}
+
+func (e *TermsError) Error() string {
+	if e.Acceptable {
+		return fmt.Sprintf("LibreView requires accepting the new %s", e.Document())
+	}
+	return fmt.Sprintf("LibreView requires completing the %q step in the LibreLinkUp app", e.Step)
+}
+
+// Document returns the name of the document to accept.
+func (e *TermsError) Document() string {
+	switch e.Step {
+	case "tou":
+		return "terms of use"
+	case "pp":
+		return "privacy policy"
+	default:
+		return e.Step
+	}
+}
@@ -0,0 +1,68 @@
+package libreclient
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/R4yL-dev/glcmd/internal/utils/timeparser"
+)
+
+// fixturesDir holds the LibreView payloads written by glcore gen-fixtures.
+// Every payload committed there is parsed by TestFixtures.
+var fixturesDir = filepath.Join("..", "..", "testdata", "fixtures", "libreview")
+
+func TestFixtures(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(fixturesDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no fixtures found in %s", fixturesDir)
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var timestamps []string
+			switch name := filepath.Base(path); {
+			case name == "connections.json":
+				var resp ConnectionsResponse
+				if err := json.Unmarshal(data, &resp); err != nil {
+					t.Fatalf("failed to decode: %v", err)
+				}
+				for _, c := range resp.Data {
+					if c.PatientID == "" {
+						t.Error("connection without a patient ID")
+					}
+					timestamps = append(timestamps, c.GlucoseMeasurement.FactoryTimestamp, c.GlucoseMeasurement.Timestamp)
+				}
+
+			case strings.HasPrefix(name, "graph-"):
+				var resp GraphResponse
+				if err := json.Unmarshal(data, &resp); err != nil {
+					t.Fatalf("failed to decode: %v", err)
+				}
+				timestamps = append(timestamps, resp.Data.Connection.GlucoseMeasurement.Timestamp)
+				for _, point := range resp.Data.GraphData {
+					timestamps = append(timestamps, point.FactoryTimestamp, point.Timestamp)
+				}
+
+			default:
+				t.Skip("unknown payload")
+			}
+
+			for _, ts := range timestamps {
+				if _, err := timeparser.ParseLibreViewTimestamp(ts); err != nil {
+					t.Errorf("failed to parse timestamp %q: %v", ts, err)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,11 @@ package libreclient
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/utils/timeparser"
 )
 
 // SensorData represents the sensor information from LibreView API.
@@ -15,27 +20,113 @@ type SensorData struct {
 	LJ bool   `json:"lj"` // Low journey (always false, not used)
 }
 
+// PatientDevice represents the LibreLink app settings from LibreView API.
+type PatientDevice struct {
+	DID               string `json:"did"`               // Device ID
+	DTID              int    `json:"dtid"`              // Device type ID
+	V                 string `json:"v"`                 // LibreLink app version
+	LL                int    `json:"ll"`                // Low glucose alarm level (mg/dL)
+	HL                int    `json:"hl"`                // High glucose alarm level (mg/dL)
+	U                 int64  `json:"u"`                 // Last settings update (Unix)
+	FixedLowThreshold int    `json:"fixedLowThreshold"` // Fixed low alarm threshold (mg/dL)
+	Alarms            bool   `json:"alarms"`            // Alarms enabled
+	L                 bool   `json:"l"`                 // Limits enabled
+
+	FixedLowAlarmValues FixedLowAlarmValues `json:"fixedLowAlarmValues"` // Urgent low alarm level
+}
+
+// FixedLowAlarmValues is the urgent low alarm level from LibreView API, in both units.
+type FixedLowAlarmValues struct {
+	MgDl  int     `json:"mgdl"`
+	MmolL float64 `json:"mmoll"`
+}
+
+// AlarmRule is a glucose alarm of the alarm rules from LibreView API.
+type AlarmRule struct {
+	On   *bool   `json:"on"`   // Alarm on (absent on alarms that cannot be turned off)
+	TH   int     `json:"th"`   // Threshold (mg/dL)
+	THMM float64 `json:"thmm"` // Threshold (mmol/L)
+	D    int     `json:"d"`    // Minutes before the alarm sounds again
+}
+
+// Enabled reports whether the alarm is on.
+func (r AlarmRule) Enabled() bool {
+	return r.On == nil || *r.On
+}
+
+// AlarmRules represents the LibreLinkUp alarm settings from LibreView API.
+type AlarmRules struct {
+	C  bool      `json:"c"` // Alarms configured by the follower
+	H  AlarmRule `json:"h"` // High glucose alarm
+	L  AlarmRule `json:"l"` // Low glucose alarm
+	F  AlarmRule `json:"f"` // Fixed (urgent) low alarm
+	ND struct {
+		I int `json:"i"` // Minutes without data before the signal loss alarm
+	} `json:"nd"`
+}
+
+// CurrentGlucose is the latest reading of a connection from LibreView API.
+type CurrentGlucose struct {
+	ValueInMgPerDl   int     `json:"ValueInMgPerDl"`
+	Value            float64 `json:"Value"`
+	TrendArrow       int     `json:"TrendArrow"`
+	TrendMessage     string  `json:"TrendMessage"`
+	MeasurementColor int     `json:"MeasurementColor"`
+	GlucoseUnits     int     `json:"GlucoseUnits"`
+	FactoryTimestamp string  `json:"FactoryTimestamp"`
+	Timestamp        string  `json:"Timestamp"`
+	IsHigh           bool    `json:"isHigh"`
+	IsLow            bool    `json:"isLow"`
+}
+
+// Objects of a connection holding its latest reading, as named in the payload
+const (
+	SourceGlucoseMeasurement = "glucoseMeasurement"
+	SourceGlucoseItem        = "glucoseItem"
+)
+
+// Connection is a patient followed by the account, with its latest reading.
+// The reading is reported twice, in glucoseMeasurement and glucoseItem, which
+// can differ during signal loss: use LatestGlucose.
+type Connection struct {
+	PatientID          string          `json:"patientId"`
+	FirstName          string          `json:"firstName"`
+	LastName           string          `json:"lastName"`
+	Country            string          `json:"country"`
+	GlucoseMeasurement CurrentGlucose  `json:"glucoseMeasurement"`
+	GlucoseItem        *CurrentGlucose `json:"glucoseItem"` // Nil when not reported
+	Sensor             SensorData      `json:"sensor"`
+	PatientDevice      PatientDevice   `json:"patientDevice"`
+	AlarmRules         *AlarmRules     `json:"alarmRules"` // Nil when not reported
+	TargetHigh         int             `json:"targetHigh"`
+	TargetLow          int             `json:"targetLow"`
+	Uom                int             `json:"uom"`
+}
+
+// LatestGlucose returns the fresher of glucoseMeasurement and glucoseItem, by
+// factory timestamp, and the name of the object it comes from. Ties keep
+// glucoseMeasurement and a timestamp that cannot be parsed loses, so the
+// choice only depends on the payload.
+func (c *Connection) LatestGlucose() (*CurrentGlucose, string) {
+	if c.GlucoseItem == nil {
+		return &c.GlucoseMeasurement, SourceGlucoseMeasurement
+	}
+
+	item, err := timeparser.ParseLibreViewTimestamp(c.GlucoseItem.FactoryTimestamp)
+	if err != nil {
+		return &c.GlucoseMeasurement, SourceGlucoseMeasurement
+	}
+	measurement, err := timeparser.ParseLibreViewTimestamp(c.GlucoseMeasurement.FactoryTimestamp)
+	if err != nil || item.After(measurement) {
+		return c.GlucoseItem, SourceGlucoseItem
+	}
+	return &c.GlucoseMeasurement, SourceGlucoseMeasurement
+}
+
 // ConnectionsResponse represents the response from /llu/connections endpoint.
+// An account following several patients has one connection per patient.
 type ConnectionsResponse struct {
-	Data []struct {
-		PatientID string `json:"patientId"`
-		GlucoseMeasurement struct {
-			ValueInMgPerDl   int     `json:"ValueInMgPerDl"`
-			Value            float64 `json:"Value"`
-			TrendArrow       int     `json:"TrendArrow"`
-			TrendMessage     string  `json:"TrendMessage"`
-			MeasurementColor int     `json:"MeasurementColor"`
-			GlucoseUnits     int     `json:"GlucoseUnits"`
-			FactoryTimestamp string  `json:"FactoryTimestamp"`
-			Timestamp        string  `json:"Timestamp"`
-			IsHigh           bool    `json:"isHigh"`
-			IsLow            bool    `json:"isLow"`
-		} `json:"glucoseMeasurement"`
-		Sensor     SensorData `json:"sensor"`
-		TargetHigh int        `json:"targetHigh"`
-		TargetLow  int        `json:"targetLow"`
-		Uom        int        `json:"uom"`
-	} `json:"data"`
+	Data []Connection `json:"data"`
 }
 
 // GraphResponse represents the response from /llu/connections/{patientId}/graph endpoint.
@@ -68,6 +159,26 @@ type GraphResponse struct {
 	} `json:"data"`
 }
 
+// LogbookEntry is a reading of the logbook of a patient.
+type LogbookEntry struct {
+	FactoryTimestamp string  `json:"FactoryTimestamp"`
+	Timestamp        string  `json:"Timestamp"`
+	ValueInMgPerDl   int     `json:"ValueInMgPerDl"`
+	Value            float64 `json:"Value"`
+	TrendArrow       *int    `json:"TrendArrow"` // Absent on some entries
+	TrendMessage     *string `json:"TrendMessage"`
+	MeasurementColor int     `json:"MeasurementColor"`
+	GlucoseUnits     int     `json:"GlucoseUnits"`
+	IsHigh           bool    `json:"isHigh"`
+	IsLow            bool    `json:"isLow"`
+	Type             int     `json:"type"`
+}
+
+// LogbookResponse represents the response from /llu/connections/{patientId}/logbook endpoint.
+type LogbookResponse struct {
+	Data []LogbookEntry `json:"data"`
+}
+
 // GetConnections retrieves the current glucose measurement and patient information.
 //
 // This endpoint is used for periodic updates (every 2 minutes).
@@ -91,8 +202,48 @@ func (c *Client) GetGraph(ctx context.Context, token, accountID, patientID strin
 	return &result, nil
 }
 
+// GetLogbook retrieves the readings of a patient between from and to.
+//
+// Unlike /graph, the logbook reaches beyond the last 12 hours: it is used to
+// backfill older history, one period at a time. Periods older than the
+// history LibreView keeps are empty.
+//
+// The from and to query parameters (Unix seconds) are not documented by
+// LibreView: they follow the requests of the LibreLinkUp app. An upstream
+// ignoring them returns its whole logbook, so the entries are also filtered
+// on their factory timestamp, within [from, to]. Entries whose timestamp
+// cannot be parsed are kept for the caller to report.
+func (c *Client) GetLogbook(ctx context.Context, token, accountID, patientID string, from, to time.Time) (*LogbookResponse, error) {
+	query := url.Values{}
+	query.Set("from", strconv.FormatInt(from.Unix(), 10))
+	query.Set("to", strconv.FormatInt(to.Unix(), 10))
+	path := fmt.Sprintf("/llu/connections/%s/logbook?%s", patientID, query.Encode())
+
+	var result LogbookResponse
+	if err := c.doRequest(ctx, "GET", path, nil, &result, token, accountID); err != nil {
+		return nil, err
+	}
+
+	entries := result.Data[:0]
+	for _, entry := range result.Data {
+		ts, err := timeparser.ParseLibreViewTimestamp(entry.FactoryTimestamp)
+		if err == nil && (ts.Before(from) || ts.After(to)) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	result.Data = entries
+	return &result, nil
+}
+
 // GetConnectionsRaw returns the raw JSON response from /llu/connections.
 // This is useful for debugging and inspecting the API response structure.
 func (c *Client) GetConnectionsRaw(ctx context.Context, token, accountID string) ([]byte, error) {
 	return c.doRequestRaw(ctx, "GET", "/llu/connections", nil, token, accountID)
 }
+
+// GetGraphRaw returns the raw JSON response from /llu/connections/{patientId}/graph.
+func (c *Client) GetGraphRaw(ctx context.Context, token, accountID, patientID string) ([]byte, error) {
+	path := fmt.Sprintf("/llu/connections/%s/graph", patientID)
+	return c.doRequestRaw(ctx, "GET", path, nil, token, accountID)
+}
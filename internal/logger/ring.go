@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultRingSize is the number of log records kept in memory by default.
+const DefaultRingSize = 2000
+
+// Entry is a log record kept in memory.
+type Entry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+
+	level slog.Level
+}
+
+// Ring keeps the most recent log records in memory, so they can be served
+// remotely without access to the host.
+type Ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int  // Index of the slot written next
+	full    bool // All slots have been written at least once
+}
+
+// NewRing creates a Ring keeping the last size records.
+func NewRing(size int) *Ring {
+	return &Ring{entries: make([]Entry, size)}
+}
+
+// add stores an entry, overwriting the oldest one when full.
+func (r *Ring) add(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Since returns the records logged at or after t with at least the given level, oldest first.
+func (r *Ring) Since(t time.Time, level slog.Level) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ordered []Entry
+	if r.full {
+		ordered = append(ordered, r.entries[r.next:]...)
+	}
+	ordered = append(ordered, r.entries[:r.next]...)
+
+	result := []Entry{}
+	for _, e := range ordered {
+		if e.level >= level && !e.Time.Before(t) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// RingHandler is a slog.Handler passing records to another handler and
+// keeping a copy in a Ring. Records are only kept when the next handler is
+// enabled for their level, so the ring holds what the logs show.
+type RingHandler struct {
+	next   slog.Handler
+	ring   *Ring
+	attrs  []slog.Attr // Attributes added with WithAttrs, keys already prefixed by groups
+	prefix string      // Group prefix of the attributes of the record
+}
+
+// NewRingHandler creates a RingHandler writing to next and ring.
+func NewRingHandler(next slog.Handler, ring *Ring) *RingHandler {
+	return &RingHandler{next: next, ring: ring}
+}
+
+// Enabled reports whether the next handler handles records at level.
+func (h *RingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle stores the record in the ring and passes it to the next handler.
+// UUIDs in string values are masked, as in logged URL paths.
+func (h *RingHandler) Handle(ctx context.Context, record slog.Record) error {
+	entry := Entry{
+		Time:    record.Time,
+		Level:   record.Level.String(),
+		Message: RedactPath(record.Message),
+		level:   record.Level,
+	}
+
+	if len(h.attrs) > 0 || record.NumAttrs() > 0 {
+		entry.Attrs = make(map[string]any, len(h.attrs)+record.NumAttrs())
+		for _, a := range h.attrs {
+			addAttr(entry.Attrs, "", a)
+		}
+		record.Attrs(func(a slog.Attr) bool {
+			addAttr(entry.Attrs, h.prefix, a)
+			return true
+		})
+	}
+
+	h.ring.add(entry)
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler adding attrs to every record.
+func (h *RingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithAttrs(attrs)
+	clone.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, a := range attrs {
+		clone.attrs = append(clone.attrs, slog.Attr{Key: h.prefix + a.Key, Value: a.Value})
+	}
+	return &clone
+}
+
+// WithGroup returns a handler nesting the following attributes under name.
+func (h *RingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// addAttr flattens an attribute into attrs, with group keys joined by dots.
+func addAttr(attrs map[string]any, prefix string, a slog.Attr) {
+	value := a.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+		for _, ga := range value.Group() {
+			addAttr(attrs, groupPrefix, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+
+	key := prefix + a.Key
+	switch value.Kind() {
+	case slog.KindString:
+		attrs[key] = RedactPath(value.String())
+	case slog.KindDuration:
+		attrs[key] = value.Duration().String()
+	case slog.KindTime:
+		attrs[key] = value.Time()
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			attrs[key] = RedactPath(err.Error())
+		} else {
+			attrs[key] = RedactPath(value.String())
+		}
+	default:
+		attrs[key] = value.Any()
+	}
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRingHandler(t *testing.T) {
+	var out bytes.Buffer
+	ring := NewRing(3)
+	log := slog.New(NewRingHandler(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo}), ring))
+
+	log.Debug("not enabled")
+	log.Info("fetched", "duration", time.Second)
+	log.With("component", "api").WithGroup("req").Warn("slow request",
+		"path", "/llu/connections/0f8e2c6a-1b2d-4e5f-8a9b-0c1d2e3f4a5b/graph",
+		"error", errors.New("timeout"))
+
+	if !strings.Contains(out.String(), "slow request") {
+		t.Errorf("expected records to reach the next handler, got %q", out.String())
+	}
+
+	entries := ring.Since(time.Time{}, slog.LevelDebug)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries (debug disabled), got %d", len(entries))
+	}
+	if entries[0].Message != "fetched" || entries[0].Attrs["duration"] != "1s" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	warn := entries[1]
+	if warn.Level != "WARN" || warn.Attrs["component"] != "api" || warn.Attrs["req.error"] != "timeout" {
+		t.Errorf("unexpected second entry: %+v", warn)
+	}
+	if path := warn.Attrs["req.path"]; path != "/llu/connections/***/graph" {
+		t.Errorf("expected UUID redacted from path, got %v", path)
+	}
+
+	if entries := ring.Since(time.Time{}, slog.LevelWarn); len(entries) != 1 {
+		t.Errorf("expected 1 entry at warn level, got %d", len(entries))
+	}
+}
+
+func TestRing_Overwrite(t *testing.T) {
+	ring := NewRing(2)
+	log := slog.New(NewRingHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), ring))
+
+	start := time.Now()
+	for _, msg := range []string{"first", "second", "third"} {
+		log.Info(msg)
+	}
+
+	entries := ring.Since(start, slog.LevelInfo)
+	if len(entries) != 2 || entries[0].Message != "second" || entries[1].Message != "third" {
+		t.Errorf("expected the 2 most recent entries oldest first, got %+v", entries)
+	}
+	if entries := ring.Since(time.Now().Add(time.Minute), slog.LevelInfo); len(entries) != 0 {
+		t.Errorf("expected no entries in the future, got %d", len(entries))
+	}
+}
@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default sampling: the first 3 identical warnings or errors per 10 minutes.
+const (
+	DefaultSamplingFirst  = 3
+	DefaultSamplingPeriod = 10 * time.Minute
+)
+
+// SamplingOptions configures a SamplingHandler.
+// First is the number of identical records passed per Period; 0 disables sampling.
+type SamplingOptions struct {
+	First  int
+	Period time.Duration
+}
+
+// ParseSampling parses a sampling setting of the form "first/period", e.g.
+// "3/10m". "off" and "0" disable sampling; empty returns the defaults.
+func ParseSampling(s string) (SamplingOptions, error) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "":
+		return SamplingOptions{First: DefaultSamplingFirst, Period: DefaultSamplingPeriod}, nil
+	case "off", "0":
+		return SamplingOptions{}, nil
+	}
+
+	firstStr, periodStr, ok := strings.Cut(s, "/")
+	if !ok {
+		return SamplingOptions{}, fmt.Errorf("invalid sampling %q: expected first/period (e.g. 3/10m)", s)
+	}
+	first, err := strconv.Atoi(firstStr)
+	if err != nil || first < 1 {
+		return SamplingOptions{}, fmt.Errorf("invalid sampling count %q: must be a positive number", firstStr)
+	}
+	period, err := time.ParseDuration(periodStr)
+	if err != nil || period <= 0 {
+		return SamplingOptions{}, fmt.Errorf("invalid sampling period %q: must be a positive duration", periodStr)
+	}
+	return SamplingOptions{First: first, Period: period}, nil
+}
+
+// samplingKey identifies identical records: same level, message and error.
+type samplingKey struct {
+	level   slog.Level
+	message string
+	err     string
+}
+
+// samplingWindow counts the records of a key since the start of its window.
+type samplingWindow struct {
+	start      time.Time
+	count      int
+	suppressed int
+	repeating  bool // The previous window suppressed records
+}
+
+// samplingState is shared by a handler and the handlers derived from it.
+type samplingState struct {
+	mu      sync.Mutex
+	windows map[samplingKey]*samplingWindow
+}
+
+// SamplingHandler is a slog.Handler dropping repetitive warnings and errors,
+// so that hours of identical fetch errors during an outage do not fill the
+// journal. The first records of each Period pass; while the same record keeps
+// repeating, only one per Period passes, with the number dropped in the
+// previous period as the "suppressed" attribute. Info and debug records
+// always pass.
+type SamplingHandler struct {
+	next  slog.Handler
+	opts  SamplingOptions
+	state *samplingState
+	now   func() time.Time
+}
+
+// NewSamplingHandler creates a SamplingHandler writing to next.
+func NewSamplingHandler(next slog.Handler, opts SamplingOptions) *SamplingHandler {
+	return &SamplingHandler{
+		next:  next,
+		opts:  opts,
+		state: &samplingState{windows: make(map[samplingKey]*samplingWindow)},
+		now:   time.Now,
+	}
+}
+
+// Enabled reports whether the next handler handles records at level.
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle passes the record to the next handler unless it is sampled out.
+func (h *SamplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if h.opts.First <= 0 || record.Level < slog.LevelWarn {
+		return h.next.Handle(ctx, record)
+	}
+
+	key := samplingKey{level: record.Level, message: record.Message}
+	record.Attrs(func(a slog.Attr) bool {
+		if a.Key == "error" {
+			key.err = a.Value.Resolve().String()
+			return false
+		}
+		return true
+	})
+
+	pass, suppressed := h.sample(key)
+	if !pass {
+		return nil
+	}
+	if suppressed > 0 {
+		record = record.Clone()
+		record.AddAttrs(slog.Int("suppressed", suppressed))
+	}
+	return h.next.Handle(ctx, record)
+}
+
+// sample returns whether a record of key passes, and the number of records
+// suppressed in the previous window when it opens a new one.
+func (h *SamplingHandler) sample(key samplingKey) (bool, int) {
+	now := h.now()
+
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+
+	w := h.state.windows[key]
+	if w == nil || now.Sub(w.start) >= h.opts.Period {
+		h.sweep(now)
+		next := &samplingWindow{start: now, count: 1}
+		suppressed := 0
+		// Keep summarizing while the record repeats without a quiet period
+		if w != nil && w.suppressed > 0 && now.Sub(w.start) < 2*h.opts.Period {
+			next.repeating = true
+			suppressed = w.suppressed
+		}
+		h.state.windows[key] = next
+		return true, suppressed
+	}
+
+	w.count++
+	limit := h.opts.First
+	if w.repeating {
+		limit = 1
+	}
+	if w.count <= limit {
+		return true, 0
+	}
+	w.suppressed++
+	return false, 0
+}
+
+// sweep forgets the keys not seen for two periods. Must be called with the lock held.
+func (h *SamplingHandler) sweep(now time.Time) {
+	for key, w := range h.state.windows {
+		if now.Sub(w.start) >= 2*h.opts.Period {
+			delete(h.state.windows, key)
+		}
+	}
+}
+
+// WithAttrs returns a handler adding attrs to every record, sampled with this one.
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithAttrs(attrs)
+	return &clone
+}
+
+// WithGroup returns a handler nesting the following attributes under name,
+// sampled with this one.
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	return &clone
+}
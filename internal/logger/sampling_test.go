@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSamplingHandler(slog.NewTextHandler(&buf, nil), SamplingOptions{First: 2, Period: 10 * time.Minute})
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+	log := slog.New(h)
+
+	fetchErr := errors.New("server error (status 502)")
+	fetchFailed := func(n int) {
+		for i := 0; i < n; i++ {
+			log.Error("fetch failed", "error", fetchErr, "attempt", i)
+			now = now.Add(time.Minute)
+		}
+	}
+	lines := func() []string {
+		out := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		buf.Reset()
+		return out
+	}
+
+	// First window: the first 2 of 10 pass
+	fetchFailed(10)
+	if got := lines(); len(got) != 2 {
+		t.Fatalf("expected 2 records in the first window, got %d: %v", len(got), got)
+	}
+
+	// Repeating: one record per window with the suppressed count
+	fetchFailed(10)
+	got := lines()
+	if len(got) != 1 || !strings.Contains(got[0], "suppressed=8") {
+		t.Fatalf("expected one summary with suppressed=8, got %v", got)
+	}
+	fetchFailed(10)
+	got = lines()
+	if len(got) != 1 || !strings.Contains(got[0], "suppressed=9") {
+		t.Fatalf("expected one summary with suppressed=9, got %v", got)
+	}
+
+	// Different errors and info records are not grouped
+	log.Error("fetch failed", "error", "network error")
+	log.Info("measurement fetched")
+	log.Info("measurement fetched")
+	log.Info("measurement fetched")
+	if got := lines(); len(got) != 4 {
+		t.Errorf("expected 4 records, got %d: %v", len(got), got)
+	}
+
+	// After a quiet period, the first records pass again without summary
+	now = now.Add(time.Hour)
+	fetchFailed(3)
+	got = lines()
+	if len(got) != 2 || strings.Contains(got[0], "suppressed") {
+		t.Errorf("expected 2 records without summary after a quiet period, got %v", got)
+	}
+}
+
+func TestSamplingHandler_Disabled(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewSamplingHandler(slog.NewTextHandler(&buf, nil), SamplingOptions{}))
+	for i := 0; i < 5; i++ {
+		log.Warn("same warning")
+	}
+	if n := strings.Count(buf.String(), "same warning"); n != 5 {
+		t.Errorf("expected 5 records with sampling disabled, got %d", n)
+	}
+}
+
+func TestParseSampling(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    SamplingOptions
+		wantErr bool
+	}{
+		{"", SamplingOptions{First: DefaultSamplingFirst, Period: DefaultSamplingPeriod}, false},
+		{"off", SamplingOptions{}, false},
+		{"0", SamplingOptions{}, false},
+		{"5/1h", SamplingOptions{First: 5, Period: time.Hour}, false},
+		{"5", SamplingOptions{}, true},
+		{"0/1m", SamplingOptions{}, true},
+		{"3/soon", SamplingOptions{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSampling(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSampling(%q): unexpected error %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSampling(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
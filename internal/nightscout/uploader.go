@@ -0,0 +1,248 @@
+// Package nightscout uploads the stored measurements to a Nightscout instance.
+//
+// This lets glcore replace a separate LibreLinkUp bridge for users who already
+// run Nightscout. After each successful fetch, the measurements stored since
+// the newest entry known to Nightscout are posted to its entries API. That
+// entry is read back from Nightscout at startup, so measurements stored while
+// Nightscout was unreachable or glcore was stopped are uploaded later.
+package nightscout
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+const (
+	// requestTimeout bounds a single Nightscout request.
+	requestTimeout = 30 * time.Second
+	// backfillWindow limits the first upload to an empty (or long outdated) Nightscout.
+	backfillWindow = 7 * 24 * time.Hour
+	// batchSize is the number of entries posted per request.
+	batchSize = 500
+	// device identifies the uploader in Nightscout entries.
+	device = "glcmd"
+)
+
+// MeasurementSource reads the stored measurements.
+type MeasurementSource interface {
+	GetMeasurementsByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error)
+}
+
+// Entry is a Nightscout sensor glucose value entry.
+type Entry struct {
+	Type       string `json:"type"`
+	SGV        int    `json:"sgv"`                 // mg/dL
+	Date       int64  `json:"date"`                // Unix milliseconds
+	DateString string `json:"dateString"`          // RFC 3339
+	Direction  string `json:"direction,omitempty"` // Omitted for historical readings
+	Device     string `json:"device"`
+}
+
+// Uploader pushes new measurements to Nightscout.
+type Uploader struct {
+	baseURL    string
+	secretHash string
+	source     MeasurementSource
+	client     *http.Client
+	logger     *slog.Logger
+	pending    chan struct{}
+	now        func() time.Time
+
+	// cursor is the timestamp of the newest entry known to Nightscout
+	// (zero = not read from Nightscout yet). Only used by Run.
+	cursor time.Time
+}
+
+// NewUploader creates an Uploader posting to the Nightscout instance at baseURL.
+func NewUploader(baseURL, apiSecret string, source MeasurementSource, logger *slog.Logger) *Uploader {
+	// Nightscout expects the SHA-1 of the API secret, never the secret itself
+	hash := sha1.Sum([]byte(apiSecret))
+	return &Uploader{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		secretHash: hex.EncodeToString(hash[:]),
+		source:     source,
+		client:     &http.Client{Timeout: requestTimeout},
+		logger:     logger,
+		pending:    make(chan struct{}, 1),
+		now:        time.Now,
+	}
+}
+
+// Notify requests an upload without blocking the caller.
+// Requests made while an upload is pending are coalesced into it.
+func (u *Uploader) Notify() {
+	select {
+	case u.pending <- struct{}{}:
+	default:
+	}
+}
+
+// Run uploads the new measurements at startup and after each Notify until
+// ctx is cancelled. Failed uploads are logged and retried on the next request.
+func (u *Uploader) Run(ctx context.Context) {
+	u.Notify()
+	for {
+		select {
+		case <-u.pending:
+			uploaded, err := u.upload(ctx)
+			if err != nil {
+				u.logger.Warn("nightscout upload failed", "error", err, "uploaded", uploaded)
+				continue
+			}
+			if uploaded > 0 {
+				u.logger.Debug("measurements uploaded to nightscout", "count", uploaded)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// upload posts the measurements stored after the cursor, oldest first, and
+// returns how many were uploaded.
+func (u *Uploader) upload(ctx context.Context) (int, error) {
+	now := u.now()
+	if u.cursor.IsZero() {
+		latest, err := u.latestEntry(ctx)
+		if err != nil {
+			return 0, err
+		}
+		u.cursor = latest
+		if oldest := now.Add(-backfillWindow); u.cursor.Before(oldest) {
+			u.cursor = oldest
+		}
+	}
+
+	// Nightscout dates have millisecond precision
+	measurements, err := u.source.GetMeasurementsByTimeRange(ctx, u.cursor.Add(time.Millisecond), now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read measurements: %w", err)
+	}
+	slices.SortFunc(measurements, func(a, b *domain.GlucoseMeasurement) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	uploaded := 0
+	for batch := range slices.Chunk(measurements, batchSize) {
+		entries := make([]Entry, 0, len(batch))
+		for _, m := range batch {
+			entries = append(entries, NewEntry(m))
+		}
+		if err := u.post(ctx, entries); err != nil {
+			return uploaded, err
+		}
+		u.cursor = batch[len(batch)-1].Timestamp
+		uploaded += len(batch)
+	}
+
+	return uploaded, nil
+}
+
+// latestEntry returns the date of the newest glucose entry in Nightscout
+// (zero if there is none).
+func (u *Uploader) latestEntry(ctx context.Context) (time.Time, error) {
+	resp, err := u.do(ctx, http.MethodGet, "/api/v1/entries/sgv.json?count=1", nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	var entries []Entry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode entries: %w", err)
+	}
+	if len(entries) == 0 {
+		return time.Time{}, nil
+	}
+	return time.UnixMilli(entries[0].Date).UTC(), nil
+}
+
+// post sends entries to the Nightscout entries API.
+func (u *Uploader) post(ctx context.Context, entries []Entry) error {
+	body, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode entries: %w", err)
+	}
+
+	resp, err := u.do(ctx, http.MethodPost, "/api/v1/entries", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends an authenticated request and checks the response status.
+// The URL is not logged: it may identify the user's instance.
+func (u *Uploader) do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("api-secret", u.secretHash)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		resp.Body.Close()
+		return nil, fmt.Errorf("API secret rejected by nightscout")
+	case resp.StatusCode >= http.StatusBadRequest:
+		resp.Body.Close()
+		return nil, fmt.Errorf("nightscout returned status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// NewEntry converts a measurement to a Nightscout entry.
+func NewEntry(m *domain.GlucoseMeasurement) Entry {
+	return Entry{
+		Type:       "sgv",
+		SGV:        m.ValueInMgPerDl,
+		Date:       m.Timestamp.UnixMilli(),
+		DateString: m.Timestamp.UTC().Format(time.RFC3339),
+		Direction:  direction(m.TrendArrow),
+		Device:     device,
+	}
+}
+
+// direction maps a LibreLinkUp trend arrow to a Nightscout direction.
+// Libre reports five arrows, without the double arrows of Nightscout.
+func direction(trendArrow *int) string {
+	if trendArrow == nil {
+		return ""
+	}
+	switch *trendArrow {
+	case 1:
+		return "SingleDown"
+	case 2:
+		return "FortyFiveDown"
+	case 3:
+		return "Flat"
+	case 4:
+		return "FortyFiveUp"
+	case 5:
+		return "SingleUp"
+	default:
+		return "NOT COMPUTABLE"
+	}
+}
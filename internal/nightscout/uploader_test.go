@@ -0,0 +1,177 @@
+package nightscout
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// fakeSource returns the measurements of a time range, newest first like the repository.
+type fakeSource struct {
+	measurements []*domain.GlucoseMeasurement
+}
+
+func (f *fakeSource) GetMeasurementsByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error) {
+	var result []*domain.GlucoseMeasurement
+	for i := len(f.measurements) - 1; i >= 0; i-- {
+		m := f.measurements[i]
+		if !m.Timestamp.Before(start) && !m.Timestamp.After(end) {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// fakeNightscout stores the posted entries and serves the newest one.
+type fakeNightscout struct {
+	mu      sync.Mutex
+	secret  string
+	entries []Entry
+	fail    bool
+}
+
+func (f *fakeNightscout) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	hash := sha1.Sum([]byte(f.secret))
+	if r.Header.Get("api-secret") != hex.EncodeToString(hash[:]) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if f.fail {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/entries/sgv.json":
+		var latest []Entry
+		for _, e := range f.entries {
+			if len(latest) == 0 || e.Date > latest[0].Date {
+				latest = []Entry{e}
+			}
+		}
+		json.NewEncoder(w).Encode(append([]Entry{}, latest...))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/entries":
+		var entries []Entry
+		if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.entries = append(f.entries, entries...)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeNightscout) setFail(fail bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fail = fail
+}
+
+func measurement(ts time.Time, mgdl int, trend *int) *domain.GlucoseMeasurement {
+	return &domain.GlucoseMeasurement{Timestamp: ts, ValueInMgPerDl: mgdl, Value: float64(mgdl) / 18, TrendArrow: trend}
+}
+
+func TestUploader_Upload(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	flat := 3
+	ns := &fakeNightscout{
+		secret: "averylongsecret",
+		// Nightscout already has the reading of 11:50 (e.g. from a previous bridge)
+		entries: []Entry{NewEntry(measurement(now.Add(-10*time.Minute), 110, &flat))},
+	}
+	server := httptest.NewServer(ns)
+	defer server.Close()
+
+	source := &fakeSource{measurements: []*domain.GlucoseMeasurement{
+		measurement(now.Add(-8*24*time.Hour), 90, nil), // Older than the backfill window
+		measurement(now.Add(-10*time.Minute), 110, &flat),
+		measurement(now.Add(-5*time.Minute), 115, nil),
+		measurement(now.Add(-1*time.Minute), 120, &flat),
+	}}
+
+	uploader := NewUploader(server.URL+"/", "averylongsecret", source, slog.Default())
+	uploader.now = func() time.Time { return now }
+
+	uploaded, err := uploader.upload(context.Background())
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if uploaded != 2 || len(ns.entries) != 3 {
+		t.Fatalf("expected the 2 readings after 11:50 uploaded, got %d (%d entries)", uploaded, len(ns.entries))
+	}
+	if e := ns.entries[1]; e.SGV != 115 || e.Direction != "" || e.Type != "sgv" || e.Device != device {
+		t.Errorf("unexpected historical entry: %+v", e)
+	}
+	if e := ns.entries[2]; e.SGV != 120 || e.Direction != "Flat" || e.Date != now.Add(-time.Minute).UnixMilli() {
+		t.Errorf("unexpected current entry: %+v", e)
+	}
+
+	// Nothing new: nothing posted
+	if uploaded, err := uploader.upload(context.Background()); err != nil || uploaded != 0 {
+		t.Errorf("expected nothing to upload, got %d (%v)", uploaded, err)
+	}
+
+	// Readings stored while Nightscout is down are uploaded once it is back
+	ns.setFail(true)
+	source.measurements = append(source.measurements, measurement(now, 125, &flat))
+	if _, err := uploader.upload(context.Background()); err == nil {
+		t.Fatal("expected an error while nightscout is down")
+	}
+	ns.setFail(false)
+	if uploaded, err := uploader.upload(context.Background()); err != nil || uploaded != 1 {
+		t.Errorf("expected the pending reading uploaded, got %d (%v)", uploaded, err)
+	}
+}
+
+func TestUploader_EmptyNightscout(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	ns := &fakeNightscout{secret: "averylongsecret"}
+	server := httptest.NewServer(ns)
+	defer server.Close()
+
+	source := &fakeSource{}
+	for i := 0; i < 2*batchSize; i++ {
+		source.measurements = append(source.measurements, measurement(now.Add(-8*24*time.Hour+time.Duration(i)*15*time.Minute), 100, nil))
+	}
+
+	uploader := NewUploader(server.URL, "averylongsecret", source, slog.Default())
+	uploader.now = func() time.Time { return now }
+
+	// Only the readings of the backfill window, in batches
+	uploaded, err := uploader.upload(context.Background())
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	oldest := now.Add(-backfillWindow).UnixMilli()
+	if uploaded == 0 || uploaded != len(ns.entries) || uploaded >= 2*batchSize {
+		t.Fatalf("expected the readings of the backfill window, got %d (%d entries)", uploaded, len(ns.entries))
+	}
+	for _, e := range ns.entries {
+		if e.Date < oldest {
+			t.Fatalf("entry older than the backfill window uploaded: %+v", e)
+		}
+	}
+}
+
+func TestUploader_RejectedSecret(t *testing.T) {
+	server := httptest.NewServer(&fakeNightscout{secret: "averylongsecret"})
+	defer server.Close()
+
+	uploader := NewUploader(server.URL, "wrongsecret", &fakeSource{}, slog.Default())
+	if _, err := uploader.upload(context.Background()); err == nil {
+		t.Fatal("expected error for a rejected API secret, got nil")
+	}
+}
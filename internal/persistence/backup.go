@@ -0,0 +1,224 @@
+package persistence
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// backupFormat is the version of the backup archive layout.
+const backupFormat = 1
+
+// Files of a backup archive
+const (
+	backupManifestName = "manifest.json"
+	backupDatabaseName = "glcmd.db"
+)
+
+// ErrBackupUnsupported is returned when backing up or restoring a database
+// other than SQLite.
+var ErrBackupUnsupported = errors.New("backup and restore only support SQLite databases")
+
+// BackupManifest describes a backup archive.
+type BackupManifest struct {
+	Format        int       `json:"format"`
+	CreatedAt     time.Time `json:"createdAt"`
+	GlcoreVersion string    `json:"glcoreVersion"`
+	SchemaVersion int       `json:"schemaVersion"` // Last migration applied, 0 for a database last migrated before versioned migrations
+	DatabaseSize  int64     `json:"databaseSize"`  // Size of the database file in bytes
+}
+
+// Backup writes a gzip-compressed tar archive of the SQLite database to w: a
+// manifest.json describing it, then a consistent snapshot taken with
+// VACUUM INTO, which does not block the daemon writing meanwhile.
+// glcoreVersion is recorded in the manifest.
+func (d *Database) Backup(ctx context.Context, w io.Writer, glcoreVersion string) (*BackupManifest, error) {
+	if d.config.Type != "sqlite" {
+		return nil, ErrBackupUnsupported
+	}
+	schemaVersion, err := d.SchemaMigrator().Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp(filepath.Dir(d.config.SQLitePath), ".glcmd-backup-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the snapshot directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	snapshot := filepath.Join(dir, backupDatabaseName)
+	if err := d.db.WithContext(ctx).Exec("VACUUM INTO ?", snapshot).Error; err != nil {
+		return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+	}
+
+	f, err := os.Open(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the snapshot: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the snapshot: %w", err)
+	}
+
+	manifest := &BackupManifest{
+		Format:        backupFormat,
+		CreatedAt:     time.Now().UTC(),
+		GlcoreVersion: glcoreVersion,
+		SchemaVersion: schemaVersion,
+		DatabaseSize:  info.Size(),
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the manifest: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	header := &tar.Header{Name: backupManifestName, Mode: 0600, Size: int64(len(manifestData)), ModTime: manifest.CreatedAt}
+	if err := tw.WriteHeader(header); err != nil {
+		return nil, fmt.Errorf("failed to write the archive: %w", err)
+	}
+	if _, err := tw.Write(manifestData); err != nil {
+		return nil, fmt.Errorf("failed to write the archive: %w", err)
+	}
+
+	header = &tar.Header{Name: backupDatabaseName, Mode: 0600, Size: info.Size(), ModTime: manifest.CreatedAt}
+	if err := tw.WriteHeader(header); err != nil {
+		return nil, fmt.Errorf("failed to write the archive: %w", err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return nil, fmt.Errorf("failed to write the archive: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write the archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write the archive: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// RestoreBackup replaces the SQLite database at path with the database of the
+// backup archive read from r. The database must not be open. The archive is
+// checked before anything is replaced: its schema must not be newer than
+// SchemaVersion (older schemas are migrated at the next start) and the
+// database must pass an integrity check. The replaced database files are kept
+// with a .pre-restore suffix.
+func RestoreBackup(r io.Reader, path string) (*BackupManifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".glcmd-restore-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the restored database: %w", err)
+	}
+	restored := false
+	defer func() {
+		tmp.Close()
+		if !restored {
+			os.Remove(tmp.Name())
+		}
+	}()
+	if err := tmp.Chmod(0600); err != nil {
+		return nil, fmt.Errorf("failed to create the restored database: %w", err)
+	}
+
+	var manifest *BackupManifest
+	hasDatabase := false
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup archive: %w", err)
+		}
+
+		switch header.Name {
+		case backupManifestName:
+			manifest = &BackupManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("invalid backup manifest: %w", err)
+			}
+		case backupDatabaseName:
+			if _, err := io.Copy(tmp, tr); err != nil {
+				return nil, fmt.Errorf("failed to extract the database: %w", err)
+			}
+			hasDatabase = true
+		}
+	}
+
+	switch {
+	case manifest == nil:
+		return nil, fmt.Errorf("invalid backup archive: no %s", backupManifestName)
+	case !hasDatabase:
+		return nil, fmt.Errorf("invalid backup archive: no %s", backupDatabaseName)
+	case manifest.Format != backupFormat:
+		return nil, fmt.Errorf("unsupported backup format %d", manifest.Format)
+	case manifest.SchemaVersion > SchemaVersion():
+		return nil, fmt.Errorf("the backup has schema version %d, newer than %d: restore it with glcore %s or later", manifest.SchemaVersion, SchemaVersion(), manifest.GlcoreVersion)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to extract the database: %w", err)
+	}
+	if err := checkIntegrity(tmp.Name()); err != nil {
+		return nil, err
+	}
+
+	// Keep the replaced database with its WAL files, which may hold
+	// committed transactions
+	for _, suffix := range sqliteFileSuffixes {
+		err := os.Rename(path+suffix, path+".pre-restore"+suffix)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to move the current database: %w", err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to restore the database: %w", err)
+	}
+	restored = true
+
+	return manifest, nil
+}
+
+// checkIntegrity runs the SQLite integrity check on the database at path.
+func checkIntegrity(path string) error {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return fmt.Errorf("failed to open the restored database: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to open the restored database: %w", err)
+	}
+	defer sqlDB.Close()
+
+	var result string
+	if err := db.Raw("PRAGMA integrity_check").Scan(&result).Error; err != nil {
+		return fmt.Errorf("the restored database is invalid: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("the restored database is corrupt: %s", result)
+	}
+	return nil
+}
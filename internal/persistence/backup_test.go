@@ -0,0 +1,111 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// backupRow is a minimal model for the backup tests.
+type backupRow struct {
+	ID    uint
+	Value int
+}
+
+func openTestSQLite(t *testing.T, path string) *Database {
+	t.Helper()
+	cfg := DefaultSQLiteConfig()
+	cfg.SQLitePath = path
+	cfg.LogLevel = "silent"
+
+	db, err := NewDatabase(cfg)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	source := openTestSQLite(t, filepath.Join(dir, "source.db"))
+	if err := source.Migrate(&backupRow{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.DB().Create(&backupRow{Value: 42}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	manifest, err := source.Backup(ctx, &archive, "v1.2.3")
+	if err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+	if manifest.SchemaVersion != SchemaVersion() || manifest.GlcoreVersion != "v1.2.3" || manifest.DatabaseSize == 0 {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+	if entries, _ := filepath.Glob(filepath.Join(dir, ".glcmd-backup-*")); len(entries) != 0 {
+		t.Errorf("expected the snapshot removed, got %v", entries)
+	}
+
+	// The replaced database is kept
+	target := filepath.Join(dir, "target.db")
+	if err := os.WriteFile(target, []byte("previous"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := RestoreBackup(bytes.NewReader(archive.Bytes()), target)
+	if err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	if restored.SchemaVersion != SchemaVersion() {
+		t.Errorf("unexpected restored manifest: %+v", restored)
+	}
+	if data, _ := os.ReadFile(target + ".pre-restore"); string(data) != "previous" {
+		t.Errorf("expected the previous database kept, got %q", data)
+	}
+
+	var row backupRow
+	if err := openTestSQLite(t, target).DB().First(&row).Error; err != nil || row.Value != 42 {
+		t.Errorf("expected the restored row, got %+v (%v)", row, err)
+	}
+}
+
+func TestRestoreBackup_NewerSchema(t *testing.T) {
+	dir := t.TempDir()
+
+	source := openTestSQLite(t, filepath.Join(dir, "source.db"))
+	if err := source.DB().AutoMigrate(&schemaMigration{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.DB().Create(&schemaMigration{Version: 999, Name: "future"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	var archive bytes.Buffer
+	if _, err := source.Backup(context.Background(), &archive, "v9.0.0"); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	target := filepath.Join(dir, "target.db")
+	if _, err := RestoreBackup(&archive, target); err == nil || !strings.Contains(err.Error(), "schema version 999") {
+		t.Fatalf("expected error for a newer schema, got %v", err)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("expected nothing restored")
+	}
+	if entries, _ := filepath.Glob(filepath.Join(dir, ".glcmd-restore-*")); len(entries) != 0 {
+		t.Errorf("expected the extracted database removed, got %v", entries)
+	}
+}
+
+func TestRestoreBackup_InvalidArchive(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "glcmd.db")
+	if _, err := RestoreBackup(strings.NewReader("not a backup"), target); err == nil {
+		t.Fatal("expected error for an invalid archive, got nil")
+	}
+}
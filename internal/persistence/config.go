@@ -10,11 +10,14 @@ import (
 // DatabaseConfig holds database connection configuration.
 type DatabaseConfig struct {
 	Type            string        // "sqlite" or "postgres"
+	Backend         string        // Repository implementation: "gorm" or "sql"
 	SQLitePath      string        // Path to SQLite file (e.g., "./data/glcmd.db")
 	MaxOpenConns    int           // Maximum number of open connections
 	MaxIdleConns    int           // Maximum number of idle connections
 	ConnMaxLifetime time.Duration // Maximum connection lifetime
 	LogLevel        string        // GORM log level: "silent", "error", "warn", "info"
+	LowMemory       bool          // Disable the prepared statement cache and shrink the SQLite page cache
+	SecureFiles     bool          // Restrict permissions of existing SQLite files to 0600
 
 	// PostgreSQL-specific (for future use)
 	Host     string // PostgreSQL host
@@ -25,10 +28,18 @@ type DatabaseConfig struct {
 	SSLMode  string // PostgreSQL SSL mode: "disable", "require", "verify-full"
 }
 
+// Repository backends. BackendSQL serves glucose measurements through
+// database/sql prepared statements instead of GORM, for low-end hardware.
+const (
+	BackendGORM = "gorm"
+	BackendSQL  = "sql"
+)
+
 // DefaultSQLiteConfig returns default configuration for SQLite.
 func DefaultSQLiteConfig() *DatabaseConfig {
 	return &DatabaseConfig{
 		Type:            "sqlite",
+		Backend:         BackendGORM,
 		SQLitePath:      "./data/glcmd.db",
 		MaxOpenConns:    1,  // SQLite: 1 writer at a time
 		MaxIdleConns:    1,  // Keep connection alive
@@ -51,6 +62,10 @@ func LoadDatabaseConfigFromEnv() *DatabaseConfig {
 		config.LogLevel = logLevel
 	}
 
+	if backend := os.Getenv("GLCMD_DB_BACKEND"); backend != "" {
+		config.Backend = backend
+	}
+
 	// PostgreSQL configuration (future)
 	if dbType := os.Getenv("GLCMD_DB_TYPE"); dbType == "postgres" {
 		config.Type = "postgres"
@@ -75,7 +90,12 @@ func (c *DatabaseConfig) BuildDSN() string {
 	case "sqlite":
 		// Enable WAL mode for better concurrency
 		// Set busy timeout to 5 seconds to avoid "database is locked" errors
-		return fmt.Sprintf("%s?_journal_mode=WAL&_busy_timeout=5000", c.SQLitePath)
+		dsn := fmt.Sprintf("%s?_journal_mode=WAL&_busy_timeout=5000", c.SQLitePath)
+		if c.LowMemory {
+			// Negative cache size is in KiB: 512 KiB instead of the 2 MiB default
+			dsn += "&_cache_size=-512"
+		}
+		return dsn
 
 	case "postgres":
 		return fmt.Sprintf(
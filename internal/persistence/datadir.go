@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// sqliteFileSuffixes are the files SQLite keeps next to the database in WAL mode.
+var sqliteFileSuffixes = []string{"", "-wal", "-shm"}
+
+// PrepareSQLiteDir creates the directory of the SQLite database and checks it
+// can be written, so a misconfigured path fails with a clear error instead of
+// "unable to open database file".
+//
+// Existing database files readable by other users are reported; when
+// secureFiles is true their permissions are restricted to 0600 instead.
+func PrepareSQLiteDir(path string, secureFiles bool) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create database directory %s: %w", dir, err)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to access database directory %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("database directory %s is not a directory", dir)
+	}
+
+	// Permission bits do not tell the whole story (owner, ACLs, read-only
+	// mounts): try to create a file
+	probe, err := os.CreateTemp(dir, ".glcmd-write-check-*")
+	if err != nil {
+		return fmt.Errorf("database directory %s is not writable: %w", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	for _, suffix := range sqliteFileSuffixes {
+		if err := checkFilePermissions(path+suffix, secureFiles); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkFilePermissions warns about or fixes a database file readable by other users.
+// Missing files are ignored.
+func checkFilePermissions(path string, secureFiles bool) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to access database file %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("database file %s is a directory", path)
+	}
+
+	mode := info.Mode().Perm()
+	if mode&0o077 == 0 {
+		return nil
+	}
+
+	if secureFiles {
+		if err := os.Chmod(path, 0600); err != nil {
+			return fmt.Errorf("failed to restrict permissions of %s: %w", path, err)
+		}
+		slog.Info("database file permissions restricted", "file", path, "previous", mode.String())
+		return nil
+	}
+
+	if mode&0o004 != 0 {
+		slog.Warn("database file is readable by all users",
+			"file", path,
+			"mode", mode.String(),
+			"hint", "set GLCMD_DB_SECURE_FILES=true or chmod 600",
+		)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrepareSQLiteDir_CreatesDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "data", "glcmd.db")
+
+	if err := PrepareSQLiteDir(path, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Dir(path))
+	if err != nil || !info.IsDir() {
+		t.Fatalf("expected directory to be created, got %v", err)
+	}
+
+	// The write check must not leave files behind
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 0 {
+		t.Errorf("expected empty directory, got %d entries", len(entries))
+	}
+}
+
+func TestPrepareSQLiteDir_NotADirectory(t *testing.T) {
+	parent := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(parent, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := PrepareSQLiteDir(filepath.Join(parent, "glcmd.db"), false); err == nil {
+		t.Fatal("expected error when the directory is a file, got nil")
+	}
+}
+
+func TestPrepareSQLiteDir_FilePermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		secureFiles bool
+		want        os.FileMode
+	}{
+		{"warn only", false, 0644},
+		{"secure files", true, 0600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "glcmd.db")
+			for _, suffix := range sqliteFileSuffixes {
+				if err := os.WriteFile(path+suffix, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+				// Not subject to the umask
+				if err := os.Chmod(path+suffix, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := PrepareSQLiteDir(path, tt.secureFiles); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, suffix := range sqliteFileSuffixes {
+				info, err := os.Stat(path + suffix)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := info.Mode().Perm(); got != tt.want {
+					t.Errorf("%s: expected mode %v, got %v", path+suffix, tt.want, got)
+				}
+			}
+		})
+	}
+}
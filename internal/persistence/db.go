@@ -5,8 +5,6 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
-	"os"
-	"path/filepath"
 	"time"
 
 	"gorm.io/driver/postgres"
@@ -23,11 +21,10 @@ type Database struct {
 
 // NewDatabase creates a new database connection based on the provided configuration.
 func NewDatabase(config *DatabaseConfig) (*Database, error) {
-	// For SQLite, ensure the directory exists
+	// For SQLite, ensure the directory exists and can be written
 	if config.Type == "sqlite" {
-		dir := filepath.Dir(config.SQLitePath)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return nil, fmt.Errorf("failed to create database directory %s: %w", dir, err)
+		if err := PrepareSQLiteDir(config.SQLitePath, config.SecureFiles); err != nil {
+			return nil, err
 		}
 	}
 
@@ -48,7 +45,7 @@ func NewDatabase(config *DatabaseConfig) (*Database, error) {
 		NowFunc: func() time.Time {
 			return time.Now().UTC() // Always use UTC for consistency
 		},
-		PrepareStmt: true, // Enable prepared statement cache for better performance
+		PrepareStmt: !config.LowMemory, // Prepared statement cache for better performance, unless memory is scarce
 	}
 
 	// Open database connection
@@ -71,6 +68,7 @@ func NewDatabase(config *DatabaseConfig) (*Database, error) {
 		"type", config.Type,
 		"maxOpenConns", config.MaxOpenConns,
 		"maxIdleConns", config.MaxIdleConns,
+		"lowMemory", config.LowMemory,
 	)
 
 	return &Database{
@@ -79,45 +77,48 @@ func NewDatabase(config *DatabaseConfig) (*Database, error) {
 	}, nil
 }
 
-// AutoMigrate runs automatic migration for all GORM models.
-// It also handles dropping legacy indexes that have been replaced.
-func (d *Database) AutoMigrate(models ...interface{}) error {
+// Migrate brings the schema up to date. The pending versioned migrations run
+// first, on the tables as the previous release left them, then AutoMigrate
+// creates the tables, columns and indexes of models. A new database is created
+// from models and its migrations are recorded as applied.
+func (d *Database) Migrate(models ...interface{}) error {
 	slog.Info("running database migrations", "type", d.config.Type)
 
-	// Drop legacy indexes before AutoMigrate (which cannot remove old indexes)
-	d.dropLegacyIndexes()
+	migrator := d.SchemaMigrator()
+	ctx := context.Background()
+
+	fresh := !d.db.Migrator().HasTable(&schemaMigration{})
+	for _, model := range models {
+		if fresh && d.db.Migrator().HasTable(model) {
+			fresh = false
+		}
+	}
+
+	if !fresh {
+		if _, err := migrator.Up(ctx); err != nil {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+	}
 
 	if err := d.db.AutoMigrate(models...); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	slog.Info("database migrations completed successfully")
+	if fresh {
+		if err := migrator.Baseline(ctx); err != nil {
+			return fmt.Errorf("failed to record migrations: %w", err)
+		}
+	}
+
+	slog.Info("database migrations completed successfully", "schemaVersion", migrator.Latest())
 	return nil
 }
 
-// dropLegacyIndexes removes old indexes that have been replaced.
-// Errors are logged but not fatal (index may not exist on new databases).
-func (d *Database) dropLegacyIndexes() {
-	migrator := d.db.Migrator()
-
-	// idx_unique_timestamp replaced by idx_unique_factory_ts (dedup key moved to factory_timestamp)
-	legacyIndexes := []struct {
-		table string
-		index string
-	}{
-		{"glucose_measurements", "idx_unique_timestamp"},
-		{"glucose_measurements", "idx_factory_ts"},
-	}
-
-	for _, li := range legacyIndexes {
-		if migrator.HasIndex(li.table, li.index) {
-			if err := migrator.DropIndex(li.table, li.index); err != nil {
-				slog.Warn("failed to drop legacy index", "table", li.table, "index", li.index, "error", err)
-			} else {
-				slog.Info("dropped legacy index", "table", li.table, "index", li.index)
-			}
-		}
-	}
+// SchemaMigrator returns the Migrator of the glcmd migrations.
+func (d *Database) SchemaMigrator() *Migrator {
+	// The migrations are checked by the tests
+	migrator, _ := NewMigrator(d.db, migrations)
+	return migrator
 }
 
 // DB returns the underlying GORM database instance.
@@ -164,6 +165,29 @@ func (d *Database) Stats() (sql.DBStats, error) {
 	return sqlDB.Stats(), nil
 }
 
+// Size returns the size of the database in bytes: the pages of the SQLite
+// file, or the disk space used by the PostgreSQL database.
+func (d *Database) Size(ctx context.Context) (int64, error) {
+	db := d.db.WithContext(ctx)
+
+	var size int64
+	if d.config.Type == "postgres" {
+		if err := db.Raw("SELECT pg_database_size(current_database())").Scan(&size).Error; err != nil {
+			return 0, fmt.Errorf("failed to get the database size: %w", err)
+		}
+		return size, nil
+	}
+
+	var pageCount, pageSize int64
+	if err := db.Raw("PRAGMA page_count").Scan(&pageCount).Error; err != nil {
+		return 0, fmt.Errorf("failed to get the database size: %w", err)
+	}
+	if err := db.Raw("PRAGMA page_size").Scan(&pageSize).Error; err != nil {
+		return 0, fmt.Errorf("failed to get the database size: %w", err)
+	}
+	return pageCount * pageSize, nil
+}
+
 // parseLogLevel converts a string log level to GORM's logger.LogLevel.
 func parseLogLevel(level string) logger.LogLevel {
 	switch level {
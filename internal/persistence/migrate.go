@@ -0,0 +1,245 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is a versioned change of the schema or data that AutoMigrate
+// cannot make: renames, drops, backfills. Migrations run in a transaction,
+// against the tables as the previous release left them, so they use table and
+// column names rather than the current models. Down reverts Up, or is nil if
+// the migration cannot be rolled back.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// MigrationStatus reports whether a migration is applied.
+type MigrationStatus struct {
+	Version    int
+	Name       string
+	AppliedAt  *time.Time // nil if pending
+	Reversible bool
+	Unknown    bool // Applied by a newer release
+}
+
+// ErrIrreversible is returned when rolling back a migration without Down.
+var ErrIrreversible = errors.New("migration cannot be rolled back")
+
+// schemaMigration records an applied migration.
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(100);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM.
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies and rolls back migrations, recording the applied ones in
+// the schema_migrations table.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a Migrator for migrations, which must have distinct
+// positive versions.
+func NewMigrator(db *gorm.DB, migrations []Migration) (*Migrator, error) {
+	sorted := slices.Clone(migrations)
+	slices.SortFunc(sorted, func(a, b Migration) int { return a.Version - b.Version })
+	for i, m := range sorted {
+		if m.Version <= 0 || m.Up == nil {
+			return nil, fmt.Errorf("invalid migration %d %s", m.Version, m.Name)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("duplicate migration version %d", m.Version)
+		}
+	}
+
+	return &Migrator{db: db, migrations: sorted}, nil
+}
+
+// Latest returns the version of the last migration (0 without migrations).
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the version of the last applied migration, 0 if none.
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	db := m.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return 0, nil
+	}
+
+	var version int
+	if err := db.Model(&schemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("failed to read the schema version: %w", err)
+	}
+	return version, nil
+}
+
+// Status returns the migrations, oldest first, with the migrations applied by
+// a newer release last.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name, Reversible: migration.Down != nil}
+		if record, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &record.AppliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	var unknown []MigrationStatus
+	for _, record := range applied {
+		unknown = append(unknown, MigrationStatus{Version: record.Version, Name: record.Name, AppliedAt: &record.AppliedAt, Unknown: true})
+	}
+	slices.SortFunc(unknown, func(a, b MigrationStatus) int { return a.Version - b.Version })
+
+	return append(statuses, unknown...), nil
+}
+
+// Up applies the pending migrations in order and returns them. It fails
+// without applying anything if the database holds migrations of a newer
+// release.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for version := range applied {
+		if version > m.Latest() {
+			return nil, fmt.Errorf("the database schema version %d is newer than %d: run a newer glcore, or roll back with it", version, m.Latest())
+		}
+	}
+
+	if err := m.db.WithContext(ctx).AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create the migrations table: %w", err)
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		slog.Info("database migration applied", "version", migration.Version, "name", migration.Name)
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down rolls back the applied migrations after version target, newest first,
+// and returns them. It fails without rolling back anything if one of them is
+// irreversible or unknown to this release.
+func (m *Migrator) Down(ctx context.Context, target int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for version, record := range applied {
+		if version <= target {
+			continue
+		}
+		i := slices.IndexFunc(m.migrations, func(migration Migration) bool { return migration.Version == version })
+		if i < 0 {
+			return nil, fmt.Errorf("migration %d %s is unknown to this release: roll it back with the glcore that applied it", version, record.Name)
+		}
+		if m.migrations[i].Down == nil {
+			return nil, fmt.Errorf("%w: %d %s", ErrIrreversible, version, record.Name)
+		}
+		pending = append(pending, m.migrations[i])
+	}
+	slices.SortFunc(pending, func(a, b Migration) int { return b.Version - a.Version })
+
+	var done []Migration
+	for _, migration := range pending {
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("rollback of migration %d %s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		slog.Info("database migration rolled back", "version", migration.Version, "name", migration.Name)
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Baseline records every migration as applied without running it, for a new
+// database created with the current schema.
+func (m *Migrator) Baseline(ctx context.Context) error {
+	db := m.db.WithContext(ctx)
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create the migrations table: %w", err)
+	}
+	if len(m.migrations) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	records := make([]schemaMigration, len(m.migrations))
+	for i, migration := range m.migrations {
+		records[i] = schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: now}
+	}
+	return db.Create(&records).Error
+}
+
+// applied returns the applied migrations by version. The migrations table is
+// not created, so a new database is still recognized as such by Migrate.
+func (m *Migrator) applied(ctx context.Context) (map[int]schemaMigration, error) {
+	db := m.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return map[int]schemaMigration{}, nil
+	}
+
+	var records []schemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read the applied migrations: %w", err)
+	}
+
+	applied := make(map[int]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
@@ -0,0 +1,197 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// testMigrations rename a column and backfill another: what AutoMigrate cannot do.
+var testMigrations = []Migration{
+	{
+		Version: 2,
+		Name:    "backfill_unit",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Exec("ALTER TABLE readings ADD COLUMN unit TEXT").Error; err != nil {
+				return err
+			}
+			return tx.Exec("UPDATE readings SET unit = 'mg/dL'").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Exec("ALTER TABLE readings DROP COLUMN unit").Error
+		},
+	},
+	{
+		Version: 1,
+		Name:    "rename_value",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec("ALTER TABLE readings RENAME COLUMN val TO value").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Exec("ALTER TABLE readings RENAME COLUMN value TO val").Error
+		},
+	},
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	ctx := context.Background()
+	db := openTestSQLite(t, filepath.Join(t.TempDir(), "glcmd.db")).DB()
+	if err := db.Exec("CREATE TABLE readings (id INTEGER PRIMARY KEY, val INTEGER)").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, err := NewMigrator(db, testMigrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if len(applied) != 2 || applied[0].Version != 1 || applied[1].Version != 2 {
+		t.Fatalf("expected migrations 1 and 2 applied in order, got %+v", applied)
+	}
+	if !db.Migrator().HasColumn("readings", "value") || !db.Migrator().HasColumn("readings", "unit") {
+		t.Error("expected the migrated columns")
+	}
+	if version, _ := migrator.Version(ctx); version != 2 {
+		t.Errorf("expected version 2, got %d", version)
+	}
+
+	// Applied migrations are not run again
+	if applied, err := migrator.Up(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("expected nothing to apply, got %+v (%v)", applied, err)
+	}
+
+	rolledBack, err := migrator.Down(ctx, 0)
+	if err != nil {
+		t.Fatalf("Down failed: %v", err)
+	}
+	if len(rolledBack) != 2 || rolledBack[0].Version != 2 {
+		t.Fatalf("expected migrations 2 and 1 rolled back, got %+v", rolledBack)
+	}
+	if !db.Migrator().HasColumn("readings", "val") || db.Migrator().HasColumn("readings", "unit") {
+		t.Error("expected the original columns")
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].AppliedAt != nil || !statuses[0].Reversible {
+		t.Errorf("expected pending reversible migrations, got %+v", statuses)
+	}
+}
+
+func TestMigrator_FailedMigrationIsNotRecorded(t *testing.T) {
+	ctx := context.Background()
+	db := openTestSQLite(t, filepath.Join(t.TempDir(), "glcmd.db")).DB()
+
+	// No readings table: migration 1 fails
+	migrator, _ := NewMigrator(db, testMigrations)
+	if _, err := migrator.Up(ctx); err == nil {
+		t.Fatal("expected the migration to fail")
+	}
+	if version, _ := migrator.Version(ctx); version != 0 {
+		t.Errorf("expected no migration recorded, got version %d", version)
+	}
+}
+
+func TestMigrator_Down_Irreversible(t *testing.T) {
+	ctx := context.Background()
+	db := openTestSQLite(t, filepath.Join(t.TempDir(), "glcmd.db")).DB()
+
+	migrator, _ := NewMigrator(db, []Migration{{Version: 1, Name: "noop", Up: func(tx *gorm.DB) error { return nil }}})
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Down(ctx, 0); !errors.Is(err, ErrIrreversible) {
+		t.Errorf("expected ErrIrreversible, got %v", err)
+	}
+}
+
+func TestMigrator_NewerDatabase(t *testing.T) {
+	ctx := context.Background()
+	db := openTestSQLite(t, filepath.Join(t.TempDir(), "glcmd.db")).DB()
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&schemaMigration{Version: 5, Name: "future"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, _ := NewMigrator(db, testMigrations)
+	if _, err := migrator.Up(ctx); err == nil {
+		t.Error("expected error for a database migrated by a newer release")
+	}
+	statuses, _ := migrator.Status(ctx)
+	if last := statuses[len(statuses)-1]; last.Version != 5 || !last.Unknown {
+		t.Errorf("expected the unknown migration listed last, got %+v", last)
+	}
+}
+
+func TestNewMigrator_DuplicateVersion(t *testing.T) {
+	up := func(tx *gorm.DB) error { return nil }
+	if _, err := NewMigrator(nil, []Migration{{Version: 1, Up: up}, {Version: 1, Up: up}}); err == nil {
+		t.Error("expected error for duplicate versions")
+	}
+	if _, err := NewMigrator(nil, migrations); err != nil {
+		t.Errorf("invalid glcmd migrations: %v", err)
+	}
+}
+
+// legacyMeasurement has the index dropped by migration 1.
+type legacyMeasurement struct {
+	ID        uint
+	Timestamp int `gorm:"uniqueIndex:idx_unique_timestamp"`
+}
+
+func (legacyMeasurement) TableName() string {
+	return "glucose_measurements"
+}
+
+// currentMeasurement is the current model of legacyMeasurement.
+type currentMeasurement struct {
+	ID        uint
+	Timestamp int
+	PatientID string
+}
+
+func (currentMeasurement) TableName() string {
+	return "glucose_measurements"
+}
+
+func TestDatabase_Migrate(t *testing.T) {
+	ctx := context.Background()
+
+	// A new database is created from the models, its migrations recorded
+	fresh := openTestSQLite(t, filepath.Join(t.TempDir(), "glcmd.db"))
+	if err := fresh.Migrate(&currentMeasurement{}); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if version, _ := fresh.SchemaMigrator().Version(ctx); version != SchemaVersion() {
+		t.Errorf("expected the new database at version %d, got %d", SchemaVersion(), version)
+	}
+
+	// A database created before versioned migrations runs them all
+	legacy := openTestSQLite(t, filepath.Join(t.TempDir(), "glcmd.db"))
+	if err := legacy.DB().AutoMigrate(&legacyMeasurement{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := legacy.Migrate(&currentMeasurement{}); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if legacy.DB().Migrator().HasIndex("glucose_measurements", "idx_unique_timestamp") {
+		t.Error("expected the legacy index dropped")
+	}
+	if !legacy.DB().Migrator().HasColumn("glucose_measurements", "patient_id") {
+		t.Error("expected AutoMigrate to add the new column")
+	}
+	if version, _ := legacy.SchemaMigrator().Version(ctx); version != SchemaVersion() {
+		t.Errorf("expected the legacy database at version %d, got %d", SchemaVersion(), version)
+	}
+}
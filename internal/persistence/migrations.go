@@ -0,0 +1,59 @@
+package persistence
+
+import (
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+// migrations are the versioned migrations of the glcmd schema, run before
+// AutoMigrate. Append new migrations with the next version; never change or
+// remove a released one.
+var migrations = []Migration{
+	{
+		// idx_unique_timestamp was replaced by idx_unique_factory_ts (dedup
+		// key moved to factory_timestamp), itself replaced by
+		// idx_unique_patient_factory_ts (dedup per patient). AutoMigrate
+		// cannot remove old indexes, and the old unique ones reject the
+		// readings of a second patient.
+		Version: 1,
+		Name:    "drop_legacy_glucose_indexes",
+		Up: func(tx *gorm.DB) error {
+			for _, index := range []string{"idx_unique_timestamp", "idx_factory_ts", "idx_unique_factory_ts"} {
+				if !tx.Migrator().HasIndex("glucose_measurements", index) {
+					continue
+				}
+				if err := tx.Migrator().DropIndex("glucose_measurements", index); err != nil {
+					return err
+				}
+				slog.Info("dropped legacy index", "table", "glucose_measurements", "index", index)
+			}
+			return nil
+		},
+	},
+	{
+		// Glucose events are detected per patient: idx_glucose_event_start,
+		// unique on (start_time, type), is replaced by
+		// idx_glucose_event_patient_start. Events are derived from the
+		// measurements, so those stored without a patient are deleted and
+		// detected again at startup.
+		Version: 2,
+		Name:    "glucose_events_per_patient",
+		Up: func(tx *gorm.DB) error {
+			if !tx.Migrator().HasTable("glucose_events") {
+				return nil
+			}
+			if tx.Migrator().HasIndex("glucose_events", "idx_glucose_event_start") {
+				if err := tx.Migrator().DropIndex("glucose_events", "idx_glucose_event_start"); err != nil {
+					return err
+				}
+			}
+			return tx.Exec("DELETE FROM glucose_events").Error
+		},
+	},
+}
+
+// SchemaVersion returns the version of the glcmd schema: the last migration.
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
@@ -0,0 +1,159 @@
+// Package plugin runs user-provided executables on each event, so custom
+// integrations can be added without forking glcore.
+//
+// Each event is written as JSON to the standard input of every configured
+// executable:
+//
+//	{"type": "glucose", "data": {...}}
+//
+// data is the event payload of the SSE stream. The executable's standard
+// output is discarded; a non-zero exit status or a timeout is logged with the
+// end of its standard error. Plugins do not inherit glcore's environment,
+// which holds credentials: they only get PATH, HOME and GLCMD_EVENT_TYPE.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/events"
+)
+
+const (
+	// maxStderr is how much of a failed plugin's standard error is logged.
+	maxStderr = 1024
+	// waitDelay bounds the wait for the output pipes once a plugin is killed,
+	// in case it left children holding them open.
+	waitDelay = time.Second
+)
+
+// EventTypes are the events delivered to plugins (keepalives are not).
+var EventTypes = []events.EventType{
+	events.EventTypeGlucose,
+	events.EventTypeSensor,
+	events.EventTypeSummary,
+	events.EventTypeConfig,
+	events.EventTypeAlert,
+}
+
+// message is the JSON document written to a plugin's standard input.
+type message struct {
+	Type events.EventType `json:"type"`
+	Data any              `json:"data"`
+}
+
+// Runner invokes the plugin executables for each event.
+type Runner struct {
+	commands []string
+	timeout  time.Duration
+	slots    chan struct{} // Limits the plugins running at once
+	logger   *slog.Logger
+	wg       sync.WaitGroup
+}
+
+// NewRunner creates a Runner for the executables at the absolute paths
+// commands. Each invocation is killed after timeout, and at most concurrency
+// invocations run at once.
+func NewRunner(commands []string, timeout time.Duration, concurrency int, logger *slog.Logger) *Runner {
+	return &Runner{
+		commands: commands,
+		timeout:  timeout,
+		slots:    make(chan struct{}, concurrency),
+		logger:   logger,
+	}
+}
+
+// Run invokes the plugins for each event received until ctx is cancelled or
+// the channel is closed, then waits for the running invocations.
+// When all slots are busy, events wait in the channel; the broker drops the
+// events that do not fit its buffer.
+func (r *Runner) Run(ctx context.Context, eventCh <-chan events.Event) {
+	defer r.wg.Wait()
+
+	for {
+		select {
+		case event, ok := <-eventCh:
+			if !ok {
+				return
+			}
+			payload, err := json.Marshal(message{Type: event.Type, Data: event.Data})
+			if err != nil {
+				r.logger.Error("failed to encode plugin event", "type", event.Type, "error", err)
+				continue
+			}
+			for _, command := range r.commands {
+				select {
+				case r.slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				r.wg.Add(1)
+				go func() {
+					defer func() {
+						<-r.slots
+						r.wg.Done()
+					}()
+					r.invoke(ctx, command, event.Type, payload)
+				}()
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// invoke runs one plugin with payload on its standard input and logs failures.
+func (r *Runner) invoke(ctx context.Context, command string, eventType events.EventType, payload []byte) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := run(ctx, command, eventType, payload)
+	name := filepath.Base(command)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", r.timeout)
+		}
+		r.logger.Warn("plugin failed", "plugin", name, "type", eventType, "error", err, "duration", time.Since(start))
+		return
+	}
+	r.logger.Debug("plugin run", "plugin", name, "type", eventType, "duration", time.Since(start))
+}
+
+// run executes command with payload on its standard input.
+// The error includes the end of the standard error output.
+func run(ctx context.Context, command string, eventType events.EventType, payload []byte) error {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stderr = &stderr
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + os.Getenv("HOME"),
+		"GLCMD_EVENT_TYPE=" + string(eventType),
+	}
+	cmd.WaitDelay = waitDelay
+
+	if err := cmd.Run(); err != nil {
+		output := strings.TrimSpace(stderr.String())
+		if len(output) > maxStderr {
+			output = "..." + output[len(output)-maxStderr:]
+		}
+		if output != "" {
+			return fmt.Errorf("%w: %s", err, output)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/events"
+)
+
+// writePlugin writes an executable shell script to dir.
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatalf("failed to write plugin: %v", err)
+	}
+	return path
+}
+
+// waitFor polls cond until it is true or fails the test after 5 seconds.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	command := writePlugin(t, dir, "record.sh", `echo "$GLCMD_EVENT_TYPE $GLCMD_PASSWORD" > `+out+`.type
+cat > `+out+`.json
+`)
+
+	t.Setenv("GLCMD_PASSWORD", "secret")
+	runner := NewRunner([]string{command}, 5*time.Second, 1, slog.Default())
+	eventCh := make(chan events.Event, 1)
+	eventCh <- events.Event{Type: events.EventTypeGlucose, Data: &domain.GlucoseMeasurement{ValueInMgPerDl: 123}}
+	close(eventCh)
+
+	// Run returns once the channel is closed and the plugin has exited
+	runner.Run(context.Background(), eventCh)
+
+	eventType, err := os.ReadFile(out + ".type")
+	if err != nil {
+		t.Fatalf("plugin did not run: %v", err)
+	}
+	if strings.TrimSpace(string(eventType)) != "glucose" {
+		t.Errorf("expected only GLCMD_EVENT_TYPE in the environment, got %q", eventType)
+	}
+
+	data, err := os.ReadFile(out + ".json")
+	if err != nil {
+		t.Fatalf("failed to read plugin input: %v", err)
+	}
+	var msg struct {
+		Type string                    `json:"type"`
+		Data domain.GlucoseMeasurement `json:"data"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("invalid plugin input %q: %v", data, err)
+	}
+	if msg.Type != "glucose" || msg.Data.ValueInMgPerDl != 123 {
+		t.Errorf("unexpected plugin input: %s", data)
+	}
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	command := writePlugin(t, t.TempDir(), "slow.sh", "exec sleep 10\n")
+
+	runner := NewRunner([]string{command}, 100*time.Millisecond, 1, slog.Default())
+	eventCh := make(chan events.Event, 1)
+	eventCh <- events.Event{Type: events.EventTypeConfig}
+	close(eventCh)
+
+	start := time.Now()
+	runner.Run(context.Background(), eventCh)
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected the plugin to be killed after the timeout, ran %s", elapsed)
+	}
+}
+
+func TestRunner_Concurrency(t *testing.T) {
+	dir := t.TempDir()
+	var commands []string
+	for _, name := range []string{"a.sh", "b.sh", "c.sh"} {
+		commands = append(commands, writePlugin(t, dir, name, "sleep 0.2\n"))
+	}
+
+	runner := NewRunner(commands, 5*time.Second, 2, slog.Default())
+	eventCh := make(chan events.Event)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var done atomic.Bool
+	go func() {
+		runner.Run(ctx, eventCh)
+		done.Store(true)
+	}()
+
+	eventCh <- events.Event{Type: events.EventTypeSensor}
+	waitFor(t, func() bool { return len(runner.slots) > 0 })
+	if running := len(runner.slots); running > 2 {
+		t.Errorf("expected at most 2 plugins at once, got %d", running)
+	}
+
+	// All 3 plugins run, the third once a slot is free
+	waitFor(t, func() bool { return len(runner.slots) == 0 })
+	close(eventCh)
+	waitFor(t, done.Load)
+}
+
+func TestRun_FailureIncludesStderr(t *testing.T) {
+	command := writePlugin(t, t.TempDir(), "fail.sh", "echo 'broker unreachable' >&2\nexit 3\n")
+
+	err := run(context.Background(), command, events.EventTypeGlucose, []byte("{}"))
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") || !strings.Contains(err.Error(), "broker unreachable") {
+		t.Errorf("expected exit status and stderr in the error, got %v", err)
+	}
+}
@@ -0,0 +1,324 @@
+// Package pumpcsv parses insulin pump CSV exports into treatment entries.
+//
+// Supported exports:
+//   - omnipod: Omnipod data exported from Glooko (bolus_data.csv, basal_data.csv)
+//   - tandem: Tandem t:connect CSV export (bolus and basal sections)
+//
+// Exports may contain preamble lines (patient name, date range) and several
+// sections: each section is recognized by its header row, and unknown
+// sections are ignored. Timestamps are in pump local time.
+package pumpcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// maxBasalSegment bounds the length of a Tandem basal segment, which only
+// records rate changes: a longer gap means data is missing, not a steady rate.
+const maxBasalSegment = 24 * time.Hour
+
+// timestampLayouts are the timestamp formats found in supported exports.
+var timestampLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"1/2/2006 15:04",
+}
+
+// Result holds the treatments parsed from an export.
+type Result struct {
+	Treatments []*domain.TreatmentEntry
+	Skipped    int // Rows of a recognized section that could not be parsed
+}
+
+// section describes a table of an export, recognized by its header columns.
+type section struct {
+	required []string
+	parse    func(r row, loc *time.Location) (*domain.TreatmentEntry, error)
+}
+
+// sections lists the tables of each export format.
+// A header matches the first section whose required columns are all present.
+var sections = map[string][]section{
+	domain.TreatmentSourceOmnipod: {
+		{required: []string{"Timestamp", "Rate", "Duration (minutes)"}, parse: parseOmnipodBasal},
+		{required: []string{"Timestamp", "Insulin Delivered (U)"}, parse: parseOmnipodBolus},
+	},
+	domain.TreatmentSourceTandem: {
+		{required: []string{"EventDateTime", "BasalRate"}, parse: parseTandemBasal},
+		{required: []string{"CompletionDateTime", "InsulinDelivered"}, parse: parseTandemBolus},
+	},
+}
+
+// Parse reads a pump export of the given source (one of domain.TreatmentSources).
+// Timestamps are interpreted in loc and returned in UTC.
+func Parse(source string, r io.Reader, loc *time.Location) (*Result, error) {
+	formatSections, ok := sections[source]
+	if !ok {
+		return nil, fmt.Errorf("unsupported source %q (use %s)", source, strings.Join(domain.TreatmentSources, ", "))
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Sections have different widths
+	reader.LazyQuotes = true
+
+	result := &Result{}
+	var current *section
+	var columns map[string]int
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+
+		if header := headerColumns(record); header != nil {
+			if s := matchSection(formatSections, header); s != nil {
+				current, columns = s, header
+				continue
+			}
+		}
+		if current == nil || isBlank(record) {
+			continue
+		}
+
+		entry, err := current.parse(row{columns: columns, values: record}, loc)
+		if err != nil {
+			result.Skipped++
+			continue
+		}
+		if entry == nil {
+			continue // Nothing delivered
+		}
+		entry.Source = source
+		entry.Timestamp = entry.Timestamp.UTC()
+		result.Treatments = append(result.Treatments, entry)
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("no %s bolus or basal data found", source)
+	}
+
+	if source == domain.TreatmentSourceTandem {
+		fillBasalSegments(result.Treatments)
+	}
+
+	return result, nil
+}
+
+// headerColumns returns the column indexes of a record that looks like a
+// header (no numeric fields), or nil.
+func headerColumns(record []string) map[string]int {
+	columns := make(map[string]int, len(record))
+	for i, field := range record {
+		field = strings.TrimSpace(field)
+		if _, err := strconv.ParseFloat(field, 64); err == nil {
+			return nil
+		}
+		if field != "" {
+			columns[field] = i
+		}
+	}
+	return columns
+}
+
+// matchSection returns the first section whose required columns are all in header.
+func matchSection(formatSections []section, header map[string]int) *section {
+	for i := range formatSections {
+		matched := true
+		for _, column := range formatSections[i].required {
+			if _, ok := header[column]; !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return &formatSections[i]
+		}
+	}
+	return nil
+}
+
+func isBlank(record []string) bool {
+	return !slices.ContainsFunc(record, func(field string) bool {
+		return strings.TrimSpace(field) != ""
+	})
+}
+
+// row gives access to the fields of a record by column name.
+type row struct {
+	columns map[string]int
+	values  []string
+}
+
+// get returns the trimmed value of a column, or "" if absent.
+func (r row) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.values) {
+		return ""
+	}
+	return strings.TrimSpace(r.values[i])
+}
+
+func (r row) float(column string) (float64, error) {
+	value, err := strconv.ParseFloat(r.get(column), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q", column, r.get(column))
+	}
+	return value, nil
+}
+
+// optionalPositive returns a pointer to a positive value of column, or nil
+// when the column is absent, empty or zero.
+func (r row) optionalPositive(column string) *float64 {
+	value, err := strconv.ParseFloat(r.get(column), 64)
+	if err != nil || value <= 0 {
+		return nil
+	}
+	return &value
+}
+
+func (r row) timestamp(column string, loc *time.Location) (time.Time, error) {
+	value := r.get(column)
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid %s: %q", column, value)
+}
+
+func parseOmnipodBolus(r row, loc *time.Location) (*domain.TreatmentEntry, error) {
+	ts, err := r.timestamp("Timestamp", loc)
+	if err != nil {
+		return nil, err
+	}
+	insulin, err := r.float("Insulin Delivered (U)")
+	if err != nil {
+		return nil, err
+	}
+	if insulin <= 0 {
+		return nil, nil
+	}
+
+	return &domain.TreatmentEntry{
+		Timestamp: ts,
+		Type:      domain.TreatmentTypeBolus,
+		Insulin:   insulin,
+		Carbs:     r.optionalPositive("Carbs Input (g)"),
+	}, nil
+}
+
+func parseOmnipodBasal(r row, loc *time.Location) (*domain.TreatmentEntry, error) {
+	ts, err := r.timestamp("Timestamp", loc)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := r.float("Rate")
+	if err != nil {
+		return nil, err
+	}
+	duration, err := r.float("Duration (minutes)")
+	if err != nil {
+		return nil, err
+	}
+
+	// Delivered insulin is exported; derive it from the rate otherwise
+	insulin, err := r.float("Insulin Delivered (U)")
+	if err != nil {
+		insulin = roundUnits(rate * duration / 60)
+	}
+
+	return &domain.TreatmentEntry{
+		Timestamp:       ts,
+		Type:            domain.TreatmentTypeBasal,
+		Insulin:         insulin,
+		BasalRate:       &rate,
+		DurationMinutes: int(duration),
+	}, nil
+}
+
+func parseTandemBolus(r row, loc *time.Location) (*domain.TreatmentEntry, error) {
+	ts, err := r.timestamp("CompletionDateTime", loc)
+	if err != nil {
+		return nil, err
+	}
+	insulin, err := r.float("InsulinDelivered")
+	if err != nil {
+		return nil, err
+	}
+	if insulin <= 0 {
+		return nil, nil
+	}
+
+	return &domain.TreatmentEntry{
+		Timestamp: ts,
+		Type:      domain.TreatmentTypeBolus,
+		Insulin:   insulin,
+		Carbs:     r.optionalPositive("CarbSize"),
+	}, nil
+}
+
+// parseTandemBasal parses a basal rate change; its duration and insulin are
+// filled in by fillBasalSegments once all changes are known.
+func parseTandemBasal(r row, loc *time.Location) (*domain.TreatmentEntry, error) {
+	ts, err := r.timestamp("EventDateTime", loc)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := r.float("BasalRate")
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TreatmentEntry{
+		Timestamp: ts,
+		Type:      domain.TreatmentTypeBasal,
+		BasalRate: &rate,
+	}, nil
+}
+
+// fillBasalSegments sets the duration and insulin of basal rate changes from
+// the next change. The last segment, and segments followed by a gap longer
+// than maxBasalSegment, keep a zero duration.
+func fillBasalSegments(treatments []*domain.TreatmentEntry) {
+	var basal []*domain.TreatmentEntry
+	for _, t := range treatments {
+		if t.Type == domain.TreatmentTypeBasal {
+			basal = append(basal, t)
+		}
+	}
+	sort.Slice(basal, func(i, j int) bool {
+		return basal[i].Timestamp.Before(basal[j].Timestamp)
+	})
+
+	for i := 0; i+1 < len(basal); i++ {
+		segment := basal[i+1].Timestamp.Sub(basal[i].Timestamp)
+		if segment > maxBasalSegment {
+			continue
+		}
+		basal[i].DurationMinutes = int(segment / time.Minute)
+		basal[i].Insulin = roundUnits(*basal[i].BasalRate * segment.Hours())
+	}
+}
+
+// roundUnits rounds computed insulin to the precision stored (0.001 U).
+func roundUnits(units float64) float64 {
+	return math.Round(units*1000) / 1000
+}
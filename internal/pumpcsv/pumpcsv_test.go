@@ -0,0 +1,100 @@
+package pumpcsv
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+func TestParse_Omnipod(t *testing.T) {
+	bolus := `Name:Jane Doe,Date Range:2026-03-01 - 2026-03-07
+Timestamp,Insulin Type,Blood Glucose Input (mg/dl),Carbs Input (g),Carbs Ratio,Insulin Delivered (U),Initial Delivery (U),Extended Delivery (U),Serial Number
+2026-03-01 08:15,Novolog,120,45,10,4.5,4.5,0,PDM123
+2026-03-01 12:30,Novolog,,0,10,2,2,0,PDM123
+2026-03-01 13:00,Novolog,,,10,0,0,0,PDM123
+not a date,Novolog,,,10,1,1,0,PDM123
+`
+	result, err := Parse(domain.TreatmentSourceOmnipod, strings.NewReader(bolus), time.UTC)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(result.Treatments) != 2 || result.Skipped != 1 {
+		t.Fatalf("expected 2 boluses and 1 skipped row, got %d and %d", len(result.Treatments), result.Skipped)
+	}
+
+	first := result.Treatments[0]
+	if first.Type != domain.TreatmentTypeBolus || first.Source != domain.TreatmentSourceOmnipod || first.Insulin != 4.5 {
+		t.Errorf("unexpected bolus: %+v", first)
+	}
+	if first.Carbs == nil || *first.Carbs != 45 {
+		t.Errorf("expected 45g carbs, got %v", first.Carbs)
+	}
+	if !first.Timestamp.Equal(time.Date(2026, 3, 1, 8, 15, 0, 0, time.UTC)) {
+		t.Errorf("unexpected timestamp %v", first.Timestamp)
+	}
+	if result.Treatments[1].Carbs != nil {
+		t.Errorf("expected no carbs for a correction bolus, got %v", *result.Treatments[1].Carbs)
+	}
+
+	basal := `Timestamp,Insulin Type,Duration (minutes),Percentage (%),Rate,Insulin Delivered (U),Serial Number
+2026-03-01 00:00,Novolog,120,100,0.8,1.6,PDM123
+`
+	result, err = Parse(domain.TreatmentSourceOmnipod, strings.NewReader(basal), time.UTC)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(result.Treatments) != 1 {
+		t.Fatalf("expected 1 basal segment, got %d", len(result.Treatments))
+	}
+	segment := result.Treatments[0]
+	if segment.Type != domain.TreatmentTypeBasal || segment.DurationMinutes != 120 || segment.Insulin != 1.6 || *segment.BasalRate != 0.8 {
+		t.Errorf("unexpected basal segment: %+v", segment)
+	}
+}
+
+func TestParse_Tandem(t *testing.T) {
+	export := `t:connect Therapy Data
+Type,BolusType,BolusRequestID,CompletionDateTime,InsulinDelivered,FoodDelivered,CorrectionDelivered,CarbSize
+Bolus,Standard,1001,2026-03-01T08:15:00,5.25,4.5,0.75,45
+
+Type,EventDateTime,BasalRate
+Basal,2026-03-01T00:00:00,0.8
+Basal,2026-03-01T06:00:00,1.2
+Basal,2026-03-01T09:30:00,0.9
+`
+	loc := time.FixedZone("CET", 3600)
+	result, err := Parse(domain.TreatmentSourceTandem, strings.NewReader(export), loc)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(result.Treatments) != 4 || result.Skipped != 0 {
+		t.Fatalf("expected 4 treatments, got %d (%d skipped)", len(result.Treatments), result.Skipped)
+	}
+
+	bolus := result.Treatments[0]
+	if bolus.Type != domain.TreatmentTypeBolus || bolus.Insulin != 5.25 || bolus.Carbs == nil || *bolus.Carbs != 45 {
+		t.Errorf("unexpected bolus: %+v", bolus)
+	}
+	if !bolus.Timestamp.Equal(time.Date(2026, 3, 1, 7, 15, 0, 0, time.UTC)) || bolus.Timestamp.Location() != time.UTC {
+		t.Errorf("expected pump local time converted to UTC, got %v", bolus.Timestamp)
+	}
+
+	night := result.Treatments[1]
+	if night.DurationMinutes != 360 || night.Insulin != 0.8*6 {
+		t.Errorf("expected 6h at 0.8 U/h, got %+v", night)
+	}
+	if last := result.Treatments[3]; last.DurationMinutes != 0 || last.Insulin != 0 {
+		t.Errorf("expected open-ended last segment, got %+v", last)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	if _, err := Parse("medtronic", strings.NewReader(""), time.UTC); err == nil {
+		t.Error("expected error for unsupported source")
+	}
+	if _, err := Parse(domain.TreatmentSourceTandem, strings.NewReader("a,b\n1,2\n"), time.UTC); err == nil {
+		t.Error("expected error when no section is recognized")
+	}
+}
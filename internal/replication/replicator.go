@@ -0,0 +1,198 @@
+// Package replication implements the secondary side of two-instance sync.
+//
+// A secondary glcore instance periodically compares its own sync manifest with
+// the primary's and pulls the content of every day whose checksum differs.
+// Saving is idempotent (measurements are deduplicated by factory timestamp and
+// sensors are upserted by serial number), so a day can safely be pulled again.
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+// Result summarizes a single replication pass.
+type Result struct {
+	DaysChecked          int `json:"daysChecked"`
+	DaysFetched          int `json:"daysFetched"`
+	MeasurementsInserted int `json:"measurementsInserted"`
+	SensorsSaved         int `json:"sensorsSaved"`
+}
+
+// Replicator pulls changed days from a primary glcore instance.
+type Replicator struct {
+	primaryURL     string
+	token          string
+	days           int
+	httpClient     *http.Client
+	syncService    service.SyncService
+	glucoseService service.GlucoseService
+	sensorService  service.SensorService
+	logger         *slog.Logger
+}
+
+// NewReplicator creates a new Replicator.
+// days is the size of the window (ending today) compared on every pass.
+func NewReplicator(
+	primaryURL string,
+	token string,
+	days int,
+	syncService service.SyncService,
+	glucoseService service.GlucoseService,
+	sensorService service.SensorService,
+	logger *slog.Logger,
+) *Replicator {
+	return &Replicator{
+		primaryURL:     primaryURL,
+		token:          token,
+		days:           days,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+		syncService:    syncService,
+		glucoseService: glucoseService,
+		sensorService:  sensorService,
+		logger:         logger,
+	}
+}
+
+// SyncOnce compares the local and primary manifests over the configured
+// window and imports every day whose checksum differs.
+func (r *Replicator) SyncOnce(ctx context.Context) (*Result, error) {
+	end := time.Now().UTC()
+	start := end.AddDate(0, 0, -(r.days - 1))
+
+	remote, err := r.fetchManifest(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := r.syncService.GetManifest(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("local manifest: %w", err)
+	}
+
+	localHashes := make(map[string]string, len(local.Days))
+	for _, day := range local.Days {
+		localHashes[day.Date] = day.Hash
+	}
+
+	result := &Result{DaysChecked: len(remote.Days)}
+	for _, day := range remote.Days {
+		if localHashes[day.Date] == day.Hash {
+			continue
+		}
+
+		inserted, saved, err := r.importDay(ctx, day.Date)
+		if err != nil {
+			return result, fmt.Errorf("import %s: %w", day.Date, err)
+		}
+
+		result.DaysFetched++
+		result.MeasurementsInserted += inserted
+		result.SensorsSaved += saved
+	}
+
+	return result, nil
+}
+
+// importDay fetches one day from the primary and saves its content locally.
+func (r *Replicator) importDay(ctx context.Context, date string) (inserted, saved int, err error) {
+	export, err := r.fetchExport(ctx, date)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, m := range export.Measurements {
+		m.ID = 0
+		m.CreatedAt = time.Time{} // Stored now: the retention job rolls up late readings by storage time
+		ok, err := r.glucoseService.SaveMeasurement(ctx, m)
+		if err != nil {
+			return inserted, saved, fmt.Errorf("save measurement: %w", err)
+		}
+		if ok {
+			inserted++
+		}
+	}
+
+	for _, s := range export.Sensors {
+		s.ID = 0
+		if err := r.sensorService.SaveSensor(ctx, s); err != nil {
+			return inserted, saved, fmt.Errorf("save sensor: %w", err)
+		}
+		saved++
+	}
+
+	r.logger.Debug("day replicated", "date", date, "inserted", inserted, "sensors", saved)
+	return inserted, saved, nil
+}
+
+// fetchManifest retrieves the primary's manifest for [start, end].
+func (r *Replicator) fetchManifest(ctx context.Context, start, end time.Time) (*service.SyncManifest, error) {
+	params := url.Values{}
+	params.Set("start", start.Format(time.RFC3339))
+	params.Set("end", end.Format(time.RFC3339))
+
+	var resp struct {
+		Data *service.SyncManifest `json:"data"`
+	}
+	if err := r.get(ctx, "/v1/sync/manifest?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("primary returned an empty manifest")
+	}
+	return resp.Data, nil
+}
+
+// fetchExport retrieves the content of a single day from the primary.
+func (r *Replicator) fetchExport(ctx context.Context, date string) (*service.SyncExport, error) {
+	var resp struct {
+		Data *service.SyncExport `json:"data"`
+	}
+	if err := r.get(ctx, "/v1/sync/export?date="+url.QueryEscape(date), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("primary returned an empty export")
+	}
+	return resp.Data, nil
+}
+
+// get performs an authenticated GET request against the primary and decodes the JSON body.
+func (r *Replicator) get(ctx context.Context, path string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.primaryURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+r.token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot connect to primary at %s: %w", r.primaryURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil && errResp.Error.Message != "" {
+			return fmt.Errorf("primary returned %d: %s", resp.StatusCode, errResp.Error.Message)
+		}
+		return fmt.Errorf("primary returned %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package replication_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/R4yL-dev/glcmd/internal/api"
+	"github.com/R4yL-dev/glcmd/internal/daemon"
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/replication"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+const testToken = "secret"
+
+// instance is an in-memory glcore instance (database + services).
+type instance struct {
+	db             *gorm.DB
+	glucoseService *service.GlucoseServiceImpl
+	sensorService  *service.SensorServiceImpl
+	configService  *service.ConfigServiceImpl
+	syncService    *service.SyncServiceImpl
+}
+
+func newInstance(t *testing.T) *instance {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&domain.GlucoseMeasurement{},
+		&domain.SensorConfig{},
+		&domain.UserPreferences{},
+		&domain.DeviceInfo{},
+		&domain.GlucoseTargets{},
+		&domain.DashboardConfig{},
+		&domain.DisplayPreferences{},
+		&domain.SavedView{},
+	); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	glucoseRepo := repository.NewGlucoseRepository(db)
+	sensorRepo := repository.NewSensorRepository(db)
+
+	return &instance{
+		db:             db,
+		glucoseService: service.NewGlucoseService(glucoseRepo, nil, slog.Default(), nil),
+		sensorService:  service.NewSensorService(sensorRepo, glucoseRepo, repository.NewUnitOfWork(db), domain.DefaultSensorGracePeriod, slog.Default(), nil),
+		configService: service.NewConfigService(
+			repository.NewUserRepository(db),
+			repository.NewDeviceRepository(db),
+			repository.NewTargetsRepository(db),
+			repository.NewDashboardRepository(db),
+			repository.NewPreferencesRepository(db),
+			slog.Default(),
+			nil,
+		),
+		syncService: service.NewSyncService(glucoseRepo, sensorRepo, slog.Default()),
+	}
+}
+
+// serve exposes the instance through the API server.
+func (i *instance) serve(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := api.NewServer(api.Options{
+		SyncToken:         testToken,
+		GlucoseService:    i.glucoseService,
+		SensorService:     i.sensorService,
+		ConfigService:     i.configService,
+		SyncService:       i.syncService,
+		GetHealthStatus:   func() daemon.HealthStatus { return daemon.HealthStatus{Status: "healthy"} },
+		GetDatabaseHealth: func() bool { return true },
+	}, slog.Default())
+
+	ts := httptest.NewServer(server.HTTPHandler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func (i *instance) count(t *testing.T) int64 {
+	t.Helper()
+
+	var count int64
+	if err := i.db.Model(&domain.GlucoseMeasurement{}).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count measurements: %v", err)
+	}
+	return count
+}
+
+func TestReplicator_SyncOnce(t *testing.T) {
+	primary := newInstance(t)
+	secondary := newInstance(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 5; i++ {
+		ts := now.Add(-time.Duration(i) * time.Hour)
+		if _, err := primary.glucoseService.SaveMeasurement(ctx, &domain.GlucoseMeasurement{
+			FactoryTimestamp: ts,
+			Timestamp:        ts,
+			Value:            5.5,
+			ValueInMgPerDl:   99,
+			GlucoseColor:     domain.GlucoseColorNormal,
+			Type:             domain.GlucoseTypeHistorical,
+		}); err != nil {
+			t.Fatalf("failed to seed primary: %v", err)
+		}
+	}
+	if err := primary.sensorService.SaveSensor(ctx, &domain.SensorConfig{
+		SerialNumber: "SN-REPL",
+		Activation:   now.Add(-time.Hour),
+		ExpiresAt:    now.AddDate(0, 0, 14),
+		SensorType:   4,
+		DurationDays: 15,
+		DetectedAt:   now,
+	}); err != nil {
+		t.Fatalf("failed to seed primary sensor: %v", err)
+	}
+
+	ts := primary.serve(t)
+	replicator := replication.NewReplicator(ts.URL, testToken, 2,
+		secondary.syncService, secondary.glucoseService, secondary.sensorService, slog.Default())
+
+	result, err := replicator.SyncOnce(ctx)
+	if err != nil {
+		t.Fatalf("SyncOnce failed: %v", err)
+	}
+
+	if result.MeasurementsInserted != 5 {
+		t.Errorf("expected 5 measurements inserted, got %d", result.MeasurementsInserted)
+	}
+	if result.SensorsSaved != 1 {
+		t.Errorf("expected 1 sensor saved, got %d", result.SensorsSaved)
+	}
+	if got := secondary.count(t); got != 5 {
+		t.Errorf("expected 5 measurements on secondary, got %d", got)
+	}
+
+	// Second pass: manifests match, nothing to fetch
+	result, err = replicator.SyncOnce(ctx)
+	if err != nil {
+		t.Fatalf("second SyncOnce failed: %v", err)
+	}
+	if result.DaysFetched != 0 {
+		t.Errorf("expected 0 days fetched after sync, got %d", result.DaysFetched)
+	}
+}
+
+func TestReplicator_SyncOnce_InvalidToken(t *testing.T) {
+	primary := newInstance(t)
+	secondary := newInstance(t)
+
+	now := time.Now().UTC()
+	if _, err := primary.glucoseService.SaveMeasurement(context.Background(), &domain.GlucoseMeasurement{
+		FactoryTimestamp: now,
+		Timestamp:        now,
+		ValueInMgPerDl:   99,
+	}); err != nil {
+		t.Fatalf("failed to seed primary: %v", err)
+	}
+
+	ts := primary.serve(t)
+	replicator := replication.NewReplicator(ts.URL, "wrong", 1,
+		secondary.syncService, secondary.glucoseService, secondary.sensorService, slog.Default())
+
+	if _, err := replicator.SyncOnce(context.Background()); err == nil {
+		t.Fatal("expected error with invalid token, got nil")
+	}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// AlertRepositoryGORM is the GORM implementation of AlertRepository.
+type AlertRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewAlertRepository creates a new AlertRepository.
+func NewAlertRepository(db *gorm.DB) *AlertRepositoryGORM {
+	return &AlertRepositoryGORM{db: db}
+}
+
+// Create inserts a new alert.
+func (r *AlertRepositoryGORM) Create(ctx context.Context, a *domain.Alert) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Create(a).Error
+}
+
+// FindWithFilters returns alerts matching filters with pagination, newest first.
+func (r *AlertRepositoryGORM) FindWithFilters(ctx context.Context, filters AlertFilters, limit, offset int) ([]*domain.Alert, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var alerts []*domain.Alert
+	result := applyAlertFilters(db.Model(&domain.Alert{}), filters).
+		Order("fired_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&alerts)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return alerts, nil
+}
+
+// CountWithFilters returns total count of alerts matching filters.
+func (r *AlertRepositoryGORM) CountWithFilters(ctx context.Context, filters AlertFilters) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var count int64
+	result := applyAlertFilters(db.Model(&domain.Alert{}), filters).Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// FindByTimeRange returns alerts fired within a time range (inclusive), oldest first.
+func (r *AlertRepositoryGORM) FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.Alert, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var alerts []*domain.Alert
+	result := db.Where("fired_at >= ? AND fired_at <= ?", start, end).
+		Order("fired_at ASC, id ASC").
+		Find(&alerts)
+
+	return alerts, result.Error
+}
+
+// Acknowledge sets AcknowledgedAt on an alert that is not already acknowledged.
+// Returns persistence.ErrNotFound if no such unacknowledged alert exists.
+func (r *AlertRepositoryGORM) Acknowledge(ctx context.Context, id uint, at time.Time) error {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Model(&domain.Alert{}).
+		Where("id = ? AND acknowledged_at IS NULL", id).
+		Update("acknowledged_at", at)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return persistence.ErrNotFound
+	}
+	return nil
+}
+
+// applyAlertFilters adds the WHERE clauses of filters to query.
+func applyAlertFilters(query *gorm.DB, filters AlertFilters) *gorm.DB {
+	if filters.StartTime != nil {
+		query = query.Where("fired_at >= ?", *filters.StartTime)
+	}
+	if filters.EndTime != nil {
+		query = query.Where("fired_at <= ?", *filters.EndTime)
+	}
+	if filters.Type != nil {
+		query = query.Where("type = ?", *filters.Type)
+	}
+	if filters.Acknowledged != nil {
+		if *filters.Acknowledged {
+			query = query.Where("acknowledged_at IS NOT NULL")
+		} else {
+			query = query.Where("acknowledged_at IS NULL")
+		}
+	}
+	return query
+}
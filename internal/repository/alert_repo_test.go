@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+func TestAlertRepository_FindWithFilters(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAlertRepository(db)
+	ctx := context.Background()
+
+	base := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	for i, alertType := range []string{domain.AlertTypeLow, domain.AlertTypeFalling, domain.AlertTypeLow} {
+		alert := &domain.Alert{
+			FiredAt:       base.Add(time.Duration(i) * time.Hour),
+			Type:          alertType,
+			ValueMgDl:     65,
+			ThresholdMgDl: domain.DefaultLowAlertMgDl,
+			Mode:          domain.ModeNormal,
+		}
+		if err := repo.Create(ctx, alert); err != nil {
+			t.Fatalf("failed to create alert: %v", err)
+		}
+	}
+
+	low := domain.AlertTypeLow
+	alerts, err := repo.FindWithFilters(ctx, AlertFilters{Type: &low}, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to find alerts: %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("expected 2 low alerts, got %d", len(alerts))
+	}
+	if !alerts[0].FiredAt.After(alerts[1].FiredAt) {
+		t.Error("expected alerts newest first")
+	}
+
+	start := base.Add(30 * time.Minute)
+	count, err := repo.CountWithFilters(ctx, AlertFilters{StartTime: &start})
+	if err != nil {
+		t.Fatalf("failed to count alerts: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 alerts after start, got %d", count)
+	}
+
+	inRange, err := repo.FindByTimeRange(ctx, base, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to find alerts by time range: %v", err)
+	}
+	if len(inRange) != 2 || inRange[0].Type != domain.AlertTypeLow {
+		t.Errorf("expected 2 alerts oldest first, got %+v", inRange)
+	}
+}
+
+func TestAlertRepository_Acknowledge(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAlertRepository(db)
+	ctx := context.Background()
+
+	alert := &domain.Alert{FiredAt: time.Now().UTC(), Type: domain.AlertTypeLow, ValueMgDl: 60, ThresholdMgDl: 70, Mode: domain.ModeNormal}
+	if err := repo.Create(ctx, alert); err != nil {
+		t.Fatalf("failed to create alert: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := repo.Acknowledge(ctx, alert.ID, now); err != nil {
+		t.Fatalf("failed to acknowledge alert: %v", err)
+	}
+
+	// Acknowledging twice reports not found
+	if err := repo.Acknowledge(ctx, alert.ID, now); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound on second acknowledge, got %v", err)
+	}
+
+	acknowledged := true
+	count, err := repo.CountWithFilters(ctx, AlertFilters{Acknowledged: &acknowledged})
+	if err != nil {
+		t.Fatalf("failed to count alerts: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 acknowledged alert, got %d", count)
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// AttachmentRepositoryGORM is the GORM implementation of AttachmentRepository.
+type AttachmentRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepository creates a new AttachmentRepository.
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepositoryGORM {
+	return &AttachmentRepositoryGORM{db: db}
+}
+
+// Create inserts a new attachment.
+func (r *AttachmentRepositoryGORM) Create(ctx context.Context, a *domain.SensorAttachment) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Create(a).Error
+}
+
+// FindByID returns an attachment by its ID.
+// Returns persistence.ErrNotFound if no such attachment exists.
+func (r *AttachmentRepositoryGORM) FindByID(ctx context.Context, id uint) (*domain.SensorAttachment, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var attachment domain.SensorAttachment
+	result := db.First(&attachment, id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &attachment, nil
+}
+
+// FindBySerialNumber returns the attachments of a sensor, oldest first.
+func (r *AttachmentRepositoryGORM) FindBySerialNumber(ctx context.Context, serial string) ([]*domain.SensorAttachment, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var attachments []*domain.SensorAttachment
+	result := db.Where("serial_number = ?", serial).Order("created_at ASC, id ASC").Find(&attachments)
+
+	return attachments, result.Error
+}
+
+// FindStorageKeys returns the storage keys of all attachments.
+func (r *AttachmentRepositoryGORM) FindStorageKeys(ctx context.Context) ([]string, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var keys []string
+	result := db.Model(&domain.SensorAttachment{}).Pluck("storage_key", &keys)
+
+	return keys, result.Error
+}
+
+// Delete removes an attachment.
+// Returns persistence.ErrNotFound if no such attachment exists.
+func (r *AttachmentRepositoryGORM) Delete(ctx context.Context, id uint) error {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Delete(&domain.SensorAttachment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return persistence.ErrNotFound
+	}
+
+	return nil
+}
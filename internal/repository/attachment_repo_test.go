@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+func TestAttachmentRepository_CRUD(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAttachmentRepository(db)
+	ctx := context.Background()
+
+	for _, a := range []*domain.SensorAttachment{
+		{SerialNumber: "S1", FileName: "day1.jpg", ContentType: "image/jpeg", Size: 1200, StorageKey: "key1"},
+		{SerialNumber: "S2", FileName: "rash.png", ContentType: "image/png", Size: 800, StorageKey: "key2"},
+		{SerialNumber: "S1", FileName: "day9.jpg", ContentType: "image/jpeg", Size: 1500, Caption: "Edges lifting", StorageKey: "key3"},
+	} {
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("failed to create attachment: %v", err)
+		}
+	}
+
+	attachments, err := repo.FindBySerialNumber(ctx, "S1")
+	if err != nil {
+		t.Fatalf("failed to list attachments: %v", err)
+	}
+	if len(attachments) != 2 || attachments[0].FileName != "day1.jpg" || attachments[1].Caption != "Edges lifting" {
+		t.Fatalf("expected the 2 attachments of S1 oldest first, got %+v", attachments)
+	}
+
+	attachment, err := repo.FindByID(ctx, attachments[1].ID)
+	if err != nil {
+		t.Fatalf("failed to find attachment: %v", err)
+	}
+	if attachment.StorageKey != "key3" {
+		t.Errorf("expected key3, got %q", attachment.StorageKey)
+	}
+
+	if err := repo.Delete(ctx, attachment.ID); err != nil {
+		t.Fatalf("failed to delete attachment: %v", err)
+	}
+	keys, err := repo.FindStorageKeys(ctx)
+	if err != nil {
+		t.Fatalf("failed to list storage keys: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("expected 2 storage keys left, got %v", keys)
+	}
+
+	if _, err := repo.FindByID(ctx, attachment.ID); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := repo.Delete(ctx, attachment.ID); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a second delete, got %v", err)
+	}
+}
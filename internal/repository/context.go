@@ -20,3 +20,27 @@ func txOrDefault(ctx context.Context, db *gorm.DB) *gorm.DB {
 	}
 	return db.WithContext(ctx)
 }
+
+// patientKey is the context key for the patient queries are scoped to
+const patientKey contextKey = "patient_id"
+
+// WithPatient scopes the glucose and sensor queries made with ctx to one
+// patient (LibreLinkUp patient ID). Rows saved with ctx without a patient are
+// stored for it. Without a patient, queries cover all patients.
+func WithPatient(ctx context.Context, patientID string) context.Context {
+	return context.WithValue(ctx, patientKey, patientID)
+}
+
+// PatientFromContext returns the patient ctx is scoped to ("" = all patients).
+func PatientFromContext(ctx context.Context) string {
+	patientID, _ := ctx.Value(patientKey).(string)
+	return patientID
+}
+
+// scopePatient restricts query to the patient of ctx, if any.
+func scopePatient(ctx context.Context, query *gorm.DB) *gorm.DB {
+	if patientID := PatientFromContext(ctx); patientID != "" {
+		return query.Where("patient_id = ?", patientID)
+	}
+	return query
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// DailySummaryRepositoryGORM is the GORM implementation of DailySummaryRepository.
+type DailySummaryRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewDailySummaryRepository creates a new DailySummaryRepository.
+func NewDailySummaryRepository(db *gorm.DB) *DailySummaryRepositoryGORM {
+	return &DailySummaryRepositoryGORM{db: db}
+}
+
+// Upsert stores summaries, replacing those of the same patient and day.
+func (r *DailySummaryRepositoryGORM) Upsert(ctx context.Context, summaries []*domain.DailySummary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	db := txOrDefault(ctx, r.db)
+
+	// ON CONFLICT (patient_id, date) DO UPDATE
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "patient_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at", "count", "average", "average_mg_dl", "min_mg_dl", "max_mg_dl",
+			"time_in_range", "time_below_range", "time_above_range", "low_count", "high_count",
+			"gmi", "target_low_mg_dl", "target_high_mg_dl",
+		}),
+	}).CreateInBatches(summaries, 100).Error
+}
+
+// FindByDateRange returns the summaries of the days from from to to
+// (YYYY-MM-DD, inclusive), oldest first.
+func (r *DailySummaryRepositoryGORM) FindByDateRange(ctx context.Context, from, to string) ([]*domain.DailySummary, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var summaries []*domain.DailySummary
+	result := scopePatient(ctx, db).
+		Where("date >= ? AND date <= ?", from, to).
+		Order("date ASC, patient_id ASC").
+		Find(&summaries)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return summaries, nil
+}
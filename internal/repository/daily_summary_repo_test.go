@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+func TestDailySummaryRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewDailySummaryRepository(db)
+	ctx := context.Background()
+
+	summaries := []*domain.DailySummary{
+		{Date: "2026-03-01", Count: 96, AverageMgDl: 120, TimeInRange: 80},
+		{Date: "2026-03-02", Count: 96, AverageMgDl: 140, TimeInRange: 70},
+		{Date: "2026-03-03", Count: 48, AverageMgDl: 110, TimeInRange: 90},
+		{PatientID: "other", Date: "2026-03-02", Count: 96, AverageMgDl: 160},
+	}
+	if err := repo.Upsert(ctx, summaries); err != nil {
+		t.Fatalf("failed to store summaries: %v", err)
+	}
+
+	// The same day is replaced, not duplicated
+	updated := &domain.DailySummary{Date: "2026-03-03", Count: 96, AverageMgDl: 115, TimeInRange: 85}
+	if err := repo.Upsert(ctx, []*domain.DailySummary{updated}); err != nil {
+		t.Fatalf("failed to update the summary: %v", err)
+	}
+
+	found, err := repo.FindByDateRange(ctx, "2026-03-02", "2026-03-03")
+	if err != nil {
+		t.Fatalf("failed to find summaries: %v", err)
+	}
+	if len(found) != 3 {
+		t.Fatalf("expected 3 summaries, got %d", len(found))
+	}
+	if last := found[2]; last.Date != "2026-03-03" || last.Count != 96 || last.AverageMgDl != 115 {
+		t.Errorf("expected the updated summary of 2026-03-03, got %+v", last)
+	}
+
+	// Patient scope
+	found, err = repo.FindByDateRange(WithPatient(ctx, "other"), "2026-03-01", "2026-03-31")
+	if err != nil || len(found) != 1 || found[0].AverageMgDl != 160 {
+		t.Errorf("expected the summary of the other patient, got %+v (%v)", found, err)
+	}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// DashboardRepositoryGORM is the GORM implementation of DashboardRepository.
+// This is a singleton repository - only one dashboard config record is expected.
+type DashboardRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewDashboardRepository creates a new DashboardRepository.
+func NewDashboardRepository(db *gorm.DB) *DashboardRepositoryGORM {
+	return &DashboardRepositoryGORM{db: db}
+}
+
+// Save creates or updates the dashboard config (singleton).
+func (r *DashboardRepositoryGORM) Save(ctx context.Context, c *domain.DashboardConfig) error {
+	db := txOrDefault(ctx, r.db)
+
+	// For singleton, we always update the first record or create if doesn't exist
+	var existing domain.DashboardConfig
+	result := db.Session(&gorm.Session{Logger: logger.Discard}).First(&existing)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// No record exists, create new one
+			return db.Create(c).Error
+		}
+		return result.Error
+	}
+
+	// Record exists, update it
+	c.ID = existing.ID // Preserve the ID
+	return db.Save(c).Error
+}
+
+// Find returns the dashboard config (only one record expected).
+func (r *DashboardRepositoryGORM) Find(ctx context.Context) (*domain.DashboardConfig, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var config domain.DashboardConfig
+	result := db.First(&config)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &config, nil
+}
@@ -31,7 +31,8 @@ func (r *DeviceRepositoryGORM) Save(ctx context.Context, d *domain.DeviceInfo) e
 		Columns: []clause.Column{{Name: "device_id"}},
 		DoUpdates: clause.AssignmentColumns([]string{
 			"device_type_id", "app_version", "alarms_enabled", "high_limit",
-			"low_limit", "fixed_low_threshold", "last_update", "limit_enabled",
+			"low_limit", "fixed_low_threshold", "fixed_low_alarm_mg_per_dl",
+			"fixed_low_alarm_mmol_per_l", "alarm_rules", "last_update", "limit_enabled",
 			"updated_at",
 		}),
 	}).Create(d)
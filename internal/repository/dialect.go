@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// The statistics queries compute their aggregates in SQL. The expressions
+// below differ between SQLite and PostgreSQL; everything else in those
+// queries is portable SQL.
+
+// isPostgres reports whether db is connected to PostgreSQL (else SQLite).
+func isPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
+
+// boolExpr returns the condition matching rows where column equals value.
+// SQLite stores booleans as 0/1 integers, PostgreSQL has a boolean type that
+// cannot be compared to an integer.
+func boolExpr(postgres bool, column string, value bool) string {
+	if postgres {
+		return fmt.Sprintf("%s = %t", column, value)
+	}
+	if value {
+		return column + " = 1"
+	}
+	return column + " = 0"
+}
+
+// daysBetweenExpr returns the number of days, with the fraction, between the
+// start and end timestamp columns.
+func daysBetweenExpr(postgres bool, start, end string) string {
+	if postgres {
+		return fmt.Sprintf("(EXTRACT(EPOCH FROM (%s - %s)) / 86400.0)", end, start)
+	}
+	return fmt.Sprintf("(julianday(%s) - julianday(%s))", end, start)
+}
+
+// localHourExpr returns the hour of the timestamp column shifted by offset
+// seconds from UTC. PostgreSQL converts to UTC first so the result does not
+// depend on the session time zone.
+func localHourExpr(postgres bool, column string, offset int) string {
+	if postgres {
+		return fmt.Sprintf("CAST(EXTRACT(HOUR FROM (%s AT TIME ZONE 'UTC') + INTERVAL '%d seconds') AS INTEGER)", column, offset)
+	}
+	return fmt.Sprintf("CAST(strftime('%%H', %s, '%+d seconds') AS INTEGER)", column, offset)
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// GlucoseEventRepositoryGORM is the GORM implementation of GlucoseEventRepository.
+type GlucoseEventRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewGlucoseEventRepository creates a new GlucoseEventRepository.
+func NewGlucoseEventRepository(db *gorm.DB) *GlucoseEventRepositoryGORM {
+	return &GlucoseEventRepositoryGORM{db: db}
+}
+
+// CreateBatch inserts detected events. Events without a patient are stored
+// for the patient of ctx.
+func (r *GlucoseEventRepositoryGORM) CreateBatch(ctx context.Context, events []*domain.GlucoseEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	db := txOrDefault(ctx, r.db)
+	for _, e := range events {
+		if e.PatientID == "" {
+			e.PatientID = PatientFromContext(ctx)
+		}
+	}
+	return db.CreateInBatches(events, 100).Error
+}
+
+// DeleteFrom deletes the events started at or after since.
+func (r *GlucoseEventRepositoryGORM) DeleteFrom(ctx context.Context, since time.Time) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	result := scopePatient(ctx, db).Where("start_time >= ?", since).Delete(&domain.GlucoseEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// FindSpanning returns the event started before t and ending at or after t.
+// Returns persistence.ErrNotFound if there is none.
+func (r *GlucoseEventRepositoryGORM) FindSpanning(ctx context.Context, t time.Time) (*domain.GlucoseEvent, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var event domain.GlucoseEvent
+	result := scopePatient(ctx, db).Where("start_time < ? AND end_time >= ?", t, t).
+		Order("start_time ASC").
+		First(&event)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &event, nil
+}
+
+// FindWithFilters returns events matching filters with pagination, newest first.
+func (r *GlucoseEventRepositoryGORM) FindWithFilters(ctx context.Context, filters GlucoseEventFilters, limit, offset int) ([]*domain.GlucoseEvent, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var events []*domain.GlucoseEvent
+	result := applyGlucoseEventFilters(scopePatient(ctx, db.Model(&domain.GlucoseEvent{})), filters).
+		Order("start_time DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return events, nil
+}
+
+// CountWithFilters returns total count of events matching filters.
+func (r *GlucoseEventRepositoryGORM) CountWithFilters(ctx context.Context, filters GlucoseEventFilters) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var count int64
+	result := applyGlucoseEventFilters(scopePatient(ctx, db.Model(&domain.GlucoseEvent{})), filters).Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// applyGlucoseEventFilters adds the WHERE clauses of filters to query.
+func applyGlucoseEventFilters(query *gorm.DB, filters GlucoseEventFilters) *gorm.DB {
+	if filters.StartTime != nil {
+		query = query.Where("start_time >= ?", *filters.StartTime)
+	}
+	if filters.EndTime != nil {
+		query = query.Where("start_time <= ?", *filters.EndTime)
+	}
+	if filters.Type != nil {
+		query = query.Where("type = ?", *filters.Type)
+	}
+	return query
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+func TestGlucoseEventRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewGlucoseEventRepository(db)
+	ctx := context.Background()
+
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	events := []*domain.GlucoseEvent{
+		{Type: domain.GlucoseEventTypeLow, StartTime: now.Add(-10 * time.Hour), EndTime: now.Add(-9 * time.Hour), DurationMinutes: 60, Readings: 5, ExtremeMgDl: 55, ThresholdMgDl: 70},
+		{Type: domain.GlucoseEventTypeHigh, StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-time.Hour), DurationMinutes: 120, Readings: 9, ExtremeMgDl: 260, ThresholdMgDl: 180},
+		{Type: domain.GlucoseEventTypeLow, StartTime: now.Add(-30 * time.Minute), EndTime: now, DurationMinutes: 30, Readings: 3, ExtremeMgDl: 62, ThresholdMgDl: 70, Ongoing: true},
+	}
+	if err := repo.CreateBatch(ctx, events); err != nil {
+		t.Fatalf("failed to create events: %v", err)
+	}
+	if err := repo.CreateBatch(ctx, nil); err != nil {
+		t.Errorf("expected no error without events, got %v", err)
+	}
+
+	// Newest first, filtered by type and start time
+	low := domain.GlucoseEventTypeLow
+	list, err := repo.FindWithFilters(ctx, GlucoseEventFilters{Type: &low}, 10, 0)
+	if err != nil || len(list) != 2 || !list[0].Ongoing {
+		t.Fatalf("expected the 2 low events, ongoing first, got %+v (%v)", list, err)
+	}
+	start := now.Add(-4 * time.Hour)
+	count, err := repo.CountWithFilters(ctx, GlucoseEventFilters{StartTime: &start})
+	if err != nil || count != 2 {
+		t.Errorf("expected 2 events started in the last 4 hours, got %d (%v)", count, err)
+	}
+
+	spanning, err := repo.FindSpanning(ctx, now.Add(-2*time.Hour))
+	if err != nil || spanning.Type != domain.GlucoseEventTypeHigh {
+		t.Errorf("expected the high event, got %+v (%v)", spanning, err)
+	}
+	if _, err := repo.FindSpanning(ctx, now.Add(-5*time.Hour)); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound between events, got %v", err)
+	}
+
+	deleted, err := repo.DeleteFrom(ctx, now.Add(-3*time.Hour))
+	if err != nil || deleted != 2 {
+		t.Errorf("expected 2 deleted events, got %d (%v)", deleted, err)
+	}
+	if count, _ := repo.CountWithFilters(ctx, GlucoseEventFilters{}); count != 1 {
+		t.Errorf("expected the oldest event kept, got %d events", count)
+	}
+
+	// Events are stored and read per patient
+	patientCtx := WithPatient(ctx, "6a1f3a4e-0000-4000-8000-000000000002")
+	other := &domain.GlucoseEvent{Type: domain.GlucoseEventTypeLow, StartTime: now.Add(-10 * time.Hour), EndTime: now.Add(-9 * time.Hour), DurationMinutes: 60, Readings: 4, ExtremeMgDl: 58, ThresholdMgDl: 70}
+	if err := repo.CreateBatch(patientCtx, []*domain.GlucoseEvent{other}); err != nil {
+		t.Fatalf("expected an event of another patient at the same time, got %v", err)
+	}
+	if other.PatientID != "6a1f3a4e-0000-4000-8000-000000000002" {
+		t.Errorf("expected the event stored for the patient of ctx, got %q", other.PatientID)
+	}
+	if list, _ := repo.FindWithFilters(patientCtx, GlucoseEventFilters{}, 10, 0); len(list) != 1 || list[0].ExtremeMgDl != 58 {
+		t.Errorf("expected the patient's event only, got %+v", list)
+	}
+	if deleted, _ := repo.DeleteFrom(patientCtx, time.Time{}); deleted != 1 {
+		t.Errorf("expected the patient's event deleted, got %d", deleted)
+	}
+	if count, _ := repo.CountWithFilters(ctx, GlucoseEventFilters{}); count != 1 {
+		t.Errorf("expected the other patient's event kept, got %d events", count)
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/R4yL-dev/glcmd/internal/domain"
 	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/utils/filterexpr"
 )
 
 // GlucoseRepositoryGORM is the GORM implementation of GlucoseRepository.
@@ -26,10 +28,13 @@ func NewGlucoseRepository(db *gorm.DB) *GlucoseRepositoryGORM {
 // Returns (true, nil) if inserted, (false, nil) if duplicate was ignored.
 func (r *GlucoseRepositoryGORM) Save(ctx context.Context, m *domain.GlucoseMeasurement) (bool, error) {
 	db := txOrDefault(ctx, r.db)
+	if m.PatientID == "" {
+		m.PatientID = PatientFromContext(ctx)
+	}
 
-	// ON CONFLICT DO NOTHING - ignore duplicates based on unique factory_timestamp
+	// ON CONFLICT DO NOTHING - ignore duplicates based on unique (patient_id, factory_timestamp)
 	result := db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "factory_timestamp"}},
+		Columns:   []clause.Column{{Name: "patient_id"}, {Name: "factory_timestamp"}},
 		DoNothing: true,
 	}).Create(m)
 
@@ -43,7 +48,7 @@ func (r *GlucoseRepositoryGORM) FindLatest(ctx context.Context) (*domain.Glucose
 	db := txOrDefault(ctx, r.db)
 
 	var measurement domain.GlucoseMeasurement
-	result := db.Order("timestamp DESC").First(&measurement)
+	result := scopePatient(ctx, db).Order("timestamp DESC").First(&measurement)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -60,7 +65,7 @@ func (r *GlucoseRepositoryGORM) FindAll(ctx context.Context) ([]*domain.GlucoseM
 	db := txOrDefault(ctx, r.db)
 
 	var measurements []*domain.GlucoseMeasurement
-	result := db.Order("timestamp DESC").Find(&measurements)
+	result := scopePatient(ctx, db).Order("timestamp DESC").Find(&measurements)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -74,7 +79,7 @@ func (r *GlucoseRepositoryGORM) FindByTimeRange(ctx context.Context, start, end
 	db := txOrDefault(ctx, r.db)
 
 	var measurements []*domain.GlucoseMeasurement
-	result := db.
+	result := scopePatient(ctx, db).
 		Where("timestamp >= ? AND timestamp <= ?", start, end).
 		Order("timestamp DESC").
 		Find(&measurements)
@@ -90,21 +95,7 @@ func (r *GlucoseRepositoryGORM) FindByTimeRange(ctx context.Context, start, end
 func (r *GlucoseRepositoryGORM) FindWithFilters(ctx context.Context, filters GlucoseFilters, limit, offset int) ([]*domain.GlucoseMeasurement, error) {
 	db := txOrDefault(ctx, r.db)
 
-	query := db.Model(&domain.GlucoseMeasurement{})
-
-	// Apply filters
-	if filters.StartTime != nil {
-		query = query.Where("timestamp >= ?", *filters.StartTime)
-	}
-	if filters.EndTime != nil {
-		query = query.Where("timestamp <= ?", *filters.EndTime)
-	}
-	if filters.Color != nil {
-		query = query.Where("measurement_color = ?", *filters.Color)
-	}
-	if filters.Type != nil {
-		query = query.Where("type = ?", *filters.Type)
-	}
+	query := applyGlucoseFilters(scopePatient(ctx, db.Model(&domain.GlucoseMeasurement{})), filters)
 
 	var measurements []*domain.GlucoseMeasurement
 	result := query.
@@ -124,9 +115,91 @@ func (r *GlucoseRepositoryGORM) FindWithFilters(ctx context.Context, filters Glu
 func (r *GlucoseRepositoryGORM) CountWithFilters(ctx context.Context, filters GlucoseFilters) (int64, error) {
 	db := txOrDefault(ctx, r.db)
 
-	query := db.Model(&domain.GlucoseMeasurement{})
+	query := applyGlucoseFilters(scopePatient(ctx, db.Model(&domain.GlucoseMeasurement{})), filters)
+
+	var count int64
+	result := query.Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// StreamWithFilters calls fn for each measurement matching filters, oldest first.
+// Rows are read one at a time from the database cursor.
+func (r *GlucoseRepositoryGORM) StreamWithFilters(ctx context.Context, filters GlucoseFilters, fn func(*domain.GlucoseMeasurement) error) error {
+	db := txOrDefault(ctx, r.db)
+
+	rows, err := applyGlucoseFilters(scopePatient(ctx, db.Model(&domain.GlucoseMeasurement{})), filters).
+		Order("timestamp ASC").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m domain.GlucoseMeasurement
+		if err := db.ScanRows(rows, &m); err != nil {
+			return err
+		}
+		if err := fn(&m); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// AssignPatient assigns the measurements stored without a patient to patientID.
+// Returns the number of measurements assigned.
+func (r *GlucoseRepositoryGORM) AssignPatient(ctx context.Context, patientID string) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Model(&domain.GlucoseMeasurement{}).
+		Where("patient_id = ?", "").
+		Update("patient_id", patientID)
+
+	return result.RowsAffected, result.Error
+}
+
+// DeleteBefore deletes the measurements taken before before.
+func (r *GlucoseRepositoryGORM) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	result := scopePatient(ctx, db).
+		Where("timestamp < ?", before).
+		Delete(&domain.GlucoseMeasurement{})
+
+	return result.RowsAffected, result.Error
+}
+
+// FindEarliestStoredSince returns the earliest taken of the measurements
+// stored at or after since.
+// Returns persistence.ErrNotFound if there is none.
+func (r *GlucoseRepositoryGORM) FindEarliestStoredSince(ctx context.Context, since time.Time) (*domain.GlucoseMeasurement, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var measurement domain.GlucoseMeasurement
+	result := scopePatient(ctx, db).
+		Where("created_at >= ?", since).
+		Order("timestamp ASC").
+		First(&measurement)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &measurement, nil
+}
 
-	// Apply filters
+// applyGlucoseFilters adds the conditions of filters to query.
+func applyGlucoseFilters(query *gorm.DB, filters GlucoseFilters) *gorm.DB {
 	if filters.StartTime != nil {
 		query = query.Where("timestamp >= ?", *filters.StartTime)
 	}
@@ -139,15 +212,62 @@ func (r *GlucoseRepositoryGORM) CountWithFilters(ctx context.Context, filters Gl
 	if filters.Type != nil {
 		query = query.Where("type = ?", *filters.Type)
 	}
+	if filters.Query != nil {
+		condition, args := filters.Query.SQL(glucoseQueryColumn(isPostgres(query)))
+		query = query.Where(condition, args...)
+	}
+	return query
+}
 
-	var count int64
-	result := query.Count(&count)
+// GetHistogram returns the number of measurements matching filters per value bucket.
+// Buckets are sorted by value; empty buckets are not returned.
+func (r *GlucoseRepositoryGORM) GetHistogram(ctx context.Context, filters GlucoseFilters, bucketMgDl int) ([]GlucoseHistogramBucket, error) {
+	db := txOrDefault(ctx, r.db)
 
-	if result.Error != nil {
-		return 0, result.Error
-	}
+	// Integer division floors positive values to the start of their bucket
+	query := applyGlucoseFilters(scopePatient(ctx, db.Model(&domain.GlucoseMeasurement{})), filters).
+		Select("(value_in_mg_per_dl / ?) * ? AS start_mg_dl, COUNT(*) AS count", bucketMgDl, bucketMgDl)
 
-	return count, nil
+	buckets := []GlucoseHistogramBucket{}
+	result := query.Group("start_mg_dl").Order("start_mg_dl ASC").Scan(&buckets)
+
+	return buckets, result.Error
+}
+
+// GlucoseQueryFields lists the fields of glucose filter expressions.
+var GlucoseQueryFields = map[string]filterexpr.Kind{
+	"value_mgdl": filterexpr.Int,
+	"value_mmol": filterexpr.Float,
+	"color":      filterexpr.Int,
+	"type":       filterexpr.Int,
+	"trend":      filterexpr.Int,
+	"hour":       filterexpr.Int,
+}
+
+// glucoseQueryColumn returns the SQL expression of each GlucoseQueryFields field.
+// hour is the hour of the reading in local time. Timestamps are stored in
+// UTC and SQLite has no time zone support, so the current UTC offset is
+// applied: readings on the other side of a DST change are off by one hour.
+func glucoseQueryColumn(postgres bool) func(field string) string {
+	return func(field string) string {
+		switch field {
+		case "value_mgdl":
+			return "value_in_mg_per_dl"
+		case "value_mmol":
+			return "value"
+		case "color":
+			return "measurement_color"
+		case "type":
+			return "type"
+		case "trend":
+			return "trend_arrow"
+		case "hour":
+			_, offset := time.Now().Zone()
+			return localHourExpr(postgres, "timestamp", offset)
+		default:
+			panic("unknown glucose query field " + field)
+		}
+	}
 }
 
 // parseTimestamp tries to parse a timestamp string in various formats
@@ -176,6 +296,12 @@ func parseTimestamp(s *string) *time.Time {
 	return nil
 }
 
+// varianceExpr computes the population variance of value in a single pass.
+// Values are shifted by a typical glucose level (7 mmol/L) before squaring:
+// the variance is unchanged, but E[X²] and E[X]² stay small so their
+// difference does not lose precision over large sets of readings.
+const varianceExpr = `COALESCE(ABS(AVG((value - 7.0) * (value - 7.0)) - AVG(value - 7.0) * AVG(value - 7.0)), 0)`
+
 // statisticsRawResult is used for scanning SQL results with string timestamps
 type statisticsRawResult struct {
 	Count           int64
@@ -200,8 +326,10 @@ type statisticsRawResult struct {
 func (r *GlucoseRepositoryGORM) GetStatistics(ctx context.Context, filters GlucoseStatisticsFilters) (*GlucoseStatisticsResult, error) {
 	db := txOrDefault(ctx, r.db)
 
+	postgres := isPostgres(db)
+
 	// Base aggregation query
-	// Variance = E[X²] - E[X]², SQRT computed in Go for SQLite compatibility
+	// SQRT of the variance computed in Go for SQLite compatibility
 	selectClause := `
 		COUNT(*) as count,
 		COALESCE(AVG(value), 0) as average,
@@ -210,10 +338,10 @@ func (r *GlucoseRepositoryGORM) GetStatistics(ctx context.Context, filters Gluco
 		COALESCE(MIN(value_in_mg_per_dl), 0) as min_mg_dl,
 		COALESCE(MAX(value), 0) as max,
 		COALESCE(MAX(value_in_mg_per_dl), 0) as max_mg_dl,
-		COALESCE(ABS(AVG(value * value) - AVG(value) * AVG(value)), 0) as variance,
+		` + varianceExpr + ` as variance,
 		COALESCE(SUM(CASE WHEN measurement_color = 1 THEN 1 ELSE 0 END), 0) as normal_count,
-		COALESCE(SUM(CASE WHEN measurement_color IN (2, 3) AND is_low = 1 THEN 1 ELSE 0 END), 0) as low_count,
-		COALESCE(SUM(CASE WHEN measurement_color IN (2, 3) AND is_low = 0 THEN 1 ELSE 0 END), 0) as high_count,
+		COALESCE(SUM(CASE WHEN measurement_color IN (2, 3) AND ` + boolExpr(postgres, "is_low", true) + ` THEN 1 ELSE 0 END), 0) as low_count,
+		COALESCE(SUM(CASE WHEN measurement_color IN (2, 3) AND ` + boolExpr(postgres, "is_low", false) + ` THEN 1 ELSE 0 END), 0) as high_count,
 		MIN(timestamp) as first_timestamp,
 		MAX(timestamp) as last_timestamp
 	`
@@ -241,16 +369,24 @@ func (r *GlucoseRepositoryGORM) GetStatistics(ctx context.Context, filters Gluco
 		query = query.Select(selectClause)
 	}
 
-	// Apply time filters
-	if filters.StartTime != nil {
-		query = query.Where("timestamp >= ?", *filters.StartTime)
-	}
-	if filters.EndTime != nil {
-		query = query.Where("timestamp <= ?", *filters.EndTime)
+	// Apply patient and time filters
+	applyFilters := func(query *gorm.DB) *gorm.DB {
+		query = scopePatient(ctx, query)
+		if filters.StartTime != nil {
+			query = query.Where("timestamp >= ?", *filters.StartTime)
+		}
+		if filters.EndTime != nil {
+			query = query.Where("timestamp <= ?", *filters.EndTime)
+		}
+		if filters.Query != nil {
+			condition, args := filters.Query.SQL(glucoseQueryColumn(postgres))
+			query = query.Where(condition, args...)
+		}
+		return query
 	}
 
 	var raw statisticsRawResult
-	if err := query.Scan(&raw).Error; err != nil {
+	if err := applyFilters(query).Scan(&raw).Error; err != nil {
 		return nil, err
 	}
 
@@ -276,5 +412,32 @@ func (r *GlucoseRepositoryGORM) GetStatistics(ctx context.Context, filters Gluco
 	result.FirstTimestamp = parseTimestamp(raw.FirstTimestamp)
 	result.LastTimestamp = parseTimestamp(raw.LastTimestamp)
 
+	// Count secondary target bands, whose number varies, in a second query
+	if len(filters.Bands) > 0 {
+		columns, args := targetBandColumns(filters.Bands)
+		query := db.Model(&domain.GlucoseMeasurement{}).Select(columns, args...)
+
+		result.BandCounts = make([]int64, len(filters.Bands))
+		dest := make([]any, len(result.BandCounts))
+		for i := range result.BandCounts {
+			dest[i] = &result.BandCounts[i]
+		}
+		if err := applyFilters(query).Row().Scan(dest...); err != nil {
+			return nil, err
+		}
+	}
+
 	return result, nil
 }
+
+// targetBandColumns returns the SQL columns counting the measurements within
+// each band (bounds inclusive), separated by commas, and their arguments.
+func targetBandColumns(bands []domain.TargetBand) (string, []any) {
+	columns := make([]string, len(bands))
+	args := make([]any, 0, 2*len(bands))
+	for i, band := range bands {
+		columns[i] = "COALESCE(SUM(CASE WHEN value_in_mg_per_dl >= ? AND value_in_mg_per_dl <= ? THEN 1 ELSE 0 END), 0)"
+		args = append(args, band.LowMgDl, band.HighMgDl)
+	}
+	return strings.Join(columns, ", "), args
+}
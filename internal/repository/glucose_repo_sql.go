@@ -0,0 +1,500 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// glucoseColumns lists the glucose_measurements columns, in scan order.
+const glucoseColumns = `id, created_at, patient_id, factory_timestamp, timestamp, value, value_in_mg_per_dl,
+	trend_arrow, trend_message, measurement_color, glucose_units, is_high, is_low, type, fetch_cycle_id, fetched_at, fetch_source`
+
+const (
+	glucoseInsertQuery = `INSERT INTO glucose_measurements (created_at, patient_id, factory_timestamp, timestamp, value,
+	value_in_mg_per_dl, trend_arrow, trend_message, measurement_color, glucose_units, is_high, is_low, type,
+	fetch_cycle_id, fetched_at, fetch_source)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (patient_id, factory_timestamp) DO NOTHING
+	RETURNING id`
+
+	glucoseLatestQuery = `SELECT ` + glucoseColumns + ` FROM glucose_measurements
+	ORDER BY timestamp DESC LIMIT 1`
+)
+
+// GlucoseRepositorySQL is a database/sql implementation of GlucoseRepository.
+//
+// It shares the tables created by GORM migrations but skips GORM's reflection
+// on the hot paths: the per-minute insert and the latest-measurement lookup
+// use statements prepared once at startup. It targets low-end hardware
+// (e.g. Raspberry Pi Zero) where that overhead is measurable.
+type GlucoseRepositorySQL struct {
+	db       *sql.DB
+	gormDB   *gorm.DB // Used only to join transactions started by the Unit of Work
+	postgres bool     // Rebind ? placeholders to $n, PostgreSQL expressions
+
+	insertStmt *sql.Stmt
+	latestStmt *sql.Stmt
+}
+
+// NewGlucoseRepositorySQL creates a GlucoseRepositorySQL on the connection pool of db
+// and prepares its statements. Call Close to release them.
+func NewGlucoseRepositorySQL(db *gorm.DB) (*GlucoseRepositorySQL, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	r := &GlucoseRepositorySQL{
+		db:       sqlDB,
+		gormDB:   db,
+		postgres: isPostgres(db),
+	}
+
+	if r.insertStmt, err = sqlDB.Prepare(r.rebind(glucoseInsertQuery)); err != nil {
+		return nil, fmt.Errorf("failed to prepare insert statement: %w", err)
+	}
+	if r.latestStmt, err = sqlDB.Prepare(glucoseLatestQuery); err != nil {
+		r.insertStmt.Close()
+		return nil, fmt.Errorf("failed to prepare latest statement: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close releases the prepared statements. The connection pool is left open.
+func (r *GlucoseRepositorySQL) Close() error {
+	return errors.Join(r.insertStmt.Close(), r.latestStmt.Close())
+}
+
+// Save creates or ignores a measurement (duplicate timestamps are silently ignored).
+// Returns (true, nil) if inserted, (false, nil) if duplicate was ignored.
+func (r *GlucoseRepositorySQL) Save(ctx context.Context, m *domain.GlucoseMeasurement) (bool, error) {
+	createdAt := m.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now().UTC()
+	}
+	if m.PatientID == "" {
+		m.PatientID = PatientFromContext(ctx)
+	}
+
+	args := []any{
+		createdAt, m.PatientID, m.FactoryTimestamp, m.Timestamp, m.Value,
+		m.ValueInMgPerDl, m.TrendArrow, m.TrendMessage, m.GlucoseColor, m.GlucoseUnits, m.IsHigh, m.IsLow, m.Type,
+		m.FetchCycleID, m.FetchedAt, m.FetchSource,
+	}
+
+	var row *sql.Row
+	if tx := r.tx(ctx); tx != nil {
+		row = tx.QueryRowContext(ctx, r.rebind(glucoseInsertQuery), args...)
+	} else {
+		row = r.insertStmt.QueryRowContext(ctx, args...)
+	}
+
+	// RETURNING yields no row when the conflict clause skipped the insert
+	var id uint
+	if err := row.Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	m.ID = id
+	m.CreatedAt = createdAt
+	return true, nil
+}
+
+// FindLatest returns the most recent measurement by timestamp.
+// The prepared statement only serves queries covering all patients.
+func (r *GlucoseRepositorySQL) FindLatest(ctx context.Context) (*domain.GlucoseMeasurement, error) {
+	var row *sql.Row
+	if patientID := PatientFromContext(ctx); patientID != "" {
+		row = r.queryRow(ctx, `SELECT `+glucoseColumns+` FROM glucose_measurements
+			WHERE patient_id = ? ORDER BY timestamp DESC LIMIT 1`, patientID)
+	} else if tx := r.tx(ctx); tx != nil {
+		row = tx.QueryRowContext(ctx, glucoseLatestQuery)
+	} else {
+		row = r.latestStmt.QueryRowContext(ctx)
+	}
+
+	m, err := scanGlucose(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// FindAll returns all measurements ordered by timestamp descending.
+func (r *GlucoseRepositorySQL) FindAll(ctx context.Context) ([]*domain.GlucoseMeasurement, error) {
+	where, args := glucoseFilterClause(ctx, GlucoseFilters{}, r.postgres)
+	return r.queryGlucose(ctx, `SELECT `+glucoseColumns+` FROM glucose_measurements`+where+` ORDER BY timestamp DESC`, args...)
+}
+
+// FindByTimeRange returns measurements within a time range (inclusive).
+func (r *GlucoseRepositorySQL) FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error) {
+	where, args := glucoseFilterClause(ctx, GlucoseFilters{StartTime: &start, EndTime: &end}, r.postgres)
+	return r.queryGlucose(ctx, `SELECT `+glucoseColumns+` FROM glucose_measurements`+where+` ORDER BY timestamp DESC`, args...)
+}
+
+// FindWithFilters returns measurements matching filters with pagination.
+func (r *GlucoseRepositorySQL) FindWithFilters(ctx context.Context, filters GlucoseFilters, limit, offset int) ([]*domain.GlucoseMeasurement, error) {
+	where, args := glucoseFilterClause(ctx, filters, r.postgres)
+	args = append(args, limit, offset)
+
+	return r.queryGlucose(ctx,
+		`SELECT `+glucoseColumns+` FROM glucose_measurements`+where+` ORDER BY timestamp DESC LIMIT ? OFFSET ?`,
+		args...,
+	)
+}
+
+// CountWithFilters returns total count of measurements matching filters.
+func (r *GlucoseRepositorySQL) CountWithFilters(ctx context.Context, filters GlucoseFilters) (int64, error) {
+	where, args := glucoseFilterClause(ctx, filters, r.postgres)
+
+	var count int64
+	err := r.queryRow(ctx, `SELECT COUNT(*) FROM glucose_measurements`+where, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// StreamWithFilters calls fn for each measurement matching filters, oldest first.
+// Rows are read one at a time from the database cursor.
+func (r *GlucoseRepositorySQL) StreamWithFilters(ctx context.Context, filters GlucoseFilters, fn func(*domain.GlucoseMeasurement) error) error {
+	where, args := glucoseFilterClause(ctx, filters, r.postgres)
+
+	rows, err := r.query(ctx, `SELECT `+glucoseColumns+` FROM glucose_measurements`+where+` ORDER BY timestamp ASC`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := scanGlucose(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetStatistics returns aggregated statistics computed by SQL.
+// The query matches GlucoseRepositoryGORM.GetStatistics.
+func (r *GlucoseRepositorySQL) GetStatistics(ctx context.Context, filters GlucoseStatisticsFilters) (*GlucoseStatisticsResult, error) {
+	var args []any
+
+	query := `SELECT
+		COUNT(*),
+		COALESCE(AVG(value), 0),
+		COALESCE(AVG(value_in_mg_per_dl), 0),
+		COALESCE(MIN(value), 0),
+		COALESCE(MIN(value_in_mg_per_dl), 0),
+		COALESCE(MAX(value), 0),
+		COALESCE(MAX(value_in_mg_per_dl), 0),
+		` + varianceExpr + `,
+		COALESCE(SUM(CASE WHEN measurement_color = 1 THEN 1 ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN measurement_color IN (2, 3) AND ` + boolExpr(r.postgres, "is_low", true) + ` THEN 1 ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN measurement_color IN (2, 3) AND ` + boolExpr(r.postgres, "is_low", false) + ` THEN 1 ELSE 0 END), 0),
+		MIN(timestamp),
+		MAX(timestamp)`
+
+	withTIR := filters.TargetLowMgDl != nil && filters.TargetHighMgDl != nil
+	if withTIR {
+		query += `,
+		COALESCE(SUM(CASE WHEN value_in_mg_per_dl < ? THEN 1 ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN value_in_mg_per_dl > ? THEN 1 ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN value_in_mg_per_dl >= ? AND value_in_mg_per_dl <= ? THEN 1 ELSE 0 END), 0)`
+		args = append(args, *filters.TargetLowMgDl, *filters.TargetHighMgDl, *filters.TargetLowMgDl, *filters.TargetHighMgDl)
+	}
+
+	if len(filters.Bands) > 0 {
+		columns, bandArgs := targetBandColumns(filters.Bands)
+		query += ",\n\t\t" + columns
+		args = append(args, bandArgs...)
+	}
+
+	where, whereArgs := glucoseFilterClause(ctx, GlucoseFilters{StartTime: filters.StartTime, EndTime: filters.EndTime, Query: filters.Query}, r.postgres)
+	query += ` FROM glucose_measurements` + where
+	args = append(args, whereArgs...)
+
+	var (
+		result      GlucoseStatisticsResult
+		first, last sql.NullString
+	)
+	dest := []any{
+		&result.Count, &result.Average, &result.AverageMgDl,
+		&result.Min, &result.MinMgDl, &result.Max, &result.MaxMgDl, &result.Variance,
+		&result.NormalCount, &result.LowCount, &result.HighCount,
+		&first, &last,
+	}
+	if withTIR {
+		dest = append(dest, &result.BelowRangeCount, &result.AboveRangeCount, &result.InRangeCount)
+	}
+	if len(filters.Bands) > 0 {
+		result.BandCounts = make([]int64, len(filters.Bands))
+		for i := range result.BandCounts {
+			dest = append(dest, &result.BandCounts[i])
+		}
+	}
+
+	if err := r.queryRow(ctx, query, args...).Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	// Parse timestamps (SQLite returns MIN/MAX of datetime columns as strings)
+	result.FirstTimestamp = parseTimestamp(&first.String)
+	result.LastTimestamp = parseTimestamp(&last.String)
+
+	return &result, nil
+}
+
+// GetHistogram returns the number of measurements matching filters per value bucket.
+// Buckets are sorted by value; empty buckets are not returned.
+func (r *GlucoseRepositorySQL) GetHistogram(ctx context.Context, filters GlucoseFilters, bucketMgDl int) ([]GlucoseHistogramBucket, error) {
+	// Integer division floors positive values to the start of their bucket
+	where, whereArgs := glucoseFilterClause(ctx, filters, r.postgres)
+	query := `SELECT (value_in_mg_per_dl / ?) * ? AS start_mg_dl, COUNT(*)
+		FROM glucose_measurements` + where + `
+		GROUP BY start_mg_dl ORDER BY start_mg_dl`
+	args := append([]any{bucketMgDl, bucketMgDl}, whereArgs...)
+
+	rows, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []GlucoseHistogramBucket{}
+	for rows.Next() {
+		var b GlucoseHistogramBucket
+		if err := rows.Scan(&b.StartMgDl, &b.Count); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+
+	return buckets, rows.Err()
+}
+
+// AssignPatient assigns the measurements stored without a patient to patientID.
+// Returns the number of measurements assigned.
+func (r *GlucoseRepositorySQL) AssignPatient(ctx context.Context, patientID string) (int64, error) {
+	result, err := r.exec(ctx, `UPDATE glucose_measurements SET patient_id = ? WHERE patient_id = ''`, patientID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// DeleteBefore deletes the measurements taken before before.
+func (r *GlucoseRepositorySQL) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	where, args := glucoseFilterClause(ctx, GlucoseFilters{}, r.postgres)
+	if where == "" {
+		where = " WHERE timestamp < ?"
+	} else {
+		where += " AND timestamp < ?"
+	}
+
+	result, err := r.exec(ctx, `DELETE FROM glucose_measurements`+where, append(args, before)...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// FindEarliestStoredSince returns the earliest taken of the measurements
+// stored at or after since.
+// Returns persistence.ErrNotFound if there is none.
+func (r *GlucoseRepositorySQL) FindEarliestStoredSince(ctx context.Context, since time.Time) (*domain.GlucoseMeasurement, error) {
+	where, args := glucoseFilterClause(ctx, GlucoseFilters{}, r.postgres)
+	if where == "" {
+		where = " WHERE created_at >= ?"
+	} else {
+		where += " AND created_at >= ?"
+	}
+
+	m, err := scanGlucose(r.queryRow(ctx, `SELECT `+glucoseColumns+` FROM glucose_measurements`+where+
+		` ORDER BY timestamp ASC LIMIT 1`, append(args, since)...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// glucoseFilterClause builds the WHERE clause and arguments for filters and
+// the patient of ctx. Returns an empty clause when no filter is set.
+func glucoseFilterClause(ctx context.Context, filters GlucoseFilters, postgres bool) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	if patientID := PatientFromContext(ctx); patientID != "" {
+		conditions = append(conditions, "patient_id = ?")
+		args = append(args, patientID)
+	}
+	if filters.StartTime != nil {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, *filters.StartTime)
+	}
+	if filters.EndTime != nil {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, *filters.EndTime)
+	}
+	if filters.Color != nil {
+		conditions = append(conditions, "measurement_color = ?")
+		args = append(args, *filters.Color)
+	}
+	if filters.Type != nil {
+		conditions = append(conditions, "type = ?")
+		args = append(args, *filters.Type)
+	}
+	if filters.Query != nil {
+		condition, queryArgs := filters.Query.SQL(glucoseQueryColumn(postgres))
+		conditions = append(conditions, condition)
+		args = append(args, queryArgs...)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// queryGlucose runs a query returning glucose measurements.
+func (r *GlucoseRepositorySQL) queryGlucose(ctx context.Context, query string, args ...any) ([]*domain.GlucoseMeasurement, error) {
+	rows, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	measurements := []*domain.GlucoseMeasurement{}
+	for rows.Next() {
+		m, err := scanGlucose(rows)
+		if err != nil {
+			return nil, err
+		}
+		measurements = append(measurements, m)
+	}
+
+	return measurements, rows.Err()
+}
+
+// query runs a query returning rows.
+func (r *GlucoseRepositorySQL) query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if tx := r.tx(ctx); tx != nil {
+		return tx.QueryContext(ctx, r.rebind(query), args...)
+	}
+	return r.db.QueryContext(ctx, r.rebind(query), args...)
+}
+
+// queryRow runs a query returning a single row.
+func (r *GlucoseRepositorySQL) queryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	if tx := r.tx(ctx); tx != nil {
+		return tx.QueryRowContext(ctx, r.rebind(query), args...)
+	}
+	return r.db.QueryRowContext(ctx, r.rebind(query), args...)
+}
+
+// exec runs a statement returning no rows.
+func (r *GlucoseRepositorySQL) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if tx := r.tx(ctx); tx != nil {
+		return tx.ExecContext(ctx, r.rebind(query), args...)
+	}
+	return r.db.ExecContext(ctx, r.rebind(query), args...)
+}
+
+// tx returns the connection of the Unit of Work transaction in ctx, or nil.
+func (r *GlucoseRepositorySQL) tx(ctx context.Context) gorm.ConnPool {
+	if tx, ok := ctx.Value(txKey).(*gorm.DB); ok && tx != nil {
+		return tx.Statement.ConnPool
+	}
+	return nil
+}
+
+// rebind converts ? placeholders to the $n form expected by PostgreSQL.
+func (r *GlucoseRepositorySQL) rebind(query string) string {
+	if !r.postgres {
+		return query
+	}
+
+	var sb strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanGlucose scans a row selected with glucoseColumns.
+func scanGlucose(row rowScanner) (*domain.GlucoseMeasurement, error) {
+	var (
+		m            domain.GlucoseMeasurement
+		trendArrow   sql.NullInt64
+		trendMessage sql.NullString
+		fetchCycleID sql.NullString // NULL for rows stored before provenance was recorded
+		fetchedAt    sql.NullTime
+		fetchSource  sql.NullString
+	)
+
+	err := row.Scan(
+		&m.ID, &m.CreatedAt, &m.PatientID, &m.FactoryTimestamp, &m.Timestamp, &m.Value, &m.ValueInMgPerDl,
+		&trendArrow, &trendMessage, &m.GlucoseColor, &m.GlucoseUnits, &m.IsHigh, &m.IsLow, &m.Type,
+		&fetchCycleID, &fetchedAt, &fetchSource,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if trendArrow.Valid {
+		arrow := int(trendArrow.Int64)
+		m.TrendArrow = &arrow
+	}
+	if trendMessage.Valid {
+		m.TrendMessage = &trendMessage.String
+	}
+	m.FetchCycleID = fetchCycleID.String
+	m.FetchSource = fetchSource.String
+	if fetchedAt.Valid {
+		m.FetchedAt = &fetchedAt.Time
+	}
+
+	return &m, nil
+}
@@ -0,0 +1,593 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/utils/filterexpr"
+)
+
+// setupSQLTestRepo returns a database/sql repository and a GORM repository on the same database.
+func setupSQLTestRepo(t *testing.T) (*GlucoseRepositorySQL, *GlucoseRepositoryGORM, *gorm.DB) {
+	db := setupTestDB(t)
+
+	// Each connection to :memory: is a distinct database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	repo, err := NewGlucoseRepositorySQL(db)
+	if err != nil {
+		t.Fatalf("NewGlucoseRepositorySQL: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	return repo, NewGlucoseRepository(db), db
+}
+
+func TestGlucoseRepositorySQL_Save(t *testing.T) {
+	repo, gormRepo, _ := setupSQLTestRepo(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	arrow := domain.TrendArrowRising
+	m := &domain.GlucoseMeasurement{
+		FactoryTimestamp: now,
+		Timestamp:        now,
+		Value:            5.5,
+		ValueInMgPerDl:   99,
+		TrendArrow:       &arrow,
+		GlucoseColor:     domain.GlucoseColorNormal,
+		Type:             domain.GlucoseTypeCurrent,
+	}
+
+	inserted, err := repo.Save(ctx, m)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if !inserted || m.ID == 0 {
+		t.Fatalf("expected insert with ID, got inserted=%v id=%d", inserted, m.ID)
+	}
+
+	// Duplicate factory timestamp is ignored
+	inserted, err = repo.Save(ctx, &domain.GlucoseMeasurement{FactoryTimestamp: now, Timestamp: now, Value: 6.0})
+	if err != nil {
+		t.Fatalf("Save duplicate: %v", err)
+	}
+	if inserted {
+		t.Error("expected duplicate to be skipped")
+	}
+
+	// Rows written by the SQL backend are readable by the GORM backend
+	latest, err := gormRepo.FindLatest(ctx)
+	if err != nil {
+		t.Fatalf("GORM FindLatest: %v", err)
+	}
+	if latest.Value != 5.5 || latest.TrendArrow == nil || *latest.TrendArrow != arrow || !latest.Timestamp.Equal(now) {
+		t.Errorf("unexpected measurement read by GORM: %+v", latest)
+	}
+}
+
+func TestGlucoseRepositorySQL_FetchProvenance(t *testing.T) {
+	repo, gormRepo, _ := setupSQLTestRepo(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	fetchedAt := now.Add(30 * time.Second)
+	m := &domain.GlucoseMeasurement{
+		FactoryTimestamp: now,
+		Timestamp:        now,
+		Value:            5.5,
+		ValueInMgPerDl:   99,
+		FetchCycleID:     "cycle-1",
+		FetchedAt:        &fetchedAt,
+		FetchSource:      "glucoseItem",
+	}
+	if _, err := repo.Save(ctx, m); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Rows without provenance read back empty
+	earlier := now.Add(-time.Minute)
+	if _, err := gormRepo.Save(ctx, &domain.GlucoseMeasurement{FactoryTimestamp: earlier, Timestamp: earlier, Value: 5.0}); err != nil {
+		t.Fatalf("GORM Save: %v", err)
+	}
+
+	for name, r := range map[string]GlucoseRepository{"sql": repo, "gorm": gormRepo} {
+		all, err := r.FindAll(ctx)
+		if err != nil {
+			t.Fatalf("%s FindAll: %v", name, err)
+		}
+		if len(all) != 2 {
+			t.Fatalf("%s: expected 2 measurements, got %d", name, len(all))
+		}
+		if all[0].FetchCycleID != "cycle-1" || all[0].FetchedAt == nil || !all[0].FetchedAt.Equal(fetchedAt) {
+			t.Errorf("%s: expected provenance cycle-1 at %v, got %q at %v", name, fetchedAt, all[0].FetchCycleID, all[0].FetchedAt)
+		}
+		if all[0].FetchSource != "glucoseItem" {
+			t.Errorf("%s: expected source glucoseItem, got %q", name, all[0].FetchSource)
+		}
+		if all[1].FetchCycleID != "" || all[1].FetchedAt != nil {
+			t.Errorf("%s: expected no provenance, got %q at %v", name, all[1].FetchCycleID, all[1].FetchedAt)
+		}
+	}
+}
+
+func TestGlucoseRepositorySQL_FindLatest_NoData(t *testing.T) {
+	repo, _, _ := setupSQLTestRepo(t)
+
+	_, err := repo.FindLatest(context.Background())
+	if err != persistence.ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestGlucoseRepositorySQL_MatchesGORM(t *testing.T) {
+	repo, gormRepo, _ := setupSQLTestRepo(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 10; i++ {
+		ts := now.Add(-time.Duration(i) * 15 * time.Minute)
+		color := domain.GlucoseColorNormal
+		if i%3 == 0 {
+			color = domain.GlucoseColorWarning
+		}
+		m := &domain.GlucoseMeasurement{
+			FactoryTimestamp: ts,
+			Timestamp:        ts,
+			Value:            4.0 + float64(i)*0.5,
+			ValueInMgPerDl:   72 + i*9,
+			GlucoseColor:     color,
+			IsLow:            i == 0,
+			Type:             i % 2,
+		}
+		if _, err := gormRepo.Save(ctx, m); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	start := now.Add(-90 * time.Minute)
+	end := now.Add(-15 * time.Minute)
+	color := domain.GlucoseColorNormal
+	filters := GlucoseFilters{StartTime: &start, EndTime: &end, Color: &color}
+
+	got, err := repo.FindWithFilters(ctx, filters, 3, 1)
+	if err != nil {
+		t.Fatalf("FindWithFilters: %v", err)
+	}
+	want, err := gormRepo.FindWithFilters(ctx, filters, 3, 1)
+	if err != nil {
+		t.Fatalf("GORM FindWithFilters: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d measurements, got %d", len(want), len(got))
+	}
+	for i := range got {
+		if got[i].ID != want[i].ID || !got[i].Timestamp.Equal(want[i].Timestamp) {
+			t.Errorf("measurement %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+
+	count, err := repo.CountWithFilters(ctx, filters)
+	if err != nil {
+		t.Fatalf("CountWithFilters: %v", err)
+	}
+	wantCount, _ := gormRepo.CountWithFilters(ctx, filters)
+	if count != wantCount {
+		t.Errorf("expected count %d, got %d", wantCount, count)
+	}
+
+	// Streaming yields the filtered measurements oldest first
+	var streamed, wantStreamed []uint
+	if err := repo.StreamWithFilters(ctx, filters, func(m *domain.GlucoseMeasurement) error {
+		streamed = append(streamed, m.ID)
+		return nil
+	}); err != nil {
+		t.Fatalf("StreamWithFilters: %v", err)
+	}
+	if err := gormRepo.StreamWithFilters(ctx, filters, func(m *domain.GlucoseMeasurement) error {
+		wantStreamed = append(wantStreamed, m.ID)
+		return nil
+	}); err != nil {
+		t.Fatalf("GORM StreamWithFilters: %v", err)
+	}
+	if int64(len(streamed)) != wantCount || fmt.Sprint(streamed) != fmt.Sprint(wantStreamed) {
+		t.Errorf("expected streamed IDs %v, got %v", wantStreamed, streamed)
+	}
+	all, _ := gormRepo.FindWithFilters(ctx, filters, 100, 0)
+	if len(all) > 0 && streamed[0] != all[len(all)-1].ID {
+		t.Errorf("expected oldest measurement %d first, got %d", all[len(all)-1].ID, streamed[0])
+	}
+
+	low, high := 80, 140
+	statsFilters := GlucoseStatisticsFilters{StartTime: &start, TargetLowMgDl: &low, TargetHighMgDl: &high}
+	stats, err := repo.GetStatistics(ctx, statsFilters)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	wantStats, err := gormRepo.GetStatistics(ctx, statsFilters)
+	if err != nil {
+		t.Fatalf("GORM GetStatistics: %v", err)
+	}
+	if stats.Count != wantStats.Count || stats.Average != wantStats.Average ||
+		stats.LowCount != wantStats.LowCount || stats.InRangeCount != wantStats.InRangeCount ||
+		stats.BelowRangeCount != wantStats.BelowRangeCount || stats.AboveRangeCount != wantStats.AboveRangeCount {
+		t.Errorf("expected statistics %+v, got %+v", wantStats, stats)
+	}
+	if stats.FirstTimestamp == nil || !stats.FirstTimestamp.Equal(*wantStats.FirstTimestamp) ||
+		stats.LastTimestamp == nil || !stats.LastTimestamp.Equal(*wantStats.LastTimestamp) {
+		t.Errorf("expected timestamps %v-%v, got %v-%v",
+			wantStats.FirstTimestamp, wantStats.LastTimestamp, stats.FirstTimestamp, stats.LastTimestamp)
+	}
+}
+
+func TestGlucoseRepositorySQL_Transaction(t *testing.T) {
+	repo, _, db := setupSQLTestRepo(t)
+	uow := NewUnitOfWork(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	err := uow.ExecuteInTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := repo.Save(txCtx, &domain.GlucoseMeasurement{FactoryTimestamp: now, Timestamp: now, Value: 5.5}); err != nil {
+			return err
+		}
+		return persistence.ErrNotFound // Force rollback
+	})
+	if err == nil {
+		t.Fatal("expected transaction error")
+	}
+
+	if _, err := repo.FindLatest(ctx); err != persistence.ErrNotFound {
+		t.Errorf("expected rolled back insert, got %v", err)
+	}
+}
+
+func TestGlucoseRepository_FilterExpression(t *testing.T) {
+	repo, gormRepo, _ := setupSQLTestRepo(t)
+	ctx := context.Background()
+
+	// One reading per hour over the last day, rising by 10 mg/dL
+	now := time.Now().Truncate(time.Hour)
+	want := 0
+	for i := 0; i < 24; i++ {
+		ts := now.Add(-time.Duration(i) * time.Hour).UTC()
+		if ts.Local().Hour() <= 5 && 60+i*10 > 100 {
+			want++
+		}
+		m := &domain.GlucoseMeasurement{
+			FactoryTimestamp: ts,
+			Timestamp:        ts,
+			ValueInMgPerDl:   60 + i*10,
+			GlucoseColor:     domain.GlucoseColorNormal,
+		}
+		if _, err := gormRepo.Save(ctx, m); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	expr, err := filterexpr.Parse("value_mgdl > 100 AND hour in 0..5", GlucoseQueryFields)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	filters := GlucoseFilters{Query: expr}
+
+	for name, r := range map[string]GlucoseRepository{"gorm": gormRepo, "sql": repo} {
+		measurements, err := r.FindWithFilters(ctx, filters, 100, 0)
+		if err != nil {
+			t.Fatalf("%s: FindWithFilters: %v", name, err)
+		}
+		if len(measurements) != want {
+			t.Errorf("%s: expected %d measurements, got %d", name, want, len(measurements))
+		}
+		for _, m := range measurements {
+			if hour := m.Timestamp.Local().Hour(); hour > 5 || m.ValueInMgPerDl <= 100 {
+				t.Errorf("%s: unexpected measurement at local hour %d with %d mg/dL", name, hour, m.ValueInMgPerDl)
+			}
+		}
+
+		count, err := r.CountWithFilters(ctx, filters)
+		if err != nil {
+			t.Fatalf("%s: CountWithFilters: %v", name, err)
+		}
+		if count != int64(len(measurements)) {
+			t.Errorf("%s: expected count %d, got %d", name, len(measurements), count)
+		}
+
+		stats, err := r.GetStatistics(ctx, GlucoseStatisticsFilters{Query: expr})
+		if err != nil {
+			t.Fatalf("%s: GetStatistics: %v", name, err)
+		}
+		if stats.Count != int64(want) {
+			t.Errorf("%s: expected statistics over %d measurements, got %d", name, want, stats.Count)
+		}
+	}
+}
+
+func TestGlucoseRepository_Histogram(t *testing.T) {
+	repo, gormRepo, _ := setupSQLTestRepo(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	for i, mgdl := range []int{65, 69, 105, 100, 250} {
+		ts := now.Add(-time.Duration(i) * time.Minute)
+		m := &domain.GlucoseMeasurement{FactoryTimestamp: ts, Timestamp: ts, ValueInMgPerDl: mgdl}
+		if _, err := gormRepo.Save(ctx, m); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	start := now.Add(-3 * time.Minute)
+	for name, r := range map[string]GlucoseRepository{"gorm": gormRepo, "sql": repo} {
+		buckets, err := r.GetHistogram(ctx, GlucoseFilters{}, 10)
+		if err != nil {
+			t.Fatalf("%s: GetHistogram: %v", name, err)
+		}
+		want := []GlucoseHistogramBucket{{60, 2}, {100, 2}, {250, 1}}
+		if len(buckets) != len(want) {
+			t.Fatalf("%s: expected %v, got %v", name, want, buckets)
+		}
+		for i := range want {
+			if buckets[i] != want[i] {
+				t.Errorf("%s: bucket %d: expected %v, got %v", name, i, want[i], buckets[i])
+			}
+		}
+
+		// The oldest reading (250 mg/dL) is outside the range
+		buckets, err = r.GetHistogram(ctx, GlucoseFilters{StartTime: &start}, 50)
+		if err != nil {
+			t.Fatalf("%s: GetHistogram: %v", name, err)
+		}
+		if len(buckets) != 2 || buckets[0] != (GlucoseHistogramBucket{50, 2}) || buckets[1] != (GlucoseHistogramBucket{100, 2}) {
+			t.Errorf("%s: unexpected buckets within range: %v", name, buckets)
+		}
+	}
+}
+
+func TestGlucoseRepository_StatisticsTargetBands(t *testing.T) {
+	repo, gormRepo, _ := setupSQLTestRepo(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	for i, mgdl := range []int{60, 63, 70, 140, 141, 200} {
+		ts := now.Add(-time.Duration(i) * time.Minute)
+		m := &domain.GlucoseMeasurement{FactoryTimestamp: ts, Timestamp: ts, ValueInMgPerDl: mgdl}
+		if _, err := gormRepo.Save(ctx, m); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	bands := []domain.TargetBand{
+		{Name: "tight", LowMgDl: 70, HighMgDl: 140},
+		{Name: "pregnancy", LowMgDl: 63, HighMgDl: 140},
+	}
+	for name, r := range map[string]GlucoseRepository{"gorm": gormRepo, "sql": repo} {
+		stats, err := r.GetStatistics(ctx, GlucoseStatisticsFilters{Bands: bands})
+		if err != nil {
+			t.Fatalf("%s: GetStatistics: %v", name, err)
+		}
+		// Bounds are inclusive
+		if len(stats.BandCounts) != 2 || stats.BandCounts[0] != 2 || stats.BandCounts[1] != 3 {
+			t.Errorf("%s: expected band counts [2 3], got %v", name, stats.BandCounts)
+		}
+
+		stats, err = r.GetStatistics(ctx, GlucoseStatisticsFilters{})
+		if err != nil {
+			t.Fatalf("%s: GetStatistics: %v", name, err)
+		}
+		if stats.BandCounts != nil {
+			t.Errorf("%s: expected no band counts without bands, got %v", name, stats.BandCounts)
+		}
+	}
+}
+
+func TestGlucoseRepository_StatisticsMatchReference(t *testing.T) {
+	repo, gormRepo, _ := setupSQLTestRepo(t)
+	ctx := context.Background()
+
+	// Deterministic readings spread over the whole sensor range
+	rng := rand.New(rand.NewSource(42))
+	now := time.Now().UTC().Truncate(time.Minute)
+	values := make([]float64, 2000)
+	for i := range values {
+		mgdl := 40 + rng.Intn(361)
+		values[i] = math.Round(float64(mgdl)/18.0*10) / 10
+		ts := now.Add(-time.Duration(i) * 5 * time.Minute)
+		m := &domain.GlucoseMeasurement{FactoryTimestamp: ts, Timestamp: ts, Value: values[i], ValueInMgPerDl: mgdl}
+		if _, err := gormRepo.Save(ctx, m); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	// Two-pass reference computation
+	var sum float64
+	minValue, maxValue := values[0], values[0]
+	for _, v := range values {
+		sum += v
+		minValue = math.Min(minValue, v)
+		maxValue = math.Max(maxValue, v)
+	}
+	mean := sum / float64(len(values))
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	variance := squares / float64(len(values))
+
+	low, high := 70, 180
+	for name, r := range map[string]GlucoseRepository{"gorm": gormRepo, "sql": repo} {
+		stats, err := r.GetStatistics(ctx, GlucoseStatisticsFilters{TargetLowMgDl: &low, TargetHighMgDl: &high})
+		if err != nil {
+			t.Fatalf("%s: GetStatistics: %v", name, err)
+		}
+		if stats.Count != int64(len(values)) || stats.Min != minValue || stats.Max != maxValue {
+			t.Errorf("%s: expected count %d min %v max %v, got %d %v %v",
+				name, len(values), minValue, maxValue, stats.Count, stats.Min, stats.Max)
+		}
+		if math.Abs(stats.Average-mean) > 1e-9 {
+			t.Errorf("%s: expected average %v, got %v", name, mean, stats.Average)
+		}
+		if math.Abs(stats.Variance-variance) > 1e-9 {
+			t.Errorf("%s: expected variance %v, got %v", name, variance, stats.Variance)
+		}
+		if stats.BelowRangeCount+stats.InRangeCount+stats.AboveRangeCount != stats.Count {
+			t.Errorf("%s: time in range counts %d+%d+%d do not add up to %d",
+				name, stats.BelowRangeCount, stats.InRangeCount, stats.AboveRangeCount, stats.Count)
+		}
+	}
+
+	// A constant series has no variance, even far from the shift
+	constant, _, _ := setupSQLTestRepo(t)
+	for i := 0; i < 100; i++ {
+		ts := now.Add(-time.Duration(i) * time.Minute)
+		if _, err := constant.Save(ctx, &domain.GlucoseMeasurement{FactoryTimestamp: ts, Timestamp: ts, Value: 27.7, ValueInMgPerDl: 499}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	stats, err := constant.GetStatistics(ctx, GlucoseStatisticsFilters{})
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if stats.Variance > 1e-12 {
+		t.Errorf("expected no variance for a constant series, got %v", stats.Variance)
+	}
+}
+
+func TestGlucoseRepository_PatientScope(t *testing.T) {
+	sqlRepo, gormRepo, db := setupSQLTestRepo(t)
+	const patientA = "6a1f3a4e-0000-4000-8000-00000000000a"
+	const patientB = "6a1f3a4e-0000-4000-8000-00000000000b"
+
+	for name, repo := range map[string]GlucoseRepository{"gorm": gormRepo, "sql": sqlRepo} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := db.Exec("DELETE FROM glucose_measurements").Error; err != nil {
+				t.Fatalf("failed to clear measurements: %v", err)
+			}
+
+			now := time.Now().UTC().Truncate(time.Second)
+			// A reading stored before patients were tracked
+			if _, err := repo.Save(ctx, &domain.GlucoseMeasurement{FactoryTimestamp: now.Add(-time.Hour), Timestamp: now.Add(-time.Hour), Value: 5.0}); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			assigned, err := repo.AssignPatient(ctx, patientA)
+			if err != nil || assigned != 1 {
+				t.Fatalf("AssignPatient: %d, %v", assigned, err)
+			}
+
+			// Both patients can have a reading at the same factory timestamp
+			for _, patientID := range []string{patientA, patientB} {
+				inserted, err := repo.Save(WithPatient(ctx, patientID), &domain.GlucoseMeasurement{FactoryTimestamp: now, Timestamp: now, Value: 6.0})
+				if err != nil || !inserted {
+					t.Fatalf("Save for %s: %v, %v", patientID, inserted, err)
+				}
+			}
+
+			all, err := repo.FindAll(ctx)
+			if err != nil || len(all) != 3 {
+				t.Fatalf("expected 3 readings without a patient scope, got %d (%v)", len(all), err)
+			}
+
+			scoped, err := repo.FindAll(WithPatient(ctx, patientA))
+			if err != nil || len(scoped) != 2 {
+				t.Fatalf("expected 2 readings of patient A, got %d (%v)", len(scoped), err)
+			}
+			for _, m := range scoped {
+				if m.PatientID != patientA {
+					t.Errorf("expected readings of patient A, got %q", m.PatientID)
+				}
+			}
+
+			latest, err := repo.FindLatest(WithPatient(ctx, patientB))
+			if err != nil || latest.PatientID != patientB {
+				t.Errorf("expected the latest reading of patient B, got %+v (%v)", latest, err)
+			}
+
+			if _, err := repo.FindLatest(WithPatient(ctx, "6a1f3a4e-0000-4000-8000-00000000000c")); err != persistence.ErrNotFound {
+				t.Errorf("expected ErrNotFound for an unknown patient, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGlucoseRepository_DeleteBefore(t *testing.T) {
+	sqlRepo, gormRepo, db := setupSQLTestRepo(t)
+	const patientA = "6a1f3a4e-0000-4000-8000-00000000000a"
+
+	for name, repo := range map[string]GlucoseRepository{"gorm": gormRepo, "sql": sqlRepo} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := db.Exec("DELETE FROM glucose_measurements").Error; err != nil {
+				t.Fatalf("failed to clear measurements: %v", err)
+			}
+
+			now := time.Now().UTC().Truncate(time.Second)
+			for i, patientID := range []string{"", "", patientA, ""} {
+				ts := now.Add(time.Duration(i-3) * time.Hour)
+				if _, err := repo.Save(WithPatient(ctx, patientID), &domain.GlucoseMeasurement{FactoryTimestamp: ts, Timestamp: ts, Value: 5.0}); err != nil {
+					t.Fatalf("Save: %v", err)
+				}
+			}
+
+			// Scoped to a patient
+			deleted, err := repo.DeleteBefore(WithPatient(ctx, patientA), now)
+			if err != nil || deleted != 1 {
+				t.Fatalf("expected 1 reading of patient A deleted, got %d (%v)", deleted, err)
+			}
+
+			// The reading at the bound is kept
+			deleted, err = repo.DeleteBefore(ctx, now)
+			if err != nil || deleted != 2 {
+				t.Fatalf("expected 2 readings deleted, got %d (%v)", deleted, err)
+			}
+			count, err := repo.CountWithFilters(ctx, GlucoseFilters{})
+			if err != nil || count != 1 {
+				t.Errorf("expected 1 reading left, got %d (%v)", count, err)
+			}
+		})
+	}
+}
+
+func TestGlucoseRepository_FindEarliestStoredSince(t *testing.T) {
+	sqlRepo, gormRepo, db := setupSQLTestRepo(t)
+
+	for name, repo := range map[string]GlucoseRepository{"gorm": gormRepo, "sql": sqlRepo} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := db.Exec("DELETE FROM glucose_measurements").Error; err != nil {
+				t.Fatalf("failed to clear measurements: %v", err)
+			}
+
+			now := time.Now().UTC().Truncate(time.Second)
+			if _, err := repo.FindEarliestStoredSince(ctx, now); err != persistence.ErrNotFound {
+				t.Fatalf("expected ErrNotFound without measurements, got %v", err)
+			}
+
+			// Taken 3 days ago but stored before now, taken 2 days ago and stored now, taken now
+			for _, m := range []struct{ taken, stored time.Time }{
+				{now.Add(-72 * time.Hour), now.Add(-time.Hour)},
+				{now.Add(-48 * time.Hour), now},
+				{now, now},
+			} {
+				if _, err := repo.Save(ctx, &domain.GlucoseMeasurement{CreatedAt: m.stored, FactoryTimestamp: m.taken, Timestamp: m.taken, Value: 5.0}); err != nil {
+					t.Fatalf("Save: %v", err)
+				}
+			}
+
+			earliest, err := repo.FindEarliestStoredSince(ctx, now)
+			if err != nil || !earliest.Timestamp.Equal(now.Add(-48*time.Hour)) {
+				t.Errorf("expected the reading taken 2 days ago, got %+v (%v)", earliest, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// GlucoseRollupRepositoryGORM is the GORM implementation of GlucoseRollupRepository.
+type GlucoseRollupRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewGlucoseRollupRepository creates a new GlucoseRollupRepository.
+func NewGlucoseRollupRepository(db *gorm.DB) *GlucoseRollupRepositoryGORM {
+	return &GlucoseRollupRepositoryGORM{db: db}
+}
+
+// Upsert stores rollups, replacing those of the same patient and interval.
+func (r *GlucoseRollupRepositoryGORM) Upsert(ctx context.Context, rollups []*domain.GlucoseRollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	db := txOrDefault(ctx, r.db)
+
+	// ON CONFLICT (patient_id, start_time) DO UPDATE
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "patient_id"}, {Name: "start_time"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"created_at", "count", "value", "value_in_mg_per_dl", "min_mg_dl", "max_mg_dl",
+		}),
+	}).CreateInBatches(rollups, 100).Error
+}
+
+// FindByTimeRange returns the rollups starting within [start, end), oldest first.
+func (r *GlucoseRollupRepositoryGORM) FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.GlucoseRollup, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var rollups []*domain.GlucoseRollup
+	result := scopePatient(ctx, db).
+		Where("start_time >= ? AND start_time < ?", start, end).
+		Order("start_time ASC").
+		Find(&rollups)
+
+	return rollups, result.Error
+}
+
+// FindLatest returns the rollup with the latest start.
+// Returns persistence.ErrNotFound if there is none.
+func (r *GlucoseRollupRepositoryGORM) FindLatest(ctx context.Context) (*domain.GlucoseRollup, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var rollup domain.GlucoseRollup
+	result := scopePatient(ctx, db).Order("start_time DESC").First(&rollup)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &rollup, nil
+}
+
+// Count returns the number of rollups.
+func (r *GlucoseRollupRepositoryGORM) Count(ctx context.Context) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var count int64
+	if err := scopePatient(ctx, db.Model(&domain.GlucoseRollup{})).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
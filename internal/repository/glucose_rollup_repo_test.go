@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+func TestGlucoseRollupRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewGlucoseRollupRepository(db)
+	ctx := context.Background()
+
+	if _, err := repo.FindLatest(ctx); err != persistence.ErrNotFound {
+		t.Fatalf("expected ErrNotFound without rollups, got %v", err)
+	}
+
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	var rollups []*domain.GlucoseRollup
+	for i := range 4 {
+		rollups = append(rollups, &domain.GlucoseRollup{
+			StartTime:      start.Add(time.Duration(i) * domain.GlucoseRollupInterval),
+			Count:          3,
+			Value:          6.1,
+			ValueInMgPerDl: 110,
+			MinMgDl:        100,
+			MaxMgDl:        121,
+		})
+	}
+	rollups[3].PatientID = "other"
+	if err := repo.Upsert(ctx, rollups); err != nil {
+		t.Fatalf("failed to create rollups: %v", err)
+	}
+
+	latest, err := repo.FindLatest(ctx)
+	if err != nil {
+		t.Fatalf("failed to find the latest rollup: %v", err)
+	}
+	if !latest.StartTime.Equal(start.Add(45*time.Minute)) || latest.ValueInMgPerDl != 110 {
+		t.Errorf("unexpected latest rollup: %+v", latest)
+	}
+
+	// Patient scope
+	latest, err = repo.FindLatest(WithPatient(ctx, "other"))
+	if err != nil || latest.PatientID != "other" {
+		t.Errorf("expected the rollup of the other patient, got %+v (%v)", latest, err)
+	}
+	if count, err := repo.Count(ctx); err != nil || count != 4 {
+		t.Errorf("expected 4 rollups, got %d (%v)", count, err)
+	}
+
+	// Rollups of the same patient and interval are replaced
+	if err := repo.Upsert(ctx, []*domain.GlucoseRollup{{StartTime: start.Add(15 * time.Minute), Count: 4, Value: 7.0, ValueInMgPerDl: 126, MinMgDl: 110, MaxMgDl: 140}}); err != nil {
+		t.Fatalf("failed to replace a rollup: %v", err)
+	}
+	found, err := repo.FindByTimeRange(ctx, start.Add(15*time.Minute), start.Add(45*time.Minute))
+	if err != nil || len(found) != 2 {
+		t.Fatalf("expected the 10:15 and 10:30 rollups, got %d (%v)", len(found), err)
+	}
+	if found[0].Count != 4 || found[0].ValueInMgPerDl != 126 || found[1].Count != 3 {
+		t.Errorf("expected the 10:15 rollup to be replaced, got %+v and %+v", found[0], found[1])
+	}
+	if count, err := repo.Count(ctx); err != nil || count != 4 {
+		t.Errorf("expected 4 rollups after the upsert, got %d (%v)", count, err)
+	}
+}
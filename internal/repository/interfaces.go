@@ -5,22 +5,33 @@ import (
 	"time"
 
 	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/utils/filterexpr"
 )
 
 // GlucoseFilters defines filter criteria for querying glucose measurements
 type GlucoseFilters struct {
 	StartTime *time.Time
 	EndTime   *time.Time
-	Color     *int // 1=normal, 2=warning, 3=critical
-	Type      *int // 0=historical, 1=current
+	Color     *int             // 1=normal, 2=warning, 3=critical
+	Type      *int             // 0=historical, 1=current
+	Query     *filterexpr.Expr // Filter expression over GlucoseQueryFields
 }
 
 // GlucoseStatisticsFilters defines filter criteria for aggregated glucose statistics
 type GlucoseStatisticsFilters struct {
-	StartTime      *time.Time // nil = no lower bound
-	EndTime        *time.Time // nil = no upper bound
-	TargetLowMgDl  *int       // For Time in Range calculation
-	TargetHighMgDl *int       // For Time in Range calculation
+	StartTime      *time.Time          // nil = no lower bound
+	EndTime        *time.Time          // nil = no upper bound
+	TargetLowMgDl  *int                // For Time in Range calculation
+	TargetHighMgDl *int                // For Time in Range calculation
+	Query          *filterexpr.Expr    // nil = no filter expression
+	Bands          []domain.TargetBand // Secondary ranges counted in BandCounts
+}
+
+// GlucoseHistogramBucket is the number of measurements whose value falls in
+// [StartMgDl, StartMgDl + bucket size)
+type GlucoseHistogramBucket struct {
+	StartMgDl int
+	Count     int64
 }
 
 // GlucoseStatisticsResult contains aggregated glucose statistics computed by SQL
@@ -32,18 +43,20 @@ type GlucoseStatisticsResult struct {
 	MinMgDl         int
 	Max             float64
 	MaxMgDl         int
-	Variance        float64 // variance = E[X²] - E[X]², sqrt computed in Go for SQLite compatibility
+	Variance        float64 // variance = E[(X-c)²] - E[X-c]², sqrt computed in Go for SQLite compatibility
 	LowCount        int64
 	NormalCount     int64
 	HighCount       int64
 	InRangeCount    int64
 	BelowRangeCount int64
 	AboveRangeCount int64
+	BandCounts      []int64    // Measurements within each of filters.Bands, in order
 	FirstTimestamp  *time.Time // Oldest measurement timestamp
 	LastTimestamp   *time.Time // Newest measurement timestamp
 }
 
 // GlucoseRepository defines the interface for glucose measurement persistence.
+// Queries are scoped to the patient of the context, if any (see WithPatient).
 type GlucoseRepository interface {
 	// Save creates or ignores a measurement (duplicate timestamps are silently ignored).
 	// Returns (true, nil) if inserted, (false, nil) if duplicate was ignored.
@@ -64,20 +77,41 @@ type GlucoseRepository interface {
 	// CountWithFilters returns total count of measurements matching filters
 	CountWithFilters(ctx context.Context, filters GlucoseFilters) (int64, error)
 
+	// StreamWithFilters calls fn for each measurement matching filters, oldest
+	// first, without loading them all in memory. It stops at the first error of fn.
+	StreamWithFilters(ctx context.Context, filters GlucoseFilters, fn func(*domain.GlucoseMeasurement) error) error
+
 	// GetStatistics returns aggregated statistics computed by SQL
 	GetStatistics(ctx context.Context, filters GlucoseStatisticsFilters) (*GlucoseStatisticsResult, error)
+
+	// GetHistogram returns the number of measurements matching filters per value
+	// bucket of bucketMgDl, computed by SQL. Empty buckets are not returned.
+	GetHistogram(ctx context.Context, filters GlucoseFilters, bucketMgDl int) ([]GlucoseHistogramBucket, error)
+
+	// AssignPatient assigns the measurements stored without a patient to patientID
+	AssignPatient(ctx context.Context, patientID string) (int64, error)
+
+	// DeleteBefore deletes the measurements taken before a time
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// FindEarliestStoredSince returns the earliest taken of the measurements stored at or after a time (persistence.ErrNotFound if none)
+	FindEarliestStoredSince(ctx context.Context, since time.Time) (*domain.GlucoseMeasurement, error)
 }
 
 // SensorFilters defines filter criteria for querying sensors
 type SensorFilters struct {
-	StartTime *time.Time // filter on activation
-	EndTime   *time.Time
+	StartTime  *time.Time // filter on activation
+	EndTime    *time.Time
+	EndedStart *time.Time // filter on ended_at, excludes the current sensor
+	EndedEnd   *time.Time
 }
 
 // SensorStatisticsFilters defines filter criteria for sensor statistics
 type SensorStatisticsFilters struct {
-	StartTime *time.Time
-	EndTime   *time.Time
+	StartTime  *time.Time // filter on activation
+	EndTime    *time.Time
+	EndedStart *time.Time // filter on ended_at, excludes the current sensor
+	EndedEnd   *time.Time
 }
 
 // SensorStatisticsResult contains aggregated sensor statistics computed by SQL
@@ -88,9 +122,12 @@ type SensorStatisticsResult struct {
 	MinDuration  float64
 	MaxDuration  float64
 	AvgExpected  float64 // average expected days
+	RatedSensors int64
+	AvgRating    *float64 // nil when no sensor is rated
 }
 
 // SensorRepository defines the interface for sensor configuration persistence.
+// Queries are scoped to the patient of the context, if any (see WithPatient).
 type SensorRepository interface {
 	// Save creates or updates a sensor (upsert by serial number)
 	Save(ctx context.Context, s *domain.SensorConfig) error
@@ -115,6 +152,190 @@ type SensorRepository interface {
 
 	// SetEndedAt marks a sensor as ended (replaced by a new sensor)
 	SetEndedAt(ctx context.Context, serial string, endedAt time.Time) error
+
+	// SetApplicationSite records the body site a sensor is applied to
+	SetApplicationSite(ctx context.Context, serial string, site string) error
+
+	// SetFeedback records the note and rating of a sensor (nil rating = not rated)
+	SetFeedback(ctx context.Context, serial string, note string, rating *int) error
+
+	// FindPrevious returns the most recent sensor activated before activation
+	FindPrevious(ctx context.Context, activation time.Time) (*domain.SensorConfig, error)
+
+	// AssignPatient assigns the sensors stored without a patient to patientID
+	AssignPatient(ctx context.Context, patientID string) (int64, error)
+}
+
+// AlertFilters defines filter criteria for querying alerts
+type AlertFilters struct {
+	StartTime    *time.Time // filter on FiredAt
+	EndTime      *time.Time
+	Type         *string
+	Acknowledged *bool
+}
+
+// AlertRepository defines the interface for alert history persistence.
+type AlertRepository interface {
+	// Create inserts a new alert
+	Create(ctx context.Context, a *domain.Alert) error
+
+	// FindWithFilters returns alerts matching filters with pagination, newest first
+	FindWithFilters(ctx context.Context, filters AlertFilters, limit, offset int) ([]*domain.Alert, error)
+
+	// CountWithFilters returns total count of alerts matching filters
+	CountWithFilters(ctx context.Context, filters AlertFilters) (int64, error)
+
+	// FindByTimeRange returns alerts fired within a time range (inclusive), oldest first
+	FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.Alert, error)
+
+	// Acknowledge marks an alert as acknowledged
+	Acknowledge(ctx context.Context, id uint, at time.Time) error
+}
+
+// GlucoseEventFilters defines filter criteria for querying glucose events
+type GlucoseEventFilters struct {
+	StartTime *time.Time // filter on the event StartTime
+	EndTime   *time.Time
+	Type      *string
+}
+
+// GlucoseEventRepository defines the interface for glucose event persistence.
+type GlucoseEventRepository interface {
+	// CreateBatch inserts detected events
+	CreateBatch(ctx context.Context, events []*domain.GlucoseEvent) error
+
+	// DeleteFrom deletes the events started at or after a time, before they are detected again
+	DeleteFrom(ctx context.Context, since time.Time) (int64, error)
+
+	// FindSpanning returns the event started before t and ending at or after t (persistence.ErrNotFound if none)
+	FindSpanning(ctx context.Context, t time.Time) (*domain.GlucoseEvent, error)
+
+	// FindWithFilters returns events matching filters with pagination, newest first
+	FindWithFilters(ctx context.Context, filters GlucoseEventFilters, limit, offset int) ([]*domain.GlucoseEvent, error)
+
+	// CountWithFilters returns total count of events matching filters
+	CountWithFilters(ctx context.Context, filters GlucoseEventFilters) (int64, error)
+}
+
+// GlucoseRollupRepository defines the interface for the persistence of the
+// downsampled measurements.
+type GlucoseRollupRepository interface {
+	// Upsert stores rollups, replacing those of the same patient and interval
+	Upsert(ctx context.Context, rollups []*domain.GlucoseRollup) error
+
+	// FindByTimeRange returns the rollups starting within [start, end), oldest first
+	FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.GlucoseRollup, error)
+
+	// FindLatest returns the rollup with the latest start (persistence.ErrNotFound if none)
+	FindLatest(ctx context.Context) (*domain.GlucoseRollup, error)
+
+	// Count returns the number of rollups
+	Count(ctx context.Context) (int64, error)
+}
+
+// DailySummaryRepository defines the interface for the persistence of the
+// daily glucose summaries.
+type DailySummaryRepository interface {
+	// Upsert stores summaries, replacing those of the same patient and day
+	Upsert(ctx context.Context, summaries []*domain.DailySummary) error
+
+	// FindByDateRange returns the summaries of the days within a range (YYYY-MM-DD, inclusive), oldest first
+	FindByDateRange(ctx context.Context, from, to string) ([]*domain.DailySummary, error)
+}
+
+// JobFilters defines filter criteria for querying jobs
+type JobFilters struct {
+	Type   *string
+	Status *string
+}
+
+// JobRepository defines the interface for background job persistence.
+type JobRepository interface {
+	// Create inserts a new job
+	Create(ctx context.Context, j *domain.Job) error
+
+	// Update saves all fields of an existing job
+	Update(ctx context.Context, j *domain.Job) error
+
+	// FindByID returns a job (persistence.ErrNotFound if none)
+	FindByID(ctx context.Context, id string) (*domain.Job, error)
+
+	// FindWithFilters returns jobs matching filters with pagination, newest first
+	FindWithFilters(ctx context.Context, filters JobFilters, limit, offset int) ([]*domain.Job, error)
+
+	// CountWithFilters returns total count of jobs matching filters
+	CountWithFilters(ctx context.Context, filters JobFilters) (int64, error)
+
+	// ClaimNext marks the oldest due pending job as running (persistence.ErrNotFound if none)
+	ClaimNext(ctx context.Context, now time.Time) (*domain.Job, error)
+
+	// CancelPending marks a pending job as canceled, returning false if it is not pending
+	CancelPending(ctx context.Context, id string, at time.Time) (bool, error)
+
+	// CountActive returns the number of pending or running jobs of a type
+	CountActive(ctx context.Context, jobType string) (int64, error)
+
+	// RequeueRunning returns the jobs interrupted by a stop to the queue
+	RequeueRunning(ctx context.Context) (int64, error)
+
+	// DeleteFinishedBefore deletes the jobs finished before a time
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// UpstreamRepository defines the interface for upstream outage persistence.
+type UpstreamRepository interface {
+	// Create inserts a new outage
+	Create(ctx context.Context, o *domain.UpstreamOutage) error
+
+	// Update saves all fields of an existing outage
+	Update(ctx context.Context, o *domain.UpstreamOutage) error
+
+	// FindOngoing returns the outage that has not ended yet (persistence.ErrNotFound if none)
+	FindOngoing(ctx context.Context) (*domain.UpstreamOutage, error)
+
+	// FindSince returns the outages ongoing or ended at or after since, newest first
+	FindSince(ctx context.Context, since time.Time) ([]*domain.UpstreamOutage, error)
+}
+
+// UpstreamSessionRepository defines the interface for stored LibreView sessions.
+// There is at most one session per account.
+type UpstreamSessionRepository interface {
+	// Save creates or replaces the session of the account
+	Save(ctx context.Context, s *domain.UpstreamSession) error
+
+	// Find returns the session of an account (persistence.ErrNotFound if none)
+	Find(ctx context.Context, account string) (*domain.UpstreamSession, error)
+
+	// Delete removes the session of an account, if any
+	Delete(ctx context.Context, account string) error
+}
+
+// TreatmentRepository defines the interface for treatment persistence.
+type TreatmentRepository interface {
+	// Save creates or ignores a treatment (duplicates of source, type and timestamp are ignored).
+	// Returns (true, nil) if inserted, (false, nil) if duplicate was ignored.
+	Save(ctx context.Context, t *domain.TreatmentEntry) (inserted bool, err error)
+
+	// FindByTimeRange returns treatments within a time range (inclusive), oldest first
+	FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.TreatmentEntry, error)
+}
+
+// InsulinRepository defines the interface for logged insulin doses.
+type InsulinRepository interface {
+	// Create inserts a new dose
+	Create(ctx context.Context, d *domain.InsulinDose) error
+
+	// FindByTimeRange returns doses within a time range (inclusive), oldest first
+	FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.InsulinDose, error)
+}
+
+// CarbRepository defines the interface for logged carbohydrate intakes.
+type CarbRepository interface {
+	// Create inserts a new intake
+	Create(ctx context.Context, c *domain.CarbIntake) error
+
+	// FindByTimeRange returns intakes within a time range (inclusive), oldest first
+	FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.CarbIntake, error)
 }
 
 // UserRepository defines the interface for user preferences persistence.
@@ -146,3 +367,146 @@ type TargetsRepository interface {
 	// Find returns the glucose targets (only one record expected)
 	Find(ctx context.Context) (*domain.GlucoseTargets, error)
 }
+
+// DashboardRepository defines the interface for dashboard layout persistence.
+// This is a singleton repository - only one dashboard config record is expected.
+type DashboardRepository interface {
+	// Save creates or updates the dashboard config (singleton)
+	Save(ctx context.Context, c *domain.DashboardConfig) error
+
+	// Find returns the dashboard config (only one record expected)
+	Find(ctx context.Context) (*domain.DashboardConfig, error)
+}
+
+// PreferencesRepository defines the interface for display preferences persistence.
+// This is a singleton repository - only one display preferences record is expected.
+type PreferencesRepository interface {
+	// Save creates or updates the display preferences (singleton)
+	Save(ctx context.Context, p *domain.DisplayPreferences) error
+
+	// Find returns the display preferences (only one record expected)
+	Find(ctx context.Context) (*domain.DisplayPreferences, error)
+}
+
+// ViewRepository defines the interface for saved view persistence.
+type ViewRepository interface {
+	// Save creates a view or replaces the view with the same name
+	Save(ctx context.Context, v *domain.SavedView) error
+
+	// FindAll returns all views, sorted by name
+	FindAll(ctx context.Context) ([]*domain.SavedView, error)
+
+	// FindByName returns the view with the given name (persistence.ErrNotFound if missing)
+	FindByName(ctx context.Context, name string) (*domain.SavedView, error)
+
+	// Delete removes the view with the given name (persistence.ErrNotFound if missing)
+	Delete(ctx context.Context, name string) error
+}
+
+// NoteFilters defines filter criteria for querying notes
+type NoteFilters struct {
+	StartTime *time.Time
+	EndTime   *time.Time
+	Tag       *string // Notes carrying this tag
+}
+
+// NoteRepository defines the interface for note persistence.
+type NoteRepository interface {
+	// Create inserts a new note
+	Create(ctx context.Context, n *domain.Note) error
+
+	// Update replaces the timestamp, text and tags of a note (persistence.ErrNotFound if missing)
+	Update(ctx context.Context, n *domain.Note) error
+
+	// FindByID returns a note by its ID (persistence.ErrNotFound if missing)
+	FindByID(ctx context.Context, id uint) (*domain.Note, error)
+
+	// FindWithFilters returns notes matching filters with pagination, newest first
+	FindWithFilters(ctx context.Context, filters NoteFilters, limit, offset int) ([]*domain.Note, error)
+
+	// CountWithFilters returns total count of notes matching filters
+	CountWithFilters(ctx context.Context, filters NoteFilters) (int64, error)
+
+	// Delete removes a note (persistence.ErrNotFound if missing)
+	Delete(ctx context.Context, id uint) error
+}
+
+// AttachmentRepository defines the interface for sensor attachment metadata persistence.
+type AttachmentRepository interface {
+	// Create inserts a new attachment
+	Create(ctx context.Context, a *domain.SensorAttachment) error
+
+	// FindByID returns an attachment by its ID (persistence.ErrNotFound if missing)
+	FindByID(ctx context.Context, id uint) (*domain.SensorAttachment, error)
+
+	// FindBySerialNumber returns the attachments of a sensor, oldest first
+	FindBySerialNumber(ctx context.Context, serial string) ([]*domain.SensorAttachment, error)
+
+	// FindStorageKeys returns the storage keys of all attachments
+	FindStorageKeys(ctx context.Context) ([]string, error)
+
+	// Delete removes an attachment (persistence.ErrNotFound if missing)
+	Delete(ctx context.Context, id uint) error
+}
+
+// TokenRepository defines the interface for API token persistence.
+type TokenRepository interface {
+	// Create inserts a new token
+	Create(ctx context.Context, t *domain.APIToken) error
+
+	// FindAll returns all tokens (including revoked and expired), newest first
+	FindAll(ctx context.Context) ([]*domain.APIToken, error)
+
+	// FindByHash returns the token with the given SHA-256 hash
+	FindByHash(ctx context.Context, hash string) (*domain.APIToken, error)
+
+	// Revoke marks a token as revoked
+	Revoke(ctx context.Context, id uint, at time.Time) error
+
+	// UpdateLastUsed records the last successful authentication of a token
+	UpdateLastUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+// SigningKeyRepository defines the interface for payload signing key persistence.
+type SigningKeyRepository interface {
+	// Create inserts a new signing key
+	Create(ctx context.Context, k *domain.SigningKey) error
+
+	// FindAll returns all keys (including retired ones), newest first
+	FindAll(ctx context.Context) ([]*domain.SigningKey, error)
+
+	// FindCurrent returns the newest non-retired key
+	FindCurrent(ctx context.Context) (*domain.SigningKey, error)
+
+	// Retire marks a key as no longer used for signing
+	Retire(ctx context.Context, id uint, at time.Time) error
+}
+
+// PersonalData contains everything stored about the user.
+// Singleton records are nil when they have not been fetched yet.
+type PersonalData struct {
+	Measurements []*domain.GlucoseMeasurement `json:"measurements"`
+	Rollups      []*domain.GlucoseRollup      `json:"rollups"` // Downsampled measurements, see GLCMD_RETENTION_DOWNSAMPLE_DAYS
+	Sensors      []*domain.SensorConfig       `json:"sensors"`
+	Treatments   []*domain.TreatmentEntry     `json:"treatments"`
+	Insulin      []*domain.InsulinDose        `json:"insulin"`
+	Carbs        []*domain.CarbIntake         `json:"carbs"`
+	Alerts       []*domain.Alert              `json:"alerts"`
+	User         *domain.UserPreferences      `json:"user"`
+	Device       *domain.DeviceInfo           `json:"device"`
+	Targets      *domain.GlucoseTargets       `json:"targets"`
+	Dashboard    *domain.DashboardConfig      `json:"dashboard"`
+	Preferences  *domain.DisplayPreferences   `json:"preferences"`
+	Views        []*domain.SavedView          `json:"views"`
+	Notes        []*domain.Note               `json:"notes"`
+	Attachments  []*domain.SensorAttachment   `json:"attachments"` // Metadata only, files are not exported
+}
+
+// PrivacyRepository defines the interface for exporting and erasing all personal data.
+type PrivacyRepository interface {
+	// Export returns all stored personal data, oldest records first
+	Export(ctx context.Context) (*PersonalData, error)
+
+	// EraseAll deletes all personal data and returns the number of rows deleted per table
+	EraseAll(ctx context.Context) (map[string]int64, error)
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// maxClaimRetries bounds the attempts to claim a job taken by another worker
+// between the select and the update.
+const maxClaimRetries = 3
+
+// JobRepositoryGORM is the GORM implementation of JobRepository.
+type JobRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewJobRepository creates a new JobRepository.
+func NewJobRepository(db *gorm.DB) *JobRepositoryGORM {
+	return &JobRepositoryGORM{db: db}
+}
+
+// Create inserts a new job.
+func (r *JobRepositoryGORM) Create(ctx context.Context, j *domain.Job) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Create(j).Error
+}
+
+// Update saves all fields of an existing job.
+func (r *JobRepositoryGORM) Update(ctx context.Context, j *domain.Job) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Save(j).Error
+}
+
+// FindByID returns the job with the given ID.
+// Returns persistence.ErrNotFound if there is none.
+func (r *JobRepositoryGORM) FindByID(ctx context.Context, id string) (*domain.Job, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var job domain.Job
+	result := db.Where("id = ?", id).First(&job)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &job, nil
+}
+
+// FindWithFilters returns jobs matching filters with pagination, newest first.
+func (r *JobRepositoryGORM) FindWithFilters(ctx context.Context, filters JobFilters, limit, offset int) ([]*domain.Job, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var jobs []*domain.Job
+	result := applyJobFilters(db.Model(&domain.Job{}), filters).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&jobs)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return jobs, nil
+}
+
+// CountWithFilters returns total count of jobs matching filters.
+func (r *JobRepositoryGORM) CountWithFilters(ctx context.Context, filters JobFilters) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var count int64
+	result := applyJobFilters(db.Model(&domain.Job{}), filters).Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// ClaimNext marks the oldest pending job due at now as running, counting a
+// new attempt, and returns it.
+// Returns persistence.ErrNotFound if no job is due.
+func (r *JobRepositoryGORM) ClaimNext(ctx context.Context, now time.Time) (*domain.Job, error) {
+	db := txOrDefault(ctx, r.db)
+
+	for range maxClaimRetries {
+		var candidate domain.Job
+		result := db.Select("id").
+			Where("status = ? AND run_at <= ?", domain.JobStatusPending, now).
+			Order("run_at ASC, created_at ASC").
+			First(&candidate)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return nil, persistence.ErrNotFound
+			}
+			return nil, result.Error
+		}
+
+		// Only one worker wins the update if several selected the same job
+		result = db.Model(&domain.Job{}).
+			Where("id = ? AND status = ?", candidate.ID, domain.JobStatusPending).
+			Updates(map[string]any{
+				"status":     domain.JobStatusRunning,
+				"attempts":   gorm.Expr("attempts + 1"),
+				"started_at": now,
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return r.FindByID(ctx, candidate.ID)
+		}
+	}
+
+	return nil, persistence.ErrNotFound
+}
+
+// CancelPending marks a pending job as canceled.
+// Returns false if the job is not pending (running, finished or unknown).
+func (r *JobRepositoryGORM) CancelPending(ctx context.Context, id string, at time.Time) (bool, error) {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Model(&domain.Job{}).
+		Where("id = ? AND status = ?", id, domain.JobStatusPending).
+		Updates(map[string]any{
+			"status":      domain.JobStatusCanceled,
+			"finished_at": at,
+		})
+
+	return result.RowsAffected == 1, result.Error
+}
+
+// CountActive returns the number of pending or running jobs of a type.
+func (r *JobRepositoryGORM) CountActive(ctx context.Context, jobType string) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var count int64
+	result := db.Model(&domain.Job{}).
+		Where("type = ? AND status IN ?", jobType, []string{domain.JobStatusPending, domain.JobStatusRunning}).
+		Count(&count)
+
+	return count, result.Error
+}
+
+// RequeueRunning returns the jobs left running by a previous process to the
+// queue, without counting their interrupted attempt.
+func (r *JobRepositoryGORM) RequeueRunning(ctx context.Context) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Model(&domain.Job{}).
+		Where("status = ?", domain.JobStatusRunning).
+		Updates(map[string]any{
+			"status":   domain.JobStatusPending,
+			"attempts": gorm.Expr("CASE WHEN attempts > 0 THEN attempts - 1 ELSE 0 END"),
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+// DeleteFinishedBefore deletes the jobs finished before a time and returns
+// how many were deleted.
+func (r *JobRepositoryGORM) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Where("finished_at IS NOT NULL AND finished_at < ?", before).Delete(&domain.Job{})
+
+	return result.RowsAffected, result.Error
+}
+
+// applyJobFilters adds the WHERE clauses of filters to query.
+func applyJobFilters(query *gorm.DB, filters JobFilters) *gorm.DB {
+	if filters.Type != nil {
+		query = query.Where("type = ?", *filters.Type)
+	}
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	return query
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+func TestJobRepository_ClaimNext(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewJobRepository(db)
+	ctx := context.Background()
+
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	jobs := []*domain.Job{
+		{ID: "later", Type: "test", Status: domain.JobStatusPending, RunAt: now.Add(time.Minute), MaxAttempts: 1},
+		{ID: "due", Type: "test", Status: domain.JobStatusPending, RunAt: now.Add(-time.Minute), MaxAttempts: 1, Payload: domain.JobData(`{"n":1}`)},
+		{ID: "done", Type: "test", Status: domain.JobStatusSucceeded, RunAt: now.Add(-time.Hour), MaxAttempts: 1},
+	}
+	for _, j := range jobs {
+		if err := repo.Create(ctx, j); err != nil {
+			t.Fatalf("failed to create job: %v", err)
+		}
+	}
+
+	claimed, err := repo.ClaimNext(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to claim job: %v", err)
+	}
+	if claimed.ID != "due" || claimed.Status != domain.JobStatusRunning || claimed.Attempts != 1 || claimed.StartedAt == nil {
+		t.Errorf("expected the due job claimed for its first attempt, got %+v", claimed)
+	}
+	if string(claimed.Payload) != `{"n":1}` {
+		t.Errorf("expected the payload read back, got %s", claimed.Payload)
+	}
+
+	// The other pending job is not due yet
+	if _, err := repo.ClaimNext(ctx, now); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	count, err := repo.CountActive(ctx, "test")
+	if err != nil || count != 2 {
+		t.Errorf("expected 2 active jobs, got %d (%v)", count, err)
+	}
+
+	// A restart returns the running job to the queue without counting its attempt
+	requeued, err := repo.RequeueRunning(ctx)
+	if err != nil || requeued != 1 {
+		t.Fatalf("expected 1 requeued job, got %d (%v)", requeued, err)
+	}
+	job, err := repo.FindByID(ctx, "due")
+	if err != nil {
+		t.Fatalf("failed to find job: %v", err)
+	}
+	if job.Status != domain.JobStatusPending || job.Attempts != 0 {
+		t.Errorf("expected pending with no attempt, got %+v", job)
+	}
+}
+
+func TestJobRepository_CancelAndDelete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewJobRepository(db)
+	ctx := context.Background()
+
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	pending := &domain.Job{ID: "pending", Type: "import", Status: domain.JobStatusPending, RunAt: now, MaxAttempts: 1}
+	if err := repo.Create(ctx, pending); err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+
+	canceled, err := repo.CancelPending(ctx, "pending", now)
+	if err != nil || !canceled {
+		t.Fatalf("expected the pending job canceled, got %v (%v)", canceled, err)
+	}
+	if canceled, _ := repo.CancelPending(ctx, "pending", now); canceled {
+		t.Error("expected a canceled job not to be canceled again")
+	}
+
+	// Errors and results round-trip as JSON
+	old := now.Add(-48 * time.Hour)
+	finished := &domain.Job{
+		ID: "finished", Type: "cleanup", Status: domain.JobStatusSucceeded, RunAt: old, MaxAttempts: 1,
+		FinishedAt: &old, Errors: domain.JobErrors{"row 2 skipped"}, Result: domain.JobData(`{"deleted":3}`),
+	}
+	if err := repo.Create(ctx, finished); err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+	job, err := repo.FindByID(ctx, "finished")
+	if err != nil {
+		t.Fatalf("failed to find job: %v", err)
+	}
+	if len(job.Errors) != 1 || string(job.Result) != `{"deleted":3}` {
+		t.Errorf("expected errors and result read back, got %+v", job)
+	}
+
+	status := domain.JobStatusCanceled
+	jobs, err := repo.FindWithFilters(ctx, JobFilters{Status: &status}, 10, 0)
+	if err != nil || len(jobs) != 1 || jobs[0].ID != "pending" {
+		t.Errorf("expected the canceled job, got %v (%v)", jobs, err)
+	}
+	total, err := repo.CountWithFilters(ctx, JobFilters{})
+	if err != nil || total != 2 {
+		t.Errorf("expected 2 jobs, got %d (%v)", total, err)
+	}
+
+	deleted, err := repo.DeleteFinishedBefore(ctx, now.Add(-24*time.Hour))
+	if err != nil || deleted != 1 {
+		t.Fatalf("expected 1 deleted job, got %d (%v)", deleted, err)
+	}
+	if _, err := repo.FindByID(ctx, "finished"); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected the old job deleted, got %v", err)
+	}
+}
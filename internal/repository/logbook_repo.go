@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// InsulinRepositoryGORM is the GORM implementation of InsulinRepository.
+type InsulinRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewInsulinRepository creates a new InsulinRepository.
+func NewInsulinRepository(db *gorm.DB) *InsulinRepositoryGORM {
+	return &InsulinRepositoryGORM{db: db}
+}
+
+// Create inserts a new dose.
+func (r *InsulinRepositoryGORM) Create(ctx context.Context, d *domain.InsulinDose) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Create(d).Error
+}
+
+// FindByTimeRange returns doses within a time range (inclusive), oldest first.
+func (r *InsulinRepositoryGORM) FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.InsulinDose, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var doses []*domain.InsulinDose
+	result := db.Where("timestamp >= ? AND timestamp <= ?", start, end).
+		Order("timestamp ASC, id ASC").
+		Find(&doses)
+
+	return doses, result.Error
+}
+
+// CarbRepositoryGORM is the GORM implementation of CarbRepository.
+type CarbRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewCarbRepository creates a new CarbRepository.
+func NewCarbRepository(db *gorm.DB) *CarbRepositoryGORM {
+	return &CarbRepositoryGORM{db: db}
+}
+
+// Create inserts a new intake.
+func (r *CarbRepositoryGORM) Create(ctx context.Context, c *domain.CarbIntake) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Create(c).Error
+}
+
+// FindByTimeRange returns intakes within a time range (inclusive), oldest first.
+func (r *CarbRepositoryGORM) FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.CarbIntake, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var intakes []*domain.CarbIntake
+	result := db.Where("timestamp >= ? AND timestamp <= ?", start, end).
+		Order("timestamp ASC, id ASC").
+		Find(&intakes)
+
+	return intakes, result.Error
+}
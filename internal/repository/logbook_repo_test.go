@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+func TestLogbookRepositories_FindByTimeRange(t *testing.T) {
+	db := setupTestDB(t)
+	insulinRepo := NewInsulinRepository(db)
+	carbRepo := NewCarbRepository(db)
+	ctx := context.Background()
+
+	ts := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	for _, d := range []*domain.InsulinDose{
+		{Timestamp: ts.Add(time.Hour), Units: 2, Type: domain.InsulinTypeRapid},
+		{Timestamp: ts, Units: 12, Type: domain.InsulinTypeLong},
+		{Timestamp: ts.Add(-48 * time.Hour), Units: 6, Type: domain.InsulinTypeRapid},
+	} {
+		if err := insulinRepo.Create(ctx, d); err != nil {
+			t.Fatalf("failed to log insulin: %v", err)
+		}
+	}
+	if err := carbRepo.Create(ctx, &domain.CarbIntake{Timestamp: ts, Grams: 45.5}); err != nil {
+		t.Fatalf("failed to log carbs: %v", err)
+	}
+
+	doses, err := insulinRepo.FindByTimeRange(ctx, ts.Add(-time.Hour), ts.Add(time.Hour))
+	if err != nil || len(doses) != 2 {
+		t.Fatalf("expected 2 doses, got %d (%v)", len(doses), err)
+	}
+	if doses[0].Type != domain.InsulinTypeLong || doses[1].Units != 2 {
+		t.Errorf("expected the doses oldest first, got %+v %+v", doses[0], doses[1])
+	}
+
+	intakes, err := carbRepo.FindByTimeRange(ctx, ts, ts)
+	if err != nil || len(intakes) != 1 || intakes[0].Grams != 45.5 {
+		t.Errorf("expected the 45.5 g intake, got %+v (%v)", intakes, err)
+	}
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// NoteRepositoryGORM is the GORM implementation of NoteRepository.
+type NoteRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewNoteRepository creates a new NoteRepository.
+func NewNoteRepository(db *gorm.DB) *NoteRepositoryGORM {
+	return &NoteRepositoryGORM{db: db}
+}
+
+// Create inserts a new note.
+func (r *NoteRepositoryGORM) Create(ctx context.Context, n *domain.Note) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Create(n).Error
+}
+
+// Update replaces the timestamp, text and tags of a note.
+// Returns persistence.ErrNotFound if no such note exists.
+func (r *NoteRepositoryGORM) Update(ctx context.Context, n *domain.Note) error {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Model(&domain.Note{}).Where("id = ?", n.ID).Updates(map[string]any{
+		"updated_at": time.Now().UTC(),
+		"timestamp":  n.Timestamp,
+		"text":       n.Text,
+		"tags":       n.Tags,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return persistence.ErrNotFound
+	}
+
+	return nil
+}
+
+// FindByID returns a note by its ID.
+// Returns persistence.ErrNotFound if no such note exists.
+func (r *NoteRepositoryGORM) FindByID(ctx context.Context, id uint) (*domain.Note, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var note domain.Note
+	result := db.First(&note, id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &note, nil
+}
+
+// FindWithFilters returns notes matching filters with pagination, newest first.
+func (r *NoteRepositoryGORM) FindWithFilters(ctx context.Context, filters NoteFilters, limit, offset int) ([]*domain.Note, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var notes []*domain.Note
+	result := applyNoteFilters(db.Model(&domain.Note{}), filters).
+		Order("timestamp DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&notes)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return notes, nil
+}
+
+// CountWithFilters returns total count of notes matching filters.
+func (r *NoteRepositoryGORM) CountWithFilters(ctx context.Context, filters NoteFilters) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var count int64
+	result := applyNoteFilters(db.Model(&domain.Note{}), filters).Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// Delete removes a note.
+// Returns persistence.ErrNotFound if no such note exists.
+func (r *NoteRepositoryGORM) Delete(ctx context.Context, id uint) error {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Delete(&domain.Note{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return persistence.ErrNotFound
+	}
+
+	return nil
+}
+
+// applyNoteFilters adds the WHERE clauses of filters to query.
+func applyNoteFilters(query *gorm.DB, filters NoteFilters) *gorm.DB {
+	if filters.StartTime != nil {
+		query = query.Where("timestamp >= ?", *filters.StartTime)
+	}
+	if filters.EndTime != nil {
+		query = query.Where("timestamp <= ?", *filters.EndTime)
+	}
+	if filters.Tag != nil {
+		// Tags are a JSON array of known tags: match the quoted tag
+		query = query.Where("tags LIKE ?", `%"`+*filters.Tag+`"%`)
+	}
+	return query
+}
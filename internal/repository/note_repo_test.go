@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+func TestNoteRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNoteRepository(db)
+	ctx := context.Background()
+
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	notes := []*domain.Note{
+		{Timestamp: now.Add(-10 * time.Hour), Text: "Pasta", Tags: domain.TagList{domain.NoteTagInsulin, domain.NoteTagMeal}},
+		{Timestamp: now.Add(-3 * time.Hour), Text: "Run", Tags: domain.TagList{domain.NoteTagExercise}},
+		{Timestamp: now, Text: "Breakfast", Tags: domain.TagList{domain.NoteTagMeal}},
+		{Timestamp: now.Add(-time.Hour), Text: "Untagged"},
+	}
+	for _, n := range notes {
+		if err := repo.Create(ctx, n); err != nil {
+			t.Fatalf("failed to create note: %v", err)
+		}
+	}
+
+	// Newest first, filtered by tag and time
+	meal := domain.NoteTagMeal
+	list, err := repo.FindWithFilters(ctx, NoteFilters{Tag: &meal}, 10, 0)
+	if err != nil || len(list) != 2 || list[0].Text != "Breakfast" || list[1].Text != "Pasta" {
+		t.Fatalf("expected the 2 meal notes, newest first, got %+v (%v)", list, err)
+	}
+	if len(list[1].Tags) != 2 {
+		t.Errorf("expected the tags to round-trip, got %v", list[1].Tags)
+	}
+	start := now.Add(-4 * time.Hour)
+	count, err := repo.CountWithFilters(ctx, NoteFilters{StartTime: &start})
+	if err != nil || count != 3 {
+		t.Errorf("expected 3 notes in the last 4 hours, got %d (%v)", count, err)
+	}
+
+	untagged, err := repo.FindByID(ctx, notes[3].ID)
+	if err != nil || untagged.Tags == nil || len(untagged.Tags) != 0 {
+		t.Errorf("expected an empty tag list, got %+v (%v)", untagged, err)
+	}
+
+	notes[1].Text = "Long run"
+	notes[1].Tags = domain.TagList{domain.NoteTagExercise, domain.NoteTagMeal}
+	if err := repo.Update(ctx, notes[1]); err != nil {
+		t.Fatalf("failed to update note: %v", err)
+	}
+	if count, _ := repo.CountWithFilters(ctx, NoteFilters{Tag: &meal}); count != 3 {
+		t.Errorf("expected 3 meal notes after the update, got %d", count)
+	}
+	updated, err := repo.FindByID(ctx, notes[1].ID)
+	if err != nil || updated.Text != "Long run" {
+		t.Errorf("expected the updated text, got %+v (%v)", updated, err)
+	}
+
+	if err := repo.Delete(ctx, notes[0].ID); err != nil {
+		t.Fatalf("failed to delete note: %v", err)
+	}
+	if _, err := repo.FindByID(ctx, notes[0].ID); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := repo.Delete(ctx, notes[0].ID); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound on second delete, got %v", err)
+	}
+	if err := repo.Update(ctx, &domain.Note{ID: notes[0].ID, Timestamp: now, Text: "Gone"}); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound when updating a deleted note, got %v", err)
+	}
+}
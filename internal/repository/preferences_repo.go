@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// PreferencesRepositoryGORM is the GORM implementation of PreferencesRepository.
+// This is a singleton repository - only one display preferences record is expected.
+type PreferencesRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewPreferencesRepository creates a new PreferencesRepository.
+func NewPreferencesRepository(db *gorm.DB) *PreferencesRepositoryGORM {
+	return &PreferencesRepositoryGORM{db: db}
+}
+
+// Save creates or updates the display preferences (singleton).
+func (r *PreferencesRepositoryGORM) Save(ctx context.Context, p *domain.DisplayPreferences) error {
+	db := txOrDefault(ctx, r.db)
+
+	// For singleton, we always update the first record or create if doesn't exist
+	var existing domain.DisplayPreferences
+	result := db.Session(&gorm.Session{Logger: logger.Discard}).First(&existing)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// No record exists, create new one
+			return db.Create(p).Error
+		}
+		return result.Error
+	}
+
+	// Record exists, update it
+	p.ID = existing.ID // Preserve the ID
+	return db.Save(p).Error
+}
+
+// Find returns the display preferences (only one record expected).
+func (r *PreferencesRepositoryGORM) Find(ctx context.Context) (*domain.DisplayPreferences, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var prefs domain.DisplayPreferences
+	result := db.First(&prefs)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &prefs, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// PrivacyRepositoryGORM is the GORM implementation of PrivacyRepository.
+type PrivacyRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewPrivacyRepository creates a new PrivacyRepository.
+func NewPrivacyRepository(db *gorm.DB) *PrivacyRepositoryGORM {
+	return &PrivacyRepositoryGORM{db: db}
+}
+
+// personalDataModels lists the tables holding personal data, in erasure order.
+// API tokens and signing keys are credentials of the instance, not personal data.
+var personalDataModels = []struct {
+	name  string
+	model any
+}{
+	{"measurements", &domain.GlucoseMeasurement{}},
+	{"rollups", &domain.GlucoseRollup{}},
+	{"sensors", &domain.SensorConfig{}},
+	{"treatments", &domain.TreatmentEntry{}},
+	{"insulin", &domain.InsulinDose{}},
+	{"carbs", &domain.CarbIntake{}},
+	{"alerts", &domain.Alert{}},
+	{"glucoseEvents", &domain.GlucoseEvent{}},
+	{"dailySummaries", &domain.DailySummary{}},
+	{"user", &domain.UserPreferences{}},
+	{"device", &domain.DeviceInfo{}},
+	{"targets", &domain.GlucoseTargets{}},
+	{"dashboard", &domain.DashboardConfig{}},
+	{"preferences", &domain.DisplayPreferences{}},
+	{"views", &domain.SavedView{}},
+	{"notes", &domain.Note{}},
+	{"attachments", &domain.SensorAttachment{}},
+	{"jobs", &domain.Job{}},                         // Payloads hold imported treatments
+	{"upstreamSessions", &domain.UpstreamSession{}}, // Sealed LibreView token, account and patient IDs
+}
+
+// Export returns all stored personal data, oldest records first.
+func (r *PrivacyRepositoryGORM) Export(ctx context.Context) (*PersonalData, error) {
+	db := txOrDefault(ctx, r.db)
+
+	data := &PersonalData{
+		Measurements: []*domain.GlucoseMeasurement{},
+		Rollups:      []*domain.GlucoseRollup{},
+		Sensors:      []*domain.SensorConfig{},
+		Treatments:   []*domain.TreatmentEntry{},
+		Insulin:      []*domain.InsulinDose{},
+		Carbs:        []*domain.CarbIntake{},
+		Alerts:       []*domain.Alert{},
+		Views:        []*domain.SavedView{},
+		Notes:        []*domain.Note{},
+		Attachments:  []*domain.SensorAttachment{},
+	}
+
+	if err := db.Order("timestamp ASC").Find(&data.Measurements).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Order("start_time ASC").Find(&data.Rollups).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Order("activation ASC").Find(&data.Sensors).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Order("timestamp ASC").Find(&data.Treatments).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Order("timestamp ASC, id ASC").Find(&data.Insulin).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Order("timestamp ASC, id ASC").Find(&data.Carbs).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Order("fired_at ASC").Find(&data.Alerts).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Order("created_at ASC").Find(&data.Views).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Order("timestamp ASC, id ASC").Find(&data.Notes).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Order("created_at ASC, id ASC").Find(&data.Attachments).Error; err != nil {
+		return nil, err
+	}
+
+	var err error
+	if data.User, err = findSingleton[domain.UserPreferences](db); err != nil {
+		return nil, err
+	}
+	if data.Device, err = findSingleton[domain.DeviceInfo](db); err != nil {
+		return nil, err
+	}
+	if data.Targets, err = findSingleton[domain.GlucoseTargets](db); err != nil {
+		return nil, err
+	}
+	if data.Dashboard, err = findSingleton[domain.DashboardConfig](db); err != nil {
+		return nil, err
+	}
+	if data.Preferences, err = findSingleton[domain.DisplayPreferences](db); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// EraseAll deletes all personal data and returns the number of rows deleted per table.
+// Run it within a transaction so that a failure leaves the data untouched.
+func (r *PrivacyRepositoryGORM) EraseAll(ctx context.Context) (map[string]int64, error) {
+	db := txOrDefault(ctx, r.db).Session(&gorm.Session{AllowGlobalUpdate: true})
+
+	deleted := make(map[string]int64, len(personalDataModels))
+	for _, m := range personalDataModels {
+		result := db.Delete(m.model)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		deleted[m.name] = result.RowsAffected
+	}
+
+	return deleted, nil
+}
+
+// findSingleton returns the single record of a table, or nil if there is none.
+func findSingleton[T any](db *gorm.DB) (*T, error) {
+	var record T
+	if err := db.First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
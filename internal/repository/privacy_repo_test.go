@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+func TestPrivacyRepository_ExportAndErase(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPrivacyRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	glucoseRepo := NewGlucoseRepository(db)
+	for i := 0; i < 3; i++ {
+		ts := now.Add(time.Duration(i) * time.Minute)
+		if _, err := glucoseRepo.Save(ctx, &domain.GlucoseMeasurement{FactoryTimestamp: ts, Timestamp: ts, ValueInMgPerDl: 100 + i}); err != nil {
+			t.Fatalf("failed to save measurement: %v", err)
+		}
+	}
+	if err := NewUserRepository(db).Save(ctx, &domain.UserPreferences{UserID: "user-1", FirstName: "Jane"}); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+	if err := NewViewRepository(db).Save(ctx, &domain.SavedView{Name: "highs", Query: "value_mgdl > 180", Aggregation: domain.ViewAggregationList}); err != nil {
+		t.Fatalf("failed to save view: %v", err)
+	}
+	if err := NewNoteRepository(db).Create(ctx, &domain.Note{Timestamp: now, Text: "Pizza", Tags: domain.TagList{domain.NoteTagMeal}}); err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+	if err := NewInsulinRepository(db).Create(ctx, &domain.InsulinDose{Timestamp: now, Units: 4.5, Type: domain.InsulinTypeRapid}); err != nil {
+		t.Fatalf("failed to log insulin: %v", err)
+	}
+	if err := NewUpstreamSessionRepository(db).Save(ctx, &domain.UpstreamSession{Account: "primary", Salt: "salt", Sealed: "sealed"}); err != nil {
+		t.Fatalf("failed to save upstream session: %v", err)
+	}
+	if err := NewTokenRepository(db).Create(ctx, &domain.APIToken{Name: "ci", TokenHash: "hash", Scope: domain.TokenScopeRead}); err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	data, err := repo.Export(ctx)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if len(data.Measurements) != 3 || data.Measurements[0].ValueInMgPerDl != 100 {
+		t.Errorf("expected 3 measurements oldest first, got %d", len(data.Measurements))
+	}
+	if data.User == nil || data.User.FirstName != "Jane" {
+		t.Errorf("expected user preferences, got %+v", data.User)
+	}
+	if len(data.Views) != 1 {
+		t.Errorf("expected 1 saved view, got %d", len(data.Views))
+	}
+	if len(data.Notes) != 1 || data.Notes[0].Text != "Pizza" {
+		t.Errorf("expected 1 note, got %+v", data.Notes)
+	}
+	if len(data.Insulin) != 1 || len(data.Carbs) != 0 {
+		t.Errorf("expected 1 insulin dose and no carbs, got %d and %d", len(data.Insulin), len(data.Carbs))
+	}
+	if data.Device != nil {
+		t.Errorf("expected no device, got %+v", data.Device)
+	}
+
+	deleted, err := repo.EraseAll(ctx)
+	if err != nil {
+		t.Fatalf("erase failed: %v", err)
+	}
+	if deleted["measurements"] != 3 || deleted["user"] != 1 || deleted["views"] != 1 || deleted["notes"] != 1 || deleted["insulin"] != 1 || deleted["upstreamSessions"] != 1 {
+		t.Errorf("unexpected deleted counts: %v", deleted)
+	}
+
+	data, err = repo.Export(ctx)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if len(data.Measurements) != 0 || data.User != nil {
+		t.Errorf("expected no data after erasure, got %d measurements, user %+v", len(data.Measurements), data.User)
+	}
+
+	if _, err := NewUpstreamSessionRepository(db).Find(ctx, "primary"); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected upstream session to be erased, got %v", err)
+	}
+
+	// Credentials of the instance are kept
+	tokens, err := NewTokenRepository(db).FindAll(ctx)
+	if err != nil || len(tokens) != 1 {
+		t.Errorf("expected API token to be kept, got %d (%v)", len(tokens), err)
+	}
+}
@@ -23,8 +23,12 @@ func NewSensorRepository(db *gorm.DB) *SensorRepositoryGORM {
 }
 
 // Save creates or updates a sensor (upsert by serial number).
+// A sensor keeps the patient it was first stored for.
 func (r *SensorRepositoryGORM) Save(ctx context.Context, s *domain.SensorConfig) error {
 	db := txOrDefault(ctx, r.db)
+	if s.PatientID == "" {
+		s.PatientID = PatientFromContext(ctx)
+	}
 
 	// Upsert: Update fields on conflict with serial_number
 	// Note: ended_at is NOT updated on conflict - it's only set via SetEndedAt
@@ -61,7 +65,7 @@ func (r *SensorRepositoryGORM) FindCurrent(ctx context.Context) (*domain.SensorC
 	db := txOrDefault(ctx, r.db)
 
 	var sensor domain.SensorConfig
-	result := db.Where("ended_at IS NULL").Order("detected_at DESC").First(&sensor)
+	result := scopePatient(ctx, db).Where("ended_at IS NULL").Order("detected_at DESC").First(&sensor)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -78,7 +82,7 @@ func (r *SensorRepositoryGORM) FindAll(ctx context.Context) ([]*domain.SensorCon
 	db := txOrDefault(ctx, r.db)
 
 	var sensors []*domain.SensorConfig
-	result := db.Order("detected_at DESC").Find(&sensors)
+	result := scopePatient(ctx, db).Order("detected_at DESC").Find(&sensors)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -91,14 +95,7 @@ func (r *SensorRepositoryGORM) FindAll(ctx context.Context) ([]*domain.SensorCon
 func (r *SensorRepositoryGORM) FindWithFilters(ctx context.Context, filters SensorFilters, limit, offset int) ([]*domain.SensorConfig, error) {
 	db := txOrDefault(ctx, r.db)
 
-	query := db.Model(&domain.SensorConfig{})
-
-	if filters.StartTime != nil {
-		query = query.Where("activation >= ?", *filters.StartTime)
-	}
-	if filters.EndTime != nil {
-		query = query.Where("activation <= ?", *filters.EndTime)
-	}
+	query := applySensorFilters(scopePatient(ctx, db.Model(&domain.SensorConfig{})), filters)
 
 	var sensors []*domain.SensorConfig
 	result := query.
@@ -118,14 +115,7 @@ func (r *SensorRepositoryGORM) FindWithFilters(ctx context.Context, filters Sens
 func (r *SensorRepositoryGORM) CountWithFilters(ctx context.Context, filters SensorFilters) (int64, error) {
 	db := txOrDefault(ctx, r.db)
 
-	query := db.Model(&domain.SensorConfig{})
-
-	if filters.StartTime != nil {
-		query = query.Where("activation >= ?", *filters.StartTime)
-	}
-	if filters.EndTime != nil {
-		query = query.Where("activation <= ?", *filters.EndTime)
-	}
+	query := applySensorFilters(scopePatient(ctx, db.Model(&domain.SensorConfig{})), filters)
 
 	var count int64
 	result := query.Count(&count)
@@ -140,35 +130,48 @@ func (r *SensorRepositoryGORM) CountWithFilters(ctx context.Context, filters Sen
 // GetStatistics returns aggregated sensor lifecycle statistics computed by SQL.
 func (r *SensorRepositoryGORM) GetStatistics(ctx context.Context, filters SensorStatisticsFilters) (*SensorStatisticsResult, error) {
 	db := txOrDefault(ctx, r.db)
+	duration := daysBetweenExpr(isPostgres(db), "activation", "ended_at")
 
 	selectClause := `
 		COUNT(*) as total_sensors,
 		COALESCE(SUM(CASE WHEN ended_at IS NOT NULL THEN 1 ELSE 0 END), 0) as completed_sensors,
 		COALESCE(AVG(CASE WHEN ended_at IS NOT NULL
-			THEN (julianday(ended_at) - julianday(activation)) END), 0) as avg_duration,
+			THEN ` + duration + ` END), 0) as avg_duration,
 		COALESCE(MIN(CASE WHEN ended_at IS NOT NULL
-			THEN (julianday(ended_at) - julianday(activation)) END), 0) as min_duration,
+			THEN ` + duration + ` END), 0) as min_duration,
 		COALESCE(MAX(CASE WHEN ended_at IS NOT NULL
-			THEN (julianday(ended_at) - julianday(activation)) END), 0) as max_duration,
-		COALESCE(AVG(duration_days), 0) as avg_expected
+			THEN ` + duration + ` END), 0) as max_duration,
+		COALESCE(AVG(duration_days), 0) as avg_expected,
+		COUNT(rating) as rated_sensors,
+		AVG(rating) as avg_rating
 	`
 
-	query := db.Model(&domain.SensorConfig{}).Select(selectClause)
+	query := scopePatient(ctx, db.Model(&domain.SensorConfig{})).Select(selectClause)
+	query = applySensorFilters(query, SensorFilters(filters))
+
+	var result SensorStatisticsResult
+	if err := query.Scan(&result).Error; err != nil {
+		return nil, err
+	}
 
-	// Apply time filters
+	return &result, nil
+}
+
+// applySensorFilters adds the WHERE clauses of filters to query.
+func applySensorFilters(query *gorm.DB, filters SensorFilters) *gorm.DB {
 	if filters.StartTime != nil {
 		query = query.Where("activation >= ?", *filters.StartTime)
 	}
 	if filters.EndTime != nil {
 		query = query.Where("activation <= ?", *filters.EndTime)
 	}
-
-	var result SensorStatisticsResult
-	if err := query.Scan(&result).Error; err != nil {
-		return nil, err
+	if filters.EndedStart != nil {
+		query = query.Where("ended_at >= ?", *filters.EndedStart)
 	}
-
-	return &result, nil
+	if filters.EndedEnd != nil {
+		query = query.Where("ended_at <= ?", *filters.EndedEnd)
+	}
+	return query
 }
 
 // SetEndedAt marks a sensor as ended (replaced by a new sensor).
@@ -189,3 +192,70 @@ func (r *SensorRepositoryGORM) SetEndedAt(ctx context.Context, serial string, en
 
 	return nil
 }
+
+// SetApplicationSite records the body site a sensor is applied to.
+func (r *SensorRepositoryGORM) SetApplicationSite(ctx context.Context, serial string, site string) error {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Model(&domain.SensorConfig{}).
+		Where("serial_number = ?", serial).
+		Update("application_site", site)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return persistence.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetFeedback records the note and rating of a sensor.
+func (r *SensorRepositoryGORM) SetFeedback(ctx context.Context, serial string, note string, rating *int) error {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Model(&domain.SensorConfig{}).
+		Where("serial_number = ?", serial).
+		Updates(map[string]interface{}{"note": note, "rating": rating})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return persistence.ErrNotFound
+	}
+
+	return nil
+}
+
+// FindPrevious returns the most recent sensor activated before activation.
+func (r *SensorRepositoryGORM) FindPrevious(ctx context.Context, activation time.Time) (*domain.SensorConfig, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var sensor domain.SensorConfig
+	result := scopePatient(ctx, db).Where("activation < ?", activation).Order("activation DESC").First(&sensor)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &sensor, nil
+}
+
+// AssignPatient assigns the sensors stored without a patient to patientID.
+// Returns the number of sensors assigned.
+func (r *SensorRepositoryGORM) AssignPatient(ctx context.Context, patientID string) (int64, error) {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Model(&domain.SensorConfig{}).
+		Where("patient_id = ?", "").
+		Update("patient_id", patientID)
+
+	return result.RowsAffected, result.Error
+}
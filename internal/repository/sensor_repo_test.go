@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -282,3 +283,298 @@ func TestSensorRepository_FindCurrent_ReturnsLatestWhenMultiple(t *testing.T) {
 		t.Errorf("expected SerialNumber = SENSOR_2 (most recent), got %s", current.SerialNumber)
 	}
 }
+
+func TestSensorRepository_SetApplicationSite(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSensorRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	sensor := &domain.SensorConfig{
+		SerialNumber: "SITE_SENSOR",
+		Activation:   now.AddDate(0, 0, -1),
+		ExpiresAt:    now.AddDate(0, 0, 14),
+		SensorType:   4,
+		DurationDays: 15,
+		DetectedAt:   now,
+	}
+	if err := repo.Save(ctx, sensor); err != nil {
+		t.Fatalf("failed to save sensor: %v", err)
+	}
+
+	if err := repo.SetApplicationSite(ctx, "SITE_SENSOR", domain.SensorSiteLeftArm); err != nil {
+		t.Fatalf("failed to set application site: %v", err)
+	}
+
+	// Upserts from the daemon must not clear the recorded site
+	lastMeasurement := now
+	sensor.LastMeasurementAt = &lastMeasurement
+	if err := repo.Save(ctx, sensor); err != nil {
+		t.Fatalf("failed to update sensor: %v", err)
+	}
+
+	retrieved, err := repo.FindBySerialNumber(ctx, "SITE_SENSOR")
+	if err != nil {
+		t.Fatalf("failed to retrieve sensor: %v", err)
+	}
+	if retrieved.ApplicationSite != domain.SensorSiteLeftArm {
+		t.Errorf("expected site %s, got %q", domain.SensorSiteLeftArm, retrieved.ApplicationSite)
+	}
+
+	if err := repo.SetApplicationSite(ctx, "UNKNOWN", domain.SensorSiteLeftArm); err != persistence.ErrNotFound {
+		t.Errorf("expected ErrNotFound for unknown sensor, got %v", err)
+	}
+}
+
+func TestSensorRepository_SetFeedback(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSensorRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	for i, serial := range []string{"RATED_SENSOR", "UNRATED_SENSOR"} {
+		sensor := &domain.SensorConfig{
+			SerialNumber: serial,
+			Activation:   now.AddDate(0, 0, -1-i),
+			ExpiresAt:    now.AddDate(0, 0, 14-i),
+			SensorType:   4,
+			DurationDays: 15,
+			DetectedAt:   now,
+		}
+		if err := repo.Save(ctx, sensor); err != nil {
+			t.Fatalf("failed to save sensor: %v", err)
+		}
+	}
+
+	rating := 2
+	if err := repo.SetFeedback(ctx, "RATED_SENSOR", "Peeled off on day 9", &rating); err != nil {
+		t.Fatalf("failed to set feedback: %v", err)
+	}
+
+	retrieved, err := repo.FindBySerialNumber(ctx, "RATED_SENSOR")
+	if err != nil {
+		t.Fatalf("failed to retrieve sensor: %v", err)
+	}
+	if retrieved.Note != "Peeled off on day 9" {
+		t.Errorf("expected note to be saved, got %q", retrieved.Note)
+	}
+	if retrieved.Rating == nil || *retrieved.Rating != 2 {
+		t.Errorf("expected rating 2, got %v", retrieved.Rating)
+	}
+
+	stats, err := repo.GetStatistics(ctx, SensorStatisticsFilters{})
+	if err != nil {
+		t.Fatalf("failed to get statistics: %v", err)
+	}
+	if stats.RatedSensors != 1 {
+		t.Errorf("expected 1 rated sensor, got %d", stats.RatedSensors)
+	}
+	if stats.AvgRating == nil || *stats.AvgRating != 2 {
+		t.Errorf("expected average rating 2, got %v", stats.AvgRating)
+	}
+
+	// Clearing the rating leaves no rated sensor
+	if err := repo.SetFeedback(ctx, "RATED_SENSOR", "", nil); err != nil {
+		t.Fatalf("failed to clear feedback: %v", err)
+	}
+	stats, err = repo.GetStatistics(ctx, SensorStatisticsFilters{})
+	if err != nil {
+		t.Fatalf("failed to get statistics: %v", err)
+	}
+	if stats.RatedSensors != 0 || stats.AvgRating != nil {
+		t.Errorf("expected no rated sensor, got %d (avg %v)", stats.RatedSensors, stats.AvgRating)
+	}
+
+	if err := repo.SetFeedback(ctx, "UNKNOWN", "", nil); err != persistence.ErrNotFound {
+		t.Errorf("expected ErrNotFound for unknown sensor, got %v", err)
+	}
+}
+
+func TestSensorRepository_GetStatistics_Durations(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSensorRepository(db)
+	ctx := context.Background()
+
+	// Two ended sensors worn 10 and 14.5 days, and the current one
+	start := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	worn := []time.Duration{240 * time.Hour, 348 * time.Hour, 0}
+	serials := []string{"SENSOR_A", "SENSOR_B", "SENSOR_C"}
+	for i, d := range worn {
+		activation := start.AddDate(0, 0, 20*i)
+		sensor := &domain.SensorConfig{
+			SerialNumber: serials[i],
+			Activation:   activation,
+			ExpiresAt:    activation.AddDate(0, 0, 15),
+			SensorType:   4,
+			DurationDays: 15,
+			DetectedAt:   activation,
+		}
+		if d > 0 {
+			endedAt := activation.Add(d)
+			sensor.EndedAt = &endedAt
+		}
+		if err := repo.Save(ctx, sensor); err != nil {
+			t.Fatalf("failed to save sensor: %v", err)
+		}
+	}
+
+	stats, err := repo.GetStatistics(ctx, SensorStatisticsFilters{})
+	if err != nil {
+		t.Fatalf("failed to get statistics: %v", err)
+	}
+	if stats.TotalSensors != 3 || stats.CompletedSensors != 2 {
+		t.Errorf("expected 3 sensors, 2 completed, got %d, %d", stats.TotalSensors, stats.CompletedSensors)
+	}
+	if math.Abs(stats.MinDuration-10) > 1e-6 || math.Abs(stats.AvgDuration-12.25) > 1e-6 || math.Abs(stats.MaxDuration-14.5) > 1e-6 {
+		t.Errorf("expected durations 10/12.25/14.5 days, got %v/%v/%v", stats.MinDuration, stats.AvgDuration, stats.MaxDuration)
+	}
+	if stats.AvgExpected != 15 {
+		t.Errorf("expected 15 expected days, got %v", stats.AvgExpected)
+	}
+}
+
+func TestSensorRepository_GetStatistics_EndedFilter(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSensorRepository(db)
+	ctx := context.Background()
+
+	// Sensors activated on March 1st and 16th, ended on the 15th and 30th, and the current one
+	start := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	for i, serial := range []string{"SENSOR_A", "SENSOR_B", "SENSOR_C"} {
+		activation := start.AddDate(0, 0, 15*i)
+		sensor := &domain.SensorConfig{
+			SerialNumber: serial,
+			Activation:   activation,
+			ExpiresAt:    activation.AddDate(0, 0, 15),
+			SensorType:   4,
+			DurationDays: 15,
+			DetectedAt:   activation,
+		}
+		if i < 2 {
+			endedAt := activation.AddDate(0, 0, 14)
+			sensor.EndedAt = &endedAt
+		}
+		if err := repo.Save(ctx, sensor); err != nil {
+			t.Fatalf("failed to save sensor: %v", err)
+		}
+	}
+
+	// Ended in the second half of March: SENSOR_B only, the current sensor is excluded
+	endedStart := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+	endedEnd := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	stats, err := repo.GetStatistics(ctx, SensorStatisticsFilters{EndedStart: &endedStart, EndedEnd: &endedEnd})
+	if err != nil {
+		t.Fatalf("failed to get statistics: %v", err)
+	}
+	if stats.TotalSensors != 1 || stats.CompletedSensors != 1 {
+		t.Errorf("expected 1 completed sensor, got %d, %d", stats.TotalSensors, stats.CompletedSensors)
+	}
+
+	// Combined with the activation period
+	activationEnd := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	stats, err = repo.GetStatistics(ctx, SensorStatisticsFilters{StartTime: &start, EndTime: &activationEnd, EndedStart: &endedStart, EndedEnd: &endedEnd})
+	if err != nil {
+		t.Fatalf("failed to get statistics: %v", err)
+	}
+	if stats.TotalSensors != 0 {
+		t.Errorf("expected no sensor, got %d", stats.TotalSensors)
+	}
+
+	// The same filters select the sensors
+	count, err := repo.CountWithFilters(ctx, SensorFilters{EndedStart: &endedStart, EndedEnd: &endedEnd})
+	if err != nil {
+		t.Fatalf("failed to count sensors: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 sensor, got %d", count)
+	}
+}
+
+func TestSensorRepository_FindPrevious(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSensorRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	for i, serial := range []string{"OLDEST", "PREVIOUS", "CURRENT"} {
+		activation := now.AddDate(0, 0, -15*(2-i))
+		sensor := &domain.SensorConfig{
+			SerialNumber: serial,
+			Activation:   activation,
+			ExpiresAt:    activation.AddDate(0, 0, 15),
+			SensorType:   4,
+			DurationDays: 15,
+			DetectedAt:   activation,
+		}
+		if err := repo.Save(ctx, sensor); err != nil {
+			t.Fatalf("failed to save sensor: %v", err)
+		}
+	}
+
+	current, err := repo.FindBySerialNumber(ctx, "CURRENT")
+	if err != nil {
+		t.Fatalf("failed to retrieve sensor: %v", err)
+	}
+
+	previous, err := repo.FindPrevious(ctx, current.Activation)
+	if err != nil {
+		t.Fatalf("FindPrevious: %v", err)
+	}
+	if previous.SerialNumber != "PREVIOUS" {
+		t.Errorf("expected PREVIOUS, got %s", previous.SerialNumber)
+	}
+
+	oldest, err := repo.FindBySerialNumber(ctx, "OLDEST")
+	if err != nil {
+		t.Fatalf("failed to retrieve sensor: %v", err)
+	}
+	if _, err := repo.FindPrevious(ctx, oldest.Activation); err != persistence.ErrNotFound {
+		t.Errorf("expected ErrNotFound before the first sensor, got %v", err)
+	}
+}
+
+func TestSensorRepository_PatientScope(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSensorRepository(db)
+	ctx := context.Background()
+	const patientA = "6a1f3a4e-0000-4000-8000-00000000000a"
+	const patientB = "6a1f3a4e-0000-4000-8000-00000000000b"
+
+	now := time.Now().UTC()
+	newSensor := func(serial string) *domain.SensorConfig {
+		return &domain.SensorConfig{
+			SerialNumber: serial,
+			Activation:   now.AddDate(0, 0, -1),
+			ExpiresAt:    now.AddDate(0, 0, 14),
+			SensorType:   4,
+			DurationDays: 15,
+			DetectedAt:   now,
+		}
+	}
+
+	// A sensor stored before patients were tracked belongs to the followed patient
+	if err := repo.Save(ctx, newSensor("SENSOR_A")); err != nil {
+		t.Fatalf("failed to save sensor: %v", err)
+	}
+	if assigned, err := repo.AssignPatient(ctx, patientA); err != nil || assigned != 1 {
+		t.Fatalf("AssignPatient: %d, %v", assigned, err)
+	}
+	if err := repo.Save(WithPatient(ctx, patientB), newSensor("SENSOR_B")); err != nil {
+		t.Fatalf("failed to save sensor: %v", err)
+	}
+
+	for patientID, serial := range map[string]string{patientA: "SENSOR_A", patientB: "SENSOR_B"} {
+		current, err := repo.FindCurrent(WithPatient(ctx, patientID))
+		if err != nil {
+			t.Fatalf("failed to find current sensor: %v", err)
+		}
+		if current.SerialNumber != serial || current.PatientID != patientID {
+			t.Errorf("expected %s of %s, got %s of %s", serial, patientID, current.SerialNumber, current.PatientID)
+		}
+	}
+
+	all, err := repo.FindAll(ctx)
+	if err != nil || len(all) != 2 {
+		t.Errorf("expected 2 sensors without a patient scope, got %d (%v)", len(all), err)
+	}
+}
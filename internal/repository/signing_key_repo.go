@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// SigningKeyRepositoryGORM is the GORM implementation of SigningKeyRepository.
+type SigningKeyRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewSigningKeyRepository creates a new SigningKeyRepository.
+func NewSigningKeyRepository(db *gorm.DB) *SigningKeyRepositoryGORM {
+	return &SigningKeyRepositoryGORM{db: db}
+}
+
+// Create inserts a new signing key.
+func (r *SigningKeyRepositoryGORM) Create(ctx context.Context, k *domain.SigningKey) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Create(k).Error
+}
+
+// FindAll returns all keys (including retired ones) ordered by creation date descending.
+func (r *SigningKeyRepositoryGORM) FindAll(ctx context.Context) ([]*domain.SigningKey, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var keys []*domain.SigningKey
+	result := db.Order("created_at DESC, id DESC").Find(&keys)
+	return keys, result.Error
+}
+
+// FindCurrent returns the newest non-retired key.
+func (r *SigningKeyRepositoryGORM) FindCurrent(ctx context.Context) (*domain.SigningKey, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var key domain.SigningKey
+	result := db.Where("retired_at IS NULL").Order("created_at DESC, id DESC").First(&key)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &key, nil
+}
+
+// Retire sets RetiredAt on a key that is not already retired.
+// Returns persistence.ErrNotFound if no such key exists.
+func (r *SigningKeyRepositoryGORM) Retire(ctx context.Context, id uint, at time.Time) error {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Model(&domain.SigningKey{}).
+		Where("id = ? AND retired_at IS NULL", id).
+		Update("retired_at", at)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return persistence.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// TokenRepositoryGORM is the GORM implementation of TokenRepository.
+type TokenRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewTokenRepository creates a new TokenRepository.
+func NewTokenRepository(db *gorm.DB) *TokenRepositoryGORM {
+	return &TokenRepositoryGORM{db: db}
+}
+
+// Create inserts a new token.
+func (r *TokenRepositoryGORM) Create(ctx context.Context, t *domain.APIToken) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Create(t).Error
+}
+
+// FindAll returns all tokens (including revoked and expired) ordered by creation date descending.
+func (r *TokenRepositoryGORM) FindAll(ctx context.Context) ([]*domain.APIToken, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var tokens []*domain.APIToken
+	result := db.Order("created_at DESC, id DESC").Find(&tokens)
+	return tokens, result.Error
+}
+
+// FindByHash returns the token with the given hash.
+func (r *TokenRepositoryGORM) FindByHash(ctx context.Context, hash string) (*domain.APIToken, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var token domain.APIToken
+	result := db.Where("token_hash = ?", hash).First(&token)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &token, nil
+}
+
+// Revoke sets RevokedAt on a token that is not already revoked.
+// Returns persistence.ErrNotFound if no such active token exists.
+func (r *TokenRepositoryGORM) Revoke(ctx context.Context, id uint, at time.Time) error {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Model(&domain.APIToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return persistence.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateLastUsed records the last successful authentication of a token.
+func (r *TokenRepositoryGORM) UpdateLastUsed(ctx context.Context, id uint, at time.Time) error {
+	db := txOrDefault(ctx, r.db)
+
+	return db.Model(&domain.APIToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+func TestTokenRepository_CreateAndFindByHash(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTokenRepository(db)
+	ctx := context.Background()
+
+	token := &domain.APIToken{Name: "display", Prefix: "glc_abcd", TokenHash: "hash1", Scope: domain.TokenScopeRead}
+	if err := repo.Create(ctx, token); err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	found, err := repo.FindByHash(ctx, "hash1")
+	if err != nil {
+		t.Fatalf("failed to find token: %v", err)
+	}
+	if found.ID != token.ID || found.Name != "display" {
+		t.Errorf("unexpected token: %+v", found)
+	}
+
+	if _, err := repo.FindByHash(ctx, "missing"); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTokenRepository_RevokeAndLastUsed(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTokenRepository(db)
+	ctx := context.Background()
+
+	token := &domain.APIToken{Name: "cli", Prefix: "glc_efgh", TokenHash: "hash2", Scope: domain.TokenScopeAdmin}
+	if err := repo.Create(ctx, token); err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := repo.UpdateLastUsed(ctx, token.ID, now); err != nil {
+		t.Fatalf("failed to update last used: %v", err)
+	}
+	if err := repo.Revoke(ctx, token.ID, now); err != nil {
+		t.Fatalf("failed to revoke token: %v", err)
+	}
+
+	// Revoking twice reports not found
+	if err := repo.Revoke(ctx, token.ID, now); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound on second revoke, got %v", err)
+	}
+
+	tokens, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to list tokens: %v", err)
+	}
+	if len(tokens) != 1 {
+		t.Fatalf("expected 1 token, got %d", len(tokens))
+	}
+	if tokens[0].LastUsedAt == nil || !tokens[0].LastUsedAt.Equal(now) {
+		t.Errorf("expected lastUsedAt %v, got %v", now, tokens[0].LastUsedAt)
+	}
+	if tokens[0].RevokedAt == nil {
+		t.Error("expected token to be revoked")
+	}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// TreatmentRepositoryGORM is the GORM implementation of TreatmentRepository.
+type TreatmentRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewTreatmentRepository creates a new TreatmentRepository.
+func NewTreatmentRepository(db *gorm.DB) *TreatmentRepositoryGORM {
+	return &TreatmentRepositoryGORM{db: db}
+}
+
+// Save creates or ignores a treatment.
+// Returns (true, nil) if inserted, (false, nil) if duplicate was ignored.
+func (r *TreatmentRepositoryGORM) Save(ctx context.Context, t *domain.TreatmentEntry) (bool, error) {
+	db := txOrDefault(ctx, r.db)
+
+	// ON CONFLICT DO NOTHING - ignore entries already imported from the same export
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "timestamp"}, {Name: "type"}, {Name: "source"}},
+		DoNothing: true,
+	}).Create(t)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindByTimeRange returns treatments within a time range (inclusive), oldest first.
+func (r *TreatmentRepositoryGORM) FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.TreatmentEntry, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var treatments []*domain.TreatmentEntry
+	result := db.Where("timestamp >= ? AND timestamp <= ?", start, end).
+		Order("timestamp ASC, id ASC").
+		Find(&treatments)
+
+	return treatments, result.Error
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+func TestTreatmentRepository_SaveIgnoresDuplicates(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTreatmentRepository(db)
+	ctx := context.Background()
+
+	ts := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	newBolus := func() *domain.TreatmentEntry {
+		return &domain.TreatmentEntry{Timestamp: ts, Type: domain.TreatmentTypeBolus, Source: domain.TreatmentSourceTandem, Insulin: 4.5}
+	}
+
+	inserted, err := repo.Save(ctx, newBolus())
+	if err != nil || !inserted {
+		t.Fatalf("expected first save to insert, got %v %v", inserted, err)
+	}
+	inserted, err = repo.Save(ctx, newBolus())
+	if err != nil || inserted {
+		t.Fatalf("expected duplicate to be ignored, got %v %v", inserted, err)
+	}
+
+	// Same time from another source is a different treatment
+	other := newBolus()
+	other.Source = domain.TreatmentSourceOmnipod
+	if inserted, err := repo.Save(ctx, other); err != nil || !inserted {
+		t.Fatalf("expected other source to insert, got %v %v", inserted, err)
+	}
+
+	treatments, err := repo.FindByTimeRange(ctx, ts.Add(-time.Hour), ts.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to find treatments: %v", err)
+	}
+	if len(treatments) != 2 {
+		t.Errorf("expected 2 treatments, got %d", len(treatments))
+	}
+}
@@ -26,6 +26,22 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		&domain.UserPreferences{},
 		&domain.DeviceInfo{},
 		&domain.GlucoseTargets{},
+		&domain.DashboardConfig{},
+		&domain.DisplayPreferences{},
+		&domain.SavedView{},
+		&domain.Note{},
+		&domain.InsulinDose{},
+		&domain.CarbIntake{},
+		&domain.APIToken{},
+		&domain.Alert{},
+		&domain.TreatmentEntry{},
+		&domain.UpstreamOutage{},
+		&domain.UpstreamSession{},
+		&domain.SensorAttachment{},
+		&domain.Job{},
+		&domain.GlucoseEvent{},
+		&domain.GlucoseRollup{},
+		&domain.DailySummary{},
 	)
 	if err != nil {
 		t.Fatalf("failed to migrate: %v", err)
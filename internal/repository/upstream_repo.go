@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// UpstreamRepositoryGORM is the GORM implementation of UpstreamRepository.
+type UpstreamRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewUpstreamRepository creates a new UpstreamRepository.
+func NewUpstreamRepository(db *gorm.DB) *UpstreamRepositoryGORM {
+	return &UpstreamRepositoryGORM{db: db}
+}
+
+// Create inserts a new outage.
+func (r *UpstreamRepositoryGORM) Create(ctx context.Context, o *domain.UpstreamOutage) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Create(o).Error
+}
+
+// Update saves all fields of an existing outage.
+func (r *UpstreamRepositoryGORM) Update(ctx context.Context, o *domain.UpstreamOutage) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Save(o).Error
+}
+
+// FindOngoing returns the outage that has not ended yet.
+// Returns persistence.ErrNotFound if there is none.
+func (r *UpstreamRepositoryGORM) FindOngoing(ctx context.Context) (*domain.UpstreamOutage, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var outage domain.UpstreamOutage
+	result := db.Where("ended_at IS NULL").Order("started_at DESC").First(&outage)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &outage, nil
+}
+
+// FindSince returns the outages ongoing or ended at or after since, newest first.
+func (r *UpstreamRepositoryGORM) FindSince(ctx context.Context, since time.Time) ([]*domain.UpstreamOutage, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var outages []*domain.UpstreamOutage
+	result := db.Where("ended_at IS NULL OR ended_at >= ?", since).
+		Order("started_at DESC, id DESC").
+		Find(&outages)
+
+	return outages, result.Error
+}
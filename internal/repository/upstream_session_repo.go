@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// UpstreamSessionRepositoryGORM is the GORM implementation of UpstreamSessionRepository.
+type UpstreamSessionRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewUpstreamSessionRepository creates a new UpstreamSessionRepository.
+func NewUpstreamSessionRepository(db *gorm.DB) *UpstreamSessionRepositoryGORM {
+	return &UpstreamSessionRepositoryGORM{db: db}
+}
+
+// Save creates or replaces the session of s.Account.
+func (r *UpstreamSessionRepositoryGORM) Save(ctx context.Context, s *domain.UpstreamSession) error {
+	db := txOrDefault(ctx, r.db)
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "expires_at", "salt", "sealed"}),
+	}).Create(s).Error
+}
+
+// Find returns the session of an account.
+// Returns persistence.ErrNotFound if there is none.
+func (r *UpstreamSessionRepositoryGORM) Find(ctx context.Context, account string) (*domain.UpstreamSession, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var session domain.UpstreamSession
+	result := db.Where("account = ?", account).First(&session)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &session, nil
+}
+
+// Delete removes the session of an account, if any.
+func (r *UpstreamSessionRepositoryGORM) Delete(ctx context.Context, account string) error {
+	db := txOrDefault(ctx, r.db)
+	return db.Where("account = ?", account).Delete(&domain.UpstreamSession{}).Error
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// ViewRepositoryGORM is the GORM implementation of ViewRepository.
+type ViewRepositoryGORM struct {
+	db *gorm.DB
+}
+
+// NewViewRepository creates a new ViewRepository.
+func NewViewRepository(db *gorm.DB) *ViewRepositoryGORM {
+	return &ViewRepositoryGORM{db: db}
+}
+
+// Save creates a view or replaces the view with the same name.
+func (r *ViewRepositoryGORM) Save(ctx context.Context, v *domain.SavedView) error {
+	db := txOrDefault(ctx, r.db)
+
+	// ON CONFLICT (name) DO UPDATE - keep the original creation time
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "description", "query", "period", "aggregation"}),
+	}).Create(v).Error
+}
+
+// FindAll returns all views, sorted by name.
+func (r *ViewRepositoryGORM) FindAll(ctx context.Context) ([]*domain.SavedView, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var views []*domain.SavedView
+	result := db.Order("name ASC").Find(&views)
+
+	return views, result.Error
+}
+
+// FindByName returns the view with the given name.
+// Returns persistence.ErrNotFound if no such view exists.
+func (r *ViewRepositoryGORM) FindByName(ctx context.Context, name string) (*domain.SavedView, error) {
+	db := txOrDefault(ctx, r.db)
+
+	var view domain.SavedView
+	result := db.Where("name = ?", name).First(&view)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, persistence.ErrNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &view, nil
+}
+
+// Delete removes the view with the given name.
+// Returns persistence.ErrNotFound if no such view exists.
+func (r *ViewRepositoryGORM) Delete(ctx context.Context, name string) error {
+	db := txOrDefault(ctx, r.db)
+
+	result := db.Where("name = ?", name).Delete(&domain.SavedView{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return persistence.ErrNotFound
+	}
+
+	return nil
+}
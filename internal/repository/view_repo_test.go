@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+func TestViewRepository_SaveReplacesByName(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewViewRepository(db)
+	ctx := context.Background()
+
+	if err := repo.Save(ctx, &domain.SavedView{Name: "nights", Query: "hour in 0..5", Aggregation: domain.ViewAggregationStats}); err != nil {
+		t.Fatalf("failed to save view: %v", err)
+	}
+	if err := repo.Save(ctx, &domain.SavedView{Name: "highs", Query: "value_mgdl > 180", Aggregation: domain.ViewAggregationList}); err != nil {
+		t.Fatalf("failed to save view: %v", err)
+	}
+	if err := repo.Save(ctx, &domain.SavedView{Name: "nights", Query: "hour in 0..6", Period: "30d", Aggregation: domain.ViewAggregationStats}); err != nil {
+		t.Fatalf("failed to replace view: %v", err)
+	}
+
+	views, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to list views: %v", err)
+	}
+	if len(views) != 2 || views[0].Name != "highs" || views[1].Name != "nights" {
+		t.Fatalf("expected highs and nights sorted by name, got %+v", views)
+	}
+
+	view, err := repo.FindByName(ctx, "nights")
+	if err != nil {
+		t.Fatalf("failed to find view: %v", err)
+	}
+	if view.Query != "hour in 0..6" || view.Period != "30d" {
+		t.Errorf("expected replaced definition, got %+v", view)
+	}
+}
+
+func TestViewRepository_NotFound(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewViewRepository(db)
+	ctx := context.Background()
+
+	if _, err := repo.FindByName(ctx, "missing"); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := repo.Save(ctx, &domain.SavedView{Name: "highs", Aggregation: domain.ViewAggregationList}); err != nil {
+		t.Fatalf("failed to save view: %v", err)
+	}
+	if err := repo.Delete(ctx, "highs"); err != nil {
+		t.Fatalf("failed to delete view: %v", err)
+	}
+	if err := repo.Delete(ctx, "highs"); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound on second delete, got %v", err)
+	}
+}
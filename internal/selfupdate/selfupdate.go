@@ -0,0 +1,362 @@
+// Package selfupdate replaces the running glcli or glcore binary with the
+// latest GitHub release.
+//
+// Release assets follow the layout produced by `make dist`:
+//   - <binary>_<goos>_<goarch> (e.g. glcli_linux_arm64), one per platform
+//   - SHA256SUMS, in sha256sum format, covering every binary
+//   - SHA256SUMS.sig, an Ed25519 signature of SHA256SUMS (base64)
+//
+// SHA256SUMS must carry a valid signature by the built-in public key
+// (PublicKey) and the downloaded binary must match its SHA256SUMS entry.
+// Binaries built without a key refuse to update themselves.
+package selfupdate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRepository is the GitHub repository publishing releases
+	DefaultRepository = "R4yL-dev/glcmd"
+	// DefaultAPIURL is the GitHub REST API base URL
+	DefaultAPIURL = "https://api.github.com"
+
+	checksumsAsset = "SHA256SUMS"
+	signatureAsset = "SHA256SUMS.sig"
+
+	// maxBinarySize bounds the download of a release binary
+	maxBinarySize = 100 << 20
+)
+
+// PublicKey is the base64 Ed25519 key release checksums are signed with.
+// It is set at build time:
+//
+//	go build -ldflags "-X github.com/R4yL-dev/glcmd/internal/selfupdate.PublicKey=<key>"
+//
+// When empty, self-update is refused: `make dist` requires it.
+var PublicKey = ""
+
+var (
+	// ErrNoAsset is returned when the release has no binary for this platform.
+	ErrNoAsset = errors.New("no release asset for this platform")
+
+	// ErrChecksumMismatch is returned when the downloaded binary does not match SHA256SUMS.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	// ErrBadSignature is returned when SHA256SUMS is not signed by PublicKey.
+	ErrBadSignature = errors.New("invalid checksums signature")
+
+	// ErrNoPublicKey is returned when the binary was built without PublicKey.
+	ErrNoPublicKey = errors.New("no release signing key built in, reinstall from a release")
+)
+
+// Release is a published GitHub release.
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+// Version returns the release version without its "v" prefix.
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// asset returns the asset with the given name, or nil.
+func (r *Release) asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// Updater checks for and installs new releases of a binary.
+type Updater struct {
+	binary     string // glcli or glcore
+	repository string
+	apiURL     string
+	publicKey  string
+	httpClient *http.Client
+}
+
+// New creates an Updater for binary using the default repository.
+func New(binary string) *Updater {
+	return &Updater{
+		binary:     binary,
+		repository: DefaultRepository,
+		apiURL:     DefaultAPIURL,
+		publicKey:  PublicKey,
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// AssetName returns the release asset name of the binary for this platform.
+func (u *Updater) AssetName() string {
+	return fmt.Sprintf("%s_%s_%s", u.binary, runtime.GOOS, runtime.GOARCH)
+}
+
+// LatestRelease fetches the latest published release.
+func (u *Updater) LatestRelease(ctx context.Context) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", u.apiURL, u.repository)
+	resp, err := u.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+	return &release, nil
+}
+
+// Apply downloads the release binary for this platform, verifies it and
+// atomically replaces the executable at path.
+func (u *Updater) Apply(ctx context.Context, release *Release, path string) error {
+	if u.publicKey == "" {
+		return ErrNoPublicKey
+	}
+
+	binAsset := release.asset(u.AssetName())
+	sumsAsset := release.asset(checksumsAsset)
+	if binAsset == nil || sumsAsset == nil {
+		return fmt.Errorf("%w: %s in %s", ErrNoAsset, u.AssetName(), release.TagName)
+	}
+
+	checksums, err := u.download(ctx, sumsAsset.DownloadURL, 1<<20)
+	if err != nil {
+		return fmt.Errorf("failed to download checksums: %w", err)
+	}
+
+	sigAsset := release.asset(signatureAsset)
+	if sigAsset == nil {
+		return fmt.Errorf("%w: %s missing", ErrBadSignature, signatureAsset)
+	}
+	signature, err := u.download(ctx, sigAsset.DownloadURL, 4096)
+	if err != nil {
+		return fmt.Errorf("failed to download signature: %w", err)
+	}
+	if err := verifySignature(u.publicKey, checksums, signature); err != nil {
+		return err
+	}
+
+	expected, err := lookupChecksum(checksums, binAsset.Name)
+	if err != nil {
+		return err
+	}
+
+	binary, err := u.download(ctx, binAsset.DownloadURL, maxBinarySize)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", binAsset.Name, err)
+	}
+
+	sum := sha256.Sum256(binary)
+	if hex.EncodeToString(sum[:]) != expected {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, binAsset.Name)
+	}
+
+	return replaceFile(path, binary)
+}
+
+// Run checks for a release newer than current and installs it over the
+// running executable, reporting progress to out. With force, the latest
+// release is installed even if it is not newer.
+// Returns true if the executable was replaced.
+func (u *Updater) Run(ctx context.Context, current string, force bool, out io.Writer) (bool, error) {
+	release, err := u.LatestRelease(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for updates: %w", err)
+	}
+
+	if !force && !IsNewer(release.TagName, current) {
+		fmt.Fprintf(out, "%s %s is up to date\n", u.binary, current)
+		return false, nil
+	}
+
+	path, err := Executable()
+	if err != nil {
+		return false, fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	fmt.Fprintf(out, "Updating %s %s -> %s (%s)\n", u.binary, current, release.Version(), path)
+	if err := u.Apply(ctx, release, path); err != nil {
+		return false, err
+	}
+
+	fmt.Fprintf(out, "%s updated to %s\n", u.binary, release.Version())
+	return true, nil
+}
+
+// get performs a GET request and checks the status code.
+func (u *Updater) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: unexpected status %d", url, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// download fetches url, refusing bodies larger than limit bytes.
+func (u *Updater) download(ctx context.Context, url string, limit int64) ([]byte, error) {
+	resp, err := u.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s exceeds %d bytes", url, limit)
+	}
+	return data, nil
+}
+
+// verifySignature checks the base64 Ed25519 signature of checksums.
+func verifySignature(publicKey string, checksums, signature []byte) error {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid built-in public key")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || !ed25519.Verify(key, checksums, sig) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// lookupChecksum returns the hex SHA-256 of name from a sha256sum-format file.
+func lookupChecksum(checksums []byte, name string) (string, error) {
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s not listed in %s", ErrChecksumMismatch, name, checksumsAsset)
+}
+
+// replaceFile atomically replaces path with data, keeping it executable.
+// The new file is written next to path so the final rename stays on one filesystem.
+func replaceFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".new-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Chmod(0755); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// Executable returns the path of the running binary, with symlinks resolved
+// so that the target file is replaced rather than the link.
+func Executable() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(path)
+}
+
+// IsNewer reports whether version latest is newer than current.
+// Versions are dotted numbers with an optional "v" prefix; pre-release
+// suffixes are ignored. A "dev" or unparsable current version is never
+// considered up to date.
+func IsNewer(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return true
+	}
+
+	for i := 0; i < len(l) || i < len(c); i++ {
+		var lv, cv int
+		if i < len(l) {
+			lv = l[i]
+		}
+		if i < len(c) {
+			cv = c[i]
+		}
+		if lv != cv {
+			return lv > cv
+		}
+	}
+	return false
+}
+
+// parseVersion parses "v1.2.3" (or "1.2.3-rc1") into its numeric parts.
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
@@ -0,0 +1,214 @@
+package selfupdate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v0.8.0", "0.7.1", true},
+		{"v0.7.1", "v0.7.1", false},
+		{"v0.7.0", "0.7.1", false},
+		{"v1.0", "0.9.9", true},
+		{"v0.7.2-rc1", "0.7.1", true},
+		{"v0.8.0", "dev", true},
+		{"nightly", "0.7.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsNewer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+// releaseServer serves a latest release with the given binary, checksums and signature.
+func releaseServer(t *testing.T, u *Updater, binary, checksums, signature []byte) *Release {
+	t.Helper()
+
+	files := map[string][]byte{
+		u.AssetName():  binary,
+		checksumsAsset: checksums,
+	}
+	if signature != nil {
+		files[signatureAsset] = signature
+	}
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	release := &Release{TagName: "v9.9.9"}
+	for name, data := range files {
+		mux.HandleFunc("/download/"+name, func(w http.ResponseWriter, r *http.Request) {
+			w.Write(data)
+		})
+		release.Assets = append(release.Assets, Asset{Name: name, DownloadURL: srv.URL + "/download/" + name})
+	}
+
+	u.httpClient = srv.Client()
+	return release
+}
+
+func checksumsFor(name string, data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return []byte(fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name))
+}
+
+// signedRelease serves a latest release with the given binary and checksums,
+// signed by a key built into u.
+func signedRelease(t *testing.T, u *Updater, binary, checksums []byte) *Release {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.publicKey = base64.StdEncoding.EncodeToString(publicKey)
+	signature := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, checksums)))
+	return releaseServer(t, u, binary, checksums, signature)
+}
+
+func TestApply_ReplacesBinary(t *testing.T) {
+	u := New("glcli")
+	binary := []byte("new binary")
+	release := signedRelease(t, u, binary, checksumsFor(u.AssetName(), binary))
+
+	path := filepath.Join(t.TempDir(), "glcli")
+	if err := os.WriteFile(path, []byte("old binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := u.Apply(context.Background(), release, path); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "new binary" {
+		t.Errorf("expected binary replaced, got %q", data)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("expected executable file, got mode %s", info.Mode())
+	}
+}
+
+func TestApply_ChecksumMismatch(t *testing.T) {
+	u := New("glcli")
+	release := signedRelease(t, u, []byte("tampered"), checksumsFor(u.AssetName(), []byte("original")))
+
+	path := filepath.Join(t.TempDir(), "glcli")
+	if err := os.WriteFile(path, []byte("old binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := u.Apply(context.Background(), release, path)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "old binary" {
+		t.Errorf("expected binary left untouched, got %q", data)
+	}
+}
+
+func TestApply_Signature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binary := []byte("new binary")
+
+	t.Run("valid", func(t *testing.T) {
+		u := New("glcore")
+		u.publicKey = base64.StdEncoding.EncodeToString(publicKey)
+		checksums := checksumsFor(u.AssetName(), binary)
+		signature := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, checksums)))
+		release := releaseServer(t, u, binary, checksums, signature)
+
+		path := filepath.Join(t.TempDir(), "glcore")
+		if err := u.Apply(context.Background(), release, path); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		u := New("glcore")
+		u.publicKey = base64.StdEncoding.EncodeToString(publicKey)
+		release := releaseServer(t, u, binary, checksumsFor(u.AssetName(), binary), nil)
+
+		err := u.Apply(context.Background(), release, filepath.Join(t.TempDir(), "glcore"))
+		if !errors.Is(err, ErrBadSignature) {
+			t.Fatalf("expected ErrBadSignature, got %v", err)
+		}
+	})
+
+	t.Run("other checksums", func(t *testing.T) {
+		u := New("glcore")
+		u.publicKey = base64.StdEncoding.EncodeToString(publicKey)
+		checksums := checksumsFor(u.AssetName(), binary)
+		signature := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte("something else"))))
+		release := releaseServer(t, u, binary, checksums, signature)
+
+		err := u.Apply(context.Background(), release, filepath.Join(t.TempDir(), "glcore"))
+		if !errors.Is(err, ErrBadSignature) {
+			t.Fatalf("expected ErrBadSignature, got %v", err)
+		}
+	})
+}
+
+func TestApply_NoPublicKey(t *testing.T) {
+	u := New("glcli")
+	binary := []byte("new binary")
+	release := signedRelease(t, u, binary, checksumsFor(u.AssetName(), binary))
+	u.publicKey = ""
+
+	path := filepath.Join(t.TempDir(), "glcli")
+	if err := os.WriteFile(path, []byte("old binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := u.Apply(context.Background(), release, path)
+	if !errors.Is(err, ErrNoPublicKey) {
+		t.Fatalf("expected ErrNoPublicKey, got %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "old binary" {
+		t.Errorf("expected binary left untouched, got %q", data)
+	}
+}
+
+func TestApply_NoAsset(t *testing.T) {
+	u := New("glcli")
+	u.publicKey = base64.StdEncoding.EncodeToString(make([]byte, ed25519.PublicKeySize))
+	release := &Release{TagName: "v9.9.9"}
+
+	err := u.Apply(context.Background(), release, filepath.Join(t.TempDir(), "glcli"))
+	if !errors.Is(err, ErrNoAsset) {
+		t.Fatalf("expected ErrNoAsset, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// AlertWeek counts the alerts fired during one week (Monday to Sunday, local time).
+type AlertWeek struct {
+	Start        time.Time `json:"start"`
+	Total        int       `json:"total"`
+	Low          int       `json:"low"`
+	Falling      int       `json:"falling"`
+	Acknowledged int       `json:"acknowledged"`
+}
+
+// AlertServiceImpl implements AlertService.
+type AlertServiceImpl struct {
+	repo   repository.AlertRepository
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewAlertService creates a new AlertService.
+func NewAlertService(repo repository.AlertRepository, logger *slog.Logger) *AlertServiceImpl {
+	return &AlertServiceImpl{
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// RecordAlert stores a fired alert.
+func (s *AlertServiceImpl) RecordAlert(ctx context.Context, alert *domain.Alert) error {
+	return s.repo.Create(ctx, alert)
+}
+
+// GetAlertsWithFilters returns filtered and paginated alerts with total count.
+func (s *AlertServiceImpl) GetAlertsWithFilters(ctx context.Context, filters repository.AlertFilters, limit, offset int) ([]*domain.Alert, int64, error) {
+	alerts, err := s.repo.FindWithFilters(ctx, filters, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.repo.CountWithFilters(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return alerts, total, nil
+}
+
+// AcknowledgeAlert marks an alert as acknowledged.
+// Returns persistence.ErrNotFound if the alert does not exist or is already acknowledged.
+func (s *AlertServiceImpl) AcknowledgeAlert(ctx context.Context, id uint) error {
+	if err := s.repo.Acknowledge(ctx, id, s.now().UTC()); err != nil {
+		return err
+	}
+
+	s.logger.Info("alert acknowledged", "id", id)
+	return nil
+}
+
+// GetWeeklyCounts returns alert counts for the last weeks weeks, including
+// the current one, oldest first. Weeks without alerts are included.
+func (s *AlertServiceImpl) GetWeeklyCounts(ctx context.Context, weeks int) ([]*AlertWeek, error) {
+	now := s.now()
+	first := startOfWeek(now).AddDate(0, 0, -7*(weeks-1))
+
+	alerts, err := s.repo.FindByTimeRange(ctx, first.UTC(), now.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]*AlertWeek, weeks)
+	for i := range counts {
+		counts[i] = &AlertWeek{Start: first.AddDate(0, 0, 7*i)}
+	}
+
+	for _, alert := range alerts {
+		// Weeks start on local midnight (AddDate keeps it across DST changes)
+		i := len(counts) - 1
+		for i > 0 && alert.FiredAt.Before(counts[i].Start) {
+			i--
+		}
+
+		week := counts[i]
+		week.Total++
+		switch alert.Type {
+		case domain.AlertTypeLow:
+			week.Low++
+		case domain.AlertTypeFalling:
+			week.Falling++
+		}
+		if alert.IsAcknowledged() {
+			week.Acknowledged++
+		}
+	}
+
+	return counts, nil
+}
+
+// startOfWeek returns local midnight of the Monday starting the week of t.
+func startOfWeek(t time.Time) time.Time {
+	t = t.Local()
+	offset := (int(t.Weekday()) + 6) % 7 // Days since Monday
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.Local)
+}
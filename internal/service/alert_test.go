@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// MockAlertRepository is an in-memory AlertRepository for testing
+type MockAlertRepository struct {
+	alerts []*domain.Alert
+}
+
+func (m *MockAlertRepository) Create(ctx context.Context, a *domain.Alert) error {
+	a.ID = uint(len(m.alerts) + 1)
+	m.alerts = append(m.alerts, a)
+	return nil
+}
+
+func (m *MockAlertRepository) FindWithFilters(ctx context.Context, filters repository.AlertFilters, limit, offset int) ([]*domain.Alert, error) {
+	return m.alerts, nil
+}
+
+func (m *MockAlertRepository) CountWithFilters(ctx context.Context, filters repository.AlertFilters) (int64, error) {
+	return int64(len(m.alerts)), nil
+}
+
+func (m *MockAlertRepository) FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.Alert, error) {
+	var result []*domain.Alert
+	for _, a := range m.alerts {
+		if !a.FiredAt.Before(start) && !a.FiredAt.After(end) {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockAlertRepository) Acknowledge(ctx context.Context, id uint, at time.Time) error {
+	for _, a := range m.alerts {
+		if a.ID == id && a.AcknowledgedAt == nil {
+			a.AcknowledgedAt = &at
+			return nil
+		}
+	}
+	return persistence.ErrNotFound
+}
+
+func TestAlertService_GetWeeklyCounts(t *testing.T) {
+	repo := &MockAlertRepository{}
+	svc := NewAlertService(repo, slog.Default())
+	// Wednesday
+	svc.now = func() time.Time { return time.Date(2026, 3, 11, 12, 0, 0, 0, time.Local) }
+	ctx := context.Background()
+
+	for _, a := range []*domain.Alert{
+		{FiredAt: time.Date(2026, 2, 20, 8, 0, 0, 0, time.Local), Type: domain.AlertTypeLow},     // Before the range
+		{FiredAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local), Type: domain.AlertTypeLow},      // Monday, first week
+		{FiredAt: time.Date(2026, 3, 8, 23, 0, 0, 0, time.Local), Type: domain.AlertTypeFalling}, // Sunday, first week
+		{FiredAt: time.Date(2026, 3, 10, 3, 0, 0, 0, time.Local), Type: domain.AlertTypeLow},     // Current week
+	} {
+		if err := svc.RecordAlert(ctx, a); err != nil {
+			t.Fatalf("RecordAlert: %v", err)
+		}
+	}
+	if err := svc.AcknowledgeAlert(ctx, 4); err != nil {
+		t.Fatalf("AcknowledgeAlert: %v", err)
+	}
+
+	weeks, err := svc.GetWeeklyCounts(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetWeeklyCounts: %v", err)
+	}
+	if len(weeks) != 2 {
+		t.Fatalf("expected 2 weeks, got %d", len(weeks))
+	}
+
+	if want := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local); !weeks[0].Start.Equal(want) {
+		t.Errorf("expected first week to start %v, got %v", want, weeks[0].Start)
+	}
+	if weeks[0].Total != 2 || weeks[0].Low != 1 || weeks[0].Falling != 1 {
+		t.Errorf("unexpected first week counts: %+v", weeks[0])
+	}
+	if weeks[1].Total != 1 || weeks[1].Acknowledged != 1 {
+		t.Errorf("unexpected current week counts: %+v", weeks[1])
+	}
+}
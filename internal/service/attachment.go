@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+var (
+	// ErrAttachmentTooLarge is returned for attachments above domain.MaxAttachmentSize.
+	ErrAttachmentTooLarge = fmt.Errorf("attachment exceeds %d MiB", domain.MaxAttachmentSize>>20)
+	// ErrAttachmentType is returned for empty attachments and content not in domain.AttachmentContentTypes.
+	ErrAttachmentType = fmt.Errorf("attachment must be one of %v", domain.AttachmentContentTypes)
+)
+
+// AttachmentServiceImpl implements AttachmentService.
+// Files are stored in dir under a random name, readable only by the owner.
+type AttachmentServiceImpl struct {
+	repo       repository.AttachmentRepository
+	sensorRepo repository.SensorRepository
+	dir        string
+	logger     *slog.Logger
+}
+
+// NewAttachmentService creates a new AttachmentService storing files in dir.
+// The directory is created on the first upload.
+func NewAttachmentService(
+	repo repository.AttachmentRepository,
+	sensorRepo repository.SensorRepository,
+	dir string,
+	logger *slog.Logger,
+) *AttachmentServiceImpl {
+	return &AttachmentServiceImpl{
+		repo:       repo,
+		sensorRepo: sensorRepo,
+		dir:        dir,
+		logger:     logger,
+	}
+}
+
+// AddAttachment stores a file and links it to a sensor.
+// The content type is detected from the data, not trusted from the client.
+func (s *AttachmentServiceImpl) AddAttachment(ctx context.Context, serial, fileName, caption string, data []byte) (*domain.SensorAttachment, error) {
+	if len(data) > domain.MaxAttachmentSize {
+		return nil, ErrAttachmentTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if len(data) == 0 || !slices.Contains(domain.AttachmentContentTypes, contentType) {
+		return nil, ErrAttachmentType
+	}
+
+	if _, err := s.sensorRepo.FindBySerialNumber(ctx, serial); err != nil {
+		return nil, err
+	}
+
+	key, err := newStorageKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.writeFile(key, data); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	attachment := &domain.SensorAttachment{
+		SerialNumber: serial,
+		FileName:     filepath.Base(fileName),
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Caption:      caption,
+		StorageKey:   key,
+	}
+	if err := s.repo.Create(ctx, attachment); err != nil {
+		os.Remove(s.path(key))
+		return nil, err
+	}
+
+	s.logger.Info("sensor attachment stored",
+		"id", attachment.ID,
+		"serialNumber", serial,
+		"contentType", contentType,
+		"size", attachment.Size,
+	)
+
+	return attachment, nil
+}
+
+// ListAttachments returns the attachments of a sensor, oldest first.
+// Returns persistence.ErrNotFound if the sensor does not exist.
+func (s *AttachmentServiceImpl) ListAttachments(ctx context.Context, serial string) ([]*domain.SensorAttachment, error) {
+	if _, err := s.sensorRepo.FindBySerialNumber(ctx, serial); err != nil {
+		return nil, err
+	}
+	return s.repo.FindBySerialNumber(ctx, serial)
+}
+
+// OpenAttachment returns an attachment and its file, which the caller must close.
+func (s *AttachmentServiceImpl) OpenAttachment(ctx context.Context, id uint) (*domain.SensorAttachment, *os.File, error) {
+	attachment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(s.path(attachment.StorageKey))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment %d: %w", id, err)
+	}
+
+	return attachment, file, nil
+}
+
+// DeleteAttachment removes an attachment and its file.
+func (s *AttachmentServiceImpl) DeleteAttachment(ctx context.Context, id uint) error {
+	attachment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// A file left behind is removed by the next PruneFiles
+	if err := os.Remove(s.path(attachment.StorageKey)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.logger.Warn("failed to remove attachment file", "id", id, "error", err)
+	}
+
+	s.logger.Info("sensor attachment deleted", "id", id, "serialNumber", attachment.SerialNumber)
+	return nil
+}
+
+// PruneFiles removes the files of the attachment directory that no attachment
+// refers to, such as those left by a personal data erasure or an interrupted
+// upload. Returns the number of files removed.
+func (s *AttachmentServiceImpl) PruneFiles(ctx context.Context) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	keys, err := s.repo.FindStorageKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || slices.Contains(keys, entry.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		s.logger.Info("orphaned attachment files removed", "count", removed)
+	}
+	return removed, nil
+}
+
+// writeFile writes data to the file of key, through a temporary file so that
+// an interrupted write never leaves a truncated attachment.
+func (s *AttachmentServiceImpl) writeFile(key string, data []byte) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	tmp := s.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, s.path(key)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// path returns the path of the file of key.
+func (s *AttachmentServiceImpl) path(key string) string {
+	return filepath.Join(s.dir, key)
+}
+
+// newStorageKey returns a random file name, so that stored files reveal
+// nothing about the sensor or the uploaded file.
+func newStorageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// fakeAttachmentRepository is an in-memory AttachmentRepository.
+type fakeAttachmentRepository struct {
+	attachments []*domain.SensorAttachment
+	nextID      uint
+}
+
+func (f *fakeAttachmentRepository) Create(ctx context.Context, a *domain.SensorAttachment) error {
+	f.nextID++
+	a.ID = f.nextID
+	f.attachments = append(f.attachments, a)
+	return nil
+}
+
+func (f *fakeAttachmentRepository) FindByID(ctx context.Context, id uint) (*domain.SensorAttachment, error) {
+	for _, a := range f.attachments {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return nil, persistence.ErrNotFound
+}
+
+func (f *fakeAttachmentRepository) FindBySerialNumber(ctx context.Context, serial string) ([]*domain.SensorAttachment, error) {
+	var result []*domain.SensorAttachment
+	for _, a := range f.attachments {
+		if a.SerialNumber == serial {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeAttachmentRepository) FindStorageKeys(ctx context.Context) ([]string, error) {
+	var keys []string
+	for _, a := range f.attachments {
+		keys = append(keys, a.StorageKey)
+	}
+	return keys, nil
+}
+
+func (f *fakeAttachmentRepository) Delete(ctx context.Context, id uint) error {
+	for i, a := range f.attachments {
+		if a.ID == id {
+			f.attachments = append(f.attachments[:i], f.attachments[i+1:]...)
+			return nil
+		}
+	}
+	return persistence.ErrNotFound
+}
+
+// pngData is the start of a PNG file, enough for content type detection.
+var pngData = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newTestAttachmentService(t *testing.T) (*AttachmentServiceImpl, *fakeAttachmentRepository, string) {
+	t.Helper()
+	sensorRepo := &MockSensorRepository{
+		FindBySerialNumberFunc: func(ctx context.Context, serial string) (*domain.SensorConfig, error) {
+			if serial != "S1" {
+				return nil, persistence.ErrNotFound
+			}
+			return &domain.SensorConfig{SerialNumber: serial}, nil
+		},
+	}
+	repo := &fakeAttachmentRepository{}
+	dir := filepath.Join(t.TempDir(), "attachments")
+	return NewAttachmentService(repo, sensorRepo, dir, slog.Default()), repo, dir
+}
+
+func TestAttachmentService_AddOpenDelete(t *testing.T) {
+	service, _, dir := newTestAttachmentService(t)
+	ctx := context.Background()
+
+	attachment, err := service.AddAttachment(ctx, "S1", "../../etc/site.png", "Day 1", pngData)
+	if err != nil {
+		t.Fatalf("AddAttachment failed: %v", err)
+	}
+	if attachment.ContentType != "image/png" || attachment.FileName != "site.png" || attachment.Size != int64(len(pngData)) {
+		t.Errorf("unexpected attachment: %+v", attachment)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, attachment.StorageKey))
+	if err != nil {
+		t.Fatalf("attachment file not stored: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	_, file, err := service.OpenAttachment(ctx, attachment.ID)
+	if err != nil {
+		t.Fatalf("OpenAttachment failed: %v", err)
+	}
+	data, _ := io.ReadAll(file)
+	file.Close()
+	if !bytes.Equal(data, pngData) {
+		t.Error("expected the stored content")
+	}
+
+	list, err := service.ListAttachments(ctx, "S1")
+	if err != nil || len(list) != 1 {
+		t.Fatalf("expected 1 attachment, got %d (%v)", len(list), err)
+	}
+
+	if err := service.DeleteAttachment(ctx, attachment.ID); err != nil {
+		t.Fatalf("DeleteAttachment failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, attachment.StorageKey)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the file removed, got %v", err)
+	}
+	if err := service.DeleteAttachment(ctx, attachment.ID); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAttachmentService_AddAttachment_Invalid(t *testing.T) {
+	service, repo, _ := newTestAttachmentService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		serial  string
+		data    []byte
+		wantErr error
+	}{
+		{"unknown sensor", "UNKNOWN", pngData, persistence.ErrNotFound},
+		{"empty", "S1", nil, ErrAttachmentType},
+		{"not an image", "S1", []byte("<html><script>alert(1)</script></html>"), ErrAttachmentType},
+		{"too large", "S1", append(pngData, make([]byte, domain.MaxAttachmentSize)...), ErrAttachmentTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.AddAttachment(ctx, tt.serial, "file", "", tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if len(repo.attachments) != 0 {
+		t.Errorf("expected no attachment stored, got %d", len(repo.attachments))
+	}
+}
+
+func TestAttachmentService_PruneFiles(t *testing.T) {
+	service, repo, dir := newTestAttachmentService(t)
+	ctx := context.Background()
+
+	// No directory yet: nothing to prune
+	if removed, err := service.PruneFiles(ctx); err != nil || removed != 0 {
+		t.Fatalf("expected nothing pruned, got %d (%v)", removed, err)
+	}
+
+	kept, err := service.AddAttachment(ctx, "S1", "kept.png", "", pngData)
+	if err != nil {
+		t.Fatalf("AddAttachment failed: %v", err)
+	}
+	erased, err := service.AddAttachment(ctx, "S1", "erased.png", "", pngData)
+	if err != nil {
+		t.Fatalf("AddAttachment failed: %v", err)
+	}
+	// Erasure deletes the rows only
+	repo.attachments = repo.attachments[:1]
+	os.WriteFile(filepath.Join(dir, "interrupted.tmp"), pngData, 0600)
+
+	removed, err := service.PruneFiles(ctx)
+	if err != nil || removed != 2 {
+		t.Fatalf("expected 2 files pruned, got %d (%v)", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, kept.StorageKey)); err != nil {
+		t.Errorf("expected the referenced file kept, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, erased.StorageKey)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the orphaned file removed, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// coalesce runs fn once for the concurrent calls made with the same key, so a
+// burst of identical reads (several widgets refreshing at once) hits the
+// database once. The key is scoped to the patient of ctx. Each caller gets its
+// own copy of the result struct; the slices and pointers it holds are shared
+// and must not be modified.
+//
+// The query runs with the context of the first caller. A caller whose own
+// context is still alive when that one was canceled runs the query itself.
+func coalesce[T any](ctx context.Context, group *singleflight.Group, key string, fn func(context.Context) (*T, error)) (*T, error) {
+	key = repository.PatientFromContext(ctx) + "\x00" + key
+
+	v, err, shared := group.Do(key, func() (any, error) {
+		return fn(ctx)
+	})
+	if err != nil {
+		if shared && ctx.Err() == nil &&
+			(errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			return fn(ctx)
+		}
+		return nil, err
+	}
+
+	result := v.(*T)
+	if result == nil || !shared {
+		return result, nil
+	}
+	cp := *result
+	return &cp, nil
+}
+
+// coalesceKey builds a coalescing key from the name of a read and its parameters.
+// Pointers are dereferenced, so equal parameters give equal keys.
+func coalesceKey(name string, params ...any) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, p := range params {
+		b.WriteByte('|')
+		switch v := p.(type) {
+		case *time.Time:
+			if v != nil {
+				b.WriteString(v.UTC().Format(time.RFC3339Nano))
+			}
+		case *int:
+			if v != nil {
+				fmt.Fprintf(&b, "%d", *v)
+			}
+		case fmt.Stringer:
+			b.WriteString(v.String())
+		default:
+			fmt.Fprintf(&b, "%v", v)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// blockingLatestRepo returns a repository whose FindLatest blocks until release
+// is closed (or its context is done), counting the queries and signaling entered
+// on the first one.
+func blockingLatestRepo(calls *atomic.Int32, entered chan<- struct{}, release <-chan struct{}) *MockGlucoseRepository {
+	var once sync.Once
+	return &MockGlucoseRepository{
+		FindLatestFunc: func(ctx context.Context) (*domain.GlucoseMeasurement, error) {
+			calls.Add(1)
+			once.Do(func() { close(entered) })
+			select {
+			case <-release:
+				return &domain.GlucoseMeasurement{ID: 1, ValueInMgPerDl: 120}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	}
+}
+
+func TestGlucoseService_GetLatestMeasurement_Coalesced(t *testing.T) {
+	var calls atomic.Int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	svc := NewGlucoseService(blockingLatestRepo(&calls, entered, release), nil, slog.Default(), nil)
+
+	const callers = 10
+	results := make([]*domain.GlucoseMeasurement, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m, err := svc.GetLatestMeasurement(context.Background())
+			if err != nil {
+				t.Errorf("caller %d: unexpected error: %v", i, err)
+				return
+			}
+			results[i] = m
+		}()
+		if i == 0 {
+			<-entered
+		}
+	}
+
+	// Let the other callers join the query in flight
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 query, got %d", got)
+	}
+	for i, m := range results {
+		if m == nil || m.ValueInMgPerDl != 120 {
+			t.Fatalf("caller %d: unexpected measurement %+v", i, m)
+		}
+		for j := range i {
+			if results[j] == m {
+				t.Errorf("callers %d and %d share the same measurement", j, i)
+			}
+		}
+	}
+}
+
+func TestGlucoseService_GetLatestMeasurement_NotCoalescedAcrossPatients(t *testing.T) {
+	var calls atomic.Int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	svc := NewGlucoseService(blockingLatestRepo(&calls, entered, release), nil, slog.Default(), nil)
+
+	done := make(chan error)
+	go func() {
+		_, err := svc.GetLatestMeasurement(repository.WithPatient(context.Background(), "alice"))
+		done <- err
+	}()
+	<-entered
+
+	go func() {
+		_, err := svc.GetLatestMeasurement(repository.WithPatient(context.Background(), "bob"))
+		done <- err
+	}()
+
+	// The second patient must run its own query
+	deadline := time.Now().Add(2 * time.Second)
+	for calls.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(release)
+	for range 2 {
+		if err := <-done; err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 queries, got %d", got)
+	}
+}
+
+func TestGlucoseService_GetLatestMeasurement_CanceledLeader(t *testing.T) {
+	var calls atomic.Int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	svc := NewGlucoseService(blockingLatestRepo(&calls, entered, release), nil, slog.Default(), nil)
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		_, err := svc.GetLatestMeasurement(leaderCtx)
+		leaderErr <- err
+	}()
+	<-entered
+
+	follower := make(chan error)
+	go func() {
+		m, err := svc.GetLatestMeasurement(context.Background())
+		if err == nil && m.ValueInMgPerDl != 120 {
+			err = errors.New("unexpected measurement")
+		}
+		follower <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the leader to be canceled, got %v", err)
+	}
+
+	// The follower retries with its own context
+	close(release)
+	if err := <-follower; err != nil {
+		t.Errorf("expected the follower to succeed, got %v", err)
+	}
+}
+
+func TestCoalesceKey(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sameStart := start.In(time.FixedZone("CET", 3600))
+	other := start.Add(time.Hour)
+	low, high := 70, 180
+	sameLow := 70
+
+	base := coalesceKey("stats", &start, (*time.Time)(nil), &low, &high, "")
+	if got := coalesceKey("stats", &sameStart, (*time.Time)(nil), &sameLow, &high, ""); got != base {
+		t.Errorf("expected equal parameters to give the same key, got %q and %q", base, got)
+	}
+
+	for name, key := range map[string]string{
+		"start":   coalesceKey("stats", &other, (*time.Time)(nil), &low, &high, ""),
+		"end":     coalesceKey("stats", &start, &start, &low, &high, ""),
+		"targets": coalesceKey("stats", &start, (*time.Time)(nil), (*int)(nil), (*int)(nil), ""),
+		"query":   coalesceKey("stats", &start, (*time.Time)(nil), &low, &high, "value > 180"),
+		"name":    coalesceKey("latest", &start, (*time.Time)(nil), &low, &high, ""),
+	} {
+		if key == base {
+			t.Errorf("%s: expected a different key than %q", name, base)
+		}
+	}
+}
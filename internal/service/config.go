@@ -2,18 +2,24 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
 	"github.com/R4yL-dev/glcmd/internal/repository"
 )
 
 // ConfigServiceImpl implements ConfigService.
 type ConfigServiceImpl struct {
-	userRepo    repository.UserRepository
-	deviceRepo  repository.DeviceRepository
-	targetsRepo repository.TargetsRepository
-	logger      *slog.Logger
+	userRepo      repository.UserRepository
+	deviceRepo    repository.DeviceRepository
+	targetsRepo   repository.TargetsRepository
+	dashboardRepo repository.DashboardRepository
+	prefsRepo     repository.PreferencesRepository
+	logger        *slog.Logger
+	eventBroker   *events.Broker
 }
 
 // NewConfigService creates a new ConfigService.
@@ -21,13 +27,19 @@ func NewConfigService(
 	userRepo repository.UserRepository,
 	deviceRepo repository.DeviceRepository,
 	targetsRepo repository.TargetsRepository,
+	dashboardRepo repository.DashboardRepository,
+	prefsRepo repository.PreferencesRepository,
 	logger *slog.Logger,
+	eventBroker *events.Broker,
 ) *ConfigServiceImpl {
 	return &ConfigServiceImpl{
-		userRepo:    userRepo,
-		deviceRepo:  deviceRepo,
-		targetsRepo: targetsRepo,
-		logger:      logger,
+		userRepo:      userRepo,
+		deviceRepo:    deviceRepo,
+		targetsRepo:   targetsRepo,
+		dashboardRepo: dashboardRepo,
+		prefsRepo:     prefsRepo,
+		logger:        logger,
+		eventBroker:   eventBroker,
 	}
 }
 
@@ -47,12 +59,28 @@ func (s *ConfigServiceImpl) GetUserPreferences(ctx context.Context) (*domain.Use
 }
 
 // SaveDeviceInfo saves device information.
+// A config change event is published when the alarm settings differ from the
+// stored ones.
 func (s *ConfigServiceImpl) SaveDeviceInfo(ctx context.Context, d *domain.DeviceInfo) error {
+	previous, err := s.deviceRepo.Find(ctx)
+	if err != nil && !errors.Is(err, persistence.ErrNotFound) {
+		return err
+	}
+
 	if err := s.deviceRepo.Save(ctx, d); err != nil {
 		return err
 	}
 
 	s.logger.Debug("device info saved", "deviceId", d.DeviceID)
+
+	if previous != nil && !previous.SameAlarmSettings(d) {
+		s.logger.Info("device alarm settings changed",
+			"lowLimit", d.LowLimit,
+			"highLimit", d.HighLimit,
+			"alarmsEnabled", d.AlarmsEnabled,
+		)
+		s.publishConfigChange(&domain.ConfigChange{Device: d})
+	}
 	return nil
 }
 
@@ -62,7 +90,13 @@ func (s *ConfigServiceImpl) GetDeviceInfo(ctx context.Context) (*domain.DeviceIn
 }
 
 // SaveGlucoseTargets saves glucose targets.
+// A config change event is published when they differ from the stored ones.
 func (s *ConfigServiceImpl) SaveGlucoseTargets(ctx context.Context, t *domain.GlucoseTargets) error {
+	previous, err := s.targetsRepo.Find(ctx)
+	if err != nil && !errors.Is(err, persistence.ErrNotFound) {
+		return err
+	}
+
 	if err := s.targetsRepo.Save(ctx, t); err != nil {
 		return err
 	}
@@ -71,10 +105,79 @@ func (s *ConfigServiceImpl) SaveGlucoseTargets(ctx context.Context, t *domain.Gl
 		"targetHigh", t.TargetHigh,
 		"targetLow", t.TargetLow,
 	)
+
+	if previous != nil && (previous.TargetHigh != t.TargetHigh ||
+		previous.TargetLow != t.TargetLow ||
+		previous.UnitOfMeasure != t.UnitOfMeasure) {
+		s.logger.Info("glucose targets changed", "targetHigh", t.TargetHigh, "targetLow", t.TargetLow)
+		s.publishConfigChange(&domain.ConfigChange{Targets: t})
+	}
 	return nil
 }
 
+// publishConfigChange notifies event subscribers of a config change.
+func (s *ConfigServiceImpl) publishConfigChange(change *domain.ConfigChange) {
+	if s.eventBroker == nil {
+		return
+	}
+	s.eventBroker.Publish(events.Event{
+		Type: events.EventTypeConfig,
+		Data: change,
+	})
+}
+
 // GetGlucoseTargets returns glucose targets.
 func (s *ConfigServiceImpl) GetGlucoseTargets(ctx context.Context) (*domain.GlucoseTargets, error) {
 	return s.targetsRepo.Find(ctx)
 }
+
+// SaveDashboardConfig saves the dashboard layout.
+func (s *ConfigServiceImpl) SaveDashboardConfig(ctx context.Context, c *domain.DashboardConfig) error {
+	if err := s.dashboardRepo.Save(ctx, c); err != nil {
+		return err
+	}
+
+	s.logger.Debug("dashboard config saved", "cards", len(c.Cards))
+	return nil
+}
+
+// GetDashboardConfig returns the saved dashboard layout, or the default
+// layout in the unit and chart range of the display preferences if none has
+// been saved yet.
+func (s *ConfigServiceImpl) GetDashboardConfig(ctx context.Context) (*domain.DashboardConfig, error) {
+	c, err := s.dashboardRepo.Find(ctx)
+	if !errors.Is(err, persistence.ErrNotFound) {
+		return c, err
+	}
+
+	prefs, err := s.GetDisplayPreferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c = domain.DefaultDashboardConfig()
+	c.Unit = prefs.Unit
+	c.ChartRange = prefs.ChartRange
+	return c, nil
+}
+
+// SaveDisplayPreferences saves the display preferences.
+// A config change event is published so connected clients apply them.
+func (s *ConfigServiceImpl) SaveDisplayPreferences(ctx context.Context, p *domain.DisplayPreferences) error {
+	if err := s.prefsRepo.Save(ctx, p); err != nil {
+		return err
+	}
+
+	s.logger.Info("display preferences saved", "unit", p.Unit, "timezone", p.Timezone)
+	s.publishConfigChange(&domain.ConfigChange{Preferences: p})
+	return nil
+}
+
+// GetDisplayPreferences returns the saved display preferences,
+// or the default preferences if none have been saved yet.
+func (s *ConfigServiceImpl) GetDisplayPreferences(ctx context.Context) (*domain.DisplayPreferences, error) {
+	p, err := s.prefsRepo.Find(ctx)
+	if errors.Is(err, persistence.ErrNotFound) {
+		return domain.DefaultDisplayPreferences(), nil
+	}
+	return p, err
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// memoryDeviceRepository keeps the device info in memory
+type memoryDeviceRepository struct {
+	device *domain.DeviceInfo
+}
+
+func (r *memoryDeviceRepository) Save(ctx context.Context, d *domain.DeviceInfo) error {
+	r.device = d
+	return nil
+}
+
+func (r *memoryDeviceRepository) Find(ctx context.Context) (*domain.DeviceInfo, error) {
+	if r.device == nil {
+		return nil, persistence.ErrNotFound
+	}
+	return r.device, nil
+}
+
+// memoryTargetsRepository keeps the glucose targets in memory
+type memoryTargetsRepository struct {
+	targets *domain.GlucoseTargets
+}
+
+func (r *memoryTargetsRepository) Save(ctx context.Context, t *domain.GlucoseTargets) error {
+	r.targets = t
+	return nil
+}
+
+func (r *memoryTargetsRepository) Find(ctx context.Context) (*domain.GlucoseTargets, error) {
+	if r.targets == nil {
+		return nil, persistence.ErrNotFound
+	}
+	return r.targets, nil
+}
+
+func TestConfigService_PublishesChanges(t *testing.T) {
+	broker := events.NewBroker(10, slog.Default())
+	broker.Start()
+	defer broker.Stop()
+	ch := broker.Subscribe("test", []events.EventType{events.EventTypeConfig})
+
+	svc := NewConfigService(nil, &memoryDeviceRepository{}, &memoryTargetsRepository{}, nil, nil, slog.Default(), broker)
+	ctx := context.Background()
+
+	device := &domain.DeviceInfo{DeviceID: "phone", AlarmsEnabled: true, LowLimit: 70, HighLimit: 250}
+	targets := &domain.GlucoseTargets{TargetLow: 70, TargetHigh: 180, UnitOfMeasure: 1}
+
+	// First saves and unchanged settings are not changes
+	for i := 0; i < 2; i++ {
+		if err := svc.SaveDeviceInfo(ctx, device); err != nil {
+			t.Fatalf("SaveDeviceInfo: %v", err)
+		}
+		if err := svc.SaveGlucoseTargets(ctx, targets); err != nil {
+			t.Fatalf("SaveGlucoseTargets: %v", err)
+		}
+	}
+
+	changed := *device
+	changed.LowLimit = 80
+	if err := svc.SaveDeviceInfo(ctx, &changed); err != nil {
+		t.Fatalf("SaveDeviceInfo: %v", err)
+	}
+
+	select {
+	case event := <-ch:
+		change, ok := event.Data.(*domain.ConfigChange)
+		if !ok || change.Device == nil || change.Device.LowLimit != 80 || change.Targets != nil {
+			t.Errorf("expected a device change to 80 mg/dL, got %+v", event.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a config event")
+	}
+
+	select {
+	case event := <-ch:
+		t.Errorf("expected a single config event, got %+v", event.Data)
+	default:
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// dailySummaryLookback is how far back each run summarizes again, from the
+// start of that local day: the previous day still changes with late readings
+// (backfilled history, sensor catch-up).
+const dailySummaryLookback = 24 * time.Hour
+
+// DailySummaryRun reports a daily summary run.
+type DailySummaryRun struct {
+	Since    time.Time `json:"since"`    // Start of the summarized window, zero for the whole history
+	Readings int       `json:"readings"` // Measurements summarized
+	Days     int       `json:"days"`     // Daily summaries stored
+}
+
+// DailySummaryServiceImpl implements DailySummaryService.
+type DailySummaryServiceImpl struct {
+	repo        repository.DailySummaryRepository
+	glucoseRepo repository.GlucoseRepository
+	targetsRepo repository.TargetsRepository
+	logger      *slog.Logger
+	now         func() time.Time
+	scanned     atomic.Bool // The whole history was summarized since the start
+}
+
+// NewDailySummaryService creates a new DailySummaryService.
+func NewDailySummaryService(
+	repo repository.DailySummaryRepository,
+	glucoseRepo repository.GlucoseRepository,
+	targetsRepo repository.TargetsRepository,
+	logger *slog.Logger,
+) *DailySummaryServiceImpl {
+	return &DailySummaryServiceImpl{
+		repo:        repo,
+		glucoseRepo: glucoseRepo,
+		targetsRepo: targetsRepo,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Summarize computes the summaries of the recent local days and stores them,
+// replacing those computed before. The first run summarizes the whole
+// history, so summaries follow target changes made while glcore was stopped.
+// Days without measurements are left as they are: their summary outlives the
+// raw measurements pruned by the retention job. Nothing is summarized until
+// the glucose targets are known.
+func (s *DailySummaryServiceImpl) Summarize(ctx context.Context) (*DailySummaryRun, error) {
+	targets, err := s.targetsRepo.Find(ctx)
+	if errors.Is(err, persistence.ErrNotFound) {
+		s.logger.Debug("glucose targets unknown, skipping daily summaries")
+		return &DailySummaryRun{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get glucose targets: %w", err)
+	}
+
+	run := &DailySummaryRun{}
+	filters := repository.GlucoseFilters{}
+	if s.scanned.Load() {
+		local := s.now().Add(-dailySummaryLookback).Local()
+		run.Since = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local).UTC()
+		filters.StartTime = &run.Since
+	}
+
+	summarizer := newDailySummarizer(targets.TargetLow, targets.TargetHigh)
+	err = s.glucoseRepo.StreamWithFilters(ctx, filters, func(m *domain.GlucoseMeasurement) error {
+		summarizer.add(m)
+		run.Readings++
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read measurements: %w", err)
+	}
+
+	summaries := summarizer.summaries()
+	if err := s.repo.Upsert(ctx, summaries); err != nil {
+		return nil, fmt.Errorf("failed to save daily summaries: %w", err)
+	}
+	run.Days = len(summaries)
+
+	s.scanned.Store(true)
+	s.logger.Debug("daily summaries computed", "since", run.Since, "readings", run.Readings, "days", run.Days)
+
+	return run, nil
+}
+
+// GetDailySummaries returns the summaries of the local days within [start,
+// end], oldest first.
+func (s *DailySummaryServiceImpl) GetDailySummaries(ctx context.Context, start, end time.Time) ([]*domain.DailySummary, error) {
+	summaries, err := s.repo.FindByDateRange(ctx, start.Local().Format(time.DateOnly), end.Local().Format(time.DateOnly))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily summaries: %w", err)
+	}
+	return summaries, nil
+}
+
+// dailySummarizer accumulates measurements into summaries per patient and
+// local day.
+type dailySummarizer struct {
+	lowMgDl, highMgDl int
+	days              map[dailySummaryKey]*dailyAccumulator
+	order             []*dailyAccumulator // In order of the first measurement
+}
+
+type dailySummaryKey struct {
+	patientID string
+	date      string
+}
+
+type dailyAccumulator struct {
+	summary *domain.DailySummary
+	sum     float64
+	sumMgDl int
+	inRange int
+}
+
+func newDailySummarizer(lowMgDl, highMgDl int) *dailySummarizer {
+	return &dailySummarizer{
+		lowMgDl:  lowMgDl,
+		highMgDl: highMgDl,
+		days:     make(map[dailySummaryKey]*dailyAccumulator),
+	}
+}
+
+// add counts m in the summary of its patient and local day.
+func (d *dailySummarizer) add(m *domain.GlucoseMeasurement) {
+	key := dailySummaryKey{patientID: m.PatientID, date: m.Timestamp.Local().Format(time.DateOnly)}
+	acc := d.days[key]
+	if acc == nil {
+		acc = &dailyAccumulator{summary: &domain.DailySummary{
+			PatientID:      m.PatientID,
+			Date:           key.date,
+			MinMgDl:        m.ValueInMgPerDl,
+			MaxMgDl:        m.ValueInMgPerDl,
+			TargetLowMgDl:  d.lowMgDl,
+			TargetHighMgDl: d.highMgDl,
+		}}
+		d.days[key] = acc
+		d.order = append(d.order, acc)
+	}
+
+	summary := acc.summary
+	summary.Count++
+	summary.MinMgDl = min(summary.MinMgDl, m.ValueInMgPerDl)
+	summary.MaxMgDl = max(summary.MaxMgDl, m.ValueInMgPerDl)
+	acc.sum += m.Value
+	acc.sumMgDl += m.ValueInMgPerDl
+
+	// Same bounds as the time in range of the statistics
+	switch {
+	case m.ValueInMgPerDl < d.lowMgDl:
+		summary.LowCount++
+	case m.ValueInMgPerDl > d.highMgDl:
+		summary.HighCount++
+	default:
+		acc.inRange++
+	}
+}
+
+// summaries returns the summaries of the days with measurements.
+func (d *dailySummarizer) summaries() []*domain.DailySummary {
+	summaries := make([]*domain.DailySummary, len(d.order))
+	for i, acc := range d.order {
+		summary := acc.summary
+		count := float64(summary.Count)
+
+		summary.Average = math.Round(acc.sum/count*100) / 100
+		summary.AverageMgDl = math.Round(float64(acc.sumMgDl)/count*10) / 10
+		summary.TimeInRange = math.Round(float64(acc.inRange)/count*1000) / 10
+		summary.TimeBelowRange = math.Round(float64(summary.LowCount)/count*1000) / 10
+		summary.TimeAboveRange = math.Round(float64(summary.HighCount)/count*1000) / 10
+		if gmi := domain.CalculateGMI(float64(acc.sumMgDl) / count); gmi != nil {
+			summary.GMI = math.Round(*gmi*100) / 100
+		}
+		summaries[i] = summary
+	}
+	return summaries
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// memoryDailySummaryRepository keeps the daily summaries in memory
+type memoryDailySummaryRepository struct {
+	summaries map[string]*domain.DailySummary // By patient and date
+}
+
+func (r *memoryDailySummaryRepository) Upsert(ctx context.Context, summaries []*domain.DailySummary) error {
+	if r.summaries == nil {
+		r.summaries = make(map[string]*domain.DailySummary)
+	}
+	for _, s := range summaries {
+		r.summaries[s.PatientID+"/"+s.Date] = s
+	}
+	return nil
+}
+
+func (r *memoryDailySummaryRepository) FindByDateRange(ctx context.Context, from, to string) ([]*domain.DailySummary, error) {
+	var result []*domain.DailySummary
+	for _, s := range r.summaries {
+		if s.Date >= from && s.Date <= to {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func TestDailySummarizer(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+
+	summarizer := newDailySummarizer(70, 180)
+	for _, m := range readings(day.Add(10*time.Hour), 60, 100, 120, 200) {
+		summarizer.add(m)
+	}
+	for _, m := range readings(day.Add(34*time.Hour), 110, 130) { // Next day
+		summarizer.add(m)
+	}
+	other := readings(day.Add(12*time.Hour), 150)[0]
+	other.PatientID = "other"
+	summarizer.add(other)
+
+	summaries := summarizer.summaries()
+	if len(summaries) != 3 {
+		t.Fatalf("expected 3 summaries, got %d: %+v", len(summaries), summaries)
+	}
+
+	first := summaries[0]
+	if first.Date != "2026-03-01" || first.Count != 4 || first.AverageMgDl != 120 || first.MinMgDl != 60 || first.MaxMgDl != 200 {
+		t.Errorf("unexpected first summary: %+v", first)
+	}
+	if first.TimeInRange != 50 || first.TimeBelowRange != 25 || first.TimeAboveRange != 25 || first.LowCount != 1 || first.HighCount != 1 {
+		t.Errorf("expected 50%% in range with one low and one high, got %+v", first)
+	}
+	if first.GMI != 6.18 || first.TargetLowMgDl != 70 || first.TargetHighMgDl != 180 {
+		t.Errorf("expected a GMI of 6.18 with the targets 70-180, got %+v", first)
+	}
+	if next := summaries[1]; next.Date != "2026-03-02" || next.Count != 2 || next.TimeInRange != 100 {
+		t.Errorf("unexpected summary of the next day: %+v", next)
+	}
+	if summaries[2].PatientID != "other" || summaries[2].Date != "2026-03-01" {
+		t.Errorf("expected a summary of the other patient, got %+v", summaries[2])
+	}
+}
+
+func TestDailySummaryService_Summarize(t *testing.T) {
+	now := time.Date(2026, 3, 10, 8, 0, 0, 0, time.Local)
+	measurements := append(
+		readings(now.AddDate(0, 0, -5), 100, 110),
+		readings(now.Add(-time.Hour), 200, 210)...,
+	)
+
+	var from *time.Time
+	glucoseRepo := &MockGlucoseRepository{
+		StreamWithFiltersFunc: func(ctx context.Context, filters repository.GlucoseFilters, fn func(*domain.GlucoseMeasurement) error) error {
+			from = filters.StartTime
+			for _, m := range measurements {
+				if filters.StartTime != nil && m.Timestamp.Before(*filters.StartTime) {
+					continue
+				}
+				if err := fn(m); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	repo := &memoryDailySummaryRepository{}
+	targetsRepo := &memoryTargetsRepository{}
+	service := NewDailySummaryService(repo, glucoseRepo, targetsRepo, slog.Default())
+	service.now = func() time.Time { return now }
+
+	// Nothing is summarized until the targets are known
+	run, err := service.Summarize(context.Background())
+	if err != nil || run.Days != 0 || len(repo.summaries) != 0 {
+		t.Fatalf("expected no summaries without targets, got %+v (%v)", run, err)
+	}
+
+	targetsRepo.targets = &domain.GlucoseTargets{TargetLow: 70, TargetHigh: 180}
+
+	// The first run summarizes the whole history
+	run, err = service.Summarize(context.Background())
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if from != nil || run.Readings != 4 || run.Days != 2 || len(repo.summaries) != 2 {
+		t.Fatalf("expected 2 days summarized from the whole history, got %+v", run)
+	}
+
+	// Later runs summarize again from the start of the previous day, keeping older days
+	measurements = append(measurements, readings(now.Add(-30*time.Minute), 120)...)
+	run, err = service.Summarize(context.Background())
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if from == nil || !from.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local)) || run.Readings != 3 || len(repo.summaries) != 2 {
+		t.Errorf("expected the recent days summarized again, got %+v from %v (%d stored)", run, from, len(repo.summaries))
+	}
+	if today := repo.summaries["/2026-03-10"]; today == nil || today.Count != 3 || today.TimeAboveRange != 66.7 {
+		t.Errorf("expected today summarized with the new reading, got %+v", today)
+	}
+
+	summaries, err := service.GetDailySummaries(context.Background(), now.AddDate(0, 0, -7), now)
+	if err != nil || len(summaries) != 2 {
+		t.Errorf("expected 2 summaries in the last week, got %d (%v)", len(summaries), err)
+	}
+}
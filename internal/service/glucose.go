@@ -4,8 +4,11 @@ import (
 	"context"
 	"log/slog"
 	"math"
+	"sync"
 	"time"
 
+	"golang.org/x/sync/singleflight"
+
 	"github.com/R4yL-dev/glcmd/internal/domain"
 	"github.com/R4yL-dev/glcmd/internal/events"
 	"github.com/R4yL-dev/glcmd/internal/persistence"
@@ -22,6 +25,7 @@ type MeasurementStats struct {
 	Max            float64    `json:"max"`
 	MaxMgDl        int        `json:"maxMgDl"`
 	StdDev         float64    `json:"stdDev"`
+	CV             float64    `json:"cv"` // Coefficient of variation (%)
 	LowCount       int        `json:"lowCount"`
 	NormalCount    int        `json:"normalCount"`
 	HighCount      int        `json:"highCount"`
@@ -29,33 +33,72 @@ type MeasurementStats struct {
 	TimeBelowRange float64    `json:"timeBelowRange"`
 	TimeAboveRange float64    `json:"timeAboveRange"`
 	GMI            *float64   `json:"gmi,omitempty"`
-	FirstTimestamp *time.Time `json:"-"` // Oldest measurement (not in JSON, used for period)
-	LastTimestamp  *time.Time `json:"-"` // Newest measurement (not in JSON, used for period)
+	EstimatedA1c   *float64   `json:"estimatedA1c,omitempty"`
+	TargetBands    []BandTime `json:"targetBands,omitempty"` // Time in the secondary target bands
+	FirstTimestamp *time.Time `json:"-"`                     // Oldest measurement (not in JSON, used for period)
+	LastTimestamp  *time.Time `json:"-"`                     // Newest measurement (not in JSON, used for period)
+}
+
+// BandTime is the share of readings within a secondary target band.
+// The "tight" band is the Time in Tight Range (TITR).
+type BandTime struct {
+	domain.TargetBand
+	Percent float64 `json:"percent"`
 }
 
 // GlucoseServiceImpl implements GlucoseService.
 type GlucoseServiceImpl struct {
 	repo        repository.GlucoseRepository
+	bandsMu     sync.RWMutex
+	targetBands []domain.TargetBand
 	retry       *persistence.RetryConfig
 	logger      *slog.Logger
 	eventBroker *events.Broker
+	reads       singleflight.Group // Coalesces concurrent identical reads
 }
 
 // NewGlucoseService creates a new GlucoseService.
+// targetBands are reported in statistics alongside Time in Range (nil = none).
 // eventBroker is optional and can be nil (for tests or when SSE is not needed).
 func NewGlucoseService(
 	repo repository.GlucoseRepository,
+	targetBands []domain.TargetBand,
 	logger *slog.Logger,
 	eventBroker *events.Broker,
 ) *GlucoseServiceImpl {
 	return &GlucoseServiceImpl{
 		repo:        repo,
+		targetBands: targetBands,
 		retry:       persistence.DefaultRetryConfig(),
 		logger:      logger,
 		eventBroker: eventBroker,
 	}
 }
 
+// SetTightBand replaces the tight range band reported in statistics, or adds
+// it if the configured bands have none.
+func (s *GlucoseServiceImpl) SetTightBand(band domain.TargetBand) {
+	band.Name = domain.TargetBandTight
+
+	s.bandsMu.Lock()
+	defer s.bandsMu.Unlock()
+
+	// Copy so statistics in flight keep the slice they read
+	bands := make([]domain.TargetBand, 0, len(s.targetBands)+1)
+	replaced := false
+	for _, b := range s.targetBands {
+		if b.Name == domain.TargetBandTight {
+			b = band
+			replaced = true
+		}
+		bands = append(bands, b)
+	}
+	if !replaced {
+		bands = append(bands, band)
+	}
+	s.targetBands = bands
+}
+
 // SaveMeasurement saves a glucose measurement with retry logic.
 // Returns (true, nil) if inserted, (false, nil) if duplicate was ignored.
 func (s *GlucoseServiceImpl) SaveMeasurement(ctx context.Context, m *domain.GlucoseMeasurement) (bool, error) {
@@ -84,8 +127,9 @@ func (s *GlucoseServiceImpl) SaveMeasurement(ctx context.Context, m *domain.Gluc
 	// Publish event if new measurement was inserted
 	if s.eventBroker != nil && inserted {
 		s.eventBroker.Publish(events.Event{
-			Type: events.EventTypeGlucose,
-			Data: m,
+			Type:      events.EventTypeGlucose,
+			Data:      m,
+			PatientID: m.PatientID,
 		})
 	}
 
@@ -93,8 +137,9 @@ func (s *GlucoseServiceImpl) SaveMeasurement(ctx context.Context, m *domain.Gluc
 }
 
 // GetLatestMeasurement returns the most recent measurement.
+// Concurrent calls for the same patient share one query.
 func (s *GlucoseServiceImpl) GetLatestMeasurement(ctx context.Context) (*domain.GlucoseMeasurement, error) {
-	return s.repo.FindLatest(ctx)
+	return coalesce(ctx, &s.reads, "latest", s.repo.FindLatest)
 }
 
 // GetAllMeasurements returns all measurements.
@@ -124,6 +169,11 @@ func (s *GlucoseServiceImpl) GetMeasurementsWithFilters(ctx context.Context, fil
 	return measurements, total, nil
 }
 
+// StreamMeasurements calls fn for each measurement matching filters, oldest first.
+func (s *GlucoseServiceImpl) StreamMeasurements(ctx context.Context, filters repository.GlucoseFilters, fn func(*domain.GlucoseMeasurement) error) error {
+	return s.repo.StreamWithFilters(ctx, filters, fn)
+}
+
 // GetStatistics calculates aggregated statistics for a time range.
 // If start and end are nil, returns statistics for all data (all time).
 func (s *GlucoseServiceImpl) GetStatistics(ctx context.Context, start, end *time.Time, targets *domain.GlucoseTargets) (*MeasurementStats, error) {
@@ -137,6 +187,32 @@ func (s *GlucoseServiceImpl) GetStatistics(ctx context.Context, start, end *time
 		filters.TargetHighMgDl = &targets.TargetHigh
 	}
 
+	return s.GetStatisticsWithFilters(ctx, filters)
+}
+
+// GetStatisticsWithFilters calculates aggregated statistics of the measurements
+// matching filters. Time in Range is computed when both targets are set; the
+// configured target bands are always reported. Concurrent calls with the same
+// filters share one query.
+func (s *GlucoseServiceImpl) GetStatisticsWithFilters(ctx context.Context, filters repository.GlucoseStatisticsFilters) (*MeasurementStats, error) {
+	s.bandsMu.RLock()
+	filters.Bands = s.targetBands
+	s.bandsMu.RUnlock()
+
+	query := ""
+	if filters.Query != nil {
+		query = filters.Query.String()
+	}
+	key := coalesceKey("stats", filters.StartTime, filters.EndTime,
+		filters.TargetLowMgDl, filters.TargetHighMgDl, query, filters.Bands)
+
+	return coalesce(ctx, &s.reads, key, func(ctx context.Context) (*MeasurementStats, error) {
+		return s.statistics(ctx, filters)
+	})
+}
+
+// statistics computes the statistics of GetStatisticsWithFilters.
+func (s *GlucoseServiceImpl) statistics(ctx context.Context, filters repository.GlucoseStatisticsFilters) (*MeasurementStats, error) {
 	result, err := s.repo.GetStatistics(ctx, filters)
 	if err != nil {
 		return nil, err
@@ -161,14 +237,27 @@ func (s *GlucoseServiceImpl) GetStatistics(ctx context.Context, start, end *time
 	}
 
 	stats.GMI = domain.CalculateGMI(stats.AverageMgDl)
+	stats.EstimatedA1c = domain.CalculateEstimatedA1c(stats.AverageMgDl)
+	if stats.Average > 0 {
+		stats.CV = stats.StdDev / stats.Average * 100
+	}
 
 	// Calculate Time in Range percentages if targets were provided
-	if result.Count > 0 && targets != nil {
+	if result.Count > 0 && filters.TargetLowMgDl != nil && filters.TargetHighMgDl != nil {
 		total := float64(result.Count)
 		stats.TimeInRange = (float64(result.InRangeCount) / total) * 100
 		stats.TimeBelowRange = (float64(result.BelowRangeCount) / total) * 100
 		stats.TimeAboveRange = (float64(result.AboveRangeCount) / total) * 100
 	}
 
+	// Report the target bands even without data, so clients see what is configured
+	for i, band := range filters.Bands {
+		bandTime := BandTime{TargetBand: band}
+		if result.Count > 0 && i < len(result.BandCounts) {
+			bandTime.Percent = float64(result.BandCounts[i]) / float64(result.Count) * 100
+		}
+		stats.TargetBands = append(stats.TargetBands, bandTime)
+	}
+
 	return stats, nil
 }
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// Glucose event detection
+const (
+	// minGlucoseEventDuration is how long readings must stay out of range to
+	// count as an event (the consensus definition of a hypoglycemic event).
+	minGlucoseEventDuration = 15 * time.Minute
+	// glucoseEventLookback is the window detected again on each run, so
+	// late readings (backfilled history) are taken into account.
+	glucoseEventLookback = 24 * time.Hour
+)
+
+// GlucoseEventDetection reports a detection run.
+type GlucoseEventDetection struct {
+	Since    time.Time `json:"since"`    // Start of the scanned window, zero for the whole history
+	Readings int       `json:"readings"` // Measurements scanned
+	Events   int       `json:"events"`   // Events detected in the window
+}
+
+// GlucoseEventServiceImpl implements GlucoseEventService.
+type GlucoseEventServiceImpl struct {
+	repo        repository.GlucoseEventRepository
+	glucoseRepo repository.GlucoseRepository
+	targetsRepo repository.TargetsRepository
+	uow         repository.UnitOfWork
+	logger      *slog.Logger
+	now         func() time.Time
+	scanned     atomic.Bool // The whole history was scanned since the start
+}
+
+// NewGlucoseEventService creates a new GlucoseEventService.
+func NewGlucoseEventService(
+	repo repository.GlucoseEventRepository,
+	glucoseRepo repository.GlucoseRepository,
+	targetsRepo repository.TargetsRepository,
+	uow repository.UnitOfWork,
+	logger *slog.Logger,
+) *GlucoseEventServiceImpl {
+	return &GlucoseEventServiceImpl{
+		repo:        repo,
+		glucoseRepo: glucoseRepo,
+		targetsRepo: targetsRepo,
+		uow:         uow,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// DetectEvents detects the low and high events of the recent measurements of
+// each patient (of the patient of ctx, if any) and replaces the stored ones.
+// The first run scans the whole history, so events follow target changes made
+// while glcore was stopped. Nothing is detected until the glucose targets are
+// known.
+func (s *GlucoseEventServiceImpl) DetectEvents(ctx context.Context) (*GlucoseEventDetection, error) {
+	targets, err := s.targetsRepo.Find(ctx)
+	if errors.Is(err, persistence.ErrNotFound) {
+		s.logger.Debug("glucose targets unknown, skipping event detection")
+		return &GlucoseEventDetection{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get glucose targets: %w", err)
+	}
+
+	now := s.now().UTC()
+	var since time.Time
+	if s.scanned.Load() {
+		since = now.Add(-glucoseEventLookback)
+
+		// Detect again the event in progress at the start of the window
+		spanning, err := s.repo.FindSpanning(ctx, since.Add(-gapThreshold))
+		if err != nil && !errors.Is(err, persistence.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get glucose events: %w", err)
+		}
+		if spanning != nil {
+			since = spanning.StartTime
+		}
+	}
+
+	measurements, err := s.glucoseRepo.FindByTimeRange(ctx, since, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get measurements: %w", err)
+	}
+	slices.Reverse(measurements) // Oldest first
+
+	// The readings of different patients never form an event together
+	var events []*domain.GlucoseEvent
+	for _, patientMeasurements := range groupByPatient(measurements) {
+		detected := detectGlucoseEvents(patientMeasurements, targets.TargetLow, targets.TargetHigh, now)
+		for _, e := range detected {
+			e.PatientID = patientMeasurements[0].PatientID
+		}
+		events = append(events, detected...)
+	}
+
+	err = s.uow.ExecuteInTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := s.repo.DeleteFrom(txCtx, since); err != nil {
+			return err
+		}
+		return s.repo.CreateBatch(txCtx, events)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save glucose events: %w", err)
+	}
+
+	s.scanned.Store(true)
+	s.logger.Debug("glucose events detected", "since", since, "readings", len(measurements), "events", len(events))
+
+	return &GlucoseEventDetection{Since: since, Readings: len(measurements), Events: len(events)}, nil
+}
+
+// GetEventsWithFilters returns filtered and paginated events with total count.
+func (s *GlucoseEventServiceImpl) GetEventsWithFilters(ctx context.Context, filters repository.GlucoseEventFilters, limit, offset int) ([]*domain.GlucoseEvent, int64, error) {
+	events, err := s.repo.FindWithFilters(ctx, filters, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.repo.CountWithFilters(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// groupByPatient splits measurements by patient, keeping their order, in the
+// order of the first measurement of each patient.
+func groupByPatient(measurements []*domain.GlucoseMeasurement) [][]*domain.GlucoseMeasurement {
+	var groups [][]*domain.GlucoseMeasurement
+	index := make(map[string]int)
+	for _, m := range measurements {
+		i, ok := index[m.PatientID]
+		if !ok {
+			i = len(groups)
+			index[m.PatientID] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], m)
+	}
+	return groups
+}
+
+// detectGlucoseEvents groups consecutive readings below lowMgDl or above
+// highMgDl into events. measurements must be sorted oldest first. A gap of
+// gapThreshold or more ends an event; events shorter than
+// minGlucoseEventDuration are dropped. The last event is ongoing when its
+// last reading is the latest one and is recent at now.
+func detectGlucoseEvents(measurements []*domain.GlucoseMeasurement, lowMgDl, highMgDl int, now time.Time) []*domain.GlucoseEvent {
+	var events []*domain.GlucoseEvent
+	var current *domain.GlucoseEvent
+
+	closeEvent := func() {
+		if current != nil && current.EndTime.Sub(current.StartTime) >= minGlucoseEventDuration {
+			current.DurationMinutes = int(current.EndTime.Sub(current.StartTime).Minutes())
+			events = append(events, current)
+		}
+		current = nil
+	}
+
+	for _, m := range measurements {
+		var eventType string
+		var threshold int
+		switch {
+		case m.ValueInMgPerDl < lowMgDl:
+			eventType, threshold = domain.GlucoseEventTypeLow, lowMgDl
+		case m.ValueInMgPerDl > highMgDl:
+			eventType, threshold = domain.GlucoseEventTypeHigh, highMgDl
+		}
+
+		if current != nil && (current.Type != eventType || m.Timestamp.Sub(current.EndTime) >= gapThreshold) {
+			closeEvent()
+		}
+		if eventType == "" {
+			continue
+		}
+
+		if current == nil {
+			current = &domain.GlucoseEvent{
+				Type:          eventType,
+				StartTime:     m.Timestamp,
+				Extreme:       m.Value,
+				ExtremeMgDl:   m.ValueInMgPerDl,
+				ExtremeTime:   m.Timestamp,
+				ThresholdMgDl: threshold,
+			}
+		}
+		current.EndTime = m.Timestamp
+		current.Readings++
+
+		lower := eventType == domain.GlucoseEventTypeLow && m.ValueInMgPerDl < current.ExtremeMgDl
+		higher := eventType == domain.GlucoseEventTypeHigh && m.ValueInMgPerDl > current.ExtremeMgDl
+		if lower || higher {
+			current.Extreme = m.Value
+			current.ExtremeMgDl = m.ValueInMgPerDl
+			current.ExtremeTime = m.Timestamp
+		}
+	}
+
+	if current != nil {
+		current.Ongoing = now.Sub(current.EndTime) < gapThreshold
+	}
+	closeEvent()
+
+	return events
+}
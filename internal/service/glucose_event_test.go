@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// memoryGlucoseEventRepository keeps the glucose events in memory
+type memoryGlucoseEventRepository struct {
+	events []*domain.GlucoseEvent
+}
+
+func (r *memoryGlucoseEventRepository) CreateBatch(ctx context.Context, events []*domain.GlucoseEvent) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *memoryGlucoseEventRepository) DeleteFrom(ctx context.Context, since time.Time) (int64, error) {
+	var kept []*domain.GlucoseEvent
+	for _, e := range r.events {
+		if e.StartTime.Before(since) {
+			kept = append(kept, e)
+		}
+	}
+	deleted := int64(len(r.events) - len(kept))
+	r.events = kept
+	return deleted, nil
+}
+
+func (r *memoryGlucoseEventRepository) FindSpanning(ctx context.Context, t time.Time) (*domain.GlucoseEvent, error) {
+	for _, e := range r.events {
+		if e.StartTime.Before(t) && !e.EndTime.Before(t) {
+			return e, nil
+		}
+	}
+	return nil, persistence.ErrNotFound
+}
+
+func (r *memoryGlucoseEventRepository) FindWithFilters(ctx context.Context, filters repository.GlucoseEventFilters, limit, offset int) ([]*domain.GlucoseEvent, error) {
+	return r.events, nil
+}
+
+func (r *memoryGlucoseEventRepository) CountWithFilters(ctx context.Context, filters repository.GlucoseEventFilters) (int64, error) {
+	return int64(len(r.events)), nil
+}
+
+// readings returns one reading every 5 minutes from start, oldest first
+func readings(start time.Time, values ...int) []*domain.GlucoseMeasurement {
+	measurements := make([]*domain.GlucoseMeasurement, len(values))
+	for i, v := range values {
+		measurements[i] = &domain.GlucoseMeasurement{
+			Timestamp:      start.Add(time.Duration(i) * 5 * time.Minute),
+			Value:          float64(v) / 18,
+			ValueInMgPerDl: v,
+		}
+	}
+	return measurements
+}
+
+func TestDetectGlucoseEvents(t *testing.T) {
+	start := time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+
+	// A 20-minute low, a 5-minute high (too short) and a 15-minute high
+	measurements := readings(start, 80, 65, 60, 52, 58, 68, 90, 190, 200, 150, 190, 240, 210, 185, 120)
+
+	events := detectGlucoseEvents(measurements, 70, 180, start.Add(24*time.Hour))
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d: %+v", len(events), events)
+	}
+
+	low := events[0]
+	if low.Type != domain.GlucoseEventTypeLow || !low.StartTime.Equal(start.Add(5*time.Minute)) || low.DurationMinutes != 20 || low.Readings != 5 {
+		t.Errorf("expected a 20-minute low event of 5 readings, got %+v", low)
+	}
+	if low.ExtremeMgDl != 52 || !low.ExtremeTime.Equal(start.Add(15*time.Minute)) || low.ThresholdMgDl != 70 || low.Ongoing {
+		t.Errorf("expected the nadir of 52 mg/dL, got %+v", low)
+	}
+
+	high := events[1]
+	if high.Type != domain.GlucoseEventTypeHigh || high.DurationMinutes != 15 || high.ExtremeMgDl != 240 || high.ThresholdMgDl != 180 {
+		t.Errorf("expected a 15-minute high event peaking at 240 mg/dL, got %+v", high)
+	}
+}
+
+func TestDetectGlucoseEvents_GapsAndOngoing(t *testing.T) {
+	start := time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+
+	// A gap of 30 minutes ends the first low, too short on its own
+	measurements := append(readings(start, 60, 60), readings(start.Add(35*time.Minute), 60, 55, 60, 58)...)
+
+	now := start.Add(55 * time.Minute)
+	events := detectGlucoseEvents(measurements, 70, 180, now)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d: %+v", len(events), events)
+	}
+	if !events[0].StartTime.Equal(start.Add(35*time.Minute)) || !events[0].Ongoing {
+		t.Errorf("expected an ongoing event after the gap, got %+v", events[0])
+	}
+
+	// Without readings for a while, the event is over
+	events = detectGlucoseEvents(measurements, 70, 180, now.Add(time.Hour))
+	if len(events) != 1 || events[0].Ongoing {
+		t.Errorf("expected a finished event, got %+v", events)
+	}
+}
+
+func TestGlucoseEventService_DetectEvents(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	old := readings(now.Add(-48*time.Hour), 60, 60, 60, 60)
+	recent := readings(now.Add(-time.Hour), 200, 210, 220, 200, 150)
+
+	var from time.Time
+	glucoseRepo := &MockGlucoseRepository{
+		FindByTimeRangeFunc: func(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error) {
+			from = start
+			var result []*domain.GlucoseMeasurement
+			for _, m := range append(old, recent...) {
+				if !m.Timestamp.Before(start) && !m.Timestamp.After(end) {
+					result = append([]*domain.GlucoseMeasurement{m}, result...) // Newest first
+				}
+			}
+			return result, nil
+		},
+	}
+	repo := &memoryGlucoseEventRepository{}
+	targetsRepo := &memoryTargetsRepository{}
+	service := NewGlucoseEventService(repo, glucoseRepo, targetsRepo, &MockUnitOfWork{}, slog.Default())
+	service.now = func() time.Time { return now }
+
+	// Nothing is detected until the targets are known
+	result, err := service.DetectEvents(context.Background())
+	if err != nil || result.Events != 0 || len(repo.events) != 0 {
+		t.Fatalf("expected no detection without targets, got %+v (%v)", result, err)
+	}
+
+	targetsRepo.targets = &domain.GlucoseTargets{TargetLow: 70, TargetHigh: 180}
+
+	// The first run scans the whole history
+	result, err = service.DetectEvents(context.Background())
+	if err != nil {
+		t.Fatalf("DetectEvents: %v", err)
+	}
+	if !from.IsZero() || result.Readings != 9 || result.Events != 2 || len(repo.events) != 2 {
+		t.Fatalf("expected the old low and the recent high, got %+v from %v", result, from)
+	}
+
+	// Later runs detect the recent window again, keeping older events
+	result, err = service.DetectEvents(context.Background())
+	if err != nil {
+		t.Fatalf("DetectEvents: %v", err)
+	}
+	if !from.Equal(now.Add(-glucoseEventLookback)) || result.Events != 1 || len(repo.events) != 2 {
+		t.Errorf("expected the recent high detected again, got %+v from %v (%d stored)", result, from, len(repo.events))
+	}
+}
+
+func TestGlucoseEventService_DetectEventsPerPatient(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	low := readings(now.Add(-time.Hour), 60, 60, 60, 60, 60)
+	normal := readings(now.Add(-time.Hour), 120, 120, 120, 120, 120)
+	var measurements []*domain.GlucoseMeasurement
+	for i := range low {
+		low[i].PatientID, normal[i].PatientID = "patient-a", "patient-b"
+		measurements = append([]*domain.GlucoseMeasurement{low[i], normal[i]}, measurements...) // Newest first
+	}
+
+	glucoseRepo := &MockGlucoseRepository{
+		FindByTimeRangeFunc: func(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error) {
+			return measurements, nil
+		},
+	}
+	repo := &memoryGlucoseEventRepository{}
+	targetsRepo := &memoryTargetsRepository{targets: &domain.GlucoseTargets{TargetLow: 70, TargetHigh: 180}}
+	service := NewGlucoseEventService(repo, glucoseRepo, targetsRepo, &MockUnitOfWork{}, slog.Default())
+	service.now = func() time.Time { return now }
+
+	// The normal readings of patient b do not interrupt the low of patient a
+	if _, err := service.DetectEvents(context.Background()); err != nil {
+		t.Fatalf("DetectEvents: %v", err)
+	}
+	if len(repo.events) != 1 || repo.events[0].PatientID != "patient-a" || repo.events[0].Readings != 5 {
+		t.Errorf("expected one low event of patient a, got %+v", repo.events)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"math"
 	"testing"
 	"time"
 
@@ -14,13 +15,39 @@ import (
 
 // MockGlucoseRepository for testing
 type MockGlucoseRepository struct {
-	SaveFunc             func(ctx context.Context, m *domain.GlucoseMeasurement) (bool, error)
-	FindLatestFunc       func(ctx context.Context) (*domain.GlucoseMeasurement, error)
-	FindAllFunc          func(ctx context.Context) ([]*domain.GlucoseMeasurement, error)
-	FindByTimeRangeFunc  func(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error)
-	FindWithFiltersFunc  func(ctx context.Context, filters repository.GlucoseFilters, limit, offset int) ([]*domain.GlucoseMeasurement, error)
-	CountWithFiltersFunc func(ctx context.Context, filters repository.GlucoseFilters) (int64, error)
-	GetStatisticsFunc    func(ctx context.Context, filters repository.GlucoseStatisticsFilters) (*repository.GlucoseStatisticsResult, error)
+	SaveFunc                    func(ctx context.Context, m *domain.GlucoseMeasurement) (bool, error)
+	FindLatestFunc              func(ctx context.Context) (*domain.GlucoseMeasurement, error)
+	FindAllFunc                 func(ctx context.Context) ([]*domain.GlucoseMeasurement, error)
+	FindByTimeRangeFunc         func(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error)
+	FindWithFiltersFunc         func(ctx context.Context, filters repository.GlucoseFilters, limit, offset int) ([]*domain.GlucoseMeasurement, error)
+	CountWithFiltersFunc        func(ctx context.Context, filters repository.GlucoseFilters) (int64, error)
+	GetStatisticsFunc           func(ctx context.Context, filters repository.GlucoseStatisticsFilters) (*repository.GlucoseStatisticsResult, error)
+	GetHistogramFunc            func(ctx context.Context, filters repository.GlucoseFilters, bucketMgDl int) ([]repository.GlucoseHistogramBucket, error)
+	StreamWithFiltersFunc       func(ctx context.Context, filters repository.GlucoseFilters, fn func(*domain.GlucoseMeasurement) error) error
+	AssignPatientFunc           func(ctx context.Context, patientID string) (int64, error)
+	DeleteBeforeFunc            func(ctx context.Context, before time.Time) (int64, error)
+	FindEarliestStoredSinceFunc func(ctx context.Context, since time.Time) (*domain.GlucoseMeasurement, error)
+}
+
+func (m *MockGlucoseRepository) FindEarliestStoredSince(ctx context.Context, since time.Time) (*domain.GlucoseMeasurement, error) {
+	if m.FindEarliestStoredSinceFunc != nil {
+		return m.FindEarliestStoredSinceFunc(ctx, since)
+	}
+	return nil, persistence.ErrNotFound
+}
+
+func (m *MockGlucoseRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	if m.DeleteBeforeFunc != nil {
+		return m.DeleteBeforeFunc(ctx, before)
+	}
+	return 0, nil
+}
+
+func (m *MockGlucoseRepository) AssignPatient(ctx context.Context, patientID string) (int64, error) {
+	if m.AssignPatientFunc != nil {
+		return m.AssignPatientFunc(ctx, patientID)
+	}
+	return 0, nil
 }
 
 func (m *MockGlucoseRepository) Save(ctx context.Context, measurement *domain.GlucoseMeasurement) (bool, error) {
@@ -72,6 +99,20 @@ func (m *MockGlucoseRepository) GetStatistics(ctx context.Context, filters repos
 	return &repository.GlucoseStatisticsResult{}, nil
 }
 
+func (m *MockGlucoseRepository) GetHistogram(ctx context.Context, filters repository.GlucoseFilters, bucketMgDl int) ([]repository.GlucoseHistogramBucket, error) {
+	if m.GetHistogramFunc != nil {
+		return m.GetHistogramFunc(ctx, filters, bucketMgDl)
+	}
+	return []repository.GlucoseHistogramBucket{}, nil
+}
+
+func (m *MockGlucoseRepository) StreamWithFilters(ctx context.Context, filters repository.GlucoseFilters, fn func(*domain.GlucoseMeasurement) error) error {
+	if m.StreamWithFiltersFunc != nil {
+		return m.StreamWithFiltersFunc(ctx, filters, fn)
+	}
+	return nil
+}
+
 func TestGlucoseService_SaveMeasurement_Success(t *testing.T) {
 	saveCalled := false
 
@@ -85,7 +126,7 @@ func TestGlucoseService_SaveMeasurement_Success(t *testing.T) {
 		},
 	}
 
-	service := NewGlucoseService(mockRepo, slog.Default(), nil)
+	service := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
 
 	measurement := &domain.GlucoseMeasurement{
 		Timestamp:      time.Now(),
@@ -123,7 +164,7 @@ func TestGlucoseService_SaveMeasurement_RetryOnTransientError(t *testing.T) {
 		},
 	}
 
-	service := NewGlucoseService(mockRepo, slog.Default(), nil)
+	service := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
 
 	measurement := &domain.GlucoseMeasurement{
 		Timestamp: time.Now(),
@@ -152,7 +193,7 @@ func TestGlucoseService_SaveMeasurement_FailureAfterRetries(t *testing.T) {
 		},
 	}
 
-	service := NewGlucoseService(mockRepo, slog.Default(), nil)
+	service := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
 
 	measurement := &domain.GlucoseMeasurement{
 		Timestamp: time.Now(),
@@ -181,7 +222,7 @@ func TestGlucoseService_GetLatestMeasurement_Success(t *testing.T) {
 		},
 	}
 
-	service := NewGlucoseService(mockRepo, slog.Default(), nil)
+	service := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
 
 	measurement, err := service.GetLatestMeasurement(context.Background())
 	if err != nil {
@@ -208,7 +249,7 @@ func TestGlucoseService_GetLatestMeasurement_NotFound(t *testing.T) {
 		},
 	}
 
-	service := NewGlucoseService(mockRepo, slog.Default(), nil)
+	service := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
 
 	measurement, err := service.GetLatestMeasurement(context.Background())
 	if err == nil {
@@ -237,7 +278,7 @@ func TestGlucoseService_GetAllMeasurements_Success(t *testing.T) {
 		},
 	}
 
-	service := NewGlucoseService(mockRepo, slog.Default(), nil)
+	service := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
 
 	measurements, err := service.GetAllMeasurements(context.Background())
 	if err != nil {
@@ -256,7 +297,7 @@ func TestGlucoseService_GetAllMeasurements_Empty(t *testing.T) {
 		},
 	}
 
-	service := NewGlucoseService(mockRepo, slog.Default(), nil)
+	service := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
 
 	measurements, err := service.GetAllMeasurements(context.Background())
 	if err != nil {
@@ -291,7 +332,7 @@ func TestGlucoseService_GetMeasurementsByTimeRange_Success(t *testing.T) {
 		},
 	}
 
-	service := NewGlucoseService(mockRepo, slog.Default(), nil)
+	service := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
 
 	measurements, err := service.GetMeasurementsByTimeRange(context.Background(), start, end)
 	if err != nil {
@@ -314,7 +355,7 @@ func TestGlucoseService_GetMeasurementsByTimeRange_Empty(t *testing.T) {
 		},
 	}
 
-	service := NewGlucoseService(mockRepo, slog.Default(), nil)
+	service := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
 
 	measurements, err := service.GetMeasurementsByTimeRange(context.Background(), start, end)
 	if err != nil {
@@ -337,7 +378,7 @@ func TestGlucoseService_SaveMeasurement_ValidatesType(t *testing.T) {
 		},
 	}
 
-	service := NewGlucoseService(mockRepo, slog.Default(), nil)
+	service := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
 
 	tests := []struct {
 		name string
@@ -362,3 +403,108 @@ func TestGlucoseService_SaveMeasurement_ValidatesType(t *testing.T) {
 		})
 	}
 }
+
+func TestGlucoseService_GetStatistics_TargetBands(t *testing.T) {
+	bands := []domain.TargetBand{
+		{Name: domain.TargetBandTight, LowMgDl: 70, HighMgDl: 140},
+		{Name: "pregnancy", LowMgDl: 63, HighMgDl: 140},
+	}
+	mockRepo := &MockGlucoseRepository{
+		GetStatisticsFunc: func(ctx context.Context, filters repository.GlucoseStatisticsFilters) (*repository.GlucoseStatisticsResult, error) {
+			if len(filters.Bands) != 2 {
+				t.Errorf("expected configured bands to be passed to the repository, got %v", filters.Bands)
+			}
+			return &repository.GlucoseStatisticsResult{Count: 200, BandCounts: []int64{120, 150}}, nil
+		},
+	}
+	service := NewGlucoseService(mockRepo, bands, slog.Default(), nil)
+
+	stats, err := service.GetStatistics(context.Background(), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if len(stats.TargetBands) != 2 {
+		t.Fatalf("expected 2 target bands, got %d", len(stats.TargetBands))
+	}
+	if stats.TargetBands[0].Name != domain.TargetBandTight || stats.TargetBands[0].Percent != 60 {
+		t.Errorf("expected tight band at 60%%, got %+v", stats.TargetBands[0])
+	}
+	if stats.TargetBands[1].Percent != 75 {
+		t.Errorf("expected pregnancy band at 75%%, got %+v", stats.TargetBands[1])
+	}
+
+	// Without any configured band, nothing is reported
+	service = NewGlucoseService(mockRepo, nil, slog.Default(), nil)
+	mockRepo.GetStatisticsFunc = func(ctx context.Context, filters repository.GlucoseStatisticsFilters) (*repository.GlucoseStatisticsResult, error) {
+		return &repository.GlucoseStatisticsResult{Count: 10}, nil
+	}
+	stats, err = service.GetStatistics(context.Background(), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if stats.TargetBands != nil {
+		t.Errorf("expected no target bands, got %+v", stats.TargetBands)
+	}
+}
+
+func TestGlucoseService_GetStatistics_Variability(t *testing.T) {
+	mockRepo := &MockGlucoseRepository{
+		GetStatisticsFunc: func(ctx context.Context, filters repository.GlucoseStatisticsFilters) (*repository.GlucoseStatisticsResult, error) {
+			return &repository.GlucoseStatisticsResult{Count: 100, Average: 8.0, AverageMgDl: 154, Variance: 4.0}, nil
+		},
+	}
+	service := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
+
+	stats, err := service.GetStatistics(context.Background(), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if stats.StdDev != 2 || stats.CV != 25 {
+		t.Errorf("expected stdDev 2 and CV 25%%, got %v and %v", stats.StdDev, stats.CV)
+	}
+	if stats.GMI == nil || stats.EstimatedA1c == nil || math.Abs(*stats.EstimatedA1c-7.0) > 0.01 {
+		t.Errorf("expected GMI and an estimated A1c of ~7.0%%, got %v and %v", stats.GMI, stats.EstimatedA1c)
+	}
+
+	// Without data, nothing is estimated
+	mockRepo.GetStatisticsFunc = func(ctx context.Context, filters repository.GlucoseStatisticsFilters) (*repository.GlucoseStatisticsResult, error) {
+		return &repository.GlucoseStatisticsResult{}, nil
+	}
+	stats, err = service.GetStatistics(context.Background(), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if stats.CV != 0 || stats.GMI != nil || stats.EstimatedA1c != nil {
+		t.Errorf("expected no variability or estimates, got %+v", stats)
+	}
+}
+
+func TestGlucoseService_SetTightBand(t *testing.T) {
+	var got []domain.TargetBand
+	mockRepo := &MockGlucoseRepository{
+		GetStatisticsFunc: func(ctx context.Context, filters repository.GlucoseStatisticsFilters) (*repository.GlucoseStatisticsResult, error) {
+			got = filters.Bands
+			return &repository.GlucoseStatisticsResult{}, nil
+		},
+	}
+	night := domain.TargetBand{Name: "night", LowMgDl: 80, HighMgDl: 120}
+	service := NewGlucoseService(mockRepo, []domain.TargetBand{{Name: domain.TargetBandTight, LowMgDl: 70, HighMgDl: 140}, night}, slog.Default(), nil)
+
+	service.SetTightBand(domain.TargetBand{LowMgDl: 70, HighMgDl: 110})
+	if _, err := service.GetStatistics(context.Background(), nil, nil, nil); err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if len(got) != 2 || got[0].HighMgDl != 110 || got[1] != night {
+		t.Errorf("expected the tight band replaced and the night band kept, got %+v", got)
+	}
+
+	// Added when no band is configured
+	service = NewGlucoseService(mockRepo, nil, slog.Default(), nil)
+	service.SetTightBand(domain.TargetBand{LowMgDl: 70, HighMgDl: 110})
+	if _, err := service.GetStatistics(context.Background(), nil, nil, nil); err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != domain.TargetBandTight {
+		t.Errorf("expected the tight band added, got %+v", got)
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// HistogramBucket is the number of readings whose value falls in [StartMgDl, EndMgDl).
+type HistogramBucket struct {
+	StartMgDl int     `json:"startMgDl"`
+	EndMgDl   int     `json:"endMgDl"` // Exclusive
+	Count     int64   `json:"count"`
+	Percent   float64 `json:"percent"` // Share of all readings in the histogram
+}
+
+// Histogram is the distribution of glucose readings in buckets of BucketMgDl.
+// Buckets are contiguous from the lowest to the highest reading.
+type Histogram struct {
+	BucketMgDl int               `json:"bucketMgDl"`
+	Total      int64             `json:"total"`
+	Buckets    []HistogramBucket `json:"buckets"`
+}
+
+// GetHistogram returns the distribution of the readings matching filters.
+// Buckets between the lowest and highest reading are included even when empty,
+// so clients can draw the bars without filling gaps.
+func (s *GlucoseServiceImpl) GetHistogram(ctx context.Context, filters repository.GlucoseFilters, bucketMgDl int) (*Histogram, error) {
+	counts, err := s.repo.GetHistogram(ctx, filters, bucketMgDl)
+	if err != nil {
+		return nil, err
+	}
+
+	return newHistogram(counts, bucketMgDl), nil
+}
+
+// newHistogram fills the gaps between sorted bucket counts and computes percentages.
+func newHistogram(counts []repository.GlucoseHistogramBucket, bucketMgDl int) *Histogram {
+	histogram := &Histogram{
+		BucketMgDl: bucketMgDl,
+		Buckets:    []HistogramBucket{},
+	}
+	if len(counts) == 0 {
+		return histogram
+	}
+
+	for _, c := range counts {
+		histogram.Total += c.Count
+	}
+
+	next := 0
+	last := counts[len(counts)-1].StartMgDl
+	for start := counts[0].StartMgDl; start <= last; start += bucketMgDl {
+		bucket := HistogramBucket{StartMgDl: start, EndMgDl: start + bucketMgDl}
+		if next < len(counts) && counts[next].StartMgDl == start {
+			bucket.Count = counts[next].Count
+			bucket.Percent = float64(bucket.Count) / float64(histogram.Total) * 100
+			next++
+		}
+		histogram.Buckets = append(histogram.Buckets, bucket)
+	}
+
+	return histogram
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+func TestGlucoseService_GetHistogram_FillsGaps(t *testing.T) {
+	mockRepo := &MockGlucoseRepository{
+		GetHistogramFunc: func(ctx context.Context, filters repository.GlucoseFilters, bucketMgDl int) ([]repository.GlucoseHistogramBucket, error) {
+			if bucketMgDl != 20 {
+				t.Errorf("expected bucket of 20, got %d", bucketMgDl)
+			}
+			return []repository.GlucoseHistogramBucket{
+				{StartMgDl: 60, Count: 1},
+				{StartMgDl: 100, Count: 2},
+				{StartMgDl: 120, Count: 1},
+			}, nil
+		},
+	}
+	svc := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
+
+	histogram, err := svc.GetHistogram(context.Background(), repository.GlucoseFilters{}, 20)
+	if err != nil {
+		t.Fatalf("GetHistogram: %v", err)
+	}
+
+	if histogram.Total != 4 {
+		t.Errorf("expected total 4, got %d", histogram.Total)
+	}
+	wantCounts := []int64{1, 0, 2, 1}
+	if len(histogram.Buckets) != len(wantCounts) {
+		t.Fatalf("expected %d buckets, got %+v", len(wantCounts), histogram.Buckets)
+	}
+	for i, want := range wantCounts {
+		b := histogram.Buckets[i]
+		if b.StartMgDl != 60+i*20 || b.EndMgDl != 80+i*20 || b.Count != want {
+			t.Errorf("bucket %d: unexpected %+v", i, b)
+		}
+	}
+	if histogram.Buckets[2].Percent != 50 {
+		t.Errorf("expected 50%% in the 100-120 bucket, got %v", histogram.Buckets[2].Percent)
+	}
+}
+
+func TestGlucoseService_GetHistogram_Empty(t *testing.T) {
+	svc := NewGlucoseService(&MockGlucoseRepository{}, nil, slog.Default(), nil)
+
+	histogram, err := svc.GetHistogram(context.Background(), repository.GlucoseFilters{}, 10)
+	if err != nil {
+		t.Fatalf("GetHistogram: %v", err)
+	}
+	if histogram.Total != 0 || len(histogram.Buckets) != 0 || histogram.BucketMgDl != 10 {
+		t.Errorf("expected an empty histogram, got %+v", histogram)
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"os"
 	"time"
 
 	"github.com/R4yL-dev/glcmd/internal/domain"
@@ -26,9 +27,27 @@ type GlucoseService interface {
 	// GetMeasurementsWithFilters returns filtered and paginated measurements with total count
 	GetMeasurementsWithFilters(ctx context.Context, filters repository.GlucoseFilters, limit, offset int) ([]*domain.GlucoseMeasurement, int64, error)
 
+	// StreamMeasurements calls fn for each measurement matching filters, oldest first
+	StreamMeasurements(ctx context.Context, filters repository.GlucoseFilters, fn func(*domain.GlucoseMeasurement) error) error
+
 	// GetStatistics calculates aggregated statistics for a time range.
 	// If start and end are nil, returns statistics for all data (all time).
 	GetStatistics(ctx context.Context, start, end *time.Time, targets *domain.GlucoseTargets) (*MeasurementStats, error)
+
+	// GetStatisticsWithFilters calculates aggregated statistics of the measurements matching filters
+	GetStatisticsWithFilters(ctx context.Context, filters repository.GlucoseStatisticsFilters) (*MeasurementStats, error)
+
+	// GetHistogram returns the distribution of the measurements matching filters in buckets of bucketMgDl
+	GetHistogram(ctx context.Context, filters repository.GlucoseFilters, bucketMgDl int) (*Histogram, error)
+
+	// GetPercentiles returns the glucose percentiles by local time of day of a time range
+	GetPercentiles(ctx context.Context, start, end time.Time, bucket time.Duration) (*Percentiles, error)
+
+	// GetDailyQuality returns the data quality (capture, gaps, artifacts) of each local day in a time range
+	GetDailyQuality(ctx context.Context, start, end time.Time) ([]*DayQuality, error)
+
+	// SetTightBand replaces the tight range band reported in statistics (added if not configured)
+	SetTightBand(band domain.TargetBand)
 }
 
 // SensorService defines the interface for sensor management business logic.
@@ -53,11 +72,48 @@ type SensorService interface {
 	// only if the provided timestamp is newer than the existing one.
 	UpdateLastMeasurementIfNewer(ctx context.Context, timestamp time.Time) error
 
+	// AssignPatient assigns the measurements and sensors stored without a patient to patientID
+	AssignPatient(ctx context.Context, patientID string) error
+
 	// GetSensorsWithFilters returns filtered and paginated sensors with total count
 	GetSensorsWithFilters(ctx context.Context, filters repository.SensorFilters, limit, offset int) ([]*domain.SensorConfig, int64, error)
 
 	// GetStatistics returns aggregated sensor lifecycle statistics
 	GetStatistics(ctx context.Context, start, end *time.Time) (*SensorStats, error)
+
+	// GetStatisticsWithFilters returns aggregated sensor lifecycle statistics of the sensors matching filters
+	GetStatisticsWithFilters(ctx context.Context, filters repository.SensorStatisticsFilters) (*SensorStats, error)
+
+	// GracePeriod returns how long an expired sensor may keep reporting
+	GracePeriod() time.Duration
+
+	// SetApplicationSite records the body site of the current sensor and
+	// reports whether the previous sensor used the same site
+	SetApplicationSite(ctx context.Context, site string) (*SiteAssignment, error)
+
+	// GetSiteHistory returns the application sites of the last limit sensors, newest first
+	GetSiteHistory(ctx context.Context, limit int) ([]*SiteUse, error)
+
+	// UpdateFeedback updates the note and rating of a sensor and returns the updated sensor
+	UpdateFeedback(ctx context.Context, serial string, feedback SensorFeedback) (*domain.SensorConfig, error)
+}
+
+// AttachmentService defines the interface for files linked to sensors (placement or skin reaction photos).
+type AttachmentService interface {
+	// AddAttachment stores a file and links it to a sensor
+	AddAttachment(ctx context.Context, serial, fileName, caption string, data []byte) (*domain.SensorAttachment, error)
+
+	// ListAttachments returns the attachments of a sensor, oldest first
+	ListAttachments(ctx context.Context, serial string) ([]*domain.SensorAttachment, error)
+
+	// OpenAttachment returns an attachment and its file, which the caller must close
+	OpenAttachment(ctx context.Context, id uint) (*domain.SensorAttachment, *os.File, error)
+
+	// DeleteAttachment removes an attachment and its file
+	DeleteAttachment(ctx context.Context, id uint) error
+
+	// PruneFiles removes the stored files no attachment refers to
+	PruneFiles(ctx context.Context) (int, error)
 }
 
 // ConfigService defines the interface for configuration management (user, device, targets).
@@ -79,4 +135,217 @@ type ConfigService interface {
 
 	// GetGlucoseTargets returns glucose targets
 	GetGlucoseTargets(ctx context.Context) (*domain.GlucoseTargets, error)
+
+	// SaveDashboardConfig saves the dashboard layout
+	SaveDashboardConfig(ctx context.Context, c *domain.DashboardConfig) error
+
+	// GetDashboardConfig returns the dashboard layout (default layout if none saved)
+	GetDashboardConfig(ctx context.Context) (*domain.DashboardConfig, error)
+
+	// SaveDisplayPreferences saves the display preferences
+	SaveDisplayPreferences(ctx context.Context, p *domain.DisplayPreferences) error
+
+	// GetDisplayPreferences returns the display preferences (defaults if none saved)
+	GetDisplayPreferences(ctx context.Context) (*domain.DisplayPreferences, error)
+}
+
+// ModeService defines the interface for the activity mode glucose alerts are evaluated in.
+type ModeService interface {
+	// StartExercise switches to exercise mode for duration
+	StartExercise(duration time.Duration) *domain.ModeStatus
+
+	// EndExercise reverts to normal mode immediately
+	EndExercise() *domain.ModeStatus
+
+	// Current returns the current mode and its alert thresholds
+	Current() *domain.ModeStatus
+
+	// SetLowAlert replaces the low alert threshold of normal mode (0 = default)
+	SetLowAlert(mgdl int)
+}
+
+// AlertService defines the interface for alert history.
+type AlertService interface {
+	// RecordAlert stores a fired alert
+	RecordAlert(ctx context.Context, alert *domain.Alert) error
+
+	// GetAlertsWithFilters returns filtered and paginated alerts with total count, newest first
+	GetAlertsWithFilters(ctx context.Context, filters repository.AlertFilters, limit, offset int) ([]*domain.Alert, int64, error)
+
+	// AcknowledgeAlert marks an alert as acknowledged
+	AcknowledgeAlert(ctx context.Context, id uint) error
+
+	// GetWeeklyCounts returns alert counts for the last weeks weeks, oldest first
+	GetWeeklyCounts(ctx context.Context, weeks int) ([]*AlertWeek, error)
+}
+
+// GlucoseEventService defines the interface for low and high glucose event detection.
+type GlucoseEventService interface {
+	// DetectEvents detects the events of the recent measurements and stores them
+	DetectEvents(ctx context.Context) (*GlucoseEventDetection, error)
+
+	// GetEventsWithFilters returns filtered and paginated events with total count, newest first
+	GetEventsWithFilters(ctx context.Context, filters repository.GlucoseEventFilters, limit, offset int) ([]*domain.GlucoseEvent, int64, error)
+}
+
+// DailySummaryService defines the interface for the daily glucose summaries.
+type DailySummaryService interface {
+	// Summarize computes the summaries of the recent days and stores them
+	Summarize(ctx context.Context) (*DailySummaryRun, error)
+
+	// GetDailySummaries returns the summaries of the local days within a time range, oldest first
+	GetDailySummaries(ctx context.Context, start, end time.Time) ([]*domain.DailySummary, error)
+}
+
+// RetentionService defines the interface for downsampling and pruning old measurements.
+type RetentionService interface {
+	// Apply rolls up the measurements past the downsampling age and deletes those past the retention horizon
+	Apply(ctx context.Context) (*RetentionRun, error)
+}
+
+// StorageService defines the interface for forecasting the database growth.
+type StorageService interface {
+	// Forecast estimates the rows and bytes stored per day and projects the database size
+	Forecast(ctx context.Context) (*StorageForecast, error)
+}
+
+// UpstreamService defines the interface for tracking LibreView availability.
+type UpstreamService interface {
+	// RecordFailure records a failed fetch; an outage opens after consecutive failures
+	RecordFailure(ctx context.Context, cause string, fetchErr error) error
+
+	// RecordSuccess records a successful fetch, ending the ongoing outage if any
+	RecordSuccess(ctx context.Context) error
+
+	// GetStatus returns the current availability, uptime and the outages of the last days days
+	GetStatus(ctx context.Context, days int) (*UpstreamStatus, error)
+}
+
+// SessionService defines the interface for the LibreView sessions the daemon
+// reuses across restarts. Sessions are encrypted with the account password.
+type SessionService interface {
+	// Load returns the usable stored session of an account (persistence.ErrNotFound if none)
+	Load(ctx context.Context, account, email, password string) (*domain.UpstreamCredentials, error)
+
+	// Save encrypts and stores the session of an account
+	Save(ctx context.Context, account, email, password string, creds *domain.UpstreamCredentials) error
+
+	// Forget deletes the stored session of an account
+	Forget(ctx context.Context, account string) error
+}
+
+// TreatmentService defines the interface for insulin treatments imported from pump exports.
+type TreatmentService interface {
+	// ImportTreatments stores parsed treatments, ignoring those already imported,
+	// reporting the number saved so far to progress (optional)
+	ImportTreatments(ctx context.Context, treatments []*domain.TreatmentEntry, progress func(done int)) (*TreatmentImport, error)
+
+	// GetTreatments returns treatments within a time range, oldest first
+	GetTreatments(ctx context.Context, start, end time.Time) ([]*domain.TreatmentEntry, error)
+
+	// AnalyzeTreatments correlates boluses in a time range with the glucose that follows
+	AnalyzeTreatments(ctx context.Context, start, end time.Time) (*TreatmentAnalysis, error)
+}
+
+// ViewService defines the interface for saved view management.
+type ViewService interface {
+	// SaveView creates or replaces a view and returns it as stored
+	SaveView(ctx context.Context, v *domain.SavedView) (*domain.SavedView, error)
+
+	// GetViews returns all views, sorted by name
+	GetViews(ctx context.Context) ([]*domain.SavedView, error)
+
+	// GetView returns the view with the given name
+	GetView(ctx context.Context, name string) (*domain.SavedView, error)
+
+	// DeleteView removes the view with the given name
+	DeleteView(ctx context.Context, name string) error
+}
+
+// NoteService defines the interface for note management.
+type NoteService interface {
+	// CreateNote stores a new note
+	CreateNote(ctx context.Context, n *domain.Note) (*domain.Note, error)
+
+	// UpdateNote replaces the timestamp, text and tags of a note and returns it as stored
+	UpdateNote(ctx context.Context, n *domain.Note) (*domain.Note, error)
+
+	// GetNote returns a note by its ID
+	GetNote(ctx context.Context, id uint) (*domain.Note, error)
+
+	// GetNotesWithFilters returns filtered and paginated notes with total count, newest first
+	GetNotesWithFilters(ctx context.Context, filters repository.NoteFilters, limit, offset int) ([]*domain.Note, int64, error)
+
+	// DeleteNote removes a note
+	DeleteNote(ctx context.Context, id uint) error
+}
+
+// LogbookService defines the interface for the insulin doses and carbohydrate
+// intakes logged by the user.
+type LogbookService interface {
+	// LogInsulin stores a new insulin dose
+	LogInsulin(ctx context.Context, d *domain.InsulinDose) (*domain.InsulinDose, error)
+
+	// GetInsulin returns insulin doses within a time range, oldest first
+	GetInsulin(ctx context.Context, start, end time.Time) ([]*domain.InsulinDose, error)
+
+	// LogCarbs stores a new carbohydrate intake
+	LogCarbs(ctx context.Context, c *domain.CarbIntake) (*domain.CarbIntake, error)
+
+	// GetCarbs returns carbohydrate intakes within a time range, oldest first
+	GetCarbs(ctx context.Context, start, end time.Time) ([]*domain.CarbIntake, error)
+
+	// GetDailyTotals sums the insulin and carbs logged on each local day of a time range
+	GetDailyTotals(ctx context.Context, start, end time.Time) ([]*DailyLogTotals, error)
+}
+
+// SyncService defines the interface for data synchronization between instances.
+type SyncService interface {
+	// GetManifest returns per-day content checksums for the UTC days covering [start, end]
+	GetManifest(ctx context.Context, start, end time.Time) (*SyncManifest, error)
+
+	// ExportDay returns all measurements and sensors of the UTC day containing date
+	ExportDay(ctx context.Context, date time.Time) (*SyncExport, error)
+}
+
+// PrivacyService defines the interface for exporting and erasing all personal data.
+type PrivacyService interface {
+	// Export returns all stored personal data
+	Export(ctx context.Context) (*PrivacyExport, error)
+
+	// RequestErasure issues a short-lived, single-use confirmation token for Erase
+	RequestErasure() (*ErasureConfirmation, error)
+
+	// Erase deletes all personal data; it requires the token from RequestErasure
+	Erase(ctx context.Context, confirmationToken string) (*ErasureResult, error)
+}
+
+// TokenService defines the interface for API token management.
+type TokenService interface {
+	// CreateToken issues a new token; the plaintext value is returned only here
+	CreateToken(ctx context.Context, name, scope string, expiresAt *time.Time) (*domain.APIToken, string, error)
+
+	// ListTokens returns all tokens (including revoked and expired), newest first
+	ListTokens(ctx context.Context) ([]*domain.APIToken, error)
+
+	// RevokeToken revokes a token immediately
+	RevokeToken(ctx context.Context, id uint) error
+
+	// Authenticate validates a token for the required scope and records its use
+	Authenticate(ctx context.Context, plaintext, requiredScope string) (*domain.APIToken, error)
+}
+
+// SigningService defines the interface for signing pushed payloads and managing keys.
+type SigningService interface {
+	// CreateKey generates a new signing key; the secret is returned only here
+	CreateKey(ctx context.Context) (*domain.SigningKey, string, error)
+
+	// ListKeys returns all keys (including retired ones), newest first
+	ListKeys(ctx context.Context) ([]*domain.SigningKey, error)
+
+	// RetireKey stops using a key for signing
+	RetireKey(ctx context.Context, id uint) error
+
+	// Sign returns the signature of a payload, or "" when no key exists
+	Sign(ctx context.Context, eventType string, payload []byte) (string, error)
 }
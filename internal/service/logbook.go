@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// DailyLogTotals sums the insulin and carbs logged on a day.
+type DailyLogTotals struct {
+	Date         string  `json:"date"`         // YYYY-MM-DD, local time
+	InsulinUnits float64 `json:"insulinUnits"` // rapidUnits + longUnits
+	RapidUnits   float64 `json:"rapidUnits"`
+	LongUnits    float64 `json:"longUnits"`
+	CarbsGrams   float64 `json:"carbsGrams"`
+}
+
+// LogbookServiceImpl implements LogbookService.
+type LogbookServiceImpl struct {
+	insulinRepo repository.InsulinRepository
+	carbRepo    repository.CarbRepository
+	logger      *slog.Logger
+}
+
+// NewLogbookService creates a new LogbookService.
+func NewLogbookService(insulinRepo repository.InsulinRepository, carbRepo repository.CarbRepository, logger *slog.Logger) *LogbookServiceImpl {
+	return &LogbookServiceImpl{
+		insulinRepo: insulinRepo,
+		carbRepo:    carbRepo,
+		logger:      logger,
+	}
+}
+
+// LogInsulin stores a new insulin dose, its timestamp in UTC.
+func (s *LogbookServiceImpl) LogInsulin(ctx context.Context, d *domain.InsulinDose) (*domain.InsulinDose, error) {
+	d.Timestamp = d.Timestamp.UTC()
+	if err := s.insulinRepo.Create(ctx, d); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("insulin logged", "id", d.ID, "units", d.Units, "type", d.Type)
+
+	return d, nil
+}
+
+// GetInsulin returns insulin doses within a time range, oldest first.
+func (s *LogbookServiceImpl) GetInsulin(ctx context.Context, start, end time.Time) ([]*domain.InsulinDose, error) {
+	return s.insulinRepo.FindByTimeRange(ctx, start, end)
+}
+
+// LogCarbs stores a new carbohydrate intake, its timestamp in UTC.
+func (s *LogbookServiceImpl) LogCarbs(ctx context.Context, c *domain.CarbIntake) (*domain.CarbIntake, error) {
+	c.Timestamp = c.Timestamp.UTC()
+	if err := s.carbRepo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("carbs logged", "id", c.ID, "grams", c.Grams)
+
+	return c, nil
+}
+
+// GetCarbs returns carbohydrate intakes within a time range, oldest first.
+func (s *LogbookServiceImpl) GetCarbs(ctx context.Context, start, end time.Time) ([]*domain.CarbIntake, error) {
+	return s.carbRepo.FindByTimeRange(ctx, start, end)
+}
+
+// GetDailyTotals sums the insulin and carbs logged on each local day of
+// [start, end), days without entries included.
+func (s *LogbookServiceImpl) GetDailyTotals(ctx context.Context, start, end time.Time) ([]*DailyLogTotals, error) {
+	doses, err := s.insulinRepo.FindByTimeRange(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get insulin doses: %w", err)
+	}
+	intakes, err := s.carbRepo.FindByTimeRange(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get carb intakes: %w", err)
+	}
+	return dailyLogTotals(doses, intakes, start, end), nil
+}
+
+// dailyLogTotals splits [start, end] into local days and sums the doses and
+// intakes of each of them.
+func dailyLogTotals(doses []*domain.InsulinDose, intakes []*domain.CarbIntake, start, end time.Time) []*DailyLogTotals {
+	days := []*DailyLogTotals{}
+	byDate := make(map[string]*DailyLogTotals)
+	for dayStart := start; dayStart.Before(end); {
+		local := dayStart.Local()
+		day := &DailyLogTotals{Date: local.Format("2006-01-02")}
+		days = append(days, day)
+		byDate[day.Date] = day
+
+		dayStart = time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, time.Local)
+	}
+
+	for _, d := range doses {
+		day, ok := byDate[d.Timestamp.Local().Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		if d.Type == domain.InsulinTypeLong {
+			day.LongUnits += d.Units
+		} else {
+			day.RapidUnits += d.Units
+		}
+	}
+	for _, c := range intakes {
+		if day, ok := byDate[c.Timestamp.Local().Format("2006-01-02")]; ok {
+			day.CarbsGrams += c.Grams
+		}
+	}
+
+	for _, day := range days {
+		day.RapidUnits = round2(day.RapidUnits)
+		day.LongUnits = round2(day.LongUnits)
+		day.InsulinUnits = round2(day.RapidUnits + day.LongUnits)
+		day.CarbsGrams = round1(day.CarbsGrams)
+	}
+	return days
+}
+
+// round2 rounds to two decimals, the precision of insulin doses.
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
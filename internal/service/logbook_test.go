@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+func TestDailyLogTotals(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	doses := []*domain.InsulinDose{
+		{Timestamp: day.Add(7 * time.Hour), Units: 4.1, Type: domain.InsulinTypeRapid},
+		{Timestamp: day.Add(12 * time.Hour), Units: 5.2, Type: domain.InsulinTypeRapid},
+		{Timestamp: day.Add(22 * time.Hour), Units: 18, Type: domain.InsulinTypeLong},
+		{Timestamp: day.Add(31 * time.Hour), Units: 3, Type: domain.InsulinTypeRapid},
+	}
+	intakes := []*domain.CarbIntake{
+		{Timestamp: day.Add(7 * time.Hour), Grams: 40.5},
+		{Timestamp: day.Add(12 * time.Hour), Grams: 60},
+	}
+
+	days := dailyLogTotals(doses, intakes, day, day.AddDate(0, 0, 3))
+	if len(days) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(days))
+	}
+
+	first := days[0]
+	if first.Date != "2026-03-02" || first.RapidUnits != 9.3 || first.LongUnits != 18 || first.InsulinUnits != 27.3 || first.CarbsGrams != 100.5 {
+		t.Errorf("unexpected first day: %+v", first)
+	}
+	if second := days[1]; second.InsulinUnits != 3 || second.CarbsGrams != 0 {
+		t.Errorf("unexpected second day: %+v", second)
+	}
+	if third := days[2]; third.Date != "2026-03-04" || third.InsulinUnits != 0 {
+		t.Errorf("expected an empty third day, got %+v", third)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// MaxExerciseDuration bounds exercise mode so a forgotten session does not
+// keep alerts adjusted for days.
+const MaxExerciseDuration = 12 * time.Hour
+
+// ModeServiceImpl implements ModeService.
+// The mode is kept in memory: a restart reverts to normal mode.
+type ModeServiceImpl struct {
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu           sync.Mutex
+	mode         domain.Mode
+	endsAt       time.Time // Zero in normal mode
+	lowAlertMgDl int       // Low alert threshold of normal mode (0 = domain.DefaultLowAlertMgDl)
+}
+
+// NewModeService creates a new ModeService in normal mode.
+func NewModeService(logger *slog.Logger) *ModeServiceImpl {
+	return &ModeServiceImpl{
+		logger: logger,
+		now:    time.Now,
+		mode:   domain.ModeNormal,
+	}
+}
+
+// StartExercise switches to exercise mode for duration, replacing any
+// running exercise session.
+func (s *ModeServiceImpl) StartExercise(duration time.Duration) *domain.ModeStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mode = domain.ModeExercise
+	s.endsAt = s.now().UTC().Add(duration)
+
+	s.logger.Info("exercise mode started", "duration", duration, "endsAt", s.endsAt)
+
+	return s.statusLocked()
+}
+
+// EndExercise reverts to normal mode immediately.
+func (s *ModeServiceImpl) EndExercise() *domain.ModeStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.mode == domain.ModeExercise {
+		s.logger.Info("exercise mode ended")
+	}
+	s.mode = domain.ModeNormal
+	s.endsAt = time.Time{}
+
+	return s.statusLocked()
+}
+
+// Current returns the current mode. An elapsed exercise session reverts to
+// normal mode.
+func (s *ModeServiceImpl) Current() *domain.ModeStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.mode == domain.ModeExercise && !s.now().Before(s.endsAt) {
+		s.logger.Info("exercise mode elapsed", "endedAt", s.endsAt)
+		s.mode = domain.ModeNormal
+		s.endsAt = time.Time{}
+	}
+
+	return s.statusLocked()
+}
+
+// SetLowAlert replaces the low alert threshold of normal mode, typically with
+// the alarm level set in the LibreLink app; exercise mode raises it as usual.
+// 0 reverts to the default threshold.
+func (s *ModeServiceImpl) SetLowAlert(mgdl int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if mgdl != s.lowAlertMgDl {
+		s.logger.Info("low alert threshold updated", "lowMgDl", mgdl)
+	}
+	s.lowAlertMgDl = mgdl
+}
+
+// statusLocked returns the current status; s.mu must be held.
+func (s *ModeServiceImpl) statusLocked() *domain.ModeStatus {
+	if s.mode == domain.ModeExercise {
+		endsAt := s.endsAt
+		thresholds := domain.ExerciseAlertThresholds()
+		if s.lowAlertMgDl > 0 {
+			thresholds.LowMgDl = s.lowAlertMgDl + domain.ExerciseLowAlertRaiseMgDl
+		}
+		return &domain.ModeStatus{
+			Mode:       domain.ModeExercise,
+			EndsAt:     &endsAt,
+			Thresholds: thresholds,
+		}
+	}
+
+	thresholds := domain.DefaultAlertThresholds()
+	if s.lowAlertMgDl > 0 {
+		thresholds.LowMgDl = s.lowAlertMgDl
+	}
+	return &domain.ModeStatus{
+		Mode:       domain.ModeNormal,
+		Thresholds: thresholds,
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+func TestModeService_Exercise(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	s := NewModeService(slog.Default())
+	s.now = func() time.Time { return now }
+
+	if status := s.Current(); status.Mode != domain.ModeNormal || status.EndsAt != nil {
+		t.Fatalf("expected normal mode initially, got %+v", status)
+	}
+
+	status := s.StartExercise(time.Hour)
+	if status.Mode != domain.ModeExercise || status.EndsAt == nil || !status.EndsAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected exercise mode until %v, got %+v", now.Add(time.Hour), status)
+	}
+	if status.Thresholds != domain.ExerciseAlertThresholds() {
+		t.Errorf("expected exercise thresholds, got %+v", status.Thresholds)
+	}
+
+	now = now.Add(59 * time.Minute)
+	if status := s.Current(); status.Mode != domain.ModeExercise {
+		t.Errorf("expected exercise mode before the end, got %s", status.Mode)
+	}
+
+	now = now.Add(time.Minute)
+	status = s.Current()
+	if status.Mode != domain.ModeNormal || status.Thresholds != domain.DefaultAlertThresholds() {
+		t.Errorf("expected normal mode once elapsed, got %+v", status)
+	}
+}
+
+func TestModeService_EndExercise(t *testing.T) {
+	s := NewModeService(slog.Default())
+	s.StartExercise(time.Hour)
+
+	if status := s.EndExercise(); status.Mode != domain.ModeNormal {
+		t.Errorf("expected normal mode, got %s", status.Mode)
+	}
+	if status := s.Current(); status.Mode != domain.ModeNormal {
+		t.Errorf("expected normal mode to persist, got %s", status.Mode)
+	}
+}
+
+func TestModeService_SetLowAlert(t *testing.T) {
+	s := NewModeService(slog.Default())
+
+	s.SetLowAlert(80)
+	if status := s.Current(); status.Thresholds.LowMgDl != 80 {
+		t.Errorf("expected low threshold of 80, got %+v", status.Thresholds)
+	}
+	if status := s.StartExercise(time.Hour); status.Thresholds.LowMgDl != 80+domain.ExerciseLowAlertRaiseMgDl {
+		t.Errorf("expected exercise to raise the app threshold, got %+v", status.Thresholds)
+	}
+
+	s.EndExercise()
+	s.SetLowAlert(0)
+	if status := s.Current(); status.Thresholds != domain.DefaultAlertThresholds() {
+		t.Errorf("expected default thresholds, got %+v", status.Thresholds)
+	}
+}
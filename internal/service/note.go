@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// NoteServiceImpl implements NoteService.
+type NoteServiceImpl struct {
+	repo   repository.NoteRepository
+	logger *slog.Logger
+}
+
+// NewNoteService creates a new NoteService.
+func NewNoteService(repo repository.NoteRepository, logger *slog.Logger) *NoteServiceImpl {
+	return &NoteServiceImpl{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// CreateNote stores a new note, its timestamp in UTC and its tags sorted.
+func (s *NoteServiceImpl) CreateNote(ctx context.Context, n *domain.Note) (*domain.Note, error) {
+	normalizeNote(n)
+	if err := s.repo.Create(ctx, n); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("note created", "id", n.ID, "tags", n.Tags)
+
+	return n, nil
+}
+
+// UpdateNote replaces the timestamp, text and tags of a note and returns it
+// as stored, so it keeps its creation time.
+func (s *NoteServiceImpl) UpdateNote(ctx context.Context, n *domain.Note) (*domain.Note, error) {
+	normalizeNote(n)
+	if err := s.repo.Update(ctx, n); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("note updated", "id", n.ID, "tags", n.Tags)
+
+	return s.repo.FindByID(ctx, n.ID)
+}
+
+// GetNote returns a note by its ID.
+func (s *NoteServiceImpl) GetNote(ctx context.Context, id uint) (*domain.Note, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetNotesWithFilters returns filtered and paginated notes with total count.
+func (s *NoteServiceImpl) GetNotesWithFilters(ctx context.Context, filters repository.NoteFilters, limit, offset int) ([]*domain.Note, int64, error) {
+	notes, err := s.repo.FindWithFilters(ctx, filters, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.repo.CountWithFilters(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notes, total, nil
+}
+
+// DeleteNote removes a note.
+func (s *NoteServiceImpl) DeleteNote(ctx context.Context, id uint) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Info("note deleted", "id", id)
+
+	return nil
+}
+
+// normalizeNote stores the timestamp in UTC and the tags sorted, without
+// duplicates, so notes compare and filter the same however they were sent.
+func normalizeNote(n *domain.Note) {
+	n.Timestamp = n.Timestamp.UTC()
+
+	tags := slices.Clone(n.Tags)
+	slices.Sort(tags)
+	n.Tags = slices.Compact(tags)
+	if n.Tags == nil {
+		n.Tags = domain.TagList{}
+	}
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+func TestNormalizeNote(t *testing.T) {
+	zurich := time.FixedZone("CET", 3600)
+	n := &domain.Note{
+		Timestamp: time.Date(2026, 3, 1, 9, 0, 0, 0, zurich),
+		Text:      "Pasta",
+		Tags:      domain.TagList{domain.NoteTagMeal, domain.NoteTagInsulin, domain.NoteTagMeal},
+	}
+
+	normalizeNote(n)
+
+	if n.Timestamp.Location() != time.UTC || n.Timestamp.Hour() != 8 {
+		t.Errorf("expected 08:00 UTC, got %v", n.Timestamp)
+	}
+	if !slices.Equal(n.Tags, domain.TagList{domain.NoteTagInsulin, domain.NoteTagMeal}) {
+		t.Errorf("expected sorted tags without duplicates, got %v", n.Tags)
+	}
+
+	untagged := &domain.Note{Text: "Untagged"}
+	normalizeNote(untagged)
+	if untagged.Tags == nil {
+		t.Error("expected an empty tag list")
+	}
+}
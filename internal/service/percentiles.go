@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// PercentileBucket holds the glucose percentiles (mg/dL) of the readings taken
+// during one time-of-day bucket, over all days of the period.
+type PercentileBucket struct {
+	Time  string  `json:"time"` // HH:MM, local start of the bucket
+	Count int     `json:"count"`
+	P5    float64 `json:"p5"`
+	P25   float64 `json:"p25"`
+	P50   float64 `json:"p50"` // Median
+	P75   float64 `json:"p75"`
+	P95   float64 `json:"p95"`
+}
+
+// Percentiles is the glucose distribution by time of day (the data behind an
+// Ambulatory Glucose Profile). Buckets without readings are omitted.
+type Percentiles struct {
+	BucketMinutes int                 `json:"bucketMinutes"`
+	Buckets       []*PercentileBucket `json:"buckets"`
+}
+
+// GetPercentiles returns the percentiles of the readings of [start, end]
+// grouped by local time of day in buckets of bucket (which must divide a day).
+func (s *GlucoseServiceImpl) GetPercentiles(ctx context.Context, start, end time.Time, bucket time.Duration) (*Percentiles, error) {
+	measurements, err := s.repo.FindByTimeRange(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get measurements: %w", err)
+	}
+	return timeOfDayPercentiles(measurements, bucket), nil
+}
+
+// timeOfDayPercentiles groups measurements by local time-of-day bucket and
+// computes the percentiles of each bucket.
+func timeOfDayPercentiles(measurements []*domain.GlucoseMeasurement, bucket time.Duration) *Percentiles {
+	slots := int(24 * time.Hour / bucket)
+	values := make([][]float64, slots)
+	for _, m := range measurements {
+		local := m.Timestamp.Local()
+		sinceMidnight := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+		slot := int(sinceMidnight / bucket)
+		values[slot] = append(values[slot], float64(m.ValueInMgPerDl))
+	}
+
+	result := &Percentiles{
+		BucketMinutes: int(bucket / time.Minute),
+		Buckets:       []*PercentileBucket{},
+	}
+	for slot, v := range values {
+		if len(v) == 0 {
+			continue
+		}
+		sort.Float64s(v)
+		offset := time.Duration(slot) * bucket
+		result.Buckets = append(result.Buckets, &PercentileBucket{
+			Time:  fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60),
+			Count: len(v),
+			P5:    percentile(v, 5),
+			P25:   percentile(v, 25),
+			P50:   percentile(v, 50),
+			P75:   percentile(v, 75),
+			P95:   percentile(v, 95),
+		})
+	}
+	return result
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating
+// linearly between the closest ranks. Rounded to 0.1 mg/dL.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	value := sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+	return math.Round(value*10) / 10
+}
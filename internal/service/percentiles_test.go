@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+func TestGlucoseService_GetPercentiles(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	var measurements []*domain.GlucoseMeasurement
+	// Five days of readings at 08:10 (100..140) and one reading at 08:40
+	for i := 0; i < 5; i++ {
+		measurements = append(measurements, &domain.GlucoseMeasurement{
+			Timestamp:      day.AddDate(0, 0, i).Add(8*time.Hour + 10*time.Minute),
+			ValueInMgPerDl: 100 + i*10,
+		})
+	}
+	measurements = append(measurements, &domain.GlucoseMeasurement{
+		Timestamp:      day.Add(8*time.Hour + 40*time.Minute),
+		ValueInMgPerDl: 90,
+	})
+
+	mockRepo := &MockGlucoseRepository{
+		FindByTimeRangeFunc: func(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error) {
+			return measurements, nil
+		},
+	}
+	svc := NewGlucoseService(mockRepo, nil, slog.Default(), nil)
+
+	percentiles, err := svc.GetPercentiles(context.Background(), day, day.AddDate(0, 0, 5), 30*time.Minute)
+	if err != nil {
+		t.Fatalf("GetPercentiles: %v", err)
+	}
+	if percentiles.BucketMinutes != 30 {
+		t.Errorf("expected 30 minute buckets, got %d", percentiles.BucketMinutes)
+	}
+	if len(percentiles.Buckets) != 2 {
+		t.Fatalf("expected 2 non-empty buckets, got %d", len(percentiles.Buckets))
+	}
+
+	morning := percentiles.Buckets[0]
+	if morning.Time != "08:00" || morning.Count != 5 {
+		t.Errorf("expected 5 readings at 08:00, got %+v", morning)
+	}
+	if morning.P50 != 120 || morning.P25 != 110 || morning.P5 != 102 || morning.P95 != 138 {
+		t.Errorf("unexpected percentiles: %+v", morning)
+	}
+
+	single := percentiles.Buckets[1]
+	if single.Time != "08:30" || single.P5 != 90 || single.P95 != 90 {
+		t.Errorf("expected a single reading bucket at 08:30, got %+v", single)
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// PrivacyExportVersion is the format version of privacy exports.
+// It is incremented when fields are removed or change meaning.
+const PrivacyExportVersion = 1
+
+// erasureConfirmationTTL is how long an erasure confirmation token stays valid.
+const erasureConfirmationTTL = 5 * time.Minute
+
+// ErrErasureConfirmation is returned when erasure is attempted without a
+// valid, unexpired confirmation token.
+var ErrErasureConfirmation = errors.New("invalid or expired confirmation token")
+
+// PrivacyExport is a complete machine-readable dump of the stored personal data.
+type PrivacyExport struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	*repository.PersonalData
+}
+
+// ErasureConfirmation is the token to pass back to Erase to confirm erasure.
+type ErasureConfirmation struct {
+	Token     string    `json:"confirmationToken"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ErasureResult reports what Erase deleted.
+type ErasureResult struct {
+	ErasedAt time.Time        `json:"erasedAt"`
+	Deleted  map[string]int64 `json:"deleted"` // Rows deleted per kind of data
+}
+
+// PrivacyServiceImpl implements PrivacyService.
+type PrivacyServiceImpl struct {
+	repo   repository.PrivacyRepository
+	uow    repository.UnitOfWork
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu           sync.Mutex
+	confirmation *ErasureConfirmation // Pending erasure confirmation, single use
+}
+
+// NewPrivacyService creates a new PrivacyService.
+func NewPrivacyService(repo repository.PrivacyRepository, uow repository.UnitOfWork, logger *slog.Logger) *PrivacyServiceImpl {
+	return &PrivacyServiceImpl{
+		repo:   repo,
+		uow:    uow,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Export returns all stored personal data.
+func (s *PrivacyServiceImpl) Export(ctx context.Context) (*PrivacyExport, error) {
+	data, err := s.repo.Export(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("personal data exported",
+		"measurements", len(data.Measurements),
+		"sensors", len(data.Sensors),
+		"treatments", len(data.Treatments),
+		"alerts", len(data.Alerts),
+	)
+
+	return &PrivacyExport{
+		Version:      PrivacyExportVersion,
+		ExportedAt:   s.now().UTC(),
+		PersonalData: data,
+	}, nil
+}
+
+// RequestErasure issues a confirmation token for Erase, replacing any pending one.
+// The token expires after 5 minutes and can be used once.
+func (s *PrivacyServiceImpl) RequestErasure() (*ErasureConfirmation, error) {
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	confirmation := &ErasureConfirmation{
+		Token:     hex.EncodeToString(secret),
+		ExpiresAt: s.now().UTC().Add(erasureConfirmationTTL),
+	}
+
+	s.mu.Lock()
+	s.confirmation = confirmation
+	s.mu.Unlock()
+
+	s.logger.Warn("personal data erasure requested", "expiresAt", confirmation.ExpiresAt)
+
+	return confirmation, nil
+}
+
+// Erase deletes all personal data in a single transaction.
+// API tokens and signing keys are kept so the instance remains usable.
+func (s *PrivacyServiceImpl) Erase(ctx context.Context, confirmationToken string) (*ErasureResult, error) {
+	if !s.consumeConfirmation(confirmationToken) {
+		return nil, ErrErasureConfirmation
+	}
+
+	var deleted map[string]int64
+	err := s.uow.ExecuteInTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		deleted, err = s.repo.EraseAll(txCtx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Warn("personal data erased", "deleted", deleted)
+
+	return &ErasureResult{
+		ErasedAt: s.now().UTC(),
+		Deleted:  deleted,
+	}, nil
+}
+
+// consumeConfirmation reports whether token matches the pending confirmation,
+// which is cleared in any case so that a token cannot be guessed repeatedly.
+func (s *PrivacyServiceImpl) consumeConfirmation(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := s.confirmation
+	s.confirmation = nil
+
+	if pending == nil || token == "" || s.now().After(pending.ExpiresAt) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(pending.Token)) == 1
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// MockPrivacyRepository is a mock implementation of PrivacyRepository for testing
+type MockPrivacyRepository struct {
+	ExportFunc   func(ctx context.Context) (*repository.PersonalData, error)
+	EraseAllFunc func(ctx context.Context) (map[string]int64, error)
+}
+
+func (m *MockPrivacyRepository) Export(ctx context.Context) (*repository.PersonalData, error) {
+	if m.ExportFunc != nil {
+		return m.ExportFunc(ctx)
+	}
+	return &repository.PersonalData{}, nil
+}
+
+func (m *MockPrivacyRepository) EraseAll(ctx context.Context) (map[string]int64, error) {
+	if m.EraseAllFunc != nil {
+		return m.EraseAllFunc(ctx)
+	}
+	return map[string]int64{}, nil
+}
+
+func TestPrivacyService_Export(t *testing.T) {
+	mockRepo := &MockPrivacyRepository{
+		ExportFunc: func(ctx context.Context) (*repository.PersonalData, error) {
+			return &repository.PersonalData{
+				Measurements: []*domain.GlucoseMeasurement{{ValueInMgPerDl: 120}},
+				User:         &domain.UserPreferences{FirstName: "Jane"},
+			}, nil
+		},
+	}
+	service := NewPrivacyService(mockRepo, &MockUnitOfWork{}, slog.Default())
+
+	export, err := service.Export(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if export.Version != PrivacyExportVersion {
+		t.Errorf("expected version %d, got %d", PrivacyExportVersion, export.Version)
+	}
+	if len(export.Measurements) != 1 || export.User == nil || export.User.FirstName != "Jane" {
+		t.Errorf("expected exported data, got %+v", export.PersonalData)
+	}
+}
+
+func TestPrivacyService_Erase(t *testing.T) {
+	erased := 0
+	mockRepo := &MockPrivacyRepository{
+		EraseAllFunc: func(ctx context.Context) (map[string]int64, error) {
+			erased++
+			return map[string]int64{"measurements": 42}, nil
+		},
+	}
+	transactions := 0
+	mockUoW := &MockUnitOfWork{
+		ExecuteInTransactionFunc: func(ctx context.Context, fn func(txCtx context.Context) error) error {
+			transactions++
+			return fn(ctx)
+		},
+	}
+	service := NewPrivacyService(mockRepo, mockUoW, slog.Default())
+	ctx := context.Background()
+
+	// Without confirmation
+	if _, err := service.Erase(ctx, "guess"); !errors.Is(err, ErrErasureConfirmation) {
+		t.Fatalf("expected ErrErasureConfirmation, got %v", err)
+	}
+
+	confirmation, err := service.RequestErasure()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := service.Erase(ctx, confirmation.Token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Deleted["measurements"] != 42 {
+		t.Errorf("expected 42 deleted measurements, got %v", result.Deleted)
+	}
+	if erased != 1 || transactions != 1 {
+		t.Errorf("expected one erasure in a transaction, got %d erasures and %d transactions", erased, transactions)
+	}
+
+	// Tokens are single use
+	if _, err := service.Erase(ctx, confirmation.Token); !errors.Is(err, ErrErasureConfirmation) {
+		t.Errorf("expected reused token to be rejected, got %v", err)
+	}
+}
+
+func TestPrivacyService_Erase_InvalidConfirmation(t *testing.T) {
+	mockRepo := &MockPrivacyRepository{
+		EraseAllFunc: func(ctx context.Context) (map[string]int64, error) {
+			t.Fatal("data must not be erased")
+			return nil, nil
+		},
+	}
+	service := NewPrivacyService(mockRepo, &MockUnitOfWork{}, slog.Default())
+	ctx := context.Background()
+
+	// A wrong token discards the pending confirmation
+	confirmation, _ := service.RequestErasure()
+	if _, err := service.Erase(ctx, "wrong"); !errors.Is(err, ErrErasureConfirmation) {
+		t.Fatalf("expected ErrErasureConfirmation, got %v", err)
+	}
+	if _, err := service.Erase(ctx, confirmation.Token); !errors.Is(err, ErrErasureConfirmation) {
+		t.Fatalf("expected discarded token to be rejected, got %v", err)
+	}
+
+	// Expired token
+	now := time.Now()
+	service.now = func() time.Time { return now }
+	confirmation, _ = service.RequestErasure()
+	service.now = func() time.Time { return now.Add(erasureConfirmationTTL + time.Second) }
+	if _, err := service.Erase(ctx, confirmation.Token); !errors.Is(err, ErrErasureConfirmation) {
+		t.Fatalf("expected expired token to be rejected, got %v", err)
+	}
+}
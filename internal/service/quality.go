@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// Data quality thresholds
+const (
+	// readingInterval is the interval between historical readings: a reading
+	// accounts for at most this long when measuring capture.
+	readingInterval = 15 * time.Minute
+	// gapThreshold is the time without readings counted as a gap.
+	gapThreshold = 30 * time.Minute
+	// artifactRateMgDlPerMin is the rate of change between two readings above
+	// which the later reading is flagged as an artifact (glucose does not
+	// physiologically change this fast; typically a compression low or noise).
+	artifactRateMgDlPerMin = 5.0
+	// minConfidenceScore is the quality score below which statistics are
+	// annotated as low-confidence (70% is the usual minimum CGM wear time).
+	minConfidenceScore = 70
+)
+
+// DayQuality describes how complete and reliable the data of one local day is.
+type DayQuality struct {
+	Date           string  `json:"date"` // YYYY-MM-DD, local time
+	Readings       int     `json:"readings"`
+	CapturePercent float64 `json:"capturePercent"` // Share of the day covered by readings
+	Gaps           int     `json:"gaps"`           // Periods of 30 minutes or more without readings
+	Artifacts      int     `json:"artifacts"`      // Readings with an implausible rate of change
+	Score          int     `json:"score"`          // 0-100: capture reduced by the share of artifacts
+	LowConfidence  bool    `json:"lowConfidence"`
+
+	covered, elapsed time.Duration
+}
+
+// QualitySummary aggregates the data quality of several days.
+type QualitySummary struct {
+	CapturePercent    float64 `json:"capturePercent"`
+	Gaps              int     `json:"gaps"`
+	Artifacts         int     `json:"artifacts"`
+	Score             int     `json:"score"`
+	LowConfidence     bool    `json:"lowConfidence"`
+	LowConfidenceDays int     `json:"lowConfidenceDays"`
+}
+
+// GetDailyQuality returns the data quality of each local day in [start, end].
+// Days are clipped to the range and to the current time, so the current day
+// is scored on the hours elapsed so far.
+func (s *GlucoseServiceImpl) GetDailyQuality(ctx context.Context, start, end time.Time) ([]*DayQuality, error) {
+	measurements, err := s.repo.FindByTimeRange(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get measurements: %w", err)
+	}
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	return dailyQuality(measurements, start, end), nil
+}
+
+// dailyQuality splits [start, end] into local days and scores each of them.
+func dailyQuality(measurements []*domain.GlucoseMeasurement, start, end time.Time) []*DayQuality {
+	sort.Slice(measurements, func(i, j int) bool {
+		return measurements[i].Timestamp.Before(measurements[j].Timestamp)
+	})
+
+	days := []*DayQuality{}
+	for dayStart := start; dayStart.Before(end); {
+		local := dayStart.Local()
+		next := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, time.Local)
+		dayEnd := next
+		if end.Before(dayEnd) {
+			dayEnd = end
+		}
+
+		first := sort.Search(len(measurements), func(i int) bool {
+			return !measurements[i].Timestamp.Before(dayStart)
+		})
+		last := sort.Search(len(measurements), func(i int) bool {
+			return !measurements[i].Timestamp.Before(dayEnd)
+		})
+
+		// The reading preceding the day is used to detect a jump at midnight
+		var previous *domain.GlucoseMeasurement
+		if first > 0 {
+			previous = measurements[first-1]
+		}
+		days = append(days, scoreDay(local.Format("2006-01-02"), measurements[first:last], previous, dayStart, dayEnd))
+
+		dayStart = next
+	}
+	return days
+}
+
+// scoreDay computes the quality of the readings of [dayStart, dayEnd).
+// readings must be sorted.
+func scoreDay(date string, readings []*domain.GlucoseMeasurement, previous *domain.GlucoseMeasurement, dayStart, dayEnd time.Time) *DayQuality {
+	day := &DayQuality{
+		Date:     date,
+		Readings: len(readings),
+		elapsed:  dayEnd.Sub(dayStart),
+	}
+
+	// A reading shortly before the day covers its first minutes
+	cursor := dayStart
+	if previous != nil && dayStart.Sub(previous.Timestamp) < gapThreshold {
+		cursor = previous.Timestamp
+		until := dayEnd
+		if len(readings) > 0 {
+			until = readings[0].Timestamp
+		}
+		if carried := previous.Timestamp.Add(readingInterval); carried.After(dayStart) {
+			if until.Before(carried) {
+				carried = until
+			}
+			day.covered += carried.Sub(dayStart)
+		}
+	}
+
+	for i, m := range readings {
+		if m.Timestamp.Sub(cursor) >= gapThreshold {
+			day.Gaps++
+		}
+
+		next := dayEnd
+		if i+1 < len(readings) {
+			next = readings[i+1].Timestamp
+		}
+		day.covered += min(next.Sub(m.Timestamp), readingInterval)
+		cursor = m.Timestamp
+
+		prior := previous
+		if i > 0 {
+			prior = readings[i-1]
+		}
+		if prior != nil && isArtifact(prior, m) {
+			day.Artifacts++
+		}
+	}
+	if dayEnd.Sub(cursor) >= gapThreshold {
+		day.Gaps++
+	}
+
+	if day.elapsed > 0 {
+		day.CapturePercent = round1(float64(day.covered) / float64(day.elapsed) * 100)
+	}
+	day.Score = qualityScore(day.CapturePercent, day.Artifacts, day.Readings)
+	day.LowConfidence = day.Score < minConfidenceScore
+
+	return day
+}
+
+// isArtifact reports whether glucose changed implausibly fast between two readings.
+func isArtifact(prev, m *domain.GlucoseMeasurement) bool {
+	minutes := m.Timestamp.Sub(prev.Timestamp).Minutes()
+	if minutes < 1 || minutes > gapThreshold.Minutes() {
+		return false
+	}
+	delta := float64(m.ValueInMgPerDl - prev.ValueInMgPerDl)
+	if delta < 0 {
+		delta = -delta
+	}
+	return delta/minutes > artifactRateMgDlPerMin
+}
+
+// qualityScore reduces the capture percentage by the share of artifacts.
+func qualityScore(capturePercent float64, artifacts, readings int) int {
+	score := capturePercent
+	if readings > 0 {
+		score *= 1 - float64(artifacts)/float64(readings)
+	}
+	return int(score + 0.5)
+}
+
+// SummarizeQuality aggregates daily quality, weighting days by their duration.
+func SummarizeQuality(days []*DayQuality) *QualitySummary {
+	summary := &QualitySummary{}
+
+	var covered, elapsed time.Duration
+	var readings int
+	for _, day := range days {
+		covered += day.covered
+		elapsed += day.elapsed
+		readings += day.Readings
+		summary.Gaps += day.Gaps
+		summary.Artifacts += day.Artifacts
+		if day.LowConfidence {
+			summary.LowConfidenceDays++
+		}
+	}
+
+	if elapsed > 0 {
+		summary.CapturePercent = round1(float64(covered) / float64(elapsed) * 100)
+	}
+	summary.Score = qualityScore(summary.CapturePercent, summary.Artifacts, readings)
+	summary.LowConfidence = summary.Score < minConfidenceScore
+
+	return summary
+}
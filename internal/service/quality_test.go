@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+func TestGlucoseService_GetDailyQuality(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+
+	// First day: a reading every 15 minutes with a one-reading spike at noon.
+	// Second day: readings stop at noon.
+	var measurements []*domain.GlucoseMeasurement
+	for ts := day; ts.Before(day.Add(36 * time.Hour)); ts = ts.Add(15 * time.Minute) {
+		value := 100
+		if ts.Equal(day.Add(12 * time.Hour)) {
+			value = 300
+		}
+		measurements = append(measurements, &domain.GlucoseMeasurement{Timestamp: ts, ValueInMgPerDl: value})
+	}
+
+	repo := &MockGlucoseRepository{
+		FindByTimeRangeFunc: func(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error) {
+			return measurements, nil
+		},
+	}
+	svc := NewGlucoseService(repo, nil, slog.Default(), nil)
+
+	days, err := svc.GetDailyQuality(context.Background(), day, day.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("GetDailyQuality: %v", err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(days))
+	}
+
+	first, second := days[0], days[1]
+	if first.Date != "2026-03-02" || first.Readings != 96 || first.CapturePercent != 100 || first.Gaps != 0 {
+		t.Errorf("unexpected first day: %+v", first)
+	}
+	// The spike and the return to normal are both implausible jumps
+	if first.Artifacts != 2 || first.Score != 98 || first.LowConfidence {
+		t.Errorf("expected 2 artifacts and a score of 98, got %+v", first)
+	}
+	if second.Readings != 48 || second.CapturePercent != 50 || second.Gaps != 1 || second.Score != 50 || !second.LowConfidence {
+		t.Errorf("expected half a day of data flagged low-confidence, got %+v", second)
+	}
+
+	summary := SummarizeQuality(days)
+	if summary.CapturePercent != 75 || summary.Gaps != 1 || summary.Artifacts != 2 || summary.LowConfidenceDays != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if summary.Score != 74 || summary.LowConfidence {
+		t.Errorf("expected a score of 74, got %+v", summary)
+	}
+}
+
+func TestGlucoseService_GetDailyQuality_NoData(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	svc := NewGlucoseService(&MockGlucoseRepository{}, nil, slog.Default(), nil)
+
+	days, err := svc.GetDailyQuality(context.Background(), day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetDailyQuality: %v", err)
+	}
+	if len(days) != 1 || days[0].CapturePercent != 0 || days[0].Gaps != 1 || !days[0].LowConfidence {
+		t.Errorf("expected an empty low-confidence day, got %+v", days)
+	}
+}
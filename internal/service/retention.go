@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// RetentionRun reports a retention run.
+type RetentionRun struct {
+	Since        time.Time  `json:"since"`                  // Start of the downsampled window, zero for the whole history
+	Until        time.Time  `json:"until"`                  // End of the downsampled window (exclusive)
+	Readings     int        `json:"readings"`               // Measurements downsampled
+	Rollups      int        `json:"rollups"`                // Rollups stored
+	PrunedBefore *time.Time `json:"prunedBefore,omitempty"` // Raw measurements are deleted before this time (nil = kept)
+	Pruned       int64      `json:"pruned"`                 // Raw measurements deleted
+}
+
+// RetentionServiceImpl implements RetentionService.
+type RetentionServiceImpl struct {
+	rollupRepo      repository.GlucoseRollupRepository
+	glucoseRepo     repository.GlucoseRepository
+	uow             repository.UnitOfWork
+	downsampleAfter time.Duration
+	keepRaw         time.Duration
+	logger          *slog.Logger
+	now             func() time.Time
+	mu              sync.Mutex // Serializes the runs of the job and of backfills
+}
+
+// NewRetentionService creates a new RetentionService. Measurements older than
+// downsampleAfter are averaged into rollups; those older than keepRaw (0 =
+// never) are then deleted. keepRaw must not be shorter than downsampleAfter.
+func NewRetentionService(
+	rollupRepo repository.GlucoseRollupRepository,
+	glucoseRepo repository.GlucoseRepository,
+	uow repository.UnitOfWork,
+	downsampleAfter time.Duration,
+	keepRaw time.Duration,
+	logger *slog.Logger,
+) *RetentionServiceImpl {
+	return &RetentionServiceImpl{
+		rollupRepo:      rollupRepo,
+		glucoseRepo:     glucoseRepo,
+		uow:             uow,
+		downsampleAfter: downsampleAfter,
+		keepRaw:         keepRaw,
+		logger:          logger,
+		now:             time.Now,
+	}
+}
+
+// Apply downsamples the measurements older than the downsampling age that
+// are not rolled up yet, then deletes the raw measurements past the retention
+// horizon. The latest rollup is computed again, as it may have been stored
+// before all its measurements were fetched. Measurements stored since the
+// previous run but taken before its latest rollup (backfilled or replicated
+// history) are rolled up from their interval on, before they can be pruned.
+func (s *RetentionServiceImpl) Apply(ctx context.Context) (*RetentionRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	started := s.now().UTC()
+	run := &RetentionRun{
+		Until: started.Add(-s.downsampleAfter).Truncate(domain.GlucoseRollupInterval),
+	}
+
+	latest, err := s.rollupRepo.FindLatest(ctx)
+	if err != nil && !errors.Is(err, persistence.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get the latest rollup: %w", err)
+	}
+	filters := repository.GlucoseFilters{EndTime: &run.Until}
+	var lastStart time.Time
+	if latest != nil {
+		run.Since = latest.StartTime.UTC()
+		lastStart = run.Since
+
+		// The latest rollup is stamped with the start of the previous run
+		late, err := s.glucoseRepo.FindEarliestStoredSince(ctx, latest.CreatedAt)
+		if err != nil && !errors.Is(err, persistence.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get the measurements stored late: %w", err)
+		}
+		if late != nil && late.Timestamp.Before(run.Since) {
+			run.Since = late.Timestamp.UTC().Truncate(domain.GlucoseRollupInterval)
+		}
+		filters.StartTime = &run.Since
+	}
+
+	// Average the measurements per patient and interval, oldest first
+	type accumulator struct {
+		rollup  *domain.GlucoseRollup
+		sum     float64
+		sumMgDl int
+	}
+	var accumulators []*accumulator
+	current := map[string]*accumulator{} // Interval in progress per patient
+
+	err = s.glucoseRepo.StreamWithFilters(ctx, filters, func(m *domain.GlucoseMeasurement) error {
+		start := m.Timestamp.UTC().Truncate(domain.GlucoseRollupInterval)
+		if !start.Before(run.Until) {
+			return nil // The window end is inclusive
+		}
+		run.Readings++
+
+		acc := current[m.PatientID]
+		if acc == nil || !acc.rollup.StartTime.Equal(start) {
+			acc = &accumulator{rollup: &domain.GlucoseRollup{
+				CreatedAt: started,
+				PatientID: m.PatientID,
+				StartTime: start,
+				MinMgDl:   m.ValueInMgPerDl,
+				MaxMgDl:   m.ValueInMgPerDl,
+			}}
+			current[m.PatientID] = acc
+			accumulators = append(accumulators, acc)
+		}
+
+		acc.sum += m.Value
+		acc.sumMgDl += m.ValueInMgPerDl
+		acc.rollup.Count++
+		acc.rollup.MinMgDl = min(acc.rollup.MinMgDl, m.ValueInMgPerDl)
+		acc.rollup.MaxMgDl = max(acc.rollup.MaxMgDl, m.ValueInMgPerDl)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read measurements: %w", err)
+	}
+
+	// Intervals pruned since they were rolled up keep their rollup: the
+	// measurements stored late are added to it
+	type rollupKey struct {
+		patientID string
+		start     int64
+	}
+	stored, err := s.rollupRepo.FindByTimeRange(ctx, run.Since, run.Until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the rollups: %w", err)
+	}
+	previous := make(map[rollupKey]*domain.GlucoseRollup, len(stored))
+	for _, rollup := range stored {
+		previous[rollupKey{rollup.PatientID, rollup.StartTime.Unix()}] = rollup
+	}
+
+	rollups := make([]*domain.GlucoseRollup, len(accumulators))
+	for i, acc := range accumulators {
+		rollup := acc.rollup
+		if prev := previous[rollupKey{rollup.PatientID, rollup.StartTime.Unix()}]; prev != nil && prev.Count > rollup.Count {
+			acc.sum += prev.Value * float64(prev.Count)
+			acc.sumMgDl += prev.ValueInMgPerDl * prev.Count
+			rollup.Count += prev.Count
+			rollup.MinMgDl = min(rollup.MinMgDl, prev.MinMgDl)
+			rollup.MaxMgDl = max(rollup.MaxMgDl, prev.MaxMgDl)
+		}
+
+		rollup.Value = math.Round(acc.sum/float64(rollup.Count)*100) / 100
+		rollup.ValueInMgPerDl = int(math.Round(float64(acc.sumMgDl) / float64(rollup.Count)))
+		rollups[i] = rollup
+		if rollup.StartTime.After(lastStart) {
+			lastStart = rollup.StartTime
+		}
+	}
+	run.Rollups = len(rollups)
+
+	err = s.uow.ExecuteInTransaction(ctx, func(txCtx context.Context) error {
+		return s.rollupRepo.Upsert(txCtx, rollups)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save rollups: %w", err)
+	}
+
+	// Only measurements already rolled up are deleted, except those of the
+	// latest rollup, which the next run computes again. Whole intervals are
+	// deleted, so a rollup with more measurements than stored was pruned.
+	if s.keepRaw > 0 {
+		before := started.Add(-s.keepRaw).Truncate(domain.GlucoseRollupInterval)
+		if before.After(lastStart) {
+			before = lastStart
+		}
+		run.PrunedBefore = &before
+
+		if run.Pruned, err = s.glucoseRepo.DeleteBefore(ctx, before); err != nil {
+			return nil, fmt.Errorf("failed to prune measurements: %w", err)
+		}
+	}
+
+	s.logger.Info("retention applied",
+		"since", run.Since,
+		"until", run.Until,
+		"readings", run.Readings,
+		"rollups", run.Rollups,
+		"pruned", run.Pruned,
+	)
+
+	return run, nil
+}
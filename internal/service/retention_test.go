@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// memoryGlucoseRollupRepository keeps the rollups in memory
+type memoryGlucoseRollupRepository struct {
+	rollups []*domain.GlucoseRollup
+}
+
+func (r *memoryGlucoseRollupRepository) Upsert(ctx context.Context, rollups []*domain.GlucoseRollup) error {
+	for _, rollup := range rollups {
+		replaced := false
+		for i, stored := range r.rollups {
+			if stored.PatientID == rollup.PatientID && stored.StartTime.Equal(rollup.StartTime) {
+				r.rollups[i] = rollup
+				replaced = true
+			}
+		}
+		if !replaced {
+			r.rollups = append(r.rollups, rollup)
+		}
+	}
+	return nil
+}
+
+func (r *memoryGlucoseRollupRepository) FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.GlucoseRollup, error) {
+	var found []*domain.GlucoseRollup
+	for _, rollup := range r.rollups {
+		if !rollup.StartTime.Before(start) && rollup.StartTime.Before(end) {
+			found = append(found, rollup)
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryGlucoseRollupRepository) FindLatest(ctx context.Context) (*domain.GlucoseRollup, error) {
+	var latest *domain.GlucoseRollup
+	for _, rollup := range r.rollups {
+		if latest == nil || rollup.StartTime.After(latest.StartTime) {
+			latest = rollup
+		}
+	}
+	if latest == nil {
+		return nil, persistence.ErrNotFound
+	}
+	return latest, nil
+}
+
+func (r *memoryGlucoseRollupRepository) Count(ctx context.Context) (int64, error) {
+	return int64(len(r.rollups)), nil
+}
+
+func TestRetentionService_Apply(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 7, 0, 0, time.UTC)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	measurements := []*domain.GlucoseMeasurement{
+		{Timestamp: at(10, 0), Value: 5.6, ValueInMgPerDl: 100},
+		{Timestamp: at(10, 2), Value: 11.1, ValueInMgPerDl: 200, PatientID: "other"},
+		{Timestamp: at(10, 5), Value: 6.1, ValueInMgPerDl: 110},
+		{Timestamp: at(10, 10), Value: 6.7, ValueInMgPerDl: 121},
+		{Timestamp: at(10, 20), Value: 5.0, ValueInMgPerDl: 90},
+		{Timestamp: at(12, 0), Value: 7.0, ValueInMgPerDl: 126}, // Not 30 days old yet
+	}
+
+	var prunedBefore time.Time
+	glucoseRepo := &MockGlucoseRepository{
+		StreamWithFiltersFunc: func(ctx context.Context, filters repository.GlucoseFilters, fn func(*domain.GlucoseMeasurement) error) error {
+			for _, m := range measurements {
+				if (filters.StartTime != nil && m.Timestamp.Before(*filters.StartTime)) || (filters.EndTime != nil && m.Timestamp.After(*filters.EndTime)) {
+					continue
+				}
+				if err := fn(m); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		DeleteBeforeFunc: func(ctx context.Context, before time.Time) (int64, error) {
+			prunedBefore = before
+			return 3, nil
+		},
+	}
+	rollupRepo := &memoryGlucoseRollupRepository{}
+
+	service := NewRetentionService(rollupRepo, glucoseRepo, &MockUnitOfWork{}, 30*24*time.Hour, 30*24*time.Hour, slog.Default())
+	service.now = func() time.Time { return now }
+
+	run, err := service.Apply(context.Background())
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !run.Until.Equal(at(12, 0)) || !run.Since.IsZero() {
+		t.Errorf("expected the history until 12:00 to be downsampled, got %v to %v", run.Since, run.Until)
+	}
+	if run.Readings != 5 || run.Rollups != 3 {
+		t.Fatalf("expected 5 readings in 3 rollups, got %d in %d", run.Readings, run.Rollups)
+	}
+
+	first := rollupRepo.rollups[0]
+	if !first.StartTime.Equal(at(10, 0)) || first.Count != 3 || first.ValueInMgPerDl != 110 || first.Value != 6.13 || first.MinMgDl != 100 || first.MaxMgDl != 121 {
+		t.Errorf("unexpected first rollup: %+v", first)
+	}
+	if other := rollupRepo.rollups[1]; other.PatientID != "other" || other.Count != 1 || other.ValueInMgPerDl != 200 {
+		t.Errorf("expected a rollup of the other patient, got %+v", other)
+	}
+
+	// The latest rollup is kept raw to be computed again
+	if !prunedBefore.Equal(at(10, 15)) || run.PrunedBefore == nil || run.Pruned != 3 {
+		t.Errorf("expected the measurements before 10:15 to be pruned, got %v (%d)", prunedBefore, run.Pruned)
+	}
+
+	// The next run starts from the latest rollup
+	run, err = service.Apply(context.Background())
+	if err != nil {
+		t.Fatalf("second Apply failed: %v", err)
+	}
+	if !run.Since.Equal(at(10, 15)) || run.Readings != 1 || len(rollupRepo.rollups) != 3 {
+		t.Errorf("expected the latest rollup to be computed again, got since %v, %d readings, %d rollups", run.Since, run.Readings, len(rollupRepo.rollups))
+	}
+}
+
+func TestRetentionService_Apply_KeepRaw(t *testing.T) {
+	deleted := false
+	glucoseRepo := &MockGlucoseRepository{
+		DeleteBeforeFunc: func(ctx context.Context, before time.Time) (int64, error) {
+			deleted = true
+			return 0, nil
+		},
+	}
+
+	service := NewRetentionService(&memoryGlucoseRollupRepository{}, glucoseRepo, &MockUnitOfWork{}, 90*24*time.Hour, 0, slog.Default())
+	run, err := service.Apply(context.Background())
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if deleted || run.PrunedBefore != nil {
+		t.Error("expected raw measurements to be kept")
+	}
+}
+
+func TestRetentionService_Apply_LateMeasurements(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 7, 0, 0, time.UTC)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	stored := now.Add(-24 * time.Hour)
+	measurements := []*domain.GlucoseMeasurement{
+		{CreatedAt: stored, Timestamp: at(10, 0), Value: 5.6, ValueInMgPerDl: 100},
+		{CreatedAt: stored, Timestamp: at(10, 5), Value: 6.1, ValueInMgPerDl: 110},
+		{CreatedAt: stored, Timestamp: at(10, 10), Value: 6.7, ValueInMgPerDl: 121},
+		{CreatedAt: stored, Timestamp: at(10, 20), Value: 5.0, ValueInMgPerDl: 90},
+		{CreatedAt: stored, Timestamp: at(11, 0), Value: 7.0, ValueInMgPerDl: 126},
+	}
+
+	glucoseRepo := &MockGlucoseRepository{
+		StreamWithFiltersFunc: func(ctx context.Context, filters repository.GlucoseFilters, fn func(*domain.GlucoseMeasurement) error) error {
+			for _, m := range measurements {
+				if (filters.StartTime != nil && m.Timestamp.Before(*filters.StartTime)) || (filters.EndTime != nil && m.Timestamp.After(*filters.EndTime)) {
+					continue
+				}
+				if err := fn(m); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		DeleteBeforeFunc: func(ctx context.Context, before time.Time) (int64, error) {
+			var kept []*domain.GlucoseMeasurement
+			for _, m := range measurements {
+				if !m.Timestamp.Before(before) {
+					kept = append(kept, m)
+				}
+			}
+			deleted := int64(len(measurements) - len(kept))
+			measurements = kept
+			return deleted, nil
+		},
+		FindEarliestStoredSinceFunc: func(ctx context.Context, since time.Time) (*domain.GlucoseMeasurement, error) {
+			var earliest *domain.GlucoseMeasurement
+			for _, m := range measurements {
+				if !m.CreatedAt.Before(since) && (earliest == nil || m.Timestamp.Before(earliest.Timestamp)) {
+					earliest = m
+				}
+			}
+			if earliest == nil {
+				return nil, persistence.ErrNotFound
+			}
+			return earliest, nil
+		},
+	}
+	rollupRepo := &memoryGlucoseRollupRepository{}
+
+	service := NewRetentionService(rollupRepo, glucoseRepo, &MockUnitOfWork{}, 30*24*time.Hour, 30*24*time.Hour, slog.Default())
+	service.now = func() time.Time { return now }
+
+	if _, err := service.Apply(context.Background()); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(rollupRepo.rollups) != 3 || len(measurements) != 1 {
+		t.Fatalf("expected 3 rollups and the 11:00 reading kept, got %d and %d", len(rollupRepo.rollups), len(measurements))
+	}
+
+	// Backfilled readings older than the latest rollup: one in an interval
+	// without rollup, one in an interval already rolled up and pruned
+	backfilled := now.Add(30 * time.Minute)
+	measurements = append(measurements,
+		&domain.GlucoseMeasurement{CreatedAt: backfilled, Timestamp: at(9, 40), Value: 4.4, ValueInMgPerDl: 80},
+		&domain.GlucoseMeasurement{CreatedAt: backfilled, Timestamp: at(10, 12), Value: 8.0, ValueInMgPerDl: 144},
+	)
+
+	service.now = func() time.Time { return now.Add(time.Hour) }
+	run, err := service.Apply(context.Background())
+	if err != nil {
+		t.Fatalf("second Apply failed: %v", err)
+	}
+	if !run.Since.Equal(at(9, 30)) || run.Readings != 3 {
+		t.Errorf("expected the readings since 09:30 to be rolled up again, got since %v, %d readings", run.Since, run.Readings)
+	}
+
+	rollups := map[time.Time]*domain.GlucoseRollup{}
+	for _, rollup := range rollupRepo.rollups {
+		rollups[rollup.StartTime] = rollup
+	}
+	if len(rollups) != 4 {
+		t.Fatalf("expected 4 rollups, got %d", len(rollups))
+	}
+	if late := rollups[at(9, 30)]; late == nil || late.Count != 1 || late.ValueInMgPerDl != 80 {
+		t.Errorf("expected a rollup of the backfilled 09:40 reading, got %+v", late)
+	}
+	if merged := rollups[at(10, 0)]; merged.Count != 4 || merged.ValueInMgPerDl != 119 || merged.Value != 6.6 || merged.MinMgDl != 100 || merged.MaxMgDl != 144 {
+		t.Errorf("expected the 10:12 reading added to the 10:00 rollup, got %+v", merged)
+	}
+	if kept := rollups[at(10, 15)]; kept.Count != 1 || kept.ValueInMgPerDl != 90 {
+		t.Errorf("expected the 10:15 rollup to be kept, got %+v", kept)
+	}
+	if run.Pruned != 2 || len(measurements) != 1 {
+		t.Errorf("expected the backfilled readings to be pruned once rolled up, got %d pruned", run.Pruned)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"time"
 
+	"golang.org/x/sync/singleflight"
+
 	"github.com/R4yL-dev/glcmd/internal/domain"
 	"github.com/R4yL-dev/glcmd/internal/events"
 	"github.com/R4yL-dev/glcmd/internal/persistence"
@@ -16,35 +18,49 @@ import (
 // SensorServiceImpl implements SensorService.
 type SensorServiceImpl struct {
 	repo        repository.SensorRepository
+	glucoseRepo repository.GlucoseRepository
 	uow         repository.UnitOfWork
+	gracePeriod time.Duration
 	logger      *slog.Logger
 	eventBroker *events.Broker
+	reads       singleflight.Group // Coalesces concurrent identical reads
 }
 
 // NewSensorService creates a new SensorService.
+// gracePeriod is how long an expired sensor may keep reporting (see domain.DefaultSensorGracePeriod).
 // eventBroker is optional and can be nil (for tests or when SSE is not needed).
 func NewSensorService(
 	repo repository.SensorRepository,
+	glucoseRepo repository.GlucoseRepository,
 	uow repository.UnitOfWork,
+	gracePeriod time.Duration,
 	logger *slog.Logger,
 	eventBroker *events.Broker,
 ) *SensorServiceImpl {
 	return &SensorServiceImpl{
 		repo:        repo,
-		uow:        uow,
+		glucoseRepo: glucoseRepo,
+		uow:         uow,
+		gracePeriod: gracePeriod,
 		logger:      logger,
 		eventBroker: eventBroker,
 	}
 }
 
+// GracePeriod returns how long an expired sensor may keep reporting before it is considered ended.
+func (s *SensorServiceImpl) GracePeriod() time.Duration {
+	return s.gracePeriod
+}
+
 // SaveSensor saves a sensor configuration.
 func (s *SensorServiceImpl) SaveSensor(ctx context.Context, sensor *domain.SensorConfig) error {
 	return s.repo.Save(ctx, sensor)
 }
 
 // GetCurrentSensor returns the current sensor (not ended).
+// Concurrent calls for the same patient share one query.
 func (s *SensorServiceImpl) GetCurrentSensor(ctx context.Context) (*domain.SensorConfig, error) {
-	return s.repo.FindCurrent(ctx)
+	return coalesce(ctx, &s.reads, "current", s.repo.FindCurrent)
 }
 
 // GetAllSensors returns all sensors.
@@ -117,6 +133,13 @@ func (s *SensorServiceImpl) HandleSensorChange(ctx context.Context, newSensor *d
 				"expiresAt", newSensor.ExpiresAt,
 				"durationDays", newSensor.DurationDays,
 			)
+
+			// The application site is not reported by LibreView: remind to record it
+			if currentSensor != nil && currentSensor.ApplicationSite != "" {
+				s.logger.Info("record the new sensor application site (glcli sensor site)",
+					"previousSite", currentSensor.ApplicationSite,
+				)
+			}
 		}
 
 		return nil
@@ -129,8 +152,9 @@ func (s *SensorServiceImpl) HandleSensorChange(ctx context.Context, newSensor *d
 	// Publish event after transaction commits successfully
 	if s.eventBroker != nil && isNewSensor {
 		s.eventBroker.Publish(events.Event{
-			Type: events.EventTypeSensor,
-			Data: newSensor,
+			Type:      events.EventTypeSensor,
+			Data:      newSensor,
+			PatientID: newSensor.PatientID,
 		})
 	}
 
@@ -139,13 +163,40 @@ func (s *SensorServiceImpl) HandleSensorChange(ctx context.Context, newSensor *d
 
 // SensorStats contains aggregated sensor lifecycle statistics
 type SensorStats struct {
-	TotalSensors  int     `json:"totalSensors"`
-	CompletedSensors  int     `json:"completedSensors"`
-	AvgDuration   float64 `json:"avgDuration"`   // days
-	MinDuration   float64 `json:"minDuration"`
-	MaxDuration   float64 `json:"maxDuration"`
-	AvgExpected   float64 `json:"avgExpected"`
-	AvgDifference float64 `json:"avgDifference"` // avg_duration - avg_expected
+	TotalSensors     int              `json:"totalSensors"`
+	CompletedSensors int              `json:"completedSensors"`
+	AvgDuration      float64          `json:"avgDuration"` // days
+	MinDuration      float64          `json:"minDuration"`
+	MaxDuration      float64          `json:"maxDuration"`
+	AvgExpected      float64          `json:"avgExpected"`
+	AvgDifference    float64          `json:"avgDifference"` // avg_duration - avg_expected
+	RatedSensors     int              `json:"ratedSensors"`
+	AvgRating        *float64         `json:"avgRating,omitempty"` // Average user rating (1-5) of the rated sensors
+	Sensors          []*SensorCapture `json:"sensors"`             // Capture rate of each sensor, newest first
+}
+
+// Sensor capture thresholds
+const (
+	// historicalInterval is the interval between the historical readings a sensor records.
+	historicalInterval = 15 * time.Minute
+	// poorCaptureRate is the capture rate (%) below which a sensor is reported
+	// as having poor connectivity.
+	poorCaptureRate = 70
+	// maxCaptureSensors bounds the number of sensors whose capture is computed.
+	maxCaptureSensors = 50
+)
+
+// SensorCapture compares the readings of a sensor with those expected over its wear time.
+type SensorCapture struct {
+	SerialNumber     string    `json:"serialNumber"`
+	Activation       time.Time `json:"activation"`
+	WearDays         float64   `json:"wearDays"`
+	Readings         int64     `json:"readings"`         // Historical readings recorded while worn
+	ExpectedReadings int64     `json:"expectedReadings"` // One every 15 minutes
+	CaptureRate      float64   `json:"captureRate"`      // Percent, capped at 100
+	PoorConnectivity bool      `json:"poorConnectivity"` // Capture rate below 70%
+	Note             string    `json:"note,omitempty"`
+	Rating           *int      `json:"rating,omitempty"`
 }
 
 // GetSensorsWithFilters returns filtered and paginated sensors with total count.
@@ -163,30 +214,134 @@ func (s *SensorServiceImpl) GetSensorsWithFilters(ctx context.Context, filters r
 	return sensors, total, nil
 }
 
-// GetStatistics returns aggregated sensor lifecycle statistics.
+// GetStatistics returns aggregated sensor lifecycle statistics of the sensors
+// activated between start and end.
 func (s *SensorServiceImpl) GetStatistics(ctx context.Context, start, end *time.Time) (*SensorStats, error) {
-	filters := repository.SensorStatisticsFilters{
+	return s.GetStatisticsWithFilters(ctx, repository.SensorStatisticsFilters{
 		StartTime: start,
 		EndTime:   end,
-	}
+	})
+}
+
+// GetStatisticsWithFilters returns aggregated sensor lifecycle statistics of
+// the sensors matching filters. Concurrent calls with the same filters share
+// one query.
+func (s *SensorServiceImpl) GetStatisticsWithFilters(ctx context.Context, filters repository.SensorStatisticsFilters) (*SensorStats, error) {
+	key := coalesceKey("stats", filters.StartTime, filters.EndTime, filters.EndedStart, filters.EndedEnd)
+
+	return coalesce(ctx, &s.reads, key, func(ctx context.Context) (*SensorStats, error) {
+		return s.statistics(ctx, filters)
+	})
+}
+
+// statistics computes the statistics of GetStatisticsWithFilters.
+func (s *SensorServiceImpl) statistics(ctx context.Context, filters repository.SensorStatisticsFilters) (*SensorStats, error) {
 	result, err := s.repo.GetStatistics(ctx, filters)
 	if err != nil {
 		return nil, err
 	}
 
 	stats := &SensorStats{
-		TotalSensors:  int(result.TotalSensors),
-		CompletedSensors:  int(result.CompletedSensors),
-		AvgDuration:   result.AvgDuration,
-		MinDuration:   result.MinDuration,
-		MaxDuration:   result.MaxDuration,
-		AvgExpected:   result.AvgExpected,
-		AvgDifference: result.AvgDuration - result.AvgExpected,
+		TotalSensors:     int(result.TotalSensors),
+		CompletedSensors: int(result.CompletedSensors),
+		AvgDuration:      result.AvgDuration,
+		MinDuration:      result.MinDuration,
+		MaxDuration:      result.MaxDuration,
+		AvgExpected:      result.AvgExpected,
+		AvgDifference:    result.AvgDuration - result.AvgExpected,
+		RatedSensors:     int(result.RatedSensors),
+	}
+	if result.AvgRating != nil {
+		avg := round1(*result.AvgRating)
+		stats.AvgRating = &avg
+	}
+
+	stats.Sensors, err = s.sensorCaptures(ctx, repository.SensorFilters(filters))
+	if err != nil {
+		return nil, err
 	}
 
 	return stats, nil
 }
 
+// sensorCaptures returns the capture rate of the sensors matching filters.
+// Measurements are not linked to a sensor: the readings of a sensor are those
+// recorded between its activation and its end (replacement or expiry plus grace).
+func (s *SensorServiceImpl) sensorCaptures(ctx context.Context, filters repository.SensorFilters) ([]*SensorCapture, error) {
+	sensors, err := s.repo.FindWithFilters(ctx, filters, maxCaptureSensors, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sensors: %w", err)
+	}
+
+	historical := domain.GlucoseTypeHistorical
+	now := time.Now()
+	captures := make([]*SensorCapture, 0, len(sensors))
+	for _, sensor := range sensors {
+		wearEnd := now
+		if sensor.EndedAt != nil {
+			wearEnd = *sensor.EndedAt
+		}
+		if limit := sensor.ExpiresAt.Add(s.gracePeriod); wearEnd.After(limit) {
+			wearEnd = limit
+		}
+		if !wearEnd.After(sensor.Activation) {
+			continue
+		}
+
+		readings, err := s.glucoseRepo.CountWithFilters(ctx, repository.GlucoseFilters{
+			StartTime: &sensor.Activation,
+			EndTime:   &wearEnd,
+			Type:      &historical,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count readings of sensor %s: %w", sensor.SerialNumber, err)
+		}
+
+		wear := wearEnd.Sub(sensor.Activation)
+		capture := &SensorCapture{
+			SerialNumber:     sensor.SerialNumber,
+			Activation:       sensor.Activation,
+			WearDays:         round1(wear.Hours() / 24),
+			Readings:         readings,
+			ExpectedReadings: int64(wear / historicalInterval),
+			Note:             sensor.Note,
+			Rating:           sensor.Rating,
+		}
+		if capture.ExpectedReadings > 0 {
+			capture.CaptureRate = round1(min(100, float64(readings)/float64(capture.ExpectedReadings)*100))
+		}
+		capture.PoorConnectivity = capture.ExpectedReadings > 0 && capture.CaptureRate < poorCaptureRate
+		captures = append(captures, capture)
+	}
+
+	return captures, nil
+}
+
+// AssignPatient assigns the measurements and sensors stored without a patient
+// (before multi-patient support) to patientID, in a single transaction.
+func (s *SensorServiceImpl) AssignPatient(ctx context.Context, patientID string) error {
+	var measurements, sensors int64
+
+	err := s.uow.ExecuteInTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if measurements, err = s.glucoseRepo.AssignPatient(txCtx, patientID); err != nil {
+			return fmt.Errorf("failed to assign measurements: %w", err)
+		}
+		if sensors, err = s.repo.AssignPatient(txCtx, patientID); err != nil {
+			return fmt.Errorf("failed to assign sensors: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if measurements > 0 || sensors > 0 {
+		s.logger.Info("assigned stored data to patient", "measurements", measurements, "sensors", sensors)
+	}
+	return nil
+}
+
 // UpdateLastMeasurementIfNewer updates the LastMeasurementAt field of the current sensor
 // only if the provided timestamp is newer than the existing one.
 // This handles historical measurements that may arrive out of order.
@@ -205,3 +360,138 @@ func (s *SensorServiceImpl) UpdateLastMeasurementIfNewer(ctx context.Context, ti
 
 	return nil // Nothing to do, the existing timestamp is more recent
 }
+
+// SiteAssignment is the result of recording a sensor application site.
+type SiteAssignment struct {
+	Sensor       *domain.SensorConfig
+	PreviousSite string // Site of the previous sensor (empty = unknown or no previous sensor)
+	Reused       bool   // The previous sensor was applied to the same site
+}
+
+// SiteUse is one entry of the application site history.
+type SiteUse struct {
+	SerialNumber string    `json:"serialNumber"`
+	Activation   time.Time `json:"activation"`
+	Site         string    `json:"site,omitempty"` // Empty when not recorded
+	Reused       bool      `json:"reused"`         // Same site as the sensor before it
+}
+
+// SetApplicationSite records the body site of the current sensor.
+// Reusing the site of the previous sensor is allowed but reported (and logged)
+// so users can rotate sites for skin health.
+func (s *SensorServiceImpl) SetApplicationSite(ctx context.Context, site string) (*SiteAssignment, error) {
+	var assignment SiteAssignment
+
+	err := s.uow.ExecuteInTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.repo.FindCurrent(txCtx)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.SetApplicationSite(txCtx, current.SerialNumber, site); err != nil {
+			return fmt.Errorf("failed to set application site: %w", err)
+		}
+		current.ApplicationSite = site
+		assignment.Sensor = current
+
+		previous, err := s.repo.FindPrevious(txCtx, current.Activation)
+		if err != nil && !errors.Is(err, persistence.ErrNotFound) {
+			return fmt.Errorf("failed to find previous sensor: %w", err)
+		}
+		if previous != nil {
+			assignment.PreviousSite = previous.ApplicationSite
+			assignment.Reused = previous.ApplicationSite == site
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.Reused {
+		s.logger.Warn("sensor applied to the same site as the previous one",
+			"serialNumber", assignment.Sensor.SerialNumber,
+			"site", site,
+		)
+	} else {
+		s.logger.Info("sensor application site recorded",
+			"serialNumber", assignment.Sensor.SerialNumber,
+			"site", site,
+			"previousSite", assignment.PreviousSite,
+		)
+	}
+
+	return &assignment, nil
+}
+
+// GetSiteHistory returns the application sites of the last limit sensors, newest first.
+func (s *SensorServiceImpl) GetSiteHistory(ctx context.Context, limit int) ([]*SiteUse, error) {
+	// One extra sensor tells whether the oldest returned one reused its site
+	sensors, err := s.repo.FindWithFilters(ctx, repository.SensorFilters{}, limit+1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*SiteUse, 0, min(len(sensors), limit))
+	for i := 0; i < len(sensors) && i < limit; i++ {
+		use := &SiteUse{
+			SerialNumber: sensors[i].SerialNumber,
+			Activation:   sensors[i].Activation,
+			Site:         sensors[i].ApplicationSite,
+		}
+		if i+1 < len(sensors) && use.Site != "" {
+			use.Reused = sensors[i+1].ApplicationSite == use.Site
+		}
+		history = append(history, use)
+	}
+
+	return history, nil
+}
+
+// SensorFeedback is an update of the note and rating of a sensor.
+type SensorFeedback struct {
+	Note   *string // nil = unchanged, empty = cleared
+	Rating *int    // nil = unchanged, 0 = cleared
+}
+
+// UpdateFeedback updates the note and rating of a sensor.
+// Values are validated by the caller; fields left nil keep their current value.
+func (s *SensorServiceImpl) UpdateFeedback(ctx context.Context, serial string, feedback SensorFeedback) (*domain.SensorConfig, error) {
+	var sensor *domain.SensorConfig
+
+	err := s.uow.ExecuteInTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		sensor, err = s.repo.FindBySerialNumber(txCtx, serial)
+		if err != nil {
+			return err
+		}
+
+		if feedback.Note != nil {
+			sensor.Note = *feedback.Note
+		}
+		if feedback.Rating != nil {
+			sensor.Rating = nil
+			if *feedback.Rating != 0 {
+				rating := *feedback.Rating
+				sensor.Rating = &rating
+			}
+		}
+
+		if err := s.repo.SetFeedback(txCtx, serial, sensor.Note, sensor.Rating); err != nil {
+			return fmt.Errorf("failed to set sensor feedback: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("sensor feedback recorded",
+		"serialNumber", serial,
+		"rated", sensor.Rating != nil,
+		"hasNote", sensor.Note != "",
+	)
+
+	return sensor, nil
+}
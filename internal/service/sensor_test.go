@@ -23,6 +23,17 @@ type MockSensorRepository struct {
 	FindWithFiltersFunc    func(ctx context.Context, filters repository.SensorFilters, limit, offset int) ([]*domain.SensorConfig, error)
 	CountWithFiltersFunc   func(ctx context.Context, filters repository.SensorFilters) (int64, error)
 	GetStatisticsFunc      func(ctx context.Context, filters repository.SensorStatisticsFilters) (*repository.SensorStatisticsResult, error)
+	SetApplicationSiteFunc func(ctx context.Context, serial string, site string) error
+	FindPreviousFunc       func(ctx context.Context, activation time.Time) (*domain.SensorConfig, error)
+	SetFeedbackFunc        func(ctx context.Context, serial string, note string, rating *int) error
+	AssignPatientFunc      func(ctx context.Context, patientID string) (int64, error)
+}
+
+func (m *MockSensorRepository) AssignPatient(ctx context.Context, patientID string) (int64, error) {
+	if m.AssignPatientFunc != nil {
+		return m.AssignPatientFunc(ctx, patientID)
+	}
+	return 0, nil
 }
 
 func (m *MockSensorRepository) FindCurrent(ctx context.Context) (*domain.SensorConfig, error) {
@@ -81,6 +92,27 @@ func (m *MockSensorRepository) GetStatistics(ctx context.Context, filters reposi
 	return &repository.SensorStatisticsResult{}, nil
 }
 
+func (m *MockSensorRepository) SetApplicationSite(ctx context.Context, serial string, site string) error {
+	if m.SetApplicationSiteFunc != nil {
+		return m.SetApplicationSiteFunc(ctx, serial, site)
+	}
+	return nil
+}
+
+func (m *MockSensorRepository) FindPrevious(ctx context.Context, activation time.Time) (*domain.SensorConfig, error) {
+	if m.FindPreviousFunc != nil {
+		return m.FindPreviousFunc(ctx, activation)
+	}
+	return nil, persistence.ErrNotFound
+}
+
+func (m *MockSensorRepository) SetFeedback(ctx context.Context, serial string, note string, rating *int) error {
+	if m.SetFeedbackFunc != nil {
+		return m.SetFeedbackFunc(ctx, serial, note, rating)
+	}
+	return nil
+}
+
 type MockUnitOfWork struct {
 	ExecuteInTransactionFunc func(ctx context.Context, fn func(txCtx context.Context) error) error
 }
@@ -111,7 +143,7 @@ func TestSensorService_HandleSensorChange_FirstSensor(t *testing.T) {
 
 	mockUoW := &MockUnitOfWork{}
 
-	service := NewSensorService(mockRepo, mockUoW, slog.Default(), nil)
+	service := NewSensorService(mockRepo, &MockGlucoseRepository{}, mockUoW, domain.DefaultSensorGracePeriod, slog.Default(), nil)
 
 	now := time.Now().UTC()
 	newSensor := &domain.SensorConfig{
@@ -164,7 +196,7 @@ func TestSensorService_HandleSensorChange_SensorChanged(t *testing.T) {
 
 	mockUoW := &MockUnitOfWork{}
 
-	service := NewSensorService(mockRepo, mockUoW, slog.Default(), nil)
+	service := NewSensorService(mockRepo, &MockGlucoseRepository{}, mockUoW, domain.DefaultSensorGracePeriod, slog.Default(), nil)
 
 	newSensor := &domain.SensorConfig{
 		SerialNumber: "NEW_SENSOR",
@@ -217,7 +249,7 @@ func TestSensorService_HandleSensorChange_SameSensor(t *testing.T) {
 
 	mockUoW := &MockUnitOfWork{}
 
-	service := NewSensorService(mockRepo, mockUoW, slog.Default(), nil)
+	service := NewSensorService(mockRepo, &MockGlucoseRepository{}, mockUoW, domain.DefaultSensorGracePeriod, slog.Default(), nil)
 
 	sameSensor := &domain.SensorConfig{
 		SerialNumber: "SAME_SENSOR", // Same serial number
@@ -276,7 +308,7 @@ func TestSensorService_HandleSensorChange_TransactionRollback(t *testing.T) {
 		},
 	}
 
-	service := NewSensorService(mockRepo, mockUoW, slog.Default(), nil)
+	service := NewSensorService(mockRepo, &MockGlucoseRepository{}, mockUoW, domain.DefaultSensorGracePeriod, slog.Default(), nil)
 
 	newSensor := &domain.SensorConfig{
 		SerialNumber: "NEW_SENSOR",
@@ -295,3 +327,199 @@ func TestSensorService_HandleSensorChange_TransactionRollback(t *testing.T) {
 		t.Error("expected transaction to be executed")
 	}
 }
+
+func TestSensorService_SetApplicationSite(t *testing.T) {
+	now := time.Now().UTC()
+	current := &domain.SensorConfig{SerialNumber: "CURRENT", Activation: now.AddDate(0, 0, -1)}
+
+	tests := []struct {
+		name         string
+		previous     *domain.SensorConfig
+		site         string
+		wantPrevious string
+		wantReused   bool
+	}{
+		{"first sensor", nil, domain.SensorSiteLeftArm, "", false},
+		{"rotated", &domain.SensorConfig{SerialNumber: "PREVIOUS", ApplicationSite: domain.SensorSiteRightArm}, domain.SensorSiteLeftArm, domain.SensorSiteRightArm, false},
+		{"reused", &domain.SensorConfig{SerialNumber: "PREVIOUS", ApplicationSite: domain.SensorSiteLeftArm}, domain.SensorSiteLeftArm, domain.SensorSiteLeftArm, true},
+		{"previous not recorded", &domain.SensorConfig{SerialNumber: "PREVIOUS"}, domain.SensorSiteLeftArm, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var savedSerial, savedSite string
+			mockRepo := &MockSensorRepository{
+				FindCurrentFunc: func(ctx context.Context) (*domain.SensorConfig, error) {
+					sensor := *current
+					return &sensor, nil
+				},
+				SetApplicationSiteFunc: func(ctx context.Context, serial string, site string) error {
+					savedSerial, savedSite = serial, site
+					return nil
+				},
+				FindPreviousFunc: func(ctx context.Context, activation time.Time) (*domain.SensorConfig, error) {
+					if tt.previous == nil {
+						return nil, persistence.ErrNotFound
+					}
+					return tt.previous, nil
+				},
+			}
+
+			service := NewSensorService(mockRepo, &MockGlucoseRepository{}, &MockUnitOfWork{}, domain.DefaultSensorGracePeriod, slog.Default(), nil)
+
+			assignment, err := service.SetApplicationSite(context.Background(), tt.site)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if savedSerial != "CURRENT" || savedSite != tt.site {
+				t.Errorf("expected site %s saved on CURRENT, got %q on %q", tt.site, savedSite, savedSerial)
+			}
+			if assignment.Sensor.ApplicationSite != tt.site {
+				t.Errorf("expected sensor site %s, got %q", tt.site, assignment.Sensor.ApplicationSite)
+			}
+			if assignment.PreviousSite != tt.wantPrevious || assignment.Reused != tt.wantReused {
+				t.Errorf("expected previous %q reused %v, got %q %v",
+					tt.wantPrevious, tt.wantReused, assignment.PreviousSite, assignment.Reused)
+			}
+		})
+	}
+}
+
+func TestSensorService_SetApplicationSite_NoCurrentSensor(t *testing.T) {
+	service := NewSensorService(&MockSensorRepository{}, &MockGlucoseRepository{}, &MockUnitOfWork{}, domain.DefaultSensorGracePeriod, slog.Default(), nil)
+
+	_, err := service.SetApplicationSite(context.Background(), domain.SensorSiteLeftArm)
+	if !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSensorService_GetSiteHistory(t *testing.T) {
+	sensors := []*domain.SensorConfig{
+		{SerialNumber: "S4", ApplicationSite: domain.SensorSiteLeftArm},
+		{SerialNumber: "S3", ApplicationSite: domain.SensorSiteLeftArm},
+		{SerialNumber: "S2"},
+		{SerialNumber: "S1", ApplicationSite: domain.SensorSiteRightArm},
+	}
+
+	mockRepo := &MockSensorRepository{
+		FindWithFiltersFunc: func(ctx context.Context, filters repository.SensorFilters, limit, offset int) ([]*domain.SensorConfig, error) {
+			return sensors[:min(limit, len(sensors))], nil
+		},
+	}
+
+	service := NewSensorService(mockRepo, &MockGlucoseRepository{}, &MockUnitOfWork{}, domain.DefaultSensorGracePeriod, slog.Default(), nil)
+
+	history, err := service.GetSiteHistory(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(history))
+	}
+	if !history[0].Reused {
+		t.Error("expected S4 to reuse the site of S3")
+	}
+	if history[1].Reused {
+		t.Error("expected S3 not to reuse a site (S2 not recorded)")
+	}
+}
+
+func TestSensorService_GetStatistics_CaptureRate(t *testing.T) {
+	activation := time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC)
+	ended := activation.Add(10 * 24 * time.Hour)
+	expired := activation.Add(-15 * 24 * time.Hour)
+
+	mockRepo := &MockSensorRepository{
+		FindWithFiltersFunc: func(ctx context.Context, filters repository.SensorFilters, limit, offset int) ([]*domain.SensorConfig, error) {
+			return []*domain.SensorConfig{
+				// Replaced after 10 days
+				{SerialNumber: "POOR", Activation: activation, ExpiresAt: activation.Add(15 * 24 * time.Hour), EndedAt: &ended},
+				// Never replaced: wear time ends at expiry plus grace period
+				{SerialNumber: "GOOD", Activation: expired, ExpiresAt: expired.Add(15 * 24 * time.Hour)},
+			}, nil
+		},
+	}
+	glucoseRepo := &MockGlucoseRepository{
+		CountWithFiltersFunc: func(ctx context.Context, filters repository.GlucoseFilters) (int64, error) {
+			if filters.Type == nil || *filters.Type != domain.GlucoseTypeHistorical {
+				t.Errorf("expected only historical readings to be counted")
+			}
+			if filters.StartTime.Equal(activation) {
+				if !filters.EndTime.Equal(ended) {
+					t.Errorf("expected wear time to end on replacement, got %v", filters.EndTime)
+				}
+				return 480, nil // Half of 10 days
+			}
+			return 2000, nil // More than expected (duplicates): capped at 100%
+		},
+	}
+	service := NewSensorService(mockRepo, glucoseRepo, &MockUnitOfWork{}, domain.DefaultSensorGracePeriod, slog.Default(), nil)
+
+	stats, err := service.GetStatistics(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if len(stats.Sensors) != 2 {
+		t.Fatalf("expected 2 sensors, got %d", len(stats.Sensors))
+	}
+
+	poor := stats.Sensors[0]
+	if poor.WearDays != 10 || poor.ExpectedReadings != 960 || poor.CaptureRate != 50 || !poor.PoorConnectivity {
+		t.Errorf("expected 50%% capture with poor connectivity, got %+v", poor)
+	}
+	good := stats.Sensors[1]
+	if good.WearDays != 15.5 || good.CaptureRate != 100 || good.PoorConnectivity {
+		t.Errorf("expected full capture over 15.5 days, got %+v", good)
+	}
+}
+
+func TestSensorService_UpdateFeedback(t *testing.T) {
+	rating := 4
+	stored := &domain.SensorConfig{SerialNumber: "S1", Note: "Good adhesion", Rating: &rating}
+
+	var savedNote string
+	var savedRating *int
+	mockRepo := &MockSensorRepository{
+		FindBySerialNumberFunc: func(ctx context.Context, serial string) (*domain.SensorConfig, error) {
+			if serial != "S1" {
+				return nil, persistence.ErrNotFound
+			}
+			sensor := *stored
+			return &sensor, nil
+		},
+		SetFeedbackFunc: func(ctx context.Context, serial string, note string, rating *int) error {
+			savedNote, savedRating = note, rating
+			return nil
+		},
+	}
+
+	service := NewSensorService(mockRepo, &MockGlucoseRepository{}, &MockUnitOfWork{}, domain.DefaultSensorGracePeriod, slog.Default(), nil)
+
+	// Only the rating changes, the note is kept
+	newRating := 2
+	sensor, err := service.UpdateFeedback(context.Background(), "S1", SensorFeedback{Rating: &newRating})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if savedNote != "Good adhesion" || savedRating == nil || *savedRating != 2 {
+		t.Errorf("expected note kept and rating 2, got %q %v", savedNote, savedRating)
+	}
+	if sensor.Rating == nil || *sensor.Rating != 2 {
+		t.Errorf("expected returned rating 2, got %v", sensor.Rating)
+	}
+
+	// A zero rating clears it
+	clear := 0
+	note := "Inaccurate the first day"
+	if _, err := service.UpdateFeedback(context.Background(), "S1", SensorFeedback{Note: &note, Rating: &clear}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if savedNote != note || savedRating != nil {
+		t.Errorf("expected new note and cleared rating, got %q %v", savedNote, savedRating)
+	}
+
+	if _, err := service.UpdateFeedback(context.Background(), "UNKNOWN", SensorFeedback{Note: &note}); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
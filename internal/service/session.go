@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// sessionKeyIterations is the PBKDF2 cost of the session key. Sessions are
+// only sealed at login and opened at startup.
+const sessionKeyIterations = 100_000
+
+// sessionExpiryMargin is how long before its expiry a stored session is no
+// longer reused, so it does not expire right after a restart.
+const sessionExpiryMargin = time.Hour
+
+// SessionServiceImpl implements SessionService.
+type SessionServiceImpl struct {
+	sessionRepo repository.UpstreamSessionRepository
+	now         func() time.Time
+}
+
+// NewSessionService creates a new SessionService.
+func NewSessionService(sessionRepo repository.UpstreamSessionRepository) *SessionServiceImpl {
+	return &SessionServiceImpl{
+		sessionRepo: sessionRepo,
+		now:         time.Now,
+	}
+}
+
+// Load returns the stored session of an account. A session that expires
+// within the hour, or was sealed with other credentials, is not returned.
+func (s *SessionServiceImpl) Load(ctx context.Context, account, email, password string) (*domain.UpstreamCredentials, error) {
+	session, err := s.sessionRepo.Find(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	if session.ExpiresAt != nil && !s.now().Add(sessionExpiryMargin).Before(*session.ExpiresAt) {
+		return nil, persistence.ErrNotFound
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(session.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session salt: %w", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(session.Sealed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sealed session: %w", err)
+	}
+
+	aead, err := sessionCipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("invalid sealed session: too short")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], sessionAAD(account, email))
+	if err != nil {
+		// Sealed with other credentials
+		return nil, persistence.ErrNotFound
+	}
+
+	var creds domain.UpstreamCredentials
+	if err := json.Unmarshal(plaintext, &creds); err != nil {
+		return nil, fmt.Errorf("invalid sealed session: %w", err)
+	}
+	if session.ExpiresAt != nil {
+		creds.ExpiresAt = *session.ExpiresAt
+	}
+	return &creds, nil
+}
+
+// Save seals the session of an account with a key derived from its password
+// and stores it, replacing the previous one.
+func (s *SessionServiceImpl) Save(ctx context.Context, account, email, password string, creds *domain.UpstreamCredentials) error {
+	plaintext, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("generating salt: %w", err)
+	}
+	aead, err := sessionCipher(password, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+
+	session := &domain.UpstreamSession{
+		UpdatedAt: s.now().UTC(),
+		Account:   account,
+		Salt:      base64.StdEncoding.EncodeToString(salt),
+		Sealed:    base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, sessionAAD(account, email))),
+	}
+	if !creds.ExpiresAt.IsZero() {
+		expiresAt := creds.ExpiresAt.UTC()
+		session.ExpiresAt = &expiresAt
+	}
+
+	return s.sessionRepo.Save(ctx, session)
+}
+
+// Forget deletes the stored session of an account, e.g. once LibreView
+// rejected its token.
+func (s *SessionServiceImpl) Forget(ctx context.Context, account string) error {
+	return s.sessionRepo.Delete(ctx, account)
+}
+
+// sessionCipher returns the AES-256-GCM cipher of the sessions sealed with
+// password and salt.
+func sessionCipher(password string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, password, salt, sessionKeyIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("deriving session key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sessionAAD binds a sealed session to its account and email, so it is not
+// reused after the email changes.
+func sessionAAD(account, email string) []byte {
+	return []byte(account + "\x00" + strings.ToLower(email))
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// memoryUpstreamSessionRepository stores sessions by account.
+type memoryUpstreamSessionRepository struct {
+	sessions map[string]*domain.UpstreamSession
+}
+
+func (r *memoryUpstreamSessionRepository) Save(ctx context.Context, s *domain.UpstreamSession) error {
+	r.sessions[s.Account] = s
+	return nil
+}
+
+func (r *memoryUpstreamSessionRepository) Find(ctx context.Context, account string) (*domain.UpstreamSession, error) {
+	s, ok := r.sessions[account]
+	if !ok {
+		return nil, persistence.ErrNotFound
+	}
+	return s, nil
+}
+
+func (r *memoryUpstreamSessionRepository) Delete(ctx context.Context, account string) error {
+	delete(r.sessions, account)
+	return nil
+}
+
+func TestSessionService_SaveLoad(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	repo := &memoryUpstreamSessionRepository{sessions: map[string]*domain.UpstreamSession{}}
+	svc := NewSessionService(repo)
+	svc.now = func() time.Time { return now }
+
+	creds := &domain.UpstreamCredentials{Token: "secret-token", AccountID: "account", PatientID: "patient", Region: "eu2", ExpiresAt: now.Add(180 * 24 * time.Hour)}
+	if err := svc.Save(ctx, "primary", "me@example.com", "password", creds); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if stored := repo.sessions["primary"]; strings.Contains(stored.Sealed, "secret") || stored.ExpiresAt == nil {
+		t.Errorf("expected a sealed session with its expiry, got %+v", stored)
+	}
+
+	loaded, err := svc.Load(ctx, "primary", "Me@example.com", "password")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Token != creds.Token || loaded.AccountID != creds.AccountID || loaded.PatientID != creds.PatientID || loaded.Region != creds.Region || !loaded.ExpiresAt.Equal(creds.ExpiresAt) {
+		t.Errorf("expected the saved session, got %+v", loaded)
+	}
+
+	// Other credentials or account cannot open it
+	for _, tt := range []struct{ account, email, password string }{
+		{"primary", "me@example.com", "new password"},
+		{"primary", "other@example.com", "password"},
+		{"secondary", "me@example.com", "password"},
+	} {
+		if _, err := svc.Load(ctx, tt.account, tt.email, tt.password); !errors.Is(err, persistence.ErrNotFound) {
+			t.Errorf("expected ErrNotFound for %+v, got %v", tt, err)
+		}
+	}
+
+	if err := svc.Forget(ctx, "primary"); err != nil {
+		t.Fatalf("Forget failed: %v", err)
+	}
+	if _, err := svc.Load(ctx, "primary", "me@example.com", "password"); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected the session to be forgotten, got %v", err)
+	}
+}
+
+func TestSessionService_Load_Expiring(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	svc := NewSessionService(&memoryUpstreamSessionRepository{sessions: map[string]*domain.UpstreamSession{}})
+	svc.now = func() time.Time { return now }
+
+	creds := &domain.UpstreamCredentials{Token: "token", AccountID: "account", ExpiresAt: now.Add(30 * time.Minute)}
+	if err := svc.Save(ctx, "primary", "me@example.com", "password", creds); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Load(ctx, "primary", "me@example.com", "password"); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("expected a session expiring within the hour not to be reused, got %v", err)
+	}
+
+	// Without a known expiry, LibreView decides on the first request
+	creds.ExpiresAt = time.Time{}
+	if err := svc.Save(ctx, "primary", "me@example.com", "password", creds); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Load(ctx, "primary", "me@example.com", "password"); err != nil {
+		t.Errorf("expected a session without expiry to be reused, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
+)
+
+// SigningServiceImpl implements SigningService.
+// The current key is cached in memory since every pushed event is signed.
+type SigningServiceImpl struct {
+	keyRepo repository.SigningKeyRepository
+	logger  *slog.Logger
+	now     func() time.Time
+
+	mu      sync.Mutex
+	current *domain.SigningKey // nil = no key
+	loaded  bool               // current reflects the database
+}
+
+// NewSigningService creates a new SigningService.
+func NewSigningService(keyRepo repository.SigningKeyRepository, logger *slog.Logger) *SigningServiceImpl {
+	return &SigningServiceImpl{
+		keyRepo: keyRepo,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// CreateKey generates a new key, which immediately becomes the signing key.
+// Previous keys stay listed until retired so consumers can rotate at their pace.
+func (s *SigningServiceImpl) CreateKey(ctx context.Context) (*domain.SigningKey, string, error) {
+	keyID := make([]byte, 4)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(keyID); err != nil {
+		return nil, "", fmt.Errorf("generating key id: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("generating key: %w", err)
+	}
+
+	key := &domain.SigningKey{
+		CreatedAt: s.now().UTC(),
+		KeyID:     "k_" + hex.EncodeToString(keyID),
+		Secret:    hex.EncodeToString(secret),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.keyRepo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	s.current, s.loaded = key, true
+
+	s.logger.Info("signing key created", "id", key.ID, "keyId", key.KeyID)
+	return key, key.Secret, nil
+}
+
+// ListKeys returns all keys, newest first. Secrets are not serialized.
+func (s *SigningServiceImpl) ListKeys(ctx context.Context) ([]*domain.SigningKey, error) {
+	return s.keyRepo.FindAll(ctx)
+}
+
+// RetireKey stops using a key. If it was the signing key, the newest remaining
+// key takes over; without one, payloads are no longer signed.
+func (s *SigningServiceImpl) RetireKey(ctx context.Context, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.keyRepo.Retire(ctx, id, s.now().UTC()); err != nil {
+		return err
+	}
+	s.loaded = false
+
+	s.logger.Info("signing key retired", "id", id)
+	return nil
+}
+
+// Sign returns the signature value of a payload with the current key,
+// or "" when no signing key exists.
+func (s *SigningServiceImpl) Sign(ctx context.Context, eventType string, payload []byte) (string, error) {
+	key, err := s.currentKey(ctx)
+	if err != nil || key == nil {
+		return "", err
+	}
+	return glclient.Sign(key.Secret, key.KeyID, s.now(), eventType, payload), nil
+}
+
+// currentKey returns the cached signing key, loading it on first use.
+func (s *SigningServiceImpl) currentKey(ctx context.Context) (*domain.SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.loaded {
+		return s.current, nil
+	}
+
+	key, err := s.keyRepo.FindCurrent(ctx)
+	if err != nil && !errors.Is(err, persistence.ErrNotFound) {
+		return nil, err
+	}
+	s.current, s.loaded = key, true
+	return key, nil
+}
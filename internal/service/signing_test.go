@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
+)
+
+// MockSigningKeyRepository is an in-memory SigningKeyRepository for testing
+type MockSigningKeyRepository struct {
+	keys []*domain.SigningKey
+}
+
+func (m *MockSigningKeyRepository) Create(ctx context.Context, k *domain.SigningKey) error {
+	k.ID = uint(len(m.keys) + 1)
+	m.keys = append(m.keys, k)
+	return nil
+}
+
+func (m *MockSigningKeyRepository) FindAll(ctx context.Context) ([]*domain.SigningKey, error) {
+	return m.keys, nil
+}
+
+func (m *MockSigningKeyRepository) FindCurrent(ctx context.Context) (*domain.SigningKey, error) {
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		if m.keys[i].RetiredAt == nil {
+			return m.keys[i], nil
+		}
+	}
+	return nil, persistence.ErrNotFound
+}
+
+func (m *MockSigningKeyRepository) Retire(ctx context.Context, id uint, at time.Time) error {
+	for _, k := range m.keys {
+		if k.ID == id && k.RetiredAt == nil {
+			k.RetiredAt = &at
+			return nil
+		}
+	}
+	return persistence.ErrNotFound
+}
+
+func TestSigningService_NoKey(t *testing.T) {
+	svc := NewSigningService(&MockSigningKeyRepository{}, slog.Default())
+
+	sig, err := svc.Sign(context.Background(), "glucose", []byte("{}"))
+	if err != nil || sig != "" {
+		t.Errorf("expected no signature without key, got %q (%v)", sig, err)
+	}
+}
+
+func TestSigningService_Rotation(t *testing.T) {
+	svc := NewSigningService(&MockSigningKeyRepository{}, slog.Default())
+	ctx := context.Background()
+	payload := []byte(`{"valueInMgPerDl":110}`)
+
+	oldKey, oldSecret, err := svc.CreateKey(ctx)
+	if err != nil {
+		t.Fatalf("CreateKey: %v", err)
+	}
+	newKey, newSecret, _ := svc.CreateKey(ctx)
+	secrets := map[string]string{oldKey.KeyID: oldSecret, newKey.KeyID: newSecret}
+
+	// Newest key signs
+	sig, _ := svc.Sign(ctx, "glucose", payload)
+	parsed, err := glclient.ParseSignature(sig)
+	if err != nil {
+		t.Fatalf("ParseSignature: %v", err)
+	}
+	if parsed.KeyID != newKey.KeyID {
+		t.Errorf("expected signature by %s, got %s", newKey.KeyID, parsed.KeyID)
+	}
+	if err := glclient.Verify(sig, secrets, "glucose", payload, time.Minute); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+
+	// Retiring the new key falls back to the previous one
+	if err := svc.RetireKey(ctx, newKey.ID); err != nil {
+		t.Fatalf("RetireKey: %v", err)
+	}
+	sig, _ = svc.Sign(ctx, "glucose", payload)
+	if parsed, _ := glclient.ParseSignature(sig); parsed == nil || parsed.KeyID != oldKey.KeyID {
+		t.Errorf("expected signature by %s after retiring %s, got %q", oldKey.KeyID, newKey.KeyID, sig)
+	}
+
+	// Retiring every key disables signing
+	svc.RetireKey(ctx, oldKey.ID)
+	if sig, _ := svc.Sign(ctx, "glucose", payload); sig != "" {
+		t.Errorf("expected no signature, got %q", sig)
+	}
+}
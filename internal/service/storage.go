@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// storageSampleWindow is the recent history the growth rates are measured on.
+const storageSampleWindow = 7 * 24 * time.Hour
+
+// storageHorizons are the projected years.
+var storageHorizons = []int{1, 5}
+
+// StorageForecast estimates the growth of the database at the current cadence.
+type StorageForecast struct {
+	DatabaseBytes      int64               `json:"databaseBytes"`
+	Measurements       int64               `json:"measurements"`
+	Rollups            int64               `json:"rollups"`
+	BytesPerRow        float64             `json:"bytesPerRow"` // Database size per measurement or rollup, indexes and other tables included
+	SampleDays         float64             `json:"sampleDays"`  // Recent history the rates are measured on
+	MeasurementsPerDay float64             `json:"measurementsPerDay"`
+	RollupsPerDay      float64             `json:"rollupsPerDay"` // 0 without downsampling
+	BytesPerDay        float64             `json:"bytesPerDay"`   // Growth before raw measurements are pruned
+	DownsampleDays     int                 `json:"downsampleDays"`
+	RawDays            int                 `json:"rawDays"`
+	Projections        []StorageProjection `json:"projections"`
+}
+
+// StorageProjection is the projected size of the database after some years.
+type StorageProjection struct {
+	Years        int   `json:"years"`
+	Measurements int64 `json:"measurements"`
+	Rollups      int64 `json:"rollups"`
+	Bytes        int64 `json:"bytes"`
+}
+
+// StorageServiceImpl implements StorageService.
+type StorageServiceImpl struct {
+	glucoseRepo     repository.GlucoseRepository
+	rollupRepo      repository.GlucoseRollupRepository
+	databaseSize    func(ctx context.Context) (int64, error)
+	downsampleAfter time.Duration
+	keepRaw         time.Duration
+	now             func() time.Time
+}
+
+// NewStorageService creates a new StorageService. databaseSize returns the
+// size of the database in bytes. downsampleAfter and keepRaw are the retention
+// policy (0 = disabled, see NewRetentionService).
+func NewStorageService(
+	glucoseRepo repository.GlucoseRepository,
+	rollupRepo repository.GlucoseRollupRepository,
+	databaseSize func(ctx context.Context) (int64, error),
+	downsampleAfter time.Duration,
+	keepRaw time.Duration,
+) *StorageServiceImpl {
+	return &StorageServiceImpl{
+		glucoseRepo:     glucoseRepo,
+		rollupRepo:      rollupRepo,
+		databaseSize:    databaseSize,
+		downsampleAfter: downsampleAfter,
+		keepRaw:         keepRaw,
+		now:             time.Now,
+	}
+}
+
+// Forecast measures the measurements stored per day over the last week, and
+// the 15-minute intervals they fill (the rollups of the retention job), then
+// projects the rows and size of the database. Raw measurements are capped at
+// those of the retention horizon; rollups are never deleted.
+func (s *StorageServiceImpl) Forecast(ctx context.Context) (*StorageForecast, error) {
+	now := s.now().UTC()
+	since := now.Add(-storageSampleWindow)
+
+	type interval struct {
+		patientID string
+		start     time.Time
+	}
+	intervals := map[interval]struct{}{}
+	var readings int
+	var first time.Time
+	err := s.glucoseRepo.StreamWithFilters(ctx, repository.GlucoseFilters{StartTime: &since, EndTime: &now}, func(m *domain.GlucoseMeasurement) error {
+		if readings == 0 {
+			first = m.Timestamp
+		}
+		readings++
+		intervals[interval{m.PatientID, m.Timestamp.UTC().Truncate(domain.GlucoseRollupInterval)}] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recent measurements: %w", err)
+	}
+
+	forecast := &StorageForecast{
+		DownsampleDays: int(s.downsampleAfter / (24 * time.Hour)),
+		RawDays:        int(s.keepRaw / (24 * time.Hour)),
+	}
+	if forecast.Measurements, err = s.glucoseRepo.CountWithFilters(ctx, repository.GlucoseFilters{}); err != nil {
+		return nil, fmt.Errorf("failed to count measurements: %w", err)
+	}
+	if forecast.Rollups, err = s.rollupRepo.Count(ctx); err != nil {
+		return nil, fmt.Errorf("failed to count rollups: %w", err)
+	}
+	if forecast.DatabaseBytes, err = s.databaseSize(ctx); err != nil {
+		return nil, err
+	}
+
+	var bytesPerRow, measurementsPerDay, rollupsPerDay float64
+	if rows := forecast.Measurements + forecast.Rollups; rows > 0 {
+		bytesPerRow = float64(forecast.DatabaseBytes) / float64(rows)
+	}
+	if readings > 0 {
+		// A new installation is measured on its history, at least an hour
+		sample := max(now.Sub(first), time.Hour).Hours() / 24
+		forecast.SampleDays = round1(sample)
+		measurementsPerDay = float64(readings) / sample
+		if s.downsampleAfter > 0 {
+			rollupsPerDay = float64(len(intervals)) / sample
+		}
+	}
+	forecast.BytesPerRow = round1(bytesPerRow)
+	forecast.MeasurementsPerDay = round1(measurementsPerDay)
+	forecast.RollupsPerDay = round1(rollupsPerDay)
+	forecast.BytesPerDay = round1((measurementsPerDay + rollupsPerDay) * bytesPerRow)
+
+	for _, years := range storageHorizons {
+		days := float64(365 * years)
+		measurements := float64(forecast.Measurements) + measurementsPerDay*days
+		if s.keepRaw > 0 {
+			measurements = min(measurements, measurementsPerDay*s.keepRaw.Hours()/24)
+		}
+		rollups := float64(forecast.Rollups) + rollupsPerDay*days
+
+		forecast.Projections = append(forecast.Projections, StorageProjection{
+			Years:        years,
+			Measurements: int64(math.Round(measurements)),
+			Rollups:      int64(math.Round(rollups)),
+			Bytes:        int64(math.Round((measurements + rollups) * bytesPerRow)),
+		})
+	}
+
+	return forecast, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+func TestStorageService_Forecast(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	// A reading every 5 minutes over the last day: 3 per rollup
+	glucoseRepo := &MockGlucoseRepository{
+		StreamWithFiltersFunc: func(ctx context.Context, filters repository.GlucoseFilters, fn func(*domain.GlucoseMeasurement) error) error {
+			for i := 288; i >= 1; i-- {
+				if err := fn(&domain.GlucoseMeasurement{Timestamp: now.Add(-time.Duration(i) * 5 * time.Minute)}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		CountWithFiltersFunc: func(ctx context.Context, filters repository.GlucoseFilters) (int64, error) {
+			return 10000, nil
+		},
+	}
+	rollupRepo := &memoryGlucoseRollupRepository{rollups: make([]*domain.GlucoseRollup, 500)}
+	databaseSize := func(ctx context.Context) (int64, error) { return 10500 * 100, nil }
+
+	service := NewStorageService(glucoseRepo, rollupRepo, databaseSize, 30*24*time.Hour, 90*24*time.Hour)
+	service.now = func() time.Time { return now }
+
+	forecast, err := service.Forecast(context.Background())
+	if err != nil {
+		t.Fatalf("Forecast failed: %v", err)
+	}
+	if forecast.SampleDays != 1 || forecast.MeasurementsPerDay != 288 || forecast.RollupsPerDay != 96 {
+		t.Errorf("expected 288 readings and 96 rollups per day, got %+v", forecast)
+	}
+	if forecast.BytesPerRow != 100 || forecast.BytesPerDay != 38400 {
+		t.Errorf("expected 100 bytes per row and 38400 per day, got %v and %v", forecast.BytesPerRow, forecast.BytesPerDay)
+	}
+	if forecast.DownsampleDays != 30 || forecast.RawDays != 90 {
+		t.Errorf("expected the retention policy, got %d and %d days", forecast.DownsampleDays, forecast.RawDays)
+	}
+
+	// Raw measurements are capped at 90 days, rollups keep growing
+	year := forecast.Projections[0]
+	if year.Years != 1 || year.Measurements != 288*90 || year.Rollups != 500+96*365 || year.Bytes != (288*90+500+96*365)*100 {
+		t.Errorf("unexpected one-year projection: %+v", year)
+	}
+	if fiveYears := forecast.Projections[1]; fiveYears.Measurements != 288*90 || fiveYears.Rollups != 500+96*365*5 {
+		t.Errorf("unexpected five-year projection: %+v", fiveYears)
+	}
+}
+
+func TestStorageService_Forecast_Empty(t *testing.T) {
+	databaseSize := func(ctx context.Context) (int64, error) { return 4096, nil }
+	service := NewStorageService(&MockGlucoseRepository{}, &memoryGlucoseRollupRepository{}, databaseSize, 0, 0)
+
+	forecast, err := service.Forecast(context.Background())
+	if err != nil {
+		t.Fatalf("Forecast failed: %v", err)
+	}
+	if forecast.MeasurementsPerDay != 0 || forecast.BytesPerRow != 0 || forecast.Projections[1].Bytes != 0 {
+		t.Errorf("expected no growth without measurements, got %+v", forecast)
+	}
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// SyncHashAlgorithm identifies the hash function used for sync checksums.
+const SyncHashAlgorithm = "sha256"
+
+// syncDayLayout is the date format used for manifest day keys (UTC).
+const syncDayLayout = "2006-01-02"
+
+// SyncManifest describes the content of a date range as per-day checksums.
+// Two instances holding the same data produce identical manifests, so a
+// consumer only needs to fetch the days whose hashes differ.
+type SyncManifest struct {
+	Algorithm string         `json:"algorithm"`
+	Start     time.Time      `json:"start"`
+	End       time.Time      `json:"end"`
+	Days      []*SyncDayHash `json:"days"`
+}
+
+// SyncDayHash contains the checksums of a single UTC day.
+// Days without any glucose measurement or sensor activation are omitted.
+type SyncDayHash struct {
+	Date         string `json:"date"`
+	Hash         string `json:"hash"`
+	GlucoseCount int    `json:"glucoseCount"`
+	GlucoseHash  string `json:"glucoseHash"`
+	SensorCount  int    `json:"sensorCount"`
+	SensorHash   string `json:"sensorHash"`
+}
+
+// SyncExport contains the full content of a single UTC day.
+// Hashing Measurements and Sensors with HashMeasurements and HashSensors yields
+// the glucoseHash and sensorHash of the matching manifest entry.
+type SyncExport struct {
+	Date         string                       `json:"date"`
+	Measurements []*domain.GlucoseMeasurement `json:"measurements"`
+	Sensors      []*domain.SensorConfig       `json:"sensors"`
+}
+
+// SyncServiceImpl implements SyncService.
+type SyncServiceImpl struct {
+	glucoseRepo repository.GlucoseRepository
+	sensorRepo  repository.SensorRepository
+	logger      *slog.Logger
+}
+
+// NewSyncService creates a new SyncService.
+func NewSyncService(
+	glucoseRepo repository.GlucoseRepository,
+	sensorRepo repository.SensorRepository,
+	logger *slog.Logger,
+) *SyncServiceImpl {
+	return &SyncServiceImpl{
+		glucoseRepo: glucoseRepo,
+		sensorRepo:  sensorRepo,
+		logger:      logger,
+	}
+}
+
+// GetManifest computes per-day checksums for the UTC days covering [start, end].
+// The range is widened to full days so that each hash always covers a complete day.
+func (s *SyncServiceImpl) GetManifest(ctx context.Context, start, end time.Time) (*SyncManifest, error) {
+	dayStart := syncDayStart(start)
+	dayEnd := syncDayStart(end).Add(24*time.Hour - time.Nanosecond)
+
+	measurements, err := s.glucoseRepo.FindByTimeRange(ctx, dayStart, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	sensors, err := s.sensorRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	glucoseByDay := make(map[string][]*domain.GlucoseMeasurement)
+	for _, m := range measurements {
+		day := m.Timestamp.UTC().Format(syncDayLayout)
+		glucoseByDay[day] = append(glucoseByDay[day], m)
+	}
+
+	sensorsByDay := make(map[string][]*domain.SensorConfig)
+	for _, sensor := range sensorsActivatedBetween(sensors, dayStart, dayEnd) {
+		day := sensor.Activation.UTC().Format(syncDayLayout)
+		sensorsByDay[day] = append(sensorsByDay[day], sensor)
+	}
+
+	manifest := &SyncManifest{
+		Algorithm: SyncHashAlgorithm,
+		Start:     dayStart,
+		End:       dayEnd,
+		Days:      make([]*SyncDayHash, 0),
+	}
+
+	for day := dayStart; !day.After(dayEnd); day = day.Add(24 * time.Hour) {
+		key := day.Format(syncDayLayout)
+		dayMeasurements := glucoseByDay[key]
+		daySensors := sensorsByDay[key]
+		if len(dayMeasurements) == 0 && len(daySensors) == 0 {
+			continue
+		}
+
+		glucoseHash := HashMeasurements(dayMeasurements)
+		sensorHash := HashSensors(daySensors)
+
+		combined := sha256.New()
+		fmt.Fprintf(combined, "%s\n%s\n%s\n", key, glucoseHash, sensorHash)
+
+		manifest.Days = append(manifest.Days, &SyncDayHash{
+			Date:         key,
+			Hash:         hex.EncodeToString(combined.Sum(nil)),
+			GlucoseCount: len(dayMeasurements),
+			GlucoseHash:  glucoseHash,
+			SensorCount:  len(daySensors),
+			SensorHash:   sensorHash,
+		})
+	}
+
+	s.logger.Debug("sync manifest computed",
+		"start", dayStart,
+		"end", dayEnd,
+		"days", len(manifest.Days),
+		"measurements", len(measurements),
+	)
+
+	return manifest, nil
+}
+
+// ExportDay returns all measurements and sensors of the UTC day containing date.
+// Measurements are ordered by patient, then factory timestamp (oldest first).
+func (s *SyncServiceImpl) ExportDay(ctx context.Context, date time.Time) (*SyncExport, error) {
+	dayStart := syncDayStart(date)
+	dayEnd := dayStart.Add(24*time.Hour - time.Nanosecond)
+
+	measurements, err := s.glucoseRepo.FindByTimeRange(ctx, dayStart, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+	sortCanonicalMeasurements(measurements)
+
+	sensors, err := s.sensorRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyncExport{
+		Date:         dayStart.Format(syncDayLayout),
+		Measurements: measurements,
+		Sensors:      sensorsActivatedBetween(sensors, dayStart, dayEnd),
+	}, nil
+}
+
+// sensorsActivatedBetween returns the sensors whose activation falls within [start, end].
+func sensorsActivatedBetween(sensors []*domain.SensorConfig, start, end time.Time) []*domain.SensorConfig {
+	result := make([]*domain.SensorConfig, 0)
+	for _, sensor := range sensors {
+		if sensor.Activation.Before(start) || sensor.Activation.After(end) {
+			continue
+		}
+		result = append(result, sensor)
+	}
+	return result
+}
+
+// HashMeasurements returns the hex-encoded SHA-256 of the canonical form of the
+// given measurements. The input order does not matter: measurements are sorted
+// by patient and factory timestamp before hashing. Database-only fields (ID,
+// CreatedAt) are excluded so that two instances with the same readings agree.
+func HashMeasurements(measurements []*domain.GlucoseMeasurement) string {
+	sorted := make([]*domain.GlucoseMeasurement, len(measurements))
+	copy(sorted, measurements)
+	sortCanonicalMeasurements(sorted)
+
+	h := sha256.New()
+	for _, m := range sorted {
+		writeCanonicalMeasurement(h, m)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashSensors returns the hex-encoded SHA-256 of the canonical form of the
+// given sensors, sorted by serial number.
+func HashSensors(sensors []*domain.SensorConfig) string {
+	sorted := make([]*domain.SensorConfig, len(sensors))
+	copy(sorted, sensors)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].SerialNumber < sorted[j].SerialNumber
+	})
+
+	h := sha256.New()
+	for _, sensor := range sorted {
+		writeCanonicalSensor(h, sensor)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// sortCanonicalMeasurements sorts measurements by patient, then factory
+// timestamp, the unique key of a reading.
+func sortCanonicalMeasurements(measurements []*domain.GlucoseMeasurement) {
+	sort.SliceStable(measurements, func(i, j int) bool {
+		if measurements[i].PatientID != measurements[j].PatientID {
+			return measurements[i].PatientID < measurements[j].PatientID
+		}
+		return measurements[i].FactoryTimestamp.Before(measurements[j].FactoryTimestamp)
+	})
+}
+
+// writeCanonicalMeasurement writes one measurement as a single pipe-separated line.
+func writeCanonicalMeasurement(h hash.Hash, m *domain.GlucoseMeasurement) {
+	trendArrow := ""
+	if m.TrendArrow != nil {
+		trendArrow = fmt.Sprintf("%d", *m.TrendArrow)
+	}
+	fmt.Fprintf(h, "%s|%s|%s|%.2f|%d|%s|%d|%d|%t|%t|%d\n",
+		m.PatientID,
+		canonicalTime(m.FactoryTimestamp),
+		canonicalTime(m.Timestamp),
+		m.Value,
+		m.ValueInMgPerDl,
+		trendArrow,
+		m.GlucoseColor,
+		m.GlucoseUnits,
+		m.IsHigh,
+		m.IsLow,
+		m.Type,
+	)
+}
+
+// writeCanonicalSensor writes one sensor as a single pipe-separated line.
+func writeCanonicalSensor(h hash.Hash, s *domain.SensorConfig) {
+	endedAt := ""
+	if s.EndedAt != nil {
+		endedAt = canonicalTime(*s.EndedAt)
+	}
+	fmt.Fprintf(h, "%s|%s|%s|%s|%s|%d|%d\n",
+		s.SerialNumber,
+		s.PatientID,
+		canonicalTime(s.Activation),
+		canonicalTime(s.ExpiresAt),
+		endedAt,
+		s.SensorType,
+		s.DurationDays,
+	)
+}
+
+// canonicalTime formats a timestamp in UTC with second precision, which is the
+// precision provided by LibreLinkUp and preserved by every supported database.
+func canonicalTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// syncDayStart truncates t to midnight UTC.
+func syncDayStart(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+func TestHashMeasurements_OrderIndependent(t *testing.T) {
+	base := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	a := &domain.GlucoseMeasurement{FactoryTimestamp: base, Timestamp: base, Value: 5.5, ValueInMgPerDl: 99}
+	b := &domain.GlucoseMeasurement{FactoryTimestamp: base.Add(5 * time.Minute), Timestamp: base.Add(5 * time.Minute), Value: 6.1, ValueInMgPerDl: 110}
+
+	h1 := HashMeasurements([]*domain.GlucoseMeasurement{a, b})
+	h2 := HashMeasurements([]*domain.GlucoseMeasurement{b, a})
+
+	if h1 != h2 {
+		t.Errorf("expected identical hashes regardless of order, got %s and %s", h1, h2)
+	}
+}
+
+func TestHashMeasurements_IgnoresDatabaseFields(t *testing.T) {
+	base := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	a := &domain.GlucoseMeasurement{ID: 1, CreatedAt: base, FactoryTimestamp: base, Timestamp: base, Value: 5.5, ValueInMgPerDl: 99}
+	b := &domain.GlucoseMeasurement{ID: 42, CreatedAt: base.Add(time.Hour), FactoryTimestamp: base, Timestamp: base, Value: 5.5, ValueInMgPerDl: 99}
+
+	if HashMeasurements([]*domain.GlucoseMeasurement{a}) != HashMeasurements([]*domain.GlucoseMeasurement{b}) {
+		t.Error("expected ID and CreatedAt to be excluded from hash")
+	}
+}
+
+func TestHashMeasurements_DetectsChange(t *testing.T) {
+	base := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	a := &domain.GlucoseMeasurement{FactoryTimestamp: base, Timestamp: base, Value: 5.5, ValueInMgPerDl: 99}
+	b := &domain.GlucoseMeasurement{FactoryTimestamp: base, Timestamp: base, Value: 5.6, ValueInMgPerDl: 101}
+
+	if HashMeasurements([]*domain.GlucoseMeasurement{a}) == HashMeasurements([]*domain.GlucoseMeasurement{b}) {
+		t.Error("expected different hashes for different values")
+	}
+}
+
+func TestHashMeasurements_Patients(t *testing.T) {
+	base := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	a := &domain.GlucoseMeasurement{PatientID: "patient-a", FactoryTimestamp: base, Timestamp: base, Value: 5.5, ValueInMgPerDl: 99}
+	b := &domain.GlucoseMeasurement{PatientID: "patient-b", FactoryTimestamp: base, Timestamp: base, Value: 6.1, ValueInMgPerDl: 110}
+
+	// Readings of two patients at the same time hash the same in any order
+	if HashMeasurements([]*domain.GlucoseMeasurement{a, b}) != HashMeasurements([]*domain.GlucoseMeasurement{b, a}) {
+		t.Error("expected identical hashes regardless of order")
+	}
+
+	// The patient is part of the reading
+	moved := *a
+	moved.PatientID = "patient-b"
+	if HashMeasurements([]*domain.GlucoseMeasurement{a}) == HashMeasurements([]*domain.GlucoseMeasurement{&moved}) {
+		t.Error("expected different hashes for different patients")
+	}
+}
+
+func TestSyncService_GetManifest_GroupsByDay(t *testing.T) {
+	day1 := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	day2 := time.Date(2026, 1, 16, 23, 59, 0, 0, time.UTC)
+
+	glucoseRepo := &MockGlucoseRepository{
+		FindByTimeRangeFunc: func(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error) {
+			if !start.Equal(time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("expected start truncated to midnight, got %v", start)
+			}
+			return []*domain.GlucoseMeasurement{
+				{FactoryTimestamp: day1, Timestamp: day1, ValueInMgPerDl: 100},
+				{FactoryTimestamp: day1.Add(time.Minute), Timestamp: day1.Add(time.Minute), ValueInMgPerDl: 101},
+				{FactoryTimestamp: day2, Timestamp: day2, ValueInMgPerDl: 102},
+			}, nil
+		},
+	}
+	sensorRepo := &MockSensorRepository{
+		FindAllFunc: func(ctx context.Context) ([]*domain.SensorConfig, error) {
+			return []*domain.SensorConfig{
+				{SerialNumber: "IN-RANGE", Activation: day2},
+				{SerialNumber: "OLD", Activation: day1.AddDate(0, -2, 0)},
+			}, nil
+		},
+	}
+
+	svc := NewSyncService(glucoseRepo, sensorRepo, slog.Default())
+
+	manifest, err := svc.GetManifest(context.Background(), time.Date(2026, 1, 14, 8, 0, 0, 0, time.UTC), day2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if manifest.Algorithm != SyncHashAlgorithm {
+		t.Errorf("expected algorithm %s, got %s", SyncHashAlgorithm, manifest.Algorithm)
+	}
+
+	// 2026-01-14 has no data and must be omitted
+	if len(manifest.Days) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(manifest.Days))
+	}
+
+	if manifest.Days[0].Date != "2026-01-15" || manifest.Days[0].GlucoseCount != 2 || manifest.Days[0].SensorCount != 0 {
+		t.Errorf("unexpected first day: %+v", manifest.Days[0])
+	}
+
+	if manifest.Days[1].Date != "2026-01-16" || manifest.Days[1].GlucoseCount != 1 || manifest.Days[1].SensorCount != 1 {
+		t.Errorf("unexpected second day: %+v", manifest.Days[1])
+	}
+
+	if manifest.Days[0].Hash == manifest.Days[1].Hash {
+		t.Error("expected different day hashes")
+	}
+}
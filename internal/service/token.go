@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// tokenPrefix marks glcmd API tokens, which makes leaked tokens easy to grep for.
+const tokenPrefix = "glc_"
+
+// tokenDisplayLength is the number of leading characters stored to identify a token.
+const tokenDisplayLength = 12
+
+// lastUsedResolution limits LastUsedAt writes to one per token per interval,
+// so that polling clients don't cause a database write on every request.
+const lastUsedResolution = time.Minute
+
+var (
+	// ErrTokenInvalid is returned when a token is unknown, revoked or expired.
+	ErrTokenInvalid = errors.New("invalid or expired token")
+
+	// ErrTokenScope is returned when a valid token lacks the required scope.
+	ErrTokenScope = errors.New("token scope does not allow this operation")
+)
+
+// TokenServiceImpl implements TokenService.
+type TokenServiceImpl struct {
+	tokenRepo repository.TokenRepository
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewTokenService creates a new TokenService.
+func NewTokenService(tokenRepo repository.TokenRepository, logger *slog.Logger) *TokenServiceImpl {
+	return &TokenServiceImpl{
+		tokenRepo: tokenRepo,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// CreateToken issues a new token and returns it with its plaintext value.
+// The plaintext is not stored and cannot be retrieved later.
+func (s *TokenServiceImpl) CreateToken(ctx context.Context, name, scope string, expiresAt *time.Time) (*domain.APIToken, string, error) {
+	if !slices.Contains(domain.TokenScopes, scope) {
+		return nil, "", fmt.Errorf("invalid scope %q (use %s)", scope, strings.Join(domain.TokenScopes, ", "))
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("generating token: %w", err)
+	}
+	plaintext := tokenPrefix + hex.EncodeToString(secret)
+
+	token := &domain.APIToken{
+		CreatedAt: s.now().UTC(),
+		Name:      name,
+		Prefix:    plaintext[:tokenDisplayLength],
+		TokenHash: HashToken(plaintext),
+		Scope:     scope,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	s.logger.Info("API token created",
+		"id", token.ID,
+		"name", token.Name,
+		"scope", token.Scope,
+		"expiresAt", token.ExpiresAt,
+	)
+	return token, plaintext, nil
+}
+
+// ListTokens returns all tokens, newest first.
+func (s *TokenServiceImpl) ListTokens(ctx context.Context) ([]*domain.APIToken, error) {
+	return s.tokenRepo.FindAll(ctx)
+}
+
+// RevokeToken revokes a token immediately.
+func (s *TokenServiceImpl) RevokeToken(ctx context.Context, id uint) error {
+	if err := s.tokenRepo.Revoke(ctx, id, s.now().UTC()); err != nil {
+		return err
+	}
+
+	s.logger.Info("API token revoked", "id", id)
+	return nil
+}
+
+// Authenticate validates a plaintext token against the required scope and
+// records its use. Returns ErrTokenInvalid or ErrTokenScope on failure.
+func (s *TokenServiceImpl) Authenticate(ctx context.Context, plaintext, requiredScope string) (*domain.APIToken, error) {
+	if !strings.HasPrefix(plaintext, tokenPrefix) {
+		return nil, ErrTokenInvalid
+	}
+
+	token, err := s.tokenRepo.FindByHash(ctx, HashToken(plaintext))
+	if err != nil {
+		if errors.Is(err, persistence.ErrNotFound) {
+			return nil, ErrTokenInvalid
+		}
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	if !token.IsActive(now) {
+		return nil, ErrTokenInvalid
+	}
+	if !token.Allows(requiredScope) {
+		return nil, ErrTokenScope
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		if err := s.tokenRepo.UpdateLastUsed(ctx, token.ID, now); err != nil {
+			// Not fatal: the request is already authenticated
+			s.logger.Warn("failed to record token use", "id", token.ID, "error", err)
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+
+	return token, nil
+}
+
+// HashToken returns the hex-encoded SHA-256 of a plaintext token.
+// Tokens carry 256 bits of entropy, so a fast unsalted hash is sufficient.
+func HashToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// MockTokenRepository is an in-memory TokenRepository for testing
+type MockTokenRepository struct {
+	tokens          []*domain.APIToken
+	lastUsedUpdates int
+}
+
+func (m *MockTokenRepository) Create(ctx context.Context, t *domain.APIToken) error {
+	t.ID = uint(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, t)
+	return nil
+}
+
+func (m *MockTokenRepository) FindAll(ctx context.Context) ([]*domain.APIToken, error) {
+	return m.tokens, nil
+}
+
+func (m *MockTokenRepository) FindByHash(ctx context.Context, hash string) (*domain.APIToken, error) {
+	for _, t := range m.tokens {
+		if t.TokenHash == hash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, persistence.ErrNotFound
+}
+
+func (m *MockTokenRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	for _, t := range m.tokens {
+		if t.ID == id && t.RevokedAt == nil {
+			t.RevokedAt = &at
+			return nil
+		}
+	}
+	return persistence.ErrNotFound
+}
+
+func (m *MockTokenRepository) UpdateLastUsed(ctx context.Context, id uint, at time.Time) error {
+	for _, t := range m.tokens {
+		if t.ID == id {
+			t.LastUsedAt = &at
+			m.lastUsedUpdates++
+		}
+	}
+	return nil
+}
+
+func TestTokenService_CreateAndAuthenticate(t *testing.T) {
+	repo := &MockTokenRepository{}
+	svc := NewTokenService(repo, slog.Default())
+	ctx := context.Background()
+
+	token, plaintext, err := svc.CreateToken(ctx, "display", domain.TokenScopeSync, nil)
+	if err != nil {
+		t.Fatalf("CreateToken: %v", err)
+	}
+	if !strings.HasPrefix(plaintext, "glc_") || !strings.HasPrefix(plaintext, token.Prefix) {
+		t.Errorf("unexpected plaintext %q for prefix %q", plaintext, token.Prefix)
+	}
+	if token.TokenHash == plaintext || token.TokenHash != HashToken(plaintext) {
+		t.Error("expected only the hash of the token to be stored")
+	}
+
+	if _, err := svc.Authenticate(ctx, plaintext, domain.TokenScopeRead); err != nil {
+		t.Errorf("expected sync token to allow read, got %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, plaintext, domain.TokenScopeAdmin); !errors.Is(err, ErrTokenScope) {
+		t.Errorf("expected ErrTokenScope, got %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, "glc_unknown", domain.TokenScopeRead); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("expected ErrTokenInvalid, got %v", err)
+	}
+}
+
+func TestTokenService_CreateToken_InvalidScope(t *testing.T) {
+	svc := NewTokenService(&MockTokenRepository{}, slog.Default())
+
+	if _, _, err := svc.CreateToken(context.Background(), "x", "root", nil); err == nil {
+		t.Error("expected error for invalid scope")
+	}
+}
+
+func TestTokenService_RevokedAndExpired(t *testing.T) {
+	repo := &MockTokenRepository{}
+	svc := NewTokenService(repo, slog.Default())
+	ctx := context.Background()
+
+	token, plaintext, _ := svc.CreateToken(ctx, "leaked", domain.TokenScopeAdmin, nil)
+	if err := svc.RevokeToken(ctx, token.ID); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, plaintext, domain.TokenScopeRead); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("expected revoked token to be rejected, got %v", err)
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	_, plaintext, _ = svc.CreateToken(ctx, "old", domain.TokenScopeRead, &expired)
+	if _, err := svc.Authenticate(ctx, plaintext, domain.TokenScopeRead); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("expected expired token to be rejected, got %v", err)
+	}
+}
+
+func TestTokenService_LastUsedThrottled(t *testing.T) {
+	repo := &MockTokenRepository{}
+	svc := NewTokenService(repo, slog.Default())
+	ctx := context.Background()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	_, plaintext, _ := svc.CreateToken(ctx, "poller", domain.TokenScopeRead, nil)
+	for i := 0; i < 5; i++ {
+		if _, err := svc.Authenticate(ctx, plaintext, domain.TokenScopeRead); err != nil {
+			t.Fatalf("Authenticate: %v", err)
+		}
+	}
+	if repo.lastUsedUpdates != 1 {
+		t.Errorf("expected 1 lastUsed write within a minute, got %d", repo.lastUsedUpdates)
+	}
+
+	now = now.Add(2 * time.Minute)
+	svc.Authenticate(ctx, plaintext, domain.TokenScopeRead)
+	if repo.lastUsedUpdates != 2 {
+		t.Errorf("expected a second lastUsed write after a minute, got %d", repo.lastUsedUpdates)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// TreatmentImport reports the outcome of a treatment import.
+type TreatmentImport struct {
+	Imported   int `json:"imported"`
+	Duplicates int `json:"duplicates"` // Already imported from an earlier export
+}
+
+// TreatmentServiceImpl implements TreatmentService.
+type TreatmentServiceImpl struct {
+	repo        repository.TreatmentRepository
+	glucoseRepo repository.GlucoseRepository
+	uow         repository.UnitOfWork
+	logger      *slog.Logger
+}
+
+// NewTreatmentService creates a new TreatmentService.
+// glucoseRepo provides the measurements treatments are analyzed against.
+func NewTreatmentService(repo repository.TreatmentRepository, glucoseRepo repository.GlucoseRepository, uow repository.UnitOfWork, logger *slog.Logger) *TreatmentServiceImpl {
+	return &TreatmentServiceImpl{
+		repo:        repo,
+		glucoseRepo: glucoseRepo,
+		uow:         uow,
+		logger:      logger,
+	}
+}
+
+// ImportTreatments stores treatments parsed from a pump export in a single
+// transaction. Treatments already stored are counted as duplicates.
+// progress is optional and is called with the number of treatments saved so
+// far. Canceling ctx rolls the whole import back.
+func (s *TreatmentServiceImpl) ImportTreatments(ctx context.Context, treatments []*domain.TreatmentEntry, progress func(done int)) (*TreatmentImport, error) {
+	result := &TreatmentImport{}
+
+	err := s.uow.ExecuteInTransaction(ctx, func(txCtx context.Context) error {
+		for i, t := range treatments {
+			if err := txCtx.Err(); err != nil {
+				return err
+			}
+			inserted, err := s.repo.Save(txCtx, t)
+			if err != nil {
+				return err
+			}
+			if inserted {
+				result.Imported++
+			} else {
+				result.Duplicates++
+			}
+			if progress != nil {
+				progress(i + 1)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("treatments imported",
+		"imported", result.Imported,
+		"duplicates", result.Duplicates,
+	)
+	return result, nil
+}
+
+// GetTreatments returns treatments within a time range, oldest first.
+func (s *TreatmentServiceImpl) GetTreatments(ctx context.Context, start, end time.Time) ([]*domain.TreatmentEntry, error) {
+	return s.repo.FindByTimeRange(ctx, start, end)
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// Treatment analysis windows
+const (
+	// postBolusWindow is how long glucose is followed after a bolus.
+	postBolusWindow = 3 * time.Hour
+	// baselineTolerance is how old the reading used as pre-bolus glucose may be.
+	baselineTolerance = 15 * time.Minute
+	// windowCoverage is how close to the end of the window the last reading must be.
+	windowCoverage = 30 * time.Minute
+	// insightRatio is how much faster glucose must rise in one period than in
+	// another before it is reported.
+	insightRatio = 1.5
+	// highPeakMgDl is the average meal peak above which meals are reported.
+	highPeakMgDl = 180
+)
+
+// Times of day meals are grouped by (local time)
+const (
+	TimeOfDayMorning = "morning" // 05:00-11:00
+	TimeOfDayMidday  = "midday"  // 11:00-16:00
+	TimeOfDayEvening = "evening" // 16:00-22:00
+	TimeOfDayNight   = "night"   // 22:00-05:00
+)
+
+// timesOfDay lists the periods in display order.
+var timesOfDay = []string{TimeOfDayMorning, TimeOfDayMidday, TimeOfDayEvening, TimeOfDayNight}
+
+// TreatmentAnalysis correlates boluses with the glucose that follows them.
+type TreatmentAnalysis struct {
+	Start       time.Time        `json:"start"`
+	End         time.Time        `json:"end"`
+	Meals       []*MealStats     `json:"meals"`                 // One entry per time of day with meals
+	Corrections *CorrectionStats `json:"corrections,omitempty"` // Boluses without carbs (nil if none)
+	Insights    []string         `json:"insights"`
+}
+
+// MealStats summarizes the glucose rise after meal boluses at one time of day.
+type MealStats struct {
+	TimeOfDay       string  `json:"timeOfDay"`
+	Count           int     `json:"count"`
+	AvgCarbs        float64 `json:"avgCarbs"`        // Grams
+	AvgInsulin      float64 `json:"avgInsulin"`      // Units
+	AvgRiseMgDl     float64 `json:"avgRiseMgDl"`     // Peak minus pre-bolus glucose
+	RisePer10gCarbs float64 `json:"risePer10gCarbs"` // mg/dL per 10 g of carbs
+	AvgPeakMgDl     float64 `json:"avgPeakMgDl"`
+}
+
+// CorrectionStats summarizes the glucose drop after boluses without carbs.
+type CorrectionStats struct {
+	Count           int     `json:"count"`
+	AvgInsulin      float64 `json:"avgInsulin"`
+	AvgDropMgDl     float64 `json:"avgDropMgDl"`     // Pre-bolus minus lowest glucose
+	DropPerUnitMgDl float64 `json:"dropPerUnitMgDl"` // Observed insulin sensitivity
+}
+
+// AnalyzeTreatments follows glucose for 3 hours after each bolus in [start, end].
+// Boluses followed by another bolus within that window, or without readings
+// covering it, are left out since their effect cannot be isolated.
+func (s *TreatmentServiceImpl) AnalyzeTreatments(ctx context.Context, start, end time.Time) (*TreatmentAnalysis, error) {
+	treatments, err := s.repo.FindByTimeRange(ctx, start, end.Add(postBolusWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get treatments: %w", err)
+	}
+	measurements, err := s.glucoseRepo.FindByTimeRange(ctx, start.Add(-baselineTolerance), end.Add(postBolusWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get measurements: %w", err)
+	}
+	sort.Slice(measurements, func(i, j int) bool {
+		return measurements[i].Timestamp.Before(measurements[j].Timestamp)
+	})
+
+	var boluses []*domain.TreatmentEntry
+	for _, t := range treatments {
+		if t.Type == domain.TreatmentTypeBolus {
+			boluses = append(boluses, t)
+		}
+	}
+
+	meals := make(map[string]*mealTotals)
+	var corrections correctionTotals
+
+	for i, bolus := range boluses {
+		if bolus.Timestamp.After(end) {
+			break // Only used to detect overlaps
+		}
+		windowEnd := bolus.Timestamp.Add(postBolusWindow)
+		if i+1 < len(boluses) && boluses[i+1].Timestamp.Before(windowEnd) {
+			continue
+		}
+
+		response, ok := followGlucose(measurements, bolus.Timestamp, windowEnd)
+		if !ok {
+			continue
+		}
+
+		if bolus.Carbs == nil {
+			corrections.add(bolus.Insulin, response)
+			continue
+		}
+
+		period := timeOfDay(bolus.Timestamp)
+		if meals[period] == nil {
+			meals[period] = &mealTotals{}
+		}
+		meals[period].add(*bolus.Carbs, bolus.Insulin, response)
+	}
+
+	analysis := &TreatmentAnalysis{
+		Start:       start,
+		End:         end,
+		Meals:       make([]*MealStats, 0, len(meals)),
+		Corrections: corrections.stats(),
+	}
+	for _, period := range timesOfDay {
+		if totals := meals[period]; totals != nil {
+			analysis.Meals = append(analysis.Meals, totals.stats(period))
+		}
+	}
+	analysis.Insights = treatmentInsights(analysis)
+
+	return analysis, nil
+}
+
+// glucoseResponse is the glucose observed around a bolus.
+type glucoseResponse struct {
+	baseline int // Last reading before the bolus
+	peak     int // Highest reading in the window
+	low      int // Lowest reading in the window
+}
+
+// followGlucose returns the glucose response to a bolus at t, or false when
+// readings do not cover the bolus and its window. measurements must be sorted.
+func followGlucose(measurements []*domain.GlucoseMeasurement, t, windowEnd time.Time) (glucoseResponse, bool) {
+	first := sort.Search(len(measurements), func(i int) bool {
+		return measurements[i].Timestamp.After(t)
+	})
+	if first == 0 || t.Sub(measurements[first-1].Timestamp) > baselineTolerance {
+		return glucoseResponse{}, false
+	}
+
+	baseline := measurements[first-1].ValueInMgPerDl
+	response := glucoseResponse{baseline: baseline, peak: baseline, low: baseline}
+
+	var last time.Time
+	for _, m := range measurements[first:] {
+		if m.Timestamp.After(windowEnd) {
+			break
+		}
+		response.peak = max(response.peak, m.ValueInMgPerDl)
+		response.low = min(response.low, m.ValueInMgPerDl)
+		last = m.Timestamp
+	}
+
+	if windowEnd.Sub(last) > windowCoverage {
+		return glucoseResponse{}, false
+	}
+	return response, true
+}
+
+// timeOfDay returns the local period of the day of t.
+func timeOfDay(t time.Time) string {
+	switch hour := t.Local().Hour(); {
+	case hour >= 5 && hour < 11:
+		return TimeOfDayMorning
+	case hour >= 11 && hour < 16:
+		return TimeOfDayMidday
+	case hour >= 16 && hour < 22:
+		return TimeOfDayEvening
+	default:
+		return TimeOfDayNight
+	}
+}
+
+// mealTotals accumulates meal responses for one time of day.
+type mealTotals struct {
+	count                       int
+	carbs, insulin, rise, peaks float64
+}
+
+func (m *mealTotals) add(carbs, insulin float64, r glucoseResponse) {
+	m.count++
+	m.carbs += carbs
+	m.insulin += insulin
+	m.rise += float64(r.peak - r.baseline)
+	m.peaks += float64(r.peak)
+}
+
+func (m *mealTotals) stats(period string) *MealStats {
+	n := float64(m.count)
+	return &MealStats{
+		TimeOfDay:       period,
+		Count:           m.count,
+		AvgCarbs:        round1(m.carbs / n),
+		AvgInsulin:      round1(m.insulin / n),
+		AvgRiseMgDl:     round1(m.rise / n),
+		RisePer10gCarbs: round1(m.rise / m.carbs * 10),
+		AvgPeakMgDl:     round1(m.peaks / n),
+	}
+}
+
+// correctionTotals accumulates correction bolus responses.
+type correctionTotals struct {
+	count         int
+	insulin, drop float64
+}
+
+func (c *correctionTotals) add(insulin float64, r glucoseResponse) {
+	c.count++
+	c.insulin += insulin
+	c.drop += float64(r.baseline - r.low)
+}
+
+func (c *correctionTotals) stats() *CorrectionStats {
+	if c.count == 0 {
+		return nil
+	}
+	n := float64(c.count)
+	return &CorrectionStats{
+		Count:           c.count,
+		AvgInsulin:      round1(c.insulin / n),
+		AvgDropMgDl:     round1(c.drop / n),
+		DropPerUnitMgDl: round1(c.drop / c.insulin),
+	}
+}
+
+// treatmentInsights returns short, actionable observations from an analysis.
+func treatmentInsights(a *TreatmentAnalysis) []string {
+	insights := []string{}
+
+	if len(a.Meals) >= 2 {
+		highest, lowest := a.Meals[0], a.Meals[0]
+		for _, m := range a.Meals[1:] {
+			if m.RisePer10gCarbs > highest.RisePer10gCarbs {
+				highest = m
+			}
+			if m.RisePer10gCarbs < lowest.RisePer10gCarbs {
+				lowest = m
+			}
+		}
+		if lowest.RisePer10gCarbs > 0 && highest.RisePer10gCarbs >= insightRatio*lowest.RisePer10gCarbs {
+			insights = append(insights, fmt.Sprintf(
+				"Glucose rises most after %s meals: %.0f mg/dL per 10 g of carbs, vs %.0f in the %s",
+				highest.TimeOfDay, highest.RisePer10gCarbs, lowest.RisePer10gCarbs, lowest.TimeOfDay))
+		}
+	}
+
+	for _, m := range a.Meals {
+		if m.AvgPeakMgDl > highPeakMgDl {
+			insights = append(insights, fmt.Sprintf(
+				"%s meals peak at %.0f mg/dL on average (above %d)",
+				capitalize(m.TimeOfDay), m.AvgPeakMgDl, highPeakMgDl))
+		}
+	}
+
+	if c := a.Corrections; c != nil {
+		insights = append(insights, fmt.Sprintf(
+			"One unit of correction insulin lowered glucose by %.0f mg/dL on average (%d corrections)",
+			c.DropPerUnitMgDl, c.Count))
+	}
+
+	return insights
+}
+
+// round1 rounds to one decimal.
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
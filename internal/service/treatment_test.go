@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+)
+
+// MockTreatmentRepository is an in-memory TreatmentRepository for testing
+type MockTreatmentRepository struct {
+	treatments []*domain.TreatmentEntry
+}
+
+func (m *MockTreatmentRepository) Save(ctx context.Context, t *domain.TreatmentEntry) (bool, error) {
+	for _, existing := range m.treatments {
+		if existing.Timestamp.Equal(t.Timestamp) && existing.Type == t.Type && existing.Source == t.Source {
+			return false, nil
+		}
+	}
+	m.treatments = append(m.treatments, t)
+	return true, nil
+}
+
+func (m *MockTreatmentRepository) FindByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.TreatmentEntry, error) {
+	return m.treatments, nil
+}
+
+func TestTreatmentService_ImportTreatments(t *testing.T) {
+	repo := &MockTreatmentRepository{}
+	svc := NewTreatmentService(repo, &MockGlucoseRepository{}, &MockUnitOfWork{}, slog.Default())
+	ctx := context.Background()
+
+	ts := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	export := func() []*domain.TreatmentEntry {
+		return []*domain.TreatmentEntry{
+			{Timestamp: ts, Type: domain.TreatmentTypeBolus, Source: domain.TreatmentSourceTandem, Insulin: 4},
+			{Timestamp: ts, Type: domain.TreatmentTypeBasal, Source: domain.TreatmentSourceTandem, Insulin: 0.8},
+		}
+	}
+
+	var done []int
+	result, err := svc.ImportTreatments(ctx, export(), func(n int) { done = append(done, n) })
+	if err != nil {
+		t.Fatalf("ImportTreatments: %v", err)
+	}
+	if result.Imported != 2 || result.Duplicates != 0 {
+		t.Errorf("expected 2 imported, got %+v", result)
+	}
+	if len(done) != 2 || done[1] != 2 {
+		t.Errorf("expected progress 1, 2, got %v", done)
+	}
+
+	// Importing the same export again only finds duplicates
+	result, err = svc.ImportTreatments(ctx, export(), nil)
+	if err != nil {
+		t.Fatalf("ImportTreatments: %v", err)
+	}
+	if result.Imported != 0 || result.Duplicates != 2 {
+		t.Errorf("expected 2 duplicates, got %+v", result)
+	}
+
+	// A canceled import stops before saving
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := svc.ImportTreatments(canceled, export(), nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTreatmentService_AnalyzeTreatments(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	carbs := func(g float64) *float64 { return &g }
+
+	// Readings every 15 minutes: the bolus baseline, then a peak or a low an hour later
+	var measurements []*domain.GlucoseMeasurement
+	response := func(bolus time.Time, baseline, extreme int) {
+		for ts := bolus.Add(-5 * time.Minute); !ts.After(bolus.Add(3 * time.Hour)); ts = ts.Add(15 * time.Minute) {
+			value := baseline
+			if ts.Sub(bolus) >= time.Hour && ts.Sub(bolus) < 90*time.Minute {
+				value = extreme
+			}
+			measurements = append(measurements, &domain.GlucoseMeasurement{Timestamp: ts, ValueInMgPerDl: value})
+		}
+	}
+	response(at(8, 0), 100, 200)  // Breakfast: +100 for 50 g
+	response(at(19, 0), 100, 150) // Dinner: +50 for 50 g
+	response(at(13, 0), 200, 140) // Correction: -60 for 2 U
+
+	repo := &MockTreatmentRepository{treatments: []*domain.TreatmentEntry{
+		{Timestamp: at(8, 0), Type: domain.TreatmentTypeBolus, Insulin: 5, Carbs: carbs(50)},
+		{Timestamp: at(13, 0), Type: domain.TreatmentTypeBolus, Insulin: 2},
+		{Timestamp: at(19, 0), Type: domain.TreatmentTypeBolus, Insulin: 4, Carbs: carbs(50)},
+		{Timestamp: at(23, 0), Type: domain.TreatmentTypeBolus, Insulin: 1, Carbs: carbs(20)}, // No readings
+		{Timestamp: at(20, 0), Type: domain.TreatmentTypeBasal, Insulin: 0.8},
+	}}
+	glucoseRepo := &MockGlucoseRepository{
+		FindByTimeRangeFunc: func(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error) {
+			return measurements, nil
+		},
+	}
+	svc := NewTreatmentService(repo, glucoseRepo, &MockUnitOfWork{}, slog.Default())
+
+	analysis, err := svc.AnalyzeTreatments(context.Background(), day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("AnalyzeTreatments: %v", err)
+	}
+
+	if len(analysis.Meals) != 2 {
+		t.Fatalf("expected morning and evening meals, got %+v", analysis.Meals)
+	}
+	morning, evening := analysis.Meals[0], analysis.Meals[1]
+	if morning.TimeOfDay != TimeOfDayMorning || morning.AvgRiseMgDl != 100 || morning.RisePer10gCarbs != 20 || morning.AvgPeakMgDl != 200 {
+		t.Errorf("unexpected morning stats: %+v", morning)
+	}
+	if evening.TimeOfDay != TimeOfDayEvening || evening.RisePer10gCarbs != 10 {
+		t.Errorf("unexpected evening stats: %+v", evening)
+	}
+
+	if analysis.Corrections == nil || analysis.Corrections.Count != 1 || analysis.Corrections.DropPerUnitMgDl != 30 {
+		t.Errorf("unexpected corrections: %+v", analysis.Corrections)
+	}
+
+	// Morning rises twice as fast, morning peaks above 180, correction sensitivity
+	if len(analysis.Insights) != 3 {
+		t.Errorf("expected 3 insights, got %q", analysis.Insights)
+	}
+}
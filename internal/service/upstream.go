@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// Upstream availability states
+const (
+	UpstreamStatusUp       = "up"       // Last fetch succeeded
+	UpstreamStatusDegraded = "degraded" // Last fetch failed, not yet an outage
+	UpstreamStatusDown     = "down"     // Outage ongoing
+)
+
+// OutageFailureThreshold is the number of consecutive failed fetches that opens
+// an outage. A single failure is usually a transient network error.
+const OutageFailureThreshold = 2
+
+// maxOutageErrorLength bounds the error message stored with an outage.
+const maxOutageErrorLength = 255
+
+// uptimeWindows are the periods uptime is reported for.
+var uptimeWindows = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"24h", 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+	{"30d", 30 * 24 * time.Hour},
+}
+
+// UptimeWindow is the share of a period during which LibreView was reachable.
+// Time not covered by a recorded outage counts as up.
+type UptimeWindow struct {
+	Window  string  `json:"window"`
+	Percent float64 `json:"percent"`
+	Outages int     `json:"outages"`
+}
+
+// UpstreamStatus is the availability of LibreView as seen by the daemon.
+type UpstreamStatus struct {
+	Status              string                   `json:"status"` // One of UpstreamStatus*
+	LastSuccessAt       *time.Time               `json:"lastSuccessAt,omitempty"`
+	ConsecutiveFailures int                      `json:"consecutiveFailures"`
+	Current             *domain.UpstreamOutage   `json:"current,omitempty"`
+	Uptime              []UptimeWindow           `json:"uptime"`
+	Outages             []*domain.UpstreamOutage `json:"outages"` // Newest first
+}
+
+// UpstreamServiceImpl implements UpstreamService.
+type UpstreamServiceImpl struct {
+	repo   repository.UpstreamRepository
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu             sync.Mutex
+	loaded         bool                   // Ongoing outage loaded from the database
+	current        *domain.UpstreamOutage // Ongoing outage, nil if none
+	failures       int                    // Consecutive failed fetches
+	firstFailureAt time.Time
+	firstCause     string
+	lastSuccessAt  time.Time
+}
+
+// NewUpstreamService creates a new UpstreamService.
+func NewUpstreamService(repo repository.UpstreamRepository, logger *slog.Logger) *UpstreamServiceImpl {
+	return &UpstreamServiceImpl{
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// RecordFailure records a failed fetch. The outage opens once
+// OutageFailureThreshold consecutive fetches failed, starting at the first of them.
+func (s *UpstreamServiceImpl) RecordFailure(ctx context.Context, cause string, fetchErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadLocked(ctx); err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	s.failures++
+	if s.failures == 1 {
+		s.firstFailureAt = now
+		s.firstCause = cause
+	}
+
+	message := ""
+	if fetchErr != nil {
+		message = fetchErr.Error()
+		if len(message) > maxOutageErrorLength {
+			message = message[:maxOutageErrorLength]
+		}
+	}
+
+	if s.current != nil {
+		s.current.Failures++
+		s.current.LastError = message
+		return s.repo.Update(ctx, s.current)
+	}
+
+	if s.failures < OutageFailureThreshold {
+		return nil
+	}
+
+	outage := &domain.UpstreamOutage{
+		StartedAt: s.firstFailureAt,
+		Cause:     s.firstCause,
+		LastError: message,
+		Failures:  s.failures,
+	}
+	if err := s.repo.Create(ctx, outage); err != nil {
+		return fmt.Errorf("failed to record outage: %w", err)
+	}
+	s.current = outage
+	s.logger.Warn("upstream outage started", "cause", outage.Cause, "since", outage.StartedAt)
+	return nil
+}
+
+// RecordSuccess records a successful fetch and ends the ongoing outage, if any.
+func (s *UpstreamServiceImpl) RecordSuccess(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadLocked(ctx); err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	s.failures = 0
+	s.lastSuccessAt = now
+
+	if s.current == nil {
+		return nil
+	}
+
+	s.current.EndedAt = &now
+	if err := s.repo.Update(ctx, s.current); err != nil {
+		return fmt.Errorf("failed to end outage: %w", err)
+	}
+	s.logger.Info("upstream outage ended", "cause", s.current.Cause, "duration", s.current.Duration(now).Round(time.Second))
+	s.current = nil
+	return nil
+}
+
+// GetStatus returns the current availability, the uptime over the last 24h,
+// 7d and 30d, and the outages of the last days days.
+func (s *UpstreamServiceImpl) GetStatus(ctx context.Context, days int) (*UpstreamStatus, error) {
+	s.mu.Lock()
+	if err := s.loadLocked(ctx); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	status := &UpstreamStatus{
+		Status:              UpstreamStatusUp,
+		ConsecutiveFailures: s.failures,
+	}
+	if s.current != nil {
+		status.Status = UpstreamStatusDown
+		current := *s.current
+		status.Current = &current
+	} else if s.failures > 0 {
+		status.Status = UpstreamStatusDegraded
+	}
+	if !s.lastSuccessAt.IsZero() {
+		lastSuccessAt := s.lastSuccessAt
+		status.LastSuccessAt = &lastSuccessAt
+	}
+	s.mu.Unlock()
+
+	now := s.now().UTC()
+	lookback := time.Duration(days) * 24 * time.Hour
+	if widest := uptimeWindows[len(uptimeWindows)-1].duration; widest > lookback {
+		lookback = widest
+	}
+	outages, err := s.repo.FindSince(ctx, now.Add(-lookback))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outages: %w", err)
+	}
+
+	for _, w := range uptimeWindows {
+		status.Uptime = append(status.Uptime, uptimeOver(outages, now.Add(-w.duration), now, w.name))
+	}
+
+	listSince := now.AddDate(0, 0, -days)
+	status.Outages = []*domain.UpstreamOutage{}
+	for _, o := range outages {
+		if o.IsOngoing() || !o.EndedAt.Before(listSince) {
+			status.Outages = append(status.Outages, o)
+		}
+	}
+
+	return status, nil
+}
+
+// loadLocked loads the ongoing outage left by a previous run, once.
+// Must be called with s.mu held.
+func (s *UpstreamServiceImpl) loadLocked(ctx context.Context) error {
+	if s.loaded {
+		return nil
+	}
+
+	outage, err := s.repo.FindOngoing(ctx)
+	if err != nil && !errors.Is(err, persistence.ErrNotFound) {
+		return fmt.Errorf("failed to get ongoing outage: %w", err)
+	}
+	s.current = outage
+	if outage != nil {
+		s.failures = outage.Failures
+		s.firstFailureAt = outage.StartedAt
+		s.firstCause = outage.Cause
+	}
+	s.loaded = true
+	return nil
+}
+
+// uptimeOver returns the uptime of [start, end] given the outages overlapping it.
+func uptimeOver(outages []*domain.UpstreamOutage, start, end time.Time, name string) UptimeWindow {
+	window := UptimeWindow{Window: name, Percent: 100}
+
+	var down time.Duration
+	for _, o := range outages {
+		outageEnd := end
+		if o.EndedAt != nil && o.EndedAt.Before(end) {
+			outageEnd = *o.EndedAt
+		}
+		outageStart := o.StartedAt
+		if outageStart.Before(start) {
+			outageStart = start
+		}
+		if !outageEnd.After(outageStart) {
+			continue
+		}
+		down += outageEnd.Sub(outageStart)
+		window.Outages++
+	}
+
+	total := end.Sub(start)
+	if down > 0 && total > 0 {
+		window.Percent = math.Round((1-float64(down)/float64(total))*10000) / 100
+	}
+	return window
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+)
+
+// memoryUpstreamRepository keeps the outages in memory
+type memoryUpstreamRepository struct {
+	outages []*domain.UpstreamOutage
+}
+
+func (r *memoryUpstreamRepository) Create(ctx context.Context, o *domain.UpstreamOutage) error {
+	o.ID = uint(len(r.outages) + 1)
+	r.outages = append(r.outages, o)
+	return nil
+}
+
+func (r *memoryUpstreamRepository) Update(ctx context.Context, o *domain.UpstreamOutage) error {
+	r.outages[o.ID-1] = o
+	return nil
+}
+
+func (r *memoryUpstreamRepository) FindOngoing(ctx context.Context) (*domain.UpstreamOutage, error) {
+	for _, o := range r.outages {
+		if o.IsOngoing() {
+			return o, nil
+		}
+	}
+	return nil, persistence.ErrNotFound
+}
+
+func (r *memoryUpstreamRepository) FindSince(ctx context.Context, since time.Time) ([]*domain.UpstreamOutage, error) {
+	var outages []*domain.UpstreamOutage
+	for i := len(r.outages) - 1; i >= 0; i-- {
+		if o := r.outages[i]; o.IsOngoing() || !o.EndedAt.Before(since) {
+			outages = append(outages, o)
+		}
+	}
+	return outages, nil
+}
+
+func TestUpstreamService_Outage(t *testing.T) {
+	repo := &memoryUpstreamRepository{}
+	svc := NewUpstreamService(repo, slog.Default())
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+	fetchErr := errors.New("server error (status 502)")
+
+	// A single failure is not an outage
+	if err := svc.RecordFailure(ctx, domain.OutageCauseServer, fetchErr); err != nil {
+		t.Fatalf("RecordFailure failed: %v", err)
+	}
+	status, err := svc.GetStatus(ctx, 30)
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if status.Status != UpstreamStatusDegraded || len(repo.outages) != 0 {
+		t.Fatalf("expected degraded without outage, got %s with %d outages", status.Status, len(repo.outages))
+	}
+
+	// The second consecutive failure opens the outage at the first one
+	started := now
+	now = now.Add(time.Minute)
+	if err := svc.RecordFailure(ctx, domain.OutageCauseNetwork, fetchErr); err != nil {
+		t.Fatalf("RecordFailure failed: %v", err)
+	}
+	status, _ = svc.GetStatus(ctx, 30)
+	if status.Status != UpstreamStatusDown || status.Current == nil {
+		t.Fatalf("expected down with a current outage, got %+v", status)
+	}
+	if !status.Current.StartedAt.Equal(started) || status.Current.Cause != domain.OutageCauseServer || status.Current.Failures != 2 {
+		t.Errorf("unexpected outage: %+v", status.Current)
+	}
+
+	// Success ends it
+	now = started.Add(2 * time.Hour)
+	if err := svc.RecordSuccess(ctx); err != nil {
+		t.Fatalf("RecordSuccess failed: %v", err)
+	}
+	status, _ = svc.GetStatus(ctx, 30)
+	if status.Status != UpstreamStatusUp || status.Current != nil {
+		t.Fatalf("expected up, got %+v", status)
+	}
+	if len(status.Outages) != 1 || status.Outages[0].Duration(now) != 2*time.Hour {
+		t.Fatalf("expected one 2h outage, got %+v", status.Outages)
+	}
+
+	// 2h down over 24h
+	if status.Uptime[0].Window != "24h" || status.Uptime[0].Percent != 91.67 || status.Uptime[0].Outages != 1 {
+		t.Errorf("unexpected 24h uptime: %+v", status.Uptime[0])
+	}
+	// 2h down over 30d
+	if status.Uptime[2].Window != "30d" || status.Uptime[2].Percent != 99.72 {
+		t.Errorf("unexpected 30d uptime: %+v", status.Uptime[2])
+	}
+
+	// Outages older than the requested days are not listed, but still count in uptime
+	now = now.Add(3 * 24 * time.Hour)
+	status, _ = svc.GetStatus(ctx, 1)
+	if len(status.Outages) != 0 {
+		t.Errorf("expected no outage in the last day, got %d", len(status.Outages))
+	}
+	if status.Uptime[1].Percent != 98.81 {
+		t.Errorf("unexpected 7d uptime: %+v", status.Uptime[1])
+	}
+}
+
+func TestUpstreamService_ResumesOngoingOutage(t *testing.T) {
+	started := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := &memoryUpstreamRepository{}
+	repo.Create(context.Background(), &domain.UpstreamOutage{StartedAt: started, Cause: domain.OutageCauseAuth, Failures: 3})
+
+	// An outage left open by a previous run is ended by the next success
+	svc := NewUpstreamService(repo, slog.Default())
+	svc.now = func() time.Time { return started.Add(time.Hour) }
+	if err := svc.RecordSuccess(context.Background()); err != nil {
+		t.Fatalf("RecordSuccess failed: %v", err)
+	}
+	if repo.outages[0].IsOngoing() {
+		t.Fatal("expected the outage to be ended")
+	}
+	if !repo.outages[0].IsSetupProblem() {
+		t.Error("expected an auth outage to be a setup problem")
+	}
+}
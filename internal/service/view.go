@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+)
+
+// ViewServiceImpl implements ViewService.
+type ViewServiceImpl struct {
+	repo   repository.ViewRepository
+	logger *slog.Logger
+}
+
+// NewViewService creates a new ViewService.
+func NewViewService(repo repository.ViewRepository, logger *slog.Logger) *ViewServiceImpl {
+	return &ViewServiceImpl{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// SaveView creates or replaces a view and returns it as stored, so a
+// replaced view keeps its original creation time.
+func (s *ViewServiceImpl) SaveView(ctx context.Context, v *domain.SavedView) (*domain.SavedView, error) {
+	if err := s.repo.Save(ctx, v); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("saved view stored", "name", v.Name, "aggregation", v.Aggregation)
+
+	return s.repo.FindByName(ctx, v.Name)
+}
+
+// GetViews returns all views, sorted by name.
+func (s *ViewServiceImpl) GetViews(ctx context.Context) ([]*domain.SavedView, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// GetView returns the view with the given name.
+func (s *ViewServiceImpl) GetView(ctx context.Context, name string) (*domain.SavedView, error) {
+	return s.repo.FindByName(ctx, name)
+}
+
+// DeleteView removes the view with the given name.
+func (s *ViewServiceImpl) DeleteView(ctx context.Context, name string) error {
+	if err := s.repo.Delete(ctx, name); err != nil {
+		return err
+	}
+
+	s.logger.Info("saved view deleted", "name", name)
+
+	return nil
+}
@@ -0,0 +1,149 @@
+// Package summary publishes a morning summary of overnight glucose.
+//
+// Every day at the configured local time, a background job summarizes the
+// preceding night (minimum, maximum, time spent low and the current value)
+// and publishes it as a "summary" event on the event stream, where clients
+// such as `glcli watch` pick it up.
+package summary
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/events"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+// maxReadingGap bounds the time a single reading accounts for when measuring
+// time low, so gaps in the data are not counted as low.
+const maxReadingGap = 15 * time.Minute
+
+// Scheduler builds and publishes the morning summary. glcore runs Publish
+// as a job at the times of Spec.
+type Scheduler struct {
+	at             time.Duration // Time of day (local) since midnight
+	night          time.Duration // Length of the summarized night, ending at the summary time
+	patientID      string        // Summarized patient ("" = all stored readings)
+	glucoseService service.GlucoseService
+	eventBroker    *events.Broker
+	logger         *slog.Logger
+}
+
+// NewScheduler creates a new Scheduler.
+// at is the local time of day of the summary (e.g. 7h for 07:00).
+// patientID is the LibreLinkUp patient summarized when several are stored.
+// eventBroker is optional and can be nil (summaries are only logged).
+func NewScheduler(
+	at time.Duration,
+	night time.Duration,
+	patientID string,
+	glucoseService service.GlucoseService,
+	eventBroker *events.Broker,
+	logger *slog.Logger,
+) *Scheduler {
+	return &Scheduler{
+		at:             at,
+		night:          night,
+		patientID:      patientID,
+		glucoseService: glucoseService,
+		eventBroker:    eventBroker,
+		logger:         logger,
+	}
+}
+
+// Spec returns the daily schedule of the summary, as a cron expression for
+// the job scheduler.
+func (s *Scheduler) Spec() string {
+	return fmt.Sprintf("%d %d * * *", int(s.at%time.Hour/time.Minute), int(s.at/time.Hour))
+}
+
+// Publish builds the summary ending at end, logs it and publishes it.
+func (s *Scheduler) Publish(ctx context.Context, end time.Time) error {
+	summary, err := s.Build(ctx, end)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("morning summary",
+		"count", summary.Count,
+		"minMgDl", summary.MinMgDl,
+		"maxMgDl", summary.MaxMgDl,
+		"timeLowMinutes", summary.TimeLowMinutes,
+	)
+
+	if s.eventBroker != nil {
+		s.eventBroker.Publish(events.Event{
+			Type:      events.EventTypeSummary,
+			Data:      summary,
+			PatientID: s.patientID,
+		})
+	}
+
+	return nil
+}
+
+// Build summarizes the night of the patient ending at end.
+func (s *Scheduler) Build(ctx context.Context, end time.Time) (*domain.MorningSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if s.patientID != "" {
+		ctx = repository.WithPatient(ctx, s.patientID)
+	}
+
+	start := end.Add(-s.night)
+	measurements, err := s.glucoseService.GetMeasurementsByTimeRange(ctx, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get night measurements: %w", err)
+	}
+
+	summary := &domain.MorningSummary{
+		Start:            start,
+		End:              end,
+		Count:            len(measurements),
+		LowThresholdMgDl: domain.DefaultLowAlertMgDl,
+	}
+
+	sort.Slice(measurements, func(i, j int) bool {
+		return measurements[i].Timestamp.Before(measurements[j].Timestamp)
+	})
+
+	for i, m := range measurements {
+		if i == 0 || m.ValueInMgPerDl < summary.MinMgDl {
+			summary.Min, summary.MinMgDl = m.Value, m.ValueInMgPerDl
+		}
+		if i == 0 || m.ValueInMgPerDl > summary.MaxMgDl {
+			summary.Max, summary.MaxMgDl = m.Value, m.ValueInMgPerDl
+		}
+	}
+	summary.TimeLowMinutes = int(timeBelow(measurements, summary.LowThresholdMgDl, end) / time.Minute)
+
+	current, err := s.glucoseService.GetLatestMeasurement(ctx)
+	if err == nil {
+		summary.Current = current
+	}
+
+	return summary, nil
+}
+
+// timeBelow returns the time spent below threshold, each reading accounting
+// for the time until the next one (at most maxReadingGap).
+// measurements must be sorted by timestamp.
+func timeBelow(measurements []*domain.GlucoseMeasurement, threshold int, end time.Time) time.Duration {
+	var total time.Duration
+	for i, m := range measurements {
+		if m.ValueInMgPerDl >= threshold {
+			continue
+		}
+		next := end
+		if i+1 < len(measurements) {
+			next = measurements[i+1].Timestamp
+		}
+		total += min(next.Sub(m.Timestamp), maxReadingGap)
+	}
+	return total
+}
@@ -0,0 +1,140 @@
+package summary
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/domain"
+	"github.com/R4yL-dev/glcmd/internal/persistence"
+	"github.com/R4yL-dev/glcmd/internal/repository"
+	"github.com/R4yL-dev/glcmd/internal/service"
+)
+
+// fakeGlucoseService serves fixed measurements, scoped to the patient of ctx.
+type fakeGlucoseService struct {
+	service.GlucoseService
+	measurements []*domain.GlucoseMeasurement
+}
+
+func (f *fakeGlucoseService) patientMeasurements(ctx context.Context) []*domain.GlucoseMeasurement {
+	patientID := repository.PatientFromContext(ctx)
+	var result []*domain.GlucoseMeasurement
+	for _, m := range f.measurements {
+		if patientID == "" || m.PatientID == patientID {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+func (f *fakeGlucoseService) GetMeasurementsByTimeRange(ctx context.Context, start, end time.Time) ([]*domain.GlucoseMeasurement, error) {
+	var result []*domain.GlucoseMeasurement
+	for _, m := range f.patientMeasurements(ctx) {
+		if !m.Timestamp.Before(start) && !m.Timestamp.After(end) {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeGlucoseService) GetLatestMeasurement(ctx context.Context) (*domain.GlucoseMeasurement, error) {
+	measurements := f.patientMeasurements(ctx)
+	if len(measurements) == 0 {
+		return nil, persistence.ErrNotFound
+	}
+	return measurements[len(measurements)-1], nil
+}
+
+func TestSpec(t *testing.T) {
+	tests := []struct {
+		at   time.Duration
+		want string
+	}{
+		{7 * time.Hour, "0 7 * * *"},
+		{6*time.Hour + 45*time.Minute, "45 6 * * *"},
+		{0, "0 0 * * *"},
+	}
+
+	for _, tt := range tests {
+		s := NewScheduler(tt.at, 8*time.Hour, "", nil, nil, slog.Default())
+		if got := s.Spec(); got != tt.want {
+			t.Errorf("Spec() for %v = %q, want %q", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	end := time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+	reading := func(ago time.Duration, mgdl int) *domain.GlucoseMeasurement {
+		return &domain.GlucoseMeasurement{
+			Timestamp:      end.Add(-ago),
+			ValueInMgPerDl: mgdl,
+			Value:          float64(mgdl) / 18.0,
+		}
+	}
+
+	glucose := &fakeGlucoseService{measurements: []*domain.GlucoseMeasurement{
+		reading(9*time.Hour, 50), // Before the night
+		reading(6*time.Hour, 120),
+		reading(5*time.Hour, 65), // Low until next reading, capped at 15 min
+		reading(4*time.Hour, 60), // Low for 15 min
+		reading(4*time.Hour-15*time.Minute, 90),
+		reading(time.Hour, 180),
+	}}
+
+	s := NewScheduler(7*time.Hour, 8*time.Hour, "", glucose, nil, slog.Default())
+	summary, err := s.Build(context.Background(), end)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	if summary.Count != 5 {
+		t.Errorf("expected 5 night measurements, got %d", summary.Count)
+	}
+	if summary.MinMgDl != 60 || summary.MaxMgDl != 180 {
+		t.Errorf("expected min 60 max 180, got %d %d", summary.MinMgDl, summary.MaxMgDl)
+	}
+	if summary.TimeLowMinutes != 30 {
+		t.Errorf("expected 30 minutes low, got %d", summary.TimeLowMinutes)
+	}
+	if summary.Current == nil || summary.Current.ValueInMgPerDl != 180 {
+		t.Errorf("expected current 180, got %+v", summary.Current)
+	}
+	if !summary.Start.Equal(end.Add(-8 * time.Hour)) {
+		t.Errorf("expected night start %v, got %v", end.Add(-8*time.Hour), summary.Start)
+	}
+}
+
+func TestBuild_Patient(t *testing.T) {
+	end := time.Date(2026, 1, 15, 7, 0, 0, 0, time.UTC)
+	reading := func(patientID string, before time.Duration, mgdl int) *domain.GlucoseMeasurement {
+		return &domain.GlucoseMeasurement{
+			PatientID:      patientID,
+			Timestamp:      end.Add(-before),
+			ValueInMgPerDl: mgdl,
+			Value:          float64(mgdl) / 18.0,
+		}
+	}
+
+	glucose := &fakeGlucoseService{measurements: []*domain.GlucoseMeasurement{
+		reading("patient-a", 3*time.Hour, 110),
+		reading("patient-b", 2*time.Hour, 55),
+		reading("patient-a", time.Hour, 130),
+		reading("patient-b", 0, 60),
+	}}
+
+	// Only the readings of the followed patient are summarized
+	s := NewScheduler(7*time.Hour, 8*time.Hour, "patient-a", glucose, nil, slog.Default())
+	summary, err := s.Build(context.Background(), end)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if summary.Count != 2 || summary.MinMgDl != 110 || summary.TimeLowMinutes != 0 {
+		t.Errorf("expected the 2 readings of patient a, got %+v", summary)
+	}
+	if summary.Current == nil || summary.Current.ValueInMgPerDl != 130 {
+		t.Errorf("expected current 130, got %+v", summary.Current)
+	}
+}
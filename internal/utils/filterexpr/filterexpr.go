@@ -0,0 +1,297 @@
+// Package filterexpr parses filter expressions such as
+// "value_mgdl > 180 AND hour in 0..6" and translates them to parameterized SQL.
+//
+// Grammar (keywords are case-insensitive):
+//
+//	expr       = and { "OR" and }
+//	and        = unary { "AND" unary }
+//	unary      = "NOT" unary | "(" expr ")" | condition
+//	condition  = field ( "=" | "!=" | "<" | "<=" | ">" | ">=" ) number
+//	           | field [ "NOT" ] "IN" number ".." number
+//
+// Fields are restricted to those declared by the caller, and values are
+// always passed as query arguments, so an expression cannot inject SQL.
+package filterexpr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Limits protecting the database from pathological expressions
+const (
+	// MaxLength is the maximum length of an expression.
+	MaxLength = 500
+	// MaxConditions is the maximum number of conditions in an expression.
+	MaxConditions = 20
+)
+
+// Kind is the type of value a field accepts.
+type Kind int
+
+const (
+	// Int fields accept integer values only.
+	Int Kind = iota
+	// Float fields accept integer and decimal values.
+	Float
+)
+
+// Expr is a parsed filter expression.
+type Expr struct {
+	root   node
+	source string
+}
+
+// String returns the expression as it was parsed.
+func (e *Expr) String() string {
+	return e.source
+}
+
+// SQL returns the expression as a SQL condition with ? placeholders and its arguments.
+// column returns the SQL expression of a field; it is only called with declared fields.
+func (e *Expr) SQL(column func(field string) string) (string, []any) {
+	var args []any
+	return e.root.sql(column, &args), args
+}
+
+// Parse parses an expression. fields declares the allowed field names and their kind.
+func Parse(input string, fields map[string]Kind) (*Expr, error) {
+	if len(input) > MaxLength {
+		return nil, fmt.Errorf("expression must not exceed %d characters", MaxLength)
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+
+	return &Expr{root: root, source: input}, nil
+}
+
+// node is an element of the expression tree.
+type node interface {
+	sql(column func(string) string, args *[]any) string
+}
+
+type logicalNode struct {
+	op          string // AND or OR
+	left, right node
+}
+
+func (n *logicalNode) sql(column func(string) string, args *[]any) string {
+	left := n.left.sql(column, args)
+	right := n.right.sql(column, args)
+	return "(" + left + " " + n.op + " " + right + ")"
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) sql(column func(string) string, args *[]any) string {
+	return "NOT " + n.operand.sql(column, args)
+}
+
+type comparisonNode struct {
+	field    string
+	operator string
+	value    any
+}
+
+func (n *comparisonNode) sql(column func(string) string, args *[]any) string {
+	*args = append(*args, n.value)
+	operator := n.operator
+	if operator == "!=" {
+		operator = "<>"
+	}
+	return "(" + column(n.field) + " " + operator + " ?)"
+}
+
+type rangeNode struct {
+	field     string
+	low, high any
+}
+
+func (n *rangeNode) sql(column func(string) string, args *[]any) string {
+	*args = append(*args, n.low, n.high)
+	return "(" + column(n.field) + " BETWEEN ? AND ?)"
+}
+
+// parser is a recursive descent parser over the tokens of an expression.
+type parser struct {
+	tokens     []token
+	pos        int
+	fields     map[string]Kind
+	conditions int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// keyword reports whether the next token is the given keyword, consuming it if so.
+func (p *parser) keyword(word string) bool {
+	tok := p.peek()
+	if tok.kind == tokenIdent && strings.EqualFold(tok.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.keyword("NOT") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ) at position %d", tok.pos+1)
+		}
+		return inner, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *parser) parseCondition() (node, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return nil, fmt.Errorf("expected a field at position %d", tok.pos+1)
+	}
+	field := strings.ToLower(tok.text)
+	kind, ok := p.fields[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q (use %s)", tok.text, strings.Join(p.fieldNames(), ", "))
+	}
+
+	p.conditions++
+	if p.conditions > MaxConditions {
+		return nil, fmt.Errorf("expression must not have more than %d conditions", MaxConditions)
+	}
+
+	negated := p.keyword("NOT")
+	if p.keyword("IN") {
+		low, err := p.parseValue(field, kind)
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenRange {
+			return nil, fmt.Errorf("expected .. at position %d", tok.pos+1)
+		}
+		high, err := p.parseValue(field, kind)
+		if err != nil {
+			return nil, err
+		}
+
+		var n node = &rangeNode{field: field, low: low, high: high}
+		if negated {
+			n = &notNode{operand: n}
+		}
+		return n, nil
+	}
+	if negated {
+		return nil, fmt.Errorf("expected IN after NOT at position %d", p.peek().pos+1)
+	}
+
+	op := p.next()
+	if op.kind != tokenOperator {
+		return nil, fmt.Errorf("expected an operator after %s at position %d", field, op.pos+1)
+	}
+	value, err := p.parseValue(field, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return &comparisonNode{field: field, operator: op.text, value: value}, nil
+}
+
+// parseValue parses a number of the kind of field.
+func (p *parser) parseValue(field string, kind Kind) (any, error) {
+	tok := p.next()
+	if tok.kind != tokenNumber {
+		return nil, fmt.Errorf("expected a number at position %d", tok.pos+1)
+	}
+
+	if kind == Int {
+		value, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects an integer, got %s", field, tok.text)
+		}
+		return value, nil
+	}
+
+	value, err := strconv.ParseFloat(tok.text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %s", tok.text)
+	}
+	return value, nil
+}
+
+// fieldNames returns the declared fields, sorted.
+func (p *parser) fieldNames() []string {
+	names := make([]string, 0, len(p.fields))
+	for name := range p.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,116 @@
+package filterexpr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testFields = map[string]Kind{
+	"value_mgdl": Int,
+	"value_mmol": Float,
+	"hour":       Int,
+}
+
+// testColumn maps fields to SQL, marking them so tests can check the mapping
+func testColumn(field string) string {
+	return "col_" + field
+}
+
+func TestParse_SQL(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			input:    "value_mgdl > 180",
+			wantSQL:  "(col_value_mgdl > ?)",
+			wantArgs: []any{int64(180)},
+		},
+		{
+			input:    "value_mgdl>180 AND hour in 0..6",
+			wantSQL:  "((col_value_mgdl > ?) AND (col_hour BETWEEN ? AND ?))",
+			wantArgs: []any{int64(180), int64(0), int64(6)},
+		},
+		{
+			input:    "value_mmol < 3.9 or value_mmol >= 10",
+			wantSQL:  "((col_value_mmol < ?) OR (col_value_mmol >= ?))",
+			wantArgs: []any{3.9, 10.0},
+		},
+		{
+			// AND binds tighter than OR
+			input:    "hour = 1 OR hour = 2 AND value_mgdl != 100",
+			wantSQL:  "((col_hour = ?) OR ((col_hour = ?) AND (col_value_mgdl <> ?)))",
+			wantArgs: []any{int64(1), int64(2), int64(100)},
+		},
+		{
+			input:    "(hour = 1 OR hour = 2) AND NOT value_mgdl <= 70",
+			wantSQL:  "(((col_hour = ?) OR (col_hour = ?)) AND NOT (col_value_mgdl <= ?))",
+			wantArgs: []any{int64(1), int64(2), int64(70)},
+		},
+		{
+			input:    "HOUR not in 8..18",
+			wantSQL:  "NOT (col_hour BETWEEN ? AND ?)",
+			wantArgs: []any{int64(8), int64(18)},
+		},
+		{
+			input:    "value_mmol in -1..2.5",
+			wantSQL:  "(col_value_mmol BETWEEN ? AND ?)",
+			wantArgs: []any{-1.0, 2.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := Parse(tt.input, testFields)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			sql, args := expr.SQL(testColumn)
+			if sql != tt.wantSQL {
+				t.Errorf("expected SQL %q, got %q", tt.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("expected args %v, got %v", tt.wantArgs, args)
+			}
+			if expr.String() != tt.input {
+				t.Errorf("expected String() = %q, got %q", tt.input, expr.String())
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{"", "expected a field"},
+		{"glucose > 180", "unknown field"},
+		{"value_mgdl > 180.5", "expects an integer"},
+		{"value_mgdl >", "expected a number"},
+		{"value_mgdl 180", "expected an operator"},
+		{"hour in 0-6", "expected .."},
+		{"hour not = 1", "expected IN after NOT"},
+		{"(hour = 1", "expected )"},
+		{"hour = 1 hour = 2", "unexpected"},
+		{"hour = 1; DROP TABLE glucose_measurements", "unexpected character"},
+		{"value_mgdl = '1'", "unexpected character"},
+		{strings.Repeat("hour = 1 OR ", MaxConditions) + "hour = 1", "conditions"},
+		{strings.Repeat(" ", MaxLength+1), "must not exceed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input, testFields)
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, err)
+			}
+		})
+	}
+}
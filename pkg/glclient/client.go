@@ -0,0 +1,193 @@
+package glclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client is a read-only client of the glcore REST API and event stream.
+//
+// It only uses net/http, so it also builds for GOOS=js GOARCH=wasm, where
+// requests go through the browser's fetch API (glcore answers CORS requests).
+type Client struct {
+	BaseURL    string       // e.g. http://localhost:8080
+	Token      string       // API token sent as a bearer token ("" = none)
+	HTTPClient *http.Client // nil = http.DefaultClient
+}
+
+// NewClient creates a Client for the glcore server at baseURL.
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// APIError is an error response of the API.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("glcore API error %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 response, e.g. no measurement stored yet.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// GlucoseQuery selects a page of GET /v1/glucose. Zero values are omitted.
+type GlucoseQuery struct {
+	Start  time.Time
+	End    time.Time
+	Limit  int // 1-1000, server default 100
+	Offset int
+}
+
+// Latest returns the most recent measurement.
+func (c *Client) Latest(ctx context.Context) (*Measurement, error) {
+	var response struct {
+		Data *Measurement `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/glucose/latest", &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// Glucose returns a page of measurements, newest first.
+func (c *Client) Glucose(ctx context.Context, query GlucoseQuery) ([]Measurement, Pagination, error) {
+	params := url.Values{}
+	if !query.Start.IsZero() {
+		params.Set("start", query.Start.UTC().Format(time.RFC3339))
+	}
+	if !query.End.IsZero() {
+		params.Set("end", query.End.UTC().Format(time.RFC3339))
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if query.Offset > 0 {
+		params.Set("offset", strconv.Itoa(query.Offset))
+	}
+
+	path := "/v1/glucose"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	var response struct {
+		Data       []Measurement `json:"data"`
+		Pagination Pagination    `json:"pagination"`
+	}
+	if err := c.get(ctx, path, &response); err != nil {
+		return nil, Pagination{}, err
+	}
+	return response.Data, response.Pagination, nil
+}
+
+// LatestSensor returns the current sensor.
+func (c *Client) LatestSensor(ctx context.Context) (*Sensor, error) {
+	var response struct {
+		Data *Sensor `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/sensor/latest", &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// Capabilities returns the features enabled on the server.
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	var response struct {
+		Data *Capabilities `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/capabilities", &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// Health returns the server status. A degraded or unhealthy server answers
+// 503 with its status, which is returned without error.
+func (c *Client) Health(ctx context.Context) (*Health, error) {
+	resp, err := c.do(ctx, "/health", "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, newAPIError(resp)
+	}
+
+	var response struct {
+		Data *Health `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil || response.Data == nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		}
+		return nil, fmt.Errorf("failed to decode health response: %w", err)
+	}
+	return response.Data, nil
+}
+
+// get sends a GET request and decodes the JSON response into v.
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	resp, err := c.do(ctx, path, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %w", path, err)
+	}
+	return nil
+}
+
+// do sends a GET request with the schema version and token headers.
+func (c *Client) do(ctx context.Context, path, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set(APIVersionHeader, strconv.Itoa(SchemaVersion))
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return httpClient.Do(req)
+}
+
+// newAPIError reads the error message of a non-2xx response.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body); err == nil && body.Error.Message != "" {
+		apiErr.Message = body.Error.Message
+	}
+	return apiErr
+}
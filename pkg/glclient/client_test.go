@@ -0,0 +1,112 @@
+package glclient_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
+	"github.com/R4yL-dev/glcmd/pkg/glclienttest"
+)
+
+func TestClient_ReadEndpoints(t *testing.T) {
+	now := time.Now()
+	server := glclienttest.NewServer(glclienttest.DefaultFixtures(now))
+	defer server.Close()
+	server.RequireToken("read-token-0123456789")
+
+	client := glclient.NewClient(server.URL + "/")
+	ctx := context.Background()
+
+	var apiErr *glclient.APIError
+	if _, err := client.Latest(ctx); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %v", err)
+	}
+
+	client.Token = "read-token-0123456789"
+	latest, err := client.Latest(ctx)
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if latest.ValueInMgPerDl != 155 || latest.TrendArrow == nil || *latest.TrendArrow != 4 {
+		t.Errorf("unexpected latest measurement: %+v", latest)
+	}
+
+	page, pagination, err := client.Glucose(ctx, glclient.GlucoseQuery{Start: now.Add(-30 * time.Minute), Limit: 3})
+	if err != nil {
+		t.Fatalf("Glucose: %v", err)
+	}
+	if len(page) != 3 || !pagination.HasMore || page[0].ValueInMgPerDl != 155 {
+		t.Errorf("unexpected page: %+v %+v", page, pagination)
+	}
+
+	sensor, err := client.LatestSensor(ctx)
+	if err != nil {
+		t.Fatalf("LatestSensor: %v", err)
+	}
+	if sensor.SerialNumber != "0TEST00001" || sensor.Status != "running" {
+		t.Errorf("unexpected sensor: %+v", sensor)
+	}
+
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		t.Fatalf("Capabilities: %v", err)
+	}
+	if !capabilities.Features["auth"].Enabled || capabilities.SchemaVersion != glclient.SchemaVersion {
+		t.Errorf("unexpected capabilities: %+v", capabilities)
+	}
+
+	health, err := client.Health(ctx)
+	if err != nil || health.Status != "healthy" {
+		t.Errorf("expected healthy status, got %+v (%v)", health, err)
+	}
+
+	server.SetFixtures(glclienttest.Fixtures{})
+	if _, err := client.Latest(ctx); !glclient.IsNotFound(err) {
+		t.Errorf("expected not found without readings, got %v", err)
+	}
+}
+
+func TestClient_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/stream" || r.URL.Query().Get("types") != "glucose,sensor" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: keepalive\ndata: {}\n\n")
+		fmt.Fprint(w, "event: glucose\nsignature: t=1,kid=k,v1=00\ndata: {\"valueInMgPerDl\":123}\n\n")
+		fmt.Fprint(w, "event: sensor\ndata: {\"serialNumber\":\"S1\"}\n\n")
+	}))
+	defer server.Close()
+
+	var received []glclient.Event
+	err := glclient.NewClient(server.URL).Stream(context.Background(), []string{"glucose", "sensor"}, func(e glclient.Event) error {
+		received = append(received, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if len(received) != 3 || received[1].Type != "glucose" || received[1].Signature != "t=1,kid=k,v1=00" {
+		t.Fatalf("unexpected events: %+v", received)
+	}
+
+	m, err := received[1].Measurement()
+	if err != nil || m.ValueInMgPerDl != 123 {
+		t.Errorf("expected 123 mg/dL, got %+v (%v)", m, err)
+	}
+
+	// An error of the callback stops the stream
+	stop := errors.New("stop")
+	err = glclient.NewClient(server.URL).Stream(context.Background(), []string{"glucose", "sensor"}, func(e glclient.Event) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+// Package glclient is a read-only client for the glcore API, with the types
+// shared with third-party clients.
+//
+// It depends only on the standard library so it can be vendored into small
+// consumers (embedded displays, browser builds) without pulling in the server.
+package glclient
+
+import (
+	"fmt"
+	"time"
+)
+
+// EncodingDelta is the value of the "encoding" query parameter selecting the
+// compact delta encoding, and of DeltaSeries.Encoding.
+const EncodingDelta = "delta"
+
+// Point is a single decoded glucose reading.
+type Point struct {
+	Timestamp      time.Time
+	ValueInMgPerDl int
+	TrendArrow     int // 0 when unknown (historical readings)
+}
+
+// DeltaSeries is the compact form of a list of glucose readings.
+//
+// Timestamps are stored as seconds relative to the previous reading (the
+// first one relative to Base) and values as the mg/dL difference from the
+// previous reading (the first one relative to zero). Readings are 1 to 15
+// minutes apart and rarely move by more than a few mg/dL, so the arrays stay
+// made of small integers that compress well and parse quickly.
+type DeltaSeries struct {
+	Encoding string  `json:"encoding"`
+	Base     int64   `json:"base"`  // Unix seconds of the first reading
+	Times    []int64 `json:"t"`     // Seconds since the previous reading
+	Values   []int   `json:"v"`     // mg/dL change since the previous reading
+	Trends   []int   `json:"trend"` // Absolute trend arrows (0 = unknown)
+}
+
+// EncodeDelta builds a DeltaSeries from readings, keeping their order.
+func EncodeDelta(points []Point) *DeltaSeries {
+	series := &DeltaSeries{
+		Encoding: EncodingDelta,
+		Times:    make([]int64, len(points)),
+		Values:   make([]int, len(points)),
+		Trends:   make([]int, len(points)),
+	}
+	if len(points) == 0 {
+		return series
+	}
+
+	series.Base = points[0].Timestamp.Unix()
+	prevTime, prevValue := series.Base, 0
+	for i, p := range points {
+		ts := p.Timestamp.Unix()
+		series.Times[i] = ts - prevTime
+		series.Values[i] = p.ValueInMgPerDl - prevValue
+		series.Trends[i] = p.TrendArrow
+		prevTime, prevValue = ts, p.ValueInMgPerDl
+	}
+
+	return series
+}
+
+// Decode expands the series back into readings with UTC timestamps.
+func (s *DeltaSeries) Decode() ([]Point, error) {
+	if s.Encoding != EncodingDelta {
+		return nil, fmt.Errorf("unsupported encoding %q", s.Encoding)
+	}
+	if len(s.Values) != len(s.Times) || len(s.Trends) != len(s.Times) {
+		return nil, fmt.Errorf("malformed delta series: %d times, %d values, %d trends",
+			len(s.Times), len(s.Values), len(s.Trends))
+	}
+
+	points := make([]Point, len(s.Times))
+	ts, value := s.Base, 0
+	for i := range s.Times {
+		ts += s.Times[i]
+		value += s.Values[i]
+		points[i] = Point{
+			Timestamp:      time.Unix(ts, 0).UTC(),
+			ValueInMgPerDl: value,
+			TrendArrow:     s.Trends[i],
+		}
+	}
+
+	return points, nil
+}
@@ -0,0 +1,75 @@
+package glclient
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDeltaSeries_RoundTrip(t *testing.T) {
+	base := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	points := []Point{
+		{Timestamp: base, ValueInMgPerDl: 120, TrendArrow: 3},
+		{Timestamp: base.Add(-5 * time.Minute), ValueInMgPerDl: 118, TrendArrow: 0},
+		{Timestamp: base.Add(-10 * time.Minute), ValueInMgPerDl: 125, TrendArrow: 0},
+	}
+
+	series := EncodeDelta(points)
+	if series.Base != base.Unix() {
+		t.Errorf("expected base %d, got %d", base.Unix(), series.Base)
+	}
+	if series.Times[1] != -300 || series.Values[1] != -2 {
+		t.Errorf("unexpected deltas: t=%v v=%v", series.Times, series.Values)
+	}
+
+	// Decode after a JSON round trip, as a client would
+	raw, err := json.Marshal(series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded DeltaSeries
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := decoded.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(got) != len(points) {
+		t.Fatalf("expected %d points, got %d", len(points), len(got))
+	}
+	for i := range points {
+		if !got[i].Timestamp.Equal(points[i].Timestamp) || got[i].ValueInMgPerDl != points[i].ValueInMgPerDl || got[i].TrendArrow != points[i].TrendArrow {
+			t.Errorf("point %d: expected %+v, got %+v", i, points[i], got[i])
+		}
+	}
+}
+
+func TestDeltaSeries_Empty(t *testing.T) {
+	got, err := EncodeDelta(nil).Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no points, got %d", len(got))
+	}
+}
+
+func TestDeltaSeries_DecodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		series DeltaSeries
+	}{
+		{"wrong encoding", DeltaSeries{Encoding: "gzip"}},
+		{"length mismatch", DeltaSeries{Encoding: EncodingDelta, Times: []int64{0, 60}, Values: []int{100}, Trends: []int{0, 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.series.Decode(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
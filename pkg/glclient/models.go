@@ -0,0 +1,72 @@
+package glclient
+
+import "time"
+
+// Measurement is a glucose measurement as returned by /v1/glucose/latest and /v1/glucose.
+type Measurement struct {
+	FactoryTimestamp time.Time `json:"factoryTimestamp"`
+	Timestamp        time.Time `json:"timestamp"`
+	Value            float64   `json:"value"` // mmol/L
+	ValueInMgPerDl   int       `json:"valueInMgPerDl"`
+	TrendArrow       *int      `json:"trendArrow,omitempty"` // 1-5, nil for historical readings
+	TrendMessage     *string   `json:"trendMessage,omitempty"`
+	MeasurementColor int       `json:"measurementColor"` // 1=normal, 2=warning, 3=critical
+	GlucoseUnits     int       `json:"glucoseUnits"`     // 0=mmol/L, 1=mg/dL
+	IsHigh           bool      `json:"isHigh"`
+	IsLow            bool      `json:"isLow"`
+	Type             int       `json:"type"` // 0=historical, 1=current
+}
+
+// Sensor is a sensor as returned by /v1/sensor/latest.
+type Sensor struct {
+	SerialNumber      string   `json:"serialNumber"`
+	Activation        string   `json:"activation"`
+	ExpiresAt         string   `json:"expiresAt"`
+	EndedAt           *string  `json:"endedAt,omitempty"`
+	LastMeasurementAt *string  `json:"lastMeasurementAt,omitempty"`
+	SensorType        int      `json:"sensorType"`
+	DurationDays      int      `json:"durationDays"`
+	DaysRemaining     *float64 `json:"daysRemaining,omitempty"`
+	DaysElapsed       float64  `json:"daysElapsed"`
+	ActualDays        *float64 `json:"actualDays,omitempty"`
+	Status            string   `json:"status"` // running, grace, expired or ended
+	ApplicationSite   string   `json:"applicationSite,omitempty"`
+	Note              string   `json:"note,omitempty"`
+	Rating            *int     `json:"rating,omitempty"`
+}
+
+// Health is the status returned by /health.
+type Health struct {
+	Status            string    `json:"status"` // healthy, degraded or unhealthy
+	Timestamp         time.Time `json:"timestamp"`
+	Uptime            string    `json:"uptime"`
+	ConsecutiveErrors int       `json:"consecutiveErrors"`
+	LastFetchError    string    `json:"lastFetchError"`
+	LastFetchTime     time.Time `json:"lastFetchTime"`
+	DatabaseConnected bool      `json:"databaseConnected"`
+	DataFresh         bool      `json:"dataFresh"`
+	SensorExpired     bool      `json:"sensorExpired"`
+	SensorInGrace     bool      `json:"sensorInGrace"`
+}
+
+// Capability is the state of an optional feature.
+type Capability struct {
+	Enabled bool `json:"enabled"`
+	Version int  `json:"version,omitempty"`
+}
+
+// Capabilities is the feature list returned by /v1/capabilities.
+type Capabilities struct {
+	APIVersion     string                `json:"apiVersion"`
+	SchemaVersion  int                   `json:"schemaVersion"`
+	SchemaVersions []int                 `json:"schemaVersions"`
+	Features       map[string]Capability `json:"features"`
+}
+
+// Pagination describes a page of a list response.
+type Pagination struct {
+	Limit   int   `json:"limit"`
+	Offset  int   `json:"offset"`
+	Total   int64 `json:"total"`
+	HasMore bool  `json:"hasMore"`
+}
@@ -0,0 +1,125 @@
+package glclient
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the HTTP header carrying the signature of a pushed payload.
+// On the SSE stream the same value is sent as a "signature:" field of the event.
+const SignatureHeader = "X-Glcmd-Signature"
+
+// signatureScheme names the MAC algorithm in the signature value.
+const signatureScheme = "v1"
+
+var (
+	// ErrSignatureMalformed is returned when a signature value cannot be parsed.
+	ErrSignatureMalformed = errors.New("malformed signature")
+
+	// ErrSignatureUnknownKey is returned when the signing key is not among the known secrets.
+	ErrSignatureUnknownKey = errors.New("signature key not known")
+
+	// ErrSignatureMismatch is returned when the MAC does not match the payload.
+	ErrSignatureMismatch = errors.New("signature does not match payload")
+
+	// ErrSignatureExpired is returned when the signature timestamp is outside the tolerance.
+	ErrSignatureExpired = errors.New("signature timestamp outside tolerance")
+)
+
+// Signature is a parsed signature value.
+type Signature struct {
+	Timestamp time.Time
+	KeyID     string
+	MAC       []byte
+}
+
+// Sign computes the signature value of a payload:
+//
+//	t=<unix seconds>,kid=<key id>,v1=<hex HMAC-SHA256>
+//
+// The MAC covers "<t>.<eventType>.<payload>", so a payload cannot be replayed
+// as another event type, and the timestamp lets consumers reject old replays.
+func Sign(secret, keyID string, timestamp time.Time, eventType string, payload []byte) string {
+	t := timestamp.Unix()
+	mac := computeMAC(secret, t, eventType, payload)
+	return fmt.Sprintf("t=%d,kid=%s,%s=%s", t, keyID, signatureScheme, hex.EncodeToString(mac))
+}
+
+// ParseSignature parses a signature value produced by Sign.
+func ParseSignature(value string) (*Signature, error) {
+	sig := &Signature{}
+	var hasTime bool
+
+	for _, part := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, ErrSignatureMalformed
+		}
+
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return nil, ErrSignatureMalformed
+			}
+			sig.Timestamp = time.Unix(t, 0).UTC()
+			hasTime = true
+		case "kid":
+			sig.KeyID = val
+		case signatureScheme:
+			mac, err := hex.DecodeString(val)
+			if err != nil {
+				return nil, ErrSignatureMalformed
+			}
+			sig.MAC = mac
+		}
+	}
+
+	if !hasTime || sig.KeyID == "" || sig.MAC == nil {
+		return nil, ErrSignatureMalformed
+	}
+	return sig, nil
+}
+
+// Verify checks a signature value against a payload. secrets maps key IDs to
+// secrets, so consumers can accept both the old and the new key during a
+// rotation. A tolerance of zero disables the timestamp check.
+func Verify(value string, secrets map[string]string, eventType string, payload []byte, tolerance time.Duration) error {
+	sig, err := ParseSignature(value)
+	if err != nil {
+		return err
+	}
+
+	secret, ok := secrets[sig.KeyID]
+	if !ok {
+		return ErrSignatureUnknownKey
+	}
+
+	expected := computeMAC(secret, sig.Timestamp.Unix(), eventType, payload)
+	if !hmac.Equal(expected, sig.MAC) {
+		return ErrSignatureMismatch
+	}
+
+	if tolerance > 0 {
+		age := time.Since(sig.Timestamp)
+		if age > tolerance || age < -tolerance {
+			return ErrSignatureExpired
+		}
+	}
+
+	return nil
+}
+
+// computeMAC returns HMAC-SHA256(secret, "<t>.<eventType>.<payload>").
+func computeMAC(secret string, t int64, eventType string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s.", t, eventType)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,52 @@
+package glclient
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	payload := []byte(`{"valueInMgPerDl":110}`)
+	secrets := map[string]string{"k1": "old-secret", "k2": "new-secret"}
+
+	value := Sign("new-secret", "k2", time.Now(), "glucose", payload)
+	if err := Verify(value, secrets, "glucose", payload, time.Minute); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	// Old key still accepted during rotation
+	value = Sign("old-secret", "k1", time.Now(), "glucose", payload)
+	if err := Verify(value, secrets, "glucose", payload, time.Minute); err != nil {
+		t.Errorf("Verify with previous key: %v", err)
+	}
+}
+
+func TestVerify_Errors(t *testing.T) {
+	payload := []byte(`{"valueInMgPerDl":110}`)
+	secrets := map[string]string{"k1": "secret"}
+	valid := Sign("secret", "k1", time.Now(), "glucose", payload)
+
+	tests := []struct {
+		name      string
+		value     string
+		eventType string
+		payload   []byte
+		want      error
+	}{
+		{"malformed", "garbage", "glucose", payload, ErrSignatureMalformed},
+		{"unknown key", Sign("secret", "k9", time.Now(), "glucose", payload), "glucose", payload, ErrSignatureUnknownKey},
+		{"tampered payload", valid, "glucose", []byte(`{"valueInMgPerDl":250}`), ErrSignatureMismatch},
+		{"other event type", valid, "sensor", payload, ErrSignatureMismatch},
+		{"too old", Sign("secret", "k1", time.Now().Add(-time.Hour), "glucose", payload), "glucose", payload, ErrSignatureExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.value, secrets, tt.eventType, tt.payload, 5*time.Minute)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
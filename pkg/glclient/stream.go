@@ -0,0 +1,82 @@
+package glclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Event is an event of the /v1/stream server-sent event stream.
+type Event struct {
+	Type      string          // glucose, sensor, summary, config or keepalive
+	Data      json.RawMessage // JSON payload, e.g. a Measurement for glucose events
+	Signature string          // Set when the stream is signed, see Verify
+}
+
+// Measurement decodes the payload of a glucose event.
+func (e Event) Measurement() (*Measurement, error) {
+	var m Measurement
+	if err := json.Unmarshal(e.Data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Stream subscribes to the event stream and calls fn for each event of the
+// given types (none = all) until ctx is cancelled, fn returns an error or the
+// server closes the stream. It does not reconnect.
+func (c *Client) Stream(ctx context.Context, types []string, fn func(Event) error) error {
+	path := "/v1/stream"
+	if len(types) > 0 {
+		path += "?" + url.Values{"types": {strings.Join(types, ",")}}.Encode()
+	}
+
+	resp, err := c.do(ctx, path, "text/event-stream")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+
+	var event Event
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// A blank line ends the event
+			if event.Type != "" {
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+			event = Event{}
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Type = value
+		case "data":
+			if len(event.Data) > 0 {
+				event.Data = append(event.Data, '\n')
+			}
+			event.Data = append(event.Data, value...)
+		case "signature":
+			event.Signature = value
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,12 @@
+package glclient
+
+// APIVersionHeader is the request header selecting the response schema version.
+// The server echoes the version it used in the same response header.
+const APIVersionHeader = "X-GLCMD-API-Version"
+
+// SchemaVersion is the response schema version implemented by this package.
+//
+// Version history:
+//   - 1: responses of glcore 0.7.1; requests without the header get this schema
+//   - 2: sensor status may be "grace" (expired but still reporting)
+const SchemaVersion = 2
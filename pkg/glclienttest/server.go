@@ -0,0 +1,328 @@
+// Package glclienttest provides an in-memory fake of the glcore HTTP API for
+// testing third-party clients without running glcore, in the spirit of
+// net/http/httptest.
+//
+// The fake serves canned fixture data on the read endpoints most integrations
+// use: /health, /v1/capabilities, /v1/glucose/latest, /v1/glucose and
+// /v1/sensor/latest. Responses follow the current schema version
+// (glclient.SchemaVersion). Like glclient, it depends only on the standard
+// library.
+package glclienttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
+)
+
+// Sensor is the sensor fixture reported by /v1/sensor/latest.
+type Sensor struct {
+	SerialNumber string
+	Activation   time.Time
+	DurationDays int
+}
+
+// Fixtures is the data served by a Server.
+type Fixtures struct {
+	Readings []glclient.Point // In any order; the most recent is the latest reading
+	Sensor   *Sensor          // nil = no sensor (404)
+}
+
+// DefaultFixtures returns a sensor activated three days before now and one
+// hour of readings every 5 minutes, rising from 100 to 155 mg/dL.
+func DefaultFixtures(now time.Time) Fixtures {
+	now = now.UTC().Truncate(time.Minute)
+
+	readings := make([]glclient.Point, 12)
+	for i := range readings {
+		readings[i] = glclient.Point{
+			Timestamp:      now.Add(time.Duration(i-11) * 5 * time.Minute),
+			ValueInMgPerDl: 100 + 5*i,
+			TrendArrow:     4, // Rising
+		}
+	}
+
+	return Fixtures{
+		Readings: readings,
+		Sensor: &Sensor{
+			SerialNumber: "0TEST00001",
+			Activation:   now.Add(-72 * time.Hour),
+			DurationDays: 15,
+		},
+	}
+}
+
+// Server is a fake glcore API listening on a loopback address.
+type Server struct {
+	URL string // Base URL, e.g. http://127.0.0.1:50000
+
+	server *httptest.Server
+	mu     sync.RWMutex
+	data   Fixtures
+	token  string
+}
+
+// NewServer starts a fake glcore serving fixtures. Close it when done.
+func NewServer(fixtures Fixtures) *Server {
+	s := &Server{}
+	s.SetFixtures(fixtures)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.handleHealth)
+	mux.HandleFunc("GET /v1/capabilities", s.handleCapabilities)
+	mux.HandleFunc("GET /v1/glucose/latest", s.authorized(s.handleLatestGlucose))
+	mux.HandleFunc("GET /v1/glucose", s.authorized(s.handleGlucose))
+	mux.HandleFunc("GET /v1/sensor/latest", s.authorized(s.handleLatestSensor))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "Not found in glclienttest")
+	})
+
+	s.server = httptest.NewServer(s.versioned(mux))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// SetFixtures replaces the served data.
+func (s *Server) SetFixtures(fixtures Fixtures) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = Fixtures{Sensor: fixtures.Sensor}
+	s.addReadings(fixtures.Readings...)
+}
+
+// AddReading adds a reading, which becomes the latest if it is the most recent.
+func (s *Server) AddReading(p glclient.Point) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addReadings(p)
+}
+
+// addReadings adds readings keeping the newest first. Callers hold mu.
+func (s *Server) addReadings(points ...glclient.Point) {
+	readings := make([]glclient.Point, 0, len(s.data.Readings)+len(points))
+	readings = append(append(readings, s.data.Readings...), points...)
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].Timestamp.After(readings[j].Timestamp)
+	})
+	s.data.Readings = readings
+}
+
+// RequireToken makes the data endpoints answer 401 unless the request has
+// an "Authorization: Bearer <token>" header, as glcore with GLCMD_API_TOKENS.
+// An empty token disables the check.
+func (s *Server) RequireToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// versioned echoes the schema version header, rejecting unsupported versions.
+func (s *Server) versioned(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header := r.Header.Get(glclient.APIVersionHeader); header != "" {
+			if v, err := strconv.Atoi(header); err != nil || v < 1 || v > glclient.SchemaVersion {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported %s %q", glclient.APIVersionHeader, header))
+				return
+			}
+		}
+		w.Header().Set(glclient.APIVersionHeader, strconv.Itoa(glclient.SchemaVersion))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorized enforces the token set with RequireToken.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		token := s.token
+		s.mu.RUnlock()
+
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			writeError(w, http.StatusUnauthorized, "Invalid token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	health := map[string]any{
+		"status":            "healthy",
+		"timestamp":         time.Now().UTC(),
+		"databaseConnected": true,
+		"dataFresh":         len(s.data.Readings) > 0,
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": health})
+}
+
+func (s *Server) handleCapabilities(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	features := map[string]any{
+		"deltaEncoding": map[string]any{"enabled": true, "version": 1},
+		"auth":          map[string]any{"enabled": s.token != "", "version": 1},
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{
+		"apiVersion":     "v1",
+		"schemaVersion":  glclient.SchemaVersion,
+		"schemaVersions": []int{1, glclient.SchemaVersion},
+		"features":       features,
+	}})
+}
+
+func (s *Server) handleLatestGlucose(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.data.Readings) == 0 {
+		writeError(w, http.StatusNotFound, "No measurements found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": newMeasurement(s.data.Readings[0], true)})
+}
+
+// handleGlucose supports the start, end, limit and offset parameters and the
+// delta encoding, newest reading first.
+func (s *Server) handleGlucose(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	start, errStart := parseTime(query.Get("start"))
+	end, errEnd := parseTime(query.Get("end"))
+	limit, errLimit := parseInt(query.Get("limit"), 100)
+	offset, errOffset := parseInt(query.Get("offset"), 0)
+	if errStart != nil || errEnd != nil || errLimit != nil || errOffset != nil || limit < 1 || limit > 1000 || offset < 0 {
+		writeError(w, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	s.mu.RLock()
+	var matched []glclient.Point
+	for _, p := range s.data.Readings {
+		if (start.IsZero() || !p.Timestamp.Before(start)) && (end.IsZero() || !p.Timestamp.After(end)) {
+			matched = append(matched, p)
+		}
+	}
+	s.mu.RUnlock()
+
+	total := len(matched)
+	page := matched[min(offset, total):min(offset+limit, total)]
+	pagination := map[string]any{
+		"limit":   limit,
+		"offset":  offset,
+		"total":   total,
+		"hasMore": offset+limit < total,
+	}
+
+	if query.Get("encoding") == glclient.EncodingDelta {
+		writeJSON(w, http.StatusOK, map[string]any{"data": glclient.EncodeDelta(page), "pagination": pagination})
+		return
+	}
+
+	measurements := make([]map[string]any, len(page))
+	for i, p := range page {
+		measurements[i] = newMeasurement(p, false)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": measurements, "pagination": pagination})
+}
+
+func (s *Server) handleLatestSensor(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sensor := s.data.Sensor
+	if sensor == nil {
+		writeError(w, http.StatusNotFound, "No sensor found")
+		return
+	}
+
+	now := time.Now()
+	expiresAt := sensor.Activation.AddDate(0, 0, sensor.DurationDays)
+	elapsed := now.Sub(sensor.Activation).Hours() / 24
+	remaining := math.Max(expiresAt.Sub(now).Hours()/24, 0)
+	status := "running"
+	if !now.Before(expiresAt) {
+		status = "expired"
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{
+		"serialNumber":  sensor.SerialNumber,
+		"activation":    sensor.Activation.UTC().Format(time.RFC3339),
+		"expiresAt":     expiresAt.UTC().Format(time.RFC3339),
+		"sensorType":    4,
+		"durationDays":  sensor.DurationDays,
+		"daysRemaining": math.Round(remaining*10) / 10,
+		"daysElapsed":   math.Round(elapsed*10) / 10,
+		"status":        status,
+	}})
+}
+
+// newMeasurement renders a reading as glcore does, with the default targets
+// (70-180 mg/dL, critical below 54 or above 250).
+func newMeasurement(p glclient.Point, current bool) map[string]any {
+	color := 1 // Normal
+	switch {
+	case p.ValueInMgPerDl < 54 || p.ValueInMgPerDl > 250:
+		color = 3 // Critical
+	case p.ValueInMgPerDl < 70 || p.ValueInMgPerDl > 180:
+		color = 2 // Warning
+	}
+
+	m := map[string]any{
+		"factoryTimestamp": p.Timestamp.UTC(),
+		"timestamp":        p.Timestamp.UTC(),
+		"value":            math.Round(float64(p.ValueInMgPerDl)/18.0*10) / 10,
+		"valueInMgPerDl":   p.ValueInMgPerDl,
+		"measurementColor": color,
+		"glucoseUnits":     1, // mg/dL
+		"isHigh":           p.ValueInMgPerDl > 180,
+		"isLow":            p.ValueInMgPerDl < 70,
+		"type":             0, // Historical
+	}
+	if current {
+		m["type"] = 1
+	}
+	if p.TrendArrow != 0 {
+		m["trendArrow"] = p.TrendArrow
+	}
+	return m
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func parseInt(s string, def int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+	return strconv.Atoi(s)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"error": map[string]any{"code": status, "message": message}})
+}
@@ -0,0 +1,84 @@
+package glclienttest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/glcmd/internal/cli"
+	"github.com/R4yL-dev/glcmd/pkg/glclient"
+	"github.com/R4yL-dev/glcmd/pkg/glclienttest"
+)
+
+// The fake is exercised with glcli's client, so its responses stay decodable
+// by a real glcore client.
+func TestServer_Fixtures(t *testing.T) {
+	now := time.Now()
+	server := glclienttest.NewServer(glclienttest.DefaultFixtures(now))
+	defer server.Close()
+
+	client := cli.NewClient(server.URL)
+	ctx := context.Background()
+
+	latest, err := client.GetLatestGlucose(ctx)
+	if err != nil {
+		t.Fatalf("GetLatestGlucose failed: %v", err)
+	}
+	if latest.ValueInMgPerDl != 155 || latest.TrendArrow == nil || *latest.TrendArrow != 4 {
+		t.Errorf("unexpected latest reading: %+v", latest)
+	}
+
+	start := now.Add(-30 * time.Minute)
+	list, err := client.GetGlucose(ctx, cli.GlucoseParams{Start: &start, Limit: 3})
+	if err != nil {
+		t.Fatalf("GetGlucose failed: %v", err)
+	}
+	if len(list.Data) != 3 || list.Data[0].ValueInMgPerDl != 155 || list.Pagination.Total < 6 {
+		t.Errorf("unexpected glucose page: %+v", list)
+	}
+
+	sensor, err := client.GetLatestSensor(ctx)
+	if err != nil {
+		t.Fatalf("GetLatestSensor failed: %v", err)
+	}
+	if sensor.SerialNumber != "0TEST00001" || sensor.Status != "running" || sensor.DurationDays != 15 {
+		t.Errorf("unexpected sensor: %+v", sensor)
+	}
+
+	// New readings become the latest
+	server.AddReading(glclient.Point{Timestamp: now.Add(time.Minute), ValueInMgPerDl: 62})
+	latest, err = client.GetLatestGlucose(ctx)
+	if err != nil {
+		t.Fatalf("GetLatestGlucose failed: %v", err)
+	}
+	if latest.ValueInMgPerDl != 62 || !latest.IsLow || latest.TrendArrow != nil {
+		t.Errorf("unexpected latest reading after AddReading: %+v", latest)
+	}
+}
+
+func TestServer_Empty(t *testing.T) {
+	server := glclienttest.NewServer(glclienttest.Fixtures{})
+	defer server.Close()
+
+	client := cli.NewClient(server.URL)
+	if _, err := client.GetLatestGlucose(context.Background()); !errors.Is(err, cli.ErrNoReadings) {
+		t.Errorf("expected ErrNoReadings, got %v", err)
+	}
+}
+
+func TestServer_RequireToken(t *testing.T) {
+	server := glclienttest.NewServer(glclienttest.DefaultFixtures(time.Now()))
+	defer server.Close()
+	server.RequireToken("test-token")
+
+	ctx := context.Background()
+	if _, err := cli.NewClient(server.URL).GetLatestGlucose(ctx); !errors.Is(err, cli.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized without token, got %v", err)
+	}
+
+	client := cli.NewClientWithConfig(server.URL, cli.ClientConfig{Timeout: time.Second, Token: "test-token"})
+	if _, err := client.GetLatestGlucose(ctx); err != nil {
+		t.Errorf("expected token to be accepted, got %v", err)
+	}
+}